@@ -1,12 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"time"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/health"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/reconciler"
 	"knative.dev/pkg/injection/sharedmain"
 )
@@ -20,11 +22,19 @@ func main() {
 		probesPort = envProbePort
 	}
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("/live", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(200)
-		_, _ = fmt.Fprint(w, "ok")
+	// we are ready once the informers have synced, we can reach the API server
+	// and the settings are valid, and live as long as the work queue is not
+	// stuck
+	checker := health.NewChecker()
+	// the informers are added when the controller is created
+	checker.AddCheck("controller", func(context.Context) error {
+		if checker.Informers() == 0 {
+			return fmt.Errorf("the controller has not been created yet")
+		}
+		return nil
 	})
+	mux := http.NewServeMux()
+	checker.Register(mux)
 
 	c := make(chan struct{})
 	go func() {
@@ -44,5 +54,5 @@ func main() {
 	}()
 	<-c
 
-	sharedmain.Main("pac-watcher", reconciler.NewController())
+	sharedmain.Main("pac-watcher", reconciler.NewController(checker), reconciler.NewWebhookController(checker))
 }
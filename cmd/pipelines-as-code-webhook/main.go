@@ -36,6 +36,7 @@ func main() {
 		injection.ParseAndGetRESTConfigOrDie(),
 		certificates.NewController,
 		newValidationAdmissionController,
+		newDefaultingAdmissionController,
 	)
 }
 
@@ -57,3 +58,14 @@ func newValidationAdmissionController(ctx context.Context, _ configmap.Watcher)
 		true,
 	)
 }
+
+func newDefaultingAdmissionController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	return validation_webhook.NewDefaultingAdmissionController(ctx,
+
+		// Name of the resource webhook.
+		"defaulting.pipelinesascode.tekton.dev",
+
+		// The path on which to serve the webhook.
+		"/defaulting",
+	)
+}
@@ -0,0 +1,67 @@
+package acl
+
+import (
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+// CodeOwnersPaths are the paths of the CODEOWNERS file in a repository, in
+// the order GitHub looks for it
+var CodeOwnersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeOwners returns the owners of a file in a CODEOWNERS file, the last
+// pattern matching the file wins like on GitHub. The owners are the @user,
+// @org/team or email as written in the file.
+func CodeOwners(content, file string) []string {
+	var owners []string
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		// skip the empty lines and the gitlab sections
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			continue
+		}
+		if codeOwnersMatch(fields[0], file) {
+			owners = fields[1:]
+		}
+	}
+	return owners
+}
+
+// codeOwnersMatch matches a file with a CODEOWNERS pattern, which follow the
+// gitignore rules: a pattern with a slash is relative to the root of the
+// repository, one without matches at any level and a pattern matching a
+// directory matches all the files inside it.
+func codeOwnersMatch(pattern, file string) bool {
+	file = strings.TrimPrefix(file, "/")
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	onlyDir := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "/"), "/")
+	if strings.HasPrefix(pattern, "**/") {
+		anchored = false
+		pattern = strings.TrimPrefix(pattern, "**/")
+	}
+	g, err := glob.Compile(pattern, '/')
+	if err != nil {
+		return false
+	}
+
+	parts := strings.Split(file, "/")
+	for start := range parts {
+		if anchored && start > 0 {
+			break
+		}
+		for end := start + 1; end <= len(parts); end++ {
+			if onlyDir && end == len(parts) {
+				continue
+			}
+			if g.Match(strings.Join(parts[start:end], "/")) {
+				return true
+			}
+		}
+	}
+	return false
+}
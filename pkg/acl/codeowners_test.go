@@ -0,0 +1,81 @@
+package acl
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCodeOwners(t *testing.T) {
+	content := `# the default owners
+*       @org/maintainers
+
+*.go    @gopher  # go code
+/docs/  docs@example.com
+build/  @builder
+/pkg/**/test/ @tester
+
+[Section]
+README.md @writer @org/docs
+`
+	tests := []struct {
+		name string
+		file string
+		want []string
+	}{
+		{
+			name: "default owners",
+			file: "Makefile",
+			want: []string{"@org/maintainers"},
+		},
+		{
+			name: "extension at any level",
+			file: "pkg/acl/acl.go",
+			want: []string{"@gopher"},
+		},
+		{
+			name: "anchored directory",
+			file: "docs/content/index.md",
+			want: []string{"docs@example.com"},
+		},
+		{
+			name: "anchored directory not matched below the root",
+			file: "pkg/docs/index.md",
+			want: []string{"@org/maintainers"},
+		},
+		{
+			name: "directory at any level",
+			file: "hack/build/script.sh",
+			want: []string{"@builder"},
+		},
+		{
+			name: "directory pattern do not match a file",
+			file: "hack/build",
+			want: []string{"@org/maintainers"},
+		},
+		{
+			name: "double star",
+			file: "pkg/acl/sub/test/data.yaml",
+			want: []string{"@tester"},
+		},
+		{
+			name: "last match wins",
+			file: "docs/main.go",
+			want: []string{"docs@example.com"},
+		},
+		{
+			name: "multiple owners after a section",
+			file: "README.md",
+			want: []string{"@writer", "@org/docs"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, CodeOwners(content, tt.file), tt.want)
+		})
+	}
+}
+
+func TestCodeOwnersNoMatch(t *testing.T) {
+	assert.Assert(t, CodeOwners("/docs/ @writer\n", "main.go") == nil)
+}
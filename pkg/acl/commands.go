@@ -0,0 +1,46 @@
+package acl
+
+import "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+
+// the gitops commands which can be commented on a pull request
+const (
+	CommandTest     = "test"
+	CommandRetest   = "retest"
+	CommandCancel   = "cancel"
+	CommandOkToTest = "ok-to-test"
+	CommandHelp     = "help"
+)
+
+// the permissions a Repository can require to run a gitops command, the
+// users with a higher permission can run the commands of the lower ones
+const (
+	// PermissionAnyone lets anyone who can comment on the pull request run
+	// the command
+	PermissionAnyone = "anyone"
+	// PermissionAuthor lets the author of the pull request run the command
+	// on their pull request
+	PermissionAuthor = "author"
+	// PermissionMember lets the users allowed to run the CI on the repository
+	// run the command, the default
+	PermissionMember = "member"
+	// PermissionMaintainer only lets the maintainers of the repository on the
+	// git provider run the command
+	PermissionMaintainer = "maintainer"
+)
+
+// CommandPermission returns the permission the Repository requires to run a
+// gitops command, the last of its comment_commands listing the command wins.
+func CommandPermission(repo *v1alpha1.Repository, command string) string {
+	permission := PermissionMember
+	if repo == nil {
+		return permission
+	}
+	for _, cc := range repo.Spec.CommentCommands {
+		for _, c := range cc.Commands {
+			if c == command && cc.Permission != "" {
+				permission = cc.Permission
+			}
+		}
+	}
+	return permission
+}
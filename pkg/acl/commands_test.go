@@ -0,0 +1,23 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"gotest.tools/v3/assert"
+)
+
+func TestCommandPermission(t *testing.T) {
+	repo := &v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{
+		CommentCommands: []v1alpha1.CommentCommandPermission{
+			{Commands: []string{CommandRetest}, Permission: PermissionAuthor},
+			{Commands: []string{CommandTest}, Permission: PermissionAnyone},
+			{Commands: []string{CommandCancel, CommandTest}, Permission: PermissionMaintainer},
+		},
+	}}
+	assert.Equal(t, CommandPermission(repo, CommandRetest), PermissionAuthor)
+	assert.Equal(t, CommandPermission(repo, CommandTest), PermissionMaintainer)
+	assert.Equal(t, CommandPermission(repo, CommandCancel), PermissionMaintainer)
+	assert.Equal(t, CommandPermission(repo, CommandOkToTest), PermissionMember)
+	assert.Equal(t, CommandPermission(nil, CommandTest), PermissionMember)
+}
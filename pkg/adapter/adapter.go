@@ -11,16 +11,18 @@ import (
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/generated/informers/externalversions"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/health"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/logproxy"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/metrics"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/version"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud"
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketserver"
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea"
+	// the git providers of the controller
+	_ "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/builtin"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab"
 	"go.uber.org/zap"
 	"knative.dev/eventing/pkg/adapter/v2"
 	"knative.dev/pkg/logging"
@@ -37,10 +39,17 @@ func NewEnvConfig() adapter.EnvConfigAccessor {
 }
 
 type listener struct {
-	run    *params.Run
-	kint   kubeinteraction.Interface
-	logger *zap.SugaredLogger
-	event  *info.Event
+	run        *params.Run
+	kint       kubeinteraction.Interface
+	logger     *zap.SugaredLogger
+	event      *info.Event
+	processed  *processedTime
+	settings   *settingsState
+	metrics    *metrics.Recorder
+	inflight   *inflightEvents
+	shards     *shardRing
+	locks      *repositoryLocks
+	deliveries *deliveries
 }
 
 type Response struct {
@@ -52,10 +61,21 @@ var _ adapter.Adapter = (*listener)(nil)
 
 func New(run *params.Run, k *kubeinteraction.Interaction) adapter.AdapterConstructor {
 	return func(ctx context.Context, processed adapter.EnvConfigAccessor, ceClient cloudevents.Client) adapter.Adapter {
+		logger := logging.FromContext(ctx)
+		recorder, err := metrics.NewRecorder()
+		if err != nil {
+			logger.Errorf("cannot initialize the metrics recorder: %v", err)
+		}
 		return &listener{
-			logger: logging.FromContext(ctx),
-			run:    run,
-			kint:   k,
+			logger:     logger,
+			run:        run,
+			kint:       k,
+			processed:  &processedTime{},
+			settings:   &settingsState{},
+			metrics:    recorder,
+			inflight:   newInflightEvents(),
+			locks:      newRepositoryLocks(),
+			deliveries: newDeliveries(),
 		}
 	}
 }
@@ -68,33 +88,90 @@ func (l *listener) Start(ctx context.Context) error {
 	}
 	l.logger.Infof("Starting Pipelines as Code version: %s", version.Version)
 
+	err := l.run.UpdatePACInfo(ctx)
+	if err != nil {
+		l.logger.Errorf("cannot get the settings on startup: %v", err)
+	}
+	l.settings.set(err)
+	if l.run.Info.Pac.ReplayMissedWebhooks {
+		go l.replayMissedWebhooks(ctx)
+	}
+	go l.run.WatchPACSettingsChanges(ctx)
+	go l.recordProcessedTime(ctx)
+	go l.recordDeliveries(ctx)
+
+	enabled, tlsCertFile, tlsKeyFile := l.isTLSEnabled()
+	if params.StringToBool(os.Getenv(shardingEnv)) {
+		l.startSharding(ctx, adapterPort, enabled)
+	}
+
+	// the resolve endpoint is not authenticated, it is never served with
+	// the webhooks
+	if resolvePort := os.Getenv(resolvePortEnv); resolvePort != "" {
+		l.startResolveServer(ctx, resolvePort)
+	}
+
 	mux := http.NewServeMux()
 
-	// for handling probes
-	mux.HandleFunc("/live", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(200)
-		_, _ = fmt.Fprint(w, "ok")
-	})
+	// for handling probes, we are ready once we can reach the API server, the
+	// settings have been loaded and we have a provider credential to use
+	checker := health.NewChecker()
+	checker.AddCheck("kubernetes", health.KubernetesCheck(l.run.Clients.Kube))
+	checker.AddCheck("settings", l.settings.check)
+	repositories := externalversions.NewSharedInformerFactory(l.run.Clients.PipelineAsCode, 0).Pipelinesascode().V1alpha1().Repositories()
+	repoLister := repositories.Lister()
+	go repositories.Informer().Run(ctx.Done())
+	checker.AddInformer("repositories", repositories.Informer().HasSynced)
+	credentials := &credentialsState{
+		now: time.Now,
+		verify: func(ctx context.Context) error {
+			return hasProviderCredentials(ctx, l.run, l.kint, repoLister)
+		},
+	}
+	checker.AddCheck("credentials", credentials.check)
+	if params.StringToBool(os.Getenv(githubAppCheckEnv)) {
+		githubApp := &credentialsState{
+			now: time.Now,
+			verify: func(ctx context.Context) error {
+				return verifyGitHubAppCredentials(ctx, l.run)
+			},
+		}
+		checker.AddCheck("github-app", githubApp.check)
+	}
+	checker.Register(mux)
+
+	// the events are processed until the end when the controller is stopping
+	eventsCtx, cancelEvents := context.WithCancel(detachedContext{ctx})
+	mux.HandleFunc("/", l.handleEvent(eventsCtx))
 
-	mux.HandleFunc("/", l.handleEvent(ctx))
+	// the logs are streamed for as long as they are read, outside of the
+	// timeout of the events
+	root := http.NewServeMux()
+	root.HandleFunc(logproxy.Path, logproxy.Handler(l.run, l.kint, l.logger))
+	root.Handle("/", http.TimeoutHandler(mux, 10*time.Second, "Listener Timeout!\n"))
 
 	//nolint: gosec
 	srv := &http.Server{
-		Addr: ":" + adapterPort,
-		Handler: http.TimeoutHandler(mux,
-			10*time.Second, "Listener Timeout!\n"),
+		Addr:    ":" + adapterPort,
+		Handler: root,
 	}
 
-	enabled, tlsCertFile, tlsKeyFile := l.isTLSEnabled()
-	if enabled {
-		if err := srv.ListenAndServeTLS(tlsCertFile, tlsKeyFile); err != nil {
-			return err
-		}
-	} else {
-		if err := srv.ListenAndServe(); err != nil {
-			return err
+	served := make(chan error, 1)
+	go func() {
+		if enabled {
+			served <- srv.ListenAndServeTLS(tlsCertFile, tlsKeyFile)
+		} else {
+			served <- srv.ListenAndServe()
 		}
+	}()
+
+	select {
+	case err := <-served:
+		cancelEvents()
+		return err
+	case <-ctx.Done():
 	}
+	l.shutdown(srv, cancelEvents)
 	return nil
 }
 
@@ -123,6 +200,21 @@ func (l listener) handleEvent(ctx context.Context) http.HandlerFunc {
 			return
 		}
 
+		// the events forwarded by the other replicas are processed without
+		// being forwarded again, they have to be signed by one of them
+		forwarded := request.Header.Get(forwardedHeader) != ""
+		if forwarded {
+			if l.shards == nil {
+				l.writeResponse(response, http.StatusForbidden, "the events are not sharded between the replicas")
+				return
+			}
+			if err := l.shards.verify(request.Header, payload); err != nil {
+				l.logger.Errorf("rejecting the event forwarded by %s: %v", request.Header.Get(forwardedHeader), err)
+				l.writeResponse(response, http.StatusForbidden, "invalid forwarded event")
+				return
+			}
+		}
+
 		var event map[string]interface{}
 		if string(payload) != "" {
 			if err := json.Unmarshal(payload, &event); err != nil {
@@ -173,23 +265,46 @@ func (l listener) handleEvent(ctx context.Context) http.HandlerFunc {
 			return
 		}
 
+		received := time.Now()
+		// the forwarded events are deduplicated again by the replica owning
+		// their repository, the one receiving a redelivery may be another
+		delivery := ""
+		if !isIncoming {
+			delivery = deliveryID(request.Header)
+		}
+		window := l.run.Info.Pac.DeliveryDeduplicationWindow
+		if !l.deliveries.accept(delivery, received, window) {
+			logger.Infof("skipping the delivery %s, it has already been received", delivery)
+			l.writeResponse(response, http.StatusOK, "skipped duplicate delivery")
+			return
+		}
+		l.processed.set(received)
+
 		s := sinker{
-			run:     l.run,
-			vcx:     gitProvider,
-			kint:    l.kint,
-			event:   l.event,
-			logger:  logger,
-			payload: payload,
+			run:       l.run,
+			vcx:       gitProvider,
+			kint:      l.kint,
+			event:     l.event,
+			logger:    logger,
+			payload:   payload,
+			shards:    l.shards,
+			locks:     l.locks,
+			forwarded: forwarded,
 		}
 
 		// clone the request to use it further
 		localRequest := request.Clone(request.Context())
 
+		id := l.inflight.add(received, request.Header.Get(deliveryHeader))
 		go func() {
+			defer l.inflight.done(id)
 			err := s.processEvent(ctx, localRequest)
 			if err != nil {
 				logger.Errorf("an error occurred: %v", err)
+				l.deliveries.forget(delivery)
+				return
 			}
+			l.deliveries.done(delivery, time.Now(), window)
 		}()
 
 		l.writeResponse(response, http.StatusAccepted, "accepted")
@@ -222,34 +337,14 @@ func (l listener) detectProvider(req *http.Request, reqBody string) (provider.In
 		return nil, &log, fmt.Errorf("invalid event body format: %w", err)
 	}
 
-	gitHub := github.New()
-	isGH, processReq, logger, reason, err := gitHub.Detect(req, reqBody, &log)
-	if isGH {
-		return l.processRes(processReq, gitHub, logger, reason, err)
-	}
-
-	zegitea := &gitea.Provider{}
-	isGitea, processReq, logger, reason, err := zegitea.Detect(req, reqBody, &log)
-	if isGitea {
-		return l.processRes(processReq, zegitea, logger, reason, err)
-	}
-
-	bitServer := &bitbucketserver.Provider{}
-	isBitServer, processReq, logger, reason, err := bitServer.Detect(req, reqBody, &log)
-	if isBitServer {
-		return l.processRes(processReq, bitServer, logger, reason, err)
-	}
-
-	gitLab := &gitlab.Provider{}
-	isGitlab, processReq, logger, reason, err := gitLab.Detect(req, reqBody, &log)
-	if isGitlab {
-		return l.processRes(processReq, gitLab, logger, reason, err)
-	}
-
-	bitCloud := &bitbucketcloud.Provider{}
-	isBitCloud, processReq, logger, reason, err := bitCloud.Detect(req, reqBody, &log)
-	if isBitCloud {
-		return l.processRes(processReq, bitCloud, logger, reason, err)
+	logger := &log
+	for _, registration := range provider.Registrations() {
+		p := registration.New(l.metrics)
+		detected, processReq, detectLogger, reason, err := p.Detect(req, reqBody, &log)
+		if detected {
+			return l.processRes(processReq, p, detectLogger, reason, err)
+		}
+		logger = detectLogger
 	}
 
 	return l.processRes(false, nil, logger, "", fmt.Errorf("no supported Git provider has been detected"))
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/v49/github"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
@@ -40,7 +43,9 @@ func TestHandleEvent(t *testing.T) {
 				},
 			},
 		},
-		logger: logger,
+		logger:    logger,
+		processed: &processedTime{},
+		inflight:  newInflightEvents(),
 	}
 
 	ts := httptest.NewServer(l.handleEvent(ctx))
@@ -60,6 +65,7 @@ func TestHandleEvent(t *testing.T) {
 		event       []byte
 		eventType   string
 		requestType string
+		forwardedBy string
 		statusCode  int
 	}{
 		{
@@ -94,6 +100,14 @@ func TestHandleEvent(t *testing.T) {
 			event:       skippedEvent,
 			statusCode:  200,
 		},
+		{
+			name:        "forwarded event not sharded",
+			requestType: "POST",
+			eventType:   "push",
+			forwardedBy: "replica",
+			event:       event,
+			statusCode:  403,
+		},
 		{
 			name:        "git provider not detected",
 			requestType: "POST",
@@ -110,6 +124,9 @@ func TestHandleEvent(t *testing.T) {
 				t.Fatalf("error creating request: %s", err)
 			}
 			req.Header.Set("X-Github-Event", tt.eventType)
+			if tt.forwardedBy != "" {
+				req.Header.Set(forwardedHeader, tt.forwardedBy)
+			}
 
 			resp, err := http.DefaultClient.Do(req)
 			if err != nil {
@@ -124,6 +141,70 @@ func TestHandleEvent(t *testing.T) {
 	}
 }
 
+func TestHandleEventForwardedDuplicate(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	cs, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	logger, _ := logger.GetLogger()
+
+	t.Setenv("SYSTEM_NAMESPACE", "test")
+	owner := replica{name: "owner"}
+	l := listener{
+		run: &params.Run{
+			Clients: clients.Clients{
+				PipelineAsCode: cs.PipelineAsCode,
+				Log:            logger,
+				Kube:           cs.Kube,
+			},
+			Info: info.Info{
+				Pac: &info.PacOpts{
+					Settings: &settings.Settings{
+						DeliveryDeduplicationWindow: time.Hour,
+					},
+				},
+			},
+		},
+		logger:     logger,
+		processed:  &processedTime{},
+		inflight:   newInflightEvents(),
+		shards:     newShardRing(nil, "", owner, []byte("secret"), nil, logger),
+		locks:      newRepositoryLocks(),
+		deliveries: newDeliveries(),
+	}
+	// the redelivery has already been processed by the owner of the
+	// repository, it is forwarded again by the replica receiving it
+	assert.Assert(t, l.deliveries.accept("delivery", time.Now(), time.Hour))
+	l.deliveries.done("delivery", time.Now(), time.Hour)
+
+	ts := httptest.NewServer(l.handleEvent(ctx))
+	defer ts.Close()
+
+	forwarded := make(chan *http.Request, 1)
+	first := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded <- r
+	}))
+	defer first.Close()
+
+	event, err := json.Marshal(github.PushEvent{Pusher: &github.User{ID: github.Int64(101)}})
+	assert.NilError(t, err)
+	request := httptest.NewRequest(http.MethodPost, "http://controller/", nil)
+	request.Header.Set("X-Github-Event", "push")
+	request.Header.Set(deliveryHeader, "delivery")
+	ring := newShardRing(nil, "", replica{name: "first"}, []byte("secret"), nil, logger)
+	assert.NilError(t, ring.forward(ctx, replica{name: "owner", address: first.URL}, request, event))
+	received := <-forwarded
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ts.URL, bytes.NewReader(event))
+	assert.NilError(t, err)
+	req.Header = received.Header.Clone()
+	resp, err := http.DefaultClient.Do(req)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Assert(t, strings.Contains(string(body), "skipped duplicate delivery"), string(body))
+}
+
 func TestWhichProvider(t *testing.T) {
 	logger, _ := logger.GetLogger()
 	l := listener{
@@ -0,0 +1,209 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// deliveriesConfigMap is where the replicas of the controller share the
+	// webhook deliveries they have processed, with when they expire
+	deliveriesConfigMap = "pipelines-as-code-webhook-deliveries"
+	deliveriesKey       = "deliveries"
+	// maxRecordedDeliveries keeps the ConfigMap well under its size limit, the
+	// deliveries expiring first are dropped beyond
+	maxRecordedDeliveries = 5000
+)
+
+// deliveryHeaders are the headers with the ID of a webhook delivery, it stays
+// the same when the git provider redelivers the webhook. Gitea and Forgejo
+// send the GitHub header as well.
+var deliveryHeaders = []string{"X-Forgejo-Delivery", "X-Gitea-Delivery", deliveryHeader, "X-Gitlab-Event-UUID"}
+
+// deliveryID returns the ID of the delivery of a webhook, empty when the git
+// provider doesn't send one.
+func deliveryID(header http.Header) string {
+	for _, name := range deliveryHeaders {
+		if id := header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// deliveries are the webhook deliveries received by the controller, to ignore
+// the ones received again during the deduplication window.
+type deliveries struct {
+	mu sync.Mutex
+	// seen are the deliveries being processed or processed, by the controller
+	// or the other replicas, with when they expire
+	seen map[string]time.Time
+	// processed are the deliveries processed since the last time they have been
+	// recorded in the ConfigMap
+	processed map[string]time.Time
+}
+
+func newDeliveries() *deliveries {
+	return &deliveries{seen: map[string]time.Time{}, processed: map[string]time.Time{}}
+}
+
+// accept returns if a delivery has not been seen during the window and should
+// be processed, it is then seen until it is done or forgotten. The deliveries
+// without ID, or all of them when the window is 0, are always accepted.
+func (d *deliveries) accept(id string, now time.Time, window time.Duration) bool {
+	if id == "" || window <= 0 {
+		return true
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if expiry, ok := d.seen[id]; ok && now.Before(expiry) {
+		return false
+	}
+	d.seen[id] = now.Add(window)
+	return true
+}
+
+// done marks an accepted delivery as processed, it expires after the window.
+func (d *deliveries) done(id string, now time.Time, window time.Duration) {
+	if id == "" || window <= 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.seen[id] = now.Add(window)
+	d.processed[id] = now.Add(window)
+}
+
+// forget an accepted delivery failing to be processed, for the git provider
+// or the user to be able to redeliver it.
+func (d *deliveries) forget(id string) {
+	if id == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.seen, id)
+}
+
+// record adds the deliveries processed to the ConfigMap and takes the ones of
+// the other replicas from it, the expired ones are dropped from both. A
+// conflict with another replica is retried on the next record.
+func (d *deliveries) record(ctx context.Context, kube kubernetes.Interface, ns string, now time.Time) error {
+	d.mu.Lock()
+	processed := make(map[string]time.Time, len(d.processed))
+	for id, expiry := range d.processed {
+		processed[id] = expiry
+	}
+	d.mu.Unlock()
+
+	cm, err := kube.CoreV1().ConfigMaps(ns).Get(ctx, deliveriesConfigMap, metav1.GetOptions{})
+	create := errors.IsNotFound(err)
+	if err != nil && !create {
+		return err
+	}
+	recorded := map[string]time.Time{}
+	if !create && cm.Data[deliveriesKey] != "" {
+		// a broken list is replaced, we only miss some duplicates
+		_ = json.Unmarshal([]byte(cm.Data[deliveriesKey]), &recorded)
+	}
+	for id, expiry := range processed {
+		if expiry.After(recorded[id]) {
+			recorded[id] = expiry
+		}
+	}
+	recorded = unexpiredDeliveries(recorded, now)
+
+	if len(processed) > 0 {
+		value, err := json.Marshal(recorded)
+		if err != nil {
+			return err
+		}
+		if create {
+			cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: deliveriesConfigMap, Namespace: ns}}
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[deliveriesKey] = string(value)
+		if create {
+			_, err = kube.CoreV1().ConfigMaps(ns).Create(ctx, cm, metav1.CreateOptions{})
+		} else {
+			_, err = kube.CoreV1().ConfigMaps(ns).Update(ctx, cm, metav1.UpdateOptions{})
+		}
+		if errors.IsConflict(err) || errors.IsAlreadyExists(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for id, expiry := range processed {
+		if d.processed[id].Equal(expiry) {
+			delete(d.processed, id)
+		}
+	}
+	for id, expiry := range recorded {
+		if expiry.After(d.seen[id]) {
+			d.seen[id] = expiry
+		}
+	}
+	for id, expiry := range d.seen {
+		if !now.Before(expiry) {
+			delete(d.seen, id)
+		}
+	}
+	return nil
+}
+
+// unexpiredDeliveries returns the deliveries not expired yet, the ones
+// expiring last when there are too many of them.
+func unexpiredDeliveries(all map[string]time.Time, now time.Time) map[string]time.Time {
+	ids := []string{}
+	for id, expiry := range all {
+		if now.Before(expiry) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return all[ids[i]].After(all[ids[j]]) })
+	if len(ids) > maxRecordedDeliveries {
+		ids = ids[:maxRecordedDeliveries]
+	}
+	kept := make(map[string]time.Time, len(ids))
+	for _, id := range ids {
+		kept[id] = all[id]
+	}
+	return kept
+}
+
+// recordDeliveries shares the processed deliveries with the other replicas at
+// every interval, as long as the deduplication is enabled.
+func (l *listener) recordDeliveries(ctx context.Context) {
+	ns := os.Getenv("SYSTEM_NAMESPACE")
+	ticker := time.NewTicker(recordInterval)
+	defer ticker.Stop()
+	for {
+		if l.run.Info.Pac.DeliveryDeduplicationWindow > 0 {
+			if err := l.deliveries.record(ctx, l.run.Clients.Kube, ns, time.Now()); err != nil {
+				l.logger.Errorf("cannot record the processed webhook deliveries: %v", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package adapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestDeliveryID(t *testing.T) {
+	gitea := http.Header{}
+	gitea.Set("X-GitHub-Delivery", "gitea-id")
+	gitea.Set("X-Gitea-Delivery", "gitea-id")
+	assert.Equal(t, deliveryID(gitea), "gitea-id")
+
+	gitlab := http.Header{}
+	gitlab.Set("X-Gitlab-Event-UUID", "gitlab-id")
+	assert.Equal(t, deliveryID(gitlab), "gitlab-id")
+
+	assert.Equal(t, deliveryID(http.Header{"X-Request-Id": []string{"bitbucket"}}), "")
+}
+
+func TestDeliveriesAccept(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	d := newDeliveries()
+
+	assert.Assert(t, d.accept("push", now, time.Hour))
+	assert.Assert(t, !d.accept("push", now, time.Hour), "duplicated while processed")
+	d.done("push", now.Add(time.Minute), time.Hour)
+	assert.Assert(t, !d.accept("push", now.Add(30*time.Minute), time.Hour), "redelivered")
+	assert.Assert(t, d.accept("push", now.Add(2*time.Hour), time.Hour), "expired")
+
+	assert.Assert(t, d.accept("failed", now, time.Hour))
+	d.forget("failed")
+	assert.Assert(t, d.accept("failed", now, time.Hour), "failed to be processed")
+
+	assert.Assert(t, d.accept("", now, time.Hour))
+	assert.Assert(t, d.accept("", now, time.Hour))
+	assert.Assert(t, d.accept("disabled", now, 0))
+	assert.Assert(t, d.accept("disabled", now, 0))
+}
+
+func TestDeliveriesRecord(t *testing.T) {
+	ns := "pipelines-as-code"
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+
+	// nothing to record yet
+	first := newDeliveries()
+	assert.NilError(t, first.record(ctx, stdata.Kube, ns, now))
+	_, err := stdata.Kube.CoreV1().ConfigMaps(ns).Get(ctx, deliveriesConfigMap, metav1.GetOptions{})
+	assert.Assert(t, err != nil)
+
+	assert.Assert(t, first.accept("push", now, time.Hour))
+	first.done("push", now, time.Hour)
+	assert.Assert(t, first.accept("old", now.Add(-2*time.Hour), time.Hour))
+	first.done("old", now.Add(-2*time.Hour), time.Hour)
+	assert.NilError(t, first.record(ctx, stdata.Kube, ns, now))
+	assert.Equal(t, len(first.processed), 0)
+	assert.Equal(t, len(first.seen), 1, "the expired deliveries are dropped")
+
+	// another replica of the controller, or the controller restarted
+	second := newDeliveries()
+	assert.Assert(t, second.accept("pull-request", now, time.Hour))
+	second.done("pull-request", now, time.Hour)
+	assert.NilError(t, second.record(ctx, stdata.Kube, ns, now))
+	assert.Assert(t, !second.accept("push", now.Add(time.Minute), time.Hour))
+
+	cm, err := stdata.Kube.CoreV1().ConfigMaps(ns).Get(ctx, deliveriesConfigMap, metav1.GetOptions{})
+	assert.NilError(t, err)
+	recorded := map[string]time.Time{}
+	assert.NilError(t, json.Unmarshal([]byte(cm.Data[deliveriesKey]), &recorded))
+	assert.Equal(t, len(recorded), 2)
+	assert.Assert(t, recorded["push"].Equal(now.Add(time.Hour)))
+
+	assert.NilError(t, first.record(ctx, stdata.Kube, ns, now))
+	assert.Assert(t, !first.accept("pull-request", now.Add(time.Minute), time.Hour))
+}
+
+func TestDeliveriesRecordBrokenConfigMap(t *testing.T) {
+	ns := "pipelines-as-code"
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		ConfigMap: []*corev1.ConfigMap{{
+			ObjectMeta: metav1.ObjectMeta{Name: deliveriesConfigMap, Namespace: ns},
+			Data:       map[string]string{deliveriesKey: "push,pull-request"},
+		}},
+	})
+
+	d := newDeliveries()
+	assert.Assert(t, d.accept("push", now, time.Hour))
+	d.done("push", now, time.Hour)
+	assert.NilError(t, d.record(ctx, stdata.Kube, ns, now))
+
+	cm, err := stdata.Kube.CoreV1().ConfigMaps(ns).Get(ctx, deliveriesConfigMap, metav1.GetOptions{})
+	assert.NilError(t, err)
+	recorded := map[string]time.Time{}
+	assert.NilError(t, json.Unmarshal([]byte(cm.Data[deliveriesKey]), &recorded))
+	assert.Equal(t, len(recorded), 1)
+}
@@ -0,0 +1,123 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/listers/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github/app"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// credentialsCheckInterval is how long the result of the check of the
+// provider credentials is kept, the readiness probe would otherwise hit the
+// API server at every probe
+const credentialsCheckInterval = time.Minute
+
+// githubAppCheckEnv enables the check of the credentials of the GitHub App
+// against the GitHub API in the readiness probe, a revoked or rotated private
+// key is otherwise only noticed when the webhooks start failing
+const githubAppCheckEnv = "PAC_READINESS_CHECK_GITHUB_APP"
+
+// settingsState is the state of the load of the settings on startup
+type settingsState struct {
+	mu     sync.Mutex
+	loaded bool
+	err    error
+}
+
+func (s *settingsState) set(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loaded = true
+	s.err = err
+}
+
+func (s *settingsState) check(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.loaded {
+		return fmt.Errorf("the settings have not been loaded yet")
+	}
+	return s.err
+}
+
+// credentialsState cache the result of the check of the provider credentials
+type credentialsState struct {
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+	now     func() time.Time
+	verify  func(ctx context.Context) error
+}
+
+func (c *credentialsState) check(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checked.IsZero() && c.now().Sub(c.checked) < credentialsCheckInterval {
+		return c.err
+	}
+	c.err = c.verify(ctx)
+	c.checked = c.now()
+	return c.err
+}
+
+// hasProviderCredentials checks there is at least one provider credential we
+// can use, the private key of the GitHub App or the token of the git provider
+// of a Repository. It only checks the credential is there, the git provider is
+// not asked whether it accepts it, see githubAppCheckEnv. Only the tokens of
+// the Repositories stored in a Kubernetes Secret are read until one is found,
+// the readiness probe never logs in to Vault nor reads the ExternalSecrets.
+func hasProviderCredentials(ctx context.Context, run *params.Run, kint kubeinteraction.Interface, repoLister pacv1alpha1.RepositoryLister) error {
+	_, privateKey, err := github.GetAppIDAndPrivateKey(ctx, run.Clients.Kube)
+	if err == nil {
+		if _, err = jwt.ParseRSAPrivateKeyFromPEM(privateKey); err == nil {
+			return nil
+		}
+		err = fmt.Errorf("invalid github app private key: %w", err)
+	}
+
+	repositories, lerr := repoLister.List(labels.Everything())
+	if lerr != nil {
+		return fmt.Errorf("cannot list the repositories: %w", lerr)
+	}
+	var failed *v1alpha1.Repository
+	for _, repo := range repositories {
+		if repo.Spec.GitProvider == nil || repo.Spec.GitProvider.Secret == nil {
+			continue
+		}
+		if source := repo.Spec.GitProvider.Secret.Source; source != "" && source != secrets.SourceKubernetes {
+			continue
+		}
+		token, serr := secrets.GetRepositorySecret(ctx, run, kint, repo.GetNamespace(), repo.Spec.GitProvider.Secret,
+			pipelineascode.DefaultGitProviderSecretKey)
+		if serr == nil && token != "" {
+			return nil
+		}
+		failed = repo
+	}
+	if failed != nil {
+		return fmt.Errorf("no provider credential, no github app (%v) and the git provider token of the repository %s/%s cannot be read",
+			err, failed.GetNamespace(), failed.GetName())
+	}
+	return fmt.Errorf("no provider credential, no github app (%v) nor repository with a git provider token", err)
+}
+
+// verifyGitHubAppCredentials checks GitHub accepts a token generated from the
+// private key of the GitHub App
+func verifyGitHubAppCredentials(ctx context.Context, run *params.Run) error {
+	client, err := app.NewAppClient(ctx, run)
+	if err != nil {
+		return fmt.Errorf("cannot generate a token for the github app: %w", err)
+	}
+	return app.VerifyCredentials(ctx, client)
+}
@@ -0,0 +1,138 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestHasProviderCredentials(t *testing.T) {
+	appSecret := func(privateKey string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "pipelines-as-code-secret", Namespace: "pac"},
+			Data: map[string][]byte{
+				"github-application-id": []byte("12345"),
+				"github-private-key":    []byte(privateKey),
+			},
+		}
+	}
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Spec: v1alpha1.RepositorySpec{
+			URL:         "https://forge/org/repo",
+			GitProvider: &v1alpha1.GitProvider{Secret: &v1alpha1.Secret{Name: "token"}},
+		},
+	}
+	vaultRepo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Spec: v1alpha1.RepositorySpec{
+			URL:         "https://forge/org/repo",
+			GitProvider: &v1alpha1.GitProvider{Secret: &v1alpha1.Secret{Name: "token", Source: "vault"}},
+		},
+	}
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "ns"},
+		Data:       map[string][]byte{"provider.token": []byte("secret")},
+	}
+	otherRepo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other"},
+		Spec: v1alpha1.RepositorySpec{
+			URL:         "https://forge/org/other",
+			GitProvider: &v1alpha1.GitProvider{Secret: &v1alpha1.Secret{Name: "token"}},
+		},
+	}
+	otherTokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "other"},
+		Data:       map[string][]byte{"provider.token": []byte("secret")},
+	}
+
+	tests := []struct {
+		name         string
+		secrets      []*corev1.Secret
+		repositories []*v1alpha1.Repository
+		wantErr      string
+	}{
+		{
+			name:    "github app",
+			secrets: []*corev1.Secret{appSecret(fakePrivateKey)},
+		},
+		{
+			name:         "invalid github app key but a repository token",
+			secrets:      []*corev1.Secret{appSecret("not a key"), tokenSecret},
+			repositories: []*v1alpha1.Repository{repo},
+		},
+		{
+			name:         "repository without its secret and another repository token",
+			secrets:      []*corev1.Secret{otherTokenSecret},
+			repositories: []*v1alpha1.Repository{repo, otherRepo},
+		},
+		{
+			name:         "only a repository without its secret",
+			repositories: []*v1alpha1.Repository{repo},
+			wantErr:      "the git provider token of the repository ns/repo cannot be read",
+		},
+		{
+			name:         "repository token in vault",
+			repositories: []*v1alpha1.Repository{vaultRepo},
+			wantErr:      "nor repository with a git provider token",
+		},
+		{
+			name:    "invalid github app key",
+			secrets: []*corev1.Secret{appSecret("not a key")},
+			wantErr: "invalid github app private key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SYSTEM_NAMESPACE", "pac")
+			ctx, _ := rtesting.SetupFakeContext(t)
+			cs, _ := testclient.SeedTestData(t, ctx, testclient.Data{Secret: tt.secrets, Repositories: tt.repositories})
+			run := &params.Run{
+				Clients: clients.Clients{
+					PipelineAsCode: cs.PipelineAsCode,
+					Kube:           cs.Kube,
+				},
+			}
+			kint := &kubeinteraction.Interaction{Run: run}
+			err := hasProviderCredentials(ctx, run, kint, cs.RepositoryLister)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestCredentialsStateCache(t *testing.T) {
+	now := time.Now()
+	calls := 0
+	credentials := &credentialsState{
+		now: func() time.Time { return now },
+		verify: func(context.Context) error {
+			calls++
+			return fmt.Errorf("call %d", calls)
+		},
+	}
+	assert.Error(t, credentials.check(context.Background()), "call 1")
+	now = now.Add(credentialsCheckInterval / 2)
+	assert.Error(t, credentials.check(context.Background()), "call 1")
+	now = now.Add(credentialsCheckInterval)
+	assert.Error(t, credentials.check(context.Background()), "call 2")
+
+	settings := &settingsState{}
+	assert.ErrorContains(t, settings.check(context.Background()), "have not been loaded yet")
+	settings.set(nil)
+	assert.NilError(t, settings.check(context.Background()))
+}
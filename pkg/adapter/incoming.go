@@ -10,12 +10,8 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud"
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketserver"
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github/app"
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab"
 	ktypes "github.com/openshift-pipelines/pipelines-as-code/pkg/secrets/types"
 	"go.uber.org/zap"
 )
@@ -45,6 +41,10 @@ func (l *listener) detectIncoming(ctx context.Context, req *http.Request, payloa
 		return false, nil, fmt.Errorf("cannot find repository %s", repository)
 	}
 
+	if _, ok := formatting.WildcardURL(repo.Spec.URL); ok {
+		return false, nil, fmt.Errorf("repository %s matches the git repositories of %s, incoming webhooks need a repository of a single git repository", repository, repo.Spec.URL)
+	}
+
 	if repo.Spec.Incomings == nil {
 		return false, nil, fmt.Errorf("you need to have incoming webhooks rules in your repo spec, repo: %s", repository)
 	}
@@ -71,6 +71,7 @@ func (l *listener) detectIncoming(ctx context.Context, req *http.Request, payloa
 
 	if repo.Spec.GitProvider == nil || repo.Spec.GitProvider.Type == "" {
 		gh := github.New()
+		gh.Metrics = l.metrics
 		enterpriseURL, token, installationID, err := app.GetAndUpdateInstallationID(ctx, req, l.run, repo, gh)
 		if err != nil {
 			return false, nil, err
@@ -107,25 +108,15 @@ func (l *listener) processIncoming(targetRepo *v1alpha1.Repository) (provider.In
 	l.event.Organization = org
 	l.event.Repository = repo
 
-	var provider provider.Interface
-	if targetRepo.Spec.GitProvider == nil || targetRepo.Spec.GitProvider.Type == "" {
-		provider = github.New()
-	} else {
-		switch targetRepo.Spec.GitProvider.Type {
-		case "github":
-			provider = github.New()
-		case "gitlab":
-			provider = &gitlab.Provider{}
-		case "gitea":
-			provider = &gitea.Provider{}
-		case "bitbucket-cloud":
-			provider = &bitbucketcloud.Provider{}
-		case "bitbucket-server":
-			provider = &bitbucketserver.Provider{}
-		default:
-			return l.processRes(false, nil, l.logger, "", fmt.Errorf("no supported Git provider has been detected"))
-		}
+	gitProvider := "github"
+	if targetRepo.Spec.GitProvider != nil && targetRepo.Spec.GitProvider.Type != "" {
+		gitProvider = targetRepo.Spec.GitProvider.Type
+	}
+	registration, ok := provider.Lookup(gitProvider)
+	if !ok {
+		return l.processRes(false, nil, l.logger, "", fmt.Errorf("no supported Git provider has been detected"))
 	}
+	p := registration.New(l.metrics)
 
-	return l.processRes(true, provider, l.logger.With("provider", "incoming"), "", nil)
+	return l.processRes(true, p, l.logger.With("provider", "incoming"), "", nil)
 }
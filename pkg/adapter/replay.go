@@ -0,0 +1,193 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github/app"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// replayConfigMap is where we store the time of the last processed
+	// webhook, to know since when the webhooks have been missed
+	replayConfigMap  = "pipelines-as-code-webhook-replay"
+	lastProcessedKey = "last-processed"
+	// unfinishedKey are the github deliveries accepted but not processed
+	// before the controller stopped, separated by a comma
+	unfinishedKey = "unfinished-deliveries"
+
+	recordInterval = time.Minute
+)
+
+// processedTime is the time of the last webhook processed by the controller.
+type processedTime struct {
+	mu   sync.Mutex
+	last time.Time
+}
+
+func (p *processedTime) set(t time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.last = t
+}
+
+func (p *processedTime) get() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.last
+}
+
+// replayMissedWebhooks ask GitHub to redeliver the webhooks of the GitHub App
+// missed since the last processed webhook.
+func (l *listener) replayMissedWebhooks(ctx context.Context) {
+	ns := os.Getenv("SYSTEM_NAMESPACE")
+	since, unfinished, claimed, err := claimReplay(ctx, l.run.Clients.Kube, ns, time.Now())
+	if err != nil {
+		l.logger.Errorf("cannot get the time of the last processed webhook: %v", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	client, err := app.NewAppClient(ctx, l.run)
+	if err != nil {
+		l.logger.Errorf("cannot create the github app client to replay the missed webhooks: %v", err)
+		return
+	}
+	redelivered, err := app.RedeliverMissedDeliveries(ctx, client, since, unfinished, l.logger)
+	if err != nil {
+		l.logger.Errorf("cannot replay the missed webhooks: %v", err)
+	}
+	l.logger.Infof("replayed %d missed github app webhooks since %s", redelivered, since.Format(time.RFC3339))
+}
+
+// recordProcessedTime store the time of the last processed webhook at every
+// interval, as long as the replay of the missed webhooks is enabled.
+func (l *listener) recordProcessedTime(ctx context.Context) {
+	ns := os.Getenv("SYSTEM_NAMESPACE")
+	ticker := time.NewTicker(recordInterval)
+	defer ticker.Stop()
+	recorded := time.Time{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			last := l.processed.get()
+			if !l.run.Info.Pac.ReplayMissedWebhooks || !last.After(recorded) {
+				continue
+			}
+			if err := recordProcessed(ctx, l.run.Clients.Kube, ns, last); err != nil {
+				l.logger.Errorf("cannot record the time of the last processed webhook: %v", err)
+				continue
+			}
+			recorded = last
+		}
+	}
+}
+
+// claimReplay get the time of the last processed webhook and replace it by
+// now, only one of the replicas of the controller can replace it and replay
+// the missed webhooks along with the unfinished ones. There is nothing to
+// replay on the first start, we only record the time.
+func claimReplay(ctx context.Context, kube kubernetes.Interface, ns string, now time.Time) (time.Time, []string, bool, error) {
+	cm, err := kube.CoreV1().ConfigMaps(ns).Get(ctx, replayConfigMap, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return time.Time{}, nil, false, createReplayConfigMap(ctx, kube, ns, now)
+	}
+	if err != nil {
+		return time.Time{}, nil, false, err
+	}
+
+	since, err := time.Parse(time.RFC3339, cm.Data[lastProcessedKey])
+	if err != nil {
+		return time.Time{}, nil, false, fmt.Errorf("cannot parse the %s of the configmap %s: %w", lastProcessedKey, replayConfigMap, err)
+	}
+	unfinished := []string{}
+	if value := cm.Data[unfinishedKey]; value != "" {
+		unfinished = strings.Split(value, ",")
+	}
+	cm.Data[lastProcessedKey] = now.UTC().Format(time.RFC3339)
+	delete(cm.Data, unfinishedKey)
+	if _, err := kube.CoreV1().ConfigMaps(ns).Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		if errors.IsConflict(err) {
+			// another replica is replaying them
+			return time.Time{}, nil, false, nil
+		}
+		return time.Time{}, nil, false, err
+	}
+	return since, unfinished, true, nil
+}
+
+// recordProcessed store the time of the last processed webhook, unless
+// another replica has stored a later one.
+func recordProcessed(ctx context.Context, kube kubernetes.Interface, ns string, last time.Time) error {
+	cm, err := kube.CoreV1().ConfigMaps(ns).Get(ctx, replayConfigMap, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return createReplayConfigMap(ctx, kube, ns, last)
+	}
+	if err != nil {
+		return err
+	}
+	if recorded, err := time.Parse(time.RFC3339, cm.Data[lastProcessedKey]); err == nil && !last.After(recorded) {
+		return nil
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[lastProcessedKey] = last.UTC().Format(time.RFC3339)
+	if _, err := kube.CoreV1().ConfigMaps(ns).Update(ctx, cm, metav1.UpdateOptions{}); err != nil && !errors.IsConflict(err) {
+		return err
+	}
+	return nil
+}
+
+// recordUnfinished store the github deliveries not processed before the
+// controller stopped, for the next start to replay them. The time of the last
+// processed webhook is moved back to the oldest of them, the deliveries are
+// only listed until then.
+func recordUnfinished(ctx context.Context, kube kubernetes.Interface, ns string, oldest time.Time, deliveries []string) error {
+	cm, err := kube.CoreV1().ConfigMaps(ns).Get(ctx, replayConfigMap, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if err := createReplayConfigMap(ctx, kube, ns, oldest); err != nil {
+			return err
+		}
+		cm, err = kube.CoreV1().ConfigMaps(ns).Get(ctx, replayConfigMap, metav1.GetOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	if recorded, err := time.Parse(time.RFC3339, cm.Data[lastProcessedKey]); err != nil || oldest.Before(recorded) {
+		cm.Data[lastProcessedKey] = oldest.UTC().Format(time.RFC3339)
+	}
+	if previous := cm.Data[unfinishedKey]; previous != "" {
+		// another replica has stopped with unfinished deliveries too
+		deliveries = append(strings.Split(previous, ","), deliveries...)
+	}
+	cm.Data[unfinishedKey] = strings.Join(deliveries, ",")
+	_, err = kube.CoreV1().ConfigMaps(ns).Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+func createReplayConfigMap(ctx context.Context, kube kubernetes.Interface, ns string, last time.Time) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: replayConfigMap, Namespace: ns},
+		Data:       map[string]string{lastProcessedKey: last.UTC().Format(time.RFC3339)},
+	}
+	if _, err := kube.CoreV1().ConfigMaps(ns).Create(ctx, cm, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package adapter
+
+import (
+	"testing"
+	"time"
+
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestClaimReplay(t *testing.T) {
+	ns := "pipelines-as-code"
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	last := now.Add(-time.Hour)
+	tests := []struct {
+		name           string
+		configMap      *corev1.ConfigMap
+		wantClaimed    bool
+		wantUnfinished []string
+		wantErr        string
+	}{
+		{
+			name: "first start",
+		},
+		{
+			name: "missed since the last processed",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: replayConfigMap, Namespace: ns},
+				Data:       map[string]string{lastProcessedKey: last.Format(time.RFC3339)},
+			},
+			wantClaimed:    true,
+			wantUnfinished: []string{},
+		},
+		{
+			name: "unfinished deliveries",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: replayConfigMap, Namespace: ns},
+				Data: map[string]string{
+					lastProcessedKey: last.Format(time.RFC3339),
+					unfinishedKey:    "push,pull-request",
+				},
+			},
+			wantClaimed:    true,
+			wantUnfinished: []string{"push", "pull-request"},
+		},
+		{
+			name: "bad time",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: replayConfigMap, Namespace: ns},
+				Data:       map[string]string{lastProcessedKey: "yesterday"},
+			},
+			wantErr: "cannot parse the last-processed of the configmap pipelines-as-code-webhook-replay",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+			if tt.configMap != nil {
+				_, err := stdata.Kube.CoreV1().ConfigMaps(ns).Create(ctx, tt.configMap, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+
+			since, unfinished, claimed, err := claimReplay(ctx, stdata.Kube, ns, now)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, claimed, tt.wantClaimed)
+			if tt.wantClaimed {
+				assert.Assert(t, since.Equal(last))
+			}
+			assert.DeepEqual(t, unfinished, tt.wantUnfinished)
+
+			cm, err := stdata.Kube.CoreV1().ConfigMaps(ns).Get(ctx, replayConfigMap, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, cm.Data[lastProcessedKey], now.Format(time.RFC3339))
+			_, ok := cm.Data[unfinishedKey]
+			assert.Assert(t, !ok)
+		})
+	}
+}
+
+func TestRecordProcessed(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	ns := "pipelines-as-code"
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorded := func() string {
+		cm, err := stdata.Kube.CoreV1().ConfigMaps(ns).Get(ctx, replayConfigMap, metav1.GetOptions{})
+		assert.NilError(t, err)
+		return cm.Data[lastProcessedKey]
+	}
+
+	assert.NilError(t, recordProcessed(ctx, stdata.Kube, ns, now))
+	assert.Equal(t, recorded(), now.Format(time.RFC3339))
+
+	assert.NilError(t, recordProcessed(ctx, stdata.Kube, ns, now.Add(time.Minute)))
+	assert.Equal(t, recorded(), now.Add(time.Minute).Format(time.RFC3339))
+
+	// another replica has recorded a later time
+	assert.NilError(t, recordProcessed(ctx, stdata.Kube, ns, now))
+	assert.Equal(t, recorded(), now.Add(time.Minute).Format(time.RFC3339))
+}
+
+func TestRecordUnfinished(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	ns := "pipelines-as-code"
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorded := func() map[string]string {
+		cm, err := stdata.Kube.CoreV1().ConfigMaps(ns).Get(ctx, replayConfigMap, metav1.GetOptions{})
+		assert.NilError(t, err)
+		return cm.Data
+	}
+
+	assert.NilError(t, recordUnfinished(ctx, stdata.Kube, ns, now, []string{"push"}))
+	assert.DeepEqual(t, recorded(), map[string]string{
+		lastProcessedKey: now.Format(time.RFC3339),
+		unfinishedKey:    "push",
+	})
+
+	// the time is only moved back
+	assert.NilError(t, recordUnfinished(ctx, stdata.Kube, ns, now.Add(time.Minute), []string{"pull-request"}))
+	assert.DeepEqual(t, recorded(), map[string]string{
+		lastProcessedKey: now.Format(time.RFC3339),
+		unfinishedKey:    "push,pull-request",
+	})
+
+	assert.NilError(t, recordUnfinished(ctx, stdata.Kube, ns, now.Add(-time.Minute), []string{"issue-comment"}))
+	assert.DeepEqual(t, recorded(), map[string]string{
+		lastProcessedKey: now.Add(-time.Minute).Format(time.RFC3339),
+		unfinishedKey:    "push,pull-request,issue-comment",
+	})
+}
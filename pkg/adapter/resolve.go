@@ -0,0 +1,49 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/resolve"
+	"go.uber.org/zap"
+)
+
+const (
+	// resolvePortEnv is the port the resolve endpoint is served on, it is not
+	// served when unset. The endpoint is not authenticated, the port is not
+	// the one of the webhooks and must not be exposed outside of the cluster.
+	resolvePortEnv = "PAC_CONTROLLER_RESOLVE_PORT"
+	resolvePath    = "/resolve"
+)
+
+// newResolveServer returns the server of the resolve endpoint, for tools
+// wanting to resolve their PipelineRuns like we do.
+func newResolveServer(logger *zap.SugaredLogger, port string) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(resolvePath, resolve.Handler(logger))
+	return &http.Server{
+		Addr:              ":" + port,
+		Handler:           http.TimeoutHandler(mux, 10*time.Second, "Resolve Timeout!\n"),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// startResolveServer serves the resolve endpoint on its own port until the
+// controller is stopping.
+func (l *listener) startResolveServer(ctx context.Context, port string) {
+	srv := newResolveServer(l.logger, port)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		l.logger.Infof("serving the resolve endpoint on the port %s", port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			l.logger.Errorf("cannot serve the resolve endpoint: %v", err)
+		}
+	}()
+}
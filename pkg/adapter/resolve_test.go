@@ -0,0 +1,24 @@
+package adapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"gotest.tools/v3/assert"
+)
+
+func TestNewResolveServer(t *testing.T) {
+	srv := newResolveServer(zap.NewNop().Sugar(), "8082")
+	assert.Equal(t, ":8082", srv.Addr)
+
+	recorder := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, resolvePath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	srv.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
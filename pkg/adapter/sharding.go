@@ -0,0 +1,397 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"go.uber.org/zap"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// shardingEnv enables the sharding of the events by repository between
+	// the replicas of the controller
+	shardingEnv = "PAC_CONTROLLER_SHARDING"
+
+	// forwardedHeader is set on the events forwarded to the replica owning
+	// their repository, with the name of the replica forwarding them
+	forwardedHeader = "X-Pipelines-As-Code-Forwarded-By"
+	// forwardedTimestampHeader and forwardedSignatureHeader authenticate the
+	// forwarded events, the signature is a HMAC-SHA256 of the timestamp, the
+	// forwarding replica and the payload with the sharding secret
+	forwardedTimestampHeader = "X-Pipelines-As-Code-Forwarded-Timestamp"
+	forwardedSignatureHeader = "X-Pipelines-As-Code-Forwarded-Signature"
+	// forwardMaxAge is how long a forwarded event is accepted after having
+	// been signed
+	forwardMaxAge = time.Minute
+
+	// shardingSecret is the secret shared by the replicas to sign the events
+	// they forward, in the namespace of the controller
+	shardingSecret    = "pipelines-as-code-sharding-secret"
+	shardingSecretKey = "secret"
+	// controllerService is the service the certificate of the controller is
+	// issued for, the replicas check it when they forward an event
+	controllerService = "pipelines-as-code-controller"
+
+	// replicaComponent is the component label of the Leases of the replicas
+	replicaComponent   = "controller-replica"
+	replicaAddressKey  = "pipelinesascode.tekton.dev/address"
+	replicaLeaseTime   = 30 * time.Second
+	replicaRenewPeriod = 10 * time.Second
+	forwardTimeout     = 10 * time.Second
+)
+
+// clusterCAFiles are the CAs of the cluster mounted in the pods, the service
+// CA is only there on OpenShift.
+var clusterCAFiles = []string{
+	"/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
+	"/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt",
+}
+
+// startSharding joins the replicas the events are sharded between, the
+// replica is reached on the IP of its pod.
+func (l *listener) startSharding(ctx context.Context, port string, tlsEnabled bool) {
+	podIP := os.Getenv("POD_IP")
+	if podIP == "" {
+		l.logger.Errorf("the POD_IP environment variable is needed to shard the events between the replicas, they are not sharded")
+		return
+	}
+	ns := os.Getenv("SYSTEM_NAMESPACE")
+	secret, err := l.run.Clients.Kube.CoreV1().Secrets(ns).Get(ctx, shardingSecret, metav1.GetOptions{})
+	if err != nil || len(secret.Data[shardingSecretKey]) == 0 {
+		l.logger.Errorf("the %s key of the %s secret is needed to shard the events between the replicas, they are not sharded: %v",
+			shardingSecretKey, shardingSecret, err)
+		return
+	}
+	var tlsConfig *tls.Config
+	if tlsEnabled {
+		tlsConfig, err = clusterTLSConfig(fmt.Sprintf("%s.%s.svc", controllerService, ns), clusterCAFiles)
+		if err != nil {
+			l.logger.Errorf("cannot verify the certificates of the replicas, the events are not sharded: %v", err)
+			return
+		}
+	}
+	name := os.Getenv("POD_NAME")
+	if name == "" {
+		name, _ = os.Hostname()
+	}
+	scheme := "http"
+	if tlsEnabled {
+		scheme = "https"
+	}
+	self := replica{name: name, address: fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(podIP, port))}
+	l.shards = newShardRing(l.run.Clients.Kube, ns, self, secret.Data[shardingSecretKey], tlsConfig, l.logger)
+	l.logger.Infof("sharding the events by repository between the replicas, this replica is %s on %s", self.name, self.address)
+	go l.shards.run(ctx)
+}
+
+// clusterTLSConfig verifies the certificates of the replicas with the CAs of
+// the cluster, they are reached by the IP of their pod but their certificate
+// is issued for the service of the controller.
+func clusterTLSConfig(serverName string, caFiles []string) (*tls.Config, error) {
+	pool := x509.NewCertPool()
+	found := false
+	for _, file := range caFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificate found in the CA %s", file)
+		}
+		found = true
+	}
+	if !found {
+		return nil, fmt.Errorf("no CA of the cluster found in %s", strings.Join(caFiles, ", "))
+	}
+	return &tls.Config{RootCAs: pool, ServerName: serverName, MinVersion: tls.VersionTLS12}, nil
+}
+
+// replica is a replica of the controller.
+type replica struct {
+	name    string
+	address string
+}
+
+// shardRing shards the events by repository between the replicas of the
+// controller. Every replica renews a Lease in the namespace of the controller
+// listing its address, the owner of a repository is picked among the replicas
+// with a live Lease with a rendezvous hash so only the repositories of a
+// replica joining or leaving move.
+type shardRing struct {
+	self      replica
+	kube      kubernetes.Interface
+	namespace string
+	logger    *zap.SugaredLogger
+	client    *http.Client
+	now       func() time.Time
+	// key signs the events forwarded to the other replicas and verifies the
+	// ones forwarded by them
+	key []byte
+
+	mu       sync.Mutex
+	replicas []replica
+}
+
+func newShardRing(kube kubernetes.Interface, namespace string, self replica, key []byte, tlsConfig *tls.Config, logger *zap.SugaredLogger) *shardRing {
+	return &shardRing{
+		self:      self,
+		kube:      kube,
+		namespace: namespace,
+		logger:    logger,
+		client: &http.Client{
+			Timeout:   forwardTimeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		now:      time.Now,
+		key:      key,
+		replicas: []replica{self},
+	}
+}
+
+func (s *shardRing) leaseName(name string) string {
+	return fmt.Sprintf("pipelines-as-code-controller-%s", name)
+}
+
+func (s *shardRing) selector() string {
+	return fmt.Sprintf("app.kubernetes.io/component=%s,%s=%s", replicaComponent,
+		keys.Controller, formatting.K8LabelsCleanup(info.ControllerName()))
+}
+
+// run renews the Lease of the replica and refreshes the replicas until the
+// context is done, the Lease is then deleted for the other replicas to take
+// over its repositories right away.
+func (s *shardRing) run(ctx context.Context) {
+	ticker := time.NewTicker(replicaRenewPeriod)
+	defer ticker.Stop()
+	for {
+		if err := s.renew(ctx); err != nil {
+			s.logger.Errorf("cannot renew the lease of the replica %s: %v", s.self.name, err)
+		}
+		if err := s.refresh(ctx); err != nil {
+			s.logger.Errorf("cannot list the replicas of the controller: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			deleteCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			err := s.kube.CoordinationV1().Leases(s.namespace).Delete(deleteCtx, s.leaseName(s.self.name), metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				s.logger.Errorf("cannot delete the lease of the replica %s: %v", s.self.name, err)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *shardRing) renew(ctx context.Context) error {
+	leases := s.kube.CoordinationV1().Leases(s.namespace)
+	now := metav1.NewMicroTime(s.now())
+	seconds := int32(replicaLeaseTime.Seconds())
+	lease, err := leases.Get(ctx, s.leaseName(s.self.name), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.leaseName(s.self.name),
+				Namespace: s.namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/component": replicaComponent,
+					keys.Controller:               formatting.K8LabelsCleanup(info.ControllerName()),
+				},
+				Annotations: map[string]string{replicaAddressKey: s.self.address},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &s.self.name,
+				LeaseDurationSeconds: &seconds,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		_, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if lease.Annotations == nil {
+		lease.Annotations = map[string]string{}
+	}
+	lease.Annotations[replicaAddressKey] = s.self.address
+	lease.Spec.HolderIdentity = &s.self.name
+	lease.Spec.LeaseDurationSeconds = &seconds
+	lease.Spec.RenewTime = &now
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// refresh lists the replicas with a live Lease, the replica itself is always
+// part of them.
+func (s *shardRing) refresh(ctx context.Context) error {
+	leases, err := s.kube.CoordinationV1().Leases(s.namespace).List(ctx, metav1.ListOptions{LabelSelector: s.selector()})
+	if err != nil {
+		return err
+	}
+	replicas := []replica{s.self}
+	now := s.now()
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == s.self.name || leaseExpired(lease, now) {
+			continue
+		}
+		address := lease.Annotations[replicaAddressKey]
+		if address == "" {
+			continue
+		}
+		replicas = append(replicas, replica{name: *lease.Spec.HolderIdentity, address: address})
+	}
+	sort.Slice(replicas, func(i, j int) bool { return replicas[i].name < replicas[j].name })
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replicas = replicas
+	return nil
+}
+
+func leaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	return lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second).Before(now)
+}
+
+// shardKey is the key of the repository an event is sharded by, the URL of
+// the repository.
+func shardKey(url string) string {
+	return strings.ToLower(strings.TrimSuffix(url, "/"))
+}
+
+// owner returns the replica owning a repository, the one with the highest
+// hash of the repository and its name.
+func (s *shardRing) owner(key string) replica {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	owner, highest := s.self, uint64(0)
+	for i, r := range s.replicas {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(key + "\x00" + r.name))
+		if sum := h.Sum64(); i == 0 || sum > highest {
+			owner, highest = r, sum
+		}
+	}
+	return owner
+}
+
+// forward sends the event to the replica owning its repository, with the
+// headers and the query of the original request for the replica to validate
+// it again.
+func (s *shardRing) forward(ctx context.Context, owner replica, request *http.Request, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, request.Method, owner.address+request.URL.RequestURI(), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header = request.Header.Clone()
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	req.Header.Set(forwardedHeader, s.self.name)
+	req.Header.Set(forwardedTimestampHeader, timestamp)
+	req.Header.Set(forwardedSignatureHeader, s.sign(timestamp, s.self.name, payload))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("the replica %s answered with the status %d", owner.name, resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *shardRing) sign(timestamp, forwarder string, payload []byte) string {
+	mac := hmac.New(sha256.New, s.key)
+	_, _ = mac.Write([]byte(timestamp + "\n" + forwarder + "\n"))
+	_, _ = mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks an event has been forwarded by another replica, signed with
+// the sharding secret less than forwardMaxAge ago.
+func (s *shardRing) verify(header http.Header, payload []byte) error {
+	timestamp := header.Get(forwardedTimestampHeader)
+	signature := header.Get(forwardedSignatureHeader)
+	if timestamp == "" || signature == "" {
+		return fmt.Errorf("the forwarded event is not signed")
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp of the forwarded event: %w", err)
+	}
+	if age := s.now().Sub(time.Unix(seconds, 0)); age > forwardMaxAge || age < -forwardMaxAge {
+		return fmt.Errorf("the forwarded event has been signed %s ago", age.Round(time.Second))
+	}
+	expected := s.sign(timestamp, header.Get(forwardedHeader), payload)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return fmt.Errorf("invalid signature of the forwarded event")
+	}
+	return nil
+}
+
+// repositoryLocks serializes the processing of the events of each repository
+// on the replica owning it.
+type repositoryLocks struct {
+	mu    sync.Mutex
+	locks map[string]*repositoryLock
+}
+
+type repositoryLock struct {
+	sync.Mutex
+	users int
+}
+
+func newRepositoryLocks() *repositoryLocks {
+	return &repositoryLocks{locks: map[string]*repositoryLock{}}
+}
+
+// lock waits for the other events of the repository to be processed, the
+// returned function releases the repository.
+func (r *repositoryLocks) lock(key string) func() {
+	r.mu.Lock()
+	l, ok := r.locks[key]
+	if !ok {
+		l = &repositoryLock{}
+		r.locks[key] = l
+	}
+	l.users++
+	r.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		l.users--
+		if l.users == 0 {
+			delete(r.locks, key)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package adapter
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"go.uber.org/zap"
+	"gotest.tools/v3/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestShardRingRefresh(t *testing.T) {
+	ns := "pipelines-as-code"
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	newLease := func(name, controller, address string, renewed time.Duration) *coordinationv1.Lease {
+		renewTime := metav1.NewMicroTime(now.Add(-renewed))
+		seconds := int32(replicaLeaseTime.Seconds())
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pipelines-as-code-controller-" + name,
+				Namespace: ns,
+				Labels: map[string]string{
+					"app.kubernetes.io/component": replicaComponent,
+					keys.Controller:               controller,
+				},
+				Annotations: map[string]string{replicaAddressKey: address},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &name,
+				LeaseDurationSeconds: &seconds,
+				RenewTime:            &renewTime,
+			},
+		}
+	}
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	for _, lease := range []*coordinationv1.Lease{
+		newLease("live", info.DefaultControllerName, "http://10.0.0.2:8080", time.Second),
+		newLease("expired", info.DefaultControllerName, "http://10.0.0.3:8080", time.Hour),
+		newLease("other-controller", "staging", "http://10.0.0.4:8080", time.Second),
+		newLease("no-address", info.DefaultControllerName, "", time.Second),
+	} {
+		_, err := stdata.Kube.CoordinationV1().Leases(ns).Create(ctx, lease, metav1.CreateOptions{})
+		assert.NilError(t, err)
+	}
+
+	self := replica{name: "self", address: "http://10.0.0.1:8080"}
+	ring := newShardRing(stdata.Kube, ns, self, nil, nil, zap.NewNop().Sugar())
+	ring.now = func() time.Time { return now }
+	assert.NilError(t, ring.renew(ctx))
+	assert.NilError(t, ring.refresh(ctx))
+	assert.DeepEqual(t, ring.replicas, []replica{{name: "live", address: "http://10.0.0.2:8080"}, self},
+		cmp.AllowUnexported(replica{}))
+
+	// the lease of the replica is renewed
+	ring.now = func() time.Time { return now.Add(time.Minute) }
+	assert.NilError(t, ring.renew(ctx))
+	lease, err := stdata.Kube.CoordinationV1().Leases(ns).Get(ctx, "pipelines-as-code-controller-self", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, lease.Spec.RenewTime.Time.Equal(now.Add(time.Minute)))
+	assert.Equal(t, lease.Annotations[replicaAddressKey], self.address)
+}
+
+func TestShardRingOwner(t *testing.T) {
+	ring := newShardRing(nil, "", replica{name: "a"}, nil, nil, zap.NewNop().Sugar())
+	ring.replicas = []replica{{name: "a"}, {name: "b"}, {name: "c"}}
+
+	owners := map[string]string{}
+	counts := map[string]int{}
+	for i := 0; i < 300; i++ {
+		key := shardKey(fmt.Sprintf("https://github.com/org/repo-%d/", i))
+		owner := ring.owner(key)
+		assert.Equal(t, ring.owner(key), owner, "the owner of %s is not stable", key)
+		owners[key] = owner.name
+		counts[owner.name]++
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		assert.Assert(t, counts[name] > 50, "the replica %s owns only %d repositories", name, counts[name])
+	}
+
+	// only the repositories of the replica leaving move
+	ring.replicas = []replica{{name: "a"}, {name: "c"}}
+	for key, previous := range owners {
+		if previous != "b" {
+			assert.Equal(t, ring.owner(key).name, previous, key)
+		}
+	}
+	assert.Equal(t, shardKey("https://GitHub.com/Org/Repo/"), "https://github.com/org/repo")
+}
+
+func TestShardRingForward(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    int
+		wantErr string
+	}{
+		{name: "accepted", code: http.StatusAccepted},
+		{name: "skipped", code: http.StatusOK},
+		{name: "failed", code: http.StatusInternalServerError, wantErr: "the replica owner answered with the status 500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := &http.Request{}
+			body := ""
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				payload, _ := io.ReadAll(r.Body)
+				body = string(payload)
+				w.WriteHeader(tt.code)
+			}))
+			defer ts.Close()
+
+			ring := newShardRing(nil, "", replica{name: "self"}, []byte("secret"), nil, zap.NewNop().Sugar())
+			request := httptest.NewRequest(http.MethodPost, "http://controller/incoming?repository=repo&secret=secret", nil)
+			request.Header.Set("X-GitHub-Event", "push")
+			err := ring.forward(context.Background(), replica{name: "owner", address: ts.URL}, request, []byte(`{"payload": true}`))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, received.URL.RequestURI(), "/incoming?repository=repo&secret=secret")
+			assert.Equal(t, received.Header.Get("X-GitHub-Event"), "push")
+			assert.Equal(t, received.Header.Get(forwardedHeader), "self")
+			assert.Equal(t, body, `{"payload": true}`)
+			assert.NilError(t, ring.verify(received.Header, []byte(body)))
+		})
+	}
+}
+
+func TestShardRingVerify(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	payload := []byte(`{"payload": true}`)
+	ring := newShardRing(nil, "", replica{name: "owner"}, []byte("secret"), nil, zap.NewNop().Sugar())
+	ring.now = func() time.Time { return now }
+	signed := func(key, forwarder string, signedAt time.Time) http.Header {
+		signer := newShardRing(nil, "", replica{name: forwarder}, []byte(key), nil, zap.NewNop().Sugar())
+		timestamp := fmt.Sprintf("%d", signedAt.Unix())
+		return http.Header{
+			forwardedHeader:          []string{forwarder},
+			forwardedTimestampHeader: []string{timestamp},
+			forwardedSignatureHeader: []string{signer.sign(timestamp, forwarder, payload)},
+		}
+	}
+	renamed := signed("secret", "self", now)
+	renamed.Set(forwardedHeader, "other")
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		payload []byte
+		wantErr string
+	}{
+		{name: "signed", header: signed("secret", "self", now), payload: payload},
+		{name: "signed recently", header: signed("secret", "self", now.Add(-30*time.Second)), payload: payload},
+		{name: "not signed", header: http.Header{forwardedHeader: []string{"self"}}, payload: payload, wantErr: "the forwarded event is not signed"},
+		{name: "other secret", header: signed("other", "self", now), payload: payload, wantErr: "invalid signature of the forwarded event"},
+		{name: "other payload", header: signed("secret", "self", now), payload: []byte("{}"), wantErr: "invalid signature of the forwarded event"},
+		{name: "other forwarder", header: renamed, payload: payload, wantErr: "invalid signature of the forwarded event"},
+		{name: "expired", header: signed("secret", "self", now.Add(-2*time.Minute)), payload: payload, wantErr: "the forwarded event has been signed 2m0s ago"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ring.verify(tt.header, tt.payload)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestClusterTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := filepath.Join(dir, "ca.crt")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	assert.NilError(t, os.WriteFile(ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	_, err := clusterTLSConfig("example.com", []string{filepath.Join(dir, "missing.crt")})
+	assert.ErrorContains(t, err, "no CA of the cluster found")
+
+	// the certificate of the test server is issued for example.com and its
+	// subdomains
+	config, err := clusterTLSConfig("example.com", []string{filepath.Join(dir, "missing.crt"), ca})
+	assert.NilError(t, err)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+	resp, err := client.Get(server.URL)
+	assert.NilError(t, err)
+	resp.Body.Close()
+
+	config, err = clusterTLSConfig("controller.pipelines-as-code.svc", []string{ca})
+	assert.NilError(t, err)
+	client = &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+	_, err = client.Get(server.URL)
+	assert.ErrorContains(t, err, "certificate is valid for")
+}
+
+func TestRepositoryLocks(t *testing.T) {
+	locks := newRepositoryLocks()
+	var mu sync.Mutex
+	order := []string{}
+	unlock := locks.lock("repo")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer locks.lock("repo")()
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, "second")
+	}()
+
+	// another repository is not waiting
+	locks.lock("other")()
+
+	// let the goroutine wait for the lock
+	time.Sleep(20 * time.Millisecond)
+	mu.Lock()
+	order = append(order, "first")
+	mu.Unlock()
+	unlock()
+	<-done
+
+	assert.DeepEqual(t, order, []string{"first", "second"})
+	assert.Equal(t, len(locks.locks), 0)
+}
+
+func TestProcessEventForwarded(t *testing.T) {
+	forwarded := make(chan string, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded <- r.Header.Get(forwardedHeader)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	self := replica{name: "self"}
+	ring := newShardRing(nil, "", self, []byte("secret"), nil, zap.NewNop().Sugar())
+	ring.replicas = []replica{self, {name: "owner", address: ts.URL}}
+	url := ""
+	for i := 0; url == ""; i++ {
+		if candidate := fmt.Sprintf("https://github.com/org/repo-%d", i); ring.owner(shardKey(candidate)).name == "owner" {
+			url = candidate
+		}
+	}
+
+	event := info.NewEvent()
+	event.EventType = "incoming"
+	event.URL = url
+	s := sinker{
+		event:   event,
+		logger:  zap.NewNop().Sugar(),
+		payload: []byte("{}"),
+		shards:  ring,
+		locks:   newRepositoryLocks(),
+	}
+	request := httptest.NewRequest(http.MethodPost, "http://controller/incoming", strings.NewReader("{}"))
+	assert.NilError(t, s.processEvent(context.Background(), request))
+	assert.Equal(t, <-forwarded, "self")
+}
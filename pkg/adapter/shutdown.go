@@ -0,0 +1,179 @@
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// shutdownTimeoutEnv is the time given to the in-flight events to be
+	// processed when the controller is stopping, it should be lower than the
+	// terminationGracePeriodSeconds of the pod
+	shutdownTimeoutEnv     = "PAC_SHUTDOWN_TIMEOUT"
+	defaultShutdownTimeout = 25 * time.Second
+
+	// deliveryHeader is the GUID of the delivery of a github webhook
+	deliveryHeader = "X-GitHub-Delivery"
+	// deliveredMargin is the time between the delivery of a webhook by github
+	// and its reception by the controller
+	deliveredMargin = time.Minute
+)
+
+// inflightEvent is an event being processed.
+type inflightEvent struct {
+	received time.Time
+	// delivery is the GUID of its github delivery, if any
+	delivery string
+}
+
+// inflightEvents are the events being processed by the controller, for the
+// controller to finish them before stopping.
+type inflightEvents struct {
+	mu     sync.Mutex
+	next   uint64
+	events map[uint64]inflightEvent
+	idle   chan struct{}
+}
+
+func newInflightEvents() *inflightEvents {
+	return &inflightEvents{events: map[uint64]inflightEvent{}}
+}
+
+// add an event received at a time, the returned id has to be passed to done
+// once the event is processed
+func (i *inflightEvents) add(received time.Time, delivery string) uint64 {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.next++
+	i.events[i.next] = inflightEvent{received: received, delivery: delivery}
+	return i.next
+}
+
+func (i *inflightEvents) done(id uint64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.events, id)
+	if len(i.events) == 0 && i.idle != nil {
+		close(i.idle)
+		i.idle = nil
+	}
+}
+
+// drain waits for the in-flight events to be processed, it returns false when
+// the context is done before.
+func (i *inflightEvents) drain(ctx context.Context) bool {
+	i.mu.Lock()
+	if len(i.events) == 0 {
+		i.mu.Unlock()
+		return true
+	}
+	if i.idle == nil {
+		i.idle = make(chan struct{})
+	}
+	idle := i.idle
+	i.mu.Unlock()
+
+	select {
+	case <-idle:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// unfinished returns the time the oldest in-flight event has been received
+// and the github deliveries of the in-flight events.
+func (i *inflightEvents) unfinished() (time.Time, []string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	oldest := time.Time{}
+	deliveries := []string{}
+	for _, event := range i.events {
+		if oldest.IsZero() || event.received.Before(oldest) {
+			oldest = event.received
+		}
+		if event.delivery != "" {
+			deliveries = append(deliveries, event.delivery)
+		}
+	}
+	sort.Strings(deliveries)
+	return oldest, deliveries
+}
+
+func (i *inflightEvents) count() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return len(i.events)
+}
+
+// detachedContext keeps the values of its parent but not its cancellation, the
+// events keep being processed while the controller is stopping.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (detachedContext) Done() <-chan struct{} { return nil }
+
+func (detachedContext) Err() error { return nil }
+
+func shutdownTimeout() time.Duration {
+	if value := os.Getenv(shutdownTimeoutEnv); value != "" {
+		if timeout, err := time.ParseDuration(value); err == nil {
+			return timeout
+		}
+	}
+	return defaultShutdownTimeout
+}
+
+// shutdown stops accepting the webhooks, waits for the in-flight events to be
+// processed and records the time of the last processed webhook for the
+// replay. The github deliveries of the events not processed in time are
+// recorded to be replayed on the next start.
+func (l *listener) shutdown(srv *http.Server, cancelEvents context.CancelFunc) {
+	defer cancelEvents()
+	timeout := shutdownTimeout()
+	l.logger.Infof("stopping Pipelines as Code, waiting up to %s for %d in-flight events", timeout, l.inflight.count())
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		l.logger.Errorf("cannot stop the server gracefully: %v", err)
+	}
+
+	oldest, deliveries := time.Time{}, []string{}
+	if !l.inflight.drain(ctx) {
+		oldest, deliveries = l.inflight.unfinished()
+		l.logger.Warnf("%d events have not been processed before stopping, received since %s",
+			l.inflight.count(), oldest.Format(time.RFC3339))
+	}
+
+	// the context of the shutdown may be expired already
+	recordCtx, recordCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer recordCancel()
+	ns := os.Getenv("SYSTEM_NAMESPACE")
+	if l.deliveries != nil && l.run.Info.Pac.DeliveryDeduplicationWindow > 0 {
+		if err := l.deliveries.record(recordCtx, l.run.Clients.Kube, ns, time.Now()); err != nil {
+			l.logger.Errorf("cannot record the processed webhook deliveries: %v", err)
+		}
+	}
+	if !l.run.Info.Pac.ReplayMissedWebhooks {
+		return
+	}
+	if last := l.processed.get(); !last.IsZero() {
+		if err := recordProcessed(recordCtx, l.run.Clients.Kube, ns, last); err != nil {
+			l.logger.Errorf("cannot record the time of the last processed webhook: %v", err)
+		}
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+	if err := recordUnfinished(recordCtx, l.run.Clients.Kube, ns, oldest.Add(-deliveredMargin), deliveries); err != nil {
+		l.logger.Errorf("cannot record the unfinished github deliveries %v: %v", deliveries, err)
+	}
+}
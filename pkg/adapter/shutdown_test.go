@@ -0,0 +1,124 @@
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"go.uber.org/zap"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestInflightEventsDrain(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	inflight := newInflightEvents()
+	assert.Assert(t, inflight.drain(context.Background()))
+
+	first := inflight.add(now, "")
+	second := inflight.add(now.Add(time.Minute), "push")
+	oldest, deliveries := inflight.unfinished()
+	assert.Assert(t, oldest.Equal(now))
+	assert.DeepEqual(t, deliveries, []string{"push"})
+
+	inflight.done(first)
+	oldest, _ = inflight.unfinished()
+	assert.Assert(t, oldest.Equal(now.Add(time.Minute)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Assert(t, !inflight.drain(ctx))
+
+	go inflight.done(second)
+	assert.Assert(t, inflight.drain(context.Background()))
+	assert.Equal(t, inflight.count(), 0)
+}
+
+func TestDetachedContext(t *testing.T) {
+	type key struct{}
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	cancel()
+	ctx := detachedContext{parent}
+	assert.NilError(t, ctx.Err())
+	assert.Assert(t, ctx.Done() == nil)
+	assert.Equal(t, ctx.Value(key{}), "value")
+}
+
+func TestShutdown(t *testing.T) {
+	ns := "pipelines-as-code"
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		replay   bool
+		inflight map[string]time.Time
+		wantData map[string]string
+	}{
+		{
+			name:   "drained",
+			replay: true,
+			wantData: map[string]string{
+				lastProcessedKey: now.Add(time.Minute).Format(time.RFC3339),
+			},
+		},
+		{
+			name:   "unfinished deliveries are replayed",
+			replay: true,
+			inflight: map[string]time.Time{
+				"push":         now.Add(time.Second),
+				"pull-request": now.Add(-time.Minute),
+				"":             now.Add(-time.Hour),
+			},
+			wantData: map[string]string{
+				lastProcessedKey: now.Add(-time.Hour - deliveredMargin).Format(time.RFC3339),
+				unfinishedKey:    "pull-request,push",
+			},
+		},
+		{
+			name:     "replay disabled",
+			inflight: map[string]time.Time{"push": now},
+			wantData: map[string]string{
+				lastProcessedKey: now.Format(time.RFC3339),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+			t.Setenv("SYSTEM_NAMESPACE", ns)
+			t.Setenv(shutdownTimeoutEnv, "10ms")
+			assert.NilError(t, recordProcessed(ctx, stdata.Kube, ns, now))
+
+			l := &listener{
+				run: &params.Run{
+					Clients: clients.Clients{Kube: stdata.Kube},
+					Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{
+						ReplayMissedWebhooks: tt.replay,
+					}}},
+				},
+				logger:    zap.NewNop().Sugar(),
+				processed: &processedTime{},
+				inflight:  newInflightEvents(),
+			}
+			l.processed.set(now.Add(time.Minute))
+			for delivery, received := range tt.inflight {
+				l.inflight.add(received, delivery)
+			}
+
+			cancelled := false
+			l.shutdown(&http.Server{}, func() { cancelled = true })
+			assert.Assert(t, cancelled)
+
+			cm, err := stdata.Kube.CoreV1().ConfigMaps(ns).Get(ctx, replayConfigMap, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.DeepEqual(t, cm.Data, tt.wantData)
+		})
+	}
+}
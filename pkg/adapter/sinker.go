@@ -20,6 +20,11 @@ type sinker struct {
 	event   *info.Event
 	logger  *zap.SugaredLogger
 	payload []byte
+	// shards and locks are set when the events are sharded between the
+	// replicas, forwarded when the event has been forwarded by another one
+	shards    *shardRing
+	locks     *repositoryLocks
+	forwarded bool
 }
 
 func (s *sinker) processEventPayload(ctx context.Context, request *http.Request) error {
@@ -36,6 +41,7 @@ func (s *sinker) processEventPayload(ctx context.Context, request *http.Request)
 
 	s.event.Request = &info.Request{
 		Header:  request.Header,
+		Query:   request.URL.Query(),
 		Payload: bytes.TrimSpace(s.payload),
 	}
 	return nil
@@ -48,6 +54,19 @@ func (s *sinker) processEvent(ctx context.Context, request *http.Request) error
 		}
 	}
 
+	if s.shards != nil && s.event.URL != "" {
+		key := shardKey(s.event.URL)
+		if owner := s.shards.owner(key); !s.forwarded && owner.name != s.shards.self.name {
+			err := s.shards.forward(ctx, owner, request, s.payload)
+			if err == nil {
+				s.logger.Infof("forwarded the event of %s to the replica %s owning it", s.event.URL, owner.name)
+				return nil
+			}
+			s.logger.Warnf("cannot forward the event of %s to the replica %s owning it, processing it here: %v", s.event.URL, owner.name, err)
+		}
+		defer s.locks.lock(key)()
+	}
+
 	p := pipelineascode.NewPacs(s.event, s.vcx, s.run, s.kint, s.logger)
 	return p.Run(ctx)
 }
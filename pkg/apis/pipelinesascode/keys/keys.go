@@ -19,35 +19,82 @@ package keys
 import "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
 
 const (
-	Task            = pipelinesascode.GroupName + "/task"
-	Pipeline        = pipelinesascode.GroupName + "/pipeline"
-	URLOrg          = pipelinesascode.GroupName + "/url-org"
-	URLRepository   = pipelinesascode.GroupName + "/url-repository"
-	SHA             = pipelinesascode.GroupName + "/sha"
-	Sender          = pipelinesascode.GroupName + "/sender"
-	EventType       = pipelinesascode.GroupName + "/event-type"
-	Branch          = pipelinesascode.GroupName + "/branch"
-	Repository      = pipelinesascode.GroupName + "/repository"
-	GitProvider     = pipelinesascode.GroupName + "/git-provider"
-	State           = pipelinesascode.GroupName + "/state"
-	ShaTitle        = pipelinesascode.GroupName + "/sha-title"
-	ShaURL          = pipelinesascode.GroupName + "/sha-url"
-	RepoURL         = pipelinesascode.GroupName + "/repo-url"
-	PullRequest     = pipelinesascode.GroupName + "/pull-request"
-	InstallationID  = pipelinesascode.GroupName + "/installation-id"
-	GHEURL          = pipelinesascode.GroupName + "/ghe-url"
-	SourceProjectID = pipelinesascode.GroupName + "/source-project-id"
-	TargetProjectID = pipelinesascode.GroupName + "/target-project-id"
-	OriginalPRName  = pipelinesascode.GroupName + "/original-prname"
-	GitAuthSecret   = pipelinesascode.GroupName + "/git-auth-secret"
-	CheckRunID      = pipelinesascode.GroupName + "/check-run-id"
-	OnEvent         = pipelinesascode.GroupName + "/on-event"
-	OnTargetBranch  = pipelinesascode.GroupName + "/on-target-branch"
-	OnCelExpression = pipelinesascode.GroupName + "/on-cel-expression"
-	TargetNamespace = pipelinesascode.GroupName + "/target-namespace"
-	MaxKeepRuns     = pipelinesascode.GroupName + "/max-keep-runs"
-	LogURL          = pipelinesascode.GroupName + "/log-url"
-	ExecutionOrder  = pipelinesascode.GroupName + "/execution-order"
+	Task             = pipelinesascode.GroupName + "/task"
+	Pipeline         = pipelinesascode.GroupName + "/pipeline"
+	CustomTask       = pipelinesascode.GroupName + "/custom-task"
+	URLOrg           = pipelinesascode.GroupName + "/url-org"
+	URLRepository    = pipelinesascode.GroupName + "/url-repository"
+	SHA              = pipelinesascode.GroupName + "/sha"
+	Sender           = pipelinesascode.GroupName + "/sender"
+	EventType        = pipelinesascode.GroupName + "/event-type"
+	Branch           = pipelinesascode.GroupName + "/branch"
+	Repository       = pipelinesascode.GroupName + "/repository"
+	GitProvider      = pipelinesascode.GroupName + "/git-provider"
+	State            = pipelinesascode.GroupName + "/state"
+	ShaTitle         = pipelinesascode.GroupName + "/sha-title"
+	ShaURL           = pipelinesascode.GroupName + "/sha-url"
+	RepoURL          = pipelinesascode.GroupName + "/repo-url"
+	PullRequest      = pipelinesascode.GroupName + "/pull-request"
+	InstallationID   = pipelinesascode.GroupName + "/installation-id"
+	GHEURL           = pipelinesascode.GroupName + "/ghe-url"
+	GitHubAppID      = pipelinesascode.GroupName + "/github-app-id"
+	SourceProjectID  = pipelinesascode.GroupName + "/source-project-id"
+	TargetProjectID  = pipelinesascode.GroupName + "/target-project-id"
+	OriginalPRName   = pipelinesascode.GroupName + "/original-prname"
+	GitAuthSecret    = pipelinesascode.GroupName + "/git-auth-secret"
+	CheckRunID       = pipelinesascode.GroupName + "/check-run-id"
+	OnEvent          = pipelinesascode.GroupName + "/on-event"
+	OnTargetBranch   = pipelinesascode.GroupName + "/on-target-branch"
+	OnCelExpression  = pipelinesascode.GroupName + "/on-cel-expression"
+	TargetNamespace  = pipelinesascode.GroupName + "/target-namespace"
+	MaxKeepRuns      = pipelinesascode.GroupName + "/max-keep-runs"
+	LogURL           = pipelinesascode.GroupName + "/log-url"
+	ExecutionOrder   = pipelinesascode.GroupName + "/execution-order"
+	Environment      = pipelinesascode.GroupName + "/environment"
+	DeploymentID     = pipelinesascode.GroupName + "/deployment-id"
+	CancelInProgress = pipelinesascode.GroupName + "/cancel-in-progress"
+	CheckRunHash     = pipelinesascode.GroupName + "/check-run-hash"
+	TaskProgress     = pipelinesascode.GroupName + "/task-progress"
+	SupersededBy     = pipelinesascode.GroupName + "/superseded-by"
+	FrozenUntil      = pipelinesascode.GroupName + "/frozen-until"
+	DraftPRs         = pipelinesascode.GroupName + "/draft-pull-requests"
+	OnCheckRun       = pipelinesascode.GroupName + "/on-check-run"
+	OnWorkflowRun    = pipelinesascode.GroupName + "/on-workflow-run"
+	OnLabel          = pipelinesascode.GroupName + "/on-label"
+	OnIssueComment   = pipelinesascode.GroupName + "/on-issue-comment"
+	Issue            = pipelinesascode.GroupName + "/issue"
+	Timeouts         = pipelinesascode.GroupName + "/timeouts"
+	RegistrySecret   = pipelinesascode.GroupName + "/registry-secret"
+	// StatusResyncAttempts is the number of times the final status of a
+	// PipelineRun has been reported again after failing to be reported.
+	StatusResyncAttempts = pipelinesascode.GroupName + "/status-resync-attempts"
+	// MaxDurationExceeded is the max-pipelinerun-duration a PipelineRun has
+	// been cancelled after.
+	MaxDurationExceeded = pipelinesascode.GroupName + "/max-duration-exceeded"
+	// TaskStatuses are the statuses of the tasks of a PipelineRun already
+	// reported as check runs, as a json object by task name.
+	TaskStatuses = pipelinesascode.GroupName + "/task-statuses"
+	// Controller is the name of the controller owning a PipelineRun or a
+	// Repository when multiple Pipelines as Code run on the same cluster.
+	Controller = pipelinesascode.GroupName + "/controller"
+	// RepositoryNamespace is the namespace of the Repository a Namespace has
+	// been created for, Namespaces are cleaned up with their branch only when
+	// it is set.
+	RepositoryNamespace = pipelinesascode.GroupName + "/repository-namespace"
+	// IssueCommentStatus is the last status of a PipelineRun run from the
+	// comment of an issue commented on the issue, it is only commented again
+	// when it changes.
+	IssueCommentStatus = pipelinesascode.GroupName + "/issue-comment-status"
+	// Matrix are the param sets a PipelineRun is run with, one PipelineRun is
+	// created for every one of them.
+	Matrix = pipelinesascode.GroupName + "/matrix"
+	// MatrixPipelineRun is the name of the PipelineRun in the .tekton
+	// directory a PipelineRun of a matrix has been created from, the
+	// original-prname of the PipelineRun has the name of its param set.
+	MatrixPipelineRun = pipelinesascode.GroupName + "/matrix-pipelinerun"
+	// Webhook is the webhook configured by the watcher on the git provider
+	// for a Repository with auto-configure-webhooks, as a json object.
+	Webhook = pipelinesascode.GroupName + "/webhook"
 	// default is "https://api.github.com" but it can be overridden by X-GitHub-Enterprise-Host header
 	APIURL = "https://api.github.com"
 	// installationURL give us the Installation ID
@@ -49,6 +49,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&Repository{},
 		&RepositoryList{},
+		&RepositoryPolicy{},
+		&RepositoryPolicyList{},
+		&PACSettings{},
+		&PACSettingsList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
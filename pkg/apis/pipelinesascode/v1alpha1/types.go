@@ -15,6 +15,58 @@ type Repository struct {
 
 	Spec   RepositorySpec        `json:"spec"`
 	Status []RepositoryRunStatus `json:"pipelinerun_status,omitempty"`
+	// CredentialsStatus is the result of the last periodic validation of the
+	// credentials of the git provider of the Repository
+	// +optional
+	CredentialsStatus *RepositoryCredentialsStatus `json:"credentials_status,omitempty"`
+	// QueueStatus is the concurrency queue of the Repository, persisted for
+	// the watcher to restore it when it restarts
+	// +optional
+	QueueStatus *RepositoryQueueStatus `json:"queue_status,omitempty"`
+}
+
+// RepositoryQueueStatus lists the PipelineRuns of the concurrency queue of the
+// Repository, with a Queued condition reporting if PipelineRuns are waiting
+// for the concurrency limit.
+type RepositoryQueueStatus struct {
+	duckv1.Status `json:",inline"`
+
+	// Running are the PipelineRuns started by the queue as namespace/name
+	// +optional
+	Running []string `json:"running,omitempty"`
+
+	// Pending are the PipelineRuns waiting for their turn, in the order they
+	// are started
+	// +optional
+	Pending []QueuedPipelineRun `json:"pending,omitempty"`
+
+	// LastUpdateTime is the time the queue has been persisted
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// QueuedPipelineRun is a PipelineRun waiting in the concurrency queue.
+type QueuedPipelineRun struct {
+	// Name is the PipelineRun as namespace/name
+	Name string `json:"name"`
+
+	// Position is the position of the PipelineRun in the queue, starting at 1
+	Position int `json:"position"`
+
+	// QueuedTime is the time the PipelineRun has been queued
+	// +optional
+	QueuedTime *metav1.Time `json:"queuedTime,omitempty"`
+}
+
+// RepositoryCredentialsStatus reports if the webhook secret and the token of
+// the Repository are usable and if the webhook is installed on the provider,
+// as one condition for each check and a Ready condition summing them up.
+type RepositoryCredentialsStatus struct {
+	duckv1.Status `json:",inline"`
+
+	// LastCheckTime is the time the credentials have been validated
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
 }
 
 type RepositoryRunStatus struct {
@@ -58,6 +110,58 @@ type RepositoryRunStatus struct {
 
 	// CollectedTaskInfos is the information about tasks
 	CollectedTaskInfos *map[string]TaskInfos `json:"failure_reason,omitempty"`
+
+	// TaskResults is the result of each task of the PipelineRun, sorted by
+	// start time
+	// +optional
+	TaskResults []TaskResult `json:"task_results,omitempty"`
+
+	// TektonResults is where the PipelineRun has been recorded by Tekton
+	// Results, it stays available after the PipelineRun is pruned
+	// +optional
+	TektonResults *TektonResultsRecord `json:"tekton_results,omitempty"`
+}
+
+// TektonResultsRecord is the result and the record of a PipelineRun in Tekton
+// Results
+type TektonResultsRecord struct {
+	// Result is the name of the result, ie: <namespace>/results/<uuid>
+	Result string `json:"result"`
+
+	// Record is the name of the record of the PipelineRun, ie:
+	// <namespace>/results/<uuid>/records/<uuid>
+	Record string `json:"record"`
+}
+
+// TaskResult is the result of a task of a PipelineRun
+type TaskResult struct {
+	// Name is the name of the task in the pipeline
+	Name string `json:"name"`
+
+	// Status is the reason of the task condition (ie: Succeeded, Failed)
+	// +optional
+	Status string `json:"status,omitempty"`
+
+	// StartTime is the time the task started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the task completed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Duration is how long the task took to run
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// LogURL is the url to the log of the task
+	// +optional
+	LogURL string `json:"logurl,omitempty"`
+
+	// FailureSnippet is the last lines of the log of the failed step or the
+	// message of the task condition when it failed
+	// +optional
+	FailureSnippet string `json:"failure_snippet,omitempty"`
 }
 
 type TaskInfos struct {
@@ -74,6 +178,189 @@ type RepositorySpec struct {
 	URL              string       `json:"url"`
 	GitProvider      *GitProvider `json:"git_provider,omitempty"`
 	Incomings        *[]Incoming  `json:"incoming,omitempty"`
+	Rules            *[]Rule      `json:"rules,omitempty"`
+	// MaxParallel is the maximum number of the PipelineRuns matched by the
+	// same event running at the same time, the others are queued
+	MaxParallel *int `json:"max_parallel,omitempty"`
+	// CancelSuperseded cancel the running PipelineRuns of the older commits of
+	// a pull request when a new commit is pushed to it
+	CancelSuperseded bool `json:"cancel_superseded,omitempty"`
+	// FreezeWindows are the periods of time when the matched PipelineRuns
+	// are not run, ie: for a change freeze
+	FreezeWindows []FreezeWindow `json:"freeze_windows,omitempty"`
+	// PipelineRunProvenance is where the PipelineRuns are fetched from, the
+	// source of the event (the default) or the default branch of the repository
+	PipelineRunProvenance string `json:"pipelinerun_provenance,omitempty"`
+	// ForkTektonChanges is what to do when a pull request from a fork changes
+	// the PipelineRuns of the tekton directory, run them (the default), wait
+	// for the approval of a maintainer or run the ones of the default branch
+	ForkTektonChanges string `json:"fork_tekton_changes,omitempty"`
+	// AllowedTaskSources are globs restricting the remote tasks and pipelines
+	// the PipelineRuns can reference in their annotations
+	AllowedTaskSources []string `json:"allowed_task_sources,omitempty"`
+	// Params are added or overridden in the params of all the PipelineRuns
+	Params map[string]string `json:"params,omitempty"`
+	// ProtectedBranchParams are added or overridden in the params of the
+	// PipelineRuns when the event targets a protected branch or tag
+	ProtectedBranchParams map[string]string `json:"protected_branch_params,omitempty"`
+	// CustomParams are template variables extracted from the payload of the
+	// webhook, replaced like the {{ revision }} ones in the PipelineRuns
+	CustomParams []CustomParam `json:"custom_params,omitempty"`
+	// Notifications are where to post a message when the PipelineRuns
+	// start, succeed or fail
+	Notifications []Notification `json:"notifications,omitempty"`
+	// CommentCommands are the permissions needed to run the gitops commands
+	// commented on the pull requests, the users allowed to run the CI can
+	// run the commands not listed
+	CommentCommands []CommentCommandPermission `json:"comment_commands,omitempty"`
+	// AutoOkToTest approves the pull requests of the users not allowed to
+	// run the CI from their teams, the CODEOWNERS or their merged pull
+	// requests, without waiting for an /ok-to-test
+	AutoOkToTest *AutoOkToTest `json:"auto_ok_to_test,omitempty"`
+	// SubPath is the directory of the git repository the Repository is
+	// mapped to, a monorepo can have a Repository for each of its
+	// sub directories with their own .tekton directory
+	SubPath string `json:"sub_path,omitempty"`
+	// Settings are the settings of how Pipelines as Code fetches and reports
+	// on the Repository
+	Settings *Settings `json:"settings,omitempty"`
+	// Mirrors are the other git providers hosting a copy of the git
+	// repository, the final status of the PipelineRuns is reported on them
+	// too, ie: during a migration from one git provider to another
+	Mirrors []Mirror `json:"mirrors,omitempty"`
+}
+
+// Mirror is a copy of the git repository on another git provider, with the
+// same commits.
+type Mirror struct {
+	// URL is the URL of the git repository on the mirror
+	URL string `json:"url"`
+
+	// GitProvider is the type, the API URL and the secret with the token of
+	// the git provider of the mirror, the type is detected for github.com and
+	// gitlab.com
+	GitProvider GitProvider `json:"git_provider"`
+}
+
+// Settings are the settings of how Pipelines as Code fetches and reports the
+// PipelineRuns of a Repository.
+type Settings struct {
+	// CheckRunPerTask reports a GitHub check run for each task of the
+	// PipelineRuns, updated while they run, beside the check run of the
+	// PipelineRun. Only GitHub Apps are supported.
+	// +optional
+	CheckRunPerTask bool `json:"checkrun_per_task,omitempty"`
+
+	// TektonDirFetch is how the tekton directory is fetched, from the content
+	// API of the git provider (api, the default), with a shallow and sparse
+	// git fetch of only the directory (git) or with git only when the content
+	// API fails (git_fallback), ie: when its rate limit is exceeded.
+	// +optional
+	TektonDirFetch string `json:"tekton_dir_fetch,omitempty"`
+}
+
+// CustomParam is a template variable whose value is a CEL expression on the
+// payload of the webhook, ie: body.pull_request.milestone.title.
+type CustomParam struct {
+	// Name is the name of the variable, replaced as {{ name }}
+	Name string `json:"name"`
+
+	// Value is the CEL expression evaluated with the body and the header of
+	// the webhook
+	Value string `json:"value"`
+}
+
+// CommentCommandPermission is the permission needed to run some gitops
+// commands, ie: only letting the maintainers cancel the PipelineRuns.
+type CommentCommandPermission struct {
+	// Commands are the gitops commands, test, retest, cancel, ok-to-test or
+	// help, or the commands commented on the issues without their slash
+	Commands []string `json:"commands"`
+
+	// Permission is who can run the commands: anyone, the author of the pull
+	// request, the members allowed to run the CI or the maintainers of the
+	// repository on the git provider
+	Permission string `json:"permission"`
+}
+
+// AutoOkToTest are the conditions approving the user of a pull request not
+// allowed to run the CI, any of them is enough. Only GitHub is supported.
+type AutoOkToTest struct {
+	// Teams are the slugs of the teams of the organization of the repository
+	// whose members are approved
+	// +optional
+	Teams []string `json:"teams,omitempty"`
+
+	// CodeOwners approves the users owning all the files changed by the pull
+	// request in the CODEOWNERS file of the default branch
+	// +optional
+	CodeOwners bool `json:"codeowners,omitempty"`
+
+	// MergedPullRequests approves the users who have had at least this
+	// number of pull requests merged in the repository
+	// +optional
+	MergedPullRequests int `json:"merged_pull_requests,omitempty"`
+}
+
+// Notification posts a message to Slack, Microsoft Teams or a generic webhook
+// when the PipelineRuns of the Repository start, succeed or fail.
+type Notification struct {
+	// Type is where the message is posted, slack, teams or webhook
+	Type string `json:"type"`
+
+	// Secret is the secret containing the URL of the incoming webhook
+	Secret Secret `json:"secret"`
+
+	// Events are the statuses of the PipelineRuns to notify, started,
+	// succeeded or failed, all of them when empty
+	// +optional
+	Events []string `json:"events,omitempty"`
+
+	// Template is a go template of the message, the text of the message for
+	// slack and teams or the whole body for a generic webhook
+	// +optional
+	Template string `json:"template,omitempty"`
+}
+
+// FreezeWindow is a period of time starting on a cron schedule during which
+// the PipelineRuns matched on the Repository are skipped or queued.
+type FreezeWindow struct {
+	// Schedule is the cron schedule (minute, hour, day of month, month, day
+	// of week) of the start of the window
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window lasts, ie: 2h or 64h
+	Duration string `json:"duration"`
+
+	// Timezone is the timezone of the schedule, UTC by default
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+
+	// Action is what to do with the matched PipelineRuns during the window,
+	// skip them (the default) or queue them until the end of the window
+	// +optional
+	Action string `json:"action,omitempty"`
+}
+
+// Rule maps a set of changed path globs to scheduling hints applied to the
+// matched PipelineRuns, the rules are applied in order and later rules
+// override the values set by earlier ones.
+type Rule struct {
+	// Paths is a list of globs matched against the files changed by the event
+	Paths []string `json:"paths"`
+
+	// Namespace is the namespace where the PipelineRun should target, there
+	// still needs to be a Repository CR in that namespace for that URL.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// NodeSelector is added to the PipelineRun pod template
+	// +optional
+	NodeSelector map[string]string `json:"node_selector,omitempty"`
+
+	// Params are added or overridden in the PipelineRun params
+	// +optional
+	Params map[string]string `json:"params,omitempty"`
 }
 
 type Incoming struct {
@@ -93,6 +380,9 @@ type GitProvider struct {
 type Secret struct {
 	Name string `json:"name"`
 	Key  string `json:"key"`
+	// Source is where the secret is fetched from: kubernetes (the default),
+	// vault or external-secrets
+	Source string `json:"source,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -104,3 +394,153 @@ type RepositoryList struct {
 
 	Items []Repository `json:"items"`
 }
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RepositoryPolicy is a cluster wide policy setting the defaults inherited by
+// the Repositories
+type RepositoryPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RepositoryPolicySpec `json:"spec"`
+}
+
+// RepositoryPolicySpec is the spec of a repository policy, the settings are
+// used when the Repository doesn't set them or when they are enforced
+type RepositoryPolicySpec struct {
+	ConcurrencyLimit      *int              `json:"concurrency_limit,omitempty"`
+	PipelineRunProvenance string            `json:"pipelinerun_provenance,omitempty"`
+	ForkTektonChanges     string            `json:"fork_tekton_changes,omitempty"`
+	AutoOkToTest          *AutoOkToTest     `json:"auto_ok_to_test,omitempty"`
+	AllowedTaskSources    []string          `json:"allowed_task_sources,omitempty"`
+	Params                map[string]string `json:"params,omitempty"`
+	ProtectedBranchParams map[string]string `json:"protected_branch_params,omitempty"`
+	DefaultMaxKeepRuns    *int              `json:"default_max_keep_runs,omitempty"`
+	MaxKeepRunUpperLimit  *int              `json:"max_keep_run_upper_limit,omitempty"`
+	// Namespaces are the namespaces of the Repositories the policy applies
+	// to, all the Repositories of the cluster when empty
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Enforced are the settings of the policy the Repositories cannot
+	// override, ie: concurrency_limit or params
+	// +optional
+	Enforced []string `json:"enforced,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RepositoryPolicyList is the list of RepositoryPolicies
+type RepositoryPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []RepositoryPolicy `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PACSettings are the settings of Pipelines as Code for the whole cluster, the
+// settings it sets override the ones of the pipelines-as-code ConfigMap.
+type PACSettings struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PACSettingsSpec   `json:"spec"`
+	Status PACSettingsStatus `json:"status,omitempty"`
+}
+
+// PACSettingsSpec are the typed settings of the pipelines-as-code ConfigMap,
+// the unset ones are taken from the ConfigMap.
+type PACSettingsSpec struct {
+	ApplicationName                    string `json:"application_name,omitempty"`
+	HubURL                             string `json:"hub_url,omitempty"`
+	HubCatalogName                     string `json:"hub_catalog_name,omitempty"`
+	RemoteTasks                        *bool  `json:"remote_tasks,omitempty"`
+	MaxKeepRunUpperLimit               *int   `json:"max_keep_run_upper_limit,omitempty"`
+	DefaultMaxKeepRuns                 *int   `json:"default_max_keep_runs,omitempty"`
+	BitbucketCloudCheckSourceIP        *bool  `json:"bitbucket_cloud_check_source_ip,omitempty"`
+	BitbucketCloudAdditionalSourceIP   string `json:"bitbucket_cloud_additional_source_ip,omitempty"`
+	TektonDashboardURL                 string `json:"tekton_dashboard_url,omitempty"`
+	AutoConfigureNewGitHubRepo         *bool  `json:"auto_configure_new_github_repo,omitempty"`
+	AutoConfigureRepoNamespaceTemplate string `json:"auto_configure_repo_namespace_template,omitempty"`
+
+	SecretAutoCreate               *bool  `json:"secret_auto_create,omitempty"`
+	SecretGitHubAppTokenScoped     *bool  `json:"secret_github_app_token_scoped,omitempty"`
+	SecretGitHubAppScopeExtraRepos string `json:"secret_github_app_scope_extra_repos,omitempty"`
+
+	ErrorLogSnippet                 *bool  `json:"error_log_snippet,omitempty"`
+	ErrorDetectionFromContainerLogs *bool  `json:"error_detection_from_container_logs,omitempty"`
+	ErrorDetectionMaxNumberOfLines  *int   `json:"error_detection_max_number_of_lines,omitempty"`
+	ErrorDetectionSimpleRegexp      string `json:"error_detection_simple_regexp,omitempty"`
+	ErrorDetectionBlame             string `json:"error_detection_blame,omitempty"`
+
+	TektonLint                 *bool `json:"tekton_lint,omitempty"`
+	ProgressUpdates            *bool `json:"progress_updates,omitempty"`
+	DisablePullRequestComments *bool `json:"disable_pull_request_comments,omitempty"`
+	DisableCommitStatuses      *bool `json:"disable_commit_statuses,omitempty"`
+
+	FreezeWindows string `json:"freeze_windows,omitempty"`
+
+	AutoProvisionRepositories       *bool  `json:"auto_provision_repositories,omitempty"`
+	AutoProvisionNamespaceTemplate  string `json:"auto_provision_namespace_template,omitempty"`
+	AutoProvisionQuota              string `json:"auto_provision_quota,omitempty"`
+	AutoProvisionRepositoryTemplate string `json:"auto_provision_repository_template,omitempty"`
+
+	CloudEventsSinkURL   string `json:"cloudevents_sink_url,omitempty"`
+	ReplayMissedWebhooks *bool  `json:"replay_missed_webhooks,omitempty"`
+
+	DraftPullRequests string `json:"draft_pull_requests,omitempty"`
+
+	BranchCleanup       *bool `json:"branch_cleanup,omitempty"`
+	BranchCleanupDryRun *bool `json:"branch_cleanup_dry_run,omitempty"`
+
+	CredentialsCheckInterval string `json:"credentials_check_interval,omitempty"`
+
+	AutoConfigureWebhooks *bool `json:"auto_configure_webhooks,omitempty"`
+
+	CheckRepositoryAccess *bool `json:"check_repository_access,omitempty"`
+
+	DeliveryDeduplicationWindow string `json:"delivery_deduplication_window,omitempty"`
+
+	MaxPipelineRunDuration string `json:"max_pipelinerun_duration,omitempty"`
+
+	SenderPrivacy string `json:"sender_privacy,omitempty"`
+
+	RegistryCredentialsBrokerURL string `json:"registry_credentials_broker_url,omitempty"`
+
+	LogProxyURL string `json:"log_proxy_url,omitempty"`
+
+	TektonResultsURL string `json:"tekton_results_url,omitempty"`
+
+	VaultAddress   string `json:"vault_address,omitempty"`
+	VaultRole      string `json:"vault_role,omitempty"`
+	VaultAuthMount string `json:"vault_auth_mount,omitempty"`
+	VaultKVMount   string `json:"vault_kv_mount,omitempty"`
+}
+
+// PACSettingsStatus reports if the settings have been applied and their
+// values once merged with the ConfigMap and defaulted
+type PACSettingsStatus struct {
+	duckv1.Status `json:",inline"`
+
+	// AppliedSettings are the settings used by Pipelines as Code, as the keys
+	// of the ConfigMap
+	// +optional
+	AppliedSettings map[string]string `json:"applied_settings,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PACSettingsList is the list of PACSettings
+type PACSettingsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []PACSettings `json:"items"`
+}
@@ -22,15 +22,360 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoOkToTest) DeepCopyInto(out *AutoOkToTest) {
+	*out = *in
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoOkToTest.
+func (in *AutoOkToTest) DeepCopy() *AutoOkToTest {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoOkToTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommentCommandPermission) DeepCopyInto(out *CommentCommandPermission) {
+	*out = *in
+	if in.Commands != nil {
+		in, out := &in.Commands, &out.Commands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommentCommandPermission.
+func (in *CommentCommandPermission) DeepCopy() *CommentCommandPermission {
+	if in == nil {
+		return nil
+	}
+	out := new(CommentCommandPermission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomParam) DeepCopyInto(out *CustomParam) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomParam.
+func (in *CustomParam) DeepCopy() *CustomParam {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomParam)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitProvider) DeepCopyInto(out *GitProvider) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(Secret)
+		**out = **in
+	}
+	if in.WebhookSecret != nil {
+		in, out := &in.WebhookSecret, &out.WebhookSecret
+		*out = new(Secret)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitProvider.
+func (in *GitProvider) DeepCopy() *GitProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(GitProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Mirror) DeepCopyInto(out *Mirror) {
+	*out = *in
+	in.GitProvider.DeepCopyInto(&out.GitProvider)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mirror.
+func (in *Mirror) DeepCopy() *Mirror {
+	if in == nil {
+		return nil
+	}
+	out := new(Mirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Notification) DeepCopyInto(out *Notification) {
+	*out = *in
+	out.Secret = in.Secret
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Notification.
+func (in *Notification) DeepCopy() *Notification {
+	if in == nil {
+		return nil
+	}
+	out := new(Notification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PACSettings) DeepCopyInto(out *PACSettings) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PACSettings.
+func (in *PACSettings) DeepCopy() *PACSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(PACSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PACSettings) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PACSettingsList) DeepCopyInto(out *PACSettingsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PACSettings, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PACSettingsList.
+func (in *PACSettingsList) DeepCopy() *PACSettingsList {
+	if in == nil {
+		return nil
+	}
+	out := new(PACSettingsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PACSettingsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PACSettingsSpec) DeepCopyInto(out *PACSettingsSpec) {
+	*out = *in
+	if in.RemoteTasks != nil {
+		in, out := &in.RemoteTasks, &out.RemoteTasks
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxKeepRunUpperLimit != nil {
+		in, out := &in.MaxKeepRunUpperLimit, &out.MaxKeepRunUpperLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.DefaultMaxKeepRuns != nil {
+		in, out := &in.DefaultMaxKeepRuns, &out.DefaultMaxKeepRuns
+		*out = new(int)
+		**out = **in
+	}
+	if in.BitbucketCloudCheckSourceIP != nil {
+		in, out := &in.BitbucketCloudCheckSourceIP, &out.BitbucketCloudCheckSourceIP
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoConfigureNewGitHubRepo != nil {
+		in, out := &in.AutoConfigureNewGitHubRepo, &out.AutoConfigureNewGitHubRepo
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SecretAutoCreate != nil {
+		in, out := &in.SecretAutoCreate, &out.SecretAutoCreate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SecretGitHubAppTokenScoped != nil {
+		in, out := &in.SecretGitHubAppTokenScoped, &out.SecretGitHubAppTokenScoped
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ErrorLogSnippet != nil {
+		in, out := &in.ErrorLogSnippet, &out.ErrorLogSnippet
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ErrorDetectionFromContainerLogs != nil {
+		in, out := &in.ErrorDetectionFromContainerLogs, &out.ErrorDetectionFromContainerLogs
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ErrorDetectionMaxNumberOfLines != nil {
+		in, out := &in.ErrorDetectionMaxNumberOfLines, &out.ErrorDetectionMaxNumberOfLines
+		*out = new(int)
+		**out = **in
+	}
+	if in.TektonLint != nil {
+		in, out := &in.TektonLint, &out.TektonLint
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ProgressUpdates != nil {
+		in, out := &in.ProgressUpdates, &out.ProgressUpdates
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisablePullRequestComments != nil {
+		in, out := &in.DisablePullRequestComments, &out.DisablePullRequestComments
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableCommitStatuses != nil {
+		in, out := &in.DisableCommitStatuses, &out.DisableCommitStatuses
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoProvisionRepositories != nil {
+		in, out := &in.AutoProvisionRepositories, &out.AutoProvisionRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReplayMissedWebhooks != nil {
+		in, out := &in.ReplayMissedWebhooks, &out.ReplayMissedWebhooks
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BranchCleanup != nil {
+		in, out := &in.BranchCleanup, &out.BranchCleanup
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BranchCleanupDryRun != nil {
+		in, out := &in.BranchCleanupDryRun, &out.BranchCleanupDryRun
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoConfigureWebhooks != nil {
+		in, out := &in.AutoConfigureWebhooks, &out.AutoConfigureWebhooks
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CheckRepositoryAccess != nil {
+		in, out := &in.CheckRepositoryAccess, &out.CheckRepositoryAccess
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PACSettingsSpec.
+func (in *PACSettingsSpec) DeepCopy() *PACSettingsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PACSettingsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PACSettingsStatus) DeepCopyInto(out *PACSettingsStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.AppliedSettings != nil {
+		in, out := &in.AppliedSettings, &out.AppliedSettings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PACSettingsStatus.
+func (in *PACSettingsStatus) DeepCopy() *PACSettingsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PACSettingsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueuedPipelineRun) DeepCopyInto(out *QueuedPipelineRun) {
+	*out = *in
+	if in.QueuedTime != nil {
+		in, out := &in.QueuedTime, &out.QueuedTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueuedPipelineRun.
+func (in *QueuedPipelineRun) DeepCopy() *QueuedPipelineRun {
+	if in == nil {
+		return nil
+	}
+	out := new(QueuedPipelineRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Repository) DeepCopyInto(out *Repository) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
 		*out = make([]RepositoryRunStatus, len(*in))
@@ -38,6 +383,16 @@ func (in *Repository) DeepCopyInto(out *Repository) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CredentialsStatus != nil {
+		in, out := &in.CredentialsStatus, &out.CredentialsStatus
+		*out = new(RepositoryCredentialsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QueueStatus != nil {
+		in, out := &in.QueueStatus, &out.QueueStatus
+		*out = new(RepositoryQueueStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -59,6 +414,27 @@ func (in *Repository) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryCredentialsStatus) DeepCopyInto(out *RepositoryCredentialsStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryCredentialsStatus.
+func (in *RepositoryCredentialsStatus) DeepCopy() *RepositoryCredentialsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryCredentialsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepositoryList) DeepCopyInto(out *RepositoryList) {
 	*out = *in
@@ -92,6 +468,164 @@ func (in *RepositoryList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryPolicy) DeepCopyInto(out *RepositoryPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryPolicy.
+func (in *RepositoryPolicy) DeepCopy() *RepositoryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepositoryPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryPolicyList) DeepCopyInto(out *RepositoryPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RepositoryPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryPolicyList.
+func (in *RepositoryPolicyList) DeepCopy() *RepositoryPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepositoryPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryPolicySpec) DeepCopyInto(out *RepositoryPolicySpec) {
+	*out = *in
+	if in.ConcurrencyLimit != nil {
+		in, out := &in.ConcurrencyLimit, &out.ConcurrencyLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.AutoOkToTest != nil {
+		in, out := &in.AutoOkToTest, &out.AutoOkToTest
+		*out = new(AutoOkToTest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedTaskSources != nil {
+		in, out := &in.AllowedTaskSources, &out.AllowedTaskSources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ProtectedBranchParams != nil {
+		in, out := &in.ProtectedBranchParams, &out.ProtectedBranchParams
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DefaultMaxKeepRuns != nil {
+		in, out := &in.DefaultMaxKeepRuns, &out.DefaultMaxKeepRuns
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxKeepRunUpperLimit != nil {
+		in, out := &in.MaxKeepRunUpperLimit, &out.MaxKeepRunUpperLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Enforced != nil {
+		in, out := &in.Enforced, &out.Enforced
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryPolicySpec.
+func (in *RepositoryPolicySpec) DeepCopy() *RepositoryPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryQueueStatus) DeepCopyInto(out *RepositoryQueueStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.Running != nil {
+		in, out := &in.Running, &out.Running
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pending != nil {
+		in, out := &in.Pending, &out.Pending
+		*out = make([]QueuedPipelineRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryQueueStatus.
+func (in *RepositoryQueueStatus) DeepCopy() *RepositoryQueueStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryQueueStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepositoryRunStatus) DeepCopyInto(out *RepositoryRunStatus) {
 	*out = *in
@@ -104,6 +638,18 @@ func (in *RepositoryRunStatus) DeepCopyInto(out *RepositoryRunStatus) {
 		in, out := &in.CompletionTime, &out.CompletionTime
 		*out = (*in).DeepCopy()
 	}
+	if in.TaskResults != nil {
+		in, out := &in.TaskResults, &out.TaskResults
+		*out = make([]TaskResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TektonResults != nil {
+		in, out := &in.TektonResults, &out.TektonResults
+		*out = new(TektonResultsRecord)
+		**out = **in
+	}
 	return
 }
 
@@ -120,6 +666,82 @@ func (in *RepositoryRunStatus) DeepCopy() *RepositoryRunStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepositorySpec) DeepCopyInto(out *RepositorySpec) {
 	*out = *in
+	if in.FreezeWindows != nil {
+		in, out := &in.FreezeWindows, &out.FreezeWindows
+		*out = make([]FreezeWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = new([]Rule)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]Rule, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.MaxParallel != nil {
+		in, out := &in.MaxParallel, &out.MaxParallel
+		*out = new(int)
+		**out = **in
+	}
+	if in.AllowedTaskSources != nil {
+		in, out := &in.AllowedTaskSources, &out.AllowedTaskSources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ProtectedBranchParams != nil {
+		in, out := &in.ProtectedBranchParams, &out.ProtectedBranchParams
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CustomParams != nil {
+		in, out := &in.CustomParams, &out.CustomParams
+		*out = make([]CustomParam, len(*in))
+		copy(*out, *in)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]Notification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CommentCommands != nil {
+		in, out := &in.CommentCommands, &out.CommentCommands
+		*out = make([]CommentCommandPermission, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AutoOkToTest != nil {
+		in, out := &in.AutoOkToTest, &out.AutoOkToTest
+		*out = new(AutoOkToTest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = new(Settings)
+		**out = **in
+	}
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]Mirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -132,3 +754,99 @@ func (in *RepositorySpec) DeepCopy() *RepositorySpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Rule) DeepCopyInto(out *Rule) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rule.
+func (in *Rule) DeepCopy() *Rule {
+	if in == nil {
+		return nil
+	}
+	out := new(Rule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Settings) DeepCopyInto(out *Settings) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Settings.
+func (in *Settings) DeepCopy() *Settings {
+	if in == nil {
+		return nil
+	}
+	out := new(Settings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskResult) DeepCopyInto(out *TaskResult) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskResult.
+func (in *TaskResult) DeepCopy() *TaskResult {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TektonResultsRecord) DeepCopyInto(out *TektonResultsRecord) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TektonResultsRecord.
+func (in *TektonResultsRecord) DeepCopy() *TektonResultsRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(TektonResultsRecord)
+	in.DeepCopyInto(out)
+	return out
+}
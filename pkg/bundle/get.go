@@ -0,0 +1,204 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+)
+
+const (
+	// annotations set on the layers of a Tekton bundle
+	kindAnnotation = "dev.tekton.image.kind"
+	nameAnnotation = "dev.tekton.image.name"
+
+	manifestMediaTypes = "application/vnd.oci.image.manifest.v1+json,application/vnd.docker.distribution.manifest.v2+json"
+
+	// maxReadSize is the maximum size of what is read from the registry, the
+	// manifests, the tokens and the layers once decompressed. A Tekton bundle
+	// has one small yaml by layer, this only protects from a registry sending
+	// an endless or a gzip bomb response.
+	maxReadSize = 10 * 1024 * 1024
+)
+
+type manifest struct {
+	Layers []layer `json:"layers"`
+}
+
+type layer struct {
+	Digest      string            `json:"digest"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+type tokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+}
+
+// GetResource get the resource of kind (task or pipeline) named resourceName
+// out of the Tekton bundle image reference, the registry is accessed
+// anonymously.
+func GetResource(ctx context.Context, cs *params.Run, reference, kind, resourceName string) (string, error) {
+	ref, err := name.ParseReference(reference)
+	if err != nil {
+		return "", fmt.Errorf("invalid bundle reference %s: %w", reference, err)
+	}
+	repo := ref.Context()
+	baseURL := fmt.Sprintf("%s://%s/v2/%s", repo.Scheme(), repo.RegistryStr(), repo.RepositoryStr())
+
+	token := ""
+	data, err := doRequest(ctx, cs, fmt.Sprintf("%s/manifests/%s", baseURL, ref.Identifier()), manifestMediaTypes, &token)
+	if err != nil {
+		return "", fmt.Errorf("could not get manifest of bundle %s: %w", reference, err)
+	}
+	m := manifest{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return "", fmt.Errorf("could not parse manifest of bundle %s: %w", reference, err)
+	}
+
+	for _, l := range m.Layers {
+		if !strings.EqualFold(l.Annotations[kindAnnotation], kind) || l.Annotations[nameAnnotation] != resourceName {
+			continue
+		}
+		blob, err := doRequest(ctx, cs, fmt.Sprintf("%s/blobs/%s", baseURL, l.Digest), "", &token)
+		if err != nil {
+			return "", fmt.Errorf("could not get layer %s of bundle %s: %w", l.Digest, reference, err)
+		}
+		return readLayer(blob)
+	}
+	return "", fmt.Errorf("could not find %s %s in bundle %s", kind, resourceName, reference)
+}
+
+// doRequest get the url, when the registry ask for it we get an anonymous
+// bearer token and retry with it, the token is kept for the next requests.
+func doRequest(ctx context.Context, cs *params.Run, uri, accept string, token *string) ([]byte, error) {
+	res, err := get(ctx, cs, uri, accept, *token)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusUnauthorized && *token == "" {
+		*token, err = getToken(ctx, cs, res.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return nil, err
+		}
+		res, err = get(ctx, cs, uri, accept, *token)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry returned %s for %s", res.Status, uri)
+	}
+	data, err := readAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", uri, err)
+	}
+	return data, nil
+}
+
+// readAll reads r up to maxReadSize, and fails when there is more.
+func readAll(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxReadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxReadSize {
+		return nil, fmt.Errorf("bigger than the maximum of %d bytes", maxReadSize)
+	}
+	return data, nil
+}
+
+func get(ctx context.Context, cs *params.Run, uri, accept, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return cs.Clients.HTTP.Do(req)
+}
+
+// getToken get an anonymous token from the realm of a WWW-Authenticate Bearer
+// challenge.
+func getToken(ctx context.Context, cs *params.Run, challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unsupported registry authentication challenge: %q", challenge)
+	}
+	fields := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(part), "="); ok {
+			fields[k] = strings.Trim(v, `"`)
+		}
+	}
+	realm, err := url.Parse(fields["realm"])
+	if err != nil || fields["realm"] == "" {
+		return "", fmt.Errorf("invalid realm in registry authentication challenge: %q", challenge)
+	}
+	query := realm.Query()
+	for _, k := range []string{"service", "scope"} {
+		if fields[k] != "" {
+			query.Set(k, fields[k])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	res, err := get(ctx, cs, realm.String(), "", "")
+	if err != nil {
+		return "", fmt.Errorf("could not get registry token: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("could not get registry token: %s", res.Status)
+	}
+	data, err := readAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("could not read registry token: %w", err)
+	}
+	tr := tokenResponse{}
+	if err := json.Unmarshal(data, &tr); err != nil {
+		return "", fmt.Errorf("could not parse registry token: %w", err)
+	}
+	if tr.Token != "" {
+		return tr.Token, nil
+	}
+	return tr.AccessToken, nil
+}
+
+// readLayer return the content of the single file inside the layer tarball,
+// which may be gzipped.
+func readLayer(blob []byte) (string, error) {
+	var reader io.Reader = bytes.NewReader(blob)
+	if len(blob) > 1 && blob[0] == 0x1f && blob[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return "", err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+	tr := tar.NewReader(reader)
+	if _, err := tr.Next(); err != nil {
+		return "", fmt.Errorf("could not read bundle layer: %w", err)
+	}
+	data, err := readAll(tr)
+	if err != nil {
+		return "", fmt.Errorf("could not read bundle layer: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,128 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func makeLayer(t *testing.T, name, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content))}))
+	_, err := tw.Write([]byte(content))
+	assert.NilError(t, err)
+	assert.NilError(t, tw.Close())
+	assert.NilError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestGetResource(t *testing.T) {
+	task := `{"apiVersion": "tekton.dev/v1beta1", "kind": "Task", "metadata": {"name": "task"}}`
+	layer := makeLayer(t, "task", task)
+	bigLayer := makeLayer(t, "task", strings.Repeat("a", maxReadSize+1))
+
+	tests := []struct {
+		name         string
+		kind         string
+		resourceName string
+		withAuth     bool
+		tooBig       bool
+		wantErr      string
+	}{
+		{
+			name:         "get task",
+			kind:         "task",
+			resourceName: "task",
+		},
+		{
+			name:         "get task with token",
+			kind:         "task",
+			resourceName: "task",
+			withAuth:     true,
+		},
+		{
+			name:         "not in bundle",
+			kind:         "pipeline",
+			resourceName: "task",
+			wantErr:      "could not find pipeline task in bundle",
+		},
+		{
+			name:         "layer too big",
+			kind:         "task",
+			resourceName: "task",
+			tooBig:       true,
+			wantErr:      fmt.Sprintf("could not read bundle layer: bigger than the maximum of %d bytes", maxReadSize),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			authorized := func(rw http.ResponseWriter, r *http.Request) bool {
+				if !tt.withAuth || r.Header.Get("Authorization") == "Bearer sesame" {
+					return true
+				}
+				rw.Header().Set("WWW-Authenticate",
+					fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:org/bundle:pull"`, server.URL))
+				rw.WriteHeader(http.StatusUnauthorized)
+				return false
+			}
+			mux.HandleFunc("/token", func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.URL.Query().Get("scope"), "repository:org/bundle:pull")
+				fmt.Fprint(rw, `{"token": "sesame"}`)
+			})
+			mux.HandleFunc("/v2/org/bundle/manifests/v1", func(rw http.ResponseWriter, r *http.Request) {
+				if !authorized(rw, r) {
+					return
+				}
+				fmt.Fprint(rw, `{"layers": [{"digest": "sha256:abcd", "annotations": {
+					"dev.tekton.image.kind": "task", "dev.tekton.image.name": "task"}}]}`)
+			})
+			mux.HandleFunc("/v2/org/bundle/blobs/sha256:abcd", func(rw http.ResponseWriter, r *http.Request) {
+				if !authorized(rw, r) {
+					return
+				}
+				if tt.tooBig {
+					_, _ = rw.Write(bigLayer)
+					return
+				}
+				_, _ = rw.Write(layer)
+			})
+
+			ctx, _ := rtesting.SetupFakeContext(t)
+			cs := &params.Run{Clients: clients.Clients{HTTP: *server.Client()}}
+			reference := strings.TrimPrefix(server.URL, "http://") + "/org/bundle:v1"
+			got, err := GetResource(ctx, cs, reference, tt.kind, tt.resourceName)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, task)
+		})
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	data, err := readAll(strings.NewReader(strings.Repeat("a", maxReadSize)))
+	assert.NilError(t, err)
+	assert.Equal(t, len(data), maxReadSize)
+
+	_, err = readAll(strings.NewReader(strings.Repeat("a", maxReadSize+1)))
+	assert.ErrorContains(t, err, "bigger than the maximum")
+}
@@ -0,0 +1,78 @@
+package info
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	kapierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	configMapPacLabel      = "app.kubernetes.io/part-of=pipelines-as-code"
+	openShiftRouteGroup    = "route.openshift.io"
+	openShiftRouteVersion  = "v1"
+	openShiftRouteResource = "routes"
+	routePacLabel          = "pipelines-as-code/route=controller"
+)
+
+// DetectOpenShiftRoute detect the openshift route where the pac controller is running
+func DetectOpenShiftRoute(ctx context.Context, run *params.Run, targetNamespace string) (string, error) {
+	gvr := schema.GroupVersionResource{
+		Group: openShiftRouteGroup, Version: openShiftRouteVersion, Resource: openShiftRouteResource,
+	}
+	routes, err := run.Clients.Dynamic.Resource(gvr).Namespace(targetNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: routePacLabel,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(routes.Items) != 1 {
+		return "", err
+	}
+	route := routes.Items[0]
+
+	spec, ok := route.Object["spec"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("couldn't find spec in the PAC Controller route")
+	}
+
+	host, ok := spec["host"].(string)
+	if !ok {
+		// this condition is satisfied if there's no metadata at all in the provided CR
+		return "", fmt.Errorf("couldn't find spec.host in the PAC controller route")
+	}
+
+	return fmt.Sprintf("https://%s", host), nil
+}
+
+func DetectPacInstallation(ctx context.Context, wantedNS string, run *params.Run) (bool, string, error) {
+	var installed bool
+	_, err := run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("").List(ctx, metav1.ListOptions{})
+	if err != nil && kapierror.IsNotFound(err) {
+		return false, "", nil
+	}
+
+	installed = true
+	if wantedNS != "" {
+		_, err := run.Clients.Kube.CoreV1().ConfigMaps(wantedNS).Get(ctx, infoConfigMap, metav1.GetOptions{})
+		if err == nil {
+			return installed, wantedNS, nil
+		}
+		return installed, "", fmt.Errorf("could not detect Pipelines as Code configmap in %s namespace : %w, please reinstall", wantedNS, err)
+	}
+
+	cms, err := run.Clients.Kube.CoreV1().ConfigMaps("").List(ctx, metav1.ListOptions{
+		LabelSelector: configMapPacLabel,
+	})
+	if err == nil {
+		for _, cm := range cms.Items {
+			if cm.Name == infoConfigMap {
+				return installed, cm.Namespace, nil
+			}
+		}
+	}
+	return installed, "", fmt.Errorf("could not detect Pipelines as Code configmap on the cluster, please reinstall")
+}
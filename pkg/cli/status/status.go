@@ -38,12 +38,14 @@ func RepositoryRunStatusRemoveSameSHA(rs []pacv1alpha1.RepositoryRunStatus, live
 func convertPrStatusToRepositoryStatus(ctx context.Context, cs *params.Run, pr tektonv1beta1.PipelineRun, logurl string) pacv1alpha1.RepositoryRunStatus {
 	kinteract, _ := kubeinteraction.NewKubernetesInteraction(cs)
 	failurereasons := kstatus.CollectFailedTasksLogSnippet(ctx, cs, kinteract, &pr, defaultNumLinesOfLogsInContainersToGrabForErr)
+	trStatus := kstatus.GetStatusFromTaskStatusOrFromAsking(ctx, &pr, cs)
 	prSHA := pr.GetLabels()["pipelinesascode.tekton.dev/sha"]
 	return pacv1alpha1.RepositoryRunStatus{
 		Status:             pr.Status.Status,
 		LogURL:             &logurl,
 		PipelineRunName:    pr.GetName(),
 		CollectedTaskInfos: &failurereasons,
+		TaskResults:        kstatus.CollectTaskResults(cs, &pr, trStatus, failurereasons),
 		StartTime:          pr.Status.StartTime,
 		SHA:                github.String(prSHA),
 		SHAURL:             github.String(pr.GetAnnotations()["pipelinesascode.tekton.dev/sha-url"]),
@@ -0,0 +1,185 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	bbv1 "github.com/gfleury/go-bitbucket-v1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/random"
+)
+
+type bitbucketServerConfig struct {
+	Client              *bbv1.APIClient
+	IOStream            *cli.IOStreams
+	controllerURL       string
+	projectKey          string
+	repoSlug            string
+	webhookSecret       string
+	personalAccessToken string
+	username            string
+	APIURL              string
+}
+
+// bitbucketServerWebhook is the payload of the webhook creation, see
+// https://developer.atlassian.com/server/bitbucket/rest/v805/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-webhooks-post
+type bitbucketServerWebhook struct {
+	Name          string            `json:"name"`
+	URL           string            `json:"url"`
+	Active        bool              `json:"active"`
+	Events        []string          `json:"events"`
+	Configuration map[string]string `json:"configuration"`
+}
+
+func (bb *bitbucketServerConfig) Run(ctx context.Context, opts *Options) (*response, error) {
+	err := bb.askBBServerWebhookConfig(opts.RepositoryURL, opts.ControllerURL, opts.ProviderAPIURL, opts.PersonalAccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response{
+		ControllerURL:       bb.controllerURL,
+		PersonalAccessToken: bb.personalAccessToken,
+		WebhookSecret:       bb.webhookSecret,
+		APIURL:              bb.APIURL,
+		UserName:            bb.username,
+	}, bb.create(ctx)
+}
+
+// parseBBServerRepositoryURL returns the project key and the slug of a
+// repository from its URL, ie: https://bitbucket.example/projects/PAC/repos/demo
+func parseBBServerRepositoryURL(repositoryURL string) (string, string, error) {
+	parsed, err := url.Parse(repositoryURL)
+	if err != nil {
+		return "", "", err
+	}
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	for i := 0; i+3 < len(parts); i++ {
+		if parts[i] == "projects" && parts[i+2] == "repos" {
+			return parts[i+1], parts[i+3], nil
+		}
+	}
+	return "", "", fmt.Errorf("invalid repository url %s, needs to be of format 'https://bitbucket.example/projects/PROJECT/repos/repository'", repositoryURL)
+}
+
+func (bb *bitbucketServerConfig) askBBServerWebhookConfig(repositoryURL, controllerURL, apiURL, personalAccessToken string) error {
+	if repositoryURL == "" {
+		msg := "Please enter the git repository url you want to be configured: "
+		if err := prompt.SurveyAskOne(&survey.Input{Message: msg}, &repositoryURL,
+			survey.WithValidator(survey.Required)); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(bb.IOStream.Out, "✓ Setting up Bitbucket Server Webhook for Repository %s\n", repositoryURL)
+	}
+
+	var err error
+	bb.projectKey, bb.repoSlug, err = parseBBServerRepositoryURL(repositoryURL)
+	if err != nil {
+		return err
+	}
+
+	if err := prompt.SurveyAskOne(&survey.Input{
+		Message: "Please enter your bitbucket server username: ",
+	}, &bb.username, survey.WithValidator(survey.Required)); err != nil {
+		return err
+	}
+
+	if personalAccessToken == "" {
+		fmt.Fprintln(bb.IOStream.Out, "ℹ ️You now need to create a Bitbucket Server personal access token with the `PROJECT_ADMIN` and `REPOSITORY_ADMIN` permissions")
+		if err := prompt.SurveyAskOne(&survey.Password{
+			Message: "Please enter the Bitbucket Server personal access token: ",
+		}, &bb.personalAccessToken, survey.WithValidator(survey.Required)); err != nil {
+			return err
+		}
+	} else {
+		bb.personalAccessToken = personalAccessToken
+	}
+
+	bb.controllerURL = controllerURL
+
+	// confirm whether to use the detected url
+	if bb.controllerURL != "" {
+		var answer bool
+		fmt.Fprintf(bb.IOStream.Out, "👀 I have detected a controller url: %s\n", bb.controllerURL)
+		err := prompt.SurveyAskOne(&survey.Confirm{
+			Message: "Do you want me to use it?",
+			Default: true,
+		}, &answer)
+		if err != nil {
+			return err
+		}
+		if !answer {
+			bb.controllerURL = ""
+		}
+	}
+
+	if bb.controllerURL == "" {
+		if err := prompt.SurveyAskOne(&survey.Input{
+			Message: "Please enter your controller public route URL: ",
+		}, &bb.controllerURL, survey.WithValidator(survey.Required)); err != nil {
+			return err
+		}
+	}
+
+	data := random.AlphaString(12)
+	msg := fmt.Sprintf("Please enter the secret to configure the webhook for payload validation (default: %s): ", data)
+	if err := prompt.SurveyAskOne(&survey.Input{Message: msg, Default: data}, &bb.webhookSecret); err != nil {
+		return err
+	}
+
+	if apiURL == "" {
+		// the REST API is served under /rest of the Bitbucket Server instance
+		defaultAPIURL := ""
+		if parsed, err := url.Parse(repositoryURL); err == nil && parsed.Host != "" {
+			defaultAPIURL = fmt.Sprintf("%s://%s/rest", parsed.Scheme, parsed.Host)
+		}
+		if err := prompt.SurveyAskOne(&survey.Input{
+			Message: fmt.Sprintf("Please enter your Bitbucket Server API URL (default: %s): ", defaultAPIURL),
+			Default: defaultAPIURL,
+		}, &bb.APIURL, survey.WithValidator(survey.Required)); err != nil {
+			return err
+		}
+	} else {
+		bb.APIURL = apiURL
+	}
+	bb.APIURL = strings.TrimSuffix(bb.APIURL, "/")
+	if !strings.HasSuffix(bb.APIURL, "/rest") {
+		bb.APIURL += "/rest"
+	}
+
+	return nil
+}
+
+func (bb *bitbucketServerConfig) create(ctx context.Context) error {
+	if bb.Client == nil {
+		ctx = context.WithValue(ctx, bbv1.ContextBasicAuth, bbv1.BasicAuth{UserName: bb.username, Password: bb.personalAccessToken})
+		bb.Client = bbv1.NewAPIClient(ctx, bbv1.NewConfiguration(bb.APIURL))
+	}
+
+	hook := bitbucketServerWebhook{
+		Name:   "Pipelines as Code",
+		URL:    bb.controllerURL,
+		Active: true,
+		Events: []string{
+			"repo:refs_changed",
+			"pr:opened",
+			"pr:from_ref_updated",
+			"pr:merged",
+			"pr:declined",
+			"pr:comment:added",
+		},
+		Configuration: map[string]string{"secret": bb.webhookSecret},
+	}
+
+	if _, err := bb.Client.DefaultApi.CreateWebhook(bb.projectKey, bb.repoSlug, hook, []string{"application/json"}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(bb.IOStream.Out, "✓ Webhook has been created on repository %v/%v\n", bb.projectKey, bb.repoSlug)
+	return nil
+}
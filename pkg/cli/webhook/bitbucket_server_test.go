@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	bbservertest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketserver/test"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestParseBBServerRepositoryURL(t *testing.T) {
+	project, slug, err := parseBBServerRepositoryURL("https://bitbucket.pac.test/projects/PAC/repos/demo/browse")
+	assert.NilError(t, err)
+	assert.Equal(t, project, "PAC")
+	assert.Equal(t, slug, "demo")
+
+	_, _, err = parseBBServerRepositoryURL("https://bitbucket.pac.test/pac/demo")
+	assert.ErrorContains(t, err, "needs to be of format")
+}
+
+func TestAskBBServerWebhookConfig(t *testing.T) {
+	//nolint
+	io, _, _, _ := cli.IOTest()
+	as, teardown := prompt.InitAskStubber()
+	defer teardown()
+	as.StubOne("user")
+	as.StubOne(true)
+	as.StubOne("webhook-secret")
+	as.StubOne("https://bitbucket.pac.test")
+
+	bb := bitbucketServerConfig{IOStream: io}
+	err := bb.askBBServerWebhookConfig("https://bitbucket.pac.test/projects/PAC/repos/demo", "https://test", "", "token")
+	assert.NilError(t, err)
+	assert.Equal(t, bb.projectKey, "PAC")
+	assert.Equal(t, bb.repoSlug, "demo")
+	assert.Equal(t, bb.APIURL, "https://bitbucket.pac.test/rest")
+	assert.Equal(t, bb.webhookSecret, "webhook-secret")
+}
+
+func TestBBServerCreate(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	client, mux, teardown := bbservertest.SetupBBServerClient(ctx, t)
+	defer teardown()
+	//nolint
+	io, _, _, _ := cli.IOTest()
+
+	mux.HandleFunc("/projects/PAC/repos/demo/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		hook := bitbucketServerWebhook{}
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&hook))
+		assert.Equal(t, hook.URL, "https://test")
+		assert.Equal(t, hook.Configuration["secret"], "webhook-secret")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, `{"id": 1}`)
+	})
+
+	bb := bitbucketServerConfig{
+		IOStream:      io,
+		Client:        client,
+		projectKey:    "PAC",
+		repoSlug:      "demo",
+		controllerURL: "https://test",
+		webhookSecret: "webhook-secret",
+	}
+	assert.NilError(t, bb.create(ctx))
+
+	bb.repoSlug = "notfound"
+	assert.Assert(t, bb.create(ctx) != nil)
+}
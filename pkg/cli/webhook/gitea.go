@@ -0,0 +1,159 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/random"
+)
+
+type giteaConfig struct {
+	Client              *gitea.Client
+	IOStream            *cli.IOStreams
+	controllerURL       string
+	repoOwner           string
+	repoName            string
+	webhookSecret       string
+	personalAccessToken string
+	APIURL              string
+}
+
+func (gt *giteaConfig) Run(_ context.Context, opts *Options) (*response, error) {
+	err := gt.askGiteaWebhookConfig(opts.RepositoryURL, opts.ControllerURL, opts.ProviderAPIURL, opts.PersonalAccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response{
+		ControllerURL:       gt.controllerURL,
+		PersonalAccessToken: gt.personalAccessToken,
+		WebhookSecret:       gt.webhookSecret,
+		APIURL:              gt.APIURL,
+	}, gt.create()
+}
+
+func (gt *giteaConfig) askGiteaWebhookConfig(repoURL, controllerURL, apiURL, personalAccessToken string) error {
+	if repoURL == "" {
+		msg := "Please enter the git repository url you want to be configured: "
+		if err := prompt.SurveyAskOne(&survey.Input{Message: msg}, &repoURL,
+			survey.WithValidator(survey.Required)); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(gt.IOStream.Out, "✓ Setting up Gitea Webhook for Repository %s\n", repoURL)
+	}
+
+	var err error
+	gt.repoOwner, gt.repoName, err = formatting.GetRepoOwnerSplitted(repoURL)
+	if err != nil {
+		return err
+	}
+
+	// set controller url
+	gt.controllerURL = controllerURL
+
+	// confirm whether to use the detected url
+	if gt.controllerURL != "" {
+		var answer bool
+		fmt.Fprintf(gt.IOStream.Out, "👀 I have detected a controller url: %s\n", gt.controllerURL)
+		err := prompt.SurveyAskOne(&survey.Confirm{
+			Message: "Do you want me to use it?",
+			Default: true,
+		}, &answer)
+		if err != nil {
+			return err
+		}
+		if !answer {
+			gt.controllerURL = ""
+		}
+	}
+
+	if gt.controllerURL == "" {
+		if err := prompt.SurveyAskOne(&survey.Input{
+			Message: "Please enter your controller public route URL: ",
+		}, &gt.controllerURL, survey.WithValidator(survey.Required)); err != nil {
+			return err
+		}
+	}
+
+	data := random.AlphaString(12)
+	msg := fmt.Sprintf("Please enter the secret to configure the webhook for payload validation (default: %s): ", data)
+	if err := prompt.SurveyAskOne(&survey.Input{Message: msg, Default: data}, &gt.webhookSecret); err != nil {
+		return err
+	}
+
+	if personalAccessToken == "" {
+		fmt.Fprintln(gt.IOStream.Out, "ℹ ️You now need to create a Gitea access token with the `repo` scope, in the Applications tab of your Gitea user settings")
+		if err := prompt.SurveyAskOne(&survey.Password{
+			Message: "Please enter the Gitea access token: ",
+		}, &gt.personalAccessToken, survey.WithValidator(survey.Required)); err != nil {
+			return err
+		}
+	} else {
+		gt.personalAccessToken = personalAccessToken
+	}
+
+	if apiURL == "" {
+		// the API is served by the Gitea instance hosting the repository
+		defaultAPIURL := ""
+		if parsed, err := url.Parse(repoURL); err == nil && parsed.Host != "" {
+			defaultAPIURL = fmt.Sprintf("%s://%s", parsed.Scheme, parsed.Host)
+		}
+		if err := prompt.SurveyAskOne(&survey.Input{
+			Message: fmt.Sprintf("Please enter your Gitea instance URL (default: %s): ", defaultAPIURL),
+			Default: defaultAPIURL,
+		}, &gt.APIURL, survey.WithValidator(survey.Required)); err != nil {
+			return err
+		}
+	} else {
+		gt.APIURL = apiURL
+	}
+	gt.APIURL = strings.TrimSuffix(gt.APIURL, "/")
+
+	return nil
+}
+
+func (gt *giteaConfig) create() error {
+	client, err := gt.newClient()
+	if err != nil {
+		return err
+	}
+
+	hookOpts := gitea.CreateHookOption{
+		Type: gitea.HookTypeGitea,
+		Config: map[string]string{
+			"url":          gt.controllerURL,
+			"content_type": "json",
+			"secret":       gt.webhookSecret,
+		},
+		Events: []string{"push", "delete", "pull_request", "issue_comment"},
+		Active: true,
+	}
+
+	_, resp, err := client.CreateRepoHook(gt.repoOwner, gt.repoName, hookOpts)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("failed to create webhook, status code: %v", resp.StatusCode)
+	}
+
+	fmt.Fprintf(gt.IOStream.Out, "✓ Webhook has been created on repository %v/%v\n", gt.repoOwner, gt.repoName)
+	return nil
+}
+
+func (gt *giteaConfig) newClient() (*gitea.Client, error) {
+	if gt.Client != nil {
+		return gt.Client, nil
+	}
+	return gitea.NewClient(gt.APIURL, gitea.SetToken(gt.personalAccessToken))
+}
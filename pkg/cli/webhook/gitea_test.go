@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	giteatest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea/test"
+	"gotest.tools/v3/assert"
+)
+
+func TestAskGiteaWebhookConfig(t *testing.T) {
+	//nolint
+	io, _, _, _ := cli.IOTest()
+	tests := []struct {
+		name                string
+		wantErrStr          string
+		askStubs            func(*prompt.AskStubber)
+		repoURL             string
+		controllerURL       string
+		apiURL              string
+		personalaccesstoken string
+		wantAPIURL          string
+	}{
+		{
+			name: "ask all details no defaults",
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOne("https://gitea.pac.test/pac/test")
+				as.StubOne("https://test")
+				as.StubOne("webhook-secret")
+				as.StubOne("token")
+				as.StubOne("https://gitea.pac.test/")
+			},
+			wantAPIURL: "https://gitea.pac.test",
+		},
+		{
+			name: "with defaults",
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOne(true)
+				as.StubOne("webhook-secret")
+			},
+			repoURL:             "https://gitea.pac.test/pac/demo",
+			controllerURL:       "https://test",
+			apiURL:              "https://gitea.pac.test",
+			personalaccesstoken: "token",
+			wantAPIURL:          "https://gitea.pac.test",
+		},
+		{
+			name: "invalid repo format",
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOne("invalid-repo")
+			},
+			wantErrStr: "invalid repo url at least a organization/project and a repo needs to be specified: invalid-repo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			as, teardown := prompt.InitAskStubber()
+			defer teardown()
+			if tt.askStubs != nil {
+				tt.askStubs(as)
+			}
+			gt := giteaConfig{IOStream: io}
+			err := gt.askGiteaWebhookConfig(tt.repoURL, tt.controllerURL, tt.apiURL, tt.personalaccesstoken)
+			if tt.wantErrStr != "" {
+				assert.Equal(t, err.Error(), tt.wantErrStr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, gt.APIURL, tt.wantAPIURL)
+		})
+	}
+}
+
+func TestGiteaCreate(t *testing.T) {
+	fakeclient, mux, teardown := giteatest.Setup(t)
+	defer teardown()
+	//nolint
+	io, _, _, _ := cli.IOTest()
+
+	mux.HandleFunc("/repos/pac/demo/hooks", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, `{"id": 1}`)
+	})
+	mux.HandleFunc("/repos/pac/forbidden/hooks", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = fmt.Fprint(w, `{"message": "forbidden"}`)
+	})
+
+	tests := []struct {
+		name     string
+		repoName string
+		wantErr  bool
+	}{
+		{
+			name:     "webhook created",
+			repoName: "demo",
+		},
+		{
+			name:     "webhook failed",
+			repoName: "forbidden",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gt := giteaConfig{
+				IOStream:  io,
+				Client:    fakeclient,
+				repoOwner: "pac",
+				repoName:  tt.repoName,
+			}
+			err := gt.create()
+			if tt.wantErr {
+				assert.Assert(t, err != nil)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
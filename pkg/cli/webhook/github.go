@@ -11,6 +11,7 @@ import (
 	"github.com/google/go-github/v49/github"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/credentials"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/random"
 	"golang.org/x/oauth2"
@@ -136,6 +137,7 @@ func (gh *gitHubConfig) create(ctx context.Context) error {
 			"issue_comment",
 			"pull_request",
 			"push",
+			"release",
 		},
 		Config: map[string]interface{}{
 			"url":          gh.controllerURL,
@@ -149,6 +151,7 @@ func (gh *gitHubConfig) create(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	gh.checkTokenPermissions(ctx, ghClient)
 
 	_, res, err := ghClient.Repositories.CreateHook(ctx, gh.repoOwner, gh.repoName, hook)
 	if err != nil {
@@ -169,6 +172,22 @@ func (gh *gitHubConfig) create(ctx context.Context) error {
 	return nil
 }
 
+// checkTokenPermissions warns about the permissions Pipelines as Code needs on
+// the repository which the token doesn't have, the token is kept in the
+// secret of the Repository for the controller.
+func (gh *gitHubConfig) checkTokenPermissions(ctx context.Context, ghClient *github.Client) {
+	missing, err := credentials.GitHubProbeMissingPermissions(ctx, ghClient, gh.repoOwner, gh.repoName)
+	if err != nil {
+		fmt.Fprintf(gh.IOStream.ErrOut, "%s cannot check the permissions of the token: %v\n",
+			gh.IOStream.ColorScheme().WarningIcon(), err)
+		return
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(gh.IOStream.ErrOut, "%s the token is missing the permissions %s on %s/%s, Pipelines as Code needs them to run the PipelineRuns and report their status\n",
+			gh.IOStream.ColorScheme().WarningIcon(), strings.Join(missing, ", "), gh.repoOwner, gh.repoName)
+	}
+}
+
 func (gh *gitHubConfig) newGHClientByToken(ctx context.Context) (*github.Client, error) {
 	if gh.Client != nil {
 		return gh.Client, nil
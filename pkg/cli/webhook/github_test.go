@@ -3,6 +3,7 @@ package webhook
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
@@ -98,7 +99,16 @@ func TestCreate(t *testing.T) {
 	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
 	defer teardown()
 	//nolint
-	io, _, _, _ := cli.IOTest()
+	io, _, _, errOut := cli.IOTest()
+
+	// a classic token without scope
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", "admin:repo_hook")
+		_, _ = fmt.Fprint(w, `{"login": "pac"}`)
+	})
+	mux.HandleFunc("/repos/pac/valid", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"private": true}`)
+	})
 
 	// webhook created for repo pac/valid
 	mux.HandleFunc("/repos/pac/valid/hooks", func(w http.ResponseWriter, r *http.Request) {
@@ -112,15 +122,17 @@ func TestCreate(t *testing.T) {
 	})
 
 	tests := []struct {
-		name      string
-		wantErr   bool
-		repoName  string
-		repoOwner string
+		name        string
+		wantErr     bool
+		repoName    string
+		repoOwner   string
+		wantWarning string
 	}{
 		{
-			name:      "webhook created",
-			repoOwner: "pac",
-			repoName:  "valid",
+			name:        "webhook created",
+			repoOwner:   "pac",
+			repoName:    "valid",
+			wantWarning: "the token is missing the permissions scope repo on pac/valid",
 		},
 		{
 			name:      "webhook failed",
@@ -139,10 +151,12 @@ func TestCreate(t *testing.T) {
 				repoOwner: tt.repoOwner,
 				repoName:  tt.repoName,
 			}
+			errOut.Reset()
 			err := gh.create(ctx)
 			if !tt.wantErr {
 				assert.NilError(t, err)
 			}
+			assert.Assert(t, strings.Contains(errOut.String(), tt.wantWarning), errOut.String())
 		})
 	}
 }
@@ -18,6 +18,8 @@ type gitLabConfig struct {
 	IOStream            *cli.IOStreams
 	controllerURL       string
 	projectID           string
+	groupHook           bool
+	groupID             string
 	webhookSecret       string
 	personalAccessToken string
 	APIURL              string
@@ -48,10 +50,18 @@ func (gl *gitLabConfig) askGLWebhookConfig(repoURL, controllerURL, apiURL, perso
 		fmt.Fprintf(gl.IOStream.Out, "✓ Setting up GitLab Webhook for Repository %s\n", repoURL)
 	}
 
-	msg := "Please enter the project ID for the repository you want to be configured, \n  project ID refers to an unique ID (e.g. 34405323) shown at the top of your GitLab project :"
-	if err := prompt.SurveyAskOne(&survey.Input{Message: msg}, &gl.projectID,
-		survey.WithValidator(survey.Required)); err != nil {
-		return err
+	if gl.groupHook {
+		msg := "Please enter the ID or the path of the group you want to be configured, \n  the webhook will be used by all the projects of the group (e.g. 34405323 or my-group) :"
+		if err := prompt.SurveyAskOne(&survey.Input{Message: msg}, &gl.groupID,
+			survey.WithValidator(survey.Required)); err != nil {
+			return err
+		}
+	} else {
+		msg := "Please enter the project ID for the repository you want to be configured, \n  project ID refers to an unique ID (e.g. 34405323) shown at the top of your GitLab project :"
+		if err := prompt.SurveyAskOne(&survey.Input{Message: msg}, &gl.projectID,
+			survey.WithValidator(survey.Required)); err != nil {
+			return err
+		}
 	}
 
 	// set controller url
@@ -82,7 +92,7 @@ func (gl *gitLabConfig) askGLWebhookConfig(repoURL, controllerURL, apiURL, perso
 	}
 
 	data := random.AlphaString(12)
-	msg = fmt.Sprintf("Please enter the secret to configure the webhook for payload validation (default: %s): ", data)
+	msg := fmt.Sprintf("Please enter the secret to configure the webhook for payload validation (default: %s): ", data)
 	var webhookSecret string
 	if err := prompt.SurveyAskOne(&survey.Input{Message: msg, Default: data}, &webhookSecret); err != nil {
 		return err
@@ -121,6 +131,10 @@ func (gl *gitLabConfig) create() error {
 		return err
 	}
 
+	if gl.groupHook {
+		return gl.createGroupHook(glClient)
+	}
+
 	hookOpts := &gitlab.AddProjectHookOptions{
 		EnableSSLVerification: gitlab.Bool(true),
 		MergeRequestsEvents:   gitlab.Bool(true),
@@ -148,6 +162,36 @@ func (gl *gitLabConfig) create() error {
 	return nil
 }
 
+// createGroupHook creates the webhook on the group, GitLab sends the events of
+// all the projects of the group to it.
+func (gl *gitLabConfig) createGroupHook(glClient *gitlab.Client) error {
+	hookOpts := &gitlab.AddGroupHookOptions{
+		EnableSSLVerification: gitlab.Bool(true),
+		MergeRequestsEvents:   gitlab.Bool(true),
+		NoteEvents:            gitlab.Bool(true),
+		PushEvents:            gitlab.Bool(true),
+		Token:                 gitlab.String(gl.webhookSecret),
+		URL:                   gitlab.String(gl.controllerURL),
+	}
+
+	_, resp, err := glClient.Groups.AddGroupHook(gl.groupID, hookOpts)
+	if err != nil {
+		return err
+	}
+
+	if resp.Response.StatusCode != http.StatusCreated {
+		payload, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+		return fmt.Errorf("failed to create group webhook, status code: %v, error : %v",
+			resp.Response.StatusCode, payload)
+	}
+
+	fmt.Fprintf(gl.IOStream.Out, "✓ Webhook has been created on the group %s\n", gl.groupID)
+	return nil
+}
+
 func (gl *gitLabConfig) newClient() (*gitlab.Client, error) {
 	if gl.Client != nil {
 		return gl.Client, nil
@@ -23,6 +23,7 @@ func TestAskGLWebhookConfig(t *testing.T) {
 		controllerURL       string
 		repoURL             string
 		personalaccesstoken string
+		groupHook           bool
 	}{
 		{
 			name: "ask all details no defaults",
@@ -63,6 +64,20 @@ func TestAskGLWebhookConfig(t *testing.T) {
 			personalaccesstoken: "Yzg5NzhlYmNkNTQwNzYzN2E2ZGExYzhkMTc4NjU0MjY3ZmQ2NmMeZg==",
 			wantErrStr:          "",
 		},
+		{
+			name: "group webhook",
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOne("my-group")
+				as.StubOne(true)
+				as.StubOne("webhook-secret")
+			},
+			repoURL:             "https://gitlab.com/pac/demo",
+			controllerURL:       "https://test",
+			providerURL:         "https://gl.pac.test",
+			personalaccesstoken: "token",
+			groupHook:           true,
+			wantErrStr:          "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -72,7 +87,7 @@ func TestAskGLWebhookConfig(t *testing.T) {
 			if tt.askStubs != nil {
 				tt.askStubs(as)
 			}
-			gl := gitLabConfig{IOStream: io}
+			gl := gitLabConfig{IOStream: io, groupHook: tt.groupHook}
 			err := gl.askGLWebhookConfig(tt.repoURL, tt.controllerURL, tt.providerURL, tt.personalaccesstoken)
 			if tt.wantErrStr != "" {
 				assert.Equal(t, err.Error(), tt.wantErrStr)
@@ -96,6 +111,12 @@ func TestGLCreate(t *testing.T) {
 		_, _ = fmt.Fprint(w, `{"status": "ok"}`)
 	})
 
+	// group webhook created
+	mux.HandleFunc("/groups/pac/hooks", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(201)
+		_, _ = fmt.Fprint(w, `{"status": "ok"}`)
+	})
+
 	// webhook failed
 	mux.HandleFunc("/projects/13/hooks", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(403)
@@ -105,6 +126,7 @@ func TestGLCreate(t *testing.T) {
 	tests := []struct {
 		name      string
 		projectID string
+		groupID   string
 		wantErr   bool
 	}{
 		{
@@ -112,6 +134,11 @@ func TestGLCreate(t *testing.T) {
 			projectID: "11",
 			wantErr:   false,
 		},
+		{
+			name:    "group webhook created",
+			groupID: "pac",
+			wantErr: false,
+		},
 		{
 			name:      "webhook failed",
 			projectID: "13",
@@ -125,6 +152,8 @@ func TestGLCreate(t *testing.T) {
 				IOStream:  io,
 				Client:    fakeclient,
 				projectID: tt.projectID,
+				groupHook: tt.groupID != "",
+				groupID:   tt.groupID,
 			}
 			err := gl.create()
 			if !tt.wantErr {
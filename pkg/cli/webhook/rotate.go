@@ -0,0 +1,216 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/AlecAivazis/survey/v2"
+	bbv1 "github.com/gfleury/go-bitbucket-v1"
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/random"
+	"github.com/xanzy/go-gitlab"
+)
+
+// secretRotator updates the secret of the webhook of the repository pointing
+// to the controller on the git provider.
+type secretRotator interface {
+	rotateSecret(ctx context.Context, opts *Options, webhookSecret string) error
+}
+
+// RotateWebhookSecret generates a new webhook secret and sets it on the
+// webhook of the repository pointing to the controller on the git provider,
+// the new secret is returned for the Secret of the repository to be updated.
+// The webhooks of Bitbucket Cloud have no secret, an empty secret is returned.
+func (w *Options) RotateWebhookSecret(ctx context.Context, providerType string) (string, error) {
+	var rotator secretRotator
+	switch providerType {
+	case "github":
+		rotator = &gitHubConfig{IOStream: w.IOStreams}
+	case "gitlab":
+		rotator = &gitLabConfig{IOStream: w.IOStreams}
+	case "gitea":
+		rotator = &giteaConfig{IOStream: w.IOStreams}
+	case "bitbucket-server":
+		rotator = &bitbucketServerConfig{IOStream: w.IOStreams}
+	case "bitbucket-cloud":
+		// the payloads of Bitbucket Cloud are not signed
+		return "", nil
+	default:
+		return "", fmt.Errorf("invalid webhook provider")
+	}
+
+	if w.ControllerURL == "" {
+		if err := w.detectControllerURL(ctx); err != nil {
+			return "", err
+		}
+	}
+	if w.ControllerURL == "" {
+		if err := prompt.SurveyAskOne(&survey.Input{
+			Message: "Please enter the controller public route URL of the webhook: ",
+		}, &w.ControllerURL, survey.WithValidator(survey.Required)); err != nil {
+			return "", err
+		}
+	}
+
+	webhookSecret := random.AlphaString(12)
+	if err := rotator.rotateSecret(ctx, w, webhookSecret); err != nil {
+		return "", err
+	}
+	return webhookSecret, nil
+}
+
+// sameHookURL compares the url of a webhook with the controller url, ignoring
+// their trailing slashes.
+func sameHookURL(hookURL, controllerURL string) bool {
+	return strings.TrimSuffix(hookURL, "/") == strings.TrimSuffix(controllerURL, "/")
+}
+
+func noHookError(controllerURL, repository string) error {
+	return fmt.Errorf("cannot find a webhook pointing to the controller url %s on repository %s", controllerURL, repository)
+}
+
+func (gh *gitHubConfig) rotateSecret(ctx context.Context, opts *Options, webhookSecret string) error {
+	var err error
+	if gh.repoOwner, gh.repoName, err = formatting.GetRepoOwnerSplitted(opts.RepositoryURL); err != nil {
+		return err
+	}
+	gh.personalAccessToken, gh.APIURL = opts.PersonalAccessToken, opts.ProviderAPIURL
+	ghClient, err := gh.newGHClientByToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	hooks, _, err := ghClient.Repositories.ListHooks(ctx, gh.repoOwner, gh.repoName, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		if hookURL, _ := hook.Config["url"].(string); !sameHookURL(hookURL, opts.ControllerURL) {
+			continue
+		}
+		// the secret is masked in the config we got, the whole config is sent back
+		hook.Config["secret"] = webhookSecret
+		if _, _, err := ghClient.Repositories.EditHook(ctx, gh.repoOwner, gh.repoName, hook.GetID(), &github.Hook{Config: hook.Config}); err != nil {
+			return err
+		}
+		fmt.Fprintf(gh.IOStream.Out, "✓ Webhook secret has been updated on repository %v/%v\n", gh.repoOwner, gh.repoName)
+		return nil
+	}
+	return noHookError(opts.ControllerURL, gh.repoOwner+"/"+gh.repoName)
+}
+
+func (gl *gitLabConfig) rotateSecret(_ context.Context, opts *Options, webhookSecret string) error {
+	org, repo, err := formatting.GetRepoOwnerSplitted(opts.RepositoryURL)
+	if err != nil {
+		return err
+	}
+	gl.projectID = org + "/" + repo
+	gl.personalAccessToken, gl.APIURL = opts.PersonalAccessToken, opts.ProviderAPIURL
+	glClient, err := gl.newClient()
+	if err != nil {
+		return err
+	}
+
+	hooks, _, err := glClient.Projects.ListProjectHooks(gl.projectID, &gitlab.ListProjectHooksOptions{PerPage: 100})
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		if !sameHookURL(hook.URL, opts.ControllerURL) {
+			continue
+		}
+		hookOpts := &gitlab.EditProjectHookOptions{
+			EnableSSLVerification: gitlab.Bool(hook.EnableSSLVerification),
+			MergeRequestsEvents:   gitlab.Bool(hook.MergeRequestsEvents),
+			NoteEvents:            gitlab.Bool(hook.NoteEvents),
+			PushEvents:            gitlab.Bool(hook.PushEvents),
+			Token:                 gitlab.String(webhookSecret),
+			URL:                   gitlab.String(hook.URL),
+		}
+		if _, _, err := glClient.Projects.EditProjectHook(gl.projectID, hook.ID, hookOpts); err != nil {
+			return err
+		}
+		fmt.Fprintf(gl.IOStream.Out, "✓ Webhook secret has been updated on project %s\n", gl.projectID)
+		return nil
+	}
+	return noHookError(opts.ControllerURL, gl.projectID)
+}
+
+func (gt *giteaConfig) rotateSecret(_ context.Context, opts *Options, webhookSecret string) error {
+	var err error
+	if gt.repoOwner, gt.repoName, err = formatting.GetRepoOwnerSplitted(opts.RepositoryURL); err != nil {
+		return err
+	}
+	gt.personalAccessToken, gt.APIURL = opts.PersonalAccessToken, strings.TrimSuffix(opts.ProviderAPIURL, "/")
+	client, err := gt.newClient()
+	if err != nil {
+		return err
+	}
+
+	hooks, _, err := client.ListRepoHooks(gt.repoOwner, gt.repoName, gitea.ListHooksOptions{})
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		if !sameHookURL(hook.Config["url"], opts.ControllerURL) {
+			continue
+		}
+		hookOpts := gitea.EditHookOption{
+			Config: map[string]string{
+				"url":          hook.Config["url"],
+				"content_type": hook.Config["content_type"],
+				"secret":       webhookSecret,
+			},
+			Events: hook.Events,
+			Active: &hook.Active,
+		}
+		if _, err := client.EditRepoHook(gt.repoOwner, gt.repoName, hook.ID, hookOpts); err != nil {
+			return err
+		}
+		fmt.Fprintf(gt.IOStream.Out, "✓ Webhook secret has been updated on repository %v/%v\n", gt.repoOwner, gt.repoName)
+		return nil
+	}
+	return noHookError(opts.ControllerURL, gt.repoOwner+"/"+gt.repoName)
+}
+
+func (bb *bitbucketServerConfig) rotateSecret(ctx context.Context, opts *Options, webhookSecret string) error {
+	var err error
+	if bb.projectKey, bb.repoSlug, err = parseBBServerRepositoryURL(opts.RepositoryURL); err != nil {
+		return err
+	}
+	if bb.Client == nil {
+		ctx = context.WithValue(ctx, bbv1.ContextBasicAuth, bbv1.BasicAuth{UserName: opts.ProviderUser, Password: opts.PersonalAccessToken})
+		bb.Client = bbv1.NewAPIClient(ctx, bbv1.NewConfiguration(opts.ProviderAPIURL))
+	}
+
+	resp, err := bb.Client.DefaultApi.FindWebhooks(bb.projectKey, bb.repoSlug, nil)
+	if err != nil {
+		return err
+	}
+	hooks, err := bbv1.GetWebhooksResponse(resp)
+	if err != nil {
+		return fmt.Errorf("cannot parse the webhooks of repository %v/%v: %w", bb.projectKey, bb.repoSlug, err)
+	}
+	for _, hook := range hooks {
+		if !sameHookURL(hook.Url, opts.ControllerURL) {
+			continue
+		}
+		update := bitbucketServerWebhook{
+			Name:          hook.Name,
+			URL:           hook.Url,
+			Active:        hook.Active,
+			Events:        hook.Events,
+			Configuration: map[string]string{"secret": webhookSecret},
+		}
+		if _, err := bb.Client.DefaultApi.UpdateWebhook(bb.projectKey, bb.repoSlug, int32(hook.ID), update, []string{"application/json"}); err != nil {
+			return err
+		}
+		fmt.Fprintf(bb.IOStream.Out, "✓ Webhook secret has been updated on repository %v/%v\n", bb.projectKey, bb.repoSlug)
+		return nil
+	}
+	return noHookError(opts.ControllerURL, bb.projectKey+"/"+bb.repoSlug)
+}
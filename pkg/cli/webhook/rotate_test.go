@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	bbservertest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketserver/test"
+	giteatest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea/test"
+	thelp "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab/test"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+const (
+	rotateControllerURL = "https://controller.pac.test"
+	rotatedSecret       = "rotated-secret"
+)
+
+func TestGHRotateSecret(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+	//nolint
+	io, _, _, _ := cli.IOTest()
+
+	mux.HandleFunc("/repos/pac/demo/hooks", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `[{"id": 1, "config": {"url": "https://other.test"}}, {"id": 2, "config": {"url": "%s/", "secret": "********", "content_type": "json"}}]`, rotateControllerURL)
+	})
+	edited := map[string]interface{}{}
+	mux.HandleFunc("/repos/pac/demo/hooks/2", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPatch)
+		hook := struct {
+			Config map[string]interface{} `json:"config"`
+		}{}
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&hook))
+		edited = hook.Config
+		_, _ = fmt.Fprint(w, `{"id": 2}`)
+	})
+
+	gh := gitHubConfig{IOStream: io, Client: fakeclient}
+	opts := &Options{RepositoryURL: "https://github.com/pac/demo", ControllerURL: rotateControllerURL}
+	assert.NilError(t, gh.rotateSecret(ctx, opts, rotatedSecret))
+	assert.Equal(t, edited["secret"], rotatedSecret)
+	assert.Equal(t, edited["content_type"], "json")
+
+	opts.ControllerURL = "https://unknown.test"
+	assert.ErrorContains(t, gh.rotateSecret(ctx, opts, rotatedSecret), "cannot find a webhook pointing to the controller url https://unknown.test")
+}
+
+func TestGLRotateSecret(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, teardown := thelp.Setup(ctx, t)
+	defer teardown()
+	//nolint
+	io, _, _, _ := cli.IOTest()
+
+	mux.HandleFunc("/projects/pac/demo/hooks", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `[{"id": 3, "url": "%s", "push_events": true, "merge_requests_events": true, "note_events": true}]`, rotateControllerURL)
+	})
+	edited := map[string]interface{}{}
+	mux.HandleFunc("/projects/pac/demo/hooks/3", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPut)
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&edited))
+		_, _ = fmt.Fprint(w, `{"id": 3}`)
+	})
+
+	gl := gitLabConfig{IOStream: io, Client: fakeclient}
+	opts := &Options{RepositoryURL: "https://gitlab.com/pac/demo", ControllerURL: rotateControllerURL}
+	assert.NilError(t, gl.rotateSecret(ctx, opts, rotatedSecret))
+	assert.Equal(t, edited["token"], rotatedSecret)
+	assert.Equal(t, edited["url"], rotateControllerURL)
+	assert.Equal(t, edited["merge_requests_events"], true)
+}
+
+func TestGiteaRotateSecret(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, teardown := giteatest.Setup(t)
+	defer teardown()
+	//nolint
+	io, _, _, _ := cli.IOTest()
+
+	mux.HandleFunc("/repos/pac/demo/hooks", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `[{"id": 4, "active": true, "events": ["push"], "config": {"url": "%s", "content_type": "json"}}]`, rotateControllerURL)
+	})
+	edited := map[string]interface{}{}
+	mux.HandleFunc("/repos/pac/demo/hooks/4", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPatch)
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&edited))
+		_, _ = fmt.Fprint(w, `{"id": 4}`)
+	})
+
+	gt := giteaConfig{IOStream: io, Client: fakeclient}
+	opts := &Options{RepositoryURL: "https://gitea.pac.test/pac/demo", ControllerURL: rotateControllerURL}
+	assert.NilError(t, gt.rotateSecret(ctx, opts, rotatedSecret))
+	config, ok := edited["config"].(map[string]interface{})
+	assert.Assert(t, ok)
+	assert.Equal(t, config["secret"], rotatedSecret)
+	assert.Equal(t, config["content_type"], "json")
+	assert.DeepEqual(t, edited["events"], []interface{}{"push"})
+}
+
+func TestBBServerRotateSecret(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	client, mux, teardown := bbservertest.SetupBBServerClient(ctx, t)
+	defer teardown()
+	//nolint
+	io, _, _, _ := cli.IOTest()
+
+	mux.HandleFunc("/projects/PAC/repos/demo/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"values": [{"id": 5, "name": "Pipelines as Code", "url": "%s", "active": true, "events": ["pr:opened"]}]}`, rotateControllerURL)
+	})
+	edited := bitbucketServerWebhook{}
+	mux.HandleFunc("/projects/PAC/repos/demo/webhooks/5", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPut)
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&edited))
+		_, _ = fmt.Fprint(w, `{"id": 5}`)
+	})
+
+	bb := bitbucketServerConfig{IOStream: io, Client: client}
+	opts := &Options{RepositoryURL: "https://bitbucket.pac.test/projects/PAC/repos/demo", ControllerURL: rotateControllerURL}
+	assert.NilError(t, bb.rotateSecret(ctx, opts, rotatedSecret))
+	assert.Equal(t, edited.Configuration["secret"], rotatedSecret)
+	assert.Equal(t, edited.Name, "Pipelines as Code")
+	assert.DeepEqual(t, edited.Events, []string{"pr:opened"})
+}
+
+func TestRotateWebhookSecretBitbucketCloud(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	opts := &Options{RepositoryURL: "https://bitbucket.org/pac/demo"}
+	secret, err := opts.RotateWebhookSecret(ctx, "bitbucket-cloud")
+	assert.NilError(t, err)
+	assert.Equal(t, secret, "")
+}
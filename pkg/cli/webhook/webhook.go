@@ -9,7 +9,6 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/bootstrap"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 )
 
@@ -30,6 +29,12 @@ type Options struct {
 	RepositoryCreateORUpdate bool
 	SecretName               string
 	ProviderSecretKey        string
+	// ProviderUser is the user of the personal access token, needed on
+	// Bitbucket Server
+	ProviderUser string
+	// GitLabGroupHook creates the webhook on a GitLab group instead of the
+	// project of the repository
+	GitLabGroupHook bool
 }
 
 type response struct {
@@ -41,28 +46,10 @@ type response struct {
 }
 
 func (w *Options) Install(ctx context.Context, providerType string) error {
-	// figure out pac installation namespace
-	installed, installationNS, err := bootstrap.DetectPacInstallation(ctx, w.PACNamespace, w.Run)
-	if !installed {
-		return fmt.Errorf("pipelines as code not installed")
-	}
-	if installed && err != nil {
-		return err
-	}
-
-	// fetch configmap to get controller url
-	pacInfo, err := info.GetPACInfo(ctx, w.Run, installationNS)
-	if err != nil {
+	if err := w.detectControllerURL(ctx); err != nil {
 		return err
 	}
 
-	// check if info configmap has url then use that otherwise try to detect
-	if pacInfo.ControllerURL != "" && w.ControllerURL == "" {
-		w.ControllerURL = pacInfo.ControllerURL
-	} else {
-		w.ControllerURL, _ = bootstrap.DetectOpenShiftRoute(ctx, w.Run, w.PACNamespace)
-	}
-
 	if w.RepositoryURL == "" {
 		q := "Please enter the Git repository url: "
 		if err := prompt.SurveyAskOne(&survey.Input{Message: q}, &w.RepositoryURL,
@@ -76,9 +63,13 @@ func (w *Options) Install(ctx context.Context, providerType string) error {
 	case "github":
 		webhookProvider = &gitHubConfig{IOStream: w.IOStreams}
 	case "gitlab":
-		webhookProvider = &gitLabConfig{IOStream: w.IOStreams}
+		webhookProvider = &gitLabConfig{IOStream: w.IOStreams, groupHook: w.GitLabGroupHook}
+	case "gitea":
+		webhookProvider = &giteaConfig{IOStream: w.IOStreams}
 	case "bitbucket-cloud":
 		webhookProvider = &bitbucketCloudConfig{IOStream: w.IOStreams}
+	case "bitbucket-server":
+		webhookProvider = &bitbucketServerConfig{IOStream: w.IOStreams}
 	default:
 		return fmt.Errorf("invalid webhook provider")
 	}
@@ -87,6 +78,7 @@ func (w *Options) Install(ctx context.Context, providerType string) error {
 	if err != nil {
 		return err
 	}
+	w.ControllerURL = response.ControllerURL
 
 	// RepositoryCreateORUpdate is false for tkn-pac webhook add command
 	if !w.RepositoryCreateORUpdate {
@@ -102,6 +94,33 @@ func (w *Options) Install(ctx context.Context, providerType string) error {
 	return w.updateRepositoryCR(ctx, response)
 }
 
+// detectControllerURL sets the controller url from the info configmap of the
+// installation or from its OpenShift route, unless it is already set.
+func (w *Options) detectControllerURL(ctx context.Context) error {
+	// figure out pac installation namespace
+	installed, installationNS, err := info.DetectPacInstallation(ctx, w.PACNamespace, w.Run)
+	if !installed {
+		return fmt.Errorf("pipelines as code not installed")
+	}
+	if installed && err != nil {
+		return err
+	}
+
+	// fetch configmap to get controller url
+	pacInfo, err := info.GetPACInfo(ctx, w.Run, installationNS)
+	if err != nil {
+		return err
+	}
+
+	// check if info configmap has url then use that otherwise try to detect
+	if pacInfo.ControllerURL != "" && w.ControllerURL == "" {
+		w.ControllerURL = pacInfo.ControllerURL
+	} else if w.ControllerURL == "" {
+		w.ControllerURL, _ = info.DetectOpenShiftRoute(ctx, w.Run, w.PACNamespace)
+	}
+	return nil
+}
+
 func GetProviderName(url string) (string, error) {
 	var (
 		err          error
@@ -112,6 +131,8 @@ func GetProviderName(url string) (string, error) {
 		providerName = "github"
 	case strings.Contains(url, "gitlab"):
 		providerName = "gitlab"
+	case strings.Contains(url, "gitea"):
+		providerName = "gitea"
 	case strings.Contains(url, "bitbucket-cloud"):
 		providerName = "bitbucket-cloud"
 	default:
@@ -119,7 +140,7 @@ func GetProviderName(url string) (string, error) {
 		if err = prompt.SurveyAskOne(
 			&survey.Select{
 				Message: msg,
-				Options: []string{"github", "gitlab", "bitbucket-cloud"},
+				Options: []string{"github", "gitlab", "gitea", "bitbucket-cloud", "bitbucket-server"},
 				Default: 0,
 			}, &providerName); err != nil {
 			return "", err
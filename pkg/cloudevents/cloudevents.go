@@ -0,0 +1,140 @@
+package cloudevents
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	ce "github.com/cloudevents/sdk-go/v2"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	"knative.dev/pkg/apis"
+)
+
+// the types of the CloudEvents published for the PipelineRuns of the
+// Repositories
+const (
+	PipelineRunQueued    = "repository.pipelinerun.queued"
+	PipelineRunStarted   = "repository.pipelinerun.started"
+	PipelineRunSucceeded = "repository.pipelinerun.succeeded"
+	PipelineRunFailed    = "repository.pipelinerun.failed"
+)
+
+// sendTimeout is how long we wait for the sink, a slow sink should not hold
+// the processing of the webhook events and PipelineRuns
+const sendTimeout = 10 * time.Second
+
+// Data is the payload of the CloudEvents.
+type Data struct {
+	Repository          string `json:"repository"`
+	Namespace           string `json:"namespace"`
+	PipelineRun         string `json:"pipelinerun"`
+	OriginalPipelineRun string `json:"original_pipelinerun,omitempty"`
+	EventType           string `json:"event_type,omitempty"`
+	Branch              string `json:"branch,omitempty"`
+	SHA                 string `json:"sha,omitempty"`
+	PullRequestNumber   int    `json:"pull_request_number,omitempty"`
+	URL                 string `json:"url,omitempty"`
+	LogURL              string `json:"log_url,omitempty"`
+}
+
+// FinalType return the type of the CloudEvent of a done PipelineRun.
+func FinalType(pr *v1beta1.PipelineRun) string {
+	if pr.Status.GetCondition(apis.ConditionSucceeded).IsTrue() {
+		return PipelineRunSucceeded
+	}
+	return PipelineRunFailed
+}
+
+// NewData make the payload of the events of the PipelineRun of the Repository
+// from its labels and annotations.
+func NewData(repo *v1alpha1.Repository, pr *v1beta1.PipelineRun) Data {
+	data := Data{
+		Repository:          repo.GetName(),
+		Namespace:           repo.GetNamespace(),
+		PipelineRun:         pr.GetName(),
+		OriginalPipelineRun: pr.GetLabels()[keys.OriginalPRName],
+		EventType:           pr.GetLabels()[keys.EventType],
+		Branch:              pr.GetLabels()[keys.Branch],
+		SHA:                 pr.GetLabels()[keys.SHA],
+		URL:                 pr.GetAnnotations()[keys.RepoURL],
+		LogURL:              pr.GetAnnotations()[keys.LogURL],
+	}
+	if data.URL == "" {
+		data.URL = repo.Spec.URL
+	}
+	if number, ok := pr.GetLabels()[keys.PullRequest]; ok {
+		data.PullRequestNumber, _ = strconv.Atoi(number)
+	}
+	return data
+}
+
+// MakeEvent make the CloudEvent of the PipelineRun of the Repository, the
+// repository, sha, pull request number and url are set as extensions so the
+// subscribers can filter on them.
+func MakeEvent(eventType string, repo *v1alpha1.Repository, pr *v1beta1.PipelineRun) (ce.Event, error) {
+	data := NewData(repo, pr)
+	event := ce.NewEvent()
+	event.SetType(eventType)
+	event.SetSource(fmt.Sprintf("/apis/%s/%s/namespaces/%s/repositories/%s",
+		pipelinesascode.GroupName, pipelinesascode.V1alpha1Version, repo.GetNamespace(), repo.GetName()))
+	event.SetSubject(pr.GetName())
+	event.SetExtension("repository", repo.GetNamespace()+"/"+repo.GetName())
+	event.SetExtension("url", data.URL)
+	if data.SHA != "" {
+		event.SetExtension("sha", data.SHA)
+	}
+	if data.PullRequestNumber != 0 {
+		event.SetExtension("pullrequest", data.PullRequestNumber)
+	}
+	if err := event.SetData(ce.ApplicationJSON, data); err != nil {
+		return event, err
+	}
+	return event, nil
+}
+
+// Emitter publish the CloudEvents of the PipelineRuns to a sink.
+type Emitter struct {
+	client ce.Client
+	logger *zap.SugaredLogger
+}
+
+func NewEmitter(logger *zap.SugaredLogger) *Emitter {
+	// the client set the id and the time of the events
+	client, err := ce.NewClientHTTP()
+	if err != nil {
+		logger.Errorf("cannot create the cloudevents client: %v", err)
+	}
+	return &Emitter{client: client, logger: logger}
+}
+
+func (e *Emitter) SetLogger(logger *zap.SugaredLogger) {
+	e.logger = logger
+}
+
+// Emit send the CloudEvent of the PipelineRun to the sink, nothing is sent
+// when the sink is empty. The errors are only logged, the PipelineRuns should
+// not be affected by an unavailable sink.
+func (e *Emitter) Emit(ctx context.Context, sink, eventType string, repo *v1alpha1.Repository, pr *v1beta1.PipelineRun) {
+	if sink == "" || e.client == nil || repo == nil || pr == nil {
+		return
+	}
+
+	event, err := MakeEvent(eventType, repo, pr)
+	if err != nil {
+		e.logger.Errorf("cannot make the cloudevent %s for pipelinerun %s/%s: %v", eventType, pr.GetNamespace(), pr.GetName(), err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ce.ContextWithTarget(ctx, sink), sendTimeout)
+	defer cancel()
+	if result := e.client.Send(ctx, event); !ce.IsACK(result) {
+		e.logger.Errorf("cannot send the cloudevent %s for pipelinerun %s/%s to %s: %v", eventType, pr.GetNamespace(), pr.GetName(), sink, result)
+		return
+	}
+	e.logger.Debugf("sent the cloudevent %s for pipelinerun %s/%s to %s", eventType, pr.GetNamespace(), pr.GetName(), sink)
+}
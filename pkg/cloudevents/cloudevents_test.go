@@ -0,0 +1,137 @@
+package cloudevents
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+var (
+	testRepo = &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Spec:       v1alpha1.RepositorySpec{URL: "https://forge/owner/repo"},
+	}
+	testPR = &v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pull-request-abcde",
+			Namespace: "ns",
+			Labels: map[string]string{
+				keys.OriginalPRName: "pull-request",
+				keys.EventType:      "pull_request",
+				keys.SHA:            "sha1234",
+				keys.PullRequest:    "42",
+			},
+			Annotations: map[string]string{
+				keys.RepoURL: "https://forge/owner/repo",
+			},
+		},
+	}
+)
+
+func TestMakeEvent(t *testing.T) {
+	got, err := MakeEvent(PipelineRunStarted, testRepo, testPR)
+	assert.NilError(t, err)
+	assert.Equal(t, got.Type(), "repository.pipelinerun.started")
+	assert.Equal(t, got.Source(), "/apis/pipelinesascode.tekton.dev/v1alpha1/namespaces/ns/repositories/repo")
+	assert.Equal(t, got.Subject(), "pull-request-abcde")
+	assert.Equal(t, got.Extensions()["repository"], "ns/repo")
+	assert.Equal(t, got.Extensions()["sha"], "sha1234")
+	assert.Equal(t, got.Extensions()["pullrequest"], int32(42))
+	assert.Equal(t, got.Extensions()["url"], "https://forge/owner/repo")
+
+	data := Data{}
+	assert.NilError(t, got.DataAs(&data))
+	assert.DeepEqual(t, data, Data{
+		Repository:          "repo",
+		Namespace:           "ns",
+		PipelineRun:         "pull-request-abcde",
+		OriginalPipelineRun: "pull-request",
+		EventType:           "pull_request",
+		SHA:                 "sha1234",
+		PullRequestNumber:   42,
+		URL:                 "https://forge/owner/repo",
+	})
+}
+
+func TestFinalType(t *testing.T) {
+	pr := testPR.DeepCopy()
+	pr.Status.Status = duckv1.Status{Conditions: duckv1.Conditions{
+		{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue},
+	}}
+	assert.Equal(t, FinalType(pr), PipelineRunSucceeded)
+
+	pr.Status.Conditions[0].Status = corev1.ConditionFalse
+	assert.Equal(t, FinalType(pr), PipelineRunFailed)
+}
+
+func TestEmit(t *testing.T) {
+	tests := []struct {
+		name       string
+		sinkStatus int
+		noSink     bool
+		wantSent   bool
+		wantLog    string
+	}{
+		{
+			name:       "sent",
+			sinkStatus: http.StatusAccepted,
+			wantSent:   true,
+		},
+		{
+			name:   "no sink",
+			noSink: true,
+		},
+		{
+			name:       "sink error",
+			sinkStatus: http.StatusInternalServerError,
+			wantSent:   true,
+			wantLog:    "cannot send the cloudevent repository.pipelinerun.queued for pipelinerun ns/pull-request-abcde",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, log := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+
+			var received *event.Event
+			sink := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				var err error
+				received, err = cehttp.NewEventFromHTTPRequest(r)
+				assert.NilError(t, err)
+				rw.WriteHeader(tt.sinkStatus)
+			}))
+			defer sink.Close()
+			sinkURL := sink.URL
+			if tt.noSink {
+				sinkURL = ""
+			}
+
+			NewEmitter(logger).Emit(ctx, sinkURL, PipelineRunQueued, testRepo, testPR)
+			assert.Equal(t, received != nil, tt.wantSent)
+			if received != nil {
+				assert.Equal(t, received.Type(), PipelineRunQueued)
+				assert.Assert(t, received.ID() != "")
+			}
+			if tt.wantLog != "" {
+				assert.Assert(t, log.FilterMessageSnippet(tt.wantLog).Len() > 0, log.All())
+			} else {
+				assert.Equal(t, log.Len(), 0, log.All())
+			}
+		})
+	}
+}
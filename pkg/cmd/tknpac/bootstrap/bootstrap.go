@@ -11,22 +11,18 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	"github.com/spf13/cobra"
-	kapierror "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
-	pacNS                  = "pipelines-as-code"
-	openShiftRouteGroup    = "route.openshift.io"
-	openShiftRouteVersion  = "v1"
-	openShiftRouteResource = "routes"
-	secretName             = "pipelines-as-code-secret"
-	defaultProviderType    = "github-app"
+	pacNS               = "pipelines-as-code"
+	openShiftRouteGroup = "route.openshift.io"
+	secretName          = "pipelines-as-code-secret"
+	defaultProviderType = "github-app"
 	// https://webhook.chmouel.com/ is a good value too :p
 	defaultWebForwarderURL = "https://smee.io"
 )
 
-var providerTargets = []string{"github-app", "github-enterprise-app"}
+var providerTargets = append([]string{"github-app", "github-enterprise-app"}, webhookProviderTargets...)
 
 type bootstrapOpts struct {
 	providerType      string
@@ -50,8 +46,6 @@ type bootstrapOpts struct {
 	forceGitHubApp         bool
 }
 
-const infoConfigMap = "pipelines-as-code-info"
-
 const indexTmpl = `
 <html>
 <body>
@@ -81,7 +75,7 @@ func install(ctx context.Context, run *params.Run, opts *bootstrapOpts) error {
 
 	// if we gt a ns back it means it has been detected in here so keep it as is.
 	// or else just set the default to pacNS
-	installed, ns, err := DetectPacInstallation(ctx, opts.targetNamespace, run)
+	installed, ns, err := info.DetectPacInstallation(ctx, opts.targetNamespace, run)
 
 	// installed but there is error for missing resources
 	if installed && err != nil && !opts.forceInstall {
@@ -105,7 +99,7 @@ func createSecret(ctx context.Context, run *params.Run, opts *bootstrapOpts) err
 	var err error
 
 	if opts.RouteName == "" {
-		opts.RouteName, _ = DetectOpenShiftRoute(ctx, run, opts.targetNamespace)
+		opts.RouteName, _ = info.DetectOpenShiftRoute(ctx, run, opts.targetNamespace)
 		if opts.RouteName != "" {
 			opts.autoDetectedRoute = true
 		}
@@ -150,6 +144,10 @@ func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 				}
 			}
 
+			if isWebhookProvider(opts.providerType) {
+				return bootstrapWebhook(ctx, run, opts)
+			}
+
 			pacInfo, err := info.GetPACInfo(ctx, run, opts.targetNamespace)
 			if err != nil {
 				return err
@@ -204,7 +202,7 @@ func GithubApp(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 
 			var err error
 			var installed bool
-			installed, opts.targetNamespace, err = DetectPacInstallation(ctx, opts.targetNamespace, run)
+			installed, opts.targetNamespace, err = info.DetectPacInstallation(ctx, opts.targetNamespace, run)
 			if err != nil {
 				return err
 			}
@@ -242,35 +240,6 @@ func GithubApp(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 	return cmd
 }
 
-func DetectPacInstallation(ctx context.Context, wantedNS string, run *params.Run) (bool, string, error) {
-	var installed bool
-	_, err := run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("").List(ctx, metav1.ListOptions{})
-	if err != nil && kapierror.IsNotFound(err) {
-		return false, "", nil
-	}
-
-	installed = true
-	if wantedNS != "" {
-		_, err := run.Clients.Kube.CoreV1().ConfigMaps(wantedNS).Get(ctx, infoConfigMap, metav1.GetOptions{})
-		if err == nil {
-			return installed, wantedNS, nil
-		}
-		return installed, "", fmt.Errorf("could not detect Pipelines as Code configmap in %s namespace : %w, please reinstall", wantedNS, err)
-	}
-
-	cms, err := run.Clients.Kube.CoreV1().ConfigMaps("").List(ctx, metav1.ListOptions{
-		LabelSelector: configMapPacLabel,
-	})
-	if err == nil {
-		for _, cm := range cms.Items {
-			if cm.Name == infoConfigMap {
-				return installed, cm.Namespace, nil
-			}
-		}
-	}
-	return installed, "", fmt.Errorf("could not detect Pipelines as Code configmap on the cluster, please reinstall")
-}
-
 func addGithubAppFlag(cmd *cobra.Command, opts *bootstrapOpts) {
 	cmd.PersistentFlags().StringVar(&opts.GithubOrganizationName, "github-organization-name", "", "Whether you want to target an organization instead of the current user")
 	cmd.PersistentFlags().StringVar(&opts.GithubApplicationName, "github-application-name", "", "GitHub Application Name")
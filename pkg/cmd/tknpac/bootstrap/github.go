@@ -22,8 +22,11 @@ func generateManifest(opts *bootstrapOpts) ([]byte, error) {
 			"issue_comment",
 			"pull_request",
 			"push",
+			"release",
+			"workflow_run",
 		},
 		DefaultPermissions: &github.InstallationPermissions{
+			Actions:          github.String("read"),
 			Checks:           github.String("write"),
 			Contents:         github.String("write"),
 			Issues:           github.String("write"),
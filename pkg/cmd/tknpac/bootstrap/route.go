@@ -6,47 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-)
-
-const (
-	routePacLabel = "pipelines-as-code/route=controller"
 )
 
-// DetectOpenShiftRoute detect the openshift route where the pac controller is running
-func DetectOpenShiftRoute(ctx context.Context, run *params.Run, targetNamespace string) (string, error) {
-	gvr := schema.GroupVersionResource{
-		Group: openShiftRouteGroup, Version: openShiftRouteVersion, Resource: openShiftRouteResource,
-	}
-	routes, err := run.Clients.Dynamic.Resource(gvr).Namespace(targetNamespace).List(ctx, metav1.ListOptions{
-		LabelSelector: routePacLabel,
-	})
-	if err != nil {
-		return "", err
-	}
-	if len(routes.Items) != 1 {
-		return "", err
-	}
-	route := routes.Items[0]
-
-	spec, ok := route.Object["spec"].(map[string]interface{})
-	if !ok {
-		return "", fmt.Errorf("couldn't find spec in the PAC Controller route")
-	}
-
-	host, ok := spec["host"].(string)
-	if !ok {
-		// this condition is satisfied if there's no metadata at all in the provided CR
-		return "", fmt.Errorf("couldn't find spec.host in the PAC controller route")
-	}
-
-	return fmt.Sprintf("https://%s", host), nil
-}
-
-func detectSelfSignedCertificate(ctx context.Context, url string) string {
+// detectSelfSignedCertificate checks the controller is reachable on its url,
+// where is what connects to it on the git provider, ie: "github app"
+func detectSelfSignedCertificate(ctx context.Context, url, where string) string {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return fmt.Sprintf("invalid url?? %s", url)
@@ -55,7 +19,7 @@ func detectSelfSignedCertificate(ctx context.Context, url string) string {
 	client := http.Client{}
 	resp, err := client.Do(req)
 	if err != nil && isTLSError(err) {
-		return "⚠️ your controller route is using a self signed certificate\n⚠️ make sure you allow to connect to self signed url in your github app setting."
+		return fmt.Sprintf("⚠️ your controller route is using a self signed certificate\n⚠️ make sure you allow to connect to self signed url in your %s setting.", where)
 	} else if err != nil {
 		return fmt.Sprintf("⚠️ could not connect to the route %s, make sure the pipelines-as-code controller is running", url)
 	}
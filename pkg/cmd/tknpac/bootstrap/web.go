@@ -77,7 +77,7 @@ func startWebServer(ctx context.Context, opts *bootstrapOpts, run *params.Run, j
 	fmt.Fprintf(opts.ioStreams.Out, "🚀 You can now add your newly created application on your repository by going to this URL:\n\n%s\n\n", *manifest.HTMLURL)
 	fmt.Fprintf(opts.ioStreams.Out, "💡 Don't forget to run the \"%s pac create repo\" to create a new Repository CRD on your cluster.\n", settings.TknBinaryName)
 
-	detectString := detectSelfSignedCertificate(ctx, opts.RouteName)
+	detectString := detectSelfSignedCertificate(ctx, opts.RouteName, "github app")
 	if detectString != "" {
 		fmt.Fprintln(opts.ioStreams.Out, detectString)
 	}
@@ -0,0 +1,113 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	pacInfo "github.com/openshift-pipelines/pipelines-as-code/pkg/cli/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/webhook"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/create"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+)
+
+// webhookProviderTargets are the install types configured with a webhook on
+// a repository instead of a GitHub Application
+var webhookProviderTargets = []string{"gitlab", "gitea", "bitbucket-cloud", "bitbucket-server"}
+
+func isWebhookProvider(providerType string) bool {
+	for _, target := range webhookProviderTargets {
+		if target == providerType {
+			return true
+		}
+	}
+	return false
+}
+
+// bootstrapWebhook creates the Repository CR of a repository, the webhook
+// delivering its events to the controller and the secret of both, then checks
+// the git provider can reach the controller.
+func bootstrapWebhook(ctx context.Context, run *params.Run, opts *bootstrapOpts) error {
+	installed, ns, err := pacInfo.DetectPacInstallation(ctx, opts.targetNamespace, run)
+	if !installed {
+		if opts.skipInstall {
+			return fmt.Errorf("pipelines as code is not installed, remove the --skip-install flag to install it")
+		}
+		return fmt.Errorf("the installation of pipelines as code cannot be detected")
+	}
+	if err != nil {
+		return err
+	}
+	opts.targetNamespace = ns
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	repoOpts := &create.RepoOptions{
+		Event:      info.NewEvent(),
+		Repository: &apipac.Repository{},
+		Run:        run,
+		GitInfo:    git.GetGitInfo(cwd),
+		IoStreams:  opts.ioStreams,
+	}
+	if err := create.GetRepoURL(repoOpts); err != nil {
+		return err
+	}
+
+	groupHook := false
+	if opts.providerType == "gitlab" {
+		if groupHook, err = askYN(false, "",
+			"Do you want to create the webhook on a GitLab group instead of the project, to use it with all the projects of the group?",
+			opts.ioStreams.Out); err != nil {
+			return err
+		}
+	}
+
+	repoName, repoNamespace, err := repoOpts.Create(ctx)
+	if err != nil {
+		return err
+	}
+
+	hook := &webhook.Options{
+		Run:                      run,
+		IOStreams:                opts.ioStreams,
+		PACNamespace:             opts.targetNamespace,
+		RepositoryURL:            repoOpts.Event.URL,
+		RepositoryName:           repoName,
+		RepositoryNamespace:      repoNamespace,
+		ControllerURL:            opts.RouteName,
+		RepositoryCreateORUpdate: true,
+		GitLabGroupHook:          groupHook,
+	}
+	if err := hook.Install(ctx, opts.providerType); err != nil {
+		return err
+	}
+
+	// keep the provider of an existing GitHub Application, the controller url
+	// is used by the next "tkn pac create repo"
+	cmInfo, err := pacInfo.GetPACInfo(ctx, run, opts.targetNamespace)
+	if err != nil {
+		return err
+	}
+	if err := pacInfo.UpdateInfoConfigMap(ctx, run, &pacInfo.Options{
+		TargetNamespace: opts.targetNamespace,
+		ControllerURL:   hook.ControllerURL,
+		Provider:        cmInfo.Provider,
+	}); err != nil {
+		return err
+	}
+
+	if detectString := detectSelfSignedCertificate(ctx, hook.ControllerURL, "webhook"); detectString != "" {
+		fmt.Fprintln(opts.ioStreams.Out, detectString)
+	} else {
+		fmt.Fprintf(opts.ioStreams.Out, "✓ The controller is reachable on %s\n", hook.ControllerURL)
+	}
+
+	fmt.Fprintf(opts.ioStreams.Out, "💡 Don't forget to run the \"%s pac generate\" to create a PipelineRun in the .tekton directory of your repository.\n", settings.TknBinaryName)
+	return nil
+}
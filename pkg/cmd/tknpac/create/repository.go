@@ -14,7 +14,6 @@ import (
 	pacInfo "github.com/openshift-pipelines/pipelines-as-code/pkg/cli/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/webhook"
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/bootstrap"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/generate"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
@@ -67,7 +66,7 @@ func repositoryCommand(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command
 				return err
 			}
 
-			if err := getRepoURL(createOpts); err != nil {
+			if err := GetRepoURL(createOpts); err != nil {
 				return err
 			}
 
@@ -77,7 +76,7 @@ func repositoryCommand(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command
 			}
 
 			var providerName string
-			_, installationNS, err := bootstrap.DetectPacInstallation(ctx, createOpts.pacNamespace, run)
+			_, installationNS, err := pacInfo.DetectPacInstallation(ctx, createOpts.pacNamespace, run)
 			if err != nil {
 				return err
 			}
@@ -217,8 +216,8 @@ func getOrCreateNamespace(ctx context.Context, opts *RepoOptions) error {
 	return err
 }
 
-// getRepoURL get the repository URL from the user using the git url as default.
-func getRepoURL(opts *RepoOptions) error {
+// GetRepoURL get the repository URL from the user using the git url as default.
+func GetRepoURL(opts *RepoOptions) error {
 	if opts.Event.URL != "" {
 		return nil
 	}
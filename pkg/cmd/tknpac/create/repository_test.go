@@ -81,7 +81,7 @@ func TestGetRepoURL(t *testing.T) {
 				tt.askStubs(as)
 			}
 			io, _, _, _ := cli.IOTest()
-			err := getRepoURL(&RepoOptions{
+			err := GetRepoURL(&RepoOptions{
 				Event:      &tt.event,
 				Repository: &tt.repo,
 				GitInfo:    &tt.gitinfo,
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strings"
 	"text/tabwriter"
 	"text/template"
 
@@ -25,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	knativeapis "knative.dev/pkg/apis"
 )
 
 var (
@@ -34,6 +36,7 @@ var (
 	showEventflag     = "show-events"
 	creationTimestamp = "{.metadata.creationTimestamp}"
 	maxEventLimit     = 50
+	queuedCondition   = knativeapis.ConditionType("Queued")
 )
 
 //go:embed templates/describe.tmpl
@@ -46,6 +49,17 @@ func formatError(cs *cli.ColorScheme, log string) string {
 	return n
 }
 
+// taskFailures returns the tasks with a failure snippet
+func taskFailures(results []v1alpha1.TaskResult) []v1alpha1.TaskResult {
+	failures := []v1alpha1.TaskResult{}
+	for _, result := range results {
+		if result.FailureSnippet != "" {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
 func formatStatus(status v1alpha1.RepositoryRunStatus, cs *cli.ColorScheme, c clockwork.Clock) string {
 	return fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%s",
 		cs.ColorStatus(status.Status.Conditions[0].Reason),
@@ -57,6 +71,27 @@ func formatStatus(status v1alpha1.RepositoryRunStatus, cs *cli.ColorScheme, c cl
 		cs.HyperLink(status.PipelineRunName, *status.LogURL))
 }
 
+// pipelineRunName returns the name of a PipelineRun of the queue, listed as
+// namespace/name
+func pipelineRunName(key string) string {
+	if _, name, ok := strings.Cut(key, "/"); ok {
+		return name
+	}
+	return key
+}
+
+// queueMessage returns the message of the Queued condition of the queue of
+// the repository
+func queueMessage(repository *v1alpha1.Repository) string {
+	if repository.QueueStatus == nil {
+		return ""
+	}
+	if condition := repository.QueueStatus.GetCondition(queuedCondition); condition != nil {
+		return condition.Message
+	}
+	return ""
+}
+
 type describeOpts struct {
 	cli.PacCliOpts
 	TargetPipelineRun string
@@ -228,13 +263,16 @@ func describe(ctx context.Context, cs *params.Run, clock clockwork.Clock, opts *
 
 	colorScheme := ioStreams.ColorScheme()
 	funcMap := template.FuncMap{
-		"formatError":     formatError,
-		"formatStatus":    formatStatus,
-		"formatEventType": formatting.CamelCasit,
-		"formatDuration":  formatting.PRDuration,
-		"formatTime":      formatting.Age,
-		"sanitizeBranch":  formatting.SanitizeBranch,
-		"shortSHA":        formatting.ShortSHA,
+		"formatError":        formatError,
+		"formatStatus":       formatStatus,
+		"formatEventType":    formatting.CamelCasit,
+		"formatDuration":     formatting.PRDuration,
+		"formatTaskDuration": formatting.Duration,
+		"taskFailures":       taskFailures,
+		"formatTime":         formatting.Age,
+		"sanitizeBranch":     formatting.SanitizeBranch,
+		"shortSHA":           formatting.ShortSHA,
+		"pipelineRunName":    pipelineRunName,
 	}
 
 	statuses := status.MixLivePRandRepoStatus(ctx, cs, *repository)
@@ -253,13 +291,16 @@ func describe(ctx context.Context, cs *params.Run, clock clockwork.Clock, opts *
 		Clock       clockwork.Clock
 		Opts        *describeOpts
 		EventList   []corev1.Event
+		// QueueMessage is the message of the Queued condition of the queue
+		QueueMessage string
 	}{
-		Repository:  repository,
-		Statuses:    statuses,
-		ColorScheme: colorScheme,
-		Clock:       clock,
-		EventList:   eventList,
-		Opts:        opts,
+		Repository:   repository,
+		Statuses:     statuses,
+		ColorScheme:  colorScheme,
+		Clock:        clock,
+		EventList:    eventList,
+		Opts:         opts,
+		QueueMessage: queueMessage(repository),
 	}
 	w := ansiterm.NewTabWriter(ioStreams.Out, 0, 5, 3, ' ', tabwriter.TabIndent)
 	t := template.Must(template.New("Describe Repository").Funcs(funcMap).Parse(describeTemplate))
@@ -37,6 +37,7 @@ func TestDescribe(t *testing.T) {
 		opts             *describeOpts
 		pruns            []*tektonv1beta1.PipelineRun
 		events           []*corev1.Event
+		queueStatus      *v1alpha1.RepositoryQueueStatus
 	}
 	tests := []struct {
 		name    string
@@ -185,6 +186,57 @@ func TestDescribe(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "task results",
+			args: args{
+				repoName:         "test-run",
+				currentNamespace: "namespace",
+				opts: &describeOpts{
+					PacCliOpts: cli.PacCliOpts{
+						Namespace: "optnamespace",
+					},
+				},
+				statuses: []v1alpha1.RepositoryRunStatus{
+					{
+						Status: knativeduckv1.Status{
+							Conditions: []knativeapis.Condition{
+								{
+									Reason: "Failed",
+								},
+							},
+						},
+						TaskResults: []v1alpha1.TaskResult{
+							{
+								Name:           "clone",
+								Status:         "Succeeded",
+								StartTime:      &metav1.Time{Time: cw.Now().Add(-16 * time.Minute)},
+								CompletionTime: &metav1.Time{Time: cw.Now().Add(-15 * time.Minute)},
+							},
+							{
+								Name:           "unit-tests",
+								Status:         "Failed",
+								StartTime:      &metav1.Time{Time: cw.Now().Add(-15 * time.Minute)},
+								CompletionTime: &metav1.Time{Time: cw.Now().Add(-12 * time.Minute)},
+								FailureSnippet: "FAIL: TestSomething",
+							},
+							{
+								Name:   "publish",
+								Status: "Cancelled",
+							},
+						},
+						PipelineRunName: "pipelinerun1",
+						LogURL:          github.String("https://everywhere.anwywhere"),
+						StartTime:       &metav1.Time{Time: cw.Now().Add(-16 * time.Minute)},
+						CompletionTime:  &metav1.Time{Time: cw.Now().Add(-12 * time.Minute)},
+						SHA:             github.String("SHA"),
+						SHAURL:          github.String("https://anurl.com/commit/SHA"),
+						Title:           github.String("A title"),
+						TargetBranch:    github.String("TargetBranch"),
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "use real time",
 			args: args{
@@ -376,6 +428,31 @@ func TestDescribe(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "queued runs",
+			args: args{
+				opts:             &describeOpts{},
+				repoName:         "test-run",
+				currentNamespace: "namespace",
+				queueStatus: &v1alpha1.RepositoryQueueStatus{
+					Status: knativeduckv1.Status{
+						Conditions: knativeduckv1.Conditions{
+							{
+								Type:    "Queued",
+								Status:  corev1.ConditionTrue,
+								Message: "2 PipelineRuns are waiting for the concurrency limit of 1, namespace/second is the next one",
+							},
+						},
+					},
+					Running: []string{"namespace/first"},
+					Pending: []v1alpha1.QueuedPipelineRun{
+						{Name: "namespace/second", Position: 1, QueuedTime: &metav1.Time{Time: cw.Now().Add(-10 * time.Minute)}},
+						{Name: "namespace/third", Position: 2, QueuedTime: &metav1.Time{Time: cw.Now().Add(-5 * time.Minute)}},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "multiple repo status",
 			args: args{
@@ -459,7 +536,8 @@ func TestDescribe(t *testing.T) {
 					Spec: v1alpha1.RepositorySpec{
 						URL: "https://anurl.com",
 					},
-					Status: tt.args.statuses,
+					Status:      tt.args.statuses,
+					QueueStatus: tt.args.queueStatus,
 				},
 			}
 
@@ -0,0 +1,225 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+var (
+	allNamespacesFlag = "all-namespaces"
+	namespaceFlag     = "namespace"
+	eventTypeFlag     = "event-type"
+	outcomeFlag       = "outcome"
+	followFlag        = "follow"
+	limitFlag         = "limit"
+)
+
+const (
+	outcomeMatched = "matched"
+	outcomeSkipped = "skipped"
+	outcomeError   = "error"
+	outcomeInfo    = "info"
+)
+
+// skippedReasons are the reasons of the events emitted by the controller when
+// it didn't run anything for a webhook event
+var skippedReasons = map[string]bool{
+	"RepositoryNoMatch":                 true,
+	"RepositoryPipelineRunNotFound":     true,
+	"RepositoryCannotLocatePipelineRun": true,
+	"RepositoryPermissionDenied":        true,
+	"RepositoryNamespaceMatch":          true,
+	"RepositorySetStatus":               true,
+	"RepositoryEvent":                   true,
+	"RepositoryFrozen":                  true,
+}
+
+var longhelp = fmt.Sprintf(`events - show the events processed by the Pipelines as Code controller.
+
+Show the kubernetes events the controller emitted on the Repositories while
+processing the webhook events, filtered by repository, webhook event type and
+outcome (matched, skipped, error or info). With the --follow flag the new
+events are streamed as they come, which is useful while setting up a webhook:
+
+%s pac events my-repo --event-type pull_request --outcome error --follow`, settings.TknBinaryName)
+
+type eventsOpts struct {
+	cli.PacCliOpts
+	Repository string
+	EventType  string
+	Outcome    string
+	Follow     bool
+	Limit      int
+}
+
+func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &eventsOpts{}
+	cmd := &cobra.Command{
+		Use:          "events [repository]",
+		Short:        "Show the events processed by the controller",
+		Long:         longhelp,
+		SilenceUsage: true,
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion("repositories", args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.PacCliOpts = *cli.NewCliOptions(cmd)
+			var err error
+			if opts.AllNameSpaces, err = cmd.Flags().GetBool(allNamespacesFlag); err != nil {
+				return err
+			}
+			if opts.Namespace, err = cmd.Flags().GetString(namespaceFlag); err != nil {
+				return err
+			}
+			if len(args) > 0 {
+				opts.Repository = args[0]
+			}
+			switch opts.Outcome {
+			case "", outcomeMatched, outcomeSkipped, outcomeError, outcomeInfo:
+			default:
+				return fmt.Errorf("invalid outcome %s, use one of: %s, %s, %s, %s", opts.Outcome,
+					outcomeMatched, outcomeSkipped, outcomeError, outcomeInfo)
+			}
+
+			ctx := context.Background()
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			return events(ctx, run, opts, ioStreams)
+		},
+	}
+
+	cmd.Flags().BoolP(allNamespacesFlag, "A", false, "show the events across all namespaces.")
+	cmd.Flags().StringP(namespaceFlag, "n", "", "If present, the namespace scope for this CLI request")
+	_ = cmd.RegisterFlagCompletionFunc(namespaceFlag,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion(namespaceFlag, args)
+		},
+	)
+	cmd.Flags().StringVar(&opts.EventType, eventTypeFlag, "", "only show the events of this webhook event type (ie: push, pull_request)")
+	cmd.Flags().StringVar(&opts.Outcome, outcomeFlag, "", "only show the events with this outcome: matched, skipped, error or info")
+	cmd.Flags().BoolVarP(&opts.Follow, followFlag, "f", false, "stream the new events as they come")
+	cmd.Flags().IntVar(&opts.Limit, limitFlag, 50, "show only the last events, use 0 for all of them")
+	return cmd
+}
+
+// outcome classify an event emitted by the controller.
+func outcome(event *corev1.Event) string {
+	switch {
+	case event.Reason == "RepositoryPipelineRunCreated":
+		return outcomeMatched
+	case skippedReasons[event.Reason]:
+		return outcomeSkipped
+	case event.Type == corev1.EventTypeWarning:
+		return outcomeError
+	}
+	return outcomeInfo
+}
+
+func events(ctx context.Context, cs *params.Run, opts *eventsOpts, ioStreams *cli.IOStreams) error {
+	ns := cs.Info.Kube.Namespace
+	if opts.Namespace != "" {
+		ns = opts.Namespace
+	}
+	if opts.AllNameSpaces {
+		ns = ""
+	}
+
+	selector := map[string]string{}
+	if opts.Repository != "" {
+		selector[keys.Repository] = opts.Repository
+	}
+	if opts.EventType != "" {
+		selector[keys.EventType] = formatting.K8LabelsCleanup(opts.EventType)
+	}
+	listOpts := metav1.ListOptions{LabelSelector: labels.SelectorFromSet(selector).String()}
+	if len(selector) == 0 {
+		// only the events emitted on Repositories
+		listOpts.LabelSelector = keys.Repository
+	}
+
+	list, err := cs.Clients.Kube.CoreV1().Events(ns).List(ctx, listOpts)
+	if err != nil {
+		return err
+	}
+	items := list.Items
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreationTimestamp.Before(&items[j].CreationTimestamp)
+	})
+
+	filtered := []corev1.Event{}
+	for i := range items {
+		if opts.Outcome == "" || outcome(&items[i]) == opts.Outcome {
+			filtered = append(filtered, items[i])
+		}
+	}
+	if opts.Limit > 0 && len(filtered) > opts.Limit {
+		filtered = filtered[len(filtered)-opts.Limit:]
+	}
+
+	colorScheme := ioStreams.ColorScheme()
+	for i := range filtered {
+		printEvent(ioStreams, colorScheme, &filtered[i], opts)
+	}
+	if !opts.Follow {
+		return nil
+	}
+
+	listOpts.ResourceVersion = list.ResourceVersion
+	watcher, err := cs.Clients.Kube.CoreV1().Events(ns).Watch(ctx, listOpts)
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+	for e := range watcher.ResultChan() {
+		if e.Type != watch.Added {
+			continue
+		}
+		event, ok := e.Object.(*corev1.Event)
+		if !ok || (opts.Outcome != "" && outcome(event) != opts.Outcome) {
+			continue
+		}
+		printEvent(ioStreams, colorScheme, event, opts)
+	}
+	return nil
+}
+
+func printEvent(ioStreams *cli.IOStreams, cs *cli.ColorScheme, event *corev1.Event, opts *eventsOpts) {
+	eventOutcome := outcome(event)
+	switch eventOutcome {
+	case outcomeMatched:
+		eventOutcome = cs.Green(eventOutcome)
+	case outcomeSkipped:
+		eventOutcome = cs.Yellow(eventOutcome)
+	case outcomeError:
+		eventOutcome = cs.Red(eventOutcome)
+	}
+
+	repository := event.GetLabels()[keys.Repository]
+	if opts.AllNameSpaces {
+		repository = event.GetNamespace() + "/" + repository
+	}
+	eventType := event.GetLabels()[keys.EventType]
+	if eventType == "" {
+		eventType = "---"
+	}
+	fmt.Fprintf(ioStreams.Out, "%s %s %s %s %s: %s\n",
+		cs.Dimmed(event.CreationTimestamp.Format("2006-01-02T15:04:05Z07:00")), // RFC3339
+		cs.Bold(repository), eventType, eventOutcome, event.Reason, event.Message)
+}
@@ -0,0 +1,138 @@
+package events
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tcli "github.com/openshift-pipelines/pipelines-as-code/pkg/test/cli"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func makeEvent(name, ns, repo, eventType, reason, kind string, age int) *corev1.Event {
+	labels := map[string]string{keys.Repository: repo}
+	if eventType != "" {
+		labels[keys.EventType] = eventType
+	}
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         ns,
+			Labels:            labels,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Duration(age) * time.Minute)),
+		},
+		Reason:  reason,
+		Type:    kind,
+		Message: "message of " + name,
+	}
+}
+
+func TestEvents(t *testing.T) {
+	ns := "ns"
+	kevents := []*corev1.Event{
+		makeEvent("created", ns, "repo", "pull_request", "RepositoryPipelineRunCreated", corev1.EventTypeNormal, 3),
+		makeEvent("nomatch", ns, "repo", "push", "RepositoryNoMatch", corev1.EventTypeNormal, 2),
+		makeEvent("failed", ns, "repo", "pull_request", "RepositoryPipelineRun", corev1.EventTypeWarning, 1),
+		makeEvent("other", ns, "other", "pull_request", "RepositoryPipelineRunCreated", corev1.EventTypeNormal, 1),
+		makeEvent("othernamespace", "otherns", "repo", "push", "RepositoryNoMatch", corev1.EventTypeNormal, 1),
+	}
+
+	tests := []struct {
+		name      string
+		opts      *eventsOpts
+		want      []string
+		wantOrder bool
+	}{
+		{
+			name:      "all events of a repository",
+			opts:      &eventsOpts{Repository: "repo"},
+			want:      []string{"created", "nomatch", "failed"},
+			wantOrder: true,
+		},
+		{
+			name: "all repositories",
+			opts: &eventsOpts{},
+			want: []string{"created", "nomatch", "failed", "other"},
+		},
+		{
+			name: "only the current namespace",
+			opts: &eventsOpts{Repository: "repo", EventType: "push"},
+			want: []string{"nomatch"},
+		},
+		{
+			name: "filter by event type",
+			opts: &eventsOpts{Repository: "repo", EventType: "pull_request"},
+			want: []string{"created", "failed"},
+		},
+		{
+			name: "filter by outcome matched",
+			opts: &eventsOpts{Outcome: outcomeMatched},
+			want: []string{"created", "other"},
+		},
+		{
+			name: "filter by outcome skipped",
+			opts: &eventsOpts{Repository: "repo", Outcome: outcomeSkipped},
+			want: []string{"nomatch"},
+		},
+		{
+			name: "filter by outcome error",
+			opts: &eventsOpts{Repository: "repo", Outcome: outcomeError},
+			want: []string{"failed"},
+		},
+		{
+			name: "limit to the last events",
+			opts: &eventsOpts{Repository: "repo", Limit: 1},
+			want: []string{"failed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{Events: kevents})
+			cs := &params.Run{
+				Clients: clients.Clients{Kube: stdata.Kube},
+				Info:    info.Info{Kube: info.KubeOpts{Namespace: ns}},
+			}
+			io, out := tcli.NewIOStream()
+			assert.NilError(t, events(ctx, cs, tt.opts, io))
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			assert.Equal(t, len(lines), len(tt.want), out.String())
+			for i, name := range tt.want {
+				if tt.wantOrder {
+					assert.Assert(t, strings.HasSuffix(lines[i], "message of "+name), out.String())
+				} else {
+					assert.Assert(t, strings.Contains(out.String(), "message of "+name+"\n"), out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestEventsAllNamespaces(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{Events: []*corev1.Event{
+		makeEvent("first", "ns1", "repo", "push", "RepositoryNoMatch", corev1.EventTypeNormal, 2),
+		makeEvent("second", "ns2", "repo", "", "RepositoryPipelineRun", corev1.EventTypeWarning, 1),
+	}})
+	cs := &params.Run{
+		Clients: clients.Clients{Kube: stdata.Kube},
+		Info:    info.Info{Kube: info.KubeOpts{Namespace: "ns1"}},
+	}
+	io, out := tcli.NewIOStream()
+	opts := &eventsOpts{}
+	opts.AllNameSpaces = true
+	assert.NilError(t, events(ctx, cs, opts, io))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, len(lines), 2)
+	assert.Assert(t, strings.Contains(lines[0], "ns1/repo push skipped RepositoryNoMatch: message of first"), lines[0])
+	assert.Assert(t, strings.Contains(lines[1], "ns2/repo --- error RepositoryPipelineRun: message of second"), lines[1])
+}
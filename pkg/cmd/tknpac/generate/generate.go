@@ -37,6 +37,8 @@ type Opts struct {
 	FileName                string
 	overwrite               bool
 	language                string
+	template                string
+	templateDir             string
 	generateWithClusterTask bool
 }
 
@@ -97,6 +99,10 @@ func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 		"Wether to overwrite the file if it exist")
 	cmd.PersistentFlags().StringVarP(&gopt.language, "language", "l", "",
 		"Generate for this programming language")
+	cmd.PersistentFlags().StringVarP(&gopt.template, "template", "t", "",
+		"Generate from this template (eg: go, go-image, container), overrides the language detection")
+	cmd.PersistentFlags().StringVar(&gopt.templateDir, "template-dir", "",
+		"A directory of extra yaml templates, they override the embedded templates with the same name")
 	cmd.PersistentFlags().BoolVarP(&gopt.generateWithClusterTask, "use-clustertasks", "", false,
 		"By default we will generate the pipeline using task from hub. If you want to use cluster tasks, set this flag")
 	return cmd
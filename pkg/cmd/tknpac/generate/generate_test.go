@@ -30,6 +30,9 @@ func TestGenerateTemplate(t *testing.T) {
 		checkRegInGeneratedFile []*regexp.Regexp
 		addExtraFilesInRepo     map[string]string
 		regenerateTemplate      bool
+		language                string
+		template                string
+		templateDir             string
 	}{
 		{
 			name: "pull request default",
@@ -72,6 +75,7 @@ func TestGenerateTemplate(t *testing.T) {
 			askStubs: func(as *prompt.AskStubber) {
 				as.StubOneDefault() // pull_request
 				as.StubOne("")      // default as main
+				as.StubOneDefault() // go template
 			},
 			addExtraFilesInRepo: map[string]string{
 				"go.mod": "random string",
@@ -94,6 +98,7 @@ func TestGenerateTemplate(t *testing.T) {
 				// I can't see to make the stubbing work for push :\
 				as.StubOneDefault() // pull_request
 				as.StubOne("")      // default as main
+				as.StubOneDefault() // python template
 			},
 			addExtraFilesInRepo: map[string]string{
 				"setup.py": "random string",
@@ -110,6 +115,141 @@ func TestGenerateTemplate(t *testing.T) {
 			},
 			regenerateTemplate: true,
 		},
+		{
+			name: "pull request golang with image",
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOneDefault()    // pull_request
+				as.StubOne("")         // default as main
+				as.StubOne("go-image") // go template with an image
+			},
+			addExtraFilesInRepo: map[string]string{
+				"go.mod":     "random string",
+				"Dockerfile": "FROM scratch",
+			},
+			checkGeneratedFile: ".tekton/pull-request.yaml",
+			checkRegInGeneratedFile: []*regexp.Regexp{
+				regexp.MustCompile("name: golang-pull-request"),
+				regexp.MustCompile("- name: golang-test"),
+				regexp.MustCompile("- name: golangci-lint"),
+				regexp.MustCompile("name: buildah"),
+			},
+			gitinfo: git.Info{
+				URL: "https://hello/golang",
+			},
+			regenerateTemplate: true,
+		},
+		{
+			name: "pull request python pyproject",
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOneDefault()        // pull_request
+				as.StubOne("")             // default as main
+				as.StubOne("python-image") // python template with an image
+			},
+			addExtraFilesInRepo: map[string]string{
+				"pyproject.toml": "random string",
+			},
+			checkGeneratedFile: ".tekton/pull-request.yaml",
+			checkRegInGeneratedFile: []*regexp.Regexp{
+				regexp.MustCompile("name: pythonrulez-pull-request"),
+				regexp.MustCompile("- name: pytest"),
+				regexp.MustCompile("- name: pylint"),
+				regexp.MustCompile("name: buildah"),
+			},
+			gitinfo: git.Info{
+				URL: "https://hello/pythonrulez",
+			},
+			regenerateTemplate: true,
+		},
+		{
+			name: "pull request container",
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOneDefault() // pull_request
+				as.StubOne("")      // default as main
+			},
+			addExtraFilesInRepo: map[string]string{
+				"Containerfile": "FROM scratch",
+			},
+			checkGeneratedFile: ".tekton/pull-request.yaml",
+			checkRegInGeneratedFile: []*regexp.Regexp{
+				regexp.MustCompile("name: box-pull-request"),
+				regexp.MustCompile("name: buildah"),
+				regexp.MustCompile("value: \\$\\(params.image_name\\)"),
+			},
+			gitinfo: git.Info{
+				URL: "https://hello/box",
+			},
+			regenerateTemplate: true,
+		},
+		{
+			name: "pull request with template flag",
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOneDefault() // pull_request
+				as.StubOne("")      // default as main
+			},
+			addExtraFilesInRepo: map[string]string{
+				"go.mod": "random string",
+			},
+			template:           "nodejs-image",
+			checkGeneratedFile: ".tekton/pull-request.yaml",
+			checkRegInGeneratedFile: []*regexp.Regexp{
+				regexp.MustCompile("name: moto-pull-request"),
+				regexp.MustCompile("- name: run-lint"),
+				regexp.MustCompile("name: buildah"),
+			},
+			gitinfo: git.Info{
+				URL: "https://hello/moto",
+			},
+			regenerateTemplate: true,
+		},
+		{
+			name: "pull request with unknown template",
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOneDefault() // pull_request
+				as.StubOne("")      // default as main
+			},
+			template:   "cobol",
+			wantErrStr: "no template named cobol, available templates are: container, generic, go, go-image",
+			gitinfo: git.Info{
+				URL: "https://hello/moto",
+			},
+			regenerateTemplate: true,
+		},
+		{
+			name: "pull request from template directory",
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOneDefault()     // pull_request
+				as.StubOne("")          // default as main
+				as.StubOne("rust-lint") // rust template
+			},
+			addExtraFilesInRepo: map[string]string{
+				"templates/rust.yaml":      "name: pipelinerun-rust\n",
+				"templates/rust-lint.yaml": "name: pipelinerun-rust-lint\npipelinesascode.tekton.dev/on-event: \"pull_request\"\n",
+			},
+			language:           "rust",
+			templateDir:        "templates",
+			checkGeneratedFile: ".tekton/pull-request.yaml",
+			checkRegInGeneratedFile: []*regexp.Regexp{
+				regexp.MustCompile("name: crab-pull-request"),
+				regexp.MustCompile(".*on-event.*pull_request"),
+			},
+			gitinfo: git.Info{
+				URL: "https://hello/crab",
+			},
+			regenerateTemplate: true,
+		},
+		{
+			name: "pull request with unknown language",
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOneDefault() // pull_request
+				as.StubOne("")      // default as main
+			},
+			language:   "cobol",
+			wantErrStr: "no template available for cobol",
+			gitinfo: git.Info{
+				URL: "https://hello/moto",
+			},
+			regenerateTemplate: true,
+		},
 		{
 			name: "pull request already exist don't regenerate sample template",
 			askStubs: func(as *prompt.AskStubber) {
@@ -151,12 +291,22 @@ func TestGenerateTemplate(t *testing.T) {
 				assert.NilError(t, err, "failed to create file", key)
 			}
 
-			err := Generate(&Opts{
+			opts := &Opts{
 				Event:     &tt.event,
 				GitInfo:   &tt.gitinfo,
 				IOStreams: io,
 				CLIOpts:   &cli.PacCliOpts{},
-			}, tt.regenerateTemplate)
+				language:  tt.language,
+				template:  tt.template,
+			}
+			if tt.templateDir != "" {
+				opts.templateDir = nd.Join(tt.templateDir)
+			}
+			err := Generate(opts, tt.regenerateTemplate)
+			if tt.wantErrStr != "" {
+				assert.ErrorContains(t, err, tt.wantErrStr)
+				return
+			}
 			assert.NilError(t, err)
 
 			// check if file has been generated
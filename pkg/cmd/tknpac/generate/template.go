@@ -4,78 +4,198 @@ import (
 	"bytes"
 	"embed"
 	"fmt"
-	"io"
-	"log"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
 
 type langOpts struct {
-	detectionFile string
+	name           string
+	detectionFiles []string
 }
 
 // I hate this part of the code so much.. but we are waiting for UBI images
 // having >1.6 golang for integrated templates.
-var languageDetection = map[string]langOpts{
-	"go": {
-		detectionFile: "go.mod",
+//
+// The languages are detected in this order, container comes last since a lot
+// of projects of the other languages have a Dockerfile too.
+var languageDetection = []langOpts{
+	{
+		name:           "go",
+		detectionFiles: []string{"go.mod"},
 	},
-	"python": {
-		detectionFile: "setup.py",
+	{
+		name:           "python",
+		detectionFiles: []string{"setup.py", "pyproject.toml", "requirements.txt"},
 	},
-	"nodejs": {
-		detectionFile: "package.json",
+	{
+		name:           "nodejs",
+		detectionFiles: []string{"package.json"},
 	},
-	"java": {
-		detectionFile: "pom.xml",
+	{
+		name:           "java",
+		detectionFiles: []string{"pom.xml"},
+	},
+	{
+		name:           "container",
+		detectionFiles: []string{"Dockerfile", "Containerfile"},
+	},
+	{
+		name: "generic",
 	},
-	"generic": {},
+}
+
+// templateDescriptions describe the embedded templates when we let the user
+// choose between them
+var templateDescriptions = map[string]string{
+	"go":           "lint the project with golangci-lint",
+	"go-image":     "test and lint the project, then build and push its image",
+	"python":       "lint the project with pylint",
+	"python-image": "test the project with pytest and lint it with pylint, then build and push its image",
+	"nodejs":       "test the project with npm",
+	"nodejs-image": "test and lint the project with npm, then build and push its image",
+	"java":         "test the project with maven",
+	"java-image":   "test the project and check its style with maven, then build and push its image",
+	"container":    "build and push the image of the project with buildah",
+	"generic":      "clone the repository and run a simple task",
 }
 
 //go:embed templates
 var resource embed.FS
 
-func (o *Opts) detectLanguage() (string, error) {
-	if o.language != "" {
-		if _, ok := languageDetection[o.language]; !ok {
-			return "", fmt.Errorf("no template available for %s", o.language)
+func (o *Opts) detectLanguage() string {
+	cs := o.IOStreams.ColorScheme()
+	for _, v := range languageDetection {
+		for _, detectionFile := range v.detectionFiles {
+			fpath := filepath.Join(o.GitInfo.TopLevelPath, detectionFile)
+			if _, err := os.Stat(fpath); !os.IsNotExist(err) {
+				fmt.Fprintf(o.IOStreams.Out, "%s We have detected your repository using the programming language %s.\n",
+					cs.SuccessIcon(),
+					cs.Bold(cases.Title(language.Und, cases.NoLower).String(v.name)),
+				)
+				return v.name
+			}
 		}
-		return o.language, nil
 	}
+	return "generic"
+}
 
-	cs := o.IOStreams.ColorScheme()
-	for t, v := range languageDetection {
-		if v.detectionFile == "" {
-			continue
+// loadTemplates returns the templates by their name, the templates of the
+// template directory override the embedded ones with the same name.
+func (o *Opts) loadTemplates() (map[string][]byte, error) {
+	templates := map[string][]byte{}
+	embedded, err := fs.Glob(resource, "templates/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	for _, fpath := range embedded {
+		b, err := resource.ReadFile(fpath)
+		if err != nil {
+			return nil, err
 		}
-		fpath := filepath.Join(o.GitInfo.TopLevelPath, v.detectionFile)
-		if _, err := os.Stat(fpath); !os.IsNotExist(err) {
-			fmt.Fprintf(o.IOStreams.Out, "%s We have detected your repository using the programming language %s.\n",
-				cs.SuccessIcon(),
-				cs.Bold(cases.Title(language.Und, cases.NoLower).String(t)),
-			)
-			return t, nil
+		templates[strings.TrimSuffix(filepath.Base(fpath), ".yaml")] = b
+	}
+
+	if o.templateDir == "" {
+		return templates, nil
+	}
+	custom, err := filepath.Glob(filepath.Join(o.templateDir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(custom) == 0 {
+		return nil, fmt.Errorf("cannot find any yaml template in the template directory %s", o.templateDir)
+	}
+	for _, fpath := range custom {
+		b, err := os.ReadFile(fpath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read template %s: %w", fpath, err)
 		}
+		templates[strings.TrimSuffix(filepath.Base(fpath), ".yaml")] = b
 	}
-	return "generic", nil
+	return templates, nil
 }
 
-func (o *Opts) genTmpl() (*bytes.Buffer, error) {
-	lang, err := o.detectLanguage()
+// templatesForLanguage returns the names of the templates of a language, the
+// template named as the language first and then its variants named
+// <language>-<variant>.
+func templatesForLanguage(templates map[string][]byte, lang string) []string {
+	variants := []string{}
+	for name := range templates {
+		if strings.HasPrefix(name, lang+"-") {
+			variants = append(variants, name)
+		}
+	}
+	sort.Strings(variants)
+	if _, ok := templates[lang]; ok {
+		return append([]string{lang}, variants...)
+	}
+	return variants
+}
+
+// selectTemplate returns the name and the content of the template asked with
+// the --template flag, or of the template for the language asked with the
+// --language flag or detected in the repository. We let the user choose when
+// the language has multiple templates.
+func (o *Opts) selectTemplate() (string, []byte, error) {
+	templates, err := o.loadTemplates()
 	if err != nil {
-		return nil, err
+		return "", nil, err
+	}
+
+	if o.template != "" {
+		tmpl, ok := templates[o.template]
+		if !ok {
+			names := make([]string, 0, len(templates))
+			for name := range templates {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return "", nil, fmt.Errorf("no template named %s, available templates are: %s", o.template, strings.Join(names, ", "))
+		}
+		return o.template, tmpl, nil
+	}
+
+	lang := o.language
+	if lang == "" {
+		lang = o.detectLanguage()
+	}
+	names := templatesForLanguage(templates, lang)
+	if len(names) == 0 {
+		return "", nil, fmt.Errorf("no template available for %s", lang)
 	}
 
-	embedfile, err := resource.Open(fmt.Sprintf("templates/%s.yaml", lang))
+	choice := names[0]
+	if len(names) > 1 {
+		if err := prompt.SurveyAskOne(&survey.Select{
+			Message: "Choose the template of the PipelineRun: ",
+			Options: names,
+			Default: names[0],
+			Description: func(value string, _ int) string {
+				return templateDescriptions[value]
+			},
+		}, &choice); err != nil {
+			return "", nil, err
+		}
+		if _, ok := templates[choice]; !ok {
+			return "", nil, fmt.Errorf("invalid template: %s", choice)
+		}
+	}
+	return choice, templates[choice], nil
+}
+
+func (o *Opts) genTmpl() (*bytes.Buffer, error) {
+	name, tmplB, err := o.selectTemplate()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	defer embedfile.Close()
-	tmplB, _ := io.ReadAll(embedfile)
 
 	prName := filepath.Base(o.GitInfo.URL)
 
@@ -91,7 +211,7 @@ func (o *Opts) genTmpl() (*bytes.Buffer, error) {
 	tmplB = bytes.ReplaceAll(tmplB, []byte("pipelinesascode.tekton.dev/on-target-branch: \"main\""),
 		[]byte(fmt.Sprintf("pipelinesascode.tekton.dev/on-target-branch: \"[%s]\"", o.Event.BaseBranch)))
 
-	tmplB = bytes.ReplaceAll(tmplB, []byte(fmt.Sprintf("name: pipelinerun-%s", lang)),
+	tmplB = bytes.ReplaceAll(tmplB, []byte(fmt.Sprintf("name: pipelinerun-%s", name)),
 		[]byte(fmt.Sprintf("name: %s", prName)))
 
 	return bytes.NewBuffer(tmplB), nil
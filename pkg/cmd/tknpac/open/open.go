@@ -0,0 +1,333 @@
+package open
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/browser"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/sort"
+	"github.com/spf13/cobra"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const longhelp = `
+
+open - open the URLs of a Repository and of its PipelineRuns
+
+tkn pac open will open in your web browser the log of a PipelineRun on the
+console, the check run or the commit on the git provider, the pull request or
+the Repository on the console.
+
+If you don't specify a target it will ask you to choose one, use --print to
+only print the URLs.`
+
+const (
+	namespaceFlag          = "namespace"
+	targetFlag             = "target"
+	printFlag              = "print"
+	useLastPipelineRunFlag = "last"
+)
+
+const (
+	targetLog         = "log"
+	targetChecks      = "checks"
+	targetPullRequest = "pull-request"
+	targetRepository  = "repository"
+)
+
+var targets = []string{targetLog, targetChecks, targetPullRequest, targetRepository}
+
+type openOption struct {
+	cs        *params.Run
+	cw        clockwork.Clock
+	opts      *cli.PacCliOpts
+	ioStreams *cli.IOStreams
+	repoName  string
+	target    string
+	printURL  bool
+	useLastPR bool
+}
+
+// targetURL is an URL we can open with the target it points to
+type targetURL struct {
+	target string
+	url    string
+}
+
+func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "open",
+		Long:  longhelp,
+		Short: "Open the URLs of a Repository and of its PipelineRuns",
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+		ValidArgsFunction: completion.ParentCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			var repoName string
+			opts := cli.NewCliOptions(cmd)
+
+			opts.Namespace, err = cmd.Flags().GetString(namespaceFlag)
+			if err != nil {
+				return err
+			}
+
+			if len(args) > 0 {
+				repoName = args[0]
+			}
+
+			target, err := cmd.Flags().GetString(targetFlag)
+			if err != nil {
+				return err
+			}
+			if target != "" && !isValidTarget(target) {
+				return fmt.Errorf("invalid target %s, it needs to be one of %s", target, strings.Join(targets, ", "))
+			}
+
+			printURL, err := cmd.Flags().GetBool(printFlag)
+			if err != nil {
+				return err
+			}
+
+			useLastPR, err := cmd.Flags().GetBool(useLastPipelineRunFlag)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			err = run.Clients.NewClients(ctx, &run.Info)
+			if err != nil {
+				return err
+			}
+
+			if os.Getenv("PAC_TEKTON_DASHBOARD_URL") != "" {
+				run.Clients.ConsoleUI = &consoleui.TektonDashboard{BaseURL: os.Getenv("PAC_TEKTON_DASHBOARD_URL")}
+			}
+
+			oopts := &openOption{
+				cs:        run,
+				cw:        clockwork.NewRealClock(),
+				opts:      opts,
+				ioStreams: ioStreams,
+				repoName:  repoName,
+				target:    target,
+				printURL:  printURL,
+				useLastPR: useLastPR,
+			}
+			return open(ctx, oopts)
+		},
+	}
+
+	cmd.Flags().StringP(
+		namespaceFlag, "n", "", "If present, the namespace scope for this CLI request")
+	_ = cmd.RegisterFlagCompletionFunc(namespaceFlag,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion(namespaceFlag, args)
+		},
+	)
+
+	cmd.Flags().StringP(
+		targetFlag, "t", "", fmt.Sprintf("The URL to open, one of %s", strings.Join(targets, ", ")))
+	_ = cmd.RegisterFlagCompletionFunc(targetFlag,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return targets, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+
+	cmd.Flags().BoolP(
+		printFlag, "p", false, "Print the URLs instead of opening the web browser")
+
+	cmd.Flags().BoolP(
+		useLastPipelineRunFlag, "L", false, "use the last PipelineRun of the Repository")
+
+	return cmd
+}
+
+func isValidTarget(target string) bool {
+	for _, t := range targets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+// selectPipelineRun returns the PipelineRun of the repository the user has
+// selected or nil if there is none
+func selectPipelineRun(ctx context.Context, oo *openOption, repoName string) (*tektonv1beta1.PipelineRun, error) {
+	opts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", keys.Repository, repoName),
+	}
+	runs, err := oo.cs.Clients.Tekton.TektonV1beta1().PipelineRuns(oo.cs.Info.Kube.Namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs.Items) == 0 {
+		return nil, nil
+	}
+	sort.PipelineRunSortByStartTime(runs.Items)
+	if oo.useLastPR || len(runs.Items) == 1 {
+		return &runs.Items[0], nil
+	}
+
+	options := []string{}
+	for _, run := range runs.Items {
+		label := "running since"
+		date := run.Status.StartTime
+		if run.Status.CompletionTime != nil {
+			label = "completed"
+			date = run.Status.CompletionTime
+		}
+		options = append(options, fmt.Sprintf("%s %s %s", run.GetName(), label, formatting.Age(date, oo.cw)))
+	}
+	var replyString string
+	if err := prompt.SurveyAskOne(&survey.Select{
+		Message: "Select a PipelineRun",
+		Options: options,
+	}, &replyString); err != nil {
+		return nil, err
+	}
+	replyName := strings.Fields(replyString)[0]
+	for i := range runs.Items {
+		if runs.Items[i].GetName() == replyName {
+			return &runs.Items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("cannot find the PipelineRun %s", replyName)
+}
+
+// pullRequestURL returns the URL of the pull request on the git provider, the
+// path differs on each provider
+func pullRequestURL(provider, repoURL, number string) string {
+	switch provider {
+	case "github", "github-enterprise", "gitea", "forgejo":
+		return fmt.Sprintf("%s/pull/%s", repoURL, number)
+	case "gitlab":
+		return fmt.Sprintf("%s/-/merge_requests/%s", repoURL, number)
+	case "bitbucket-cloud", "bitbucket-server":
+		return fmt.Sprintf("%s/pull-requests/%s", repoURL, number)
+	}
+	return ""
+}
+
+// getURLs returns the URLs we can open for the repository and the
+// PipelineRun, the PipelineRun can be nil when there is none
+func getURLs(cs *params.Run, repo *v1alpha1.Repository, pr *tektonv1beta1.PipelineRun) []targetURL {
+	urls := []targetURL{}
+	if pr != nil {
+		repoURL := strings.TrimSuffix(pr.GetAnnotations()[keys.RepoURL], "/")
+		if repoURL == "" {
+			repoURL = strings.TrimSuffix(repo.Spec.URL, "/")
+		}
+		provider := pr.GetLabels()[keys.GitProvider]
+
+		urls = append(urls, targetURL{target: targetLog, url: cs.Clients.ConsoleUI.DetailURL(pr.GetNamespace(), pr.GetName())})
+
+		// only GitHub apps have check runs, the other providers show the
+		// statuses on the commit
+		if checkRunID := pr.GetLabels()[keys.CheckRunID]; checkRunID != "" && strings.HasPrefix(provider, "github") {
+			urls = append(urls, targetURL{target: targetChecks, url: fmt.Sprintf("%s/runs/%s", repoURL, checkRunID)})
+		} else if shaURL := pr.GetAnnotations()[keys.ShaURL]; shaURL != "" {
+			urls = append(urls, targetURL{target: targetChecks, url: shaURL})
+		}
+
+		if number := pr.GetLabels()[keys.PullRequest]; number != "" {
+			if prURL := pullRequestURL(provider, repoURL, number); prURL != "" {
+				urls = append(urls, targetURL{target: targetPullRequest, url: prURL})
+			}
+		}
+	}
+	urls = append(urls, targetURL{target: targetRepository, url: cs.Clients.ConsoleUI.RepositoryURL(repo.GetNamespace(), repo.GetName())})
+	return urls
+}
+
+func open(ctx context.Context, oo *openOption) error {
+	var repository *v1alpha1.Repository
+	var err error
+
+	if oo.opts.Namespace != "" {
+		oo.cs.Info.Kube.Namespace = oo.opts.Namespace
+	}
+
+	if oo.repoName != "" {
+		repository, err = oo.cs.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(oo.cs.Info.Kube.Namespace).Get(ctx,
+			oo.repoName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+	} else {
+		repository, err = prompt.SelectRepo(ctx, oo.cs, oo.cs.Info.Kube.Namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	var pr *tektonv1beta1.PipelineRun
+	// the Repository on the console is the only target without a PipelineRun
+	if oo.target != targetRepository {
+		if pr, err = selectPipelineRun(ctx, oo, repository.GetName()); err != nil {
+			return err
+		}
+	}
+
+	urls := getURLs(oo.cs, repository, pr)
+	if oo.target != "" {
+		found := false
+		for _, u := range urls {
+			if u.target == oo.target {
+				urls = []targetURL{u}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("cannot find the %s URL of repository: %s", oo.target, repository.GetName())
+		}
+	}
+
+	if oo.printURL {
+		for _, u := range urls {
+			if len(urls) == 1 {
+				fmt.Fprintln(oo.ioStreams.Out, u.url)
+				continue
+			}
+			fmt.Fprintf(oo.ioStreams.Out, "%s: %s\n", u.target, u.url)
+		}
+		return nil
+	}
+
+	if len(urls) == 1 {
+		return browser.OpenWebBrowser(urls[0].url)
+	}
+	options := []string{}
+	for _, u := range urls {
+		options = append(options, u.target)
+	}
+	var replyString string
+	if err := prompt.SurveyAskOne(&survey.Select{
+		Message: "Select the URL to open",
+		Options: options,
+	}, &replyString); err != nil {
+		return err
+	}
+	for _, u := range urls {
+		if u.target == replyString {
+			return browser.OpenWebBrowser(u.url)
+		}
+	}
+	return nil
+}
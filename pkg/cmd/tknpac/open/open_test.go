@@ -0,0 +1,148 @@
+package open
+
+import (
+	"testing"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tcli "github.com/openshift-pipelines/pipelines-as-code/pkg/test/cli"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektontest "github.com/openshift-pipelines/pipelines-as-code/pkg/test/tekton"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestOpen(t *testing.T) {
+	cw := clockwork.NewFakeClock()
+	ns := "ns"
+	completed := tektonv1beta1.PipelineRunReasonCompleted.String()
+
+	makePR := func(provider string, labels map[string]string) *tektonv1beta1.PipelineRun {
+		labels[keys.Repository] = "test"
+		labels[keys.GitProvider] = provider
+		pr := tektontest.MakePRCompletion(cw, "test-pipeline", ns, completed, labels, 30)
+		pr.Annotations = map[string]string{
+			keys.RepoURL: "https://forge/org/repo",
+			keys.ShaURL:  "https://forge/org/repo/commit/sha",
+		}
+		return pr
+	}
+
+	tests := []struct {
+		name    string
+		target  string
+		pruns   []*tektonv1beta1.PipelineRun
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "github check run",
+			target: targetChecks,
+			pruns:  []*tektonv1beta1.PipelineRun{makePR("github", map[string]string{keys.CheckRunID: "1234"})},
+			want:   "https://forge/org/repo/runs/1234\n",
+		},
+		{
+			name:   "commit statuses",
+			target: targetChecks,
+			pruns:  []*tektonv1beta1.PipelineRun{makePR("gitlab", map[string]string{})},
+			want:   "https://forge/org/repo/commit/sha\n",
+		},
+		{
+			name:   "gitlab merge request",
+			target: targetPullRequest,
+			pruns:  []*tektonv1beta1.PipelineRun{makePR("gitlab", map[string]string{keys.PullRequest: "6"})},
+			want:   "https://forge/org/repo/-/merge_requests/6\n",
+		},
+		{
+			name:   "log",
+			target: targetLog,
+			pruns:  []*tektonv1beta1.PipelineRun{makePR("github", map[string]string{})},
+			want:   "https://dashboard/#/namespaces/ns/pipelineruns/test-pipeline\n",
+		},
+		{
+			name:   "repository without pipelinerun",
+			target: targetRepository,
+			want:   "https://dashboard/#/pipelinesascode.tekton.dev/v1alpha1/namespaces/ns/repositories/test\n",
+		},
+		{
+			name:  "all urls",
+			pruns: []*tektonv1beta1.PipelineRun{makePR("github", map[string]string{keys.PullRequest: "6"})},
+			want: `log: https://dashboard/#/namespaces/ns/pipelineruns/test-pipeline
+checks: https://forge/org/repo/commit/sha
+pull-request: https://forge/org/repo/pull/6
+repository: https://dashboard/#/pipelinesascode.tekton.dev/v1alpha1/namespaces/ns/repositories/test
+`,
+		},
+		{
+			name:    "no pull request on a push",
+			target:  targetPullRequest,
+			pruns:   []*tektonv1beta1.PipelineRun{makePR("github", map[string]string{})},
+			wantErr: "cannot find the pull-request URL of repository: test",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tdata := testclient.Data{
+				Namespaces: []*corev1.Namespace{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: ns,
+						},
+					},
+				},
+				PipelineRuns: tt.pruns,
+				Repositories: []*v1alpha1.Repository{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "test",
+							Namespace: ns,
+						},
+						Spec: v1alpha1.RepositorySpec{
+							URL: "https://forge/org/repo",
+						},
+					},
+				},
+			}
+
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+			cs := &params.Run{
+				Clients: clients.Clients{
+					PipelineAsCode: stdata.PipelineAsCode,
+					Tekton:         stdata.Pipeline,
+					ConsoleUI:      &consoleui.TektonDashboard{BaseURL: "https://dashboard"},
+				},
+				Info: info.Info{Kube: info.KubeOpts{Namespace: ns}},
+			}
+
+			io, out := tcli.NewIOStream()
+			oopts := &openOption{
+				cs:        cs,
+				cw:        cw,
+				opts:      &cli.PacCliOpts{Namespace: ns},
+				ioStreams: io,
+				repoName:  "test",
+				target:    tt.target,
+				printURL:  true,
+				useLastPR: true,
+			}
+
+			err := open(ctx, oopts)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, out.String(), tt.want)
+		})
+	}
+}
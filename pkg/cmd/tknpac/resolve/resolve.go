@@ -5,18 +5,20 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/resolve"
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/templates"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"sigs.k8s.io/yaml"
@@ -28,7 +30,12 @@ var (
 	skipInlining   []string
 	noGenerateName bool
 	remoteTask     bool
+	inlineBundles  bool
+	vendor         bool
 	noSecret       bool
+	explain        bool
+	runOnCluster   bool
+	runTimeout     time.Duration
 	providerToken  string
 	output         string
 )
@@ -57,12 +64,33 @@ or a directory where it will get all the files ending by .yaml  :
 
 %s pac resolve -f .tekton/
 
+The remote tasks and pipelines referenced in the annotations are fetched from
+the hub, from URLs or from the local filesystem. With the --inline-bundles
+flag the tasks and pipelines referenced from Tekton OCI bundles are fetched
+and inlined as well. With the --vendor flag all the fetched tasks and pipelines
+are added to the output as their own documents:
+
+%s pac resolve -f .tekton/pull-request.yaml --inline-bundles --vendor
+
+With the --explain flag the file each PipelineRun comes from, the events it is
+matched on and the remote tasks and pipelines fetched for it are printed on
+the standard error:
+
+%s pac resolve -f .tekton/ --explain
+
+With the --run flag the resolved PipelineRuns are created on the cluster of the
+current kubeconfig, with the git-auth secret made from the credentials of git
+for the repository, and watched until they are finished or for as long as the
+--run-timeout flag:
+
+%s pac resolve -f .tekton/pull-request.yaml --run
+
 If it detect a {{ git_auth_secret }} in the template it will ask you if you want
 to provide a token. You can set the environment variable PAC_PROVIDER_TOKEN to
 not have to ask about it.
 
 *It does not support task from local directory referenced in annotations at the
- moment*.`, settings.TknBinaryName, settings.TknBinaryName, settings.TknBinaryName)
+ moment*.`, settings.TknBinaryName, settings.TknBinaryName, settings.TknBinaryName, settings.TknBinaryName, settings.TknBinaryName, settings.TknBinaryName)
 
 func Command(run *params.Run, streams *cli.IOStreams) *cobra.Command {
 	cmd := &cobra.Command{
@@ -84,6 +112,9 @@ func Command(run *params.Run, streams *cli.IOStreams) *cobra.Command {
 			run.Clients.Log = zaplog.Sugar()
 
 			if errc != nil {
+				if runOnCluster {
+					return errc
+				}
 				// this check allows resolve to be run without
 				// a kubeconfig so users can verify the tkn version
 				noConfigErr := strings.Contains(errc.Error(), "Couldn't get kubeConfiguration namespace")
@@ -99,6 +130,10 @@ func Command(run *params.Run, streams *cli.IOStreams) *cobra.Command {
 				return fmt.Errorf("you need to at least specify a file with -f")
 			}
 
+			if runOnCluster && (output != "" || vendor) {
+				return fmt.Errorf("the --run flag cannot be used with the --output or --vendor flags")
+			}
+
 			if err := settings.ConfigToSettings(run.Clients.Log, run.Info.Pac.Settings, map[string]string{}); err != nil {
 				return err
 			}
@@ -124,10 +159,39 @@ func Command(run *params.Run, streams *cli.IOStreams) *cobra.Command {
 				mapped["repo_name"] = strings.Split(repoOwner, "/")[1]
 			}
 
-			s, err := resolveFilenames(ctx, run, filenames, mapped)
+			if runOnCluster {
+				// the PipelineRun runs as if the current branch was pushed
+				for _, key := range []string{"source_branch", "target_branch"} {
+					if _, ok := mapped[key]; !ok && gitinfo.Branch != "" {
+						mapped[key] = gitinfo.Branch
+					}
+				}
+				if providerToken == "" && os.Getenv("PAC_PROVIDER_TOKEN") == "" && gitinfo.URL != "" {
+					providerToken = gitCredentialToken(gitinfo.URL)
+				}
+				if unpushed := unpushedFiles(gitinfo); len(unpushed) > 0 {
+					fmt.Fprintf(streams.ErrOut, "⚠️ the changes to %s are not pushed, the PipelineRuns are going to clone the commit %s without them\n",
+						strings.Join(unpushed, ", "), mapped["revision"])
+				}
+			}
+
+			s, explanations, err := resolveFilenames(ctx, run, filenames, mapped)
 			if err != nil {
 				return err
 			}
+			for _, explanation := range explanations {
+				fmt.Fprint(streams.ErrOut, explanation.String())
+			}
+
+			if runOnCluster {
+				// the PipelineRuns are left running on the cluster when we
+				// stop watching them
+				runCtx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+				defer cancel()
+				runCtx, cancelTimeout := context.WithTimeout(runCtx, runTimeout)
+				defer cancelTimeout()
+				return runResolved(runCtx, run, s, run.Info.Kube.Namespace, streams)
+			}
 
 			if output != "" {
 				fmt.Fprintf(streams.Out, "PipelineRun has been written to %s\n", output)
@@ -162,6 +226,21 @@ func Command(run *params.Run, streams *cli.IOStreams) *cobra.Command {
 	cmd.Flags().BoolVar(&remoteTask, "remoteTask", true,
 		"set this to false to avoid fetching and embed remote tasks")
 
+	cmd.Flags().BoolVar(&inlineBundles, "inline-bundles", false,
+		"fetch and embed the tasks and pipelines referenced from Tekton OCI bundles")
+
+	cmd.Flags().BoolVar(&vendor, "vendor", false,
+		"add the fetched remote tasks and pipelines to the output")
+
+	cmd.Flags().BoolVar(&explain, "explain", false,
+		"print where each PipelineRun comes from and on which events it is matched")
+
+	cmd.Flags().BoolVar(&runOnCluster, "run", false,
+		"create the resolved pipelineruns on the cluster and watch them until they are finished")
+
+	cmd.Flags().DurationVar(&runTimeout, "run-timeout", time.Hour,
+		"how long the pipelineruns created with --run are watched before giving up")
+
 	cmd.Flags().StringVarP(&providerToken, "providerToken", "t", "", "use this token to generate the git-auth secret,\n you can set the environment PAC_PROVIDER_TOKEN to have this set automatically")
 	err := run.Info.Pac.AddFlags(cmd)
 	if err != nil {
@@ -180,20 +259,19 @@ func splitArgsInMap(args []string) map[string]string {
 	return m
 }
 
-func resolveFilenames(ctx context.Context, cs *params.Run, filenames []string, params map[string]string) (string, error) {
+func resolveFilenames(ctx context.Context, cs *params.Run, filenames []string, params map[string]string) (string, []resolve.Explanation, error) {
 	var ret string
 
-	ropt := &resolve.Opts{
-		GenerateName:  !noGenerateName,
-		RemoteTasks:   remoteTask,
-		SkipInlining:  skipInlining,
-		ProviderToken: providerToken,
+	// each file is resolved on its own to know where the PipelineRuns come
+	// from when explaining them
+	files := map[string]string{"templates": enumerateFiles(filenames)}
+	if explain {
+		files = enumerateFilesByName(filenames)
 	}
-	allTemplates := enumerateFiles(filenames)
 	if !noSecret {
-		outSecret, secretName, err := makeGitAuthSecret(ctx, cs, filenames, ropt.ProviderToken, params)
+		outSecret, secretName, err := makeGitAuthSecret(ctx, cs, filenames, providerToken, params)
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
 		if secretName != "" {
 			params["git_auth_secret"] = secretName
@@ -201,24 +279,58 @@ func resolveFilenames(ctx context.Context, cs *params.Run, filenames []string, p
 		ret += outSecret
 	}
 
-	// TODO: flags
-	allTemplates = templates.ReplacePlaceHoldersVariables(allTemplates, params)
-	// We use github here but since we don't do remotetask we would not care
-	providerintf := github.New()
-	event := info.NewEvent()
-	prun, err := resolve.Resolve(ctx, cs, cs.Clients.Log, providerintf, event, allTemplates, ropt)
+	input := resolve.Input{
+		Files:         files,
+		Params:        params,
+		GenerateName:  !noGenerateName,
+		SkipInlining:  skipInlining,
+		InlineBundles: inlineBundles,
+		Explain:       explain,
+	}
+	var vendoring *vendoringFetcher
+	if remoteTask {
+		// the remote tasks are fetched from the hub or their url, the github
+		// provider is only asked for the files of the repository of the event
+		// and there is no event here
+		remote := matcher.RemoteTasks{
+			Run:               cs,
+			Event:             info.NewEvent(),
+			ProviderInterface: github.New(),
+			Logger:            cs.Clients.Log,
+		}
+		input.Fetcher = remote
+		if vendor {
+			vendoring = &vendoringFetcher{remote: remote}
+			input.Fetcher = vendoring
+		}
+	}
+	output, err := resolve.ResolveFiles(ctx, input)
+	// the diagnostics without a file are the error we return
+	for _, diagnostic := range output.Diagnostics {
+		if diagnostic.File != "" {
+			cs.Clients.Log.Info(diagnostic.Message)
+		}
+	}
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	for _, run := range prun {
+	for _, run := range output.PipelineRuns {
 		d, err := yaml.Marshal(run)
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
 		ret += fmt.Sprintf("---\n%s\n", d)
 	}
-	return ret, nil
+
+	if vendoring != nil {
+		vendored, err := vendoring.vendored()
+		if err != nil {
+			return "", nil, err
+		}
+		ret += vendored
+	}
+	return ret, output.Explanations, nil
 }
 
 func appendYaml(filename string) string {
@@ -235,16 +347,32 @@ func appendYaml(filename string) string {
 
 func enumerateFiles(filenames []string) string {
 	var yamlDoc string
+	walkFiles(filenames, func(path string) {
+		yamlDoc += appendYaml(path)
+	})
+	return yamlDoc
+}
+
+// enumerateFilesByName returns the content of the yaml files by their path
+func enumerateFilesByName(filenames []string) map[string]string {
+	files := map[string]string{}
+	walkFiles(filenames, func(path string) {
+		files[path] += appendYaml(path)
+	})
+	return files
+}
+
+func walkFiles(filenames []string, add func(path string)) {
 	for _, paths := range filenames {
 		if stat, err := os.Stat(paths); err == nil && !stat.IsDir() {
-			yamlDoc += appendYaml(paths)
+			add(paths)
 			continue
 		}
 
 		// walk dir getting all yamls
 		err := filepath.Walk(paths, func(path string, fi os.FileInfo, err error) error {
 			if filepath.Ext(path) == ".yaml" {
-				yamlDoc += appendYaml(path)
+				add(path)
 			}
 			return nil
 		})
@@ -252,6 +380,4 @@ func enumerateFiles(filenames []string) string {
 			log.Fatalf("Error enumerating files: %v", err)
 		}
 	}
-
-	return yamlDoc
 }
@@ -3,6 +3,8 @@ package resolve
 import (
 	"bytes"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -101,7 +103,7 @@ func TestResolveFilenames(t *testing.T) {
 				assertfs.WithFile("file.yaml", strings.ReplaceAll(tt.tmpl, "\t", "    ")))
 			defer dir.Remove()
 			ctx, _ := rtesting.SetupFakeContext(t)
-			got, err := resolveFilenames(ctx, cs, []string{dir.Path()}, map[string]string{"foo": "bar"})
+			got, _, err := resolveFilenames(ctx, cs, []string{dir.Path()}, map[string]string{"foo": "bar"})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("resolveFilenames() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -111,3 +113,67 @@ func TestResolveFilenames(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveFilenamesLogDiagnostics(t *testing.T) {
+	observer, logs := zapobserver.New(zap.InfoLevel)
+	fakelogger := zap.New(observer).Sugar()
+	cs := &params.Run{Clients: clients.Clients{Log: fakelogger}}
+
+	tmpl := fmt.Sprintf("---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n---\n%s", tmplSimpleNoPrefix)
+	dir := assertfs.NewDir(t, "test-name",
+		assertfs.WithFile("file.yaml", strings.ReplaceAll(tmpl, "\t", "    ")))
+	defer dir.Remove()
+	ctx, _ := rtesting.SetupFakeContext(t)
+	got, _, err := resolveFilenames(ctx, cs, []string{dir.Path()}, map[string]string{"foo": "bar"})
+	assert.NilError(t, err)
+	assert.Assert(t, got != "")
+	assert.Equal(t, logs.FilterMessage("Skipping document not looking like a tekton resource we can Resolve.").Len(), 1)
+}
+
+func TestResolveFilenamesVendor(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	fakelogger := zap.New(observer).Sugar()
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `---
+apiVersion: tekton.dev/v1beta1
+kind: Task
+metadata:
+  name: remote-task
+spec:
+  steps:
+    - name: step
+      image: remote
+`)
+	}))
+	defer server.Close()
+	cs := &params.Run{Clients: clients.Clients{Log: fakelogger, HTTP: *server.Client()}}
+
+	tmpl := fmt.Sprintf(`---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: test
+  annotations:
+    pipelinesascode.tekton.dev/task: "[%s/task.yaml]"
+spec:
+  pipelineSpec:
+    tasks:
+      - name: task
+        taskRef:
+          name: remote-task
+`, server.URL)
+
+	for _, withVendor := range []bool{false, true} {
+		t.Run(fmt.Sprintf("vendor %v", withVendor), func(t *testing.T) {
+			remoteTask, vendor = true, withVendor
+			defer func() { vendor = false }()
+			dir := assertfs.NewDir(t, "test-name", assertfs.WithFile("file.yaml", tmpl))
+			defer dir.Remove()
+			ctx, _ := rtesting.SetupFakeContext(t)
+			got, _, err := resolveFilenames(ctx, cs, []string{dir.Path()}, map[string]string{})
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(got, "image: remote"))
+			assert.Equal(t, strings.Contains(got, "kind: Task"), withVendor, got)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package resolve
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/yaml"
+)
+
+// pollInterval is how often the PipelineRuns run with --run are checked for
+// their completion
+var pollInterval = 2 * time.Second
+
+var yamlDocSeparatorRe = regexp.MustCompile(`(?m)^---\s*$`)
+
+// decodeResolved decode the secret and the PipelineRuns of the output of the
+// resolver
+func decodeResolved(resolved string) ([]*corev1.Secret, []*tektonv1beta1.PipelineRun, error) {
+	secrets := []*corev1.Secret{}
+	pipelineruns := []*tektonv1beta1.PipelineRun{}
+	for _, doc := range yamlDocSeparatorRe.Split(resolved, -1) {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		typeMeta := metav1.TypeMeta{}
+		if err := yaml.Unmarshal([]byte(doc), &typeMeta); err != nil {
+			return nil, nil, err
+		}
+		switch typeMeta.Kind {
+		case "Secret":
+			secret := &corev1.Secret{}
+			if err := yaml.Unmarshal([]byte(doc), secret); err != nil {
+				return nil, nil, err
+			}
+			secrets = append(secrets, secret)
+		case "PipelineRun":
+			pipelinerun := &tektonv1beta1.PipelineRun{}
+			if err := yaml.Unmarshal([]byte(doc), pipelinerun); err != nil {
+				return nil, nil, err
+			}
+			pipelineruns = append(pipelineruns, pipelinerun)
+		}
+	}
+	return secrets, pipelineruns, nil
+}
+
+// gitCredentialToken ask the git credential helpers for the password of the
+// url, it returns an empty string when git has no credentials for it.
+func gitCredentialToken(repoURL string) string {
+	parsed, err := url.Parse(repoURL)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		return ""
+	}
+	c := exec.Command(gitPath, "credential", "fill")
+	c.Stdin = strings.NewReader(fmt.Sprintf("protocol=%s\nhost=%s\npath=%s\n\n",
+		parsed.Scheme, parsed.Host, strings.TrimPrefix(parsed.Path, "/")))
+	// never let git prompt for the credentials it doesn't have
+	c.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=true")
+	var output bytes.Buffer
+	c.Stdout = &output
+	if err := c.Run(); err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(output.String(), "\n") {
+		if strings.HasPrefix(line, "password=") {
+			return strings.TrimPrefix(line, "password=")
+		}
+	}
+	return ""
+}
+
+// unpushedFiles returns the files changed in the checkout and not pushed to
+// the upstream branch, outside of the .tekton directory which is resolved
+// locally.
+func unpushedFiles(gitinfo *git.Info) []string {
+	if gitinfo.TopLevelPath == "" {
+		return nil
+	}
+	// there is nothing to compare with when there is no upstream branch
+	changed, err := git.ChangedFiles(gitinfo.TopLevelPath, "@{upstream}")
+	if err != nil {
+		return nil
+	}
+	unpushed := []string{}
+	for _, file := range changed {
+		if !strings.HasPrefix(file, ".tekton/") {
+			unpushed = append(unpushed, file)
+		}
+	}
+	return unpushed
+}
+
+// waitForCompletion poll the PipelineRun until it is done and returns it, it
+// gives up when the context is done.
+func waitForCompletion(ctx context.Context, cs *params.Run, pr *tektonv1beta1.PipelineRun) (*tektonv1beta1.PipelineRun, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		current, err := cs.Clients.Tekton.TektonV1beta1().PipelineRuns(pr.GetNamespace()).Get(ctx, pr.GetName(), metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, fmt.Errorf("the pipelinerun %s has been deleted before it finished", pr.GetName())
+			}
+			return nil, err
+		}
+		if current.IsDone() {
+			return current, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("stopped waiting for the pipelinerun %s to finish: %w", pr.GetName(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// runResolved create the secret and the PipelineRuns of the output of the
+// resolver in the namespace and watch the PipelineRuns to their completion,
+// the secret is owned by the first PipelineRun to be deleted with it.
+func runResolved(ctx context.Context, cs *params.Run, resolved, namespace string, streams *cli.IOStreams) error {
+	secrets, pipelineruns, err := decodeResolved(resolved)
+	if err != nil {
+		return err
+	}
+	if len(pipelineruns) == 0 {
+		return fmt.Errorf("there is no pipelinerun to run")
+	}
+
+	for _, secret := range secrets {
+		if _, err := cs.Clients.Kube.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("cannot create the secret %s: %w", secret.GetName(), err)
+		}
+	}
+
+	created := make([]*tektonv1beta1.PipelineRun, 0, len(pipelineruns))
+	for _, pipelinerun := range pipelineruns {
+		pr, err := cs.Clients.Tekton.TektonV1beta1().PipelineRuns(namespace).Create(ctx, pipelinerun, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("cannot create the pipelinerun %s: %w", pipelinerun.GetName()+pipelinerun.GetGenerateName(), err)
+		}
+		fmt.Fprintf(streams.Out, "PipelineRun %s has been created in the namespace %s\n", pr.GetName(), namespace)
+		created = append(created, pr)
+	}
+
+	kinteract := kubeinteraction.Interaction{Run: cs}
+	for _, secret := range secrets {
+		if err := kinteract.UpdateSecretWithOwnerRef(ctx, cs.Clients.Log, namespace, secret.GetName(), created[0]); err != nil {
+			return err
+		}
+	}
+
+	failed := []string{}
+	for _, pr := range created {
+		done, err := waitForCompletion(ctx, cs, pr)
+		if err != nil {
+			return err
+		}
+		condition := done.Status.GetCondition(apis.ConditionSucceeded)
+		fmt.Fprintf(streams.Out, "PipelineRun %s has finished: %s\n", done.GetName(), condition.GetReason())
+		if !condition.IsTrue() {
+			failed = append(failed, done.GetName())
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("the pipelineruns %s have failed", strings.Join(failed, ", "))
+	}
+	return nil
+}
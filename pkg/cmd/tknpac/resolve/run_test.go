@@ -0,0 +1,123 @@
+package resolve
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+const resolvedSecret = `---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: pac-gitauth-abcd
+stringData:
+  git-provider-token: token
+`
+
+func resolvedPipelineRun(name, status string) string {
+	return `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: ` + name + `
+spec:
+  pipelineSpec:
+    tasks:
+      - name: task
+        taskSpec:
+          steps:
+            - name: step
+              image: image
+status:
+  conditions:
+    - type: Succeeded
+      status: "` + status + `"
+      reason: Done
+`
+}
+
+func TestDecodeResolved(t *testing.T) {
+	secrets, pipelineruns, err := decodeResolved(resolvedSecret + resolvedPipelineRun("first", "True") + resolvedPipelineRun("second", "True") + "---\nkind: Task\n")
+	assert.NilError(t, err)
+	assert.Equal(t, len(secrets), 1)
+	assert.Equal(t, secrets[0].GetName(), "pac-gitauth-abcd")
+	assert.Equal(t, len(pipelineruns), 2)
+	assert.Equal(t, pipelineruns[1].GetName(), "second")
+}
+
+func TestRunResolved(t *testing.T) {
+	pollInterval = time.Millisecond
+	tests := []struct {
+		name       string
+		resolved   string
+		timeout    time.Duration
+		wantErr    string
+		wantOut    string
+		wantSecret bool
+	}{
+		{
+			name:       "run with the git auth secret",
+			resolved:   resolvedSecret + resolvedPipelineRun("pr", "True"),
+			wantOut:    "PipelineRun pr has finished: Done",
+			wantSecret: true,
+		},
+		{
+			name:     "failed pipelinerun",
+			resolved: resolvedPipelineRun("pr", "True") + resolvedPipelineRun("failing", "False"),
+			wantErr:  "the pipelineruns failing have failed",
+		},
+		{
+			name:     "pipelinerun not finishing before the timeout",
+			resolved: resolvedPipelineRun("pr", "Unknown"),
+			timeout:  10 * time.Millisecond,
+			wantErr:  "stopped waiting for the pipelinerun pr to finish: context deadline exceeded",
+		},
+		{
+			name:     "no pipelinerun",
+			resolved: resolvedSecret,
+			wantErr:  "there is no pipelinerun to run",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			cs := &params.Run{Clients: clients.Clients{
+				Kube:   stdata.Kube,
+				Tekton: stdata.Pipeline,
+				Log:    zap.New(observer).Sugar(),
+			}}
+			if tt.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			io, _, out, _ := cli.IOTest()
+			err := runResolved(ctx, cs, tt.resolved, "ns", io)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(out.String(), tt.wantOut), out.String())
+			if tt.wantSecret {
+				secret, err := stdata.Kube.CoreV1().Secrets("ns").Get(ctx, "pac-gitauth-abcd", metav1.GetOptions{})
+				assert.NilError(t, err)
+				assert.Equal(t, secret.OwnerReferences[0].Name, "pr")
+			}
+		})
+	}
+}
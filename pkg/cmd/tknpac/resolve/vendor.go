@@ -0,0 +1,99 @@
+package resolve
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+// vendoringFetcher fetch the remote tasks and pipelines and keep them around
+// so we can output them along the resolved PipelineRuns.
+type vendoringFetcher struct {
+	remote    matcher.RemoteTasks
+	tasks     []*tektonv1beta1.Task
+	pipelines []*tektonv1beta1.Pipeline
+}
+
+func (v *vendoringFetcher) GetTaskFromAnnotations(ctx context.Context, annotations map[string]string) ([]*tektonv1beta1.Task, error) {
+	tasks, err := v.remote.GetTaskFromAnnotations(ctx, annotations)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		v.addTask(task)
+	}
+	return tasks, nil
+}
+
+func (v *vendoringFetcher) GetPipelineFromAnnotations(ctx context.Context, annotations map[string]string) ([]*tektonv1beta1.Pipeline, error) {
+	pipelines, err := v.remote.GetPipelineFromAnnotations(ctx, annotations)
+	if err != nil {
+		return nil, err
+	}
+	for _, pipeline := range pipelines {
+		v.addPipeline(pipeline)
+	}
+	return pipelines, nil
+}
+
+func (v *vendoringFetcher) GetTaskFromBundle(ctx context.Context, reference, name string) (*tektonv1beta1.Task, error) {
+	task, err := v.remote.GetTaskFromBundle(ctx, reference, name)
+	if err != nil {
+		return nil, err
+	}
+	v.addTask(task)
+	return task, nil
+}
+
+func (v *vendoringFetcher) GetPipelineFromBundle(ctx context.Context, reference, name string) (*tektonv1beta1.Pipeline, error) {
+	pipeline, err := v.remote.GetPipelineFromBundle(ctx, reference, name)
+	if err != nil {
+		return nil, err
+	}
+	v.addPipeline(pipeline)
+	return pipeline, nil
+}
+
+func (v *vendoringFetcher) addTask(task *tektonv1beta1.Task) {
+	for _, t := range v.tasks {
+		if t.GetName() == task.GetName() {
+			return
+		}
+	}
+	// keep a copy before the resolver inline things into it
+	v.tasks = append(v.tasks, task.DeepCopy())
+}
+
+func (v *vendoringFetcher) addPipeline(pipeline *tektonv1beta1.Pipeline) {
+	for _, p := range v.pipelines {
+		if p.GetName() == pipeline.GetName() {
+			return
+		}
+	}
+	v.pipelines = append(v.pipelines, pipeline.DeepCopy())
+}
+
+// vendored output the fetched pipelines and tasks as yaml documents
+func (v *vendoringFetcher) vendored() (string, error) {
+	var ret string
+	for _, pipeline := range v.pipelines {
+		pipeline.APIVersion, pipeline.Kind = tektonv1beta1.SchemeGroupVersion.String(), "Pipeline"
+		d, err := yaml.Marshal(pipeline)
+		if err != nil {
+			return "", err
+		}
+		ret += fmt.Sprintf("---\n%s\n", d)
+	}
+	for _, task := range v.tasks {
+		task.APIVersion, task.Kind = tektonv1beta1.SchemeGroupVersion.String(), "Task"
+		d, err := yaml.Marshal(task)
+		if err != nil {
+			return "", err
+		}
+		ret += fmt.Sprintf("---\n%s\n", d)
+	}
+	return ret, nil
+}
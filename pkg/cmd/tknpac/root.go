@@ -7,10 +7,14 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/create"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/deleterepo"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/describe"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/events"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/generate"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/list"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/logs"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/open"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/resolve"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/run"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/simulate"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/version"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/webhook"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
@@ -37,7 +41,11 @@ func Root(clients *params.Run) *cobra.Command {
 	cmd.AddCommand(deleterepo.Root(clients, ioStreams))
 	cmd.AddCommand(describe.Root(clients, ioStreams))
 	cmd.AddCommand(logs.Command(clients, ioStreams))
+	cmd.AddCommand(events.Command(clients, ioStreams))
+	cmd.AddCommand(open.Command(clients, ioStreams))
 	cmd.AddCommand(resolve.Command(clients, ioStreams))
+	cmd.AddCommand(simulate.Command(clients, ioStreams))
+	cmd.AddCommand(run.Command(clients, ioStreams))
 	cmd.AddCommand(completion.Command())
 	cmd.AddCommand(bootstrap.Command(clients, ioStreams))
 	cmd.AddCommand(generate.Command(clients, ioStreams))
@@ -0,0 +1,286 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/spf13/cobra"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultIncomingSecretKey is the key of the secret of an incoming webhook
+// when it has none
+const defaultIncomingSecretKey = "secret"
+
+// pollInterval is how often the PipelineRun created by the controller is
+// looked for
+var pollInterval = 2 * time.Second
+
+var longhelp = fmt.Sprintf(`run - run a PipelineRun of the current git checkout now
+
+Trigger the incoming webhook of the Repository of the current git checkout for
+the current branch, the controller then runs the PipelineRun of the .tekton
+directory with that name on the last commit of the branch pushed to the git
+provider. The PipelineRun is shown when it has been created and its logs are
+followed with %s:
+
+%s pac run pipelinerun-name
+
+The Repository needs an incoming webhook targeting the branch, see the incoming
+webhook documentation. Its secret and the URL of the controller are detected
+from the cluster when not provided.`, settings.TknBinaryName, settings.TknBinaryName)
+
+type runOpts struct {
+	namespace     string
+	repository    string
+	branch        string
+	secret        string
+	controllerURL string
+	pacNamespace  string
+	tknPath       string
+	noLogs        bool
+	timeout       time.Duration
+	gitInfo       *git.Info
+}
+
+func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &runOpts{}
+	cmd := &cobra.Command{
+		Use:          "run PIPELINERUN",
+		Short:        "Run a PipelineRun of the current git checkout now",
+		Long:         longhelp,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			// ignore error, the flags are enough outside of a git checkout
+			opts.gitInfo = git.GetGitInfo(".")
+			if opts.branch == "" {
+				opts.branch = opts.gitInfo.Branch
+			}
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			if opts.namespace == "" {
+				opts.namespace = run.Info.Kube.Namespace
+			}
+			if !opts.noLogs && opts.tknPath == "" {
+				// the logs are only shown when tkn is installed
+				opts.tknPath, _ = exec.LookPath(settings.TknBinaryName)
+			}
+			return runPipelineRun(ctx, run, opts, args[0], ioStreams)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "",
+		"the namespace of the Repository")
+	cmd.Flags().StringVar(&opts.repository, "repository", "",
+		"the name of the Repository, defaults to the Repository of the url of the current git checkout")
+	cmd.Flags().StringVar(&opts.branch, "branch", "",
+		"the branch to run the PipelineRun on, defaults to the branch of the current git checkout")
+	cmd.Flags().StringVar(&opts.secret, "secret", "",
+		"the secret of the incoming webhook, defaults to the secret of the incoming webhook of the Repository")
+	cmd.Flags().StringVar(&opts.controllerURL, "controller-url", "",
+		"the url of the controller, defaults to the url of the Pipelines as Code installation")
+	cmd.Flags().StringVar(&opts.pacNamespace, "pac-namespace", "",
+		"the namespace where Pipelines as Code is installed")
+	cmd.Flags().StringVar(&opts.tknPath, "tkn-path", "",
+		fmt.Sprintf("Path to the %s binary (default to search for it in you $PATH)", settings.TknBinaryName))
+	cmd.Flags().BoolVar(&opts.noLogs, "no-logs", false,
+		"only show the PipelineRun without following its logs")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", time.Minute,
+		"how long to wait for the controller to create the PipelineRun")
+	return cmd
+}
+
+// getRepository returns the Repository of the flag or the one with the url of
+// the git checkout in the namespace.
+func getRepository(ctx context.Context, run *params.Run, opts *runOpts) (*v1alpha1.Repository, error) {
+	if opts.repository != "" {
+		return run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(opts.namespace).Get(ctx, opts.repository, metav1.GetOptions{})
+	}
+	if opts.gitInfo.URL == "" {
+		return nil, fmt.Errorf("cannot detect the url of the current git checkout, use the --repository flag")
+	}
+	repos, err := run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(opts.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	matched := []*v1alpha1.Repository{}
+	for i := range repos.Items {
+		if strings.EqualFold(strings.TrimSuffix(repos.Items[i].Spec.URL, "/"), opts.gitInfo.URL) {
+			matched = append(matched, &repos.Items[i])
+		}
+	}
+	switch len(matched) {
+	case 0:
+		return nil, fmt.Errorf("cannot find a repository for %s in the namespace %s, use the --repository flag", opts.gitInfo.URL, opts.namespace)
+	case 1:
+		return matched[0], nil
+	}
+	return nil, fmt.Errorf("there are %d repositories for %s in the namespace %s, use the --repository flag", len(matched), opts.gitInfo.URL, opts.namespace)
+}
+
+// getIncomingSecret returns the secret of the incoming webhook of the
+// Repository targeting the branch.
+func getIncomingSecret(ctx context.Context, run *params.Run, repo *v1alpha1.Repository, branch string) (string, error) {
+	if repo.Spec.Incomings == nil {
+		return "", fmt.Errorf("the repository %s has no incoming webhook", repo.GetName())
+	}
+	hook := matcher.IncomingWebhookRule(branch, *repo.Spec.Incomings)
+	if hook == nil {
+		return "", fmt.Errorf("the repository %s has no incoming webhook targeting the branch %s", repo.GetName(), branch)
+	}
+	secret, err := run.Clients.Kube.CoreV1().Secrets(repo.GetNamespace()).Get(ctx, hook.Secret.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("cannot get the secret of the incoming webhook: %w", err)
+	}
+	key := hook.Secret.Key
+	if key == "" {
+		key = defaultIncomingSecretKey
+	}
+	return string(secret.Data[key]), nil
+}
+
+// detectControllerURL returns the URL of the controller of the Pipelines as
+// Code installation.
+func detectControllerURL(ctx context.Context, run *params.Run, pacNamespace string) string {
+	installed, ns, err := info.DetectPacInstallation(ctx, pacNamespace, run)
+	if !installed || err != nil {
+		return ""
+	}
+	if pacInfo, err := info.GetPACInfo(ctx, run, ns); err == nil && pacInfo.ControllerURL != "" {
+		return pacInfo.ControllerURL
+	}
+	controllerURL, _ := info.DetectOpenShiftRoute(ctx, run, ns)
+	return controllerURL
+}
+
+func listPipelineRuns(ctx context.Context, run *params.Run, repo *v1alpha1.Repository, name string) ([]tektonv1beta1.PipelineRun, error) {
+	prs, err := run.Clients.Tekton.TektonV1beta1().PipelineRuns(repo.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%s,%s=incoming", keys.Repository, formatting.K8LabelsCleanup(repo.GetName()),
+			keys.OriginalPRName, formatting.K8LabelsCleanup(name), keys.EventType),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prs.Items, nil
+}
+
+// waitForPipelineRun returns the PipelineRun created by the controller, the
+// first one which was not there before the incoming webhook was triggered.
+func waitForPipelineRun(ctx context.Context, run *params.Run, repo *v1alpha1.Repository, name string, before map[string]bool, timeout time.Duration) (*tektonv1beta1.PipelineRun, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		prs, err := listPipelineRuns(ctx, run, repo, name)
+		if err != nil {
+			return nil, err
+		}
+		for i := range prs {
+			if !before[prs[i].GetName()] {
+				return &prs[i], nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("the controller has not created the pipelinerun %s after %s, check the events of the repository %s", name, timeout, repo.GetName())
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+func runPipelineRun(ctx context.Context, run *params.Run, opts *runOpts, name string, ioStreams *cli.IOStreams) error {
+	if opts.branch == "" {
+		return fmt.Errorf("cannot detect the branch of the current git checkout, use the --branch flag")
+	}
+	repo, err := getRepository(ctx, run, opts)
+	if err != nil {
+		return err
+	}
+	if opts.secret == "" {
+		if opts.secret, err = getIncomingSecret(ctx, run, repo, opts.branch); err != nil {
+			return err
+		}
+	}
+	if opts.controllerURL == "" {
+		if opts.controllerURL = detectControllerURL(ctx, run, opts.pacNamespace); opts.controllerURL == "" {
+			return fmt.Errorf("cannot detect the url of the controller, use the --controller-url flag")
+		}
+	}
+
+	existing, err := listPipelineRuns(ctx, run, repo, name)
+	if err != nil {
+		return err
+	}
+	before := map[string]bool{}
+	for _, pr := range existing {
+		before[pr.GetName()] = true
+	}
+
+	query := url.Values{}
+	query.Set("repository", repo.GetName())
+	query.Set("branch", opts.branch)
+	query.Set("pipelinerun", name)
+	query.Set("secret", opts.secret)
+	incomingURL := fmt.Sprintf("%s/incoming?%s", strings.TrimSuffix(opts.controllerURL, "/"), query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, incomingURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := run.Clients.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("the controller has rejected the incoming webhook: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	fmt.Fprintf(ioStreams.Out, "🚀 PipelineRun %s requested on the branch %s of %s\n", name, opts.branch, repo.Spec.URL)
+
+	pr, err := waitForPipelineRun(ctx, run, repo, name, before, opts.timeout)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(ioStreams.Out, "PipelineRun %s has been created in the namespace %s: %s\n",
+		pr.GetName(), pr.GetNamespace(), run.Clients.ConsoleUI.DetailURL(pr.GetNamespace(), pr.GetName()))
+	if sha := pr.GetLabels()[keys.SHA]; sha != "" && opts.gitInfo.SHA != "" && sha != opts.gitInfo.SHA {
+		fmt.Fprintf(ioStreams.ErrOut, "⚠️ the PipelineRun runs on the commit %s, the current git checkout is on the commit %s, push it first to run it\n",
+			formatting.ShortSHA(sha), formatting.ShortSHA(opts.gitInfo.SHA))
+	}
+	if opts.gitInfo.Dirty {
+		fmt.Fprintln(ioStreams.ErrOut, "⚠️ the current git checkout has uncommitted changes, they are not part of the PipelineRun")
+	}
+
+	if opts.noLogs {
+		return nil
+	}
+	if opts.tknPath == "" {
+		fmt.Fprintf(ioStreams.Out, "%s is not installed, follow the logs with: %s pr logs -f -n %s %s\n",
+			settings.TknBinaryName, settings.TknBinaryName, pr.GetNamespace(), pr.GetName())
+		return nil
+	}
+	//nolint: gosec
+	logs := exec.CommandContext(ctx, opts.tknPath, "pr", "logs", "-f", "-n", pr.GetNamespace(), pr.GetName())
+	logs.Stdout = ioStreams.Out
+	logs.Stderr = ioStreams.ErrOut
+	return logs.Run()
+}
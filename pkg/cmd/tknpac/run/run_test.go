@@ -0,0 +1,168 @@
+package run
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func newRepository(name, url string, incomings *[]v1alpha1.Incoming) *v1alpha1.Repository {
+	return &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+		Spec:       v1alpha1.RepositorySpec{URL: url, Incomings: incomings},
+	}
+}
+
+func newPipelineRun(name, sha string) *tektonv1beta1.PipelineRun {
+	return &tektonv1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns",
+			Labels: map[string]string{
+				keys.Repository:     "repo",
+				keys.OriginalPRName: "build",
+				keys.EventType:      "incoming",
+				keys.SHA:            sha,
+			},
+		},
+	}
+}
+
+func TestRunPipelineRun(t *testing.T) {
+	pollInterval = time.Millisecond
+	incomings := &[]v1alpha1.Incoming{{
+		Type:    "webhook-url",
+		Targets: []string{"main"},
+		Secret:  v1alpha1.Secret{Name: "incoming"},
+	}}
+	tests := []struct {
+		name       string
+		repos      []*v1alpha1.Repository
+		opts       *runOpts
+		status     int
+		wantErr    string
+		wantOut    string
+		wantErrOut string
+	}{
+		{
+			name:    "run the pipelinerun of the checkout",
+			repos:   []*v1alpha1.Repository{newRepository("repo", "https://github.com/owner/repo/", incomings)},
+			opts:    &runOpts{branch: "main", gitInfo: &git.Info{URL: "https://github.com/owner/repo", SHA: "abcdef"}},
+			status:  http.StatusAccepted,
+			wantOut: "PipelineRun build-new has been created in the namespace ns",
+		},
+		{
+			name:       "warn about the commits not pushed",
+			repos:      []*v1alpha1.Repository{newRepository("repo", "https://github.com/owner/repo", incomings)},
+			opts:       &runOpts{branch: "main", gitInfo: &git.Info{URL: "https://github.com/owner/repo", SHA: "123456"}},
+			status:     http.StatusAccepted,
+			wantErrOut: "the PipelineRun runs on the commit abcdef, the current git checkout is on the commit 123456",
+		},
+		{
+			name:       "warn about the uncommitted changes",
+			repos:      []*v1alpha1.Repository{newRepository("repo", "https://github.com/owner/repo", incomings)},
+			opts:       &runOpts{branch: "main", gitInfo: &git.Info{URL: "https://github.com/owner/repo", SHA: "abcdef", Dirty: true}},
+			status:     http.StatusAccepted,
+			wantErrOut: "the current git checkout has uncommitted changes",
+		},
+		{
+			name:    "no incoming webhook for the branch",
+			repos:   []*v1alpha1.Repository{newRepository("repo", "https://github.com/owner/repo", incomings)},
+			opts:    &runOpts{branch: "feature", gitInfo: &git.Info{URL: "https://github.com/owner/repo"}},
+			wantErr: "the repository repo has no incoming webhook targeting the branch feature",
+		},
+		{
+			name:    "no repository for the checkout",
+			repos:   []*v1alpha1.Repository{newRepository("repo", "https://github.com/owner/other", incomings)},
+			opts:    &runOpts{branch: "main", gitInfo: &git.Info{URL: "https://github.com/owner/repo"}},
+			wantErr: "cannot find a repository for https://github.com/owner/repo in the namespace ns, use the --repository flag",
+		},
+		{
+			name: "several repositories for the checkout",
+			repos: []*v1alpha1.Repository{
+				newRepository("repo", "https://github.com/owner/repo", incomings),
+				newRepository("other", "https://github.com/owner/repo", incomings),
+			},
+			opts:    &runOpts{branch: "main", gitInfo: &git.Info{URL: "https://github.com/owner/repo"}},
+			wantErr: "there are 2 repositories for https://github.com/owner/repo in the namespace ns, use the --repository flag",
+		},
+		{
+			name:    "rejected by the controller",
+			repos:   []*v1alpha1.Repository{newRepository("repo", "https://github.com/owner/repo", incomings)},
+			opts:    &runOpts{branch: "main", gitInfo: &git.Info{URL: "https://github.com/owner/repo"}},
+			status:  http.StatusBadRequest,
+			wantErr: "the controller has rejected the incoming webhook: 400 Bad Request: cannot find repository repo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Repositories: tt.repos,
+				PipelineRuns: []*tektonv1beta1.PipelineRun{newPipelineRun("build-old", "abcdef")},
+				Secret: []*corev1.Secret{{
+					ObjectMeta: metav1.ObjectMeta{Name: "incoming", Namespace: "ns"},
+					Data:       map[string][]byte{"secret": []byte("shhh")},
+				}},
+			})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.URL.Path, "/incoming")
+				assert.Equal(t, r.URL.Query().Get("secret"), "shhh")
+				assert.Equal(t, r.URL.Query().Get("repository"), "repo")
+				assert.Equal(t, r.URL.Query().Get("pipelinerun"), "build")
+				assert.Equal(t, r.URL.Query().Get("branch"), tt.opts.branch)
+				if tt.status != http.StatusAccepted {
+					w.WriteHeader(tt.status)
+					fmt.Fprint(w, "cannot find repository repo")
+					return
+				}
+				_, err := stdata.Pipeline.TektonV1beta1().PipelineRuns("ns").Create(ctx, newPipelineRun("build-new", "abcdef"), metav1.CreateOptions{})
+				assert.NilError(t, err)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			run := &params.Run{Clients: clients.Clients{
+				PipelineAsCode: stdata.PipelineAsCode,
+				Kube:           stdata.Kube,
+				Tekton:         stdata.Pipeline,
+				ConsoleUI:      consoleui.FallBackConsole{},
+			}}
+			tt.opts.namespace = "ns"
+			tt.opts.controllerURL = server.URL
+			tt.opts.noLogs = true
+			tt.opts.timeout = time.Second
+			io, _, out, errOut := cli.IOTest()
+			err := runPipelineRun(ctx, run, tt.opts, "build", io)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, out.String() != "")
+			if tt.wantOut != "" {
+				assert.Assert(t, strings.Contains(out.String(), tt.wantOut), out.String())
+			}
+			if tt.wantErrOut != "" {
+				assert.Assert(t, strings.Contains(errOut.String(), tt.wantErrOut), errOut.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package simulate
+
+import (
+	"crypto/hmac"
+	//nolint:gosec
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/random"
+)
+
+const (
+	providerGitHub = "github"
+	providerGitLab = "gitlab"
+
+	eventPullRequest = "pull_request"
+	eventPush        = "push"
+	eventComment     = "comment"
+)
+
+var (
+	providers  = []string{providerGitHub, providerGitLab}
+	eventTypes = []string{eventPullRequest, eventPush, eventComment}
+)
+
+// pullRequest is what we need to know about the simulated pull request or push
+type pullRequest struct {
+	repositoryURL string
+	number        int
+	title         string
+	sha           string
+	sourceBranch  string
+	targetBranch  string
+	sender        string
+	comment       string
+	// projectID is the id of the GitLab project
+	projectID int
+	// installationID is the id of the GitHub App installation
+	installationID int64
+}
+
+// simulatedEvent is the payload of a webhook with its headers
+type simulatedEvent struct {
+	headers map[string]string
+	payload []byte
+}
+
+// splitRepositoryURL returns the owner, the name and the host of a
+// repository from its URL, the owner can have multiple levels on GitLab.
+func splitRepositoryURL(repositoryURL string) (string, string, string, error) {
+	parsed, err := url.Parse(repositoryURL)
+	if err != nil {
+		return "", "", "", err
+	}
+	path := strings.TrimSuffix(strings.Trim(parsed.Path, "/"), ".git")
+	idx := strings.LastIndex(path, "/")
+	if parsed.Host == "" || idx <= 0 {
+		return "", "", "", fmt.Errorf("invalid repository url %s, needs to be of format 'https://git.provider/owner/repository'", repositoryURL)
+	}
+	return path[:idx], path[idx+1:], parsed.Host, nil
+}
+
+func buildEvent(providerType, eventType string, pr *pullRequest) (*simulatedEvent, error) {
+	switch providerType {
+	case providerGitHub:
+		return githubEvent(eventType, pr)
+	case providerGitLab:
+		return gitlabEvent(eventType, pr)
+	}
+	return nil, fmt.Errorf("unsupported provider %s, use one of: %s", providerType, strings.Join(providers, ", "))
+}
+
+func githubEvent(eventType string, pr *pullRequest) (*simulatedEvent, error) {
+	owner, name, host, err := splitRepositoryURL(pr.repositoryURL)
+	if err != nil {
+		return nil, err
+	}
+	repo := &github.Repository{
+		Name:          github.String(name),
+		FullName:      github.String(owner + "/" + name),
+		Owner:         &github.User{Login: github.String(owner)},
+		HTMLURL:       github.String(pr.repositoryURL),
+		DefaultBranch: github.String(pr.targetBranch),
+	}
+	sender := &github.User{Login: github.String(pr.sender)}
+	var installation *github.Installation
+	if pr.installationID > 0 {
+		installation = &github.Installation{ID: github.Int64(pr.installationID)}
+	}
+	pullRequestURL := fmt.Sprintf("%s/pull/%d", pr.repositoryURL, pr.number)
+
+	var event interface{}
+	var githubEventType string
+	switch eventType {
+	case eventPullRequest:
+		githubEventType = "pull_request"
+		event = &github.PullRequestEvent{
+			Action: github.String("opened"),
+			Number: github.Int(pr.number),
+			PullRequest: &github.PullRequest{
+				Number:  github.Int(pr.number),
+				Title:   github.String(pr.title),
+				HTMLURL: github.String(pullRequestURL),
+				User:    sender,
+				Head:    &github.PullRequestBranch{Ref: github.String(pr.sourceBranch), SHA: github.String(pr.sha), Repo: repo},
+				Base:    &github.PullRequestBranch{Ref: github.String(pr.targetBranch), Repo: repo},
+			},
+			Repo:         repo,
+			Sender:       sender,
+			Installation: installation,
+		}
+	case eventPush:
+		githubEventType = "push"
+		event = &github.PushEvent{
+			Ref:   github.String("refs/heads/" + pr.targetBranch),
+			After: github.String(pr.sha),
+			HeadCommit: &github.HeadCommit{
+				ID:      github.String(pr.sha),
+				Message: github.String(pr.title),
+				URL:     github.String(fmt.Sprintf("%s/commit/%s", pr.repositoryURL, pr.sha)),
+			},
+			Repo: &github.PushEventRepository{
+				Name:          repo.Name,
+				FullName:      repo.FullName,
+				Owner:         &github.User{Login: github.String(owner)},
+				HTMLURL:       repo.HTMLURL,
+				DefaultBranch: repo.DefaultBranch,
+			},
+			Sender:       sender,
+			Installation: installation,
+		}
+	case eventComment:
+		githubEventType = "issue_comment"
+		event = &github.IssueCommentEvent{
+			Action: github.String("created"),
+			Issue: &github.Issue{
+				Number:           github.Int(pr.number),
+				Title:            github.String(pr.title),
+				State:            github.String("open"),
+				PullRequestLinks: &github.PullRequestLinks{HTMLURL: github.String(pullRequestURL)},
+			},
+			Comment:      &github.IssueComment{Body: github.String(pr.comment), User: sender},
+			Repo:         repo,
+			Sender:       sender,
+			Installation: installation,
+		}
+	default:
+		return nil, fmt.Errorf("unsupported event type %s, use one of: %s", eventType, strings.Join(eventTypes, ", "))
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{
+		"Content-Type":      "application/json",
+		"X-GitHub-Event":    githubEventType,
+		"X-GitHub-Delivery": random.AlphaString(12),
+	}
+	if host != "github.com" {
+		headers["X-GitHub-Enterprise-Host"] = host
+	}
+	return &simulatedEvent{headers: headers, payload: payload}, nil
+}
+
+func gitlabEvent(eventType string, pr *pullRequest) (*simulatedEvent, error) {
+	owner, name, _, err := splitRepositoryURL(pr.repositoryURL)
+	if err != nil {
+		return nil, err
+	}
+	project := map[string]interface{}{
+		"id":                  pr.projectID,
+		"name":                name,
+		"web_url":             pr.repositoryURL,
+		"path_with_namespace": owner + "/" + name,
+		"default_branch":      pr.targetBranch,
+	}
+	user := map[string]interface{}{"username": pr.sender, "name": pr.sender}
+	lastCommit := map[string]interface{}{
+		"id":      pr.sha,
+		"message": pr.title,
+		"url":     fmt.Sprintf("%s/-/commit/%s", pr.repositoryURL, pr.sha),
+	}
+	mergeRequestURL := fmt.Sprintf("%s/-/merge_requests/%d", pr.repositoryURL, pr.number)
+
+	var event map[string]interface{}
+	var gitlabEventType string
+	switch eventType {
+	case eventPullRequest:
+		gitlabEventType = "Merge Request Hook"
+		event = map[string]interface{}{
+			"object_kind": "merge_request",
+			"event_type":  "merge_request",
+			"user":        user,
+			"project":     project,
+			"object_attributes": map[string]interface{}{
+				"iid":               pr.number,
+				"title":             pr.title,
+				"action":            "open",
+				"state":             "opened",
+				"url":               mergeRequestURL,
+				"source_branch":     pr.sourceBranch,
+				"target_branch":     pr.targetBranch,
+				"source_project_id": pr.projectID,
+				"target_project_id": pr.projectID,
+				"last_commit":       lastCommit,
+				"source":            project,
+				"target":            project,
+			},
+		}
+	case eventPush:
+		gitlabEventType = "Push Hook"
+		event = map[string]interface{}{
+			"object_kind":   "push",
+			"event_name":    "push",
+			"ref":           "refs/heads/" + pr.targetBranch,
+			"after":         pr.sha,
+			"checkout_sha":  pr.sha,
+			"user_username": pr.sender,
+			"user_name":     pr.sender,
+			"project_id":    pr.projectID,
+			"project":       project,
+			"commits": []map[string]interface{}{
+				{"id": pr.sha, "title": pr.title, "message": pr.title, "url": lastCommit["url"]},
+			},
+			"total_commits_count": 1,
+		}
+	case eventComment:
+		gitlabEventType = "Note Hook"
+		event = map[string]interface{}{
+			"object_kind": "note",
+			"event_type":  "note",
+			"user":        user,
+			"project":     project,
+			"object_attributes": map[string]interface{}{
+				"note":          pr.comment,
+				"noteable_type": "MergeRequest",
+				"url":           mergeRequestURL,
+			},
+			"merge_request": map[string]interface{}{
+				"iid":               pr.number,
+				"title":             pr.title,
+				"state":             "opened",
+				"source_branch":     pr.sourceBranch,
+				"target_branch":     pr.targetBranch,
+				"source_project_id": pr.projectID,
+				"target_project_id": pr.projectID,
+				"last_commit":       lastCommit,
+			},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported event type %s, use one of: %s", eventType, strings.Join(eventTypes, ", "))
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return &simulatedEvent{
+		headers: map[string]string{
+			"Content-Type":   "application/json",
+			"X-Gitlab-Event": gitlabEventType,
+		},
+		payload: payload,
+	}, nil
+}
+
+// sign adds the headers the controller validates the payload with, GitHub
+// signs the payload with the webhook secret while GitLab sends it as is.
+func (e *simulatedEvent) sign(providerType, webhookSecret string) {
+	if webhookSecret == "" {
+		return
+	}
+	switch providerType {
+	case providerGitHub:
+		mac := hmac.New(sha1.New, []byte(webhookSecret))
+		mac.Write(e.payload)
+		e.headers["X-Hub-Signature"] = "sha1=" + hex.EncodeToString(mac.Sum(nil))
+		mac = hmac.New(sha256.New, []byte(webhookSecret))
+		mac.Write(e.payload)
+		e.headers["X-Hub-Signature-256"] = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	case providerGitLab:
+		e.headers["X-Gitlab-Token"] = webhookSecret
+	}
+}
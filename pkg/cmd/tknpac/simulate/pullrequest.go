@@ -0,0 +1,134 @@
+package simulate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v49/github"
+)
+
+// pullRequestRef is a pull request parsed from its URL with the URL of the
+// API we can get its details from.
+type pullRequestRef struct {
+	provider      string
+	repositoryURL string
+	number        int
+	apiURL        string
+}
+
+// parsePullRequestURL parses the URL of a GitHub pull request, ie:
+// https://github.com/owner/repo/pull/1, or of a GitLab merge request, ie:
+// https://gitlab.com/group/project/-/merge_requests/1.
+func parsePullRequestURL(pullRequestURL string) (*pullRequestRef, error) {
+	parsed, err := url.Parse(pullRequestURL)
+	if err != nil {
+		return nil, err
+	}
+	path := strings.Trim(parsed.Path, "/")
+	base := fmt.Sprintf("%s://%s", parsed.Scheme, parsed.Host)
+
+	if idx := strings.LastIndex(path, "/-/merge_requests/"); idx > 0 {
+		number, err := strconv.Atoi(path[idx+len("/-/merge_requests/"):])
+		if err != nil {
+			return nil, fmt.Errorf("invalid merge request number in %s: %w", pullRequestURL, err)
+		}
+		project := path[:idx]
+		return &pullRequestRef{
+			provider:      providerGitLab,
+			repositoryURL: fmt.Sprintf("%s/%s", base, project),
+			number:        number,
+			apiURL:        fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d", base, url.PathEscape(project), number),
+		}, nil
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) == 4 && parts[2] == "pull" {
+		number, err := strconv.Atoi(parts[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid pull request number in %s: %w", pullRequestURL, err)
+		}
+		apiBase := base + "/api/v3"
+		if parsed.Host == "github.com" {
+			apiBase = "https://api.github.com"
+		}
+		return &pullRequestRef{
+			provider:      providerGitHub,
+			repositoryURL: fmt.Sprintf("%s/%s/%s", base, parts[0], parts[1]),
+			number:        number,
+			apiURL:        fmt.Sprintf("%s/repos/%s/%s/pulls/%d", apiBase, parts[0], parts[1], number),
+		}, nil
+	}
+	return nil, fmt.Errorf("cannot parse %s as a GitHub pull request or a GitLab merge request url", pullRequestURL)
+}
+
+// gitlabMergeRequest are the fields we need from the GitLab API
+type gitlabMergeRequest struct {
+	Title           string `json:"title"`
+	SHA             string `json:"sha"`
+	SourceBranch    string `json:"source_branch"`
+	TargetBranch    string `json:"target_branch"`
+	TargetProjectID int    `json:"target_project_id"`
+	Author          struct {
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+// fetch gets the details of the pull request from the API of the provider,
+// the token is only needed for the private repositories.
+func (ref *pullRequestRef) fetch(ctx context.Context, client *http.Client, token string) (*pullRequest, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ref.apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		if ref.provider == providerGitLab {
+			req.Header.Set("PRIVATE-TOKEN", token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot get the pull request from %s: %s", ref.apiURL, resp.Status)
+	}
+
+	pr := &pullRequest{repositoryURL: ref.repositoryURL, number: ref.number}
+	switch ref.provider {
+	case providerGitLab:
+		mr := &gitlabMergeRequest{}
+		if err := json.Unmarshal(body, mr); err != nil {
+			return nil, err
+		}
+		pr.title = mr.Title
+		pr.sha = mr.SHA
+		pr.sourceBranch = mr.SourceBranch
+		pr.targetBranch = mr.TargetBranch
+		pr.sender = mr.Author.Username
+		pr.projectID = mr.TargetProjectID
+	default:
+		ghpr := &github.PullRequest{}
+		if err := json.Unmarshal(body, ghpr); err != nil {
+			return nil, err
+		}
+		pr.title = ghpr.GetTitle()
+		pr.sha = ghpr.GetHead().GetSHA()
+		pr.sourceBranch = ghpr.GetHead().GetRef()
+		pr.targetBranch = ghpr.GetBase().GetRef()
+		pr.sender = ghpr.GetUser().GetLogin()
+	}
+	return pr, nil
+}
@@ -0,0 +1,144 @@
+package simulate
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+var cmpOpt = cmp.AllowUnexported(pullRequestRef{}, pullRequest{})
+
+func TestParsePullRequestURL(t *testing.T) {
+	tests := []struct {
+		name           string
+		pullRequestURL string
+		want           *pullRequestRef
+		wantErr        string
+	}{
+		{
+			name:           "github",
+			pullRequestURL: "https://github.com/owner/repo/pull/6",
+			want: &pullRequestRef{
+				provider:      providerGitHub,
+				repositoryURL: "https://github.com/owner/repo",
+				number:        6,
+				apiURL:        "https://api.github.com/repos/owner/repo/pulls/6",
+			},
+		},
+		{
+			name:           "github enterprise",
+			pullRequestURL: "https://ghe.company.com/owner/repo/pull/6",
+			want: &pullRequestRef{
+				provider:      providerGitHub,
+				repositoryURL: "https://ghe.company.com/owner/repo",
+				number:        6,
+				apiURL:        "https://ghe.company.com/api/v3/repos/owner/repo/pulls/6",
+			},
+		},
+		{
+			name:           "gitlab",
+			pullRequestURL: "https://gitlab.com/group/subgroup/project/-/merge_requests/6",
+			want: &pullRequestRef{
+				provider:      providerGitLab,
+				repositoryURL: "https://gitlab.com/group/subgroup/project",
+				number:        6,
+				apiURL:        "https://gitlab.com/api/v4/projects/group%2Fsubgroup%2Fproject/merge_requests/6",
+			},
+		},
+		{
+			name:           "bad number",
+			pullRequestURL: "https://github.com/owner/repo/pull/foo",
+			wantErr:        "invalid pull request number",
+		},
+		{
+			name:           "not a pull request",
+			pullRequestURL: "https://github.com/owner/repo",
+			wantErr:        "cannot parse",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePullRequestURL(tt.pullRequestURL)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want, cmpOpt)
+		})
+	}
+}
+
+func TestFetchPullRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		body     string
+		code     int
+		want     pullRequest
+		wantErr  string
+	}{
+		{
+			name:     "github",
+			provider: providerGitHub,
+			code:     http.StatusOK,
+			body: `{"title": "Add a feature", "user": {"login": "contributor"},
+				"head": {"sha": "abcdef", "ref": "feature"}, "base": {"ref": "main"}}`,
+			want: pullRequest{title: "Add a feature", sender: "contributor", sha: "abcdef", sourceBranch: "feature", targetBranch: "main"},
+		},
+		{
+			name:     "gitlab",
+			provider: providerGitLab,
+			code:     http.StatusOK,
+			body: `{"title": "Add a feature", "author": {"username": "contributor"}, "sha": "abcdef",
+				"source_branch": "feature", "target_branch": "main", "target_project_id": 10}`,
+			want: pullRequest{title: "Add a feature", sender: "contributor", sha: "abcdef", sourceBranch: "feature", targetBranch: "main", projectID: 10},
+		},
+		{
+			name:     "not found",
+			provider: providerGitHub,
+			code:     http.StatusNotFound,
+			wantErr:  "cannot get the pull request",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.provider == providerGitLab {
+					assert.Equal(t, r.Header.Get("PRIVATE-TOKEN"), "token")
+				} else {
+					assert.Equal(t, r.Header.Get("Authorization"), "Bearer token")
+				}
+				w.WriteHeader(tt.code)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			ref := &pullRequestRef{provider: tt.provider, repositoryURL: "https://git.provider/owner/repo", number: 6, apiURL: server.URL}
+			got, err := ref.fetch(ctx, server.Client(), "token")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			tt.want.repositoryURL = "https://git.provider/owner/repo"
+			tt.want.number = 6
+			assert.DeepEqual(t, *got, tt.want, cmpOpt)
+		})
+	}
+}
+
+func TestFillFrom(t *testing.T) {
+	pr := &pullRequest{sha: "abcdef", targetBranch: "release"}
+	pr.fillFrom(&pullRequest{sha: "123456", targetBranch: "main", sourceBranch: "feature", number: 6})
+	assert.Equal(t, pr.sha, "abcdef")
+	assert.Equal(t, pr.targetBranch, "release")
+	assert.Equal(t, pr.sourceBranch, "feature")
+	assert.Equal(t, pr.number, 6)
+}
@@ -0,0 +1,259 @@
+package simulate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	secretName       = "pipelines-as-code-secret"
+	webhookSecretKey = "webhook.secret"
+	defaultComment   = "/retest"
+	defaultTitle     = "Simulated event from tkn pac simulate-event"
+)
+
+var longhelp = fmt.Sprintf(`simulate-event - send a simulated webhook event to the controller.
+
+Craft the payload a provider would send for a pull request, a push or a
+comment on a pull request, sign it with the webhook secret and post it to the
+Pipelines as Code controller. The controller then matches and runs the
+PipelineRuns of the repository like it would for a real event, without
+having to touch the repository on the provider.
+
+The details of the event default to the current git checkout, or are fetched
+from a real pull request with the --pull-request-url flag:
+
+%s pac simulate-event --provider github --event-type pull_request \
+		--repository-url https://github.com/owner/repo --sha 1234 \
+		--source-branch feature --target-branch main
+
+%s pac simulate-event --event-type comment --comment /retest \
+		--pull-request-url https://github.com/owner/repo/pull/1
+
+The webhook secret and the URL of the controller are detected from the
+Pipelines as Code installation when not provided. With the --dry-run flag the
+headers and the payload are shown without being sent.`, settings.TknBinaryName, settings.TknBinaryName)
+
+type simulateOpts struct {
+	provider       string
+	eventType      string
+	pullRequestURL string
+	token          string
+	webhookSecret  string
+	controllerURL  string
+	pacNamespace   string
+	dryRun         bool
+	pr             pullRequest
+}
+
+func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &simulateOpts{}
+	cmd := &cobra.Command{
+		Use:          "simulate-event",
+		Short:        "Send a simulated webhook event to the controller",
+		Long:         longhelp,
+		SilenceUsage: true,
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			if opts.pullRequestURL != "" {
+				ref, err := parsePullRequestURL(opts.pullRequestURL)
+				if err != nil {
+					return err
+				}
+				if !cmd.Flags().Changed("provider") {
+					opts.provider = ref.provider
+				}
+				fetched, err := ref.fetch(ctx, &run.Clients.HTTP, opts.token)
+				if err != nil {
+					return err
+				}
+				opts.pr.fillFrom(fetched)
+			}
+			// ignore error, we may not be in a git checkout
+			gitinfo := git.GetGitInfo(".")
+			opts.pr.fillFrom(&pullRequest{repositoryURL: gitinfo.URL, sha: gitinfo.SHA, sourceBranch: gitinfo.Branch})
+
+			if !opts.dryRun && (opts.webhookSecret == "" || opts.controllerURL == "") {
+				// it's OK if we cannot reach the cluster as long as the
+				// flags have been provided
+				if err := run.Clients.NewClients(ctx, &run.Info); err == nil {
+					detectInstallation(ctx, run, opts)
+				}
+			}
+			return simulate(ctx, run, opts, ioStreams)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.provider, "provider", providerGitHub,
+		fmt.Sprintf("the provider sending the event: %s", strings.Join(providers, ", ")))
+	cmd.Flags().StringVar(&opts.eventType, "event-type", eventPullRequest,
+		fmt.Sprintf("the event to send: %s", strings.Join(eventTypes, ", ")))
+	cmd.Flags().StringVar(&opts.pullRequestURL, "pull-request-url", "",
+		"get the details of the event from this pull request or merge request")
+	cmd.Flags().StringVar(&opts.token, "token", "",
+		"the token to get the details of the pull request of a private repository")
+	cmd.Flags().StringVar(&opts.pr.repositoryURL, "repository-url", "",
+		"the url of the repository, defaults to the current git checkout")
+	cmd.Flags().StringVar(&opts.pr.sha, "sha", "",
+		"the sha of the commit, defaults to the current git checkout")
+	cmd.Flags().StringVar(&opts.pr.sourceBranch, "source-branch", "",
+		"the branch of the pull request, defaults to the current git checkout")
+	cmd.Flags().StringVar(&opts.pr.targetBranch, "target-branch", "",
+		"the branch the pull request targets or the branch pushed to, defaults to main")
+	cmd.Flags().IntVar(&opts.pr.number, "pull-request-number", 0,
+		"the number of the pull request, defaults to 1")
+	cmd.Flags().StringVar(&opts.pr.title, "title", "",
+		"the title of the pull request or the message of the commit")
+	cmd.Flags().StringVar(&opts.pr.sender, "sender", "",
+		"the user sending the event, defaults to the owner of the repository")
+	cmd.Flags().StringVar(&opts.pr.comment, "comment", defaultComment,
+		"the comment of a comment event")
+	cmd.Flags().IntVar(&opts.pr.projectID, "project-id", 0,
+		"the id of the GitLab project")
+	cmd.Flags().Int64Var(&opts.pr.installationID, "installation-id", 0,
+		"the id of the GitHub App installation")
+	cmd.Flags().StringVar(&opts.webhookSecret, "webhook-secret", "",
+		"the secret to sign the payload with, defaults to the secret of the Pipelines as Code installation")
+	cmd.Flags().StringVar(&opts.controllerURL, "controller-url", "",
+		"the url of the controller, defaults to the url of the Pipelines as Code installation")
+	cmd.Flags().StringVar(&opts.pacNamespace, "pac-namespace", "",
+		"the namespace where Pipelines as Code is installed")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false,
+		"show the headers and the payload instead of sending them")
+	return cmd
+}
+
+// fillFrom sets the fields which have not been set from another pull request.
+func (pr *pullRequest) fillFrom(other *pullRequest) {
+	if pr.repositoryURL == "" {
+		pr.repositoryURL = other.repositoryURL
+	}
+	if pr.number == 0 {
+		pr.number = other.number
+	}
+	if pr.title == "" {
+		pr.title = other.title
+	}
+	if pr.sha == "" {
+		pr.sha = other.sha
+	}
+	if pr.sourceBranch == "" {
+		pr.sourceBranch = other.sourceBranch
+	}
+	if pr.targetBranch == "" {
+		pr.targetBranch = other.targetBranch
+	}
+	if pr.sender == "" {
+		pr.sender = other.sender
+	}
+	if pr.projectID == 0 {
+		pr.projectID = other.projectID
+	}
+}
+
+// detectInstallation gets the webhook secret and the URL of the controller
+// which haven't been provided from the Pipelines as Code installation.
+func detectInstallation(ctx context.Context, run *params.Run, opts *simulateOpts) {
+	installed, ns, err := info.DetectPacInstallation(ctx, opts.pacNamespace, run)
+	if !installed || err != nil {
+		return
+	}
+	if opts.webhookSecret == "" {
+		if secret, err := run.Clients.Kube.CoreV1().Secrets(ns).Get(ctx, secretName, metav1.GetOptions{}); err == nil {
+			opts.webhookSecret = string(secret.Data[webhookSecretKey])
+		}
+	}
+	if opts.controllerURL == "" {
+		if pacInfo, err := info.GetPACInfo(ctx, run, ns); err == nil && pacInfo.ControllerURL != "" {
+			opts.controllerURL = pacInfo.ControllerURL
+		} else {
+			opts.controllerURL, _ = info.DetectOpenShiftRoute(ctx, run, ns)
+		}
+	}
+}
+
+func simulate(ctx context.Context, run *params.Run, opts *simulateOpts, ioStreams *cli.IOStreams) error {
+	if opts.pr.repositoryURL == "" {
+		return fmt.Errorf("cannot detect the url of the repository, use the --repository-url flag")
+	}
+	owner, _, _, err := splitRepositoryURL(opts.pr.repositoryURL)
+	if err != nil {
+		return err
+	}
+	opts.pr.fillFrom(&pullRequest{
+		number:       1,
+		title:        defaultTitle,
+		targetBranch: "main",
+		// the owner of the repository is always allowed to run the CI
+		sender: strings.Split(owner, "/")[0],
+	})
+	if opts.pr.sha == "" {
+		return fmt.Errorf("cannot detect the sha of the commit, use the --sha flag")
+	}
+	if opts.eventType != eventPush && opts.pr.sourceBranch == "" {
+		return fmt.Errorf("cannot detect the branch of the pull request, use the --source-branch flag")
+	}
+
+	event, err := buildEvent(opts.provider, opts.eventType, &opts.pr)
+	if err != nil {
+		return err
+	}
+	if opts.webhookSecret == "" {
+		fmt.Fprintf(ioStreams.ErrOut, "⚠️ no webhook secret has been provided or detected, the event is not signed\n")
+	}
+	event.sign(opts.provider, opts.webhookSecret)
+
+	if opts.dryRun {
+		headers := []string{}
+		for k, v := range event.headers {
+			headers = append(headers, fmt.Sprintf("%s: %s", k, v))
+		}
+		sort.Strings(headers)
+		fmt.Fprintf(ioStreams.Out, "%s\n\n%s\n", strings.Join(headers, "\n"), event.payload)
+		return nil
+	}
+
+	if opts.controllerURL == "" {
+		return fmt.Errorf("cannot detect the url of the controller, use the --controller-url flag")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, opts.controllerURL, bytes.NewReader(event.payload))
+	if err != nil {
+		return err
+	}
+	for k, v := range event.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := run.Clients.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(ioStreams.Out, "%s event sent to %s: %s\n", opts.eventType, opts.controllerURL, resp.Status)
+	if len(body) > 0 {
+		fmt.Fprintf(ioStreams.Out, "%s\n", strings.TrimSpace(string(body)))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("the controller has rejected the event: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package simulate
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	ghprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	glprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func testPullRequest(repositoryURL string) *pullRequest {
+	return &pullRequest{
+		repositoryURL: repositoryURL,
+		number:        6,
+		title:         "Add a feature",
+		sha:           "abcdef",
+		sourceBranch:  "feature",
+		targetBranch:  "main",
+		sender:        "owner",
+		comment:       "/retest",
+		projectID:     10,
+	}
+}
+
+func makeRequest(event *simulatedEvent) *http.Request {
+	request := &http.Request{Header: map[string][]string{}}
+	for k, v := range event.headers {
+		request.Header.Set(k, v)
+	}
+	return request
+}
+
+func TestGitHubEvents(t *testing.T) {
+	tests := []struct {
+		name          string
+		eventType     string
+		triggerTarget string
+		headBranch    string
+		baseBranch    string
+	}{
+		{
+			name:          "pull request",
+			eventType:     eventPullRequest,
+			triggerTarget: "pull_request",
+			headBranch:    "feature",
+			baseBranch:    "main",
+		},
+		{
+			name:          "push",
+			eventType:     eventPush,
+			triggerTarget: "push",
+			headBranch:    "refs/heads/main",
+			baseBranch:    "refs/heads/main",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			event, err := buildEvent(providerGitHub, tt.eventType, testPullRequest("https://github.com/owner/repo"))
+			assert.NilError(t, err)
+			event.sign(providerGitHub, "secret")
+
+			run := &params.Run{Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{}}}}
+			provider := &ghprovider.Provider{}
+			got, err := provider.ParsePayload(ctx, run, makeRequest(event), string(event.payload))
+			assert.NilError(t, err)
+			assert.Equal(t, got.TriggerTarget, tt.triggerTarget)
+			assert.Equal(t, got.Organization, "owner")
+			assert.Equal(t, got.Repository, "repo")
+			assert.Equal(t, got.SHA, "abcdef")
+			assert.Equal(t, got.HeadBranch, tt.headBranch)
+			assert.Equal(t, got.BaseBranch, tt.baseBranch)
+
+			got.Request = &info.Request{Header: makeRequest(event).Header, Payload: event.payload}
+			got.Provider.WebhookSecret = "secret"
+			assert.NilError(t, provider.Validate(ctx, run, got))
+			got.Provider.WebhookSecret = "another"
+			assert.Assert(t, provider.Validate(ctx, run, got) != nil)
+		})
+	}
+}
+
+func TestGitHubEnterpriseEvent(t *testing.T) {
+	event, err := buildEvent(providerGitHub, eventComment, testPullRequest("https://ghe.company.com/owner/repo"))
+	assert.NilError(t, err)
+	assert.Equal(t, event.headers["X-GitHub-Event"], "issue_comment")
+	assert.Equal(t, event.headers["X-GitHub-Enterprise-Host"], "ghe.company.com")
+}
+
+func TestGitLabEvents(t *testing.T) {
+	tests := []struct {
+		name          string
+		eventType     string
+		eventName     string
+		triggerTarget string
+		testPipeline  string
+	}{
+		{
+			name:          "merge request",
+			eventType:     eventPullRequest,
+			eventName:     "Merge Request",
+			triggerTarget: "pull_request",
+		},
+		{
+			name:          "push",
+			eventType:     eventPush,
+			eventName:     "Push",
+			triggerTarget: "push",
+		},
+		{
+			name:          "comment",
+			eventType:     eventComment,
+			eventName:     "Note",
+			triggerTarget: "pull_request",
+			testPipeline:  "pr",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			pr := testPullRequest("https://gitlab.com/group/subgroup/project")
+			pr.comment = "/test pr"
+			event, err := buildEvent(providerGitLab, tt.eventType, pr)
+			assert.NilError(t, err)
+			event.sign(providerGitLab, "secret")
+
+			provider := &glprovider.Provider{}
+			got, err := provider.ParsePayload(ctx, &params.Run{}, makeRequest(event), string(event.payload))
+			assert.NilError(t, err)
+			assert.Equal(t, got.EventType, tt.eventName)
+			assert.Equal(t, got.TriggerTarget, tt.triggerTarget)
+			assert.Equal(t, got.Organization, "group-subgroup")
+			assert.Equal(t, got.Repository, "project")
+			assert.Equal(t, got.SHA, "abcdef")
+			assert.Equal(t, got.TargetProjectID, 10)
+			assert.Equal(t, got.TargetTestPipelineRun, tt.testPipeline)
+
+			got.Request = &info.Request{Header: makeRequest(event).Header, Payload: event.payload}
+			got.Provider.WebhookSecret = "secret"
+			assert.NilError(t, provider.Validate(ctx, &params.Run{}, got))
+		})
+	}
+}
+
+func TestBuildEventErrors(t *testing.T) {
+	_, err := buildEvent("bitbucket", eventPush, testPullRequest("https://bitbucket.org/owner/repo"))
+	assert.ErrorContains(t, err, "unsupported provider bitbucket")
+	_, err = buildEvent(providerGitHub, "tag", testPullRequest("https://github.com/owner/repo"))
+	assert.ErrorContains(t, err, "unsupported event type tag")
+	_, err = buildEvent(providerGitLab, eventPush, testPullRequest("https://gitlab.com/project"))
+	assert.ErrorContains(t, err, "invalid repository url")
+}
+
+func TestSimulate(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          *simulateOpts
+		code          int
+		wantErr       string
+		wantOut       string
+		wantErrOut    string
+		wantSignature bool
+	}{
+		{
+			name: "sent",
+			opts: &simulateOpts{
+				provider:      providerGitHub,
+				eventType:     eventPullRequest,
+				webhookSecret: "secret",
+				pr:            pullRequest{repositoryURL: "https://github.com/owner/repo", sha: "abcdef", sourceBranch: "feature"},
+			},
+			code:          http.StatusAccepted,
+			wantOut:       "pull_request event sent to",
+			wantSignature: true,
+		},
+		{
+			name: "unsigned",
+			opts: &simulateOpts{
+				provider:  providerGitHub,
+				eventType: eventPush,
+				pr:        pullRequest{repositoryURL: "https://github.com/owner/repo", sha: "abcdef"},
+			},
+			code:       http.StatusAccepted,
+			wantErrOut: "the event is not signed",
+		},
+		{
+			name: "rejected",
+			opts: &simulateOpts{
+				provider:      providerGitHub,
+				eventType:     eventPullRequest,
+				webhookSecret: "secret",
+				pr:            pullRequest{repositoryURL: "https://github.com/owner/repo", sha: "abcdef", sourceBranch: "feature"},
+			},
+			code:          http.StatusBadRequest,
+			wantErr:       "the controller has rejected the event",
+			wantSignature: true,
+		},
+		{
+			name: "no sha",
+			opts: &simulateOpts{
+				provider:  providerGitHub,
+				eventType: eventPullRequest,
+				pr:        pullRequest{repositoryURL: "https://github.com/owner/repo"},
+			},
+			wantErr: "use the --sha flag",
+		},
+		{
+			name: "dry run",
+			opts: &simulateOpts{
+				provider:      providerGitLab,
+				eventType:     eventPush,
+				webhookSecret: "secret",
+				dryRun:        true,
+				pr:            pullRequest{repositoryURL: "https://gitlab.com/owner/repo", sha: "abcdef"},
+			},
+			wantOut: "X-Gitlab-Event: Push Hook\nX-Gitlab-Token: secret",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				assert.Assert(t, len(body) > 0)
+				assert.Equal(t, r.Header.Get("X-Hub-Signature-256") != "", tt.wantSignature)
+				w.WriteHeader(tt.code)
+				fmt.Fprint(w, "accepted")
+			}))
+			defer server.Close()
+			if !tt.opts.dryRun {
+				tt.opts.controllerURL = server.URL
+			}
+
+			ioStreams, _, out, errOut := cli.IOTest()
+			err := simulate(ctx, &params.Run{}, tt.opts, ioStreams)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, tt.opts.pr.sender == "owner")
+			assert.Assert(t, tt.opts.pr.number == 1)
+			if tt.wantOut != "" {
+				assert.Assert(t, strings.Contains(out.String(), tt.wantOut), out.String())
+			}
+			if tt.wantErrOut != "" {
+				assert.Assert(t, strings.Contains(errOut.String(), tt.wantErrOut), errOut.String())
+			}
+		})
+	}
+}
@@ -3,23 +3,46 @@ package webhook
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/webhook"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/credentials"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	rotateWebhookSecretFlag = "rotate-webhook-secret"
+	gracePeriodFlag         = "grace-period"
+)
+
 func webhookUpdateToken(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	var (
+		pacNamespace        string
+		rotateWebhookSecret bool
+		gracePeriod         time.Duration
+	)
 	cmd := &cobra.Command{
 		Use:     "update-token",
 		Aliases: []string{""},
 		Short:   "Update webhook provider token",
+		Long: `Update the provider token of the Secret of a Repository.
+
+With --rotate-webhook-secret a new webhook secret is generated and set on the
+webhook of the repository on GitHub, GitLab, Gitea or Bitbucket Server and in
+the Secret of the Repository. The webhooks of Bitbucket Cloud have no secret,
+only the token is updated.
+
+The previous webhook secret is kept in the Secret and the payloads signed with
+it are still accepted during the --grace-period, for the webhooks not updated
+yet.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var (
 				err      error
@@ -40,7 +63,7 @@ func webhookUpdateToken(run *params.Run, ioStreams *cli.IOStreams) *cobra.Comman
 			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
 				return err
 			}
-			return update(ctx, opts, run, ioStreams, repoName)
+			return update(ctx, opts, run, ioStreams, repoName, pacNamespace, rotateWebhookSecret, gracePeriod)
 		},
 		Annotations: map[string]string{
 			"commandType": "main",
@@ -52,6 +75,12 @@ func webhookUpdateToken(run *params.Run, ioStreams *cli.IOStreams) *cobra.Comman
 
 	cmd.Flags().StringP(
 		namespaceFlag, "n", "", "If present, the namespace scope for this CLI request")
+	cmd.PersistentFlags().StringVarP(&pacNamespace, "pac-namespace",
+		"", "", "The namespace where pac is installed")
+	cmd.Flags().BoolVar(&rotateWebhookSecret, rotateWebhookSecretFlag, false,
+		"Rotate the webhook secret on the git provider and in the Secret of the Repository too")
+	cmd.Flags().DurationVar(&gracePeriod, gracePeriodFlag, 24*time.Hour,
+		"How long the previous webhook secret is still accepted after its rotation, 0 to stop accepting it right away")
 
 	_ = cmd.RegisterFlagCompletionFunc(namespaceFlag,
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -61,7 +90,7 @@ func webhookUpdateToken(run *params.Run, ioStreams *cli.IOStreams) *cobra.Comman
 	return cmd
 }
 
-func update(ctx context.Context, opts *cli.PacCliOpts, run *params.Run, ioStreams *cli.IOStreams, repoName string) error {
+func update(ctx context.Context, opts *cli.PacCliOpts, run *params.Run, ioStreams *cli.IOStreams, repoName, pacNamespace string, rotateWebhookSecret bool, gracePeriod time.Duration) error {
 	var (
 		err                 error
 		repo                *v1alpha1.Repository
@@ -113,13 +142,107 @@ func update(ctx context.Context, opts *cli.PacCliOpts, run *params.Run, ioStream
 		gitProviderSecretKey = pipelineascode.DefaultGitProviderSecretKey
 	}
 
+	webhookSecret := ""
+	if rotateWebhookSecret {
+		if webhookSecret, err = rotateProviderWebhookSecret(ctx, run, ioStreams, repo, pacNamespace, personalAccessToken); err != nil {
+			return err
+		}
+	}
+
 	secretData.Data[gitProviderSecretKey] = []byte(personalAccessToken)
+	// the webhook secret is usually in the same Secret as the token
+	webhookSecretInData := webhookSecret != "" && repo.Spec.GitProvider.WebhookSecret.Name == secretName
+	if webhookSecretInData {
+		rotateWebhookSecretData(secretData.Data, webhookSecretKey(repo), webhookSecret, gracePeriod, time.Now())
+	}
 	_, err = run.Clients.Kube.CoreV1().Secrets(repo.Namespace).Update(ctx, secretData, metav1.UpdateOptions{})
 	if err != nil {
+		if webhookSecret != "" {
+			return fmt.Errorf("the webhook secret has been rotated on the git provider but the secret %s cannot be updated: %w", secretName, err)
+		}
 		return err
 	}
 
 	fmt.Fprintf(ioStreams.Out, "🔑 Secret %s has been updated with new personal access token in the %s namespace.\n", secretName, repo.Namespace)
+	if webhookSecret == "" {
+		return nil
+	}
+
+	webhookSecretName := repo.Spec.GitProvider.WebhookSecret.Name
+	if !webhookSecretInData {
+		webhookSecretData, err := run.Clients.Kube.CoreV1().Secrets(repo.Namespace).Get(ctx, webhookSecretName, metav1.GetOptions{})
+		if err == nil {
+			rotateWebhookSecretData(webhookSecretData.Data, webhookSecretKey(repo), webhookSecret, gracePeriod, time.Now())
+			_, err = run.Clients.Kube.CoreV1().Secrets(repo.Namespace).Update(ctx, webhookSecretData, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return fmt.Errorf("the webhook secret has been rotated on the git provider but the secret %s cannot be updated: %w", webhookSecretName, err)
+		}
+	}
+	fmt.Fprintf(ioStreams.Out, "🔑 Secret %s has been updated with new webhook secret in the %s namespace.\n", webhookSecretName, repo.Namespace)
 
 	return nil
 }
+
+// rotateProviderWebhookSecret sets a new secret on the webhook of the
+// repository on the git provider, the webhook is found with the new token.
+// An empty secret is returned when the secret cannot be rotated.
+func rotateProviderWebhookSecret(ctx context.Context, run *params.Run, ioStreams *cli.IOStreams, repo *v1alpha1.Repository,
+	pacNamespace, personalAccessToken string,
+) (string, error) {
+	if repo.Spec.GitProvider.WebhookSecret == nil {
+		fmt.Fprintf(ioStreams.Out, "%s Can not rotate the webhook secret when git_provider webhook_secret is empty\n",
+			ioStreams.ColorScheme().WarningIcon())
+		return "", nil
+	}
+
+	providerName := credentials.ProviderType(repo)
+	if providerName == "" {
+		var err error
+		if providerName, err = webhook.GetProviderName(repo.Spec.URL); err != nil {
+			return "", err
+		}
+	}
+
+	config := &webhook.Options{
+		Run:                 run,
+		IOStreams:           ioStreams,
+		PACNamespace:        pacNamespace,
+		RepositoryURL:       repo.Spec.URL,
+		ProviderAPIURL:      repo.Spec.GitProvider.URL,
+		PersonalAccessToken: personalAccessToken,
+		ProviderUser:        repo.Spec.GitProvider.User,
+	}
+	webhookSecret, err := config.RotateWebhookSecret(ctx, providerName)
+	if err != nil {
+		return "", err
+	}
+	if webhookSecret == "" {
+		fmt.Fprintf(ioStreams.Out, "%s The webhooks of %s have no secret, only the token is updated\n",
+			ioStreams.ColorScheme().InfoIcon(), providerName)
+	}
+	return webhookSecret, nil
+}
+
+// rotateWebhookSecretData set the new webhook secret in the data of the
+// Secret, the current one becomes the previous one with the time until when the
+// controller accepts it.
+func rotateWebhookSecretData(data map[string][]byte, key, webhookSecret string, gracePeriod time.Duration, now time.Time) {
+	previousKey := key + pipelineascode.PreviousWebhookSecretSuffix
+	expiryKey := key + pipelineascode.PreviousWebhookSecretExpirySuffix
+	if previous := data[key]; len(previous) > 0 && gracePeriod > 0 {
+		data[previousKey] = previous
+		data[expiryKey] = []byte(now.Add(gracePeriod).UTC().Format(time.RFC3339))
+	} else {
+		delete(data, previousKey)
+		delete(data, expiryKey)
+	}
+	data[key] = []byte(webhookSecret)
+}
+
+func webhookSecretKey(repo *v1alpha1.Repository) string {
+	if repo.Spec.GitProvider.WebhookSecret.Key != "" {
+		return repo.Spec.GitProvider.WebhookSecret.Key
+	}
+	return pipelineascode.DefaultGitProviderWebhookSecretKey
+}
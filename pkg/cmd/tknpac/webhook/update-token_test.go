@@ -2,6 +2,7 @@ package webhook
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
@@ -11,6 +12,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -177,7 +179,7 @@ func TestWebhookUpdateToken(t *testing.T) {
 			}
 			io, out := newIOStream()
 			if err := update(ctx, tt.opts, cs, io,
-				tt.repoName); (err != nil) != tt.wantErr {
+				tt.repoName, "", false, 0); (err != nil) != tt.wantErr {
 				t.Errorf("update() error = %v, wantErr %v", err, tt.wantErr)
 			} else {
 				if res := cmp.Diff(out.String(), tt.wantMsg); res != "" {
@@ -203,3 +205,17 @@ func TestWebhookUpdateToken(t *testing.T) {
 		})
 	}
 }
+
+func TestRotateWebhookSecretData(t *testing.T) {
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	data := map[string][]byte{"webhook.secret": []byte("old")}
+	rotateWebhookSecretData(data, "webhook.secret", "new", time.Hour, now)
+	assert.DeepEqual(t, data, map[string][]byte{
+		"webhook.secret":                 []byte("new"),
+		"webhook.secret.previous":        []byte("old"),
+		"webhook.secret.previous-expiry": []byte("2023-01-02T04:04:05Z"),
+	})
+
+	rotateWebhookSecretData(data, "webhook.secret", "newer", 0, now)
+	assert.DeepEqual(t, data, map[string][]byte{"webhook.secret": []byte("newer")})
+}
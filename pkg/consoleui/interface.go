@@ -12,6 +12,7 @@ const consoleIsnotConfiguredURL = "https://dashboard.is.not.configured"
 type Interface interface {
 	DetailURL(ns, pr string) string
 	TaskLogURL(ns, pr, task string) string
+	RepositoryURL(ns, repo string) string
 	UI(ctx context.Context, kdyn dynamic.Interface) error
 	URL() string
 	GetName() string
@@ -31,6 +32,10 @@ func (f FallBackConsole) TaskLogURL(ns, pr, task string) string {
 	return consoleIsnotConfiguredURL
 }
 
+func (f FallBackConsole) RepositoryURL(ns, repo string) string {
+	return consoleIsnotConfiguredURL
+}
+
 func (f FallBackConsole) UI(ctx context.Context, kdyn dynamic.Interface) error {
 	return nil
 }
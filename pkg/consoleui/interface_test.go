@@ -23,4 +23,5 @@ func TestFallbackConsole(t *testing.T) {
 	assert.Assert(t, fbc.URL() != "")
 	assert.Assert(t, fbc.DetailURL("ns", "pr") != "")
 	assert.Assert(t, fbc.TaskLogURL("ns", "pr", "task") != "")
+	assert.Assert(t, fbc.RepositoryURL("ns", "repo") != "")
 }
@@ -0,0 +1,60 @@
+package consoleui
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"k8s.io/client-go/dynamic"
+)
+
+const logProxyName = "Pipelines as Code log proxy"
+
+// LogProxy links the PipelineRuns to the logs served by the log proxy of the
+// controller. The viewers log in on the git provider with the OAuth app of the
+// log proxy, the logs of a PipelineRun are only served to the users who can
+// read the git repository of its Repository.
+type LogProxy struct {
+	BaseURL string
+	// Key signs the OAuth states and encrypts the sessions of the viewers
+	Key   []byte
+	OAuth LogProxyOAuth
+}
+
+// LogProxyOAuth is the OAuth app of the git provider the viewers of the log
+// proxy log in with.
+type LogProxyOAuth struct {
+	// Provider is the type of the git provider, github, gitlab, gitea or
+	// forgejo
+	Provider string
+	// URL is the URL of the git provider, only the logs of its repositories
+	// are served
+	URL          string
+	ClientID     string
+	ClientSecret string
+}
+
+func (l *LogProxy) GetName() string {
+	return logProxyName
+}
+
+func (l *LogProxy) DetailURL(ns, pr string) string {
+	return fmt.Sprintf("%s/logs/%s/%s", l.BaseURL, ns, pr)
+}
+
+func (l *LogProxy) TaskLogURL(ns, pr, task string) string {
+	return fmt.Sprintf("%s?task=%s", l.DetailURL(ns, pr), url.QueryEscape(task))
+}
+
+// RepositoryURL returns the proxy URL, the Repositories are not served by it.
+func (l *LogProxy) RepositoryURL(ns, repo string) string {
+	return l.BaseURL
+}
+
+func (l *LogProxy) URL() string {
+	return l.BaseURL
+}
+
+func (l *LogProxy) UI(ctx context.Context, kdyn dynamic.Interface) error {
+	return nil
+}
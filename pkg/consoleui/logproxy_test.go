@@ -0,0 +1,15 @@
+package consoleui
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLogProxy(t *testing.T) {
+	lp := &LogProxy{BaseURL: "https://pac.example.com", Key: []byte("secret")}
+	assert.Equal(t, lp.DetailURL("ns", "pr"), "https://pac.example.com/logs/ns/pr")
+	assert.Equal(t, lp.TaskLogURL("ns", "pr", "my task"), "https://pac.example.com/logs/ns/pr?task=my+task")
+	assert.Equal(t, lp.RepositoryURL("ns", "repo"), "https://pac.example.com")
+	assert.Equal(t, lp.URL(), "https://pac.example.com")
+}
@@ -14,6 +14,7 @@ const (
 	openShiftConsoleRouteName      = "console"
 	openShiftPipelineDetailViewURL = "https://%s/k8s/ns/%s/tekton.dev~v1beta1~PipelineRun/%s"
 	openShiftPipelineTaskLogURL    = "%s/logs/%s"
+	openShiftRepositoryURL         = "https://%s/k8s/ns/%s/pipelinesascode.tekton.dev~v1alpha1~Repository/%s"
 	openShiftRouteGroup            = "route.openshift.io"
 	openShiftRouteVersion          = "v1"
 	openShiftRouteResource         = "routes"
@@ -40,6 +41,10 @@ func (o *OpenshiftConsole) TaskLogURL(ns, pr, task string) string {
 	return fmt.Sprintf(openShiftPipelineTaskLogURL, o.DetailURL(ns, pr), task)
 }
 
+func (o *OpenshiftConsole) RepositoryURL(ns, repo string) string {
+	return fmt.Sprintf(openShiftRepositoryURL, o.host, ns, repo)
+}
+
 // UI use dynamic client to get the route of the openshift
 // console where we can point to.
 func (o *OpenshiftConsole) UI(ctx context.Context, kdyn dynamic.Interface) error {
@@ -103,4 +103,5 @@ func TestOpenshiftConsoleURLs(t *testing.T) {
 	assert.Assert(t, o.URL() != "")
 	assert.Assert(t, o.DetailURL("ns", "pr") != "")
 	assert.Assert(t, o.TaskLogURL("ns", "pr", "task") != "")
+	assert.Assert(t, o.RepositoryURL("ns", "repo") != "")
 }
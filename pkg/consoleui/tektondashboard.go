@@ -25,6 +25,10 @@ func (t *TektonDashboard) TaskLogURL(ns, pr, task string) string {
 	return fmt.Sprintf("%s?pipelineTask=%s", t.DetailURL(ns, pr), task)
 }
 
+func (t *TektonDashboard) RepositoryURL(ns, repo string) string {
+	return fmt.Sprintf("%s/#/pipelinesascode.tekton.dev/v1alpha1/namespaces/%s/repositories/%s", t.BaseURL, ns, repo)
+}
+
 func (t *TektonDashboard) URL() string {
 	return t.BaseURL
 }
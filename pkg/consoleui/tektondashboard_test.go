@@ -26,5 +26,6 @@ func TestTektonDashboard(t *testing.T) {
 	assert.NilError(t, tr.UI(ctx, dynClient))
 	assert.Assert(t, strings.Contains(tr.DetailURL("ns", "pr"), "namespaces/ns"))
 	assert.Assert(t, strings.Contains(tr.TaskLogURL("ns", "pr", "task"), "pipelineTask=task"))
+	assert.Assert(t, strings.Contains(tr.RepositoryURL("ns", "repo"), "namespaces/ns/repositories/repo"))
 	assert.Assert(t, strings.Contains(tr.URL(), "test"))
 }
@@ -0,0 +1,23 @@
+package consoleui
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// TektonResultsResultAnnotation is set by Tekton Results on the
+	// PipelineRuns with the name of their result.
+	TektonResultsResultAnnotation = "results.tekton.dev/result"
+	// TektonResultsRecordAnnotation is set by Tekton Results on the
+	// PipelineRuns with the name of their record.
+	TektonResultsRecordAnnotation = "results.tekton.dev/record"
+
+	tektonResultsRecordURL = "%s/apis/results.tekton.dev/v1alpha2/parents/%s"
+)
+
+// TektonResultsRecordURL returns the URL of a record on the API of Tekton
+// Results, the record is named <namespace>/results/<uuid>/records/<uuid>.
+func TektonResultsRecordURL(baseURL, record string) string {
+	return fmt.Sprintf(tektonResultsRecordURL, strings.TrimSuffix(baseURL, "/"), record)
+}
@@ -0,0 +1,13 @@
+package consoleui
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTektonResultsRecordURL(t *testing.T) {
+	want := "https://results.example.com/apis/results.tekton.dev/v1alpha2/parents/ns/results/123/records/456"
+	assert.Equal(t, TektonResultsRecordURL("https://results.example.com", "ns/results/123/records/456"), want)
+	assert.Equal(t, TektonResultsRecordURL("https://results.example.com/", "ns/results/123/records/456"), want)
+}
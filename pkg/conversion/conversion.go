@@ -0,0 +1,51 @@
+package conversion
+
+import (
+	"context"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+//nolint:gochecknoinits
+func init() {
+	_ = tektonv1.AddToScheme(k8scheme.Scheme)
+	_ = tektonv1beta1.AddToScheme(k8scheme.Scheme)
+}
+
+func typeMeta(kind string) metav1.TypeMeta {
+	return metav1.TypeMeta{APIVersion: tektonv1beta1.SchemeGroupVersion.String(), Kind: kind}
+}
+
+// ToV1Beta1 converts the tekton.dev/v1 PipelineRuns, Pipelines and Tasks to
+// tekton.dev/v1beta1 which is the version we are using everywhere else, the
+// other objects are returned as is.
+func ToV1Beta1(ctx context.Context, obj runtime.Object) (runtime.Object, error) {
+	switch o := obj.(type) {
+	case *tektonv1.PipelineRun:
+		pr := &tektonv1beta1.PipelineRun{}
+		if err := pr.ConvertFrom(ctx, o); err != nil {
+			return nil, err
+		}
+		pr.TypeMeta = typeMeta("PipelineRun")
+		return pr, nil
+	case *tektonv1.Pipeline:
+		pipeline := &tektonv1beta1.Pipeline{}
+		if err := pipeline.ConvertFrom(ctx, o); err != nil {
+			return nil, err
+		}
+		pipeline.TypeMeta = typeMeta("Pipeline")
+		return pipeline, nil
+	case *tektonv1.Task:
+		task := &tektonv1beta1.Task{}
+		if err := task.ConvertFrom(ctx, o); err != nil {
+			return nil, err
+		}
+		task.TypeMeta = typeMeta("Task")
+		return task, nil
+	}
+	return obj, nil
+}
@@ -0,0 +1,62 @@
+package conversion
+
+import (
+	"context"
+	"testing"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestToV1Beta1(t *testing.T) {
+	meta := metav1.ObjectMeta{Name: "foo"}
+	tests := []struct {
+		name     string
+		obj      runtime.Object
+		wantKind string
+	}{
+		{
+			name: "pipelinerun",
+			obj: &tektonv1.PipelineRun{
+				ObjectMeta: meta,
+				Spec: tektonv1.PipelineRunSpec{
+					TaskRunTemplate: tektonv1.PipelineTaskRunTemplate{ServiceAccountName: "builder"},
+				},
+			},
+			wantKind: "PipelineRun",
+		},
+		{
+			name:     "pipeline",
+			obj:      &tektonv1.Pipeline{ObjectMeta: meta},
+			wantKind: "Pipeline",
+		},
+		{
+			name:     "task",
+			obj:      &tektonv1.Task{ObjectMeta: meta},
+			wantKind: "Task",
+		},
+		{
+			name: "v1beta1 as is",
+			obj:  &tektonv1beta1.Task{ObjectMeta: meta},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToV1Beta1(context.Background(), tt.obj)
+			assert.NilError(t, err)
+			if tt.wantKind == "" {
+				assert.Equal(t, got, tt.obj)
+				return
+			}
+			assert.Equal(t, got.GetObjectKind().GroupVersionKind().Kind, tt.wantKind)
+			assert.Equal(t, got.GetObjectKind().GroupVersionKind().GroupVersion(), tektonv1beta1.SchemeGroupVersion)
+			if pr, ok := got.(*tektonv1beta1.PipelineRun); ok {
+				assert.Equal(t, pr.Spec.ServiceAccountName, "builder")
+				assert.Equal(t, pr.GetName(), "foo")
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// ConditionWebhookSecret is whether the webhook secret of the Repository
+	// can be read and is not empty
+	ConditionWebhookSecret apis.ConditionType = "WebhookSecretValid"
+	// ConditionToken is whether the token of the Repository is accepted by
+	// the git provider
+	ConditionToken apis.ConditionType = "TokenValid"
+	// ConditionTokenPermissions is whether the token has the permissions
+	// Pipelines as Code needs on the repository of the git provider
+	ConditionTokenPermissions apis.ConditionType = "TokenPermissionsValid"
+	// ConditionWebhook is whether a webhook to the controller is installed on
+	// the repository of the git provider
+	ConditionWebhook apis.ConditionType = "WebhookInstalled"
+
+	defaultTokenKey         = "provider.token"
+	defaultWebhookSecretKey = "webhook.secret"
+
+	providerGitHub         = "github"
+	providerGitLab         = "gitlab"
+	providerGitea          = "gitea"
+	providerForgejo        = "forgejo"
+	providerBitbucketCloud = "bitbucket-cloud"
+)
+
+// ProviderType returns the git provider of a Repository, from its
+// git_provider type or guessed from the host of its URL for the public
+// GitHub, GitLab and Codeberg.
+func ProviderType(repo *v1alpha1.Repository) string {
+	if repo.Spec.GitProvider != nil && repo.Spec.GitProvider.Type != "" {
+		return repo.Spec.GitProvider.Type
+	}
+	parsed, err := url.Parse(repo.Spec.URL)
+	if err != nil {
+		return ""
+	}
+	switch strings.ToLower(parsed.Host) {
+	case "github.com":
+		return providerGitHub
+	case "gitlab.com":
+		return providerGitLab
+	case "codeberg.org":
+		return providerForgejo
+	}
+	return ""
+}
+
+// Validate checks the webhook secret, the token and the webhook of the git
+// provider of a Repository and returns a condition for each of them with a
+// Ready condition, nil when the Repository has no token since it is then
+// using the GitHub App. A webhook pointing to the controller URL is looked
+// for, any webhook will do when the URL is unknown.
+func Validate(ctx context.Context, run *params.Run, kint kubeinteraction.Interface, repo *v1alpha1.Repository, controllerURL string) apis.Conditions {
+	if repo.Spec.GitProvider == nil || repo.Spec.GitProvider.Secret == nil {
+		return nil
+	}
+	providerType := ProviderType(repo)
+
+	conditions := apis.Conditions{checkWebhookSecret(ctx, run, kint, repo, providerType)}
+
+	client, tokenCondition := checkToken(ctx, run, kint, repo, providerType)
+	conditions = append(conditions, tokenCondition)
+	if tokenCondition.IsTrue() {
+		conditions = append(conditions, checkTokenPermissions(ctx, client), checkWebhook(ctx, client, repo, controllerURL))
+	} else {
+		conditions = append(conditions,
+			unknown(ConditionTokenPermissions, "TokenInvalid", "cannot check the permissions of an invalid token"),
+			unknown(ConditionWebhook, "TokenInvalid", "cannot look for the webhook without a valid token"))
+	}
+
+	ready := apis.Condition{
+		Type:    apis.ConditionReady,
+		Status:  corev1.ConditionTrue,
+		Reason:  "Valid",
+		Message: "the credentials of the git provider are valid",
+	}
+	failed := []string{}
+	for _, condition := range conditions {
+		if condition.IsFalse() {
+			failed = append(failed, condition.Message)
+		}
+	}
+	if len(failed) > 0 {
+		ready.Status = corev1.ConditionFalse
+		ready.Reason = "Invalid"
+		ready.Message = strings.Join(failed, ", ")
+	}
+	return append(conditions, ready)
+}
+
+// CheckAccess checks the token of the git_provider secret of a Repository can
+// access its git repository on the git provider, and has the permissions
+// needed on it when the git provider can tell them.
+func CheckAccess(ctx context.Context, run *params.Run, kint kubeinteraction.Interface, repo *v1alpha1.Repository) error {
+	if repo.Spec.GitProvider == nil || repo.Spec.GitProvider.Secret == nil {
+		return fmt.Errorf("the repository has no git_provider secret")
+	}
+	client, err := repositoryClient(ctx, run, kint, repo)
+	if err != nil {
+		return err
+	}
+	if err := client.checkRepository(ctx); err != nil {
+		return fmt.Errorf("the token of the secret %s cannot access %s: %w", repo.Spec.GitProvider.Secret.Name, repo.Spec.URL, err)
+	}
+	if condition := checkTokenPermissions(ctx, client); condition.IsFalse() {
+		return fmt.Errorf("%s on %s, update the token of the secret %s", condition.Message, repo.Spec.URL, repo.Spec.GitProvider.Secret.Name)
+	}
+	return nil
+}
+
+func checkWebhookSecret(ctx context.Context, run *params.Run, kint kubeinteraction.Interface, repo *v1alpha1.Repository, providerType string) apis.Condition {
+	if repo.Spec.GitProvider.WebhookSecret == nil {
+		if providerType == providerBitbucketCloud {
+			// bitbucket cloud doesn't sign its webhooks
+			return unknown(ConditionWebhookSecret, "NotSupported", "bitbucket cloud webhooks have no secret")
+		}
+		return invalid(ConditionWebhookSecret, "Missing", "no webhook_secret in the git_provider of the repository")
+	}
+	value, err := secrets.GetRepositorySecret(ctx, run, kint, repo.GetNamespace(), repo.Spec.GitProvider.WebhookSecret, defaultWebhookSecretKey)
+	if err != nil {
+		return invalid(ConditionWebhookSecret, "Missing", fmt.Sprintf("cannot get the webhook secret %s: %v", repo.Spec.GitProvider.WebhookSecret.Name, err))
+	}
+	if strings.TrimSpace(value) == "" {
+		return invalid(ConditionWebhookSecret, "Empty", fmt.Sprintf("the webhook secret %s is empty", repo.Spec.GitProvider.WebhookSecret.Name))
+	}
+	return valid(ConditionWebhookSecret, "the webhook secret is set")
+}
+
+func checkToken(ctx context.Context, run *params.Run, kint kubeinteraction.Interface, repo *v1alpha1.Repository, providerType string) (providerClient, apis.Condition) {
+	token, err := secrets.GetRepositorySecret(ctx, run, kint, repo.GetNamespace(), repo.Spec.GitProvider.Secret, defaultTokenKey)
+	if err != nil {
+		return nil, invalid(ConditionToken, "Missing", fmt.Sprintf("cannot get the token secret %s: %v", repo.Spec.GitProvider.Secret.Name, err))
+	}
+	if token = strings.TrimSpace(token); token == "" {
+		return nil, invalid(ConditionToken, "Empty", fmt.Sprintf("the token secret %s is empty", repo.Spec.GitProvider.Secret.Name))
+	}
+	client, err := newProviderClient(ctx, run, repo, providerType, token)
+	if err != nil {
+		return nil, unknown(ConditionToken, "UnsupportedProvider", err.Error())
+	}
+	if err := client.checkToken(ctx); err != nil {
+		return nil, invalid(ConditionToken, "Invalid", fmt.Sprintf("the token is refused by %s: %v", providerType, err))
+	}
+	return client, valid(ConditionToken, "the token is accepted by the git provider")
+}
+
+func checkWebhook(ctx context.Context, client providerClient, repo *v1alpha1.Repository, controllerURL string) apis.Condition {
+	hooks, err := client.hooks(ctx)
+	if err != nil {
+		return invalid(ConditionWebhook, "CannotList", fmt.Sprintf("cannot list the webhooks of %s: %v", repo.Spec.URL, err))
+	}
+	controllerURL = strings.TrimSuffix(controllerURL, "/")
+	for _, hook := range hooks {
+		if controllerURL == "" || strings.TrimSuffix(hook.url, "/") == controllerURL {
+			return valid(ConditionWebhook, "the webhook is installed on the repository")
+		}
+	}
+	if controllerURL == "" {
+		return invalid(ConditionWebhook, "NotFound", fmt.Sprintf("there is no webhook on %s", repo.Spec.URL))
+	}
+	return invalid(ConditionWebhook, "NotFound", fmt.Sprintf("there is no webhook to %s on %s", controllerURL, repo.Spec.URL))
+}
+
+func valid(conditionType apis.ConditionType, message string) apis.Condition {
+	return apis.Condition{Type: conditionType, Status: corev1.ConditionTrue, Reason: "Valid", Message: message}
+}
+
+func invalid(conditionType apis.ConditionType, reason, message string) apis.Condition {
+	return apis.Condition{Type: conditionType, Status: corev1.ConditionFalse, Reason: reason, Message: message}
+}
+
+func unknown(conditionType apis.ConditionType, reason, message string) apis.Condition {
+	return apis.Condition{Type: conditionType, Status: corev1.ConditionUnknown, Reason: reason, Message: message}
+}
@@ -0,0 +1,217 @@
+package credentials
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	kitesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+const controllerURL = "https://pac.company.com"
+
+// providerServer answers like GitHub Enterprise, GitLab and Gitea to the
+// requests with a valid token, the repository has one webhook to hookURL.
+func providerServer(t *testing.T, hookURL string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" && r.Header.Get("PRIVATE-TOKEN") != "token" &&
+			r.Header.Get("Authorization") != "token token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message": "Bad credentials"}`)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v3/user", "/api/v4/user", "/api/v1/user":
+			w.Header().Set("X-OAuth-Scopes", "repo, admin:repo_hook")
+			fmt.Fprint(w, `{"login": "owner", "username": "owner"}`)
+		case "/api/v3/repos/owner/repo/hooks", "/api/v1/repos/owner/repo/hooks":
+			fmt.Fprintf(w, `[{"id": 1, "config": {"url": "%s"}}]`, hookURL)
+		case "/api/v4/projects/owner/repo/hooks":
+			fmt.Fprintf(w, `[{"id": 1, "url": "%s"}]`, hookURL)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func testRepo(providerType, apiURL string) *v1alpha1.Repository {
+	return &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Spec: v1alpha1.RepositorySpec{
+			URL: "https://git.company.com/owner/repo",
+			GitProvider: &v1alpha1.GitProvider{
+				Type:          providerType,
+				URL:           apiURL,
+				Secret:        &v1alpha1.Secret{Name: "token-secret"},
+				WebhookSecret: &v1alpha1.Secret{Name: "webhook-secret"},
+			},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		providerType  string
+		secrets       map[string]string
+		hookURL       string
+		noProvider    bool
+		noWebhook     bool
+		want          map[apis.ConditionType]corev1.ConditionStatus
+		wantReadyText string
+	}{
+		{
+			name:         "valid github credentials",
+			providerType: providerGitHub,
+			secrets:      map[string]string{"token-secret": "token", "webhook-secret": "secret"},
+			hookURL:      controllerURL + "/",
+			want: map[apis.ConditionType]corev1.ConditionStatus{
+				ConditionWebhookSecret:    corev1.ConditionTrue,
+				ConditionToken:            corev1.ConditionTrue,
+				ConditionTokenPermissions: corev1.ConditionTrue,
+				ConditionWebhook:          corev1.ConditionTrue,
+				apis.ConditionReady:       corev1.ConditionTrue,
+			},
+		},
+		{
+			name:         "valid gitlab credentials",
+			providerType: providerGitLab,
+			secrets:      map[string]string{"token-secret": "token", "webhook-secret": "secret"},
+			hookURL:      controllerURL,
+			want: map[apis.ConditionType]corev1.ConditionStatus{
+				ConditionWebhookSecret:    corev1.ConditionTrue,
+				ConditionToken:            corev1.ConditionTrue,
+				ConditionTokenPermissions: corev1.ConditionUnknown,
+				ConditionWebhook:          corev1.ConditionTrue,
+				apis.ConditionReady:       corev1.ConditionTrue,
+			},
+		},
+		{
+			name:         "valid gitea credentials",
+			providerType: providerGitea,
+			secrets:      map[string]string{"token-secret": "token", "webhook-secret": "secret"},
+			hookURL:      controllerURL,
+			want: map[apis.ConditionType]corev1.ConditionStatus{
+				ConditionWebhookSecret:    corev1.ConditionTrue,
+				ConditionToken:            corev1.ConditionTrue,
+				ConditionTokenPermissions: corev1.ConditionUnknown,
+				ConditionWebhook:          corev1.ConditionTrue,
+				apis.ConditionReady:       corev1.ConditionTrue,
+			},
+		},
+		{
+			name:         "expired token",
+			providerType: providerGitHub,
+			secrets:      map[string]string{"token-secret": "expired", "webhook-secret": "secret"},
+			want: map[apis.ConditionType]corev1.ConditionStatus{
+				ConditionWebhookSecret:    corev1.ConditionTrue,
+				ConditionToken:            corev1.ConditionFalse,
+				ConditionTokenPermissions: corev1.ConditionUnknown,
+				ConditionWebhook:          corev1.ConditionUnknown,
+				apis.ConditionReady:       corev1.ConditionFalse,
+			},
+			wantReadyText: "the token is refused by github",
+		},
+		{
+			name:         "webhook to another url",
+			providerType: providerGitLab,
+			secrets:      map[string]string{"token-secret": "token", "webhook-secret": "secret"},
+			hookURL:      "https://old.company.com",
+			want: map[apis.ConditionType]corev1.ConditionStatus{
+				ConditionWebhookSecret:    corev1.ConditionTrue,
+				ConditionToken:            corev1.ConditionTrue,
+				ConditionTokenPermissions: corev1.ConditionUnknown,
+				ConditionWebhook:          corev1.ConditionFalse,
+				apis.ConditionReady:       corev1.ConditionFalse,
+			},
+			wantReadyText: "there is no webhook to https://pac.company.com",
+		},
+		{
+			name:         "missing webhook secret",
+			providerType: providerGitHub,
+			secrets:      map[string]string{"token-secret": "token"},
+			hookURL:      controllerURL,
+			want: map[apis.ConditionType]corev1.ConditionStatus{
+				ConditionWebhookSecret:    corev1.ConditionFalse,
+				ConditionToken:            corev1.ConditionTrue,
+				ConditionTokenPermissions: corev1.ConditionTrue,
+				ConditionWebhook:          corev1.ConditionTrue,
+				apis.ConditionReady:       corev1.ConditionFalse,
+			},
+			wantReadyText: "cannot get the webhook secret webhook-secret",
+		},
+		{
+			name:         "bitbucket cloud",
+			providerType: providerBitbucketCloud,
+			secrets:      map[string]string{"token-secret": "token"},
+			noWebhook:    true,
+			want: map[apis.ConditionType]corev1.ConditionStatus{
+				ConditionWebhookSecret:    corev1.ConditionUnknown,
+				ConditionToken:            corev1.ConditionUnknown,
+				ConditionTokenPermissions: corev1.ConditionUnknown,
+				ConditionWebhook:          corev1.ConditionUnknown,
+				apis.ConditionReady:       corev1.ConditionTrue,
+			},
+		},
+		{
+			name:       "github app",
+			noProvider: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			server := providerServer(t, tt.hookURL)
+			defer server.Close()
+
+			apiURL := server.URL
+			if tt.providerType == providerGitHub {
+				apiURL += "/api/v3/"
+			}
+			repo := testRepo(tt.providerType, apiURL)
+			if tt.noProvider {
+				repo.Spec.GitProvider = nil
+			}
+			if tt.noWebhook {
+				repo.Spec.GitProvider.WebhookSecret = nil
+			}
+			kint := &kitesthelper.KinterfaceTest{GetSecretResult: tt.secrets}
+
+			conditions := Validate(ctx, &params.Run{}, kint, repo, controllerURL)
+			if tt.want == nil {
+				assert.Assert(t, conditions == nil)
+				return
+			}
+			assert.Equal(t, len(conditions), len(tt.want))
+			for _, condition := range conditions {
+				assert.Equal(t, condition.Status, tt.want[condition.Type], "%s: %s", condition.Type, condition.Message)
+				if condition.Type == apis.ConditionReady && tt.wantReadyText != "" {
+					assert.Assert(t, strings.Contains(condition.Message, tt.wantReadyText), condition.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestProviderType(t *testing.T) {
+	repo := &v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{URL: "https://GitHub.com/owner/repo"}}
+	assert.Equal(t, ProviderType(repo), providerGitHub)
+	repo.Spec.URL = "https://gitlab.com/group/project"
+	assert.Equal(t, ProviderType(repo), providerGitLab)
+	repo.Spec.URL = "https://codeberg.org/owner/repo"
+	assert.Equal(t, ProviderType(repo), providerForgejo)
+	repo.Spec.URL = "https://git.company.com/owner/repo"
+	assert.Equal(t, ProviderType(repo), "")
+	repo.Spec.GitProvider = &v1alpha1.GitProvider{Type: providerGitea}
+	assert.Equal(t, ProviderType(repo), providerGitea)
+}
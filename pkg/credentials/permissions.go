@@ -0,0 +1,213 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v49/github"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	githubScopesHeader = "X-OAuth-Scopes"
+	// a commit which never exists, the commit statuses created on it are
+	// refused after the check of the permissions of the token
+	githubZeroSHA = "0000000000000000000000000000000000000000"
+)
+
+// permissionsChecker is implemented by the provider clients which can tell
+// the permissions their token is missing on the repository
+type permissionsChecker interface {
+	missingPermissions(ctx context.Context) ([]string, error)
+}
+
+func checkTokenPermissions(ctx context.Context, client providerClient) apis.Condition {
+	checker, ok := client.(permissionsChecker)
+	if !ok {
+		return unknown(ConditionTokenPermissions, "NotSupported", "the permissions of the token cannot be checked on this git provider")
+	}
+	missing, err := checker.missingPermissions(ctx)
+	if err != nil {
+		return unknown(ConditionTokenPermissions, "CannotCheck", fmt.Sprintf("cannot check the permissions of the token: %v", err))
+	}
+	if len(missing) > 0 {
+		return invalid(ConditionTokenPermissions, "Missing",
+			fmt.Sprintf("the token is missing the permissions %s", strings.Join(missing, ", ")))
+	}
+	return valid(ConditionTokenPermissions, "the token has the permissions needed on the repository")
+}
+
+func (g *githubClient) missingPermissions(ctx context.Context) ([]string, error) {
+	return GitHubMissingPermissions(ctx, g.client, g.owner, g.repo)
+}
+
+// GitHubMissingPermissions returns the permissions Pipelines as Code needs on
+// the repository which the token of the client is missing. The scopes of the
+// classic personal access tokens are sent by GitHub with each response, the
+// permissions of the fine-grained ones are not so they are checked by reading
+// what each of them gives access to. Only read requests are sent, it is used
+// by the periodic checks of the credentials and by the admission webhook.
+func GitHubMissingPermissions(ctx context.Context, client *github.Client, owner, repo string) ([]string, error) {
+	return githubMissingPermissions(ctx, client, owner, repo, false)
+}
+
+// GitHubProbeMissingPermissions is like GitHubMissingPermissions but probes
+// the write permissions of the fine-grained tokens with write requests, it is
+// only used by the preflight of tkn pac when the token is set up.
+func GitHubProbeMissingPermissions(ctx context.Context, client *github.Client, owner, repo string) ([]string, error) {
+	return githubMissingPermissions(ctx, client, owner, repo, true)
+}
+
+func githubMissingPermissions(ctx context.Context, client *github.Client, owner, repo string, probeWrites bool) ([]string, error) {
+	_, resp, err := client.Users.Get(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if scopes, classic := resp.Header[http.CanonicalHeaderKey(githubScopesHeader)]; classic {
+		return githubMissingScopes(ctx, client, owner, repo, strings.Join(scopes, ","))
+	}
+	return githubMissingFineGrainedPermissions(ctx, client, owner, repo, probeWrites)
+}
+
+// githubMissingScopes checks a classic token has the repo scope, the
+// public_repo scope is enough for a public repository.
+func githubMissingScopes(ctx context.Context, client *github.Client, owner, repo, header string) ([]string, error) {
+	scopes := map[string]bool{}
+	for _, scope := range strings.Split(header, ",") {
+		scopes[strings.TrimSpace(scope)] = true
+	}
+	if scopes["repo"] {
+		return nil, nil
+	}
+	ghRepo, resp, err := client.Repositories.Get(ctx, owner, repo)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		// a private repository is not found without the repo scope
+		return []string{"scope repo"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case ghRepo.GetPrivate():
+		return []string{"scope repo"}, nil
+	case !scopes["public_repo"]:
+		return []string{"scope public_repo"}, nil
+	}
+	return nil, nil
+}
+
+// githubMissingFineGrainedPermissions checks the permissions of a
+// fine-grained token, GitHub refuses the requests with a 403 when the token
+// doesn't have the permission they need. Without probeWrites the resources of
+// each permission are only read, and the write permissions are missing when
+// GitHub reports the user of the token cannot push to or administer the
+// repository. With probeWrites the write permissions are probed with requests
+// GitHub always rejects as invalid, after the check of the permission, so
+// nothing is ever created on the repository.
+func githubMissingFineGrainedPermissions(ctx context.Context, client *github.Client, owner, repo string, probeWrites bool) ([]string, error) {
+	// without the metadata permission the repository is not found, and
+	// nothing else can be checked on it
+	ghRepo, resp, err := client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden) {
+			return []string{"Metadata: Read-only"}, nil
+		}
+		return nil, err
+	}
+
+	listOptions := github.ListOptions{PerPage: 1}
+	probes := []struct {
+		permission string
+		// role is the role the user of the token needs on the repository for
+		// the permission, as reported in the permissions of the repository
+		role  string
+		read  func() error
+		write func() error
+	}{
+		{
+			permission: "Contents: Read-only",
+			read: func() error {
+				_, _, err := client.Repositories.ListCommits(ctx, owner, repo, &github.CommitsListOptions{ListOptions: listOptions})
+				return err
+			},
+		},
+		{
+			permission: "Commit statuses: Read and write",
+			role:       "push",
+			read: func() error {
+				_, _, err := client.Repositories.ListStatuses(ctx, owner, repo, ghRepo.GetDefaultBranch(), &listOptions)
+				return err
+			},
+			write: func() error {
+				_, _, err := client.Repositories.CreateStatus(ctx, owner, repo, githubZeroSHA, &github.RepoStatus{
+					State:   github.String("pending"),
+					Context: github.String("Pipelines as Code permissions check"),
+				})
+				return err
+			},
+		},
+		{
+			permission: "Pull requests: Read and write",
+			role:       "push",
+			read: func() error {
+				_, _, err := client.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{ListOptions: listOptions})
+				return err
+			},
+			write: func() error {
+				// a pull request without head and base branches is invalid
+				_, _, err := client.PullRequests.Create(ctx, owner, repo, &github.NewPullRequest{})
+				return err
+			},
+		},
+		{
+			permission: "Webhooks: Read and write",
+			role:       "admin",
+			read: func() error {
+				_, _, err := client.Repositories.ListHooks(ctx, owner, repo, &listOptions)
+				return err
+			},
+			write: func() error {
+				// a webhook without url is invalid
+				_, _, err := client.Repositories.CreateHook(ctx, owner, repo, &github.Hook{Config: map[string]interface{}{}})
+				return err
+			},
+		},
+	}
+	roles := ghRepo.GetPermissions()
+	missing := []string{}
+	for _, p := range probes {
+		if p.role != "" && len(roles) > 0 && !roles[p.role] {
+			missing = append(missing, p.permission)
+			continue
+		}
+		probe := p.read
+		if probeWrites && p.write != nil {
+			probe = p.write
+		}
+		err := probe()
+		if err == nil {
+			continue
+		}
+		// the rate limits are refused with a 403 too, they don't tell anything
+		// about the permissions of the token
+		var rateLimitErr *github.RateLimitError
+		var abuseRateLimitErr *github.AbuseRateLimitError
+		if errors.As(err, &rateLimitErr) || errors.As(err, &abuseRateLimitErr) {
+			return nil, fmt.Errorf("rate limited by GitHub while checking the permission %s: %w", p.permission, err)
+		}
+		var errResponse *github.ErrorResponse
+		if !errors.As(err, &errResponse) {
+			return nil, err
+		}
+		// the other errors come after the check of the permission, like an
+		// empty repository, the commit which doesn't exist or the invalid
+		// pull request and webhook
+		if errResponse.Response.StatusCode == http.StatusForbidden {
+			missing = append(missing, p.permission)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,182 @@
+package credentials
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v49/github"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestGitHubMissingPermissions(t *testing.T) {
+	tests := []struct {
+		name        string
+		scopes      *string
+		private     bool
+		permissions string
+		probeWrites bool
+		forbidden   []string
+		want        []string
+	}{
+		{
+			name:   "classic token with the repo scope",
+			scopes: github.String("repo"),
+			want:   []string{},
+		},
+		{
+			name:   "classic token with the public_repo scope on a public repository",
+			scopes: github.String("public_repo, read:org"),
+			want:   []string{},
+		},
+		{
+			name:    "classic token with the public_repo scope on a private repository",
+			scopes:  github.String("public_repo"),
+			private: true,
+			want:    []string{"scope repo"},
+		},
+		{
+			name:   "classic token without scope",
+			scopes: github.String(""),
+			want:   []string{"scope public_repo"},
+		},
+		{
+			name: "fine-grained token with all the permissions",
+			want: []string{},
+		},
+		{
+			name:      "fine-grained token without access to the repository",
+			forbidden: []string{"/repos/owner/repo"},
+			want:      []string{"Metadata: Read-only"},
+		},
+		{
+			name:        "fine-grained token missing permissions",
+			probeWrites: true,
+			forbidden:   []string{"/repos/owner/repo/statuses/" + githubZeroSHA, "/repos/owner/repo/hooks"},
+			want:        []string{"Commit statuses: Read and write", "Webhooks: Read and write"},
+		},
+		{
+			name:        "fine-grained token missing the pull requests permission",
+			probeWrites: true,
+			forbidden:   []string{"/repos/owner/repo/pulls"},
+			want:        []string{"Pull requests: Read and write"},
+		},
+		{
+			name:      "fine-grained token without read access to the webhooks",
+			forbidden: []string{"/repos/owner/repo/commits/main/statuses", "/repos/owner/repo/hooks"},
+			want:      []string{"Commit statuses: Read and write", "Webhooks: Read and write"},
+		},
+		{
+			name:        "fine-grained token of a user who cannot push to the repository",
+			permissions: `{"admin": false, "push": false, "pull": true}`,
+			want:        []string{"Commit statuses: Read and write", "Pull requests: Read and write", "Webhooks: Read and write"},
+		},
+		{
+			name:        "fine-grained token of a user who can push to the repository",
+			permissions: `{"admin": false, "push": true, "pull": true}`,
+			want:        []string{"Webhooks: Read and write"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for _, path := range tt.forbidden {
+					if r.URL.Path == "/api/v3"+path {
+						w.WriteHeader(http.StatusForbidden)
+						fmt.Fprint(w, `{"message": "Resource not accessible by personal access token"}`)
+						return
+					}
+				}
+				if !tt.probeWrites {
+					assert.Equal(t, r.Method, http.MethodGet)
+				}
+				switch r.URL.Path {
+				case "/api/v3/user":
+					if tt.scopes != nil {
+						w.Header().Set("X-OAuth-Scopes", *tt.scopes)
+					}
+					fmt.Fprint(w, `{"login": "owner"}`)
+				case "/api/v3/repos/owner/repo":
+					if tt.permissions != "" {
+						fmt.Fprintf(w, `{"private": %t, "default_branch": "main", "permissions": %s}`, tt.private, tt.permissions)
+						return
+					}
+					fmt.Fprintf(w, `{"private": %t, "default_branch": "main"}`, tt.private)
+				case "/api/v3/repos/owner/repo/statuses/" + githubZeroSHA:
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					fmt.Fprint(w, `{"message": "No commit found for SHA"}`)
+				case "/api/v3/repos/owner/repo/pulls", "/api/v3/repos/owner/repo/hooks":
+					if r.Method == http.MethodGet {
+						fmt.Fprint(w, `[]`)
+						return
+					}
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					fmt.Fprint(w, `{"message": "Validation Failed"}`)
+				default:
+					fmt.Fprint(w, `[]`)
+				}
+			}))
+			defer server.Close()
+			client, err := github.NewEnterpriseClient(server.URL+"/api/v3/", "", server.Client())
+			assert.NilError(t, err)
+
+			check := GitHubMissingPermissions
+			if tt.probeWrites {
+				check = GitHubProbeMissingPermissions
+			}
+			got, err := check(ctx, client, "owner", "repo")
+			assert.NilError(t, err)
+			if len(tt.want) == 0 {
+				assert.Equal(t, len(got), 0, "%v", got)
+				return
+			}
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestGitHubMissingPermissionsRateLimited(t *testing.T) {
+	tests := []struct {
+		name   string
+		header map[string]string
+		body   string
+	}{
+		{
+			name:   "primary rate limit",
+			header: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Limit": "5000"},
+			body:   `{"message": "API rate limit exceeded"}`,
+		},
+		{
+			name: "secondary rate limit",
+			body: `{"message": "You have exceeded a secondary rate limit", "documentation_url": "https://docs.github.com/rest/overview/resources-in-the-rest-api#secondary-rate-limits"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v3/user":
+					fmt.Fprint(w, `{"login": "owner"}`)
+				case "/api/v3/repos/owner/repo":
+					fmt.Fprint(w, `{"private": false}`)
+				default:
+					for k, v := range tt.header {
+						w.Header().Set(k, v)
+					}
+					w.WriteHeader(http.StatusForbidden)
+					fmt.Fprint(w, tt.body)
+				}
+			}))
+			defer server.Close()
+			client, err := github.NewEnterpriseClient(server.URL+"/api/v3/", "", server.Client())
+			assert.NilError(t, err)
+
+			_, err = GitHubMissingPermissions(ctx, client, "owner", "repo")
+			assert.ErrorContains(t, err, "rate limited by GitHub while checking the permission Contents: Read-only")
+		})
+	}
+}
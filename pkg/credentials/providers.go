@@ -0,0 +1,289 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/xanzy/go-gitlab"
+	"golang.org/x/oauth2"
+)
+
+const (
+	gitlabPublicAPIURL = "https://gitlab.com"
+	hooksPerPage       = 100
+)
+
+// providerClient checks the token, manages the webhooks and sets the commit
+// statuses of a repository on a git provider
+type providerClient interface {
+	checkToken(ctx context.Context) error
+	checkRepository(ctx context.Context) error
+	hooks(ctx context.Context) ([]hook, error)
+	createHook(ctx context.Context, hookURL, secret string) (int64, error)
+	updateHook(ctx context.Context, id int64, hookURL, secret string) error
+	deleteHook(ctx context.Context, id int64) error
+	createStatus(ctx context.Context, sha string, status CommitStatus) error
+}
+
+// hook is a webhook of a repository
+type hook struct {
+	id  int64
+	url string
+}
+
+func newProviderClient(ctx context.Context, run *params.Run, repo *v1alpha1.Repository, providerType, token string) (providerClient, error) {
+	org, name, err := formatting.GetRepoOwnerSplitted(repo.Spec.URL)
+	if err != nil {
+		return nil, err
+	}
+	apiURL := repo.Spec.GitProvider.URL
+
+	switch providerType {
+	case providerGitHub:
+		httpClient := &http.Client{
+			Timeout: run.Clients.HTTP.Timeout,
+			Transport: &oauth2.Transport{
+				Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}),
+				Base:   run.Clients.HTTP.Transport,
+			},
+		}
+		client := github.NewClient(httpClient)
+		if apiURL != "" && !strings.Contains(apiURL, "api.github.com") {
+			if client, err = github.NewEnterpriseClient(apiURL, "", httpClient); err != nil {
+				return nil, err
+			}
+		}
+		return &githubClient{client: client, owner: org, repo: name}, nil
+	case providerGitLab:
+		if apiURL == "" {
+			apiURL = gitlabPublicAPIURL
+		}
+		client, err := gitlab.NewClient(token, gitlab.WithBaseURL(apiURL), gitlab.WithHTTPClient(&run.Clients.HTTP))
+		if err != nil {
+			return nil, err
+		}
+		return &gitlabClient{client: client, project: org + "/" + name}, nil
+	case providerGitea, providerForgejo:
+		if apiURL == "" {
+			parsed, err := url.Parse(repo.Spec.URL)
+			if err != nil {
+				return nil, err
+			}
+			apiURL = parsed.Scheme + "://" + parsed.Host
+		}
+		client, err := gitea.NewClient(apiURL, gitea.SetToken(token), gitea.SetHTTPClient(&run.Clients.HTTP),
+			gitea.SetContext(ctx), gitea.SetGiteaVersion(""))
+		if err != nil {
+			return nil, err
+		}
+		return &giteaClient{client: client, owner: org, repo: name}, nil
+	case "":
+		return nil, fmt.Errorf("cannot detect the git provider of %s, set the type of its git_provider", repo.Spec.URL)
+	}
+	return nil, fmt.Errorf("the %s git provider is not supported", providerType)
+}
+
+// CheckRepositoryAccess checks the token of a user of a git provider can read
+// the git repository at repoURL, apiURL is the API of the git provider, the
+// public one of GitHub and GitLab when empty.
+func CheckRepositoryAccess(ctx context.Context, run *params.Run, repoURL, providerType, apiURL, token string) error {
+	client, err := newProviderClient(ctx, run, &v1alpha1.Repository{
+		Spec: v1alpha1.RepositorySpec{URL: repoURL, GitProvider: &v1alpha1.GitProvider{URL: apiURL}},
+	}, providerType, token)
+	if err != nil {
+		return err
+	}
+	return client.checkRepository(ctx)
+}
+
+type githubClient struct {
+	client      *github.Client
+	owner, repo string
+}
+
+func (g *githubClient) checkToken(ctx context.Context) error {
+	_, _, err := g.client.Users.Get(ctx, "")
+	return err
+}
+
+func (g *githubClient) checkRepository(ctx context.Context) error {
+	_, _, err := g.client.Repositories.Get(ctx, g.owner, g.repo)
+	return err
+}
+
+func (g *githubClient) hooks(ctx context.Context) ([]hook, error) {
+	ghHooks, _, err := g.client.Repositories.ListHooks(ctx, g.owner, g.repo, &github.ListOptions{PerPage: hooksPerPage})
+	if err != nil {
+		return nil, err
+	}
+	hooks := []hook{}
+	for _, ghHook := range ghHooks {
+		if hookURL, ok := ghHook.Config["url"].(string); ok {
+			hooks = append(hooks, hook{id: ghHook.GetID(), url: hookURL})
+		}
+	}
+	return hooks, nil
+}
+
+func githubHook(hookURL, secret string) *github.Hook {
+	return &github.Hook{
+		Name:   github.String("web"),
+		Active: github.Bool(true),
+		Events: []string{"issue_comment", "pull_request", "push", "release"},
+		Config: map[string]interface{}{
+			"url":          hookURL,
+			"content_type": "json",
+			"insecure_ssl": "0",
+			"secret":       secret,
+		},
+	}
+}
+
+func (g *githubClient) createHook(ctx context.Context, hookURL, secret string) (int64, error) {
+	created, _, err := g.client.Repositories.CreateHook(ctx, g.owner, g.repo, githubHook(hookURL, secret))
+	if err != nil {
+		return 0, err
+	}
+	return created.GetID(), nil
+}
+
+func (g *githubClient) updateHook(ctx context.Context, id int64, hookURL, secret string) error {
+	_, _, err := g.client.Repositories.EditHook(ctx, g.owner, g.repo, id, githubHook(hookURL, secret))
+	return err
+}
+
+func (g *githubClient) deleteHook(ctx context.Context, id int64) error {
+	_, err := g.client.Repositories.DeleteHook(ctx, g.owner, g.repo, id)
+	return err
+}
+
+type gitlabClient struct {
+	client  *gitlab.Client
+	project string
+}
+
+func (g *gitlabClient) checkToken(ctx context.Context) error {
+	_, _, err := g.client.Users.CurrentUser(gitlab.WithContext(ctx))
+	return err
+}
+
+func (g *gitlabClient) checkRepository(ctx context.Context) error {
+	_, _, err := g.client.Projects.GetProject(g.project, &gitlab.GetProjectOptions{}, gitlab.WithContext(ctx))
+	return err
+}
+
+func (g *gitlabClient) hooks(ctx context.Context) ([]hook, error) {
+	glHooks, _, err := g.client.Projects.ListProjectHooks(g.project,
+		&gitlab.ListProjectHooksOptions{PerPage: hooksPerPage}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	hooks := []hook{}
+	for _, glHook := range glHooks {
+		hooks = append(hooks, hook{id: int64(glHook.ID), url: glHook.URL})
+	}
+	return hooks, nil
+}
+
+func (g *gitlabClient) createHook(ctx context.Context, hookURL, secret string) (int64, error) {
+	created, _, err := g.client.Projects.AddProjectHook(g.project, &gitlab.AddProjectHookOptions{
+		EnableSSLVerification: gitlab.Bool(true),
+		MergeRequestsEvents:   gitlab.Bool(true),
+		NoteEvents:            gitlab.Bool(true),
+		PushEvents:            gitlab.Bool(true),
+		Token:                 gitlab.String(secret),
+		URL:                   gitlab.String(hookURL),
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	return int64(created.ID), nil
+}
+
+func (g *gitlabClient) updateHook(ctx context.Context, id int64, hookURL, secret string) error {
+	_, _, err := g.client.Projects.EditProjectHook(g.project, int(id), &gitlab.EditProjectHookOptions{
+		EnableSSLVerification: gitlab.Bool(true),
+		MergeRequestsEvents:   gitlab.Bool(true),
+		NoteEvents:            gitlab.Bool(true),
+		PushEvents:            gitlab.Bool(true),
+		Token:                 gitlab.String(secret),
+		URL:                   gitlab.String(hookURL),
+	}, gitlab.WithContext(ctx))
+	return err
+}
+
+func (g *gitlabClient) deleteHook(ctx context.Context, id int64) error {
+	_, err := g.client.Projects.DeleteProjectHook(g.project, int(id), gitlab.WithContext(ctx))
+	return err
+}
+
+type giteaClient struct {
+	client      *gitea.Client
+	owner, repo string
+}
+
+func (g *giteaClient) checkToken(_ context.Context) error {
+	_, _, err := g.client.GetMyUserInfo()
+	return err
+}
+
+func (g *giteaClient) checkRepository(_ context.Context) error {
+	_, _, err := g.client.GetRepo(g.owner, g.repo)
+	return err
+}
+
+func (g *giteaClient) hooks(_ context.Context) ([]hook, error) {
+	giteaHooks, _, err := g.client.ListRepoHooks(g.owner, g.repo, gitea.ListHooksOptions{
+		ListOptions: gitea.ListOptions{PageSize: hooksPerPage},
+	})
+	if err != nil {
+		return nil, err
+	}
+	hooks := []hook{}
+	for _, giteaHook := range giteaHooks {
+		hooks = append(hooks, hook{id: giteaHook.ID, url: giteaHook.Config["url"]})
+	}
+	return hooks, nil
+}
+
+var giteaHookEvents = []string{"push", "delete", "pull_request", "issue_comment"}
+
+func giteaHookConfig(hookURL, secret string) map[string]string {
+	return map[string]string{"url": hookURL, "content_type": "json", "secret": secret}
+}
+
+func (g *giteaClient) createHook(_ context.Context, hookURL, secret string) (int64, error) {
+	created, _, err := g.client.CreateRepoHook(g.owner, g.repo, gitea.CreateHookOption{
+		Type:   gitea.HookTypeGitea,
+		Config: giteaHookConfig(hookURL, secret),
+		Events: giteaHookEvents,
+		Active: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+func (g *giteaClient) updateHook(_ context.Context, id int64, hookURL, secret string) error {
+	active := true
+	_, err := g.client.EditRepoHook(g.owner, g.repo, id, gitea.EditHookOption{
+		Config: giteaHookConfig(hookURL, secret),
+		Events: giteaHookEvents,
+		Active: &active,
+	})
+	return err
+}
+
+func (g *giteaClient) deleteHook(_ context.Context, id int64) error {
+	_, err := g.client.DeleteRepoHook(g.owner, g.repo, id)
+	return err
+}
@@ -0,0 +1,96 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/xanzy/go-gitlab"
+)
+
+// the states of a commit status
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+	StatusPending = "pending"
+)
+
+// CommitStatus is a status set on a commit of a repository of a git provider.
+type CommitStatus struct {
+	// State is success, failure or pending
+	State string
+	// Name is the name of the status, the commit has one status by name
+	Name        string
+	Description string
+	TargetURL   string
+}
+
+// CreateCommitStatus sets a status on a commit of the git repository of a
+// Repository, with the token of its git_provider secret.
+func CreateCommitStatus(ctx context.Context, run *params.Run, kint kubeinteraction.Interface, repo *v1alpha1.Repository, sha string, status CommitStatus) error {
+	if repo.Spec.GitProvider == nil || repo.Spec.GitProvider.Secret == nil {
+		return fmt.Errorf("the repository has no git_provider secret")
+	}
+	client, err := repositoryClient(ctx, run, kint, repo)
+	if err != nil {
+		return err
+	}
+	return client.createStatus(ctx, sha, status)
+}
+
+func (g *githubClient) createStatus(ctx context.Context, sha string, status CommitStatus) error {
+	_, _, err := g.client.Repositories.CreateStatus(ctx, g.owner, g.repo, sha, &github.RepoStatus{
+		State:       github.String(status.State),
+		Context:     github.String(status.Name),
+		Description: github.String(status.Description),
+		TargetURL:   github.String(status.TargetURL),
+	})
+	return err
+}
+
+func (g *gitlabClient) createStatus(ctx context.Context, sha string, status CommitStatus) error {
+	state := gitlab.Success
+	switch status.State {
+	case StatusFailure:
+		state = gitlab.Failed
+	case StatusPending:
+		state = gitlab.Running
+	}
+	_, _, err := g.client.Commits.SetCommitStatus(g.project, sha, &gitlab.SetCommitStatusOptions{
+		State:       state,
+		Name:        gitlab.String(status.Name),
+		Description: gitlab.String(status.Description),
+		TargetURL:   gitlab.String(status.TargetURL),
+	}, gitlab.WithContext(ctx))
+	return err
+}
+
+func (g *giteaClient) createStatus(_ context.Context, sha string, status CommitStatus) error {
+	_, _, err := g.client.CreateStatus(g.owner, g.repo, sha, gitea.CreateStatusOption{
+		State:       gitea.StatusState(status.State),
+		Context:     status.Name,
+		Description: status.Description,
+		TargetURL:   status.TargetURL,
+	})
+	return err
+}
+
+// ValidateMirror checks the git provider of a mirror is one whose commit
+// statuses can be set, with a secret for its token.
+func ValidateMirror(mirror v1alpha1.Mirror) error {
+	if mirror.GitProvider.Secret == nil || mirror.GitProvider.Secret.Name == "" {
+		return fmt.Errorf("the mirror %s has no git_provider secret", mirror.URL)
+	}
+	switch providerType := ProviderType(&v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{URL: mirror.URL, GitProvider: &mirror.GitProvider}}); providerType {
+	case providerGitHub, providerGitLab, providerGitea, providerForgejo:
+	case "":
+		return fmt.Errorf("cannot detect the git provider of the mirror %s, set the type of its git_provider", mirror.URL)
+	default:
+		return fmt.Errorf("the statuses cannot be reported on the %s git provider of the mirror %s", providerType, mirror.URL)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package credentials
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	kitesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCreateCommitStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		providerType string
+		state        string
+		path         string
+		wantState    string
+	}{
+		{
+			name:         "gitlab failure",
+			providerType: providerGitLab,
+			state:        StatusFailure,
+			path:         "/api/v4/projects/owner/repo/statuses/abcdef",
+			wantState:    "failed",
+		},
+		{
+			name:         "gitlab pending",
+			providerType: providerGitLab,
+			state:        StatusPending,
+			path:         "/api/v4/projects/owner/repo/statuses/abcdef",
+			wantState:    "running",
+		},
+		{
+			name:         "gitea success",
+			providerType: providerGitea,
+			state:        StatusSuccess,
+			path:         "/api/v1/repos/owner/repo/statuses/abcdef",
+			wantState:    "success",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			var gotState string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != tt.path {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				body := map[string]interface{}{}
+				assert.NilError(t, json.NewDecoder(r.Body).Decode(&body))
+				// gitlab sends the options in the query
+				gotState = r.URL.Query().Get("state")
+				if state, ok := body["state"].(string); ok {
+					gotState = state
+				}
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			repo := testRepo(tt.providerType, server.URL)
+			kint := &kitesthelper.KinterfaceTest{GetSecretResult: map[string]string{"token-secret": "token"}}
+			err := CreateCommitStatus(ctx, &params.Run{}, kint, repo, "abcdef", CommitStatus{State: tt.state, Name: "ci"})
+			assert.NilError(t, err)
+			assert.Equal(t, gotState, tt.wantState)
+		})
+	}
+}
+
+func TestValidateMirror(t *testing.T) {
+	secret := &v1alpha1.Secret{Name: "token"}
+	assert.NilError(t, ValidateMirror(v1alpha1.Mirror{URL: "https://github.com/owner/repo", GitProvider: v1alpha1.GitProvider{Secret: secret}}))
+	assert.NilError(t, ValidateMirror(v1alpha1.Mirror{URL: "https://git.company.com/owner/repo", GitProvider: v1alpha1.GitProvider{Type: providerGitea, Secret: secret}}))
+	assert.ErrorContains(t, ValidateMirror(v1alpha1.Mirror{URL: "https://github.com/owner/repo"}), "has no git_provider secret")
+	assert.ErrorContains(t, ValidateMirror(v1alpha1.Mirror{URL: "https://git.company.com/owner/repo", GitProvider: v1alpha1.GitProvider{Secret: secret}}),
+		"cannot detect the git provider of the mirror")
+	assert.ErrorContains(t, ValidateMirror(v1alpha1.Mirror{URL: "https://bitbucket.org/owner/repo", GitProvider: v1alpha1.GitProvider{Type: providerBitbucketCloud, Secret: secret}}),
+		"the statuses cannot be reported on the bitbucket-cloud git provider")
+}
@@ -0,0 +1,128 @@
+package credentials
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
+)
+
+// Webhook is a webhook configured on the git repository of a Repository.
+type Webhook struct {
+	ID int64 `json:"id"`
+	// Repository is the URL of the git repository the webhook is on
+	Repository string `json:"repository"`
+	// URL is where the webhook sends the events
+	URL string `json:"url"`
+	// Secret is the sha256 of the webhook secret, the providers don't show
+	// the secret of the webhooks to find out if it has changed
+	Secret string `json:"secret"`
+}
+
+// ConfigureWebhook creates the webhook of a Repository to the controller URL
+// with its webhook secret, or updates it when they have changed since the
+// previous webhook configured, nil when none has been. The webhook already
+// pointing to the controller URL is taken over when the previous one is not
+// there, ie: created with tkn pac. It returns the webhook now configured.
+func ConfigureWebhook(ctx context.Context, run *params.Run, kint kubeinteraction.Interface, repo *v1alpha1.Repository, controllerURL string, previous *Webhook) (*Webhook, error) {
+	if repo.Spec.GitProvider == nil || repo.Spec.GitProvider.Secret == nil {
+		return nil, fmt.Errorf("the repository has no git_provider secret")
+	}
+	if repo.Spec.GitProvider.WebhookSecret == nil {
+		return nil, fmt.Errorf("the repository has no git_provider webhook_secret")
+	}
+	secret, err := secrets.GetRepositorySecret(ctx, run, kint, repo.GetNamespace(), repo.Spec.GitProvider.WebhookSecret, defaultWebhookSecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the webhook secret %s: %w", repo.Spec.GitProvider.WebhookSecret.Name, err)
+	}
+	// the payloads are validated with the secret as it is
+	if strings.TrimSpace(secret) == "" {
+		return nil, fmt.Errorf("the webhook secret %s is empty", repo.Spec.GitProvider.WebhookSecret.Name)
+	}
+	client, err := repositoryClient(ctx, run, kint, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	hooks, err := client.hooks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the webhooks of %s: %w", repo.Spec.URL, err)
+	}
+	wanted := &Webhook{Repository: repo.Spec.URL, URL: controllerURL, Secret: secretHash(secret)}
+	current := findHook(hooks, func(h hook) bool {
+		return previous != nil && previous.Repository == repo.Spec.URL && h.id == previous.ID
+	})
+	if current == nil {
+		current = findHook(hooks, func(h hook) bool {
+			return strings.TrimSuffix(h.url, "/") == strings.TrimSuffix(controllerURL, "/")
+		})
+	}
+
+	if current == nil {
+		if wanted.ID, err = client.createHook(ctx, controllerURL, secret); err != nil {
+			return nil, fmt.Errorf("cannot create the webhook on %s: %w", repo.Spec.URL, err)
+		}
+		return wanted, nil
+	}
+	wanted.ID = current.id
+	if previous != nil && *previous == *wanted && current.url == controllerURL {
+		return wanted, nil
+	}
+	if err := client.updateHook(ctx, current.id, controllerURL, secret); err != nil {
+		return nil, fmt.Errorf("cannot update the webhook %d on %s: %w", current.id, repo.Spec.URL, err)
+	}
+	return wanted, nil
+}
+
+// DeleteWebhook deletes a webhook configured for a Repository, a webhook
+// already deleted is not an error.
+func DeleteWebhook(ctx context.Context, run *params.Run, kint kubeinteraction.Interface, repo *v1alpha1.Repository, webhook *Webhook) error {
+	onRepo := repo.DeepCopy()
+	onRepo.Spec.URL = webhook.Repository
+	client, err := repositoryClient(ctx, run, kint, onRepo)
+	if err != nil {
+		return err
+	}
+	hooks, err := client.hooks(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot list the webhooks of %s: %w", webhook.Repository, err)
+	}
+	if findHook(hooks, func(h hook) bool { return h.id == webhook.ID }) == nil {
+		return nil
+	}
+	if err := client.deleteHook(ctx, webhook.ID); err != nil {
+		return fmt.Errorf("cannot delete the webhook %d on %s: %w", webhook.ID, webhook.Repository, err)
+	}
+	return nil
+}
+
+func findHook(hooks []hook, match func(hook) bool) *hook {
+	for i := range hooks {
+		if match(hooks[i]) {
+			return &hooks[i]
+		}
+	}
+	return nil
+}
+
+func repositoryClient(ctx context.Context, run *params.Run, kint kubeinteraction.Interface, repo *v1alpha1.Repository) (providerClient, error) {
+	token, err := secrets.GetRepositorySecret(ctx, run, kint, repo.GetNamespace(), repo.Spec.GitProvider.Secret, defaultTokenKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the token secret %s: %w", repo.Spec.GitProvider.Secret.Name, err)
+	}
+	if token = strings.TrimSpace(token); token == "" {
+		return nil, fmt.Errorf("the token secret %s is empty", repo.Spec.GitProvider.Secret.Name)
+	}
+	return newProviderClient(ctx, run, repo, ProviderType(repo), token)
+}
+
+func secretHash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,135 @@
+package credentials
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	kitesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+type fakeHook struct {
+	ID     int64             `json:"id"`
+	Config map[string]string `json:"config"`
+}
+
+// hooksServer answers like Gitea to the requests on the webhooks of
+// owner/repo, keeping them in hooks.
+func hooksServer(t *testing.T, hooks map[int64]*fakeHook) *httptest.Server {
+	t.Helper()
+	nextID := int64(10)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/repos/owner/repo/hooks" {
+			switch r.Method {
+			case http.MethodGet:
+				list := []*fakeHook{}
+				for _, hook := range hooks {
+					list = append(list, hook)
+				}
+				assert.NilError(t, json.NewEncoder(w).Encode(list))
+			case http.MethodPost:
+				hook := &fakeHook{}
+				assert.NilError(t, json.NewDecoder(r.Body).Decode(hook))
+				nextID++
+				hook.ID = nextID
+				hooks[hook.ID] = hook
+				w.WriteHeader(http.StatusCreated)
+				assert.NilError(t, json.NewEncoder(w).Encode(hook))
+			}
+			return
+		}
+		id, err := strconv.ParseInt(path.Base(r.URL.Path), 10, 64)
+		if err != nil || hooks[id] == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodPatch:
+			hook := &fakeHook{}
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(hook))
+			hooks[id].Config = hook.Config
+			fmt.Fprint(w, `{}`)
+		case http.MethodDelete:
+			delete(hooks, id)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func TestConfigureWebhook(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	hooks := map[int64]*fakeHook{
+		1: {ID: 1, Config: map[string]string{"url": "https://other.company.com"}},
+	}
+	server := hooksServer(t, hooks)
+	defer server.Close()
+
+	repo := testRepo(providerGitea, server.URL)
+	kint := &kitesthelper.KinterfaceTest{GetSecretResult: map[string]string{"token-secret": "token", "webhook-secret": "secret"}}
+	run := &params.Run{}
+
+	webhook, err := ConfigureWebhook(ctx, run, kint, repo, controllerURL, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, webhook.ID, int64(11))
+	assert.Equal(t, webhook.Repository, repo.Spec.URL)
+	assert.Equal(t, hooks[11].Config["url"], controllerURL)
+	assert.Equal(t, hooks[11].Config["secret"], "secret")
+	assert.Equal(t, len(hooks), 2)
+
+	// nothing has changed
+	again, err := ConfigureWebhook(ctx, run, kint, repo, controllerURL, webhook)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, again, webhook)
+
+	// the secret and the url of the controller have changed
+	kint.GetSecretResult["webhook-secret"] = "rotated"
+	updated, err := ConfigureWebhook(ctx, run, kint, repo, controllerURL+"/new", webhook)
+	assert.NilError(t, err)
+	assert.Equal(t, updated.ID, int64(11))
+	assert.Assert(t, updated.Secret != webhook.Secret)
+	assert.Equal(t, hooks[11].Config["url"], controllerURL+"/new")
+	assert.Equal(t, hooks[11].Config["secret"], "rotated")
+
+	// the webhook has been deleted by hand
+	delete(hooks, 11)
+	recreated, err := ConfigureWebhook(ctx, run, kint, repo, controllerURL, updated)
+	assert.NilError(t, err)
+	assert.Equal(t, recreated.ID, int64(12))
+
+	assert.NilError(t, DeleteWebhook(ctx, run, kint, repo, recreated))
+	assert.Equal(t, len(hooks), 1)
+	// already deleted
+	assert.NilError(t, DeleteWebhook(ctx, run, kint, repo, recreated))
+}
+
+func TestConfigureWebhookTakesOverExistingWebhook(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	hooks := map[int64]*fakeHook{
+		1: {ID: 1, Config: map[string]string{"url": controllerURL + "/"}},
+	}
+	server := hooksServer(t, hooks)
+	defer server.Close()
+
+	repo := testRepo(providerGitea, server.URL)
+	kint := &kitesthelper.KinterfaceTest{GetSecretResult: map[string]string{"token-secret": "token", "webhook-secret": "secret"}}
+	webhook, err := ConfigureWebhook(ctx, &params.Run{}, kint, repo, controllerURL, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, webhook.ID, int64(1))
+	assert.Equal(t, hooks[1].Config["secret"], "secret")
+	assert.Equal(t, len(hooks), 1)
+}
+
+func TestConfigureWebhookWithoutWebhookSecret(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	repo := testRepo(providerGitea, "https://git.company.com")
+	repo.Spec.GitProvider.WebhookSecret = nil
+	_, err := ConfigureWebhook(ctx, &params.Run{}, &kitesthelper.KinterfaceTest{}, repo, controllerURL, nil)
+	assert.ErrorContains(t, err, "no git_provider webhook_secret")
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	v1 "k8s.io/api/core/v1"
@@ -21,17 +22,24 @@ func NewEventEmitter(client kubernetes.Interface, logger *zap.SugaredLogger) *Ev
 }
 
 type EventEmitter struct {
-	client kubernetes.Interface
-	logger *zap.SugaredLogger
+	client    kubernetes.Interface
+	logger    *zap.SugaredLogger
+	eventType string
 }
 
 func (e *EventEmitter) SetLogger(logger *zap.SugaredLogger) {
 	e.logger = logger
 }
 
+// SetEventType set the type of the webhook event we are processing, it is
+// added as a label on the kubernetes events we emit.
+func (e *EventEmitter) SetEventType(eventType string) {
+	e.eventType = eventType
+}
+
 func (e *EventEmitter) EmitMessage(repo *v1alpha1.Repository, loggerLevel zapcore.Level, reason, message string) {
 	if repo != nil {
-		event := makeEvent(repo, loggerLevel, reason, message)
+		event := makeEvent(repo, loggerLevel, reason, message, e.eventType)
 		if _, err := e.client.CoreV1().Events(event.Namespace).Create(context.Background(), event, metav1.CreateOptions{}); err != nil {
 			e.logger.Infof("Cannot create event: %s", err.Error())
 		}
@@ -50,7 +58,7 @@ func (e *EventEmitter) EmitMessage(repo *v1alpha1.Repository, loggerLevel zapcor
 	}
 }
 
-func makeEvent(repo *v1alpha1.Repository, loggerLevel zapcore.Level, reason, message string) *v1.Event {
+func makeEvent(repo *v1alpha1.Repository, loggerLevel zapcore.Level, reason, message, eventType string) *v1.Event {
 	event := &v1.Event{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: repo.Name + "-",
@@ -77,5 +85,8 @@ func makeEvent(repo *v1alpha1.Repository, loggerLevel zapcore.Level, reason, mes
 	if loggerLevel == zap.InfoLevel {
 		event.Type = v1.EventTypeNormal
 	}
+	if eventType != "" {
+		event.Labels[keys.EventType] = formatting.K8LabelsCleanup(eventType)
+	}
 	return event
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
 	"go.uber.org/zap"
@@ -25,6 +26,7 @@ func TestEventEmitter_EmitMessage(t *testing.T) {
 		message     string
 		reason      string
 		logLevel    zapcore.Level
+		eventType   string
 		expectEvent bool
 	}{
 		{
@@ -55,6 +57,20 @@ func TestEventEmitter_EmitMessage(t *testing.T) {
 			expectEvent: true,
 			reason:      "aintnosunshine",
 		},
+		{
+			name: "event with an event type",
+			repo: &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-repo",
+					Namespace: "test-ns",
+				},
+				Spec: v1alpha1.RepositorySpec{},
+			},
+			message:     "info-message",
+			logLevel:    zap.InfoLevel,
+			expectEvent: true,
+			eventType:   "pull_request",
+		},
 		{
 			name:        "repo doesn't exists",
 			repo:        nil,
@@ -70,7 +86,9 @@ func TestEventEmitter_EmitMessage(t *testing.T) {
 			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
 
 			// emit event
-			NewEventEmitter(stdata.Kube, fakelogger).EmitMessage(tt.repo, tt.logLevel, tt.reason, tt.message)
+			emitter := NewEventEmitter(stdata.Kube, fakelogger)
+			emitter.SetEventType(tt.eventType)
+			emitter.EmitMessage(tt.repo, tt.logLevel, tt.reason, tt.message)
 
 			if tt.expectEvent {
 				events, err := stdata.Kube.CoreV1().Events(tt.repo.Namespace).List(context.Background(), metav1.ListOptions{})
@@ -84,6 +102,8 @@ func TestEventEmitter_EmitMessage(t *testing.T) {
 				assert.Equal(t, events.Items[0].InvolvedObject.Kind, pipelinesascode.RepositoryKind)
 				assert.Equal(t, events.Items[0].InvolvedObject.APIVersion, pipelinesascode.V1alpha1Version)
 				assert.Assert(t, events.Items[0].Source.Component != "")
+				assert.Equal(t, events.Items[0].Labels[keys.Repository], tt.repo.Name)
+				assert.Equal(t, events.Items[0].Labels[keys.EventType], tt.eventType)
 			}
 		})
 	}
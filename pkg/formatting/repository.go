@@ -1,6 +1,9 @@
 package formatting
 
 import (
+	"path"
+	"strings"
+
 	"github.com/jonboulle/clockwork"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
@@ -30,3 +33,29 @@ func ShowLastAge(repository v1alpha1.Repository, cw clockwork.Clock) string {
 	}
 	return Age(repository.Status[len(repository.Status)-1].CompletionTime, cw)
 }
+
+// ShowSubPath returns the cleaned up sub_path of a Repository, empty when it
+// is mapped to the whole git repository.
+func ShowSubPath(repository v1alpha1.Repository) string {
+	if strings.Trim(repository.Spec.SubPath, "/") == "" {
+		return ""
+	}
+	return strings.Trim(path.Clean("/"+repository.Spec.SubPath), "/")
+}
+
+// WildcardURL returns the URL of the organization or group a Repository with a
+// wildcard URL, ie: https://github.com/myorg/*, is matching the git
+// repositories of.
+func WildcardURL(repoURL string) (string, bool) {
+	if !strings.HasSuffix(repoURL, "/*") {
+		return "", false
+	}
+	return strings.TrimSuffix(repoURL, "/*"), true
+}
+
+// MatchWildcardURL returns true when a git repository is in the organization
+// or group of a wildcard URL, or in one of its subgroups.
+func MatchWildcardURL(wildcardURL, repoURL string) bool {
+	org, ok := WildcardURL(wildcardURL)
+	return ok && strings.HasPrefix(repoURL, org+"/") && len(repoURL) > len(org)+1
+}
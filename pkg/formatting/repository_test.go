@@ -135,3 +135,38 @@ func TestShowStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestShowSubPath(t *testing.T) {
+	for subPath, want := range map[string]string{
+		"":                  "",
+		"/":                 "",
+		"services/api":      "services/api",
+		"/services/api/":    "services/api",
+		"./services//api":   "services/api",
+		"services/../api/.": "api",
+	} {
+		repo := v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{SubPath: subPath}}
+		if got := ShowSubPath(repo); got != want {
+			t.Errorf("ShowSubPath(%q) = %q, want %q", subPath, got, want)
+		}
+	}
+}
+
+func TestMatchWildcardURL(t *testing.T) {
+	tests := []struct {
+		wildcardURL string
+		repoURL     string
+		want        bool
+	}{
+		{wildcardURL: "https://github.com/org/*", repoURL: "https://github.com/org/repo", want: true},
+		{wildcardURL: "https://gitlab.com/group/*", repoURL: "https://gitlab.com/group/subgroup/repo", want: true},
+		{wildcardURL: "https://github.com/org/*", repoURL: "https://github.com/organization/repo", want: false},
+		{wildcardURL: "https://github.com/org/*", repoURL: "https://github.com/org/", want: false},
+		{wildcardURL: "https://github.com/org/repo", repoURL: "https://github.com/org/repo", want: false},
+	}
+	for _, tt := range tests {
+		if got := MatchWildcardURL(tt.wildcardURL, tt.repoURL); got != tt.want {
+			t.Errorf("MatchWildcardURL(%q, %q) = %v, want %v", tt.wildcardURL, tt.repoURL, got, tt.want)
+		}
+	}
+}
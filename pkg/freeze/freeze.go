@@ -0,0 +1,202 @@
+package freeze
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	ActionSkip  = "skip"
+	ActionQueue = "queue"
+
+	// maxDuration bound how far back we look for the start of a window
+	maxDuration = 31 * 24 * time.Hour
+)
+
+// Status is the state of the freeze windows at a given time.
+type Status struct {
+	// Until is the end of the active freeze window
+	Until time.Time
+	// Action is the action of the active freeze window
+	Action string
+}
+
+type field struct {
+	// restricted is false when the field is a wildcard
+	restricted bool
+	values     map[int]bool
+}
+
+type schedule struct {
+	minute, hour, dom, month, dow field
+}
+
+// ParseWindows parse the freeze windows from the global configuration, the
+// format is a yaml list using the same fields as the Repository
+// freeze_windows.
+func ParseWindows(config string) ([]v1alpha1.FreezeWindow, error) {
+	windows := []v1alpha1.FreezeWindow{}
+	if strings.TrimSpace(config) == "" {
+		return windows, nil
+	}
+	if err := yaml.Unmarshal([]byte(config), &windows); err != nil {
+		return nil, fmt.Errorf("cannot parse freeze windows: %w", err)
+	}
+	for _, window := range windows {
+		if err := Validate(window); err != nil {
+			return nil, err
+		}
+	}
+	return windows, nil
+}
+
+// Validate check that a freeze window is valid.
+func Validate(window v1alpha1.FreezeWindow) error {
+	_, _, _, err := parseWindow(window)
+	return err
+}
+
+// Frozen return the status of the freeze windows active at now, the window
+// ending last wins when multiple windows are active.
+func Frozen(windows []v1alpha1.FreezeWindow, now time.Time) (*Status, error) {
+	var status *Status
+	for _, window := range windows {
+		sched, duration, loc, err := parseWindow(window)
+		if err != nil {
+			return nil, err
+		}
+		until, ok := sched.activeUntil(now.In(loc), duration)
+		if !ok || (status != nil && !until.After(status.Until)) {
+			continue
+		}
+		action := window.Action
+		if action == "" {
+			action = ActionSkip
+		}
+		status = &Status{Until: until, Action: action}
+	}
+	return status, nil
+}
+
+func parseWindow(window v1alpha1.FreezeWindow) (*schedule, time.Duration, *time.Location, error) {
+	sched, err := parseSchedule(window.Schedule)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("invalid freeze window schedule %q: %w", window.Schedule, err)
+	}
+	duration, err := time.ParseDuration(window.Duration)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("invalid freeze window duration %q: %w", window.Duration, err)
+	}
+	if duration <= 0 || duration > maxDuration {
+		return nil, 0, nil, fmt.Errorf("invalid freeze window duration %q: needs to be positive and at most %s", window.Duration, maxDuration)
+	}
+	loc := time.UTC
+	if window.Timezone != "" {
+		if loc, err = time.LoadLocation(window.Timezone); err != nil {
+			return nil, 0, nil, fmt.Errorf("invalid freeze window timezone %q: %w", window.Timezone, err)
+		}
+	}
+	switch window.Action {
+	case "", ActionSkip, ActionQueue:
+	default:
+		return nil, 0, nil, fmt.Errorf("invalid freeze window action %q: use %s or %s", window.Action, ActionSkip, ActionQueue)
+	}
+	return sched, duration, loc, nil
+}
+
+// activeUntil look for the last start of the window in the duration before
+// now and return when it ends, the windows start on a minute but their
+// duration may end in the middle of one.
+func (s *schedule) activeUntil(now time.Time, duration time.Duration) (time.Time, bool) {
+	for start := now.Truncate(time.Minute); start.Add(duration).After(now); start = start.Add(-time.Minute) {
+		if s.matches(start) {
+			return start.Add(duration), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (s *schedule) matches(t time.Time) bool {
+	if !s.minute.values[t.Minute()] || !s.hour.values[t.Hour()] || !s.month.values[int(t.Month())] {
+		return false
+	}
+	dom, dow := s.dom.values[t.Day()], s.dow.values[int(t.Weekday())]
+	// like cron, when both the day of month and day of week are restricted
+	// matching one of them is enough
+	if s.dom.restricted && s.dow.restricted {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+func parseSchedule(spec string) (*schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+	var err error
+	s := &schedule{}
+	for i, f := range []struct {
+		field  *field
+		lo, hi int
+	}{
+		{&s.minute, 0, 59},
+		{&s.hour, 0, 23},
+		{&s.dom, 1, 31},
+		{&s.month, 1, 12},
+		{&s.dow, 0, 7},
+	} {
+		if *f.field, err = parseField(fields[i], f.lo, f.hi); err != nil {
+			return nil, err
+		}
+	}
+	// sunday can be 0 or 7
+	if s.dow.values[7] {
+		s.dow.values[0] = true
+	}
+	return s, nil
+}
+
+// parseField parse a cron field: a list of *, numbers or ranges with an
+// optional step.
+func parseField(spec string, lo, hi int) (field, error) {
+	f := field{values: map[int]bool{}, restricted: spec != "*"}
+	for _, part := range strings.Split(spec, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return f, fmt.Errorf("invalid step in %q", part)
+			}
+			rng = part[:i]
+		}
+		start, end := lo, hi
+		if rng != "*" {
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return f, fmt.Errorf("invalid value %q", part)
+			}
+			end = start
+			if len(bounds) == 2 {
+				if end, err = strconv.Atoi(bounds[1]); err != nil {
+					return f, fmt.Errorf("invalid value %q", part)
+				}
+			} else if step > 1 {
+				end = hi
+			}
+		}
+		if start < lo || end > hi || start > end {
+			return f, fmt.Errorf("value %q out of range %d-%d", part, lo, hi)
+		}
+		for v := start; v <= end; v += step {
+			f.values[v] = true
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,168 @@
+package freeze
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// 2023-01-06 is a friday
+	friday := time.Date(2023, 1, 6, 18, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		spec    string
+		at      time.Time
+		want    bool
+		wantErr string
+	}{
+		{name: "wildcard", spec: "* * * * *", at: friday, want: true},
+		{name: "exact", spec: "30 18 6 1 5", at: friday, want: true},
+		{name: "range", spec: "0-30 17-19 * * 1-5", at: friday, want: true},
+		{name: "out of range", spec: "0-29 17-19 * * 1-5", at: friday, want: false},
+		{name: "step", spec: "*/15 * * * *", at: friday, want: true},
+		{name: "step from value", spec: "20/20 * * * *", at: friday, want: false},
+		{name: "list", spec: "0,30 18 * * *", at: friday, want: true},
+		{name: "weekend", spec: "* * * * 6,7", at: friday, want: false},
+		{name: "sunday as 7", spec: "* * * * 7", at: friday.Add(48 * time.Hour), want: true},
+		{name: "day of month or day of week", spec: "30 18 1 * 5", at: friday, want: true},
+		{name: "day of month and wildcard day of week", spec: "30 18 1 * *", at: friday, want: false},
+		{name: "not enough fields", spec: "* * * *", wantErr: "expected 5 fields, got 4"},
+		{name: "invalid value", spec: "a * * * *", wantErr: `invalid value "a"`},
+		{name: "invalid step", spec: "*/0 * * * *", wantErr: `invalid step in "*/0"`},
+		{name: "value too big", spec: "* 24 * * *", wantErr: `value "24" out of range 0-23`},
+		{name: "reversed range", spec: "* * * 5-2 *", wantErr: `value "5-2" out of range 1-12`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, err := parseSchedule(tt.spec)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, sched.matches(tt.at), tt.want)
+		})
+	}
+}
+
+func TestFrozen(t *testing.T) {
+	// 2023-01-07 is a saturday
+	saturday := time.Date(2023, 1, 7, 12, 0, 0, 0, time.UTC)
+	weekend := v1alpha1.FreezeWindow{Schedule: "0 18 * * 5", Duration: "62h"}
+	tests := []struct {
+		name       string
+		windows    []v1alpha1.FreezeWindow
+		now        time.Time
+		wantUntil  time.Time
+		wantAction string
+		wantErr    string
+	}{
+		{
+			name:       "in the window",
+			windows:    []v1alpha1.FreezeWindow{weekend},
+			now:        saturday,
+			wantUntil:  time.Date(2023, 1, 9, 8, 0, 0, 0, time.UTC),
+			wantAction: ActionSkip,
+		},
+		{
+			name:    "after the window",
+			windows: []v1alpha1.FreezeWindow{weekend},
+			now:     time.Date(2023, 1, 9, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "before the window",
+			windows: []v1alpha1.FreezeWindow{weekend},
+			now:     time.Date(2023, 1, 6, 17, 59, 0, 0, time.UTC),
+		},
+		{
+			name: "window ending last wins",
+			windows: []v1alpha1.FreezeWindow{
+				weekend,
+				{Schedule: "0 12 * * 6", Duration: "72h", Action: ActionQueue},
+				{Schedule: "0 10 * * 6", Duration: "4h"},
+			},
+			now:        saturday,
+			wantUntil:  time.Date(2023, 1, 10, 12, 0, 0, 0, time.UTC),
+			wantAction: ActionQueue,
+		},
+		{
+			name:       "timezone",
+			windows:    []v1alpha1.FreezeWindow{{Schedule: "0 13 * * 6", Duration: "1h", Timezone: "Europe/Paris"}},
+			now:        saturday,
+			wantUntil:  time.Date(2023, 1, 7, 13, 0, 0, 0, time.UTC),
+			wantAction: ActionSkip,
+		},
+		{
+			name:       "in a window not ending on a minute",
+			windows:    []v1alpha1.FreezeWindow{{Schedule: "0 10 * * 6", Duration: "5m30s"}},
+			now:        time.Date(2023, 1, 7, 10, 5, 20, 0, time.UTC),
+			wantUntil:  time.Date(2023, 1, 7, 10, 5, 30, 0, time.UTC),
+			wantAction: ActionSkip,
+		},
+		{
+			name:    "after a window not ending on a minute",
+			windows: []v1alpha1.FreezeWindow{{Schedule: "0 10 * * 6", Duration: "5m30s"}},
+			now:     time.Date(2023, 1, 7, 10, 5, 40, 0, time.UTC),
+		},
+		{
+			name:    "invalid duration",
+			windows: []v1alpha1.FreezeWindow{{Schedule: "* * * * *", Duration: "forever"}},
+			wantErr: `invalid freeze window duration "forever"`,
+		},
+		{
+			name:    "duration too long",
+			windows: []v1alpha1.FreezeWindow{{Schedule: "* * * * *", Duration: "1000h"}},
+			wantErr: "needs to be positive and at most",
+		},
+		{
+			name:    "invalid action",
+			windows: []v1alpha1.FreezeWindow{{Schedule: "* * * * *", Duration: "1h", Action: "ignore"}},
+			wantErr: `invalid freeze window action "ignore"`,
+		},
+		{
+			name:    "invalid timezone",
+			windows: []v1alpha1.FreezeWindow{{Schedule: "* * * * *", Duration: "1h", Timezone: "Nowhere/Land"}},
+			wantErr: `invalid freeze window timezone "Nowhere/Land"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Frozen(tt.windows, tt.now)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			if tt.wantAction == "" {
+				assert.Assert(t, got == nil)
+				return
+			}
+			assert.Assert(t, got != nil)
+			assert.Assert(t, got.Until.Equal(tt.wantUntil), got.Until)
+			assert.Equal(t, got.Action, tt.wantAction)
+		})
+	}
+}
+
+func TestParseWindows(t *testing.T) {
+	windows, err := ParseWindows(`
+- schedule: "0 18 * * 5"
+  duration: 62h
+  action: queue
+`)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, windows, []v1alpha1.FreezeWindow{{Schedule: "0 18 * * 5", Duration: "62h", Action: ActionQueue}})
+
+	windows, err = ParseWindows("")
+	assert.NilError(t, err)
+	assert.Equal(t, len(windows), 0)
+
+	_, err = ParseWindows("- schedule: \"0 18 * *\"\n  duration: 1h\n")
+	assert.ErrorContains(t, err, "invalid freeze window schedule")
+
+	_, err = ParseWindows("schedule: 1")
+	assert.ErrorContains(t, err, "cannot parse freeze windows")
+}
@@ -28,10 +28,18 @@ type FakePipelinesascodeV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakePipelinesascodeV1alpha1) PACSettings() v1alpha1.PACSettingsInterface {
+	return &FakePACSettings{c}
+}
+
 func (c *FakePipelinesascodeV1alpha1) Repositories(namespace string) v1alpha1.RepositoryInterface {
 	return &FakeRepositories{c, namespace}
 }
 
+func (c *FakePipelinesascodeV1alpha1) RepositoryPolicies() v1alpha1.RepositoryPolicyInterface {
+	return &FakeRepositoryPolicies{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakePipelinesascodeV1alpha1) RESTClient() rest.Interface {
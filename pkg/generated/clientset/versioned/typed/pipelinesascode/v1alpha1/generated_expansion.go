@@ -18,4 +18,8 @@ limitations under the License.
 
 package v1alpha1
 
+type PACSettingsExpansion interface{}
+
 type RepositoryExpansion interface{}
+
+type RepositoryPolicyExpansion interface{}
@@ -26,7 +26,9 @@ import (
 
 type PipelinesascodeV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	PACSettingsGetter
 	RepositoriesGetter
+	RepositoryPoliciesGetter
 }
 
 // PipelinesascodeV1alpha1Client is used to interact with features provided by the pipelinesascode.tekton.dev group.
@@ -34,10 +36,18 @@ type PipelinesascodeV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *PipelinesascodeV1alpha1Client) PACSettings() PACSettingsInterface {
+	return newPACSettings(c)
+}
+
 func (c *PipelinesascodeV1alpha1Client) Repositories(namespace string) RepositoryInterface {
 	return newRepositories(c, namespace)
 }
 
+func (c *PipelinesascodeV1alpha1Client) RepositoryPolicies() RepositoryPolicyInterface {
+	return newRepositoryPolicies(c)
+}
+
 // NewForConfig creates a new PipelinesascodeV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*PipelinesascodeV1alpha1Client, error) {
 	config := *c
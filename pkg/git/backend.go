@@ -0,0 +1,63 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+)
+
+const (
+	// BackendExec runs the git binary
+	BackendExec = "exec"
+	// BackendGoGit uses the go-git library, for the environments without the
+	// git binary
+	BackendGoGit = "go-git"
+
+	// BackendEnv is the environment variable selecting the backend at runtime,
+	// it overrides the default backend of the build
+	BackendEnv = "PAC_GIT_BACKEND"
+)
+
+// Backend returns the backend the functions of the package use, the one of
+// the PAC_GIT_BACKEND environment variable or the default one of the build.
+// The exec backend falls back to go-git when there is no git binary.
+func Backend() string {
+	switch backend := os.Getenv(BackendEnv); backend {
+	case BackendExec, BackendGoGit:
+		return backend
+	}
+	if defaultBackend == BackendExec {
+		if _, err := exec.LookPath("git"); err != nil {
+			return BackendGoGit
+		}
+	}
+	return defaultBackend
+}
+
+// GetGitInfo try to detect the current remote for this URL return the origin url transformed and the topdir
+func GetGitInfo(dir string) *Info {
+	if Backend() == BackendGoGit {
+		return goGitGetGitInfo(dir)
+	}
+	return execGetGitInfo(dir)
+}
+
+// ChangedFiles returns the files changed in the checkout since it diverged
+// from baseRef, the commits made since then and the uncommitted changes. The
+// baseRef can be @{upstream} for the upstream branch of the current branch.
+func ChangedFiles(dir, baseRef string) ([]string, error) {
+	if Backend() == BackendGoGit {
+		return goGitChangedFiles(dir, baseRef)
+	}
+	return execChangedFiles(dir, baseRef)
+}
+
+// SparseFetch fetch only the last commit of the revision and write only the
+// directory of the path to dir, the other files of the repository are not
+// checked out. It returns false when the revision has no such directory.
+func SparseFetch(ctx context.Context, dir string, opts SparseFetchOpts) (bool, error) {
+	if Backend() == BackendGoGit {
+		return goGitSparseFetch(ctx, dir, opts)
+	}
+	return execSparseFetch(ctx, dir, opts)
+}
@@ -0,0 +1,7 @@
+//go:build !gogit
+
+package git
+
+// defaultBackend is the git binary, build with the gogit tag to use go-git
+// by default
+const defaultBackend = BackendExec
@@ -0,0 +1,6 @@
+//go:build gogit
+
+package git
+
+// defaultBackend is go-git with the gogit build tag
+const defaultBackend = BackendGoGit
@@ -12,6 +12,12 @@ type Info struct {
 	TopLevelPath string
 	SHA          string
 	Branch       string
+	// Tag is the most recent tag reachable from the current commit
+	Tag string
+	// Dirty is set when the checkout has uncommitted changes
+	Dirty bool
+	// Remotes are the urls of all the remotes by their name
+	Remotes map[string]string
 }
 
 func RunGit(dir string, args ...string) (string, error) {
@@ -35,8 +41,8 @@ func RunGit(dir string, args ...string) (string, error) {
 	return output.String(), nil
 }
 
-// GetGitInfo try to detect the current remote for this URL return the origin url transformed and the topdir
-func GetGitInfo(dir string) *Info {
+// execGetGitInfo is GetGitInfo with the git binary
+func execGetGitInfo(dir string) *Info {
 	gitURL, err := RunGit(dir, "remote", "get-url", "origin")
 	if err != nil {
 		gitURL, err = RunGit(dir, "remote", "get-url", "upstream")
@@ -44,16 +50,7 @@ func GetGitInfo(dir string) *Info {
 			return &Info{}
 		}
 	}
-	gitURL = strings.TrimSpace(gitURL)
-	gitURL = strings.TrimSuffix(gitURL, ".git")
-
-	// convert github and probably others ssh access format into https
-	// i think it only fails with bitbucket server
-	if strings.HasPrefix(gitURL, "git@") {
-		sp := strings.Split(gitURL, ":")
-		prefix := strings.ReplaceAll(sp[0], "git@", "https://")
-		gitURL = fmt.Sprintf("%s/%s", prefix, strings.Join(sp[1:], ":"))
-	}
+	gitURL = normalizeURL(gitURL)
 
 	brootdir, err := RunGit(dir, "rev-parse", "--show-toplevel")
 	if err != nil {
@@ -70,10 +67,72 @@ func GetGitInfo(dir string) *Info {
 		return &Info{}
 	}
 
+	// there is no tag when it fails
+	tag, _ := RunGit(dir, "describe", "--tags", "--abbrev=0")
+
+	status, err := RunGit(dir, "status", "--porcelain")
+	if err != nil {
+		return &Info{}
+	}
+
 	return &Info{
 		URL:          gitURL,
 		TopLevelPath: strings.TrimSpace(brootdir),
 		SHA:          strings.TrimSpace(sha),
 		Branch:       strings.TrimSpace(headbranch),
+		Tag:          strings.TrimSpace(tag),
+		Dirty:        strings.TrimSpace(status) != "",
+		Remotes:      getRemotes(dir),
+	}
+}
+
+// normalizeURL remove the .git suffix of a remote url and convert it to https
+// when it's a ssh one
+func normalizeURL(gitURL string) string {
+	gitURL = strings.TrimSpace(gitURL)
+	gitURL = strings.TrimSuffix(gitURL, ".git")
+
+	// convert github and probably others ssh access format into https
+	// i think it only fails with bitbucket server
+	if strings.HasPrefix(gitURL, "git@") {
+		sp := strings.Split(gitURL, ":")
+		prefix := strings.ReplaceAll(sp[0], "git@", "https://")
+		gitURL = fmt.Sprintf("%s/%s", prefix, strings.Join(sp[1:], ":"))
+	}
+	return gitURL
+}
+
+func getRemotes(dir string) map[string]string {
+	remotes := map[string]string{}
+	output, err := RunGit(dir, "remote")
+	if err != nil {
+		return remotes
+	}
+	for _, name := range strings.Fields(output) {
+		remoteURL, err := RunGit(dir, "remote", "get-url", name)
+		if err != nil {
+			continue
+		}
+		remotes[name] = normalizeURL(remoteURL)
+	}
+	return remotes
+}
+
+// execChangedFiles is ChangedFiles with the git binary
+func execChangedFiles(dir, baseRef string) ([]string, error) {
+	mergeBase, err := RunGit(dir, "merge-base", baseRef, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	output, err := RunGit(dir, "diff", "--name-only", strings.TrimSpace(mergeBase))
+	if err != nil {
+		return nil, err
+	}
+	files := []string{}
+	for _, file := range strings.Split(output, "\n") {
+		if file = strings.TrimSpace(file); file != "" {
+			files = append(files, file)
+		}
 	}
+	return files, nil
 }
@@ -1,7 +1,9 @@
 package git
 
 import (
+	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -22,6 +24,7 @@ func TestGetGitInfo(t *testing.T) {
 		gitURL       string
 		remoteTarget string
 		branchName   string
+		dirty        bool
 	}{
 		{
 			name:         "Get git info",
@@ -63,39 +66,116 @@ func TestGetGitInfo(t *testing.T) {
 				Branch: "targetheadbranch",
 			},
 		},
+		{
+			name:         "Get tag, remotes and dirty",
+			gitURL:       "git@github.com:chmouel/demo.git",
+			remoteTarget: "origin",
+			dirty:        true,
+			want: Info{
+				Tag:     "v1.0.0",
+				Dirty:   true,
+				Remotes: map[string]string{"origin": "https://github.com/chmouel/demo"},
+			},
+		},
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// create temporary file
-			tmpFile := fs.NewFile(t, "gitconfig-")
-			defer tmpFile.Remove()
-			defer env.PatchAll(t, map[string]string{
-				"HOME":  tmpFile.Path(),
-				"PATH":  "/usr/local/bin:/usr/bin:/bin:/usr/sbin:/sbin",
-				"EMAIL": "foo@foo.com",
-			})()
+	for _, backend := range []string{BackendExec, BackendGoGit} {
+		for _, tt := range tests {
+			t.Run(backend+"/"+tt.name, func(t *testing.T) {
+				// create temporary file
+				tmpFile := fs.NewFile(t, "gitconfig-")
+				defer tmpFile.Remove()
+				defer env.PatchAll(t, map[string]string{
+					"HOME":     tmpFile.Path(),
+					"PATH":     "/usr/local/bin:/usr/bin:/bin:/usr/sbin:/sbin",
+					"EMAIL":    "foo@foo.com",
+					BackendEnv: backend,
+				})()
 
-			nd := fs.NewDir(t, "TestGetGitInfo")
-			defer nd.Remove()
-			gitDir := nd.Path()
-			var err error
+				nd := fs.NewDir(t, "TestGetGitInfo")
+				defer nd.Remove()
+				gitDir := nd.Path()
+				var err error
 
-			_, err = RunGit(gitDir, "init")
-			assert.NilError(t, err)
-			_, err = RunGit(gitDir, "remote", "add", tt.remoteTarget, tt.gitURL)
-			assert.NilError(t, err)
-			_, err = RunGit(gitDir, "commit", "--allow-empty", "-m", "Empty Commmit")
+				_, err = RunGit(gitDir, "init")
+				assert.NilError(t, err)
+				_, err = RunGit(gitDir, "remote", "add", tt.remoteTarget, tt.gitURL)
+				assert.NilError(t, err)
+				_, err = RunGit(gitDir, "commit", "--allow-empty", "-m", "Empty Commmit")
+				assert.NilError(t, err)
+				if tt.want.Branch != "" {
+					_, _ = RunGit(gitDir, "checkout", "-b", tt.want.Branch)
+				}
+				if tt.want.Tag != "" {
+					_, err = RunGit(gitDir, "tag", tt.want.Tag)
+					assert.NilError(t, err)
+				}
+				if tt.dirty {
+					assert.NilError(t, os.WriteFile(filepath.Join(gitDir, "file"), []byte("hello"), 0o600))
+				}
+				gitinfo := GetGitInfo(gitDir)
+				assert.Equal(t, gitinfo.Tag, tt.want.Tag)
+				assert.Equal(t, gitinfo.Dirty, tt.want.Dirty)
+				if tt.want.Remotes != nil {
+					assert.DeepEqual(t, gitinfo.Remotes, tt.want.Remotes)
+				}
+				if tt.want.URL != "" {
+					assert.Equal(t, gitinfo.URL, tt.want.URL)
+				}
+				if tt.want.Branch != "" {
+					assert.Equal(t, gitinfo.Branch, tt.want.Branch)
+				}
+				assert.Equal(t, gitinfo.TopLevelPath, gitDir)
+				assert.Equal(t, len(gitinfo.SHA), 40)
+			})
+		}
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	gitPath, _ := exec.LookPath("git")
+	if gitPath == "" {
+		t.Skip("could not find the git binary in path, skipping test")
+		return
+	}
+	tmpFile := fs.NewFile(t, "gitconfig-")
+	defer tmpFile.Remove()
+	defer env.PatchAll(t, map[string]string{
+		"HOME":  tmpFile.Path(),
+		"PATH":  "/usr/local/bin:/usr/bin:/bin:/usr/sbin:/sbin",
+		"EMAIL": "foo@foo.com",
+	})()
+
+	nd := fs.NewDir(t, "TestChangedFiles", fs.WithFile("README.md", "hello"))
+	defer nd.Remove()
+	gitDir := nd.Path()
+	for _, args := range [][]string{
+		{"init"},
+		{"checkout", "-b", "main"},
+		{"add", "."},
+		{"commit", "-m", "Initial commit"},
+		{"checkout", "-b", "feature"},
+	} {
+		_, err := RunGit(gitDir, args...)
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, os.MkdirAll(filepath.Join(gitDir, ".tekton"), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(gitDir, ".tekton", "pr.yaml"), []byte("---"), 0o600))
+	for _, args := range [][]string{{"add", "."}, {"commit", "-m", "Add a PipelineRun"}} {
+		_, err := RunGit(gitDir, args...)
+		assert.NilError(t, err)
+	}
+	// uncommitted change
+	assert.NilError(t, os.WriteFile(filepath.Join(gitDir, "README.md"), []byte("hello world"), 0o600))
+
+	for _, backend := range []string{BackendExec, BackendGoGit} {
+		t.Run(backend, func(t *testing.T) {
+			defer env.Patch(t, BackendEnv, backend)()
+			files, err := ChangedFiles(gitDir, "main")
 			assert.NilError(t, err)
-			if tt.want.Branch != "" {
-				_, _ = RunGit(gitDir, "checkout", "-b", tt.want.Branch)
-			}
-			gitinfo := GetGitInfo(gitDir)
-			if tt.want.URL != "" {
-				assert.Equal(t, gitinfo.URL, tt.want.URL)
-			}
-			if tt.want.Branch != "" {
-				assert.Equal(t, gitinfo.Branch, tt.want.Branch)
-			}
+			assert.DeepEqual(t, files, []string{".tekton/pr.yaml", "README.md"})
+
+			_, err = ChangedFiles(gitDir, "unknown")
+			assert.Assert(t, err != nil)
 		})
 	}
 }
@@ -0,0 +1,302 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// fetchedRef is where the revision fetched with go-git is stored
+const fetchedRef = "refs/pac/fetch"
+
+var reSHA = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+func goGitOpen(dir string) (*gogit.Repository, error) {
+	return gogit.PlainOpenWithOptions(dir, &gogit.PlainOpenOptions{DetectDotGit: true})
+}
+
+// goGitGetGitInfo is GetGitInfo with go-git
+func goGitGetGitInfo(dir string) *Info {
+	repo, err := goGitOpen(dir)
+	if err != nil {
+		return &Info{}
+	}
+	remotes := goGitRemotes(repo)
+	gitURL, ok := remotes["origin"]
+	if !ok {
+		if gitURL, ok = remotes["upstream"]; !ok {
+			return &Info{}
+		}
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return &Info{}
+	}
+	toplevel, err := filepath.Abs(worktree.Filesystem.Root())
+	if err != nil {
+		return &Info{}
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return &Info{}
+	}
+	// like git rev-parse --abbrev-ref HEAD on a detached HEAD
+	headbranch := "HEAD"
+	if head.Name().IsBranch() {
+		headbranch = head.Name().Short()
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return &Info{}
+	}
+
+	return &Info{
+		URL:          gitURL,
+		TopLevelPath: toplevel,
+		SHA:          head.Hash().String(),
+		Branch:       headbranch,
+		Tag:          goGitLastTag(repo, head.Hash()),
+		Dirty:        !status.IsClean(),
+		Remotes:      remotes,
+	}
+}
+
+func goGitRemotes(repo *gogit.Repository) map[string]string {
+	remotes := map[string]string{}
+	list, err := repo.Remotes()
+	if err != nil {
+		return remotes
+	}
+	for _, remote := range list {
+		if urls := remote.Config().URLs; len(urls) > 0 {
+			remotes[remote.Config().Name] = normalizeURL(urls[0])
+		}
+	}
+	return remotes
+}
+
+// goGitLastTag returns the first tag found walking the history from the
+// commit, the lightweight and the annotated ones
+func goGitLastTag(repo *gogit.Repository, from plumbing.Hash) string {
+	tagged := map[plumbing.Hash][]string{}
+	tags, err := repo.Tags()
+	if err != nil {
+		return ""
+	}
+	_ = tags.ForEach(func(ref *plumbing.Reference) error {
+		target := ref.Hash()
+		if tag, err := repo.TagObject(target); err == nil {
+			if commit, err := tag.Commit(); err == nil {
+				target = commit.Hash
+			}
+		}
+		tagged[target] = append(tagged[target], ref.Name().Short())
+		return nil
+	})
+	if len(tagged) == 0 {
+		return ""
+	}
+
+	commits, err := repo.Log(&gogit.LogOptions{From: from, Order: gogit.LogOrderCommitterTime})
+	if err != nil {
+		return ""
+	}
+	var last string
+	errFound := errors.New("found")
+	_ = commits.ForEach(func(commit *object.Commit) error {
+		if names, ok := tagged[commit.Hash]; ok {
+			sort.Strings(names)
+			last = names[0]
+			return errFound
+		}
+		return nil
+	})
+	return last
+}
+
+// goGitResolve resolve a revision to a commit, @{upstream} is the remote branch
+// the current branch tracks
+func goGitResolve(repo *gogit.Repository, revision string) (plumbing.Hash, error) {
+	if revision != "@{upstream}" && revision != "@{u}" {
+		hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("cannot resolve %s: %w", revision, err)
+		}
+		return *hash, nil
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	branch, ok := cfg.Branches[head.Name().Short()]
+	if !ok || branch.Remote == "" || branch.Merge == "" {
+		return plumbing.ZeroHash, fmt.Errorf("the branch %s has no upstream branch", head.Name().Short())
+	}
+	ref, err := repo.Reference(plumbing.NewRemoteReferenceName(branch.Remote, branch.Merge.Short()), true)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("cannot resolve the upstream branch of %s: %w", head.Name().Short(), err)
+	}
+	return ref.Hash(), nil
+}
+
+// goGitChangedFiles is ChangedFiles with go-git
+func goGitChangedFiles(dir, baseRef string) ([]string, error) {
+	repo, err := goGitOpen(dir)
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	baseHash, err := goGitResolve(repo, baseRef)
+	if err != nil {
+		return nil, err
+	}
+	baseCommit, err := repo.CommitObject(baseHash)
+	if err != nil {
+		return nil, err
+	}
+	mergeBases, err := baseCommit.MergeBase(headCommit)
+	if err != nil {
+		return nil, err
+	}
+	if len(mergeBases) == 0 {
+		return nil, fmt.Errorf("no merge-base between %s and HEAD", baseRef)
+	}
+
+	baseTree, err := mergeBases[0].Tree()
+	if err != nil {
+		return nil, err
+	}
+	headTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := object.DiffTree(baseTree, headTree)
+	if err != nil {
+		return nil, err
+	}
+	changed := map[string]bool{}
+	for _, change := range changes {
+		for _, name := range []string{change.From.Name, change.To.Name} {
+			if name != "" {
+				changed[name] = true
+			}
+		}
+	}
+
+	// the uncommitted changes to the tracked files
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, err
+	}
+	for name, fileStatus := range status {
+		if fileStatus.Worktree == gogit.Untracked {
+			continue
+		}
+		if fileStatus.Staging != gogit.Unmodified || fileStatus.Worktree != gogit.Unmodified {
+			changed[name] = true
+		}
+	}
+
+	files := make([]string, 0, len(changed))
+	for name := range changed {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// goGitSparseFetch is SparseFetch with go-git, the commit is fetched in memory
+// and only the files of the path are written to dir
+func goGitSparseFetch(ctx context.Context, dir string, opts SparseFetchOpts) (bool, error) {
+	path := strings.Trim(opts.Path, "/")
+	repo, err := gogit.Init(memory.NewStorage(), nil)
+	if err != nil {
+		return false, err
+	}
+	remote, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{opts.CloneURL}})
+	if err != nil {
+		return false, err
+	}
+
+	source := opts.Revision
+	switch {
+	case reSHA.MatchString(source):
+	case strings.HasPrefix(source, "refs/"):
+		source = "+" + source
+	default:
+		source = "+" + plumbing.NewBranchReferenceName(source).String()
+	}
+	fetchOpts := &gogit.FetchOptions{
+		RefSpecs: []config.RefSpec{config.RefSpec(source + ":" + fetchedRef)},
+		Depth:    1,
+		Tags:     gogit.NoTags,
+	}
+	if opts.Token != "" {
+		fetchOpts.Auth = &githttp.BasicAuth{Username: opts.User, Password: opts.Token}
+	}
+	if err := remote.FetchContext(ctx, fetchOpts); err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return false, fmt.Errorf("cannot fetch %s from %s: %w", opts.Revision, opts.CloneURL, err)
+	}
+
+	ref, err := repo.Reference(fetchedRef, true)
+	if err != nil {
+		return false, err
+	}
+	commit, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return false, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return false, err
+	}
+	subtree, err := tree.Tree(path)
+	if errors.Is(err, object.ErrDirectoryNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	err = subtree.Files().ForEach(func(file *object.File) error {
+		contents, err := file.Contents()
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(path), filepath.FromSlash(file.Name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(target, []byte(contents), 0o600)
+	})
+	return err == nil, err
+}
@@ -0,0 +1,134 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// SparseFetchOpts are the options of a shallow and sparse fetch of a
+// directory of a repository
+type SparseFetchOpts struct {
+	// CloneURL is the url of the repository
+	CloneURL string
+	// Revision is the commit or the branch to fetch
+	Revision string
+	// Path is the directory to checkout, relative to the root of the
+	// repository
+	Path string
+	// User and Token are the basic auth credentials to fetch with, they are
+	// passed to git in its environment and not on its command line
+	User  string
+	Token string
+}
+
+// runGitEnv runs git with additional environment variables, it fails when the
+// git binary cannot be found.
+func runGitEnv(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		return "", fmt.Errorf("cannot find the git binary: %w", err)
+	}
+	c := exec.CommandContext(ctx, gitPath, args...)
+	c.Dir = dir
+	c.Env = append(os.Environ(), env...)
+	var output bytes.Buffer
+	c.Stderr = &output
+	c.Stdout = &output
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("error running, %s, output: %s error: %w", args, output.String(), err)
+	}
+	return output.String(), nil
+}
+
+// execSparseFetch is SparseFetch with the git binary, a checkout of the
+// directory of the path with a sparse checkout
+func execSparseFetch(ctx context.Context, dir string, opts SparseFetchOpts) (bool, error) {
+	path := strings.Trim(opts.Path, "/")
+	// never prompt for the credentials, with the credentials in the
+	// environment of git rather than on its command line
+	env := []string{"GIT_TERMINAL_PROMPT=0"}
+	if opts.Token != "" {
+		auth := base64.StdEncoding.EncodeToString([]byte(opts.User + ":" + opts.Token))
+		env = append(env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+auth)
+	}
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"remote", "add", "origin", opts.CloneURL},
+		{"config", "core.sparseCheckout", "true"},
+	} {
+		if _, err := runGitEnv(ctx, dir, env, args...); err != nil {
+			return false, err
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".git", "info", "sparse-checkout"), []byte("/"+path+"/\n"), 0o600); err != nil {
+		return false, err
+	}
+	// the blobs are only downloaded for the files of the checkout, when the
+	// server supports partial clones
+	if _, err := runGitEnv(ctx, dir, env, "fetch", "-q", "--depth=1", "--filter=blob:none", "--no-tags", "origin", opts.Revision); err != nil {
+		return false, err
+	}
+	tree, err := runGitEnv(ctx, dir, env, "ls-tree", "-d", "FETCH_HEAD", path)
+	if err != nil {
+		return false, err
+	}
+	if strings.TrimSpace(tree) == "" {
+		return false, nil
+	}
+	if _, err := runGitEnv(ctx, dir, env, "checkout", "-q", "FETCH_HEAD"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ConcatYamlFiles concat all the yaml files of a directory and of its sub
+// directories as one multi document yaml string.
+func ConcatYamlFiles(dir string) (string, error) {
+	var allTemplates string
+	err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() || (filepath.Ext(path) != ".yaml" && filepath.Ext(path) != ".yml") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if allTemplates != "" && !strings.HasPrefix(string(data), "---") {
+			allTemplates += "---"
+		}
+		allTemplates += "\n" + string(data) + "\n"
+		return nil
+	})
+	return allTemplates, err
+}
+
+// FetchTektonDir returns the yaml files of the directory of the path at the
+// revision as one multi document yaml string, fetched with a shallow and sparse
+// git fetch in a temporary directory. It returns an empty string when the
+// revision has no such directory.
+func FetchTektonDir(ctx context.Context, opts SparseFetchOpts) (string, error) {
+	dir, err := os.MkdirTemp("", "pac-tekton-dir-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	found, err := SparseFetch(ctx, dir, opts)
+	if err != nil || !found {
+		return "", err
+	}
+	return ConcatYamlFiles(filepath.Join(dir, filepath.FromSlash(strings.Trim(opts.Path, "/"))))
+}
@@ -0,0 +1,62 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/env"
+	"gotest.tools/v3/fs"
+)
+
+func TestFetchTektonDir(t *testing.T) {
+	gitPath, _ := exec.LookPath("git")
+	if gitPath == "" {
+		t.Skip("could not find the git binary in path, skipping test")
+		return
+	}
+	tmpFile := fs.NewFile(t, "gitconfig-")
+	defer tmpFile.Remove()
+	defer env.PatchAll(t, map[string]string{
+		"HOME":  tmpFile.Path(),
+		"PATH":  "/usr/local/bin:/usr/bin:/bin:/usr/sbin:/sbin",
+		"EMAIL": "foo@foo.com",
+	})()
+
+	nd := fs.NewDir(t, "TestFetchTektonDir",
+		fs.WithFile("README.md", "not fetched"),
+		fs.WithDir(".tekton",
+			fs.WithFile("pull-request.yaml", "kind: PipelineRun"),
+			fs.WithFile("notes.txt", "not a template"),
+			fs.WithDir("tasks", fs.WithFile("task.yml", "---\nkind: Task"))))
+	defer nd.Remove()
+	for _, args := range [][]string{
+		{"init"},
+		{"checkout", "-b", "main"},
+		{"add", "."},
+		{"commit", "-m", "Initial commit"},
+	} {
+		_, err := RunGit(nd.Path(), args...)
+		assert.NilError(t, err)
+	}
+
+	ctx := context.Background()
+	for _, backend := range []string{BackendExec, BackendGoGit} {
+		t.Run(backend, func(t *testing.T) {
+			defer env.Patch(t, BackendEnv, backend)()
+			got, err := FetchTektonDir(ctx, SparseFetchOpts{CloneURL: "file://" + nd.Path(), Revision: "main", Path: ".tekton"})
+			assert.NilError(t, err)
+			assert.Equal(t, got, "\nkind: PipelineRun\n\n---\nkind: Task\n")
+			assert.Assert(t, !strings.Contains(got, "not"))
+
+			got, err = FetchTektonDir(ctx, SparseFetchOpts{CloneURL: "file://" + nd.Path(), Revision: "main", Path: "sub/.tekton"})
+			assert.NilError(t, err)
+			assert.Equal(t, got, "")
+
+			_, err = FetchTektonDir(ctx, SparseFetchOpts{CloneURL: "file://" + nd.Path(), Revision: "unknown", Path: ".tekton"})
+			assert.ErrorContains(t, err, "fetch")
+		})
+	}
+}
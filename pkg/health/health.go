@@ -0,0 +1,227 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/reconciler"
+)
+
+// defaultStuckAfter is how long a work queue can stay non-empty without any
+// of its items being taken off before the component is reported as not live
+const defaultStuckAfter = 5 * time.Minute
+
+// Check is a readiness check, it returns an error when the component is not
+// ready
+type Check func(ctx context.Context) error
+
+type informer struct {
+	name      string
+	hasSynced cache.InformerSynced
+}
+
+type check struct {
+	name  string
+	check Check
+}
+
+type workQueue struct {
+	name   string
+	length func() int
+	// since is when the queue has been seen non-empty
+	since time.Time
+	// lastDequeue is when an item has been taken off the queue for the last
+	// time
+	lastDequeue time.Time
+}
+
+// Checker serves the readiness and the liveness probes of a component. The
+// component is ready when all its informers have synced and all its checks
+// pass, it is live as long as none of its work queues is stuck.
+type Checker struct {
+	mu         sync.Mutex
+	informers  []informer
+	checks     []check
+	queues     []*workQueue
+	stuckAfter time.Duration
+	now        func() time.Time
+}
+
+func NewChecker() *Checker {
+	return &Checker{
+		stuckAfter: defaultStuckAfter,
+		now:        time.Now,
+	}
+}
+
+// AddInformer add an informer which needs to have synced for the component to
+// be ready
+func (c *Checker) AddInformer(name string, hasSynced cache.InformerSynced) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.informers = append(c.informers, informer{name: name, hasSynced: hasSynced})
+}
+
+// Informers returns the number of informers added
+func (c *Checker) Informers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.informers)
+}
+
+// AddCheck add a check which needs to pass for the component to be ready
+func (c *Checker) AddCheck(name string, fn Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, check{name: name, check: fn})
+}
+
+// AddWorkQueue add the work queue of a controller which should not get stuck
+// for the component to be live, its reconciler is wrapped to record when the
+// items are taken off the queue.
+func (c *Checker) AddWorkQueue(name string, impl *controller.Impl) {
+	queue := c.addWorkQueue(name, impl.WorkQueue().Len)
+	impl.Reconciler = trackDequeue(impl.Reconciler, func() { c.dequeued(queue) })
+}
+
+func (c *Checker) addWorkQueue(name string, length func() int) *workQueue {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	queue := &workQueue{name: name, length: length}
+	c.queues = append(c.queues, queue)
+	return queue
+}
+
+func (c *Checker) dequeued(queue *workQueue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	queue.lastDequeue = c.now()
+}
+
+type dequeueTracker struct {
+	controller.Reconciler
+	dequeued func()
+}
+
+func (t *dequeueTracker) Reconcile(ctx context.Context, key string) error {
+	t.dequeued()
+	return t.Reconciler.Reconcile(ctx, key)
+}
+
+type leaderAwareDequeueTracker struct {
+	*dequeueTracker
+	reconciler.LeaderAware
+}
+
+// trackDequeue calls dequeued each time r is given a key, the controller stops
+// promoting and demoting its reconciler if the wrapper is not leader aware
+// when it is.
+func trackDequeue(r controller.Reconciler, dequeued func()) controller.Reconciler {
+	tracker := &dequeueTracker{Reconciler: r, dequeued: dequeued}
+	if la, ok := r.(reconciler.LeaderAware); ok {
+		return &leaderAwareDequeueTracker{dequeueTracker: tracker, LeaderAware: la}
+	}
+	return tracker
+}
+
+// Ready returns the reasons why the component is not ready, none when it is
+func (c *Checker) Ready(ctx context.Context) []string {
+	c.mu.Lock()
+	informers := append([]informer{}, c.informers...)
+	checks := append([]check{}, c.checks...)
+	c.mu.Unlock()
+
+	reasons := []string{}
+	for _, informer := range informers {
+		if !informer.hasSynced() {
+			reasons = append(reasons, fmt.Sprintf("informer %s has not synced", informer.name))
+		}
+	}
+	// the checks are run outside of the lock as they can be slow
+	for _, check := range checks {
+		if err := check.check(ctx); err != nil {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", check.name, err.Error()))
+		}
+	}
+	return reasons
+}
+
+// Live returns the reasons why the component is not live, none when it is. A
+// work queue is stuck when none of its items has been taken off for a while,
+// its length says nothing as items keep being added to a busy queue.
+func (c *Checker) Live() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	reasons := []string{}
+	for _, queue := range c.queues {
+		length := queue.length()
+		if length == 0 {
+			queue.since = time.Time{}
+			continue
+		}
+		if queue.since.IsZero() {
+			queue.since = now
+		}
+		progress := queue.since
+		if queue.lastDequeue.After(progress) {
+			progress = queue.lastDequeue
+		}
+		if now.Sub(progress) > c.stuckAfter {
+			reasons = append(reasons, fmt.Sprintf("work queue %s is stuck with %d items, none taken off since %s", queue.name, length,
+				progress.Format(time.RFC3339)))
+		}
+	}
+	return reasons
+}
+
+func writeProbe(w http.ResponseWriter, reasons []string) {
+	if len(reasons) > 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprint(w, strings.Join(reasons, "\n"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprint(w, "ok")
+}
+
+// ReadyHandler serves the readiness probe
+func (c *Checker) ReadyHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeProbe(w, c.Ready(r.Context()))
+	}
+}
+
+// KubernetesCheck checks the component can reach the API server
+func KubernetesCheck(kube kubernetes.Interface) Check {
+	return func(context.Context) error {
+		if _, err := kube.Discovery().ServerVersion(); err != nil {
+			return fmt.Errorf("cannot reach the kubernetes api: %w", err)
+		}
+		return nil
+	}
+}
+
+// Register serves the probes on a mux, on /readyz and /healthz and on the
+// /ready and /live paths used by the older deployments
+func (c *Checker) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/readyz", c.ReadyHandler())
+	mux.HandleFunc("/ready", c.ReadyHandler())
+	mux.HandleFunc("/healthz", c.LiveHandler())
+	mux.HandleFunc("/live", c.LiveHandler())
+}
+
+// LiveHandler serves the liveness probe
+func (c *Checker) LiveHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeProbe(w, c.Live())
+	}
+}
@@ -0,0 +1,151 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"knative.dev/pkg/reconciler"
+)
+
+func TestReady(t *testing.T) {
+	synced := false
+	var checkErr error
+	checker := NewChecker()
+	checker.AddInformer("pipelineruns", func() bool { return synced })
+	checker.AddCheck("credentials", func(context.Context) error { return checkErr })
+	assert.Equal(t, checker.Informers(), 1)
+
+	checkErr = fmt.Errorf("no credential")
+	assert.DeepEqual(t, checker.Ready(context.Background()), []string{
+		"informer pipelineruns has not synced",
+		"credentials: no credential",
+	})
+
+	rec := httptest.NewRecorder()
+	checker.ReadyHandler()(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, rec.Code, http.StatusServiceUnavailable)
+	assert.Equal(t, rec.Body.String(), "informer pipelineruns has not synced\ncredentials: no credential")
+
+	synced, checkErr = true, nil
+	rec = httptest.NewRecorder()
+	checker.ReadyHandler()(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Equal(t, rec.Body.String(), "ok")
+}
+
+func TestLive(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	length := 0
+	checker := NewChecker()
+	checker.now = func() time.Time { return now }
+	queue := checker.addWorkQueue("pipelineruns", func() int { return length })
+
+	tests := []struct {
+		name     string
+		length   int
+		elapsed  time.Duration
+		dequeued bool
+		want     []string
+	}{
+		{name: "empty", length: 0},
+		{name: "items added", length: 2, elapsed: time.Minute},
+		{name: "getting longer but moving", length: 5, elapsed: 4 * time.Minute, dequeued: true},
+		{name: "not moving yet", length: 5, elapsed: 4 * time.Minute},
+		{
+			name:    "stuck",
+			length:  5,
+			elapsed: 2 * time.Minute,
+			want:    []string{"work queue pipelineruns is stuck with 5 items, none taken off since 2022-01-01T00:05:00Z"},
+		},
+		{name: "emptied", length: 0, elapsed: time.Minute},
+		{name: "refilled", length: 1, elapsed: time.Minute},
+	}
+	for _, tt := range tests {
+		now = now.Add(tt.elapsed)
+		length = tt.length
+		if tt.dequeued {
+			checker.dequeued(queue)
+		}
+		got := checker.Live()
+		if tt.want == nil {
+			assert.Equal(t, len(got), 0, "%s: %v", tt.name, got)
+			continue
+		}
+		assert.DeepEqual(t, got, tt.want)
+		rec := httptest.NewRecorder()
+		checker.LiveHandler()(rec, httptest.NewRequest(http.MethodGet, "/live", nil))
+		assert.Equal(t, rec.Code, http.StatusServiceUnavailable, tt.name)
+	}
+}
+
+type fakeReconciler struct {
+	keys []string
+}
+
+func (f *fakeReconciler) Reconcile(_ context.Context, key string) error {
+	f.keys = append(f.keys, key)
+	return nil
+}
+
+type fakeLeaderAwareReconciler struct {
+	fakeReconciler
+	reconciler.LeaderAwareFuncs
+}
+
+func TestTrackDequeue(t *testing.T) {
+	dequeued := 0
+	r := &fakeReconciler{}
+	tracked := trackDequeue(r, func() { dequeued++ })
+	assert.NilError(t, tracked.Reconcile(context.Background(), "ns/name"))
+	assert.Equal(t, dequeued, 1)
+	assert.DeepEqual(t, r.keys, []string{"ns/name"})
+	_, leaderAware := tracked.(reconciler.LeaderAware)
+	assert.Assert(t, !leaderAware)
+
+	tracked = trackDequeue(&fakeLeaderAwareReconciler{}, func() { dequeued++ })
+	_, leaderAware = tracked.(reconciler.LeaderAware)
+	assert.Assert(t, leaderAware, "the leader awareness of the reconciler should be kept")
+}
+
+func TestKubernetesCheck(t *testing.T) {
+	serverUp := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !serverUp {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `{"major": "1", "minor": "25"}`)
+	}))
+	defer server.Close()
+	kube, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	assert.NilError(t, err)
+
+	check := KubernetesCheck(kube)
+	assert.NilError(t, check(context.Background()))
+	serverUp = false
+	assert.ErrorContains(t, check(context.Background()), "cannot reach the kubernetes api")
+}
+
+func TestRegister(t *testing.T) {
+	checker := NewChecker()
+	checker.AddCheck("settings", func(context.Context) error { return fmt.Errorf("not loaded") })
+	mux := http.NewServeMux()
+	checker.Register(mux)
+	for path, code := range map[string]int{
+		"/readyz":  http.StatusServiceUnavailable,
+		"/ready":   http.StatusServiceUnavailable,
+		"/healthz": http.StatusOK,
+		"/live":    http.StatusOK,
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, rec.Code, code, path)
+	}
+}
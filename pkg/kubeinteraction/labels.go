@@ -19,6 +19,17 @@ const (
 	StateFailed    = "failed"
 )
 
+// OwnedByController returns whether the PipelineRun with those labels has been
+// created by the controller, the PipelineRuns created before the controllers
+// were named belong to the default one.
+func OwnedByController(labels map[string]string, controllerName string) bool {
+	owner, ok := labels[keys.Controller]
+	if !ok || owner == "" {
+		return controllerName == info.DefaultControllerName
+	}
+	return owner == formatting.K8LabelsCleanup(controllerName)
+}
+
 func AddLabelsAndAnnotations(event *info.Event, pipelineRun *tektonv1beta1.PipelineRun, repo *apipac.Repository, providerinfo *info.ProviderConfig) {
 	// Add labels on the soon to be created pipelinerun so UI/CLI can easily
 	// query them.
@@ -34,6 +45,7 @@ func AddLabelsAndAnnotations(event *info.Event, pipelineRun *tektonv1beta1.Pipel
 		keys.Repository:                formatting.K8LabelsCleanup(repo.GetName()),
 		keys.GitProvider:               providerinfo.Name,
 		keys.State:                     StateStarted,
+		keys.Controller:                formatting.K8LabelsCleanup(info.ControllerName()),
 	}
 
 	annotations := map[string]string{
@@ -45,6 +57,9 @@ func AddLabelsAndAnnotations(event *info.Event, pipelineRun *tektonv1beta1.Pipel
 	if event.PullRequestNumber != 0 {
 		labels[keys.PullRequest] = strconv.Itoa(event.PullRequestNumber)
 	}
+	if event.IssueNumber != 0 {
+		labels[keys.Issue] = strconv.Itoa(event.IssueNumber)
+	}
 
 	// TODO: move to provider specific function
 	if providerinfo.Name == "github" || providerinfo.Name == "github-enterprise" {
@@ -54,6 +69,9 @@ func AddLabelsAndAnnotations(event *info.Event, pipelineRun *tektonv1beta1.Pipel
 		if event.GHEURL != "" {
 			annotations[keys.GHEURL] = event.GHEURL
 		}
+		if event.GitHubAppID > 0 {
+			annotations[keys.GitHubAppID] = strconv.FormatInt(event.GitHubAppID, 10)
+		}
 	}
 
 	// GitLab
@@ -54,6 +54,14 @@ func TestAddLabelsAndAnnotations(t *testing.T) {
 			assert.Assert(t, tt.args.pipelineRun.Labels[keys.URLOrg] == tt.args.event.Organization, "'%s' != %s",
 				tt.args.pipelineRun.Labels[keys.URLOrg], tt.args.event.Organization)
 			assert.Assert(t, tt.args.pipelineRun.Annotations[keys.ShaURL] == tt.args.event.SHAURL)
+			assert.Equal(t, tt.args.pipelineRun.Labels[keys.Controller], info.DefaultControllerName)
 		})
 	}
 }
+
+func TestOwnedByController(t *testing.T) {
+	assert.Assert(t, OwnedByController(map[string]string{}, info.DefaultControllerName))
+	assert.Assert(t, !OwnedByController(map[string]string{}, "staging"))
+	assert.Assert(t, OwnedByController(map[string]string{keys.Controller: "staging"}, "staging"))
+	assert.Assert(t, !OwnedByController(map[string]string{keys.Controller: "staging"}, info.DefaultControllerName))
+}
@@ -0,0 +1,34 @@
+package kubeinteraction
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	tektonDefaultsConfigMap        = "config-defaults"
+	tektonDefaultServiceAccountKey = "default-service-account"
+	tektonDefaultServiceAccount    = "default"
+)
+
+// tektonNamespaces are the namespaces Tekton Pipelines is installed in,
+// upstream and on OpenShift
+var tektonNamespaces = []string{"tekton-pipelines", "openshift-pipelines"}
+
+// GetDefaultServiceAccount returns the ServiceAccount Tekton runs the
+// PipelineRuns which don't specify one with, the default-service-account of
+// the config-defaults ConfigMap of Tekton or default when it is not set.
+func (k Interaction) GetDefaultServiceAccount(ctx context.Context) string {
+	for _, ns := range tektonNamespaces {
+		cm, err := k.Run.Clients.Kube.CoreV1().ConfigMaps(ns).Get(ctx, tektonDefaultsConfigMap, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		if sa := cm.Data[tektonDefaultServiceAccountKey]; sa != "" {
+			return sa
+		}
+		break
+	}
+	return tektonDefaultServiceAccount
+}
@@ -0,0 +1,62 @@
+package kubeinteraction
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestGetDefaultServiceAccount(t *testing.T) {
+	tests := []struct {
+		name      string
+		configMap *corev1.ConfigMap
+		want      string
+	}{
+		{
+			name: "no tekton defaults",
+			want: "default",
+		},
+		{
+			name: "default service account of tekton",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "config-defaults", Namespace: "tekton-pipelines"},
+				Data:       map[string]string{"default-service-account": "pipeline"},
+			},
+			want: "pipeline",
+		},
+		{
+			name: "default service account of openshift pipelines",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "config-defaults", Namespace: "openshift-pipelines"},
+				Data:       map[string]string{"default-service-account": "pipeline"},
+			},
+			want: "pipeline",
+		},
+		{
+			name: "no default service account in the tekton defaults",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "config-defaults", Namespace: "tekton-pipelines"},
+				Data:       map[string]string{"default-timeout-minutes": "60"},
+			},
+			want: "default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			tdata := testclient.Data{}
+			if tt.configMap != nil {
+				tdata.ConfigMap = []*corev1.ConfigMap{tt.configMap}
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+			kint := Interaction{Run: &params.Run{Clients: clients.Clients{Kube: stdata.Kube}}}
+			assert.Equal(t, kint.GetDefaultServiceAccount(ctx), tt.want)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package status
+
+import (
+	"context"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Footprint is the estimated resource footprint of a PipelineRun, the CPU and
+// the memory requested by the pods of its tasks multiplied by how long they
+// ran.
+type Footprint struct {
+	Tasks             int
+	CPUCoreSeconds    float64
+	MemoryByteSeconds float64
+}
+
+// IsZero is true when no task had any pod or resource requests to estimate
+// the footprint from.
+func (f Footprint) IsZero() bool {
+	return f.CPUCoreSeconds == 0 && f.MemoryByteSeconds == 0
+}
+
+// CollectFootprint estimates the footprint of the completed tasks from the
+// requests of their pods, the pods which have already been pruned are
+// skipped.
+func CollectFootprint(ctx context.Context, run *params.Run, pr *tektonv1beta1.PipelineRun, trStatus map[string]*tektonv1beta1.PipelineRunTaskRunStatus) Footprint {
+	footprint := Footprint{}
+	for _, task := range trStatus {
+		if task.Status == nil || task.Status.PodName == "" ||
+			task.Status.StartTime == nil || task.Status.CompletionTime == nil {
+			continue
+		}
+		pod, err := run.Clients.Kube.CoreV1().Pods(pr.GetNamespace()).Get(ctx, task.Status.PodName, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		cpu, memory := podRequests(pod)
+		seconds := task.Status.CompletionTime.Sub(task.Status.StartTime.Time).Seconds()
+		if seconds <= 0 || (cpu == 0 && memory == 0) {
+			continue
+		}
+		footprint.Tasks++
+		footprint.CPUCoreSeconds += cpu * seconds
+		footprint.MemoryByteSeconds += memory * seconds
+	}
+	return footprint
+}
+
+// podRequests returns the CPU cores and the memory bytes a pod is scheduled
+// with, the sum of the requests of its containers or the biggest requests of
+// its init containers when they are greater since they run one after the
+// other.
+func podRequests(pod *corev1.Pod) (float64, float64) {
+	var milliCPU, memory int64
+	for _, container := range pod.Spec.Containers {
+		milliCPU += container.Resources.Requests.Cpu().MilliValue()
+		memory += container.Resources.Requests.Memory().Value()
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if c := container.Resources.Requests.Cpu().MilliValue(); c > milliCPU {
+			milliCPU = c
+		}
+		if m := container.Resources.Requests.Memory().Value(); m > memory {
+			memory = m
+		}
+	}
+	return float64(milliCPU) / 1000, float64(memory)
+}
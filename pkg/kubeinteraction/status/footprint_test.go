@@ -0,0 +1,84 @@
+package status
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	paramclients "github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCollectFootprint(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	requests := func(cpu, memory string) corev1.ResourceRequirements {
+		return corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}}
+	}
+	pods := []*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "build-pod", Namespace: "ns"},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "prepare", Resources: requests("100m", "64Mi")}},
+				Containers: []corev1.Container{
+					{Name: "step-build", Resources: requests("500m", "512Mi")},
+					{Name: "step-push", Resources: requests("500m", "512Mi")},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "ns"},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "prepare", Resources: requests("2", "2Gi")}},
+				Containers:     []corev1.Container{{Name: "step-test", Resources: requests("250m", "256Mi")}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "lint-pod", Namespace: "ns"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "step-lint"}}},
+		},
+	}
+	kube := kubefake.NewSimpleClientset()
+	for _, pod := range pods {
+		_, err := kube.CoreV1().Pods("ns").Create(ctx, pod, metav1.CreateOptions{})
+		assert.NilError(t, err)
+	}
+	run := &params.Run{Clients: paramclients.Clients{Kube: kube}}
+
+	start := metav1.NewTime(time.Now())
+	taskStatus := func(podName string, duration time.Duration) *tektonv1beta1.PipelineRunTaskRunStatus {
+		completion := metav1.NewTime(start.Add(duration))
+		return &tektonv1beta1.PipelineRunTaskRunStatus{Status: &tektonv1beta1.TaskRunStatus{
+			TaskRunStatusFields: tektonv1beta1.TaskRunStatusFields{
+				PodName:        podName,
+				StartTime:      &start,
+				CompletionTime: &completion,
+			},
+		}}
+	}
+	trStatus := map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+		"build":   taskStatus("build-pod", time.Minute),
+		"test":    taskStatus("test-pod", 30*time.Second),
+		"lint":    taskStatus("lint-pod", time.Minute),
+		"pruned":  taskStatus("pruned-pod", time.Minute),
+		"pending": {Status: &tektonv1beta1.TaskRunStatus{}},
+	}
+	pr := &tektonv1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr", Namespace: "ns"}}
+
+	footprint := CollectFootprint(ctx, run, pr, trStatus)
+	assert.Equal(t, footprint.Tasks, 2)
+	// 1 core for 60s, and the 2 cores of the init container for 30s
+	assert.Equal(t, footprint.CPUCoreSeconds, 120.0)
+	assert.Equal(t, footprint.MemoryByteSeconds, float64(60*1024*1024*1024+30*2*1024*1024*1024))
+	assert.Assert(t, !footprint.IsZero())
+
+	assert.Assert(t, CollectFootprint(ctx, run, pr, map[string]*tektonv1beta1.PipelineRunTaskRunStatus{}).IsZero())
+}
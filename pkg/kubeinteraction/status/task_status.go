@@ -2,7 +2,10 @@ package status
 
 import (
 	"context"
+	"encoding/json"
+	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 
 	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
@@ -10,10 +13,22 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	tektonstatus "github.com/tektoncd/pipeline/pkg/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var reasonMessageReplacementRegexp = regexp.MustCompile(`\(image: .*`)
 
+// ArtifactsResultName is the name of the task result where the tasks write
+// the manifest of the artifacts they published, as a json list of artifacts
+const ArtifactsResultName = "pac-artifacts"
+
+// Artifact is an image, a report or any other artifact published by a task.
+type Artifact struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Digest string `json:"digest,omitempty"`
+}
+
 // GetStatusFromTaskStatusOrFromAsking will return the status of the taskruns,
 // it would use the embedded one if it's available (pre tekton 0.44.0) or try
 // to get it from the child references
@@ -89,3 +104,79 @@ func CollectFailedTasksLogSnippet(ctx context.Context, cs *params.Run, kinteract
 	}
 	return failureReasons
 }
+
+// CollectTaskResults collects the result of each task of a PipelineRun, sorted
+// by start time, to be stored in the Repository status. The failure snippet
+// of the failed tasks is taken from failures as collected by
+// CollectFailedTasksLogSnippet.
+func CollectTaskResults(cs *params.Run, pr *tektonv1beta1.PipelineRun, trStatus map[string]*tektonv1beta1.PipelineRunTaskRunStatus, failures map[string]pacv1alpha1.TaskInfos) []pacv1alpha1.TaskResult {
+	results := []pacv1alpha1.TaskResult{}
+	for _, task := range trStatus {
+		if task.Status == nil {
+			continue
+		}
+		result := pacv1alpha1.TaskResult{
+			Name:           task.PipelineTaskName,
+			StartTime:      task.Status.StartTime,
+			CompletionTime: task.Status.CompletionTime,
+			LogURL:         cs.Clients.ConsoleUI.TaskLogURL(pr.GetNamespace(), pr.GetName(), task.PipelineTaskName),
+		}
+		if len(task.Status.Conditions) > 0 {
+			result.Status = task.Status.Conditions[0].Reason
+		}
+		if result.StartTime != nil && result.CompletionTime != nil {
+			result.Duration = &metav1.Duration{Duration: result.CompletionTime.Sub(result.StartTime.Time)}
+		}
+		if failure, ok := failures[task.PipelineTaskName]; ok {
+			result.FailureSnippet = failure.LogSnippet
+			if result.FailureSnippet == "" {
+				result.FailureSnippet = failure.Message
+			}
+		}
+		results = append(results, result)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].StartTime == nil || results[j].StartTime == nil {
+			return results[j].StartTime == nil && results[i].StartTime != nil
+		}
+		return results[i].StartTime.Before(results[j].StartTime)
+	})
+	return results
+}
+
+// CollectArtifacts collects the artifacts of the manifests written by the
+// tasks in their pac-artifacts result, ordered by task name. The manifests
+// we cannot parse and the artifacts without a name or an http(s) url are
+// skipped.
+func CollectArtifacts(trStatus map[string]*tektonv1beta1.PipelineRunTaskRunStatus) []Artifact {
+	tasks := []*tektonv1beta1.PipelineRunTaskRunStatus{}
+	for _, task := range trStatus {
+		if task.Status != nil {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].PipelineTaskName < tasks[j].PipelineTaskName
+	})
+
+	artifacts := []Artifact{}
+	for _, task := range tasks {
+		for _, result := range task.Status.TaskRunResults {
+			if result.Name != ArtifactsResultName {
+				continue
+			}
+			manifest := []Artifact{}
+			if err := json.Unmarshal([]byte(result.Value.StringVal), &manifest); err != nil {
+				continue
+			}
+			for _, artifact := range manifest {
+				u, err := url.Parse(artifact.URL)
+				if artifact.Name == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+					continue
+				}
+				artifacts = append(artifacts, artifact)
+			}
+		}
+	}
+	return artifacts
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/jonboulle/clockwork"
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	paramclients "github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
 	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
@@ -226,3 +228,84 @@ func TestGetStatusFromTaskStatusOrFromAsking(t *testing.T) {
 		})
 	}
 }
+
+func TestCollectTaskResults(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	makeTaskStatus := func(name, reason string, start, end int) *tektonv1beta1.PipelineRunTaskRunStatus {
+		fields := tektonv1beta1.TaskRunStatusFields{}
+		if start >= 0 {
+			fields.StartTime = &metav1.Time{Time: clock.Now().Add(time.Duration(start) * time.Minute)}
+		}
+		if end >= 0 {
+			fields.CompletionTime = &metav1.Time{Time: clock.Now().Add(time.Duration(end) * time.Minute)}
+		}
+		return &tektonv1beta1.PipelineRunTaskRunStatus{
+			PipelineTaskName: name,
+			Status: &tektonv1beta1.TaskRunStatus{
+				TaskRunStatusFields: fields,
+				Status: knativeduckv1.Status{
+					Conditions: knativeduckv1.Conditions{{Type: knativeapi.ConditionSucceeded, Reason: reason}},
+				},
+			},
+		}
+	}
+	pr := tektontest.MakePRCompletion(clock, "pipeline", "ns",
+		tektonv1beta1.PipelineRunReasonFailed.String(), make(map[string]string), 10)
+	trStatus := map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+		"tr-tests":   makeTaskStatus("tests", "Failed", 2, 5),
+		"tr-clone":   makeTaskStatus("clone", "Succeeded", 1, 2),
+		"tr-publish": makeTaskStatus("publish", "", -1, -1),
+		"tr-nil":     {PipelineTaskName: "nil"},
+	}
+	failures := map[string]pacv1alpha1.TaskInfos{
+		"tests": {Name: "tests", LogSnippet: "FAIL: TestSomething", Message: "step failed"},
+	}
+
+	cs := &params.Run{Clients: paramclients.Clients{ConsoleUI: consoleui.FallBackConsole{}}}
+	got := CollectTaskResults(cs, pr, trStatus, failures)
+	assert.Equal(t, len(got), 3)
+	assert.Equal(t, got[0].Name, "clone")
+	assert.Equal(t, got[0].Status, "Succeeded")
+	assert.Equal(t, got[0].Duration.Duration, time.Minute)
+	assert.Equal(t, got[0].FailureSnippet, "")
+	assert.Assert(t, got[0].LogURL != "")
+	assert.Equal(t, got[1].Name, "tests")
+	assert.Equal(t, got[1].Duration.Duration, 3*time.Minute)
+	assert.Equal(t, got[1].FailureSnippet, "FAIL: TestSomething")
+	assert.Equal(t, got[2].Name, "publish")
+	assert.Assert(t, got[2].Duration == nil)
+}
+
+func TestCollectArtifacts(t *testing.T) {
+	makeTaskStatus := func(name string, results ...tektonv1beta1.TaskRunResult) *tektonv1beta1.PipelineRunTaskRunStatus {
+		return &tektonv1beta1.PipelineRunTaskRunStatus{
+			PipelineTaskName: name,
+			Status: &tektonv1beta1.TaskRunStatus{
+				TaskRunStatusFields: tektonv1beta1.TaskRunStatusFields{TaskRunResults: results},
+			},
+		}
+	}
+	manifest := func(value string) tektonv1beta1.TaskRunResult {
+		return tektonv1beta1.TaskRunResult{
+			Name:  ArtifactsResultName,
+			Value: *tektonv1beta1.NewArrayOrString(value),
+		}
+	}
+	trStatus := map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+		"tr-report": makeTaskStatus("report",
+			manifest(`[{"name": "coverage", "url": "https://reports/coverage.html"}]`)),
+		"tr-build": makeTaskStatus("build",
+			manifest(`[{"name": "image", "url": "https://quay.io/org/image", "digest": "sha256:1234"},
+				{"name": "nourl"}, {"url": "https://quay.io/noname"}, {"name": "js", "url": "javascript:alert(1)"}]`),
+			tektonv1beta1.TaskRunResult{Name: "other", Value: *tektonv1beta1.NewArrayOrString(`[{"name": "other", "url": "https://other"}]`)}),
+		"tr-invalid": makeTaskStatus("invalid", manifest("not json")),
+		"tr-nil":     {PipelineTaskName: "nil"},
+	}
+
+	got := CollectArtifacts(trStatus)
+	assert.DeepEqual(t, got, []Artifact{
+		{Name: "image", URL: "https://quay.io/org/image", Digest: "sha256:1234"},
+		{Name: "coverage", URL: "https://reports/coverage.html"},
+	})
+	assert.Equal(t, len(CollectArtifacts(map[string]*tektonv1beta1.PipelineRunTaskRunStatus{})), 0)
+}
@@ -0,0 +1,115 @@
+package kubeinteraction
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ParseTimeouts parse the timeouts annotation of a PipelineRun, for example
+// "pipeline=1h, tasks=50m, finally=10m". A timeout of 0 means no timeout.
+func ParseTimeouts(annotation string) (*tektonv1beta1.TimeoutFields, error) {
+	timeouts := &tektonv1beta1.TimeoutFields{}
+	for _, entry := range strings.Split(annotation, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid timeout %q, it needs to be name=duration", entry)
+		}
+		name = strings.TrimSpace(name)
+		duration, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration for the %s timeout: %w", name, err)
+		}
+		if duration < 0 {
+			return nil, fmt.Errorf("the %s timeout cannot be negative: %s", name, duration)
+		}
+		var field **metav1.Duration
+		switch name {
+		case "pipeline":
+			field = &timeouts.Pipeline
+		case "tasks":
+			field = &timeouts.Tasks
+		case "finally":
+			field = &timeouts.Finally
+		default:
+			return nil, fmt.Errorf("unknown timeout %q, it needs to be one of pipeline, tasks or finally", name)
+		}
+		if *field != nil {
+			return nil, fmt.Errorf("the %s timeout is set more than once", name)
+		}
+		*field = &metav1.Duration{Duration: duration}
+	}
+	if timeouts.Pipeline == nil && timeouts.Tasks == nil && timeouts.Finally == nil {
+		return nil, fmt.Errorf("no timeouts set in %q", annotation)
+	}
+	return timeouts, validateTimeouts(timeouts)
+}
+
+// validateTimeouts checks the tasks and finally timeouts fit in the pipeline
+// timeout, tekton would refuse the PipelineRun otherwise.
+func validateTimeouts(timeouts *tektonv1beta1.TimeoutFields) error {
+	if timeouts.Pipeline == nil || timeouts.Pipeline.Duration == 0 {
+		return nil
+	}
+	pipeline := timeouts.Pipeline.Duration
+	var tasks, finally time.Duration
+	if timeouts.Tasks != nil {
+		if tasks = timeouts.Tasks.Duration; tasks == 0 {
+			return fmt.Errorf("the tasks timeout cannot be 0 when the pipeline timeout is %s", pipeline)
+		}
+	}
+	if timeouts.Finally != nil {
+		if finally = timeouts.Finally.Duration; finally == 0 {
+			return fmt.Errorf("the finally timeout cannot be 0 when the pipeline timeout is %s", pipeline)
+		}
+	}
+	if tasks+finally > pipeline {
+		return fmt.Errorf("the tasks and finally timeouts (%s) are longer than the pipeline timeout %s", tasks+finally, pipeline)
+	}
+	return nil
+}
+
+// ApplyTimeouts set the timeouts of the annotation on the PipelineRun, they
+// override the ones of its spec.
+func ApplyTimeouts(pr *tektonv1beta1.PipelineRun) error {
+	annotation, ok := pr.GetAnnotations()[keys.Timeouts]
+	if !ok {
+		return nil
+	}
+	timeouts, err := ParseTimeouts(annotation)
+	if err != nil {
+		return fmt.Errorf("invalid %s annotation: %w", keys.Timeouts, err)
+	}
+
+	merged := &tektonv1beta1.TimeoutFields{}
+	if pr.Spec.Timeouts != nil {
+		merged = pr.Spec.Timeouts.DeepCopy()
+	}
+	// the deprecated timeout cannot be set with the timeouts
+	if pr.Spec.Timeout != nil && merged.Pipeline == nil {
+		merged.Pipeline = pr.Spec.Timeout
+	}
+	if timeouts.Pipeline != nil {
+		merged.Pipeline = timeouts.Pipeline
+	}
+	if timeouts.Tasks != nil {
+		merged.Tasks = timeouts.Tasks
+	}
+	if timeouts.Finally != nil {
+		merged.Finally = timeouts.Finally
+	}
+	if err := validateTimeouts(merged); err != nil {
+		return fmt.Errorf("invalid %s annotation: %w", keys.Timeouts, err)
+	}
+	pr.Spec.Timeout = nil
+	pr.Spec.Timeouts = merged
+	return nil
+}
@@ -0,0 +1,107 @@
+package kubeinteraction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyTimeouts(t *testing.T) {
+	duration := func(d time.Duration) *metav1.Duration {
+		return &metav1.Duration{Duration: d}
+	}
+	tests := []struct {
+		name       string
+		annotation string
+		spec       tektonv1beta1.PipelineRunSpec
+		want       *tektonv1beta1.TimeoutFields
+		wantErr    string
+	}{
+		{
+			name: "no annotation",
+			spec: tektonv1beta1.PipelineRunSpec{Timeout: duration(time.Hour)},
+		},
+		{
+			name:       "all timeouts",
+			annotation: "pipeline=1h, tasks=50m, finally=10m",
+			want:       &tektonv1beta1.TimeoutFields{Pipeline: duration(time.Hour), Tasks: duration(50 * time.Minute), Finally: duration(10 * time.Minute)},
+		},
+		{
+			name:       "override the spec",
+			annotation: "tasks=30m",
+			spec:       tektonv1beta1.PipelineRunSpec{Timeout: duration(time.Hour)},
+			want:       &tektonv1beta1.TimeoutFields{Pipeline: duration(time.Hour), Tasks: duration(30 * time.Minute)},
+		},
+		{
+			name:       "no timeout",
+			annotation: "pipeline=0",
+			want:       &tektonv1beta1.TimeoutFields{Pipeline: duration(0)},
+		},
+		{
+			name:       "longer than the spec pipeline timeout",
+			annotation: "tasks=2h",
+			spec:       tektonv1beta1.PipelineRunSpec{Timeouts: &tektonv1beta1.TimeoutFields{Pipeline: duration(time.Hour)}},
+			wantErr:    "the tasks and finally timeouts (2h0m0s) are longer than the pipeline timeout 1h0m0s",
+		},
+		{
+			name:       "unknown timeout",
+			annotation: "pipelines=1h",
+			wantErr:    "unknown timeout \"pipelines\"",
+		},
+		{
+			name:       "invalid duration",
+			annotation: "pipeline=one hour",
+			wantErr:    "invalid duration for the pipeline timeout",
+		},
+		{
+			name:       "negative",
+			annotation: "finally=-1m",
+			wantErr:    "the finally timeout cannot be negative",
+		},
+		{
+			name:       "no value",
+			annotation: "pipeline",
+			wantErr:    "invalid timeout \"pipeline\", it needs to be name=duration",
+		},
+		{
+			name:       "set twice",
+			annotation: "pipeline=1h,pipeline=2h",
+			wantErr:    "the pipeline timeout is set more than once",
+		},
+		{
+			name:       "no tasks timeout with a pipeline timeout",
+			annotation: "pipeline=1h,tasks=0",
+			wantErr:    "the tasks timeout cannot be 0 when the pipeline timeout is 1h0m0s",
+		},
+		{
+			name:       "empty",
+			annotation: " ",
+			wantErr:    "no timeouts set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &tektonv1beta1.PipelineRun{Spec: *tt.spec.DeepCopy()}
+			if tt.annotation != "" {
+				pr.SetAnnotations(map[string]string{keys.Timeouts: tt.annotation})
+			}
+			err := ApplyTimeouts(pr)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.DeepEqual(t, pr.Spec, tt.spec)
+				return
+			}
+			assert.NilError(t, err)
+			if tt.want == nil {
+				assert.DeepEqual(t, pr.Spec, tt.spec)
+				return
+			}
+			assert.Assert(t, pr.Spec.Timeout == nil)
+			assert.DeepEqual(t, pr.Spec.Timeouts, tt.want)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/resolve"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/templates"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// the placeholders are replaced before we parse the yaml, we do the same
+	// with a dummy value to not get an invalid yaml flow mapping
+	rePlaceholder = regexp.MustCompile(`{{[^}]{2,}}}`)
+	reYamlLine    = regexp.MustCompile(`line ([0-9]+)`)
+	// annotations taking a single string or an array of strings
+	reArrayAnnotations = regexp.MustCompile(fmt.Sprintf(`^%s/(on-event|on-target-branch|on-check-run|on-issue-comment|task(-[0-9]+)?|pipeline(-[0-9]+)?)$`,
+		regexp.QuoteMeta(pipelinesascode.GroupName)))
+)
+
+// Problem is an error found on a line of a file.
+type Problem struct {
+	File    string
+	Line    int
+	Message string
+}
+
+// Lint check the PipelineRuns of a file from the .tekton directory for
+// the errors we can detect before running them: invalid yaml, invalid
+// annotations, CEL expressions not compiling and unknown template variables,
+// the custom variables of the Repository are known.
+func Lint(file, content string, customVariables ...string) []Problem {
+	problems := []Problem{}
+	for line, vars := range templates.UnknownVariables(content, customVariables...) {
+		for _, v := range vars {
+			problems = append(problems, Problem{File: file, Line: line, Message: fmt.Sprintf("unknown template variable {{ %s }}", v)})
+		}
+	}
+
+	decoder := yaml.NewDecoder(strings.NewReader(rePlaceholder.ReplaceAllString(content, "placeholder")))
+	for {
+		doc := &yaml.Node{}
+		err := decoder.Decode(doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			line := 1
+			if m := reYamlLine.FindStringSubmatch(err.Error()); m != nil {
+				line, _ = strconv.Atoi(m[1])
+			}
+			problems = append(problems, Problem{File: file, Line: line, Message: fmt.Sprintf("invalid yaml: %s", err.Error())})
+			// the decoder cannot go further after an error
+			break
+		}
+		problems = append(problems, lintDocument(file, doc)...)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Line < problems[j].Line })
+	return problems
+}
+
+// lintDocument check the annotations of a PipelineRun document.
+func lintDocument(file string, doc *yaml.Node) []Problem {
+	problems := []Problem{}
+	if len(doc.Content) == 0 {
+		return problems
+	}
+	root := doc.Content[0]
+	if kind := mappingValue(root, "kind"); kind == nil || kind.Value != "PipelineRun" {
+		return problems
+	}
+	annotations := mappingValue(mappingValue(root, "metadata"), "annotations")
+	if annotations == nil || annotations.Kind != yaml.MappingNode {
+		return problems
+	}
+
+	for i := 0; i+1 < len(annotations.Content); i += 2 {
+		key, value := annotations.Content[i], annotations.Content[i+1]
+		var err error
+		switch {
+		case key.Value == keys.OnTargetBranch:
+			err = matcher.ValidateTargetBranchValues(value.Value)
+		case reArrayAnnotations.MatchString(key.Value):
+			err = matcher.ValidateAnnotationValues(value.Value)
+		case key.Value == keys.OnCelExpression:
+			err = matcher.ValidateCELExpression(value.Value)
+		case key.Value == keys.MaxKeepRuns:
+			if _, cerr := strconv.Atoi(value.Value); cerr != nil {
+				err = fmt.Errorf("annotation %s needs to be an integer: %s", keys.MaxKeepRuns, value.Value)
+			}
+		case key.Value == keys.Matrix:
+			if merr := resolve.ValidateMatrix(value.Value); merr != nil {
+				err = fmt.Errorf("annotation %s is invalid: %w", keys.Matrix, merr)
+			}
+		case key.Value == keys.Timeouts:
+			if _, terr := kubeinteraction.ParseTimeouts(value.Value); terr != nil {
+				err = fmt.Errorf("annotation %s is invalid: %w", keys.Timeouts, terr)
+			}
+		}
+		if err != nil {
+			problems = append(problems, Problem{File: file, Line: value.Line, Message: err.Error()})
+		}
+	}
+	return problems
+}
+
+// mappingValue return the value of key in a yaml mapping node.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
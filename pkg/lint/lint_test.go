@@ -0,0 +1,133 @@
+package lint
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Problem
+	}{
+		{
+			name: "valid",
+			content: `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pr
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[pull_request]"
+    pipelinesascode.tekton.dev/on-target-branch: "[main]"
+    pipelinesascode.tekton.dev/max-keep-runs: "5"
+    pipelinesascode.tekton.dev/timeouts: "pipeline=1h, tasks=50m"
+spec:
+  params:
+    - name: revision
+      value: {{ revision }}
+`,
+			want: []Problem{},
+		},
+		{
+			name: "invalid annotations",
+			content: `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pr
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[pull_request"
+    pipelinesascode.tekton.dev/on-cel-expression: event == 
+    pipelinesascode.tekton.dev/max-keep-runs: "five"
+    pipelinesascode.tekton.dev/timeouts: "pipeline=1h, tasks=2h"
+`,
+			want: []Problem{
+				{File: "pr.yaml", Line: 7, Message: "annotations in pipeline are in wrong format: [pull_request"},
+				{File: "pr.yaml", Line: 8},
+				{File: "pr.yaml", Line: 9, Message: "annotation pipelinesascode.tekton.dev/max-keep-runs needs to be an integer: five"},
+				{File: "pr.yaml", Line: 10, Message: "annotation pipelinesascode.tekton.dev/timeouts is invalid: the tasks and finally timeouts (2h0m0s) are longer than the pipeline timeout 1h0m0s"},
+			},
+		},
+		{
+			name: "invalid semver constraint",
+			content: `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pr
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[push]"
+    pipelinesascode.tekton.dev/on-target-branch: "[>=two]"
+`,
+			want: []Problem{
+				{File: "pr.yaml", Line: 8},
+			},
+		},
+		{
+			name: "invalid matrix",
+			content: `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pr
+  annotations:
+    pipelinesascode.tekton.dev/matrix: "{go: 1.19}"
+`,
+			want: []Problem{
+				{File: "pr.yaml", Line: 7},
+			},
+		},
+		{
+			name: "unknown variable",
+			content: `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: {{ foo }}
+`,
+			want: []Problem{
+				{File: "pr.yaml", Line: 5, Message: "unknown template variable {{ foo }}"},
+			},
+		},
+		{
+			name: "not a pipelinerun",
+			content: `---
+apiVersion: tekton.dev/v1beta1
+kind: Task
+metadata:
+  name: task
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[pull_request"
+`,
+			want: []Problem{},
+		},
+		{
+			name: "invalid yaml",
+			content: `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+	name: pr
+`,
+			want: []Problem{
+				{File: "pr.yaml", Line: 5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Lint("pr.yaml", tt.content)
+			assert.Equal(t, len(got), len(tt.want), "%+v", got)
+			for i := range tt.want {
+				assert.Equal(t, got[i].File, tt.want[i].File)
+				assert.Equal(t, got[i].Line, tt.want[i].Line, got[i].Message)
+				if tt.want[i].Message != "" {
+					assert.Equal(t, got[i].Message, tt.want[i].Message)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package logproxy
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/credentials"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
+	ktypes "github.com/openshift-pipelines/pipelines-as-code/pkg/secrets/types"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Path is where the log proxy is served on the controller.
+const Path = "/logs/"
+
+// Handler streams the logs of the steps of the TaskRuns of a PipelineRun. The
+// viewers log in on the git provider with the OAuth app of the log proxy, the
+// logs are only streamed to the ones who can read the git repository of the
+// Repository of the PipelineRun, with the values of its secrets hidden.
+func Handler(run *params.Run, kint kubeinteraction.Interface, logger *zap.SugaredLogger) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet {
+			http.Error(response, fmt.Sprintf("method %s is not allowed, use GET", request.Method), http.StatusMethodNotAllowed)
+			return
+		}
+
+		proxy, ok := run.Clients.ConsoleUI.(*consoleui.LogProxy)
+		if !ok {
+			http.Error(response, "the log proxy is not enabled", http.StatusNotFound)
+			return
+		}
+		if request.URL.Path == CallbackPath {
+			callback(response, request, run, proxy, logger)
+			return
+		}
+
+		ns, prName, ok := strings.Cut(strings.TrimPrefix(request.URL.Path, Path), "/")
+		if !ok || ns == "" || prName == "" || strings.Contains(prName, "/") {
+			http.Error(response, "the path should be /logs/<namespace>/<pipelinerun>", http.StatusNotFound)
+			return
+		}
+
+		token := viewerToken(request, proxy)
+		if token == "" {
+			login(response, request, proxy, logger)
+			return
+		}
+
+		ctx := request.Context()
+		pr, err := run.Clients.Tekton.TektonV1beta1().PipelineRuns(ns).Get(ctx, prName, metav1.GetOptions{})
+		if err != nil {
+			http.Error(response, fmt.Sprintf("cannot find pipelinerun %s/%s", ns, prName), http.StatusNotFound)
+			return
+		}
+		repo, err := run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(ns).Get(ctx,
+			pr.GetLabels()[keys.Repository], metav1.GetOptions{})
+		if err != nil {
+			http.Error(response, fmt.Sprintf("cannot find the repository of pipelinerun %s/%s", ns, prName), http.StatusNotFound)
+			return
+		}
+		if !sameHost(repo.Spec.URL, proxy.OAuth.URL) {
+			http.Error(response, fmt.Sprintf("the log proxy logs in on %s, it cannot serve the logs of %s", proxy.OAuth.URL, repo.Spec.URL),
+				http.StatusForbidden)
+			return
+		}
+		if err := credentials.CheckRepositoryAccess(ctx, run, repo.Spec.URL, proxy.OAuth.Provider, apiURL(proxy.OAuth), token); err != nil {
+			logger.Infof("a viewer of the log proxy cannot read %s: %v", repo.Spec.URL, err)
+			// the token may have expired or been revoked, the viewer logs in
+			// again on the next request
+			clearCookie(response, proxy, sessionCookie)
+			http.Error(response, fmt.Sprintf("the account you are logged in with cannot read %s, reload the page to log in again", repo.Spec.URL),
+				http.StatusForbidden)
+			return
+		}
+		secretValues := secrets.GetSecretsAttachedToPipelineRun(ctx, kint, pr)
+
+		taskRuns := sortedTaskRuns(kstatus.GetStatusFromTaskStatusOrFromAsking(ctx, pr, run))
+		if task := request.URL.Query().Get("task"); task != "" {
+			filtered := []*tektonv1beta1.PipelineRunTaskRunStatus{}
+			for _, taskRun := range taskRuns {
+				if taskRun.PipelineTaskName == task {
+					filtered = append(filtered, taskRun)
+				}
+			}
+			if len(filtered) == 0 {
+				http.Error(response, fmt.Sprintf("cannot find task %s in pipelinerun %s/%s", task, ns, prName), http.StatusNotFound)
+				return
+			}
+			taskRuns = filtered
+		}
+
+		response.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		flusher, _ := response.(http.Flusher)
+		for _, taskRun := range taskRuns {
+			if taskRun.Status == nil || taskRun.Status.PodName == "" {
+				fmt.Fprintf(response, "==> %s <==\nthe task has not started yet\n\n", taskRun.PipelineTaskName)
+				continue
+			}
+			for _, step := range taskRun.Status.Steps {
+				fmt.Fprintf(response, "==> %s/%s <==\n", taskRun.PipelineTaskName, step.Name)
+				logs, err := run.Clients.Kube.CoreV1().Pods(ns).GetLogs(taskRun.Status.PodName,
+					&corev1.PodLogOptions{Container: step.ContainerName}).Stream(ctx)
+				if err != nil {
+					logger.Infof("cannot get the logs of the step %s of pod %s/%s: %v", step.Name, ns, taskRun.Status.PodName, err)
+					fmt.Fprintf(response, "the logs are not available anymore\n\n")
+					continue
+				}
+				if err := copyRedacted(response, logs, secretValues); err != nil {
+					logger.Errorf("failed to stream the logs of pod %s/%s: %v", ns, taskRun.Status.PodName, err)
+				}
+				logs.Close()
+				fmt.Fprintln(response)
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+		}
+	}
+}
+
+// copyRedacted copies the logs line by line, with the values of the secrets
+// hidden like in the log snippets of the statuses.
+func copyRedacted(w io.Writer, logs io.Reader, values []ktypes.SecretValue) error {
+	reader := bufio.NewReader(logs)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if _, werr := io.WriteString(w, secrets.ReplaceSecretsInText(line, values)); werr != nil {
+				return werr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// sameHost returns whether the two URLs are on the same host.
+func sameHost(a, b string) bool {
+	parsedA, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	parsedB, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return parsedA.Host != "" && strings.EqualFold(parsedA.Host, parsedB.Host)
+}
+
+// sortedTaskRuns returns the TaskRuns statuses in the order they have been
+// started, the ones not started yet at the end.
+func sortedTaskRuns(statuses map[string]*tektonv1beta1.PipelineRunTaskRunStatus) []*tektonv1beta1.PipelineRunTaskRunStatus {
+	taskRuns := []*tektonv1beta1.PipelineRunTaskRunStatus{}
+	for _, taskRun := range statuses {
+		taskRuns = append(taskRuns, taskRun)
+	}
+	sort.SliceStable(taskRuns, func(i, j int) bool {
+		si, sj := taskRuns[i].Status, taskRuns[j].Status
+		if si == nil || si.StartTime == nil {
+			return false
+		}
+		if sj == nil || sj.StartTime == nil {
+			return true
+		}
+		if si.StartTime.Equal(sj.StartTime) {
+			return taskRuns[i].PipelineTaskName < taskRuns[j].PipelineTaskName
+		}
+		return si.StartTime.Before(sj.StartTime)
+	})
+	return taskRuns
+}
@@ -0,0 +1,377 @@
+package logproxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+const viewerAccessToken = "viewer-token"
+
+func taskRunStatus(task, pod string, started time.Time) *tektonv1beta1.PipelineRunTaskRunStatus {
+	return &tektonv1beta1.PipelineRunTaskRunStatus{
+		PipelineTaskName: task,
+		Status: &tektonv1beta1.TaskRunStatus{
+			TaskRunStatusFields: tektonv1beta1.TaskRunStatusFields{
+				PodName:   pod,
+				StartTime: &metav1.Time{Time: started},
+				Steps: []tektonv1beta1.StepState{
+					{Name: "build", ContainerName: "step-build"},
+				},
+			},
+		},
+	}
+}
+
+func pipelineRun(name, repo string, now time.Time) *tektonv1beta1.PipelineRun {
+	return &tektonv1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns",
+			Labels:    map[string]string{keys.Repository: repo},
+		},
+		Spec: tektonv1beta1.PipelineRunSpec{
+			PipelineSpec: &tektonv1beta1.PipelineSpec{
+				Tasks: []tektonv1beta1.PipelineTask{{
+					Name: "build",
+					TaskSpec: &tektonv1beta1.EmbeddedTask{TaskSpec: tektonv1beta1.TaskSpec{
+						Steps: []tektonv1beta1.Step{{
+							Name: "build",
+							Env: []corev1.EnvVar{{
+								Name: "TOKEN",
+								ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: "creds"},
+									Key:                  "token",
+								}},
+							}},
+						}},
+					}},
+				}},
+			},
+		},
+		Status: tektonv1beta1.PipelineRunStatus{
+			PipelineRunStatusFields: tektonv1beta1.PipelineRunStatusFields{
+				TaskRuns: map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+					name + "-test":  taskRunStatus("test", name+"-test-pod", now.Add(time.Minute)),
+					name + "-clone": taskRunStatus("clone", name+"-clone-pod", now),
+				},
+			},
+		},
+	}
+}
+
+// setupProvider returns a fake GitHub Enterprise with its OAuth endpoints,
+// the viewer can only read the owner/repo repository.
+func setupProvider(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", func(rw http.ResponseWriter, r *http.Request) {
+		assert.NilError(t, r.ParseForm())
+		rw.Header().Set("Content-Type", "application/json")
+		if r.Form.Get("code") != "good-code" {
+			rw.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(rw, `{"error": "bad_verification_code"}`)
+			return
+		}
+		fmt.Fprintf(rw, `{"access_token": "%s", "token_type": "bearer"}`, viewerAccessToken)
+	})
+	mux.HandleFunc("/api/v3/repos/owner/repo", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+viewerAccessToken {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(rw, `{}`)
+	})
+	mux.HandleFunc("/api/v3/repos/owner/private", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+	})
+	return httptest.NewServer(mux)
+}
+
+func sealedSession(t *testing.T, key []byte, token string, expires time.Time) *http.Cookie {
+	t.Helper()
+	sealed, err := seal(key, sessionCookie, session{Token: token, Expires: expires.Unix()})
+	assert.NilError(t, err)
+	return &http.Cookie{Name: sessionCookie, Value: sealed}
+}
+
+func newRun(t *testing.T, provider *httptest.Server, key []byte, console consoleui.Interface, logger *zap.SugaredLogger) (*params.Run, *kubernetestint.KinterfaceTest) {
+	t.Helper()
+	ctx, _ := rtesting.SetupFakeContext(t)
+	now := time.Now()
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		PipelineRuns: []*tektonv1beta1.PipelineRun{
+			pipelineRun("pr", "repo", now),
+			pipelineRun("private-pr", "private", now),
+			pipelineRun("gitlab-pr", "gitlab", now),
+		},
+		Repositories: []*v1alpha1.Repository{
+			{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}, Spec: v1alpha1.RepositorySpec{URL: provider.URL + "/owner/repo"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "private", Namespace: "ns"}, Spec: v1alpha1.RepositorySpec{URL: provider.URL + "/owner/private"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "gitlab", Namespace: "ns"}, Spec: v1alpha1.RepositorySpec{URL: "https://gitlab.com/owner/repo"}},
+		},
+	})
+	if console == nil {
+		console = &consoleui.LogProxy{
+			BaseURL: "https://pac.example.com",
+			Key:     key,
+			OAuth:   consoleui.LogProxyOAuth{Provider: "github", URL: provider.URL, ClientID: "id", ClientSecret: "secret"},
+		}
+	}
+	run := &params.Run{
+		Clients: clients.Clients{
+			Log:            logger,
+			Tekton:         stdata.Pipeline,
+			Kube:           stdata.Kube,
+			PipelineAsCode: stdata.PipelineAsCode,
+			HTTP:           *provider.Client(),
+			ConsoleUI:      console,
+		},
+	}
+	// the fake pods all log "fake logs"
+	return run, &kubernetestint.KinterfaceTest{GetSecretResult: map[string]string{"creds": "fake"}}
+}
+
+func TestHandler(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	key := []byte("secret")
+	provider := setupProvider(t)
+	defer provider.Close()
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		session      *http.Cookie
+		console      consoleui.Interface
+		wantStatus   int
+		wantLogin    bool
+		wantBody     []string
+		wantNoBody   []string
+		wantNoCookie bool
+	}{
+		{
+			name:       "all the tasks",
+			path:       "/logs/ns/pr",
+			session:    sealedSession(t, key, viewerAccessToken, time.Now().Add(time.Hour)),
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"==> clone/build <==", "***** logs", "==> test/build <==", "***** logs"},
+			wantNoBody: []string{"fake"},
+		},
+		{
+			name:       "a task",
+			path:       "/logs/ns/pr?task=test",
+			session:    sealedSession(t, key, viewerAccessToken, time.Now().Add(time.Hour)),
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"==> test/build <=="},
+			wantNoBody: []string{"clone/build"},
+		},
+		{
+			name:       "unknown task",
+			path:       "/logs/ns/pr?task=deploy",
+			session:    sealedSession(t, key, viewerAccessToken, time.Now().Add(time.Hour)),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "not logged in",
+			path:       "/logs/ns/pr",
+			wantStatus: http.StatusFound,
+			wantLogin:  true,
+		},
+		{
+			name:       "expired session",
+			path:       "/logs/ns/pr",
+			session:    sealedSession(t, key, viewerAccessToken, time.Now().Add(-time.Minute)),
+			wantStatus: http.StatusFound,
+			wantLogin:  true,
+		},
+		{
+			name:       "session sealed with another key",
+			path:       "/logs/ns/pr",
+			session:    sealedSession(t, []byte("other"), viewerAccessToken, time.Now().Add(time.Hour)),
+			wantStatus: http.StatusFound,
+			wantLogin:  true,
+		},
+		{
+			name:       "tampered session",
+			path:       "/logs/ns/pr",
+			session:    &http.Cookie{Name: sessionCookie, Value: "Zm9vYmFyYmF6cXV4cXV1eHF1dXg"},
+			wantStatus: http.StatusFound,
+			wantLogin:  true,
+		},
+		{
+			name:         "no access to the repository",
+			path:         "/logs/ns/private-pr",
+			session:      sealedSession(t, key, viewerAccessToken, time.Now().Add(time.Hour)),
+			wantStatus:   http.StatusForbidden,
+			wantBody:     []string{"cannot read " + provider.URL + "/owner/private"},
+			wantNoCookie: true,
+		},
+		{
+			name:       "repository on another git provider",
+			path:       "/logs/ns/gitlab-pr",
+			session:    sealedSession(t, key, viewerAccessToken, time.Now().Add(time.Hour)),
+			wantStatus: http.StatusForbidden,
+			wantBody:   []string{"cannot serve the logs of https://gitlab.com/owner/repo"},
+		},
+		{
+			name:       "unknown pipelinerun",
+			path:       "/logs/ns/other",
+			session:    sealedSession(t, key, viewerAccessToken, time.Now().Add(time.Hour)),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "bad path",
+			path:       "/logs/ns",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "not a GET",
+			method:     http.MethodPost,
+			path:       "/logs/ns/pr",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "log proxy not enabled",
+			path:       "/logs/ns/pr",
+			console:    consoleui.FallBackConsole{},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run, kint := newRun(t, provider, key, tt.console, logger)
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			request := httptest.NewRequest(method, tt.path, nil)
+			if tt.session != nil {
+				request.AddCookie(tt.session)
+			}
+			recorder := httptest.NewRecorder()
+			Handler(run, kint, logger)(recorder, request)
+			assert.Equal(t, recorder.Code, tt.wantStatus, recorder.Body.String())
+			if tt.wantLogin {
+				assert.Assert(t, strings.HasPrefix(recorder.Header().Get("Location"), provider.URL+"/login/oauth/authorize?"),
+					recorder.Header().Get("Location"))
+			}
+			if tt.wantNoCookie {
+				assert.Assert(t, strings.Contains(recorder.Header().Get("Set-Cookie"), sessionCookie+"=;"), recorder.Header().Get("Set-Cookie"))
+			}
+			// the bodies are expected in this order
+			body := recorder.Body.String()
+			for _, want := range tt.wantBody {
+				index := strings.Index(body, want)
+				assert.Assert(t, index >= 0, "%s not found in %s", want, recorder.Body.String())
+				body = body[index+len(want):]
+			}
+			for _, notWant := range tt.wantNoBody {
+				assert.Assert(t, !strings.Contains(recorder.Body.String(), notWant), recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlerLogin(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	key := []byte("secret")
+	provider := setupProvider(t)
+	defer provider.Close()
+	run, kint := newRun(t, provider, key, nil, logger)
+	handler := Handler(run, kint, logger)
+
+	// the viewer is sent to the git provider to log in
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/logs/ns/pr?task=test", nil))
+	assert.Equal(t, recorder.Code, http.StatusFound)
+	location, err := url.Parse(recorder.Header().Get("Location"))
+	assert.NilError(t, err)
+	assert.Equal(t, location.Query().Get("client_id"), "id")
+	assert.Equal(t, location.Query().Get("redirect_uri"), "https://pac.example.com"+CallbackPath)
+	assert.Equal(t, location.Query().Get("scope"), "repo")
+	loginState := location.Query().Get("state")
+	stateCookies := recorder.Result().Cookies()
+	assert.Equal(t, len(stateCookies), 1)
+	assert.Equal(t, stateCookies[0].Name, stateCookie)
+
+	tests := []struct {
+		name       string
+		query      string
+		cookie     bool
+		wantStatus int
+	}{
+		{
+			name:       "the login is started by another browser",
+			query:      fmt.Sprintf("code=good-code&state=%s", url.QueryEscape(loginState)),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "tampered state",
+			query:      "code=good-code&state=" + url.QueryEscape(loginState[:len(loginState)-2]),
+			cookie:     true,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "bad code",
+			query:      fmt.Sprintf("code=bad-code&state=%s", url.QueryEscape(loginState)),
+			cookie:     true,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "logged in",
+			query:      fmt.Sprintf("code=good-code&state=%s", url.QueryEscape(loginState)),
+			cookie:     true,
+			wantStatus: http.StatusFound,
+		},
+	}
+	var session *http.Cookie
+	for _, tt := range tests {
+		request := httptest.NewRequest(http.MethodGet, CallbackPath+"?"+tt.query, nil)
+		if tt.cookie {
+			request.AddCookie(stateCookies[0])
+		}
+		recorder := httptest.NewRecorder()
+		handler(recorder, request)
+		assert.Equal(t, recorder.Code, tt.wantStatus, "%s: %s", tt.name, recorder.Body.String())
+		if tt.wantStatus != http.StatusFound {
+			continue
+		}
+		// the viewer is sent back to the logs it asked for
+		assert.Equal(t, recorder.Header().Get("Location"), "/logs/ns/pr?task=test")
+		for _, cookie := range recorder.Result().Cookies() {
+			if cookie.Name == sessionCookie {
+				session = cookie
+			}
+		}
+	}
+	assert.Assert(t, session != nil, "no session cookie")
+	assert.Assert(t, session.HttpOnly && session.Secure)
+
+	request := httptest.NewRequest(http.MethodGet, "/logs/ns/pr?task=test", nil)
+	request.AddCookie(session)
+	recorder = httptest.NewRecorder()
+	handler(recorder, request)
+	assert.Equal(t, recorder.Code, http.StatusOK, recorder.Body.String())
+	assert.Assert(t, strings.Contains(recorder.Body.String(), "==> test/build <=="), recorder.Body.String())
+}
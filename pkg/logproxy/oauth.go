@@ -0,0 +1,228 @@
+package logproxy
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// CallbackPath is where the git provider sends back the viewers once they
+	// have logged in, a namespace can't be named _oauth
+	CallbackPath = Path + "_oauth/callback"
+
+	sessionCookie = "pac-log-proxy-session"
+	stateCookie   = "pac-log-proxy-state"
+	// sessionDuration is how long a viewer stays logged in, the access to the
+	// repository is checked again on each request
+	sessionDuration = 8 * time.Hour
+	// stateDuration is how long a viewer has to log in on the git provider
+	stateDuration = 10 * time.Minute
+)
+
+// session is kept encrypted in a cookie of the viewer.
+type session struct {
+	Token   string `json:"token"`
+	Expires int64  `json:"expires"`
+}
+
+// state is sent encrypted to the git provider, which gives it back on the
+// callback with the code. The nonce is also set in a cookie so the callback
+// only logs in the browser which started the login.
+type state struct {
+	Return  string `json:"return"`
+	Nonce   string `json:"nonce"`
+	Expires int64  `json:"expires"`
+}
+
+// oauthConfig returns the OAuth config of the app of the log proxy, the
+// scopes are the ones needed to read the repositories.
+func oauthConfig(proxy *consoleui.LogProxy) *oauth2.Config {
+	endpoint := oauth2.Endpoint{
+		AuthURL:  proxy.OAuth.URL + "/login/oauth/authorize",
+		TokenURL: proxy.OAuth.URL + "/login/oauth/access_token",
+	}
+	scopes := []string{}
+	switch proxy.OAuth.Provider {
+	case "github":
+		scopes = []string{"repo"}
+	case "gitlab":
+		endpoint = oauth2.Endpoint{
+			AuthURL:  proxy.OAuth.URL + "/oauth/authorize",
+			TokenURL: proxy.OAuth.URL + "/oauth/token",
+		}
+		scopes = []string{"read_api"}
+	}
+	return &oauth2.Config{
+		ClientID:     proxy.OAuth.ClientID,
+		ClientSecret: proxy.OAuth.ClientSecret,
+		Endpoint:     endpoint,
+		RedirectURL:  proxy.BaseURL + CallbackPath,
+		Scopes:       scopes,
+	}
+}
+
+// apiURL returns the API of the git provider of the OAuth app, empty for the
+// public GitHub.
+func apiURL(oauth consoleui.LogProxyOAuth) string {
+	if oauth.Provider == "github" {
+		if oauth.URL == "https://github.com" {
+			return ""
+		}
+		return oauth.URL + "/api/v3/"
+	}
+	return oauth.URL
+}
+
+// seal encrypts and authenticates value with the key of the log proxy, the
+// purpose is authenticated too so a state can't be used as a session.
+func seal(key []byte, purpose string, value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(gcm.Seal(nonce, nonce, data, []byte(purpose))), nil
+}
+
+// unseal decrypts into value what has been sealed for purpose.
+func unseal(key []byte, purpose, sealed string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(sealed)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	if len(data) < gcm.NonceSize() {
+		return fmt.Errorf("sealed value too short")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(purpose))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(plain, value)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	sum := sha256.Sum256(key)
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func setCookie(response http.ResponseWriter, proxy *consoleui.LogProxy, name, value string, maxAge time.Duration) {
+	http.SetCookie(response, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     Path,
+		MaxAge:   int(maxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(proxy.BaseURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func clearCookie(response http.ResponseWriter, proxy *consoleui.LogProxy, name string) {
+	// a negative MaxAge deletes the cookie
+	setCookie(response, proxy, name, "", -time.Second)
+}
+
+// viewerToken returns the token of the git provider of the logged in viewer,
+// empty when the viewer has no valid session.
+func viewerToken(request *http.Request, proxy *consoleui.LogProxy) string {
+	cookie, err := request.Cookie(sessionCookie)
+	if err != nil {
+		return ""
+	}
+	s := session{}
+	if err := unseal(proxy.Key, sessionCookie, cookie.Value, &s); err != nil || time.Now().Unix() > s.Expires {
+		return ""
+	}
+	return s.Token
+}
+
+// login sends the viewer to the git provider to log in, to come back to the
+// page it asked for.
+func login(response http.ResponseWriter, request *http.Request, proxy *consoleui.LogProxy, logger *zap.SugaredLogger) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		logger.Errorf("cannot generate the nonce of the login state: %v", err)
+		http.Error(response, "cannot log in", http.StatusInternalServerError)
+		return
+	}
+	st := state{
+		Return:  request.URL.RequestURI(),
+		Nonce:   hex.EncodeToString(nonce),
+		Expires: time.Now().Add(stateDuration).Unix(),
+	}
+	sealed, err := seal(proxy.Key, stateCookie, st)
+	if err != nil {
+		logger.Errorf("cannot seal the login state: %v", err)
+		http.Error(response, "cannot log in", http.StatusInternalServerError)
+		return
+	}
+	setCookie(response, proxy, stateCookie, st.Nonce, stateDuration)
+	http.Redirect(response, request, oauthConfig(proxy).AuthCodeURL(sealed), http.StatusFound)
+}
+
+// callback exchanges the code given by the git provider for the token of the
+// viewer, kept in its session, and sends it back to the page it asked for.
+func callback(response http.ResponseWriter, request *http.Request, run *params.Run, proxy *consoleui.LogProxy, logger *zap.SugaredLogger) {
+	st := state{}
+	if err := unseal(proxy.Key, stateCookie, request.URL.Query().Get("state"), &st); err != nil ||
+		time.Now().Unix() > st.Expires || !strings.HasPrefix(st.Return, Path) {
+		http.Error(response, "invalid or expired login, open the link to the logs again", http.StatusForbidden)
+		return
+	}
+	cookie, err := request.Cookie(stateCookie)
+	if err != nil || !hmac.Equal([]byte(cookie.Value), []byte(st.Nonce)) {
+		http.Error(response, "the login has not been started by this browser, open the link to the logs again", http.StatusForbidden)
+		return
+	}
+
+	ctx := context.WithValue(request.Context(), oauth2.HTTPClient, &run.Clients.HTTP)
+	token, err := oauthConfig(proxy).Exchange(ctx, request.URL.Query().Get("code"))
+	if err != nil {
+		logger.Infof("cannot log in a viewer of the log proxy on %s: %v", proxy.OAuth.URL, err)
+		http.Error(response, fmt.Sprintf("cannot log in on %s", proxy.OAuth.URL), http.StatusForbidden)
+		return
+	}
+	sealed, err := seal(proxy.Key, sessionCookie, session{
+		Token:   token.AccessToken,
+		Expires: time.Now().Add(sessionDuration).Unix(),
+	})
+	if err != nil {
+		logger.Errorf("cannot seal the session of a viewer of the log proxy: %v", err)
+		http.Error(response, "cannot log in", http.StatusInternalServerError)
+		return
+	}
+	clearCookie(response, proxy, stateCookie)
+	setCookie(response, proxy, sessionCookie, sealed, sessionDuration)
+	http.Redirect(response, request, st.Return, http.StatusFound)
+}
@@ -54,6 +54,31 @@ func branchMatch(prunBranch, baseBranch string) bool {
 	return g.Match(baseBranch)
 }
 
+// ValidateAnnotationValues check that the value of an annotation taking a
+// single string or an array of strings is well formatted.
+func ValidateAnnotationValues(annotation string) error {
+	_, err := getAnnotationValues(annotation)
+	return err
+}
+
+// ValidateTargetBranchValues check that the value of the on-target-branch
+// annotation is well formatted and that its semver constraints are valid.
+func ValidateTargetBranchValues(annotation string) error {
+	values, err := getAnnotationValues(annotation)
+	if err != nil {
+		return err
+	}
+	for _, value := range values {
+		if !isSemverConstraint(value) {
+			continue
+		}
+		if _, err := parseSemverConstraint(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // TODO: move to another file since it's common to all annotations_* files
 func getAnnotationValues(annotation string) ([]string, error) {
 	re := regexp.MustCompile(reValidateTag)
@@ -85,8 +110,23 @@ func getTargetBranch(prun *v1beta1.PipelineRun, logger *zap.SugaredLogger, event
 	var targetEvent, targetBranch string
 	if key, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnEvent]; ok {
 		targetEvent = event.TriggerTarget
-		if event.EventType == "incoming" {
+		switch event.EventType {
+		case "incoming":
 			targetEvent = "incoming"
+		case "pull_request_review", "pull_request_review_comment":
+			// reviews are matched on their own so PipelineRuns on pull_request
+			// don't get rerun on every review
+			targetEvent = event.EventType
+		case "release":
+			// the PipelineRuns on push have already run on the push of the tag
+			targetEvent = event.EventType
+		case "merge_group":
+			// the checks required by the merge queue are the ones of the pull
+			// requests, the PipelineRuns on pull_request or merge_group both
+			// run on the merge groups
+			if matched, _ := matchOnAnnotation(key, event.EventType, false); matched {
+				targetEvent = event.EventType
+			}
 		}
 		matched, err := matchOnAnnotation(key, targetEvent, false)
 		targetEvent = key
@@ -115,6 +155,82 @@ func getTargetBranch(prun *v1beta1.PipelineRun, logger *zap.SugaredLogger, event
 	return true, targetEvent, targetBranch, nil
 }
 
+// matchCheckRun check that a PipelineRun waiting with the on-check-run
+// annotation on the check runs of other apps is only matched when one of them
+// has completed, the other PipelineRuns are never matched to a check run.
+func matchCheckRun(prun *v1beta1.PipelineRun, event *info.Event) (bool, error) {
+	key, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnCheckRun]
+	if !ok {
+		return event.CheckRunName == "", nil
+	}
+	if event.CheckRunName == "" {
+		return false, nil
+	}
+	return matchOnAnnotation(key, event.CheckRunName, false)
+}
+
+// matchWorkflowRun check that a PipelineRun waiting with the on-workflow-run
+// annotation on GitHub Actions workflows is only matched when one of them has
+// completed, the other PipelineRuns are never matched to a workflow run.
+func matchWorkflowRun(prun *v1beta1.PipelineRun, event *info.Event) (bool, error) {
+	key, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnWorkflowRun]
+	if !ok {
+		return event.WorkflowRunName == "", nil
+	}
+	if event.WorkflowRunName == "" {
+		return false, nil
+	}
+	return matchOnAnnotation(key, event.WorkflowRunName, false)
+}
+
+// matchLabel check that a PipelineRun gated with the on-label annotation is
+// only matched on the pull requests having one of its labels, the events
+// adding a label only match the PipelineRuns gated on it. A PipelineRun asked
+// by name with /test is matched regardless of the labels.
+func matchLabel(prun *v1beta1.PipelineRun, event *info.Event) (bool, error) {
+	_, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnLabel]
+	if !ok {
+		return len(event.LabelsAdded) == 0, nil
+	}
+	if event.TargetTestPipelineRun != "" {
+		return true, nil
+	}
+	if len(event.LabelsAdded) > 0 {
+		return HasLabel(prun, event.LabelsAdded)
+	}
+	return HasLabel(prun, event.PullRequestLabels)
+}
+
+// HasLabel returns whether one of the labels is in the on-label annotation of
+// the PipelineRun.
+func HasLabel(prun *v1beta1.PipelineRun, labels []string) (bool, error) {
+	key, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnLabel]
+	if !ok {
+		return false, nil
+	}
+	for _, label := range labels {
+		matched, err := matchOnAnnotation(key, label, false)
+		if err != nil || matched {
+			return matched, err
+		}
+	}
+	return false, nil
+}
+
+// matchIssueComment check that a PipelineRun waiting with the on-issue-comment
+// annotation on the commands commented on the issues is only matched on them,
+// the other PipelineRuns are never matched to an issue comment.
+func matchIssueComment(prun *v1beta1.PipelineRun, event *info.Event) (bool, error) {
+	key, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnIssueComment]
+	if !ok {
+		return event.IssueNumber == 0, nil
+	}
+	if event.IssueNumber == 0 || event.CommentCommand == "" {
+		return false, nil
+	}
+	return matchOnAnnotation(key, "/"+event.CommentCommand, false)
+}
+
 type Match struct {
 	PipelineRun *v1beta1.PipelineRun
 	Repo        *apipac.Repository
@@ -160,6 +276,39 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 			}
 		}
 
+		matched, err := matchCheckRun(prun, event)
+		if err != nil {
+			return matchedPRs, err
+		}
+		if !matched {
+			continue
+		}
+
+		matched, err = matchWorkflowRun(prun, event)
+		if err != nil {
+			return matchedPRs, err
+		}
+		if !matched {
+			continue
+		}
+
+		matched, err = matchIssueComment(prun, event)
+		if err != nil {
+			return matchedPRs, err
+		}
+		if !matched {
+			continue
+		}
+
+		matched, err = matchLabel(prun, event)
+		if err != nil {
+			return matchedPRs, err
+		}
+		if !matched {
+			logger.Infof("skipping pipelinerun %s, it does not match the labels of the pull request", prun.GetGenerateName())
+			continue
+		}
+
 		if celExpr, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnCelExpression]; ok {
 			out, err := celEvaluate(ctx, celExpr, event, vcx)
 			if err != nil {
@@ -211,6 +360,17 @@ func matchOnAnnotation(annotations, eventType string, branchMatching bool) (bool
 
 	var gotit string
 	for _, v := range targets {
+		if branchMatching && isSemverConstraint(v) {
+			// only the tags matching the constraint, ie: [>=2.0.0 <3.0.0]
+			matched, err := semverMatches(tagName(eventType), v)
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				gotit = v
+			}
+			continue
+		}
 		if v == eventType {
 			gotit = v
 		}
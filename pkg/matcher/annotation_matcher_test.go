@@ -119,6 +119,798 @@ func TestMatchPipelinerunAnnotationAndRepositories(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:       "cel/match protected target branch",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: "target_branch == \"" + mainBranch + "\" && target_branch.protected",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:                 targetURL,
+					TriggerTarget:       "pull_request",
+					EventType:           "pull_request",
+					BaseBranch:          mainBranch,
+					BaseBranchProtected: true,
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name: "cel/no match unprotected target branch",
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: "target_branch == \"" + mainBranch + "\" && target_branch.protected",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:                 targetURL,
+					TriggerTarget:       "pull_request",
+					EventType:           "pull_request",
+					BaseBranch:          mainBranch,
+					BaseBranchProtected: false,
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "cel/match review approved by reviewer",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: "event == \"pull_request_review\" && review_state == \"approved\" && reviewer == \"maintainer\"",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:           targetURL,
+					TriggerTarget: "pull_request",
+					EventType:     "pull_request_review",
+					BaseBranch:    mainBranch,
+					Reviewer:      "maintainer",
+					ReviewState:   "approved",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "match on pull_request_review event",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request_review]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:           targetURL,
+					TriggerTarget: "pull_request",
+					EventType:     "pull_request_review",
+					BaseBranch:    mainBranch,
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "pull_request pipelinerun not matching review event",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{pipelineTargetNS},
+				runevent: info.Event{
+					URL:           targetURL,
+					TriggerTarget: "pull_request",
+					EventType:     "pull_request_review",
+					BaseBranch:    mainBranch,
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "pull_request pipelinerun matching merge group event",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{pipelineTargetNS},
+				runevent: info.Event{
+					URL:           targetURL,
+					TriggerTarget: "pull_request",
+					EventType:     "merge_group",
+					BaseBranch:    mainBranch,
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "match on merge_group event",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[merge_group]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:           targetURL,
+					TriggerTarget: "pull_request",
+					EventType:     "merge_group",
+					BaseBranch:    mainBranch,
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "match on a completed check run",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+								keys.OnCheckRun:     "[security-scan]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:           targetURL,
+					TriggerTarget: "pull_request",
+					EventType:     "pull_request",
+					BaseBranch:    mainBranch,
+					CheckRunName:  "security-scan",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "not matching another completed check run",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+								keys.OnCheckRun:     "[security-scan]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:           targetURL,
+					TriggerTarget: "pull_request",
+					EventType:     "pull_request",
+					BaseBranch:    mainBranch,
+					CheckRunName:  "lint",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "pipelinerun waiting on a check run not matching the pull request",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+								keys.OnCheckRun:     "[security-scan]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:           targetURL,
+					TriggerTarget: "pull_request",
+					EventType:     "pull_request",
+					BaseBranch:    mainBranch,
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "match on a completed workflow run",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[push]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+								keys.OnWorkflowRun:  "[build, lint]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:             targetURL,
+					TriggerTarget:   "push",
+					EventType:       "push",
+					BaseBranch:      mainBranch,
+					WorkflowRunName: "lint",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "match on a command commented on an issue",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[issue_comment]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+								keys.OnIssueComment: "[/deploy, /rollback]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:            targetURL,
+					TriggerTarget:  "issue_comment",
+					EventType:      "issue_comment",
+					BaseBranch:     mainBranch,
+					IssueNumber:    42,
+					CommentCommand: "deploy",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "pipelinerun not matching a command commented on an issue",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[issue_comment]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:            targetURL,
+					TriggerTarget:  "issue_comment",
+					EventType:      "issue_comment",
+					BaseBranch:     mainBranch,
+					IssueNumber:    42,
+					CommentCommand: "deploy",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "match on a published release",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[release]",
+								keys.OnTargetBranch: "[refs/tags/*]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:           targetURL,
+					TriggerTarget: "push",
+					EventType:     "release",
+					BaseBranch:    "refs/tags/v1.0.0",
+					ReleaseTag:    "v1.0.0",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "push pipelinerun not matching a published release",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[push]",
+								keys.OnTargetBranch: "[refs/tags/*]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:           targetURL,
+					TriggerTarget: "push",
+					EventType:     "release",
+					BaseBranch:    "refs/tags/v1.0.0",
+					ReleaseTag:    "v1.0.0",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "push pipelinerun not matching a completed workflow run",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[push]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:             targetURL,
+					TriggerTarget:   "push",
+					EventType:       "push",
+					BaseBranch:      mainBranch,
+					WorkflowRunName: "build",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "pull_request pipelinerun not matching a completed check run",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:           targetURL,
+					TriggerTarget: "pull_request",
+					EventType:     "pull_request",
+					BaseBranch:    mainBranch,
+					CheckRunName:  "security-scan",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "match on a pull request with the label",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+								keys.OnLabel:        "[e2e, perf]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					PullRequestLabels: []string{"bug", "perf"},
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "not matching a pull request without the label",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+								keys.OnLabel:        "[e2e, perf]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					PullRequestLabels: []string{"bug"},
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "match on the label added",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+								keys.OnLabel:        "[e2e, perf]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					PullRequestLabels: []string{"e2e"},
+					State:             info.State{LabelsAdded: []string{"e2e"}},
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "not matching another label added",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+								keys.OnLabel:        "[e2e, perf]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					PullRequestLabels: []string{"e2e", "bug"},
+					State:             info.State{LabelsAdded: []string{"bug"}},
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "pipelinerun without on-label not matching a label added",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:           targetURL,
+					TriggerTarget: "pull_request",
+					EventType:     "pull_request",
+					BaseBranch:    mainBranch,
+					State:         info.State{LabelsAdded: []string{"e2e"}},
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "match a pipelinerun asked by name regardless of the labels",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pull_request]",
+								keys.OnTargetBranch: fmt.Sprintf("[%s]", mainBranch),
+								keys.OnLabel:        "[e2e, perf]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:           targetURL,
+					TriggerTarget: "pull_request",
+					EventType:     "pull_request",
+					BaseBranch:    mainBranch,
+					State:         info.State{TargetTestPipelineRun: pipelineTargetNSName},
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
 		{
 			name:       "cel/match path by glob",
 			wantPRName: pipelineTargetNSName,
@@ -273,6 +1065,283 @@ func TestMatchPipelinerunAnnotationAndRepositories(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:       "cel/match files by glob",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				fileChanged: []string{".tekton/pull_request.yaml"},
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: `event == "pull_request" && files.matches(".tekton/*yaml")`,
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					Organization:      "mylittle",
+					Repository:        "pony",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "cel/no match files by glob",
+			wantErr: true,
+			args: annotationTestArgs{
+				fileChanged: []string{".tekton/pull_request.yaml"},
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: `files.matches("docs/**")`,
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					Organization:      "mylittle",
+					Repository:        "pony",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "cel/match a label of the body",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: `body.labelExists("e2e")`,
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					Organization:      "mylittle",
+					Repository:        "pony",
+					Request: &info.Request{
+						Header:  http.Header{"X-Github-Event": []string{"pull_request"}},
+						Payload: []byte(`{"pull_request": {"labels": [{"name": "e2e"}]}}`),
+					},
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "cel/no match a label of the body",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: `body.labelExists("wip")`,
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					Organization:      "mylittle",
+					Repository:        "pony",
+					Request: &info.Request{
+						Header:  http.Header{"X-Github-Event": []string{"pull_request"}},
+						Payload: []byte(`{"pull_request": {"labels": [{"name": "e2e"}]}}`),
+					},
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "cel/match a header",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: `header.match("X-GitHub-Event", "^pull_")`,
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					Organization:      "mylittle",
+					Repository:        "pony",
+					Request: &info.Request{
+						Header:  http.Header{"X-Github-Event": []string{"pull_request"}},
+						Payload: []byte(`{"pull_request": {"labels": [{"name": "e2e"}]}}`),
+					},
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "cel/no match on a missing header",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: `header.match("X-Gitlab-Event", ".*")`,
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					Organization:      "mylittle",
+					Repository:        "pony",
+					Request: &info.Request{
+						Header:  http.Header{"X-Github-Event": []string{"pull_request"}},
+						Payload: []byte(`{"pull_request": {"labels": [{"name": "e2e"}]}}`),
+					},
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "cel/match the prefix of the target branch",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1beta1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: `target_branch.startsWith("ma") && !target_branch.protected`,
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					Organization:      "mylittle",
+					Repository:        "pony",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
 		{
 			name:    "cel/no match path by glob",
 			wantErr: true,
@@ -4,25 +4,29 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"regexp"
 	"strings"
 
+	"github.com/gobwas/glob"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/bundle"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/conversion"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/hub"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
 )
 
 const (
-	taskAnnotationsRegexp     = `task(-[0-9]+)?$`
-	pipelineAnnotationsRegexp = `pipeline(-[0-9]+)?$`
+	taskAnnotationsRegexp       = `task(-[0-9]+)?$`
+	pipelineAnnotationsRegexp   = `pipeline(-[0-9]+)?$`
+	customTaskAnnotationsRegexp = `custom-task(-[0-9]+)?$`
 )
 
 type RemoteTasks struct {
@@ -30,14 +34,38 @@ type RemoteTasks struct {
 	ProviderInterface provider.Interface
 	Event             *info.Event
 	Logger            *zap.SugaredLogger
+	// AllowedSources are globs restricting the remote tasks and pipelines
+	// which can be fetched, everything is allowed when empty
+	AllowedSources []string
 }
 
-func (rt RemoteTasks) convertToPipeline(data string) (*tektonv1beta1.Pipeline, error) {
+// checkAllowedSource return an error if the uri doesn't match any of the
+// allowed sources
+func (rt RemoteTasks) checkAllowedSource(uri string) error {
+	if len(rt.AllowedSources) == 0 {
+		return nil
+	}
+	for _, source := range rt.AllowedSources {
+		g, err := glob.Compile(source)
+		if err != nil {
+			return fmt.Errorf("invalid glob %s in allowed task sources: %w", source, err)
+		}
+		if g.Match(uri) {
+			return nil
+		}
+	}
+	return fmt.Errorf("\"%s\" is not in the allowed task sources of the repository", uri)
+}
+
+func (rt RemoteTasks) convertToPipeline(ctx context.Context, data string) (*tektonv1beta1.Pipeline, error) {
 	decoder := k8scheme.Codecs.UniversalDeserializer()
 	obj, _, err := decoder.Decode([]byte(data), nil, nil)
 	if err != nil {
 		return nil, fmt.Errorf("we have a pipeline that is not looking like a kubernetes resource: pipeline: %s resource: %w", data, err)
 	}
+	if obj, err = conversion.ToV1Beta1(ctx, obj); err != nil {
+		return nil, fmt.Errorf("cannot convert the pipeline to tekton.dev/v1beta1: %w", err)
+	}
 
 	pipeline, ok := obj.(*tektonv1beta1.Pipeline)
 	if !ok {
@@ -47,12 +75,15 @@ func (rt RemoteTasks) convertToPipeline(data string) (*tektonv1beta1.Pipeline, e
 	return pipeline, nil
 }
 
-func (rt RemoteTasks) convertTotask(data string) (*tektonv1beta1.Task, error) {
+func (rt RemoteTasks) convertTotask(ctx context.Context, data string) (*tektonv1beta1.Task, error) {
 	decoder := k8scheme.Codecs.UniversalDeserializer()
 	obj, _, err := decoder.Decode([]byte(data), nil, nil)
 	if err != nil {
 		return nil, fmt.Errorf("we have a task that is not looking like a kubernetes resource: task: %s resource: %w", data, err)
 	}
+	if obj, err = conversion.ToV1Beta1(ctx, obj); err != nil {
+		return nil, fmt.Errorf("cannot convert the task to tekton.dev/v1beta1: %w", err)
+	}
 
 	task, ok := obj.(*tektonv1beta1.Task)
 	if !ok {
@@ -62,25 +93,43 @@ func (rt RemoteTasks) convertTotask(data string) (*tektonv1beta1.Task, error) {
 	return task, nil
 }
 
+// ParseCustomTask parses the definition of a custom task, a resource which is
+// not a Tekton resource but which is run by a custom task controller, ie:
+// a shipwright Build. It needs an apiVersion, a kind, a name and a spec.
+func ParseCustomTask(data string) (*unstructured.Unstructured, error) {
+	obj := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(data), &obj); err != nil {
+		return nil, fmt.Errorf("we have a custom task that is not looking like a kubernetes resource: custom task: %s resource: %w", data, err)
+	}
+	customTask := &unstructured.Unstructured{Object: obj}
+	if customTask.GetAPIVersion() == "" || customTask.GetKind() == "" || customTask.GetName() == "" {
+		return nil, fmt.Errorf("this doesn't seem to be a proper custom task, it needs an apiVersion, a kind and a name")
+	}
+	if strings.HasPrefix(customTask.GetAPIVersion(), "tekton.dev/") {
+		return nil, fmt.Errorf("this doesn't seem to be a proper custom task, %s is a tekton resource", customTask.GetAPIVersion())
+	}
+	if _, ok := obj["spec"]; !ok {
+		return nil, fmt.Errorf("this doesn't seem to be a proper custom task, %s/%s has no spec", customTask.GetKind(), customTask.GetName())
+	}
+	return customTask, nil
+}
+
 func (rt RemoteTasks) getRemote(ctx context.Context, uri string, fromHub bool) (string, error) {
+	if err := rt.checkAllowedSource(uri); err != nil {
+		return "", err
+	}
 	if fetchedFromURIFromProvider, task, err := rt.ProviderInterface.GetTaskURI(ctx, rt.Run, rt.Event, uri); fetchedFromURIFromProvider {
 		return task, err
 	}
 
 	switch {
 	case strings.HasPrefix(uri, "https://"), strings.HasPrefix(uri, "http://"):
-		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
-		res, err := rt.Run.Clients.HTTP.Do(req)
+		data, err := fetchRemote(ctx, rt.Run.Clients.HTTP, uri, defaultRemoteFetchLimits)
 		if err != nil {
 			return "", err
 		}
-		if res.StatusCode != http.StatusOK {
-			return "", fmt.Errorf("cannot get remote resource: \"%s\": %s", uri, res.Status)
-		}
-		data, _ := io.ReadAll(res.Body)
-		defer res.Body.Close()
 		rt.Logger.Infof("successfully fetched \"%s\" from remote https url", uri)
-		return string(data), nil
+		return data, nil
 	case strings.Contains(uri, "/"):
 		var data string
 		var err error
@@ -144,7 +193,7 @@ func (rt RemoteTasks) GetTaskFromAnnotations(ctx context.Context, annotations ma
 			return nil, fmt.Errorf("error getting remote task \"%s\": returning empty", v)
 		}
 
-		task, err := rt.convertTotask(data)
+		task, err := rt.convertTotask(ctx, data)
 		if err != nil {
 			return nil, err
 		}
@@ -172,7 +221,7 @@ func (rt RemoteTasks) GetPipelineFromAnnotations(ctx context.Context, annotation
 		if data == "" {
 			return nil, fmt.Errorf("could not get pipeline \"%s\": returning empty", v)
 		}
-		pipeline, err := rt.convertToPipeline(data)
+		pipeline, err := rt.convertToPipeline(ctx, data)
 		if err != nil {
 			return nil, err
 		}
@@ -181,6 +230,57 @@ func (rt RemoteTasks) GetPipelineFromAnnotations(ctx context.Context, annotation
 	return ret, nil
 }
 
+// GetCustomTaskFromAnnotations Get the custom tasks remotely if they are on
+// Annotations, they are not fetched from the hub.
+func (rt RemoteTasks) GetCustomTaskFromAnnotations(ctx context.Context, annotations map[string]string) ([]*unstructured.Unstructured, error) {
+	ret := []*unstructured.Unstructured{}
+	customTasks, err := grabValuesFromAnnotations(annotations, customTaskAnnotationsRegexp)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range customTasks {
+		data, err := rt.getRemote(ctx, v, false)
+		if err != nil {
+			return nil, fmt.Errorf("error getting remote custom task \"%s\": %w", v, err)
+		}
+		if data == "" {
+			return nil, fmt.Errorf("error getting remote custom task \"%s\": returning empty", v)
+		}
+		customTask, err := ParseCustomTask(data)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, customTask)
+	}
+	return ret, nil
+}
+
+// GetTaskFromBundle Get the task named name from a Tekton OCI bundle
+func (rt RemoteTasks) GetTaskFromBundle(ctx context.Context, reference, name string) (*tektonv1beta1.Task, error) {
+	if err := rt.checkAllowedSource(reference); err != nil {
+		return nil, fmt.Errorf("error getting task \"%s\" from bundle: %w", name, err)
+	}
+	data, err := bundle.GetResource(ctx, rt.Run, reference, "task", name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting task \"%s\" from bundle: %w", name, err)
+	}
+	rt.Logger.Infof("successfully fetched task \"%s\" from bundle %s", name, reference)
+	return rt.convertTotask(ctx, data)
+}
+
+// GetPipelineFromBundle Get the pipeline named name from a Tekton OCI bundle
+func (rt RemoteTasks) GetPipelineFromBundle(ctx context.Context, reference, name string) (*tektonv1beta1.Pipeline, error) {
+	if err := rt.checkAllowedSource(reference); err != nil {
+		return nil, fmt.Errorf("error getting pipeline \"%s\" from bundle: %w", name, err)
+	}
+	data, err := bundle.GetResource(ctx, rt.Run, reference, "pipeline", name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting pipeline \"%s\" from bundle: %w", name, err)
+	}
+	rt.Logger.Infof("successfully fetched pipeline \"%s\" from bundle %s", name, reference)
+	return rt.convertToPipeline(ctx, data)
+}
+
 // getTaskFromLocalFS get task locally if file exist
 // TODO: may want to try chroot to the git root dir first as well if we are able so.
 func getTaskFromLocalFS(taskName string, logger *zap.SugaredLogger) (string, error) {
@@ -3,6 +3,7 @@ package matcher
 import (
 	"log"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
@@ -39,6 +40,14 @@ spec:
           - name: task
             image: registry.access.redhat.com/ubi9/ubi-micro
             command: ["/bin/echo", "HELLOMOTO"]`
+	simpleCustomTask = `---
+apiVersion: shipwright.io/v1alpha1
+kind: Build
+metadata:
+  name: build
+spec:
+  source:
+    url: https://github.com/shipwright-io/sample-nodejs`
 	simpleTask = `---
 apiVersion: tekton.dev/v1beta1
 kind: Task
@@ -63,6 +72,7 @@ func TestMain(m *testing.M) {
 
 func TestRemoteTasksGetTaskFromAnnotations(t *testing.T) {
 	tests := []struct {
+		allowedSources         []string
 		annotations            map[string]string
 		filesInsideRepo        map[string]string
 		gotTaskName            string
@@ -115,6 +125,19 @@ func TestRemoteTasksGetTaskFromAnnotations(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "test-annotations-remote-v1",
+			annotations: map[string]string{
+				keys.Task: "[http://remote.task]",
+			},
+			gotTaskName: "task",
+			remoteURLS: map[string]map[string]string{
+				"http://remote.task": {
+					"body": strings.Replace(simpleTask, "tekton.dev/v1beta1", "tekton.dev/v1", 1),
+					"code": "200",
+				},
+			},
+		},
 		{
 			name: "test-annotations-remote-https",
 			annotations: map[string]string{
@@ -128,6 +151,34 @@ func TestRemoteTasksGetTaskFromAnnotations(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:           "test-annotations-allowed-source",
+			allowedSources: []string{"https://other.task", "https://remote.*"},
+			annotations: map[string]string{
+				keys.Task: "[https://remote.task]",
+			},
+			gotTaskName: "task",
+			remoteURLS: map[string]map[string]string{
+				"https://remote.task": {
+					"body": simpleTask,
+					"code": "200",
+				},
+			},
+		},
+		{
+			name:           "test-annotations-not-allowed-source",
+			allowedSources: []string{"https://other.task"},
+			annotations: map[string]string{
+				keys.Task: "[https://remote.task]",
+			},
+			remoteURLS: map[string]map[string]string{
+				"https://remote.task": {
+					"body": simpleTask,
+					"code": "200",
+				},
+			},
+			wantErr: "\"https://remote.task\" is not in the allowed task sources of the repository",
+		},
 		{
 			name: "test-annotations-inside-repo",
 			annotations: map[string]string{
@@ -242,7 +293,8 @@ func TestRemoteTasksGetTaskFromAnnotations(t *testing.T) {
 					FilesInsideRepo:        tt.filesInsideRepo,
 					WantProviderRemoteTask: tt.wantProviderRemoteTask,
 				},
-				Event: &tt.runevent,
+				Event:          &tt.runevent,
+				AllowedSources: tt.allowedSources,
 			}
 
 			got, err := rt.GetTaskFromAnnotations(ctx, tt.annotations)
@@ -388,6 +440,109 @@ func TestGetPipelineFromAnnotations(t *testing.T) {
 	}
 }
 
+func TestGetCustomTaskFromAnnotations(t *testing.T) {
+	tests := []struct {
+		annotations       map[string]string
+		gotCustomTaskName string
+		name              string
+		remoteURLS        map[string]map[string]string
+		wantErr           string
+	}{
+		{
+			name:              "good/fetching from remote http",
+			gotCustomTaskName: "build",
+			annotations: map[string]string{
+				keys.CustomTask: "[http://remote.build]",
+			},
+			remoteURLS: map[string]map[string]string{
+				"http://remote.build": {
+					"body": simpleCustomTask,
+					"code": "200",
+				},
+			},
+		},
+		{
+			name: "bad/a tekton task",
+			annotations: map[string]string{
+				keys.CustomTask: "[http://remote.build]",
+			},
+			remoteURLS: map[string]map[string]string{
+				"http://remote.build": {
+					"body": simpleTask,
+					"code": "200",
+				},
+			},
+			wantErr: "tekton.dev/v1beta1 is a tekton resource",
+		},
+		{
+			name: "bad/no spec",
+			annotations: map[string]string{
+				keys.CustomTask: "[http://remote.build]",
+			},
+			remoteURLS: map[string]map[string]string{
+				"http://remote.build": {
+					"body": "apiVersion: shipwright.io/v1alpha1\nkind: Build\nmetadata:\n  name: build",
+					"code": "200",
+				},
+			},
+			wantErr: "Build/build has no spec",
+		},
+		{
+			name: "bad/returning empty",
+			annotations: map[string]string{
+				keys.CustomTask: "[http://remote.build]",
+			},
+			remoteURLS: map[string]map[string]string{
+				"http://remote.build": {
+					"body": "",
+					"code": "200",
+				},
+			},
+			wantErr: "returning empty",
+		},
+		{
+			name: "no custom task annotations",
+			annotations: map[string]string{
+				keys.Task: "[http://remote.task]",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpTestClient := httptesthelper.MakeHTTPTestClient(t, tt.remoteURLS)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			cs := &params.Run{
+				Clients: clients.Clients{
+					HTTP: *httpTestClient,
+					Log:  logger,
+				},
+			}
+			ctx, _ := rtesting.SetupFakeContext(t)
+			rt := RemoteTasks{
+				Run:               cs,
+				Logger:            logger,
+				ProviderInterface: &provider.TestProviderImp{},
+				Event:             &info.Event{},
+			}
+
+			got, err := rt.GetCustomTaskFromAnnotations(ctx, tt.annotations)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			if tt.gotCustomTaskName == "" {
+				assert.Equal(t, len(got), 0)
+				return
+			}
+			assert.Equal(t, len(got), 1)
+			assert.Equal(t, got[0].GetName(), tt.gotCustomTaskName)
+			assert.Equal(t, got[0].GetKind(), "Build")
+		})
+	}
+}
+
 func TestGetTaskFromLocalFS(t *testing.T) {
 	content := "hellomoto"
 	defer env.ChangeWorkingDir(t, fs.NewDir(t, "TestGetTaskFromLocalFS", fs.WithFile("task1", content)).Path())()
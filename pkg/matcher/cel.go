@@ -2,15 +2,22 @@ package matcher
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/gobwas/glob"
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/checker/decls"
+	"github.com/google/cel-go/common"
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func celEvaluate(ctx context.Context, expr string, event *info.Event, vcx provider.Interface) (ref.Val, error) {
@@ -19,44 +26,143 @@ func celEvaluate(ctx context.Context, expr string, event *info.Event, vcx provid
 		eventTitle = event.SHATitle
 	}
 
+	targetEvent := event.TriggerTarget
+	if event.EventType == "pull_request_review" || event.EventType == "pull_request_review_comment" || event.EventType == "release" {
+		targetEvent = event.EventType
+	}
+
 	data := map[string]interface{}{
-		"event":         event.TriggerTarget,
+		"event":         targetEvent,
 		"event_title":   eventTitle,
 		"target_branch": event.BaseBranch,
 		"source_branch": event.HeadBranch,
+		"tag":           tagName(event.BaseBranch),
+		"reviewer":      event.Reviewer,
+		"review_state":  event.ReviewState,
+		// a qualified name so target_branch stays a string in the expressions
+		"target_branch.protected": event.BaseBranchProtected,
+		// the changed files are only asked to the provider when used
+		"files": func() ref.Val {
+			return types.DefaultTypeAdapter.NativeToValue(celFiles(ctx, event, vcx))
+		},
+		"body": func() ref.Val {
+			return types.DefaultTypeAdapter.NativeToValue(celBody(event))
+		},
+		"header": celHeader(event),
+	}
+
+	env, checked, err := celCompile(ctx, expr, event, vcx)
+	if err != nil {
+		return nil, err
+	}
+
+	prg, err := env.Program(checked)
+	if err != nil {
+		return nil, fmt.Errorf("expression %#v failed to create a Program: %w", expr, err)
 	}
 
+	out, _, err := prg.Eval(data)
+	if err != nil {
+		return nil, fmt.Errorf("expression %#v failed to evaluate: %w", expr, err)
+	}
+	return out, nil
+}
+
+func celCompile(ctx context.Context, expr string, event *info.Event, vcx provider.Interface) (*cel.Env, *cel.Ast, error) {
 	env, err := cel.NewEnv(
 		cel.Lib(celPac{vcx, ctx, event}),
 		cel.Declarations(
 			decls.NewVar("event", decls.String),
 			decls.NewVar("event_title", decls.String),
 			decls.NewVar("target_branch", decls.String),
-			decls.NewVar("source_branch", decls.String)))
+			decls.NewVar("target_branch.protected", decls.Bool),
+			decls.NewVar("source_branch", decls.String),
+			decls.NewVar("tag", decls.String),
+			decls.NewVar("reviewer", decls.String),
+			decls.NewVar("review_state", decls.String),
+			decls.NewVar("files", decls.NewListType(decls.String)),
+			decls.NewVar("body", decls.NewMapType(decls.String, decls.Dyn)),
+			decls.NewVar("header", decls.NewMapType(decls.String, decls.String))))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	parsed, issues := env.Parse(expr)
 	if issues != nil && issues.Err() != nil {
-		return nil, fmt.Errorf("failed to parse expression %#v: %w", expr, issues.Err())
+		return nil, nil, fmt.Errorf("failed to parse expression %#v: %w", expr, issues.Err())
 	}
 
 	checked, issues := env.Check(parsed)
 	if issues != nil && issues.Err() != nil {
-		return nil, fmt.Errorf("expression %#v check failed: %w", expr, issues.Err())
+		return nil, nil, fmt.Errorf("expression %#v check failed: %w", expr, issues.Err())
 	}
+	return env, checked, nil
+}
 
-	prg, err := env.Program(checked)
+// ValidateCELExpression check that the on-cel-expression compiles, without
+// evaluating it.
+func ValidateCELExpression(expr string) error {
+	_, _, err := celCompile(context.Background(), expr, info.NewEvent(), nil)
+	return err
+}
+
+// CELString evaluates a CEL expression to a string, the strings are returned
+// as is, null as an empty string and the other values are marshalled to JSON.
+func CELString(ctx context.Context, expr string, event *info.Event, vcx provider.Interface) (string, error) {
+	out, err := celEvaluate(ctx, expr, event, vcx)
 	if err != nil {
-		return nil, fmt.Errorf("expression %#v failed to create a Program: %w", expr, err)
+		return "", err
+	}
+	switch value := out.Value().(type) {
+	case string:
+		return value, nil
+	case structpb.NullValue:
+		return "", nil
+	default:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("expression %#v value cannot be converted to a string: %w", expr, err)
+		}
+		return string(b), nil
 	}
+}
 
-	out, _, err := prg.Eval(data)
+// celFiles return the files changed by the event, none when the provider
+// cannot list them.
+func celFiles(ctx context.Context, event *info.Event, vcx provider.Interface) []string {
+	if vcx == nil {
+		return []string{}
+	}
+	files, err := vcx.GetFiles(ctx, event)
 	if err != nil {
-		return nil, fmt.Errorf("expression %#v failed to evaluate: %w", expr, err)
+		return []string{}
 	}
-	return out, nil
+	return files
+}
+
+// celBody return the JSON payload of the event as a map.
+func celBody(event *info.Event) map[string]interface{} {
+	body := map[string]interface{}{}
+	if event.Request == nil {
+		return body
+	}
+	_ = json.Unmarshal(event.Request.Payload, &body)
+	return body
+}
+
+// celHeader return the headers of the request of the event, only their first
+// value is kept and their names are lowercased.
+func celHeader(event *info.Event) map[string]string {
+	header := map[string]string{}
+	if event.Request == nil {
+		return header
+	}
+	for name, values := range event.Request.Header {
+		if len(values) > 0 {
+			header[strings.ToLower(name)] = values[0]
+		}
+	}
+	return header
 }
 
 type celPac struct {
@@ -88,10 +194,109 @@ func (t celPac) pathChanged(vals ref.Val) ref.Val {
 	return match
 }
 
+func filesMatchesMacro(eh cel.MacroExprHelper, target *exprpb.Expr, args []*exprpb.Expr) (*exprpb.Expr, *common.Error) {
+	if target.GetIdentExpr().GetName() != "files" {
+		return nil, nil
+	}
+	return eh.ReceiverCall("filesMatches", target, args...), nil
+}
+
+// filesMatches check if one of the files matches the glob.
+func (t celPac) filesMatches(files, pattern ref.Val) ref.Val {
+	g, err := glob.Compile(fmt.Sprint(pattern.Value()))
+	if err != nil {
+		return types.NewErr("invalid glob %s: %v", pattern.Value(), err)
+	}
+	list, ok := files.(traits.Lister)
+	if !ok {
+		return types.Bool(false)
+	}
+	it := list.Iterator()
+	for it.HasNext() == types.True {
+		if file, ok := it.Next().Value().(string); ok && g.Match(file) {
+			return types.Bool(true)
+		}
+	}
+	return types.Bool(false)
+}
+
+// bodyLabelExists check if the pull or merge request of the payload has the
+// label, GitHub and Gitea have the labels of the pull request in
+// pull_request.labels and GitLab in the labels of the merge request hook.
+func (t celPac) bodyLabelExists(body, name ref.Val) ref.Val {
+	payload, ok := body.Value().(map[string]interface{})
+	if !ok {
+		return types.Bool(false)
+	}
+	labels := []interface{}{}
+	if pr, ok := payload["pull_request"].(map[string]interface{}); ok {
+		if prLabels, ok := pr["labels"].([]interface{}); ok {
+			labels = append(labels, prLabels...)
+		}
+	}
+	if mrLabels, ok := payload["labels"].([]interface{}); ok {
+		labels = append(labels, mrLabels...)
+	}
+	for _, label := range labels {
+		label, ok := label.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if label["name"] == name.Value() || label["title"] == name.Value() {
+			return types.Bool(true)
+		}
+	}
+	return types.Bool(false)
+}
+
+// headerMatch check if the value of the header matches the regexp, the header
+// name is case insensitive.
+func (t celPac) headerMatch(args ...ref.Val) ref.Val {
+	name, pattern := fmt.Sprint(args[1].Value()), fmt.Sprint(args[2].Value())
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return types.NewErr("invalid regexp %s: %v", pattern, err)
+	}
+	header, ok := args[0].(traits.Mapper)
+	if !ok {
+		return types.Bool(false)
+	}
+	value, found := header.Find(types.String(strings.ToLower(name)))
+	if !found {
+		return types.Bool(false)
+	}
+	return types.Bool(re.MatchString(fmt.Sprint(value.Value())))
+}
+
+// tagSemverMatches check if the string is a version matching the semver
+// constraint, ie: tag.semverMatches(">=2.0.0 <3.0.0").
+func (t celPac) tagSemverMatches(tag, constraint ref.Val) ref.Val {
+	matched, err := semverMatches(fmt.Sprint(tag.Value()), fmt.Sprint(constraint.Value()))
+	if err != nil {
+		return types.NewErr("%v", err)
+	}
+	return types.Bool(matched)
+}
+
 func (t celPac) CompileOptions() []cel.EnvOption {
 	return []cel.EnvOption{
 		cel.Function("pathChanged",
 			cel.MemberOverload("pathChanged", []*cel.Type{cel.StringType}, cel.BoolType,
 				cel.UnaryBinding(t.pathChanged))),
+		// matches is already the regexp matching of the strings, the macro
+		// only turns files.matches() into our glob matching of the files
+		cel.Macros(cel.NewReceiverMacro("matches", 1, filesMatchesMacro)),
+		cel.Function("filesMatches",
+			cel.MemberOverload("list_string_filesMatches", []*cel.Type{cel.ListType(cel.StringType), cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(t.filesMatches))),
+		cel.Function("labelExists",
+			cel.MemberOverload("map_labelExists", []*cel.Type{cel.MapType(cel.StringType, cel.DynType), cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(t.bodyLabelExists))),
+		cel.Function("match",
+			cel.MemberOverload("map_match_header", []*cel.Type{cel.MapType(cel.StringType, cel.StringType), cel.StringType, cel.StringType}, cel.BoolType,
+				cel.FunctionBinding(t.headerMatch))),
+		cel.Function("semverMatches",
+			cel.MemberOverload("string_semverMatches", []*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(t.tagSemverMatches))),
 	}
 }
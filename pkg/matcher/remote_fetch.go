@@ -0,0 +1,121 @@
+package matcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// remoteFetchLimits are the limits of the fetch of a remote task or pipeline
+// from an http url, a huge or slow remote file would otherwise stall the
+// resolution of all the PipelineRuns of the event.
+type remoteFetchLimits struct {
+	maxSize      int64
+	timeout      time.Duration
+	maxRedirects int
+}
+
+var defaultRemoteFetchLimits = remoteFetchLimits{
+	maxSize:      1024 * 1024,
+	timeout:      30 * time.Second,
+	maxRedirects: 5,
+}
+
+// remoteFetchContentTypes are the content types accepted beside the text
+// ones, text/html is refused since it is usually a login or an error page.
+var remoteFetchContentTypes = []string{
+	"application/yaml",
+	"application/x-yaml",
+	"application/json",
+	"application/octet-stream",
+}
+
+// RemoteFetchError is the error of the fetch of a remote task or pipeline, it
+// is reported in the status of the commit.
+type RemoteFetchError struct {
+	URI    string
+	Reason string
+}
+
+func (e *RemoteFetchError) Error() string {
+	return fmt.Sprintf("cannot fetch remote resource \"%s\": %s", e.URI, e.Reason)
+}
+
+func isAllowedContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if strings.HasPrefix(mediaType, "text/") {
+		return mediaType != "text/html"
+	}
+	for _, allowed := range remoteFetchContentTypes {
+		if mediaType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchRemote get the content of a remote task or pipeline from an http url
+// with a copy of the http client enforcing the limits.
+func fetchRemote(ctx context.Context, httpClient http.Client, uri string, limits remoteFetchLimits) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, limits.timeout)
+	defer cancel()
+
+	httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > limits.maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", limits.maxRedirects)
+		}
+		if req.URL.Scheme != "https" && req.URL.Scheme != "http" {
+			return fmt.Errorf("redirect to %s is not an http url", req.URL.Redacted())
+		}
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return "", &RemoteFetchError{URI: uri, Reason: err.Error()}
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return "", fetchError(uri, err, limits)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", &RemoteFetchError{URI: uri, Reason: fmt.Sprintf("unexpected status %s", res.Status)}
+	}
+	if contentType := res.Header.Get("Content-Type"); !isAllowedContentType(contentType) {
+		return "", &RemoteFetchError{URI: uri, Reason: fmt.Sprintf("unexpected content type %s", contentType)}
+	}
+	if res.ContentLength > limits.maxSize {
+		return "", &RemoteFetchError{URI: uri, Reason: fmt.Sprintf("size %d is larger than the limit of %d bytes", res.ContentLength, limits.maxSize)}
+	}
+
+	// read one more byte than the limit to know if the body is over it
+	data, err := io.ReadAll(io.LimitReader(res.Body, limits.maxSize+1))
+	if err != nil {
+		return "", fetchError(uri, err, limits)
+	}
+	if int64(len(data)) > limits.maxSize {
+		return "", &RemoteFetchError{URI: uri, Reason: fmt.Sprintf("size is larger than the limit of %d bytes", limits.maxSize)}
+	}
+	return string(data), nil
+}
+
+func fetchError(uri string, err error, limits remoteFetchLimits) *RemoteFetchError {
+	if errors.Is(err, context.DeadlineExceeded) || os.IsTimeout(err) {
+		return &RemoteFetchError{URI: uri, Reason: fmt.Sprintf("timed out after %s", limits.timeout)}
+	}
+	return &RemoteFetchError{URI: uri, Reason: err.Error()}
+}
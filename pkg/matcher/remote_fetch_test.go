@@ -0,0 +1,104 @@
+package matcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFetchRemote(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/task.yaml", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(rw, "kind: Task")
+	})
+	mux.HandleFunc("/huge.yaml", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, strings.Repeat("a", 20))
+	})
+	mux.HandleFunc("/login", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(rw, "<html></html>")
+	})
+	mux.HandleFunc("/loop", func(rw http.ResponseWriter, r *http.Request) {
+		http.Redirect(rw, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/redirect", func(rw http.ResponseWriter, r *http.Request) {
+		http.Redirect(rw, r, "/task.yaml", http.StatusFound)
+	})
+	mux.HandleFunc("/slow", func(rw http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+	mux.HandleFunc("/notfound", func(rw http.ResponseWriter, r *http.Request) {
+		http.NotFound(rw, r)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	limits := remoteFetchLimits{maxSize: 10, timeout: 100 * time.Millisecond, maxRedirects: 2}
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "fetched",
+			path: "/task.yaml",
+			want: "kind: Task",
+		},
+		{
+			name: "fetched after a redirect",
+			path: "/redirect",
+			want: "kind: Task",
+		},
+		{
+			name:    "too large",
+			path:    "/huge.yaml",
+			wantErr: "is larger than the limit of 10 bytes",
+		},
+		{
+			name:    "html content type",
+			path:    "/login",
+			wantErr: "unexpected content type text/html",
+		},
+		{
+			name:    "too many redirects",
+			path:    "/loop",
+			wantErr: "stopped after 2 redirects",
+		},
+		{
+			name:    "timeout",
+			path:    "/slow",
+			wantErr: "timed out after 100ms",
+		},
+		{
+			name:    "not found",
+			path:    "/notfound",
+			wantErr: "unexpected status 404 Not Found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fetchRemote(context.Background(), *server.Client(), server.URL+tt.path, limits)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				var fetchErr *RemoteFetchError
+				assert.Assert(t, errors.As(err, &fetchErr))
+				assert.Equal(t, fetchErr.URI, server.URL+tt.path)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
@@ -5,26 +5,59 @@ import (
 	"strings"
 
 	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func MatchEventURLRepo(ctx context.Context, cs *params.Run, event *info.Event, ns string) (*apipac.Repository, error) {
+	repos, err := MatchEventURLRepos(ctx, cs, event, ns)
+	if err != nil || len(repos) == 0 {
+		return nil, err
+	}
+	return repos[0], nil
+}
+
+// MatchEventURLRepos returns all the Repositories matching the Event URL, the
+// latest first, there are more than one for a monorepo with a Repository for
+// each of its sub_path.
+//
+// When no Repository has the URL of the Event, the Repositories with a
+// wildcard URL matching the organization or the group of the git repository
+// are returned, the ones of the closest group.
+func MatchEventURLRepos(ctx context.Context, cs *params.Run, event *info.Event, ns string) ([]*apipac.Repository, error) {
 	repositories, err := cs.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(ns).List(
 		ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
+	repos := []*apipac.Repository{}
+	wildcards := []*apipac.Repository{}
+	closest := ""
 	for i := len(repositories.Items) - 1; i >= 0; i-- {
 		repo := repositories.Items[i]
 		repo.Spec.URL = strings.TrimSuffix(repo.Spec.URL, "/")
 		if repo.Spec.URL == event.URL {
-			return &repo, nil
+			repos = append(repos, &repo)
+			continue
+		}
+		if !formatting.MatchWildcardURL(repo.Spec.URL, event.URL) {
+			continue
+		}
+		switch org, _ := formatting.WildcardURL(repo.Spec.URL); {
+		case len(org) > len(closest):
+			closest = org
+			wildcards = []*apipac.Repository{&repo}
+		case org == closest:
+			wildcards = append(wildcards, &repo)
 		}
 	}
+	if len(repos) == 0 {
+		return wildcards, nil
+	}
 
-	return nil, nil
+	return repos, nil
 }
 
 // GetRepo get a repo by name anywhere on a cluster
@@ -134,6 +134,92 @@ func Test_getRepoByCR(t *testing.T) {
 			wantTargetNS: targetNamespace,
 			wantErr:      false,
 		},
+		{
+			name: "wildcard-organization",
+			args: args{
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "org",
+								URL:              "https://github.com/org/*",
+								InstallNamespace: "org",
+							},
+						),
+					},
+				},
+				runevent: info.Event{URL: "https://github.com/org/repo", BaseBranch: mainBranch, EventType: "pull_request"},
+			},
+			wantTargetNS: "org",
+		},
+		{
+			name: "wildcard-other-organization",
+			args: args{
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "org",
+								URL:              "https://github.com/org/*",
+								InstallNamespace: "org",
+							},
+						),
+					},
+				},
+				runevent: info.Event{URL: "https://github.com/organization/repo", BaseBranch: mainBranch, EventType: "pull_request"},
+			},
+			wantTargetNS: "",
+		},
+		{
+			name: "exact-url-over-wildcard",
+			args: args{
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "org",
+								URL:              "https://github.com/org/*",
+								InstallNamespace: "org",
+							},
+						),
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "repo",
+								URL:              "https://github.com/org/repo",
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+				runevent: info.Event{URL: "https://github.com/org/repo", BaseBranch: mainBranch, EventType: "pull_request"},
+			},
+			wantTargetNS: targetNamespace,
+		},
+		{
+			name: "wildcard-closest-group",
+			args: args{
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "subgroup",
+								URL:              "https://gitlab.com/group/subgroup/*",
+								InstallNamespace: "subgroup",
+							},
+						),
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "group",
+								URL:              "https://gitlab.com/group/*",
+								InstallNamespace: "group",
+							},
+						),
+					},
+				},
+				runevent: info.Event{URL: "https://gitlab.com/group/subgroup/repo", BaseBranch: mainBranch, EventType: "pull_request"},
+			},
+			wantTargetNS: "subgroup",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
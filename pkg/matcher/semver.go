@@ -0,0 +1,56 @@
+package matcher
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+const tagsPrefix = "refs/tags/"
+
+// reConstraintPrefix is the v of the versions of a constraint, ie: >=v2.0.0,
+// the range of semver only takes the versions without it
+var reConstraintPrefix = regexp.MustCompile(`([<>=!]+)\s*v(\d)`)
+
+// isSemverConstraint returns if a value of on-target-branch is a semver
+// constraint on the tags rather than a branch or a glob, the constraints
+// start with a comparison, ie: >=2.0.0 <3.0.0.
+func isSemverConstraint(value string) bool {
+	value = strings.TrimSpace(value)
+	return value != "" && strings.ContainsRune("<>=!", rune(value[0]))
+}
+
+// tagName returns the tag of a ref, empty when it is not a tag.
+func tagName(ref string) string {
+	if !strings.HasPrefix(ref, tagsPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(ref, tagsPrefix)
+}
+
+func parseSemverConstraint(constraint string) (semver.Range, error) {
+	versionRange, err := semver.ParseRange(reConstraintPrefix.ReplaceAllString(strings.TrimSpace(constraint), "$1$2"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid semver constraint %q: %w", constraint, err)
+	}
+	return versionRange, nil
+}
+
+// semverMatches returns if a tag is a version matching the constraint, the
+// tags can start with a v and omit their patch or minor version, ie: v2.1.
+func semverMatches(tag, constraint string) (bool, error) {
+	versionRange, err := parseSemverConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	if tag == "" {
+		return false, nil
+	}
+	version, err := semver.ParseTolerant(tag)
+	if err != nil {
+		return false, nil
+	}
+	return versionRange(version), nil
+}
@@ -0,0 +1,80 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestSemverMatches(t *testing.T) {
+	tests := []struct {
+		name       string
+		tag        string
+		constraint string
+		want       bool
+		wantErr    string
+	}{
+		{name: "in range", tag: "v2.1.0", constraint: ">=2.0.0 <3.0.0", want: true},
+		{name: "out of range", tag: "v3.0.0", constraint: ">=2.0.0 <3.0.0"},
+		{name: "without v", tag: "2.0.0", constraint: ">=2.0.0", want: true},
+		{name: "v in constraint", tag: "v2.0.0", constraint: ">= v2.0.0", want: true},
+		{name: "short version", tag: "v2.1", constraint: ">2.0.0", want: true},
+		{name: "or", tag: "v1.5.0", constraint: "<1.0.0 || >=1.5.0", want: true},
+		{name: "not a version", tag: "nightly", constraint: ">=2.0.0"},
+		{name: "not a tag", constraint: ">=2.0.0"},
+		{name: "invalid constraint", tag: "v2.1.0", constraint: ">=two", wantErr: `invalid semver constraint ">=two"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := semverMatches(tt.tag, tt.constraint)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestMatchOnAnnotationSemver(t *testing.T) {
+	matched, err := matchOnAnnotation("[>=2.0.0 <3.0.0]", "refs/tags/v2.1.0", true)
+	assert.NilError(t, err)
+	assert.Assert(t, matched)
+
+	matched, err = matchOnAnnotation("[>=2.0.0 <3.0.0]", "refs/tags/v3.0.0", true)
+	assert.NilError(t, err)
+	assert.Assert(t, !matched)
+
+	matched, err = matchOnAnnotation("[main, >=2.0.0]", "refs/heads/main", true)
+	assert.NilError(t, err)
+	assert.Assert(t, matched, "the branches still match next to a constraint")
+
+	_, err = matchOnAnnotation("[>=two]", "refs/tags/v2.1.0", true)
+	assert.ErrorContains(t, err, "invalid semver constraint")
+}
+
+func TestValidateTargetBranchValues(t *testing.T) {
+	assert.NilError(t, ValidateTargetBranchValues("[main, refs/tags/*, >=2.0.0 <3.0.0]"))
+	assert.ErrorContains(t, ValidateTargetBranchValues("[>=two]"), "invalid semver constraint")
+	assert.ErrorContains(t, ValidateTargetBranchValues("[main"), "annotations in pipeline are in wrong format")
+}
+
+func TestCELSemverMatches(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	event := &info.Event{EventType: "push", BaseBranch: "refs/tags/v2.1.0"}
+
+	val, err := celEvaluate(ctx, `tag.semverMatches(">=2.0.0 <3.0.0")`, event, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, val.Value(), true)
+
+	val, err = celEvaluate(ctx, `tag == "v2.1.0" && !tag.semverMatches("<2.0.0")`, event, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, val.Value(), true)
+
+	val, err = celEvaluate(ctx, `tag.semverMatches(">=2.0.0")`, &info.Event{EventType: "push", BaseBranch: "refs/heads/main"}, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, val.Value(), false)
+}
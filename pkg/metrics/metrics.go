@@ -3,6 +3,7 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"go.opencensus.io/stats"
@@ -15,11 +16,41 @@ var prCount = stats.Float64("pipelines_as_code_pipelinerun_count",
 	"number of pipeline runs by pipelines as code",
 	stats.UnitDimensionless)
 
+var prCPUCoreSeconds = stats.Float64("pipelines_as_code_pipelinerun_cpu_core_seconds",
+	"estimated cpu core seconds requested by the pods of the pipeline runs",
+	stats.UnitDimensionless)
+
+var prMemoryByteSeconds = stats.Float64("pipelines_as_code_pipelinerun_memory_byte_seconds",
+	"estimated memory byte seconds requested by the pods of the pipeline runs",
+	stats.UnitDimensionless)
+
+var repoCredentialsValid = stats.Int64("pipelines_as_code_repository_credentials_valid",
+	"whether the credentials of the git provider of a repository passed their last check",
+	stats.UnitDimensionless)
+
+var webhookVerificationCount = stats.Int64("pipelines_as_code_webhook_verification_count",
+	"number of verifications of the origin of the webhooks of the git providers",
+	stats.UnitDimensionless)
+
+var githubAppKeyCount = stats.Int64("pipelines_as_code_github_app_key_count",
+	"number of authentications of the github apps by the private key of their key ring",
+	stats.UnitDimensionless)
+
+// lastValue is shared by the registrations of the views of the recorders, a
+// view can only be registered again with the same aggregation
+var lastValue = view.LastValue()
+
 // Recorder holds keys for metrics
 type Recorder struct {
 	initialized     bool
 	provider        tag.Key
 	eventType       tag.Key
+	namespace       tag.Key
+	repository      tag.Key
+	check           tag.Key
+	valid           tag.Key
+	app             tag.Key
+	key             tag.Key
 	ReportingPeriod time.Duration
 }
 
@@ -45,6 +76,42 @@ func NewRecorder() (*Recorder, error) {
 	}
 	r.eventType = eventType
 
+	namespace, err := tag.NewKey("namespace")
+	if err != nil {
+		return nil, err
+	}
+	r.namespace = namespace
+
+	repository, err := tag.NewKey("repository")
+	if err != nil {
+		return nil, err
+	}
+	r.repository = repository
+
+	check, err := tag.NewKey("check")
+	if err != nil {
+		return nil, err
+	}
+	r.check = check
+
+	valid, err := tag.NewKey("valid")
+	if err != nil {
+		return nil, err
+	}
+	r.valid = valid
+
+	app, err := tag.NewKey("app")
+	if err != nil {
+		return nil, err
+	}
+	r.app = app
+
+	key, err := tag.NewKey("key")
+	if err != nil {
+		return nil, err
+	}
+	r.key = key
+
 	err = view.Register(
 		&view.View{
 			Description: prCount.Description(),
@@ -52,6 +119,36 @@ func NewRecorder() (*Recorder, error) {
 			Aggregation: view.Count(),
 			TagKeys:     []tag.Key{r.provider, r.eventType},
 		},
+		&view.View{
+			Description: prCPUCoreSeconds.Description(),
+			Measure:     prCPUCoreSeconds,
+			Aggregation: view.Sum(),
+			TagKeys:     []tag.Key{r.namespace, r.repository},
+		},
+		&view.View{
+			Description: prMemoryByteSeconds.Description(),
+			Measure:     prMemoryByteSeconds,
+			Aggregation: view.Sum(),
+			TagKeys:     []tag.Key{r.namespace, r.repository},
+		},
+		&view.View{
+			Description: repoCredentialsValid.Description(),
+			Measure:     repoCredentialsValid,
+			Aggregation: lastValue,
+			TagKeys:     []tag.Key{r.namespace, r.repository, r.check},
+		},
+		&view.View{
+			Description: webhookVerificationCount.Description(),
+			Measure:     webhookVerificationCount,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{r.provider, r.check, r.valid},
+		},
+		&view.View{
+			Description: githubAppKeyCount.Description(),
+			Measure:     githubAppKeyCount,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{r.app, r.key, r.valid},
+		},
 	)
 
 	if err != nil {
@@ -81,3 +178,95 @@ func (r *Recorder) Count(provider, event string) error {
 	metrics.Record(ctx, prCount.M(1))
 	return nil
 }
+
+// Footprint logs the estimated cpu and memory footprint of a pipeline run of a
+// repository
+func (r *Recorder) Footprint(namespace, repository string, cpuCoreSeconds, memoryByteSeconds float64) error {
+	if !r.initialized {
+		return fmt.Errorf(
+			"ignoring the metrics recording for pipeline runs,  failed to initialize the metrics recorder")
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.namespace, namespace),
+		tag.Insert(r.repository, repository),
+	)
+	if err != nil {
+		return err
+	}
+
+	metrics.Record(ctx, prCPUCoreSeconds.M(cpuCoreSeconds))
+	metrics.Record(ctx, prMemoryByteSeconds.M(memoryByteSeconds))
+	return nil
+}
+
+// CredentialsValidity logs the result of a check of the credentials of a
+// repository, 1 when they are valid and 0 otherwise
+func (r *Recorder) CredentialsValidity(namespace, repository, check string, valid bool) error {
+	if !r.initialized {
+		return fmt.Errorf(
+			"ignoring the metrics recording for repository credentials,  failed to initialize the metrics recorder")
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.namespace, namespace),
+		tag.Insert(r.repository, repository),
+		tag.Insert(r.check, check),
+	)
+	if err != nil {
+		return err
+	}
+
+	value := int64(0)
+	if valid {
+		value = 1
+	}
+	metrics.Record(ctx, repoCredentialsValid.M(value))
+	return nil
+}
+
+// WebhookVerification logs the result of a verification of the origin of a
+// webhook of a provider, ie: its source ip or its token
+func (r *Recorder) WebhookVerification(provider, check string, valid bool) error {
+	if !r.initialized {
+		return fmt.Errorf(
+			"ignoring the metrics recording for webhook verifications,  failed to initialize the metrics recorder")
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.provider, provider),
+		tag.Insert(r.check, check),
+		tag.Insert(r.valid, strconv.FormatBool(valid)),
+	)
+	if err != nil {
+		return err
+	}
+
+	metrics.Record(ctx, webhookVerificationCount.M(1))
+	return nil
+}
+
+// GitHubAppKey logs the result of an authentication of a GitHub App with a
+// private key of its key ring, the key is the name of the key in the Secret
+func (r *Recorder) GitHubAppKey(app, key string, valid bool) error {
+	if !r.initialized {
+		return fmt.Errorf(
+			"ignoring the metrics recording for github app keys,  failed to initialize the metrics recorder")
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.app, app),
+		tag.Insert(r.key, key),
+		tag.Insert(r.valid, strconv.FormatBool(valid)),
+	)
+	if err != nil {
+		return err
+	}
+
+	metrics.Record(ctx, githubAppKeyCount.M(1))
+	return nil
+}
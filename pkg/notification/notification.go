@@ -0,0 +1,205 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cloudevents"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/apis"
+)
+
+// the types of notifications
+const (
+	TypeSlack   = "slack"
+	TypeTeams   = "teams"
+	TypeWebhook = "webhook"
+)
+
+// the statuses of the PipelineRuns we notify
+const (
+	Started   = "started"
+	Succeeded = "succeeded"
+	Failed    = "failed"
+)
+
+// DefaultSecretKey is the key of the secret containing the URL of the
+// incoming webhook when the notification doesn't specify one
+const DefaultSecretKey = "url"
+
+// sendTimeout is how long we wait for the webhook, a slow webhook should not
+// hold the processing of the PipelineRuns
+const sendTimeout = 10 * time.Second
+
+const (
+	defaultSlackTemplate = `PipelineRun <{{ .LogURL }}|{{ .PipelineRun }}> of <{{ .URL }}|{{ .Namespace }}/{{ .Repository }}> has {{ .Status }}` +
+		`{{ if .EventType }} on {{ .EventType }}{{ end }}{{ if .Branch }} to {{ .Branch }}{{ end }}{{ if .SHA }} ({{ .SHA }}){{ end }}`
+	defaultTeamsTemplate = `PipelineRun [{{ .PipelineRun }}]({{ .LogURL }}) of [{{ .Namespace }}/{{ .Repository }}]({{ .URL }}) has {{ .Status }}` +
+		`{{ if .EventType }} on {{ .EventType }}{{ end }}{{ if .Branch }} to {{ .Branch }}{{ end }}{{ if .SHA }} ({{ .SHA }}){{ end }}`
+)
+
+// Data is what the templates of the messages are rendered with.
+type Data struct {
+	cloudevents.Data
+	Status string `json:"status"`
+}
+
+// FinalStatus return the status to notify of a done PipelineRun.
+func FinalStatus(pr *v1beta1.PipelineRun) string {
+	if pr.Status.GetCondition(apis.ConditionSucceeded).IsTrue() {
+		return Succeeded
+	}
+	return Failed
+}
+
+// Validate check the type, the events and the template of a notification.
+func Validate(notification v1alpha1.Notification) error {
+	switch notification.Type {
+	case TypeSlack, TypeTeams, TypeWebhook:
+	default:
+		return fmt.Errorf("invalid notification type %q, must be one of %s, %s or %s", notification.Type, TypeSlack, TypeTeams, TypeWebhook)
+	}
+	for _, event := range notification.Events {
+		switch event {
+		case Started, Succeeded, Failed:
+		default:
+			return fmt.Errorf("invalid notification event %q, must be one of %s, %s or %s", event, Started, Succeeded, Failed)
+		}
+	}
+	if _, err := template.New(notification.Type).Parse(notification.Template); err != nil {
+		return fmt.Errorf("invalid notification template: %w", err)
+	}
+	return nil
+}
+
+// MakeBody render the body of the message of the notification. The template
+// is the text of the message for slack and teams and the whole body for a
+// generic webhook, where it defaults to the data as json.
+func MakeBody(notification v1alpha1.Notification, data Data) ([]byte, error) {
+	tmpl := notification.Template
+	if tmpl == "" {
+		switch notification.Type {
+		case TypeSlack:
+			tmpl = defaultSlackTemplate
+		case TypeTeams:
+			tmpl = defaultTeamsTemplate
+		case TypeWebhook:
+			return json.Marshal(data)
+		}
+	}
+
+	t, err := template.New(notification.Type).Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the notification template: %w", err)
+	}
+	var text bytes.Buffer
+	if err := t.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("cannot render the notification template: %w", err)
+	}
+
+	switch notification.Type {
+	case TypeSlack:
+		return json.Marshal(map[string]string{"text": text.String()})
+	case TypeTeams:
+		return json.Marshal(map[string]string{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  fmt.Sprintf("PipelineRun %s has %s", data.PipelineRun, data.Status),
+			"text":     text.String(),
+		})
+	}
+	return text.Bytes(), nil
+}
+
+// Notifier post the notifications of the Repositories.
+type Notifier struct {
+	kube   kubernetes.Interface
+	client *http.Client
+	logger *zap.SugaredLogger
+}
+
+func NewNotifier(kube kubernetes.Interface, logger *zap.SugaredLogger) *Notifier {
+	return &Notifier{
+		kube:   kube,
+		client: &http.Client{Timeout: sendTimeout},
+		logger: logger,
+	}
+}
+
+func (n *Notifier) SetLogger(logger *zap.SugaredLogger) {
+	n.logger = logger
+}
+
+// Notify post the message of each notification of the Repository interested
+// in the status of the PipelineRun. The errors are only logged, the
+// PipelineRuns should not be affected by an unavailable webhook.
+func (n *Notifier) Notify(ctx context.Context, status string, repo *v1alpha1.Repository, pr *v1beta1.PipelineRun) {
+	if repo == nil || pr == nil || len(repo.Spec.Notifications) == 0 {
+		return
+	}
+	data := Data{Data: cloudevents.NewData(repo, pr), Status: status}
+	for _, notification := range repo.Spec.Notifications {
+		if !wants(notification, status) {
+			continue
+		}
+		if err := n.send(ctx, repo.GetNamespace(), notification, data); err != nil {
+			n.logger.Errorf("cannot send the %s notification of pipelinerun %s/%s: %v", notification.Type, pr.GetNamespace(), pr.GetName(), err)
+		}
+	}
+}
+
+func (n *Notifier) send(ctx context.Context, ns string, notification v1alpha1.Notification, data Data) error {
+	body, err := MakeBody(notification, data)
+	if err != nil {
+		return err
+	}
+
+	key := notification.Secret.Key
+	if key == "" {
+		key = DefaultSecretKey
+	}
+	secret, err := n.kube.CoreV1().Secrets(ns).Get(ctx, notification.Secret.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("cannot get the secret %s: %w", notification.Secret.Name, err)
+	}
+	url := string(bytes.TrimSpace(secret.Data[key]))
+	if url == "" {
+		return fmt.Errorf("no url in the key %s of the secret %s", key, notification.Secret.Name)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("the webhook answered with status %d", res.StatusCode)
+	}
+	return nil
+}
+
+func wants(notification v1alpha1.Notification, status string) bool {
+	if len(notification.Events) == 0 {
+		return true
+	}
+	for _, event := range notification.Events {
+		if event == status {
+			return true
+		}
+	}
+	return false
+}
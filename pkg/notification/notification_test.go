@@ -0,0 +1,229 @@
+package notification
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cloudevents"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+var testData = Data{
+	Data: cloudevents.Data{
+		Repository:  "repo",
+		Namespace:   "ns",
+		PipelineRun: "pull-request-abcde",
+		EventType:   "pull_request",
+		Branch:      "main",
+		SHA:         "sha1234",
+		URL:         "https://forge/owner/repo",
+		LogURL:      "https://console/pull-request-abcde",
+	},
+	Status: Failed,
+}
+
+func TestMakeBody(t *testing.T) {
+	tests := []struct {
+		name         string
+		notification v1alpha1.Notification
+		want         map[string]interface{}
+		wantRaw      string
+		wantErr      string
+	}{
+		{
+			name:         "slack",
+			notification: v1alpha1.Notification{Type: TypeSlack},
+			want: map[string]interface{}{
+				"text": "PipelineRun <https://console/pull-request-abcde|pull-request-abcde> of <https://forge/owner/repo|ns/repo> has failed on pull_request to main (sha1234)",
+			},
+		},
+		{
+			name:         "teams",
+			notification: v1alpha1.Notification{Type: TypeTeams},
+			want: map[string]interface{}{
+				"@type":    "MessageCard",
+				"@context": "https://schema.org/extensions",
+				"summary":  "PipelineRun pull-request-abcde has failed",
+				"text":     "PipelineRun [pull-request-abcde](https://console/pull-request-abcde) of [ns/repo](https://forge/owner/repo) has failed on pull_request to main (sha1234)",
+			},
+		},
+		{
+			name:         "slack template",
+			notification: v1alpha1.Notification{Type: TypeSlack, Template: `:x: {{ .PipelineRun }} "{{ .Status }}"`},
+			want:         map[string]interface{}{"text": `:x: pull-request-abcde "failed"`},
+		},
+		{
+			name:         "webhook",
+			notification: v1alpha1.Notification{Type: TypeWebhook},
+			want: map[string]interface{}{
+				"repository":  "repo",
+				"namespace":   "ns",
+				"pipelinerun": "pull-request-abcde",
+				"event_type":  "pull_request",
+				"branch":      "main",
+				"sha":         "sha1234",
+				"url":         "https://forge/owner/repo",
+				"log_url":     "https://console/pull-request-abcde",
+				"status":      "failed",
+			},
+		},
+		{
+			name:         "webhook template",
+			notification: v1alpha1.Notification{Type: TypeWebhook, Template: `{"run": "{{ .PipelineRun }}"}`},
+			wantRaw:      `{"run": "pull-request-abcde"}`,
+		},
+		{
+			name:         "bad template",
+			notification: v1alpha1.Notification{Type: TypeSlack, Template: `{{ .Nope }}`},
+			wantErr:      "cannot render the notification template",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MakeBody(tt.notification, testData)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			if tt.wantRaw != "" {
+				assert.Equal(t, string(got), tt.wantRaw)
+				return
+			}
+			body := map[string]interface{}{}
+			assert.NilError(t, json.Unmarshal(got, &body))
+			assert.DeepEqual(t, body, tt.want)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NilError(t, Validate(v1alpha1.Notification{Type: TypeTeams, Events: []string{Started, Failed}}))
+	assert.ErrorContains(t, Validate(v1alpha1.Notification{Type: "irc"}),
+		`invalid notification type "irc", must be one of slack, teams or webhook`)
+	assert.ErrorContains(t, Validate(v1alpha1.Notification{Type: TypeSlack, Events: []string{"queued"}}),
+		`invalid notification event "queued", must be one of started, succeeded or failed`)
+	assert.ErrorContains(t, Validate(v1alpha1.Notification{Type: TypeSlack, Template: "{{ .Status"}),
+		"invalid notification template")
+}
+
+func TestFinalStatus(t *testing.T) {
+	pr := &v1beta1.PipelineRun{}
+	pr.Status.Status = duckv1.Status{Conditions: duckv1.Conditions{
+		{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue},
+	}}
+	assert.Equal(t, FinalStatus(pr), Succeeded)
+
+	pr.Status.Conditions[0].Status = corev1.ConditionFalse
+	assert.Equal(t, FinalStatus(pr), Failed)
+}
+
+func TestNotify(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		events     []string
+		secretKey  string
+		webhookURL string
+		sinkStatus int
+		wantSent   bool
+		wantLog    string
+	}{
+		{
+			name:       "sent",
+			status:     Started,
+			sinkStatus: http.StatusOK,
+			wantSent:   true,
+		},
+		{
+			name:       "not interested in the status",
+			status:     Started,
+			events:     []string{Succeeded, Failed},
+			sinkStatus: http.StatusOK,
+		},
+		{
+			name:       "webhook error",
+			status:     Failed,
+			events:     []string{Failed},
+			sinkStatus: http.StatusForbidden,
+			wantSent:   true,
+			wantLog:    "cannot send the slack notification of pipelinerun ns/pull-request-abcde: the webhook answered with status 403",
+		},
+		{
+			name:      "no url in the secret",
+			status:    Failed,
+			secretKey: "webhook",
+			wantLog:   "no url in the key webhook of the secret slack",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, log := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+
+			var received []byte
+			sink := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				var err error
+				received, err = io.ReadAll(r.Body)
+				assert.NilError(t, err)
+				assert.Equal(t, r.Header.Get("Content-Type"), "application/json")
+				rw.WriteHeader(tt.sinkStatus)
+			}))
+			defer sink.Close()
+
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+			_, err := stdata.Kube.CoreV1().Secrets("ns").Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "ns"},
+				Data:       map[string][]byte{"url": []byte(sink.URL + "\n")},
+			}, metav1.CreateOptions{})
+			assert.NilError(t, err)
+
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec: v1alpha1.RepositorySpec{
+					URL: "https://forge/owner/repo",
+					Notifications: []v1alpha1.Notification{{
+						Type:   TypeSlack,
+						Secret: v1alpha1.Secret{Name: "slack", Key: tt.secretKey},
+						Events: tt.events,
+					}},
+				},
+			}
+			pr := &v1beta1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pull-request-abcde",
+					Namespace: "ns",
+					Labels:    map[string]string{keys.SHA: "sha1234"},
+				},
+			}
+
+			NewNotifier(stdata.Kube, logger).Notify(ctx, tt.status, repo, pr)
+			assert.Equal(t, received != nil, tt.wantSent)
+			if received != nil {
+				body := map[string]string{}
+				assert.NilError(t, json.Unmarshal(received, &body))
+				assert.Assert(t, body["text"] != "")
+			}
+			if tt.wantLog != "" {
+				assert.Assert(t, log.FilterMessageSnippet(tt.wantLog).Len() > 0, log.All())
+			} else {
+				assert.Equal(t, log.Len(), 0, log.All())
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package info
+
+import "os"
+
+const (
+	// ControllerNameEnv is the environment variable naming a controller when
+	// multiple Pipelines as Code are installed on the same cluster.
+	ControllerNameEnv = "PAC_CONTROLLER_NAME"
+	// DefaultControllerName is the name of the controller when it has not
+	// been named, it owns the PipelineRuns created without a controller.
+	DefaultControllerName = "default"
+)
+
+// ControllerName returns the name of the controller from the
+// PAC_CONTROLLER_NAME environment variable.
+func ControllerName() string {
+	if name := os.Getenv(ControllerNameEnv); name != "" {
+		return name
+	}
+	return DefaultControllerName
+}
@@ -1,6 +1,9 @@
 package info
 
-import "net/http"
+import (
+	"net/http"
+	"net/url"
+)
 
 type Event struct {
 	State
@@ -34,6 +37,36 @@ type Event struct {
 	SHATitle          string // commit title for UIs
 	PullRequestNumber int    // Pull or Merge Request number
 	PullRequestTitle  string // Title of the pull Request
+	Reviewer          string // User who submitted the review or review comment on the pull request
+	ReviewState       string // State of the submitted review, ie: approved, commented or changes_requested
+	PullRequestAuthor string // Author of the pull request, when the provider tells us
+	CommentCommand    string // gitops command of a comment event, ie: test, retest, cancel, ok-to-test or help
+	PullRequestDraft  bool   // Whether the pull request of a pull request event is a draft
+	PullRequestFork   bool   // Whether the head of the pull request is in a fork of the repository
+	CheckRunName      string // Name of the check run of another app which has completed successfully
+	WorkflowRunName   string // Name of the GitHub Actions workflow which has completed successfully
+	ReleaseTag        string // Tag of the published release
+	ReleaseName       string // Name of the published release
+	ReleasePrerelease bool   // Whether the published release is a prerelease
+	IssueNumber       int    // Number of the issue, not a pull request, a command has been commented on
+	IssueTitle        string // Title of the issue a command has been commented on
+	IssueCommentArgs  string // Arguments of the command commented on the issue, ie: staging for /deploy staging
+
+	// BaseBranchProtected is set when the BaseBranch is a protected branch or
+	// tag on the provider
+	BaseBranchProtected bool
+
+	// PullRequestLabels are the labels of the pull request, the PipelineRuns
+	// with the on-label annotation are only run when it has one of theirs
+	PullRequestLabels []string
+
+	// PullRequestAssignees are the users assigned to the pull request, when
+	// the provider tells us
+	PullRequestAssignees []string
+
+	// RepositoryPrivate is set when the provider reports the repository as
+	// private, the users are then not hidden by the sender-privacy setting
+	RepositoryPrivate bool
 
 	// TODO: move forge specifics to each driver
 	// Github
@@ -41,6 +74,9 @@ type Event struct {
 	Repository     string
 	InstallationID int64
 	GHEURL         string
+	// GitHubAppID is the id of the GitHub App which has sent the event, when
+	// the controller has the credentials of multiple apps
+	GitHubAppID int64
 
 	// TODO: move out inside the provider
 	// Bitbucket Cloud
@@ -60,6 +96,20 @@ type State struct {
 	TargetTestPipelineRun   string
 	CancelPipelineRuns      bool
 	TargetCancelPipelineRun string
+	// CancelInProgress is set when the pull request has been closed or
+	// merged and its PipelineRuns still running should get cancelled
+	CancelInProgress bool
+	// BranchDeleted is set when the push deleted the branch, nothing is run
+	// and the resources of the branch get cleaned up
+	BranchDeleted bool
+	// TestParams are the params passed by a /test or /retest comment to the
+	// PipelineRuns, on top of the params of the Repository
+	TestParams map[string]string
+	// LabelsAdded and LabelsRemoved are the labels added to or removed from
+	// the pull request by the event, only the PipelineRuns with these labels
+	// in their on-label annotation are run or cancelled
+	LabelsAdded   []string
+	LabelsRemoved []string
 }
 
 type Provider struct {
@@ -68,10 +118,14 @@ type Provider struct {
 	User                  string
 	WebhookSecret         string
 	WebhookSecretFromRepo bool
+	// PreviousWebhookSecret is the webhook secret before its rotation, the
+	// payloads signed with it are accepted until it expires
+	PreviousWebhookSecret string
 }
 
 type Request struct {
 	Header  http.Header
+	Query   url.Values
 	Payload []byte
 }
 
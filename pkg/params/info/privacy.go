@@ -0,0 +1,36 @@
+package info
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+)
+
+// HiddenUser replaces the users omitted by the sender-privacy setting
+const HiddenUser = "[hidden]"
+
+// PublicUser returns how a user (login, name or email) is shown in the
+// statuses and comments of the repository of the event, according to the
+// sender-privacy setting. The users of the private repositories are always
+// shown, the logs should keep using the user itself.
+func (p *PacOpts) PublicUser(event *Event, user string) string {
+	if !p.HidesUsers(event) || user == "" {
+		return user
+	}
+	if p.SenderPrivacy == settings.SenderPrivacyHash {
+		sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(user))))
+		return fmt.Sprintf("user-%x", sum[:4])
+	}
+	return HiddenUser
+}
+
+// HidesUsers returns whether the users are hidden in the statuses and
+// comments of the repository of the event
+func (p *PacOpts) HidesUsers(event *Event) bool {
+	if p == nil || p.Settings == nil || (event != nil && event.RepositoryPrivate) {
+		return false
+	}
+	return p.SenderPrivacy == settings.SenderPrivacyOmit || p.SenderPrivacy == settings.SenderPrivacyHash
+}
@@ -0,0 +1,29 @@
+package info
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"gotest.tools/v3/assert"
+)
+
+func TestPublicUser(t *testing.T) {
+	public := &Event{}
+	private := &Event{RepositoryPrivate: true}
+
+	pacopts := &PacOpts{Settings: &settings.Settings{SenderPrivacy: settings.SenderPrivacyNone}}
+	assert.Equal(t, pacopts.PublicUser(public, "alice"), "alice")
+
+	pacopts.SenderPrivacy = settings.SenderPrivacyOmit
+	assert.Equal(t, pacopts.PublicUser(public, "alice"), HiddenUser)
+	assert.Equal(t, pacopts.PublicUser(public, ""), "")
+	assert.Equal(t, pacopts.PublicUser(private, "alice"), "alice")
+
+	pacopts.SenderPrivacy = settings.SenderPrivacyHash
+	hashed := pacopts.PublicUser(public, "Alice@Example.com")
+	assert.Equal(t, hashed, "user-ff8d9819")
+	assert.Equal(t, pacopts.PublicUser(public, " alice@example.com"), hashed)
+	assert.Equal(t, pacopts.PublicUser(private, "alice"), "alice")
+
+	assert.Assert(t, !(&PacOpts{}).HidesUsers(public))
+}
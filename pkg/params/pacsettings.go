@@ -0,0 +1,125 @@
+package params
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+const (
+	pacSettingsAppliedReason = "Applied"
+	pacSettingsInvalidReason = "Invalid"
+	// pacSettingsRewatchInterval is how long we wait before watching the
+	// PACSettings again once its watch has been closed.
+	pacSettingsRewatchInterval = 5 * time.Second
+)
+
+// getPACSettings return the PACSettings of the cluster, nil when there is none
+// or when we cannot get it, the ConfigMap alone is then used.
+func (r *Run) getPACSettings(ctx context.Context) *v1alpha1.PACSettings {
+	if r.Clients.PipelineAsCode == nil {
+		return nil
+	}
+	pacSettings, err := r.Clients.PipelineAsCode.PipelinesascodeV1alpha1().PACSettings().Get(ctx, settings.PACSettingsName, v1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			r.Clients.Log.Warnf("cannot get the pacsettings %s, using only the configmap: %v", settings.PACSettingsName, err)
+		}
+		return nil
+	}
+	return pacSettings
+}
+
+// updatePACSettingsStatus report on the PACSettings if its settings have been
+// applied, the applied settings are kept from the previous status when the
+// new ones are invalid since we are still running with them.
+func (r *Run) updatePACSettingsStatus(ctx context.Context, pacSettings *v1alpha1.PACSettings, applied map[string]string, applyErr error) {
+	condition := apis.Condition{
+		Type:    apis.ConditionReady,
+		Status:  corev1.ConditionTrue,
+		Reason:  pacSettingsAppliedReason,
+		Message: "the settings have been applied",
+	}
+	if applyErr != nil {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = pacSettingsInvalidReason
+		condition.Message = applyErr.Error()
+		applied = pacSettings.Status.AppliedSettings
+	}
+
+	current := pacSettings.Status.GetCondition(apis.ConditionReady)
+	if current != nil && current.Status == condition.Status && current.Reason == condition.Reason &&
+		current.Message == condition.Message && pacSettings.Status.ObservedGeneration == pacSettings.GetGeneration() &&
+		reflect.DeepEqual(pacSettings.Status.AppliedSettings, applied) {
+		return
+	}
+
+	condition.LastTransitionTime = apis.VolatileTime{Inner: v1.Now()}
+	updated := pacSettings.DeepCopy()
+	updated.Status = v1alpha1.PACSettingsStatus{
+		Status: duckv1.Status{
+			ObservedGeneration: pacSettings.GetGeneration(),
+			Conditions:         duckv1.Conditions{condition},
+		},
+		AppliedSettings: applied,
+	}
+	if _, err := r.Clients.PipelineAsCode.PipelinesascodeV1alpha1().PACSettings().UpdateStatus(ctx, updated, v1.UpdateOptions{}); err != nil {
+		// the controller and the watcher both update the status, the other
+		// one may have been faster.
+		if !errors.IsConflict(err) {
+			r.Clients.Log.Warnf("cannot update the status of the pacsettings %s: %v", pacSettings.GetName(), err)
+		}
+	}
+}
+
+// WatchPACSettingsChanges update the settings when the PACSettings of the
+// cluster is created, modified or deleted, the watch is established again
+// when the API server closes it until the context is done. Nothing is watched
+// when the CRD is not installed.
+func (r *Run) WatchPACSettingsChanges(ctx context.Context) {
+	if r.Clients.PipelineAsCode == nil {
+		return
+	}
+	for {
+		r.watchPACSettings(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pacSettingsRewatchInterval):
+		}
+	}
+}
+
+// watchPACSettings watches the PACSettings of the cluster until the API
+// server closes the watch.
+func (r *Run) watchPACSettings(ctx context.Context) {
+	watcher, err := r.Clients.PipelineAsCode.PipelinesascodeV1alpha1().PACSettings().Watch(ctx, v1.SingleObject(v1.ObjectMeta{
+		Name: settings.PACSettingsName,
+	}))
+	if err != nil {
+		r.Clients.Log.Warnf("unable to watch the pacsettings %s: %v", settings.PACSettingsName, err)
+		return
+	}
+	defer watcher.Stop()
+	for event := range watcher.ResultChan() {
+		switch event.Type {
+		case watch.Added, watch.Modified, watch.Deleted:
+			if err := r.UpdatePACInfo(ctx); err != nil {
+				r.Clients.Log.Errorf("failed to update PAC info from the pacsettings: %v", err)
+			}
+		case watch.Bookmark, watch.Error:
+			// Do nothing
+		default:
+			// Do nothing
+		}
+	}
+}
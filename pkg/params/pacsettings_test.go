@@ -0,0 +1,99 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestUpdatePACInfoWithPACSettings(t *testing.T) {
+	lint := true
+	tests := []struct {
+		name            string
+		pacSettings     *v1alpha1.PACSettings
+		wantAppName     string
+		wantLint        bool
+		wantErr         string
+		wantReady       corev1.ConditionStatus
+		wantAppliedLint string
+	}{
+		{
+			name:        "only the configmap",
+			wantAppName: "ConfigMap CI",
+		},
+		{
+			name: "pacsettings override the configmap",
+			pacSettings: &v1alpha1.PACSettings{
+				ObjectMeta: metav1.ObjectMeta{Name: settings.PACSettingsName},
+				Spec:       v1alpha1.PACSettingsSpec{ApplicationName: "PACSettings CI", TektonLint: &lint},
+			},
+			wantAppName:     "PACSettings CI",
+			wantLint:        true,
+			wantReady:       corev1.ConditionTrue,
+			wantAppliedLint: "true",
+		},
+		{
+			name: "invalid pacsettings",
+			pacSettings: &v1alpha1.PACSettings{
+				ObjectMeta: metav1.ObjectMeta{Name: settings.PACSettingsName},
+				Spec:       v1alpha1.PACSettingsSpec{TektonDashboardURL: "not an url"},
+			},
+			wantErr:   "invalid value for key tekton-dashboard-url",
+			wantReady: corev1.ConditionFalse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SYSTEM_NAMESPACE", "pipelines-as-code")
+			ctx, _ := rtesting.SetupFakeContext(t)
+			tdata := testclient.Data{
+				ConfigMap: []*corev1.ConfigMap{{
+					ObjectMeta: metav1.ObjectMeta{Name: PACConfigmapName, Namespace: "pipelines-as-code"},
+					Data:       map[string]string{settings.ApplicationNameKey: "ConfigMap CI"},
+				}},
+			}
+			if tt.pacSettings != nil {
+				tdata.PACSettings = []*v1alpha1.PACSettings{tt.pacSettings}
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+
+			run := &Run{
+				Clients: clients.Clients{
+					Kube:           stdata.Kube,
+					PipelineAsCode: stdata.PipelineAsCode,
+					Log:            zap.New(observer).Sugar(),
+					ConsoleUI:      &consoleui.TektonDashboard{},
+				},
+				Info: New().Info,
+			}
+			err := run.UpdatePACInfo(ctx)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, run.Info.Pac.Settings.ApplicationName, tt.wantAppName)
+				assert.Equal(t, run.Info.Pac.Settings.TektonLint, tt.wantLint)
+			}
+
+			if tt.pacSettings == nil {
+				return
+			}
+			got, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().PACSettings().Get(ctx, settings.PACSettingsName, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, got.Status.GetCondition(apis.ConditionReady).Status, tt.wantReady)
+			assert.Equal(t, got.Status.AppliedSettings[settings.TektonLintKey], tt.wantAppliedLint)
+		})
+	}
+}
@@ -10,18 +10,27 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"go.uber.org/zap"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
 )
 
 const (
 	PACConfigmapName        = "pipelines-as-code"
+	LogProxySecretName      = "pipelines-as-code-log-proxy"
+	logProxySecretKey       = "secret"
+	logProxyClientIDKey     = "oauth-client-id"
+	logProxyClientSecretKey = "oauth-client-secret"
+	logProxyProviderKey     = "oauth-provider"
+	logProxyProviderURLKey  = "oauth-url"
 	StartingPipelineRunText = `Starting Pipelinerun <b>%s</b> in namespace
   <b>%s</b><br><br>You can follow the execution on the [%s](%s) PipelineRun viewer or via
   the command line with :
 	<br><code>%s pr logs -n %s %s</code>`
 	QueuingPipelineRunText = `PipelineRun <b>%s</b> has been queued Queuing in namespace
   <b>%s</b><br><br>`
+	FrozenPipelineRunText = `The Repository is frozen until <b>%s</b>, PipelineRun <b>%s</b> %s.<br><br>`
+	DraftPipelineRunText  = `The pull request is a draft, PipelineRun <b>%s</b> %s until it is marked ready for review.<br><br>`
 )
 
 type Run struct {
@@ -93,10 +102,36 @@ func (r *Run) UpdatePACInfo(ctx context.Context) error {
 		return err
 	}
 
-	if err = settings.ConfigToSettings(r.Clients.Log, r.Info.Pac.Settings, cfg.Data); err != nil {
+	// the settings of the PACSettings of the cluster override the ConfigMap
+	config := cfg.Data
+	pacSettings := r.getPACSettings(ctx)
+	if pacSettings != nil {
+		config = settings.MergeSpec(cfg.Data, pacSettings.Spec)
+	}
+	err = settings.ConfigToSettings(r.Clients.Log, r.Info.Pac.Settings, config)
+	if pacSettings != nil {
+		r.updatePACSettingsStatus(ctx, pacSettings, config, err)
+	}
+	if err != nil {
 		return err
 	}
 
+	if r.Info.Pac.Settings.LogProxyURL != "" {
+		proxy, err := r.getLogProxy(ctx, ns)
+		if err == nil {
+			if r.Info.Pac.Settings.LogProxyURL != r.Clients.ConsoleUI.URL() {
+				r.Clients.Log.Infof("serving the logs from the log proxy on: %s", r.Info.Pac.Settings.LogProxyURL)
+			}
+			r.Clients.ConsoleUI = proxy
+			return nil
+		}
+		r.Clients.Log.Errorf("cannot use the log proxy: %v", err)
+	}
+	if _, ok := r.Clients.ConsoleUI.(*consoleui.LogProxy); ok {
+		r.Clients.Log.Info("the log proxy is disabled")
+		r.Clients.ConsoleUI = consoleui.New(ctx, r.Clients.Dynamic, &r.Info)
+	}
+
 	if r.Info.Pac.Settings.TektonDashboardURL != "" && r.Info.Pac.Settings.TektonDashboardURL != r.Clients.ConsoleUI.URL() {
 		r.Clients.Log.Infof("updating console url to: %s", r.Info.Pac.Settings.TektonDashboardURL)
 		r.Clients.ConsoleUI = &consoleui.TektonDashboard{BaseURL: r.Info.Pac.Settings.TektonDashboardURL}
@@ -108,6 +143,94 @@ func (r *Run) UpdatePACInfo(ctx context.Context) error {
 	return nil
 }
 
+// getLogProxy returns the log proxy with the key encrypting its sessions and
+// its OAuth app from its secret in the installation namespace.
+func (r *Run) getLogProxy(ctx context.Context, ns string) (*consoleui.LogProxy, error) {
+	secret, err := r.Clients.Kube.CoreV1().Secrets(ns).Get(ctx, LogProxySecretName, v1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the secret %s/%s: %w", ns, LogProxySecretName, err)
+	}
+	for _, key := range []string{logProxySecretKey, logProxyClientIDKey, logProxyClientSecretKey} {
+		if len(secret.Data[key]) == 0 {
+			return nil, fmt.Errorf("the secret %s/%s has no %s key", ns, LogProxySecretName, key)
+		}
+	}
+	oauth := consoleui.LogProxyOAuth{
+		Provider:     string(secret.Data[logProxyProviderKey]),
+		URL:          strings.TrimSuffix(string(secret.Data[logProxyProviderURLKey]), "/"),
+		ClientID:     string(secret.Data[logProxyClientIDKey]),
+		ClientSecret: string(secret.Data[logProxyClientSecretKey]),
+	}
+	switch oauth.Provider {
+	case "", "github":
+		oauth.Provider = "github"
+		if oauth.URL == "" {
+			oauth.URL = "https://github.com"
+		}
+	case "gitlab":
+		if oauth.URL == "" {
+			oauth.URL = "https://gitlab.com"
+		}
+	case "gitea", "forgejo":
+		if oauth.URL == "" {
+			return nil, fmt.Errorf("the secret %s/%s has no %s key, it is needed with %s", ns, LogProxySecretName, logProxyProviderURLKey, oauth.Provider)
+		}
+	default:
+		return nil, fmt.Errorf("the log proxy cannot log in on the %s git provider, use github, gitlab, gitea or forgejo", oauth.Provider)
+	}
+	return &consoleui.LogProxy{
+		BaseURL: r.Info.Pac.Settings.LogProxyURL,
+		Key:     secret.Data[logProxySecretKey],
+		OAuth:   oauth,
+	}, nil
+}
+
+// CheckPACConfig checks the Pipelines as Code ConfigMap can be read and its
+// settings merged with the PACSettings are valid, without updating the
+// settings in use.
+func (r *Run) CheckPACConfig(ctx context.Context) error {
+	config, err := r.pacConfig(ctx)
+	if err != nil {
+		return err
+	}
+	settings.SetDefaults(config)
+	return settings.Validate(config)
+}
+
+// ReadPACSettings returns the settings of the Pipelines as Code ConfigMap
+// merged with the PACSettings, without updating the settings in use nor the
+// status of the PACSettings.
+func (r *Run) ReadPACSettings(ctx context.Context) (*settings.Settings, error) {
+	config, err := r.pacConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pacSettings := &settings.Settings{}
+	if err := settings.ConfigToSettings(zap.NewNop().Sugar(), pacSettings, config); err != nil {
+		return nil, err
+	}
+	return pacSettings, nil
+}
+
+func (r *Run) pacConfig(ctx context.Context) (map[string]string, error) {
+	ns := os.Getenv("SYSTEM_NAMESPACE")
+	if ns == "" {
+		return nil, fmt.Errorf("failed to find pipelines-as-code installation namespace")
+	}
+	cfg, err := r.Clients.Kube.CoreV1().ConfigMaps(ns).Get(ctx, PACConfigmapName, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	config := map[string]string{}
+	for k, v := range cfg.Data {
+		config[k] = v
+	}
+	if pacSettings := r.getPACSettings(ctx); pacSettings != nil {
+		config = settings.MergeSpec(config, pacSettings.Spec)
+	}
+	return config, nil
+}
+
 func New() *Run {
 	return &Run{
 		Info: info.Info{
@@ -0,0 +1,186 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"go.uber.org/zap"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCheckPACConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		configMap   map[string]string
+		noConfigMap bool
+		pacSettings *v1alpha1.PACSettings
+		wantErr     string
+	}{
+		{
+			name:      "valid",
+			configMap: map[string]string{settings.ApplicationNameKey: "ConfigMap CI"},
+		},
+		{
+			name:      "invalid configmap",
+			configMap: map[string]string{settings.SecretAutoCreateKey: "maybe"},
+			wantErr:   "invalid value for key secret-auto-create",
+		},
+		{
+			name:      "invalid pacsettings",
+			configMap: map[string]string{},
+			pacSettings: &v1alpha1.PACSettings{
+				ObjectMeta: metav1.ObjectMeta{Name: settings.PACSettingsName},
+				Spec:       v1alpha1.PACSettingsSpec{TektonDashboardURL: "not an url"},
+			},
+			wantErr: "invalid value for key tekton-dashboard-url",
+		},
+		{
+			name:        "no configmap",
+			noConfigMap: true,
+			wantErr:     "not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SYSTEM_NAMESPACE", "pipelines-as-code")
+			ctx, _ := rtesting.SetupFakeContext(t)
+			tdata := testclient.Data{}
+			if !tt.noConfigMap {
+				tdata.ConfigMap = []*corev1.ConfigMap{{
+					ObjectMeta: metav1.ObjectMeta{Name: PACConfigmapName, Namespace: "pipelines-as-code"},
+					Data:       tt.configMap,
+				}}
+			}
+			if tt.pacSettings != nil {
+				tdata.PACSettings = []*v1alpha1.PACSettings{tt.pacSettings}
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+			run := &Run{
+				Clients: clients.Clients{
+					Kube:           stdata.Kube,
+					PipelineAsCode: stdata.PipelineAsCode,
+					Log:            zap.NewNop().Sugar(),
+				},
+				Info: New().Info,
+			}
+			err := run.CheckPACConfig(ctx)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			// the settings in use are not updated by the check
+			assert.Assert(t, run.Info.Pac.Settings.ApplicationName != "ConfigMap CI")
+		})
+	}
+}
+
+func TestUpdatePACInfoLogProxy(t *testing.T) {
+	tests := []struct {
+		name        string
+		secret      *corev1.Secret
+		wantConsole string
+		wantOAuth   consoleui.LogProxyOAuth
+	}{
+		{
+			name: "log proxy",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: LogProxySecretName, Namespace: "pipelines-as-code"},
+				Data: map[string][]byte{
+					"secret":              []byte("key"),
+					"oauth-client-id":     []byte("id"),
+					"oauth-client-secret": []byte("secret"),
+				},
+			},
+			wantConsole: "Pipelines as Code log proxy",
+			wantOAuth:   consoleui.LogProxyOAuth{Provider: "github", URL: "https://github.com", ClientID: "id", ClientSecret: "secret"},
+		},
+		{
+			name: "log proxy on a gitea",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: LogProxySecretName, Namespace: "pipelines-as-code"},
+				Data: map[string][]byte{
+					"secret":              []byte("key"),
+					"oauth-client-id":     []byte("id"),
+					"oauth-client-secret": []byte("secret"),
+					"oauth-provider":      []byte("gitea"),
+					"oauth-url":           []byte("https://gitea.example.com/"),
+				},
+			},
+			wantConsole: "Pipelines as Code log proxy",
+			wantOAuth:   consoleui.LogProxyOAuth{Provider: "gitea", URL: "https://gitea.example.com", ClientID: "id", ClientSecret: "secret"},
+		},
+		{
+			name: "gitea without its url",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: LogProxySecretName, Namespace: "pipelines-as-code"},
+				Data: map[string][]byte{
+					"secret":              []byte("key"),
+					"oauth-client-id":     []byte("id"),
+					"oauth-client-secret": []byte("secret"),
+					"oauth-provider":      []byte("gitea"),
+				},
+			},
+			wantConsole: "Tekton Dashboard",
+		},
+		{
+			name: "no oauth app in the secret",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: LogProxySecretName, Namespace: "pipelines-as-code"},
+				Data:       map[string][]byte{"secret": []byte("key")},
+			},
+			wantConsole: "Tekton Dashboard",
+		},
+		{
+			name: "no key in the secret",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: LogProxySecretName, Namespace: "pipelines-as-code"},
+			},
+			wantConsole: "Tekton Dashboard",
+		},
+		{
+			name:        "no secret",
+			wantConsole: "Tekton Dashboard",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SYSTEM_NAMESPACE", "pipelines-as-code")
+			ctx, _ := rtesting.SetupFakeContext(t)
+			tdata := testclient.Data{
+				ConfigMap: []*corev1.ConfigMap{{
+					ObjectMeta: metav1.ObjectMeta{Name: PACConfigmapName, Namespace: "pipelines-as-code"},
+					Data: map[string]string{
+						settings.LogProxyURLKey:        "https://pac.example.com",
+						settings.TektonDashboardURLKey: "https://dashboard.example.com",
+					},
+				}},
+			}
+			if tt.secret != nil {
+				tdata.Secret = []*corev1.Secret{tt.secret}
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+			run := &Run{
+				Clients: clients.Clients{
+					Kube:           stdata.Kube,
+					PipelineAsCode: stdata.PipelineAsCode,
+					Log:            zap.NewNop().Sugar(),
+					ConsoleUI:      consoleui.FallBackConsole{},
+				},
+				Info: New().Info,
+			}
+			assert.NilError(t, run.UpdatePACInfo(ctx))
+			assert.Equal(t, run.Clients.ConsoleUI.GetName(), tt.wantConsole)
+			if proxy, ok := run.Clients.ConsoleUI.(*consoleui.LogProxy); ok {
+				assert.Equal(t, proxy.OAuth, tt.wantOAuth)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -46,6 +47,79 @@ const (
 
 	ErrorDetectionSimpleRegexpKey   = "error-detection-simple-regexp"
 	errorDetectionSimpleRegexpValue = `^(?P<filename>[^:]*):(?P<line>[0-9]+):(?P<column>[0-9]+):([ ]*)?(?P<error>.*)`
+
+	ErrorDetectionBlameKey     = "error-detection-blame"
+	ErrorDetectionBlameNone    = "none"
+	ErrorDetectionBlameMention = "mention"
+	ErrorDetectionBlameReview  = "review"
+
+	TektonLintKey   = "tekton-lint"
+	tektonLintValue = "false"
+
+	ProgressUpdatesKey   = "progress-updates"
+	progressUpdatesValue = "false"
+
+	DisablePullRequestCommentsKey   = "disable-pull-request-comments"
+	disablePullRequestCommentsValue = "false"
+
+	DisableCommitStatusesKey   = "disable-commit-statuses"
+	disableCommitStatusesValue = "false"
+
+	FreezeWindowsKey = "freeze-windows"
+
+	AutoProvisionRepositoriesKey       = "auto-provision-repositories"
+	autoProvisionRepositoriesValue     = "false"
+	AutoProvisionNamespaceTemplateKey  = "auto-provision-namespace-template"
+	AutoProvisionQuotaKey              = "auto-provision-quota"
+	AutoProvisionRepositoryTemplateKey = "auto-provision-repository-template"
+
+	CloudEventsSinkURLKey = "cloudevents-sink-url"
+
+	RegistryCredentialsBrokerURLKey = "registry-credentials-broker-url"
+
+	LogProxyURLKey = "log-proxy-url"
+
+	TektonResultsURLKey = "tekton-results-url"
+
+	VaultAddressKey          = "vault-address"
+	VaultRoleKey             = "vault-role"
+	VaultAuthMountKey        = "vault-auth-mount"
+	vaultAuthMountValue      = "kubernetes"
+	VaultKVMountKey          = "vault-kv-mount"
+	vaultKVMountDefaultValue = "secret"
+
+	ReplayMissedWebhooksKey   = "replay-missed-webhooks"
+	replayMissedWebhooksValue = "false"
+
+	DraftPullRequestsKey   = "draft-pull-requests"
+	DraftPullRequestsRun   = "run"
+	DraftPullRequestsSkip  = "skip"
+	DraftPullRequestsQueue = "queue"
+
+	BranchCleanupKey         = "branch-cleanup"
+	branchCleanupValue       = "false"
+	BranchCleanupDryRunKey   = "branch-cleanup-dry-run"
+	branchCleanupDryRunValue = "false"
+
+	CredentialsCheckIntervalKey   = "credentials-check-interval"
+	credentialsCheckIntervalValue = "24h"
+
+	AutoConfigureWebhooksKey   = "auto-configure-webhooks"
+	autoConfigureWebhooksValue = "false"
+
+	CheckRepositoryAccessKey   = "check-repository-access"
+	checkRepositoryAccessValue = "false"
+
+	DeliveryDeduplicationWindowKey   = "delivery-deduplication-window"
+	deliveryDeduplicationWindowValue = "1h"
+
+	MaxPipelineRunDurationKey   = "max-pipelinerun-duration"
+	maxPipelineRunDurationValue = "0"
+
+	SenderPrivacyKey  = "sender-privacy"
+	SenderPrivacyNone = "none"
+	SenderPrivacyOmit = "omit"
+	SenderPrivacyHash = "hash"
 )
 
 var TknBinaryName = `tkn`
@@ -71,6 +145,53 @@ type Settings struct {
 	ErrorDetection              bool
 	ErrorDetectionNumberOfLines int
 	ErrorDetectionSimpleRegexp  string
+	ErrorDetectionBlame         string
+
+	TektonLint bool
+
+	ProgressUpdates bool
+
+	DisablePullRequestComments bool
+	DisableCommitStatuses      bool
+
+	FreezeWindows string
+
+	AutoProvisionRepositories       bool
+	AutoProvisionNamespaceTemplate  string
+	AutoProvisionQuota              string
+	AutoProvisionRepositoryTemplate string
+
+	CloudEventsSinkURL string
+
+	RegistryCredentialsBrokerURL string
+
+	LogProxyURL string
+
+	TektonResultsURL string
+
+	VaultAddress   string
+	VaultRole      string
+	VaultAuthMount string
+	VaultKVMount   string
+
+	ReplayMissedWebhooks bool
+
+	DraftPullRequests string
+
+	BranchCleanup       bool
+	BranchCleanupDryRun bool
+
+	CredentialsCheckInterval time.Duration
+
+	AutoConfigureWebhooks bool
+
+	CheckRepositoryAccess bool
+
+	DeliveryDeduplicationWindow time.Duration
+
+	MaxPipelineRunDuration time.Duration
+
+	SenderPrivacy string
 }
 
 func ConfigToSettings(logger *zap.SugaredLogger, setting *Settings, config map[string]string) error {
@@ -175,6 +296,152 @@ func ConfigToSettings(logger *zap.SugaredLogger, setting *Settings, config map[s
 		setting.ErrorDetectionSimpleRegexp = strings.TrimSpace(config[ErrorDetectionSimpleRegexpKey])
 	}
 
+	if setting.ErrorDetection && setting.ErrorDetectionBlame != config[ErrorDetectionBlameKey] {
+		logger.Infof("CONFIG: setting error detection blame to %v", config[ErrorDetectionBlameKey])
+		setting.ErrorDetectionBlame = config[ErrorDetectionBlameKey]
+	}
+
+	tektonLint := StringToBool(config[TektonLintKey])
+	if setting.TektonLint != tektonLint {
+		logger.Infof("CONFIG: setting tekton lint to %v", tektonLint)
+		setting.TektonLint = tektonLint
+	}
+
+	progressUpdates := StringToBool(config[ProgressUpdatesKey])
+	if setting.ProgressUpdates != progressUpdates {
+		logger.Infof("CONFIG: setting progress updates to %v", progressUpdates)
+		setting.ProgressUpdates = progressUpdates
+	}
+
+	disablePullRequestComments := StringToBool(config[DisablePullRequestCommentsKey])
+	if setting.DisablePullRequestComments != disablePullRequestComments {
+		logger.Infof("CONFIG: setting disable pull request comments to %v", disablePullRequestComments)
+		setting.DisablePullRequestComments = disablePullRequestComments
+	}
+
+	disableCommitStatuses := StringToBool(config[DisableCommitStatusesKey])
+	if setting.DisableCommitStatuses != disableCommitStatuses {
+		logger.Infof("CONFIG: setting disable commit statuses to %v", disableCommitStatuses)
+		setting.DisableCommitStatuses = disableCommitStatuses
+	}
+
+	if setting.FreezeWindows != config[FreezeWindowsKey] {
+		logger.Infof("CONFIG: setting freeze windows to %v", config[FreezeWindowsKey])
+		setting.FreezeWindows = config[FreezeWindowsKey]
+	}
+
+	autoProvision := StringToBool(config[AutoProvisionRepositoriesKey])
+	if setting.AutoProvisionRepositories != autoProvision {
+		logger.Infof("CONFIG: setting auto provision repositories to %v", autoProvision)
+		setting.AutoProvisionRepositories = autoProvision
+	}
+	if setting.AutoProvisionNamespaceTemplate != config[AutoProvisionNamespaceTemplateKey] {
+		logger.Infof("CONFIG: setting auto provision namespace template to %v", config[AutoProvisionNamespaceTemplateKey])
+		setting.AutoProvisionNamespaceTemplate = config[AutoProvisionNamespaceTemplateKey]
+	}
+	if setting.AutoProvisionQuota != config[AutoProvisionQuotaKey] {
+		logger.Infof("CONFIG: setting auto provision quota to %v", config[AutoProvisionQuotaKey])
+		setting.AutoProvisionQuota = config[AutoProvisionQuotaKey]
+	}
+	if setting.AutoProvisionRepositoryTemplate != config[AutoProvisionRepositoryTemplateKey] {
+		logger.Infof("CONFIG: setting auto provision repository template to %v", config[AutoProvisionRepositoryTemplateKey])
+		setting.AutoProvisionRepositoryTemplate = config[AutoProvisionRepositoryTemplateKey]
+	}
+
+	if setting.CloudEventsSinkURL != config[CloudEventsSinkURLKey] {
+		logger.Infof("CONFIG: setting cloudevents sink url to %v", config[CloudEventsSinkURLKey])
+		setting.CloudEventsSinkURL = config[CloudEventsSinkURLKey]
+	}
+
+	if setting.RegistryCredentialsBrokerURL != config[RegistryCredentialsBrokerURLKey] {
+		logger.Infof("CONFIG: setting registry credentials broker url to %v", config[RegistryCredentialsBrokerURLKey])
+		setting.RegistryCredentialsBrokerURL = config[RegistryCredentialsBrokerURLKey]
+	}
+
+	if setting.LogProxyURL != config[LogProxyURLKey] {
+		logger.Infof("CONFIG: setting log proxy url to %v", config[LogProxyURLKey])
+		setting.LogProxyURL = config[LogProxyURLKey]
+	}
+
+	if setting.TektonResultsURL != config[TektonResultsURLKey] {
+		logger.Infof("CONFIG: setting tekton results url to %v", config[TektonResultsURLKey])
+		setting.TektonResultsURL = config[TektonResultsURLKey]
+	}
+
+	if setting.VaultAddress != config[VaultAddressKey] {
+		logger.Infof("CONFIG: setting vault address to %v", config[VaultAddressKey])
+		setting.VaultAddress = config[VaultAddressKey]
+	}
+	if setting.VaultRole != config[VaultRoleKey] {
+		logger.Infof("CONFIG: setting vault role to %v", config[VaultRoleKey])
+		setting.VaultRole = config[VaultRoleKey]
+	}
+	if setting.VaultAuthMount != config[VaultAuthMountKey] {
+		logger.Infof("CONFIG: setting vault auth mount to %v", config[VaultAuthMountKey])
+		setting.VaultAuthMount = config[VaultAuthMountKey]
+	}
+	if setting.VaultKVMount != config[VaultKVMountKey] {
+		logger.Infof("CONFIG: setting vault kv mount to %v", config[VaultKVMountKey])
+		setting.VaultKVMount = config[VaultKVMountKey]
+	}
+
+	replayMissedWebhooks := StringToBool(config[ReplayMissedWebhooksKey])
+	if setting.ReplayMissedWebhooks != replayMissedWebhooks {
+		logger.Infof("CONFIG: setting replay missed webhooks to %v", replayMissedWebhooks)
+		setting.ReplayMissedWebhooks = replayMissedWebhooks
+	}
+
+	if setting.DraftPullRequests != config[DraftPullRequestsKey] {
+		logger.Infof("CONFIG: setting draft pull requests to %v", config[DraftPullRequestsKey])
+		setting.DraftPullRequests = config[DraftPullRequestsKey]
+	}
+
+	branchCleanup := StringToBool(config[BranchCleanupKey])
+	if setting.BranchCleanup != branchCleanup {
+		logger.Infof("CONFIG: setting branch cleanup to %v", branchCleanup)
+		setting.BranchCleanup = branchCleanup
+	}
+	branchCleanupDryRun := StringToBool(config[BranchCleanupDryRunKey])
+	if setting.BranchCleanupDryRun != branchCleanupDryRun {
+		logger.Infof("CONFIG: setting branch cleanup dry run to %v", branchCleanupDryRun)
+		setting.BranchCleanupDryRun = branchCleanupDryRun
+	}
+
+	credentialsCheckInterval, _ := time.ParseDuration(config[CredentialsCheckIntervalKey])
+	if setting.CredentialsCheckInterval != credentialsCheckInterval {
+		logger.Infof("CONFIG: setting credentials check interval to %v", credentialsCheckInterval)
+		setting.CredentialsCheckInterval = credentialsCheckInterval
+	}
+
+	autoConfigureWebhooks := StringToBool(config[AutoConfigureWebhooksKey])
+	if setting.AutoConfigureWebhooks != autoConfigureWebhooks {
+		logger.Infof("CONFIG: setting auto configure webhooks to %v", autoConfigureWebhooks)
+		setting.AutoConfigureWebhooks = autoConfigureWebhooks
+	}
+
+	checkRepositoryAccess := StringToBool(config[CheckRepositoryAccessKey])
+	if setting.CheckRepositoryAccess != checkRepositoryAccess {
+		logger.Infof("CONFIG: setting check repository access to %v", checkRepositoryAccess)
+		setting.CheckRepositoryAccess = checkRepositoryAccess
+	}
+
+	deliveryDeduplicationWindow, _ := time.ParseDuration(config[DeliveryDeduplicationWindowKey])
+	if setting.DeliveryDeduplicationWindow != deliveryDeduplicationWindow {
+		logger.Infof("CONFIG: setting delivery deduplication window to %v", deliveryDeduplicationWindow)
+		setting.DeliveryDeduplicationWindow = deliveryDeduplicationWindow
+	}
+
+	maxPipelineRunDuration, _ := time.ParseDuration(config[MaxPipelineRunDurationKey])
+	if setting.MaxPipelineRunDuration != maxPipelineRunDuration {
+		logger.Infof("CONFIG: setting max pipelinerun duration to %v", maxPipelineRunDuration)
+		setting.MaxPipelineRunDuration = maxPipelineRunDuration
+	}
+
+	if setting.SenderPrivacy != config[SenderPrivacyKey] {
+		logger.Infof("CONFIG: setting sender privacy to %v", config[SenderPrivacyKey])
+		setting.SenderPrivacy = config[SenderPrivacyKey]
+	}
+
 	return nil
 }
 
@@ -50,4 +50,76 @@ func SetDefaults(config map[string]string) {
 	if errorDetectionSimpleRegexp, ok := config[ErrorDetectionSimpleRegexpKey]; !ok || errorDetectionSimpleRegexp == "" {
 		config[ErrorDetectionSimpleRegexpKey] = errorDetectionSimpleRegexpValue
 	}
+
+	if blame, ok := config[ErrorDetectionBlameKey]; !ok || blame == "" {
+		config[ErrorDetectionBlameKey] = ErrorDetectionBlameNone
+	}
+
+	if tektonLint, ok := config[TektonLintKey]; !ok || tektonLint == "" {
+		config[TektonLintKey] = tektonLintValue
+	}
+
+	if progressUpdates, ok := config[ProgressUpdatesKey]; !ok || progressUpdates == "" {
+		config[ProgressUpdatesKey] = progressUpdatesValue
+	}
+
+	if disableComments, ok := config[DisablePullRequestCommentsKey]; !ok || disableComments == "" {
+		config[DisablePullRequestCommentsKey] = disablePullRequestCommentsValue
+	}
+
+	if disableStatuses, ok := config[DisableCommitStatusesKey]; !ok || disableStatuses == "" {
+		config[DisableCommitStatusesKey] = disableCommitStatusesValue
+	}
+
+	if autoProvision, ok := config[AutoProvisionRepositoriesKey]; !ok || autoProvision == "" {
+		config[AutoProvisionRepositoriesKey] = autoProvisionRepositoriesValue
+	}
+
+	if replay, ok := config[ReplayMissedWebhooksKey]; !ok || replay == "" {
+		config[ReplayMissedWebhooksKey] = replayMissedWebhooksValue
+	}
+
+	if draft, ok := config[DraftPullRequestsKey]; !ok || draft == "" {
+		config[DraftPullRequestsKey] = DraftPullRequestsRun
+	}
+
+	if cleanup, ok := config[BranchCleanupKey]; !ok || cleanup == "" {
+		config[BranchCleanupKey] = branchCleanupValue
+	}
+
+	if dryRun, ok := config[BranchCleanupDryRunKey]; !ok || dryRun == "" {
+		config[BranchCleanupDryRunKey] = branchCleanupDryRunValue
+	}
+
+	if interval, ok := config[CredentialsCheckIntervalKey]; !ok || interval == "" {
+		config[CredentialsCheckIntervalKey] = credentialsCheckIntervalValue
+	}
+
+	if auto, ok := config[AutoConfigureWebhooksKey]; !ok || auto == "" {
+		config[AutoConfigureWebhooksKey] = autoConfigureWebhooksValue
+	}
+
+	if check, ok := config[CheckRepositoryAccessKey]; !ok || check == "" {
+		config[CheckRepositoryAccessKey] = checkRepositoryAccessValue
+	}
+
+	if window, ok := config[DeliveryDeduplicationWindowKey]; !ok || window == "" {
+		config[DeliveryDeduplicationWindowKey] = deliveryDeduplicationWindowValue
+	}
+
+	if duration, ok := config[MaxPipelineRunDurationKey]; !ok || duration == "" {
+		config[MaxPipelineRunDurationKey] = maxPipelineRunDurationValue
+	}
+
+	if privacy, ok := config[SenderPrivacyKey]; !ok || privacy == "" {
+		config[SenderPrivacyKey] = SenderPrivacyNone
+	}
+
+	if mount, ok := config[VaultAuthMountKey]; !ok || mount == "" {
+		config[VaultAuthMountKey] = vaultAuthMountValue
+	}
+
+	if mount, ok := config[VaultKVMountKey]; !ok || mount == "" {
+		config[VaultKVMountKey] = vaultKVMountDefaultValue
+	}
 }
@@ -0,0 +1,133 @@
+package settings
+
+import (
+	"strconv"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+)
+
+// PACSettingsName is the name of the PACSettings of the cluster, the
+// PACSettings with another name are refused by the webhook
+const PACSettingsName = "cluster"
+
+// specField map a key of the ConfigMap to a field of the PACSettingsSpec, only
+// one of str, boolean or integer is set.
+type specField struct {
+	key     string
+	str     *string
+	boolean **bool
+	integer **int
+}
+
+func specFields(spec *v1alpha1.PACSettingsSpec) []specField {
+	return []specField{
+		{key: ApplicationNameKey, str: &spec.ApplicationName},
+		{key: HubURLKey, str: &spec.HubURL},
+		{key: HubCatalogNameKey, str: &spec.HubCatalogName},
+		{key: RemoteTasksKey, boolean: &spec.RemoteTasks},
+		{key: MaxKeepRunUpperLimitKey, integer: &spec.MaxKeepRunUpperLimit},
+		{key: DefaultMaxKeepRunsKey, integer: &spec.DefaultMaxKeepRuns},
+		{key: BitbucketCloudCheckSourceIPKey, boolean: &spec.BitbucketCloudCheckSourceIP},
+		{key: BitbucketCloudAdditionalSourceIPKey, str: &spec.BitbucketCloudAdditionalSourceIP},
+		{key: TektonDashboardURLKey, str: &spec.TektonDashboardURL},
+		{key: AutoConfigureNewGitHubRepoKey, boolean: &spec.AutoConfigureNewGitHubRepo},
+		{key: AutoConfigureRepoNamespaceTemplateKey, str: &spec.AutoConfigureRepoNamespaceTemplate},
+		{key: SecretAutoCreateKey, boolean: &spec.SecretAutoCreate},
+		{key: SecretGhAppTokenRepoScopedKey, boolean: &spec.SecretGitHubAppTokenScoped},
+		{key: SecretGhAppTokenScopedExtraReposKey, str: &spec.SecretGitHubAppScopeExtraRepos},
+		{key: ErrorLogSnippetKey, boolean: &spec.ErrorLogSnippet},
+		{key: ErrorDetectionKey, boolean: &spec.ErrorDetectionFromContainerLogs},
+		{key: ErrorDetectionNumberOfLinesKey, integer: &spec.ErrorDetectionMaxNumberOfLines},
+		{key: ErrorDetectionSimpleRegexpKey, str: &spec.ErrorDetectionSimpleRegexp},
+		{key: ErrorDetectionBlameKey, str: &spec.ErrorDetectionBlame},
+		{key: TektonLintKey, boolean: &spec.TektonLint},
+		{key: ProgressUpdatesKey, boolean: &spec.ProgressUpdates},
+		{key: DisablePullRequestCommentsKey, boolean: &spec.DisablePullRequestComments},
+		{key: DisableCommitStatusesKey, boolean: &spec.DisableCommitStatuses},
+		{key: FreezeWindowsKey, str: &spec.FreezeWindows},
+		{key: AutoProvisionRepositoriesKey, boolean: &spec.AutoProvisionRepositories},
+		{key: AutoProvisionNamespaceTemplateKey, str: &spec.AutoProvisionNamespaceTemplate},
+		{key: AutoProvisionQuotaKey, str: &spec.AutoProvisionQuota},
+		{key: AutoProvisionRepositoryTemplateKey, str: &spec.AutoProvisionRepositoryTemplate},
+		{key: CloudEventsSinkURLKey, str: &spec.CloudEventsSinkURL},
+		{key: RegistryCredentialsBrokerURLKey, str: &spec.RegistryCredentialsBrokerURL},
+		{key: LogProxyURLKey, str: &spec.LogProxyURL},
+		{key: TektonResultsURLKey, str: &spec.TektonResultsURL},
+		{key: VaultAddressKey, str: &spec.VaultAddress},
+		{key: VaultRoleKey, str: &spec.VaultRole},
+		{key: VaultAuthMountKey, str: &spec.VaultAuthMount},
+		{key: VaultKVMountKey, str: &spec.VaultKVMount},
+		{key: ReplayMissedWebhooksKey, boolean: &spec.ReplayMissedWebhooks},
+		{key: DraftPullRequestsKey, str: &spec.DraftPullRequests},
+		{key: BranchCleanupKey, boolean: &spec.BranchCleanup},
+		{key: BranchCleanupDryRunKey, boolean: &spec.BranchCleanupDryRun},
+		{key: CredentialsCheckIntervalKey, str: &spec.CredentialsCheckInterval},
+		{key: AutoConfigureWebhooksKey, boolean: &spec.AutoConfigureWebhooks},
+		{key: CheckRepositoryAccessKey, boolean: &spec.CheckRepositoryAccess},
+		{key: DeliveryDeduplicationWindowKey, str: &spec.DeliveryDeduplicationWindow},
+		{key: MaxPipelineRunDurationKey, str: &spec.MaxPipelineRunDuration},
+		{key: SenderPrivacyKey, str: &spec.SenderPrivacy},
+	}
+}
+
+// SpecToConfig convert the spec of a PACSettings to the keys of the
+// ConfigMap, the unset fields are not in the config.
+func SpecToConfig(spec v1alpha1.PACSettingsSpec) map[string]string {
+	config := map[string]string{}
+	for _, field := range specFields(&spec) {
+		switch {
+		case field.str != nil && *field.str != "":
+			config[field.key] = *field.str
+		case field.boolean != nil && *field.boolean != nil:
+			config[field.key] = strconv.FormatBool(**field.boolean)
+		case field.integer != nil && *field.integer != nil:
+			config[field.key] = strconv.Itoa(**field.integer)
+		}
+	}
+	return config
+}
+
+// ConfigToSpec convert the keys of the ConfigMap to the spec of a
+// PACSettings, the empty keys and the integers we cannot parse are left unset.
+func ConfigToSpec(config map[string]string) v1alpha1.PACSettingsSpec {
+	spec := v1alpha1.PACSettingsSpec{}
+	for _, field := range specFields(&spec) {
+		value, ok := config[field.key]
+		if !ok || value == "" {
+			continue
+		}
+		switch {
+		case field.str != nil:
+			*field.str = value
+		case field.boolean != nil:
+			boolean := StringToBool(value)
+			*field.boolean = &boolean
+		case field.integer != nil:
+			if integer, err := strconv.Atoi(value); err == nil {
+				*field.integer = &integer
+			}
+		}
+	}
+	return spec
+}
+
+// MergeSpec return a copy of the config of the ConfigMap with the fields set
+// in the spec of the PACSettings overriding its keys.
+func MergeSpec(config map[string]string, spec v1alpha1.PACSettingsSpec) map[string]string {
+	merged := make(map[string]string, len(config))
+	for key, value := range config {
+		merged[key] = value
+	}
+	for key, value := range SpecToConfig(spec) {
+		merged[key] = value
+	}
+	return merged
+}
+
+// DefaultSpec set the unset fields of the spec from the config of the
+// ConfigMap and from the defaults.
+func DefaultSpec(spec *v1alpha1.PACSettingsSpec, config map[string]string) {
+	merged := MergeSpec(config, *spec)
+	SetDefaults(merged)
+	*spec = ConfigToSpec(merged)
+}
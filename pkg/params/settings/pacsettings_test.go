@@ -0,0 +1,64 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"gotest.tools/v3/assert"
+)
+
+func TestSpecToConfig(t *testing.T) {
+	remoteTasks := false
+	maxKeepRuns := 5
+	spec := v1alpha1.PACSettingsSpec{
+		ApplicationName:    "My CI",
+		RemoteTasks:        &remoteTasks,
+		DefaultMaxKeepRuns: &maxKeepRuns,
+	}
+	config := SpecToConfig(spec)
+	assert.DeepEqual(t, config, map[string]string{
+		ApplicationNameKey:    "My CI",
+		RemoteTasksKey:        "false",
+		DefaultMaxKeepRunsKey: "5",
+	})
+	assert.DeepEqual(t, ConfigToSpec(config), spec)
+}
+
+func TestConfigToSpec(t *testing.T) {
+	spec := ConfigToSpec(map[string]string{
+		HubURLKey:               "https://hub",
+		TektonLintKey:           "yes",
+		MaxKeepRunUpperLimitKey: "many",
+		FreezeWindowsKey:        "",
+	})
+	assert.Equal(t, spec.HubURL, "https://hub")
+	assert.Equal(t, *spec.TektonLint, true)
+	assert.Assert(t, spec.MaxKeepRunUpperLimit == nil)
+	assert.Equal(t, spec.FreezeWindows, "")
+}
+
+func TestMergeSpec(t *testing.T) {
+	disable := true
+	config := map[string]string{
+		ApplicationNameKey:       "ConfigMap CI",
+		DisableCommitStatusesKey: "false",
+	}
+	merged := MergeSpec(config, v1alpha1.PACSettingsSpec{DisableCommitStatuses: &disable})
+	assert.Equal(t, merged[ApplicationNameKey], "ConfigMap CI")
+	assert.Equal(t, merged[DisableCommitStatusesKey], "true")
+	// the config of the ConfigMap is not modified
+	assert.Equal(t, config[DisableCommitStatusesKey], "false")
+}
+
+func TestDefaultSpec(t *testing.T) {
+	spec := v1alpha1.PACSettingsSpec{ApplicationName: "CR CI"}
+	DefaultSpec(&spec, map[string]string{
+		ApplicationNameKey: "ConfigMap CI",
+		HubCatalogNameKey:  "custom",
+	})
+	assert.Equal(t, spec.ApplicationName, "CR CI")
+	assert.Equal(t, spec.HubCatalogName, "custom")
+	assert.Equal(t, spec.HubURL, HubURLDefaultValue)
+	assert.Equal(t, *spec.RemoteTasks, true)
+	assert.Equal(t, *spec.ErrorDetectionMaxNumberOfLines, errorDetectionNumberOfLinesValue)
+}
@@ -5,6 +5,10 @@ import (
 	"net/url"
 	"regexp"
 	"strconv"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/freeze"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provision"
 )
 
 func Validate(config map[string]string) error {
@@ -63,9 +67,122 @@ func Validate(config map[string]string) error {
 			return fmt.Errorf("cannot use %v as regexp for error detection: %w", config[ErrorDetectionSimpleRegexpKey], err)
 		}
 	}
+
+	if blame, ok := config[ErrorDetectionBlameKey]; ok && blame != "" {
+		if blame != ErrorDetectionBlameNone && blame != ErrorDetectionBlameMention && blame != ErrorDetectionBlameReview {
+			return fmt.Errorf("invalid value for key %v, acceptable values: none, mention or review", ErrorDetectionBlameKey)
+		}
+	}
+
+	if freezeWindows, ok := config[FreezeWindowsKey]; ok && freezeWindows != "" {
+		if _, err := freeze.ParseWindows(freezeWindows); err != nil {
+			return fmt.Errorf("invalid value for key %v: %w", FreezeWindowsKey, err)
+		}
+	}
+
+	if check, ok := config[AutoProvisionRepositoriesKey]; ok && check != "" {
+		if !isValidBool(check) {
+			return fmt.Errorf("invalid value for key %v, acceptable values: true or false", AutoProvisionRepositoriesKey)
+		}
+	}
+
+	if quota, ok := config[AutoProvisionQuotaKey]; ok && quota != "" {
+		if _, err := provision.ParseQuota(quota); err != nil {
+			return fmt.Errorf("invalid value for key %v: %w", AutoProvisionQuotaKey, err)
+		}
+	}
+
+	if template, ok := config[AutoProvisionRepositoryTemplateKey]; ok && template != "" {
+		if _, err := provision.ParseRepositoryTemplate(template); err != nil {
+			return fmt.Errorf("invalid value for key %v: %w", AutoProvisionRepositoryTemplateKey, err)
+		}
+	}
+
+	if sinkURL, ok := config[CloudEventsSinkURLKey]; ok && sinkURL != "" {
+		if _, err := url.ParseRequestURI(sinkURL); err != nil {
+			return fmt.Errorf("invalid value for key %v, invalid url: %w", CloudEventsSinkURLKey, err)
+		}
+	}
+
+	if brokerURL, ok := config[RegistryCredentialsBrokerURLKey]; ok && brokerURL != "" {
+		if _, err := url.ParseRequestURI(brokerURL); err != nil {
+			return fmt.Errorf("invalid value for key %v, invalid url: %w", RegistryCredentialsBrokerURLKey, err)
+		}
+	}
+
+	if logProxyURL, ok := config[LogProxyURLKey]; ok && logProxyURL != "" {
+		if _, err := url.ParseRequestURI(logProxyURL); err != nil {
+			return fmt.Errorf("invalid value for key %v, invalid url: %w", LogProxyURLKey, err)
+		}
+	}
+
+	if resultsURL, ok := config[TektonResultsURLKey]; ok && resultsURL != "" {
+		if _, err := url.ParseRequestURI(resultsURL); err != nil {
+			return fmt.Errorf("invalid value for key %v, invalid url: %w", TektonResultsURLKey, err)
+		}
+	}
+
+	if vaultAddress, ok := config[VaultAddressKey]; ok && vaultAddress != "" {
+		if _, err := url.ParseRequestURI(vaultAddress); err != nil {
+			return fmt.Errorf("invalid value for key %v, invalid url: %w", VaultAddressKey, err)
+		}
+		if config[VaultRoleKey] == "" {
+			return fmt.Errorf("key %v needs to be set with %v", VaultRoleKey, VaultAddressKey)
+		}
+	}
+
+	if check, ok := config[ReplayMissedWebhooksKey]; ok && check != "" {
+		if !isValidBool(check) {
+			return fmt.Errorf("invalid value for key %v, acceptable values: true or false", ReplayMissedWebhooksKey)
+		}
+	}
+
+	if draft, ok := config[DraftPullRequestsKey]; ok && draft != "" {
+		if !IsValidDraftPullRequests(draft) {
+			return fmt.Errorf("invalid value for key %v, acceptable values: run, skip or queue", DraftPullRequestsKey)
+		}
+	}
+
+	for _, key := range []string{BranchCleanupKey, BranchCleanupDryRunKey, AutoConfigureWebhooksKey, CheckRepositoryAccessKey} {
+		if check, ok := config[key]; ok && check != "" {
+			if !isValidBool(check) {
+				return fmt.Errorf("invalid value for key %v, acceptable values: true or false", key)
+			}
+		}
+	}
+
+	if interval, ok := config[CredentialsCheckIntervalKey]; ok && interval != "" {
+		if duration, err := time.ParseDuration(interval); err != nil || duration < 0 {
+			return fmt.Errorf("invalid value for key %v, acceptable values: a duration like 24h or 0 to disable", CredentialsCheckIntervalKey)
+		}
+	}
+
+	if window, ok := config[DeliveryDeduplicationWindowKey]; ok && window != "" {
+		if duration, err := time.ParseDuration(window); err != nil || duration < 0 {
+			return fmt.Errorf("invalid value for key %v, acceptable values: a duration like 1h or 0 to disable", DeliveryDeduplicationWindowKey)
+		}
+	}
+
+	if maxDuration, ok := config[MaxPipelineRunDurationKey]; ok && maxDuration != "" {
+		if duration, err := time.ParseDuration(maxDuration); err != nil || duration < 0 {
+			return fmt.Errorf("invalid value for key %v, acceptable values: a duration like 2h or 0 to disable", MaxPipelineRunDurationKey)
+		}
+	}
+
+	if privacy, ok := config[SenderPrivacyKey]; ok && privacy != "" {
+		if privacy != SenderPrivacyNone && privacy != SenderPrivacyOmit && privacy != SenderPrivacyHash {
+			return fmt.Errorf("invalid value for key %v, acceptable values: none, omit or hash", SenderPrivacyKey)
+		}
+	}
 	return nil
 }
 
+// IsValidDraftPullRequests check how the PipelineRuns of the draft pull
+// requests are handled, by the setting or by the annotation of a PipelineRun.
+func IsValidDraftPullRequests(value string) bool {
+	return value == DraftPullRequestsRun || value == DraftPullRequestsSkip || value == DraftPullRequestsQueue
+}
+
 func isValidBool(value string) bool {
 	return value == "true" || value == "false"
 }
@@ -58,6 +58,143 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: "invalid value for key tekton-dashboard-url, invalid url: parse \"abc.xyz\": invalid URI for request",
 		},
+		{
+			name: "valid freeze windows",
+			config: map[string]string{
+				FreezeWindowsKey: "- schedule: \"0 18 * * 5\"\n  duration: 62h\n  action: queue\n",
+			},
+			wantErr: "",
+		},
+		{
+			name: "invalid freeze windows",
+			config: map[string]string{
+				FreezeWindowsKey: "- schedule: \"0 18 * * 5\"\n  duration: 62h\n  action: wait\n",
+			},
+			wantErr: "invalid value for key freeze-windows: invalid freeze window action \"wait\": use skip or queue",
+		},
+		{
+			name: "valid draft pull requests",
+			config: map[string]string{
+				DraftPullRequestsKey: "queue",
+			},
+			wantErr: "",
+		},
+		{
+			name: "invalid draft pull requests",
+			config: map[string]string{
+				DraftPullRequestsKey: "wait",
+			},
+			wantErr: "invalid value for key draft-pull-requests, acceptable values: run, skip or queue",
+		},
+		{
+			name: "valid auto provision",
+			config: map[string]string{
+				AutoProvisionRepositoriesKey:       "true",
+				AutoProvisionQuotaKey:              "pods=10,requests.cpu=4",
+				AutoProvisionRepositoryTemplateKey: "concurrency_limit: 2\n",
+			},
+			wantErr: "",
+		},
+		{
+			name: "invalid auto provision quota",
+			config: map[string]string{
+				AutoProvisionQuotaKey: "pods",
+			},
+			wantErr: "invalid value for key auto-provision-quota: invalid quota \"pods\", expected resource=quantity",
+		},
+		{
+			name: "invalid auto provision repository template",
+			config: map[string]string{
+				AutoProvisionRepositoryTemplateKey: "concurrency: 2\n",
+			},
+			wantErr: "invalid value for key auto-provision-repository-template: cannot parse repository template: error unmarshaling JSON: while decoding JSON: json: unknown field \"concurrency\"",
+		},
+		{
+			name: "invalid cloudevents sink url",
+			config: map[string]string{
+				CloudEventsSinkURLKey: "sink",
+			},
+			wantErr: "invalid value for key cloudevents-sink-url, invalid url: parse \"sink\": invalid URI for request",
+		},
+		{
+			name: "invalid error detection blame",
+			config: map[string]string{
+				ErrorDetectionBlameKey: "everyone",
+			},
+			wantErr: "invalid value for key error-detection-blame, acceptable values: none, mention or review",
+		},
+		{
+			name: "invalid vault address",
+			config: map[string]string{
+				VaultAddressKey: "vault",
+				VaultRoleKey:    "pac",
+			},
+			wantErr: "invalid value for key vault-address, invalid url: parse \"vault\": invalid URI for request",
+		},
+		{
+			name: "vault address without role",
+			config: map[string]string{
+				VaultAddressKey: "https://vault:8200",
+			},
+			wantErr: "key vault-role needs to be set with vault-address",
+		},
+		{
+			name: "invalid registry credentials broker url",
+			config: map[string]string{
+				RegistryCredentialsBrokerURLKey: "broker",
+			},
+			wantErr: "invalid value for key registry-credentials-broker-url, invalid url: parse \"broker\": invalid URI for request",
+		},
+		{
+			name: "invalid log proxy url",
+			config: map[string]string{
+				LogProxyURLKey: "proxy",
+			},
+			wantErr: "invalid value for key log-proxy-url, invalid url: parse \"proxy\": invalid URI for request",
+		},
+		{
+			name: "invalid tekton results url",
+			config: map[string]string{
+				TektonResultsURLKey: "results",
+			},
+			wantErr: "invalid value for key tekton-results-url, invalid url: parse \"results\": invalid URI for request",
+		},
+		{
+			name: "invalid replay missed webhooks",
+			config: map[string]string{
+				ReplayMissedWebhooksKey: "sometimes",
+			},
+			wantErr: "invalid value for key replay-missed-webhooks, acceptable values: true or false",
+		},
+		{
+			name: "invalid branch cleanup dry run",
+			config: map[string]string{
+				BranchCleanupKey:       "true",
+				BranchCleanupDryRunKey: "maybe",
+			},
+			wantErr: "invalid value for key branch-cleanup-dry-run, acceptable values: true or false",
+		},
+		{
+			name: "invalid credentials check interval",
+			config: map[string]string{
+				CredentialsCheckIntervalKey: "daily",
+			},
+			wantErr: "invalid value for key credentials-check-interval, acceptable values: a duration like 24h or 0 to disable",
+		},
+		{
+			name: "invalid max pipelinerun duration",
+			config: map[string]string{
+				MaxPipelineRunDurationKey: "-1h",
+			},
+			wantErr: "invalid value for key max-pipelinerun-duration, acceptable values: a duration like 2h or 0 to disable",
+		},
+		{
+			name: "invalid sender privacy",
+			config: map[string]string{
+				SenderPrivacyKey: "mask",
+			},
+			wantErr: "invalid value for key sender-privacy, acceptable values: none, omit or hash",
+		},
 		{
 			name: "empty values",
 			config: map[string]string{
@@ -0,0 +1,158 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provision"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+const branchCleanupReason = "RepositoryBranchCleanup"
+
+// branchLabelValues returns the values of the branch label of the resources
+// of a branch, the label of the PipelineRuns has the full ref of a push while
+// users label their resources with the name of the branch.
+func branchLabelValues(branch string) []string {
+	values := []string{formatting.K8LabelsCleanup(branch)}
+	if short := formatting.K8LabelsCleanup(strings.TrimPrefix(branch, "refs/heads/")); short != values[0] {
+		values = append(values, short)
+	}
+	return values
+}
+
+// branchSelector select the resources of the branch of the Repository, with
+// the extra labels.
+func branchSelector(repo *v1alpha1.Repository, branch string, extra map[string]string) (string, error) {
+	selector := labels.NewSelector()
+	branchReq, err := labels.NewRequirement(keys.Branch, selection.In, branchLabelValues(branch))
+	if err != nil {
+		return "", err
+	}
+	selector = selector.Add(*branchReq)
+
+	extra[keys.Repository] = formatting.K8LabelsCleanup(repo.GetName())
+	for k, v := range extra {
+		req, err := labels.NewRequirement(k, selection.Equals, []string{v})
+		if err != nil {
+			return "", err
+		}
+		selector = selector.Add(*req)
+	}
+	return selector.String(), nil
+}
+
+// cleanupDeletedBranch cleans up the resources of a branch deleted by a push:
+// its queued PipelineRuns which would fail to fetch it once started, and the
+// PersistentVolumeClaims and the Namespaces labelled with the Repository and
+// the branch. Every resource cleaned up, or only found on a dry run, is
+// recorded as an event of the Repository.
+func (p *PacRun) cleanupDeletedBranch(ctx context.Context, repo *v1alpha1.Repository) {
+	branch := p.event.BaseBranch
+	if !p.run.Info.Pac.BranchCleanup {
+		p.logger.Infof("branch %s has been deleted, not cleaning up its resources since %s is disabled", branch, settings.BranchCleanupKey)
+		return
+	}
+
+	if err := p.cleanupBranchPipelineRuns(ctx, repo, branch); err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, branchCleanupReason,
+			fmt.Sprintf("cannot cleanup the queued pipelineruns of the deleted branch %s: %s", branch, err.Error()))
+	}
+	if err := p.cleanupBranchPersistentVolumeClaims(ctx, repo, branch); err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, branchCleanupReason,
+			fmt.Sprintf("cannot cleanup the persistentvolumeclaims of the deleted branch %s: %s", branch, err.Error()))
+	}
+	if err := p.cleanupBranchNamespaces(ctx, repo, branch); err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, branchCleanupReason,
+			fmt.Sprintf("cannot cleanup the namespaces of the deleted branch %s: %s", branch, err.Error()))
+	}
+}
+
+func (p *PacRun) cleanupBranchPipelineRuns(ctx context.Context, repo *v1alpha1.Repository, branch string) error {
+	selector, err := branchSelector(repo, branch, map[string]string{keys.State: kubeinteraction.StateQueued})
+	if err != nil {
+		return err
+	}
+	prs, err := p.run.Clients.Tekton.TektonV1beta1().PipelineRuns(repo.GetNamespace()).List(ctx, v1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	for _, pr := range prs.Items {
+		p.deleteBranchResource(repo, branch, "pipelinerun", fmt.Sprintf("%s/%s", pr.GetNamespace(), pr.GetName()), func() error {
+			return p.run.Clients.Tekton.TektonV1beta1().PipelineRuns(pr.GetNamespace()).Delete(ctx, pr.GetName(), v1.DeleteOptions{})
+		})
+	}
+	return nil
+}
+
+func (p *PacRun) cleanupBranchPersistentVolumeClaims(ctx context.Context, repo *v1alpha1.Repository, branch string) error {
+	selector, err := branchSelector(repo, branch, map[string]string{})
+	if err != nil {
+		return err
+	}
+	pvcs, err := p.run.Clients.Kube.CoreV1().PersistentVolumeClaims(repo.GetNamespace()).List(ctx, v1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	for _, pvc := range pvcs.Items {
+		p.deleteBranchResource(repo, branch, "persistentvolumeclaim", fmt.Sprintf("%s/%s", pvc.GetNamespace(), pvc.GetName()), func() error {
+			return p.run.Clients.Kube.CoreV1().PersistentVolumeClaims(pvc.GetNamespace()).Delete(ctx, pvc.GetName(), v1.DeleteOptions{})
+		})
+	}
+	return nil
+}
+
+// cleanupBranchNamespaces deletes the Namespaces created for the branch, they
+// are cluster wide so they need to be labelled with the namespace of the
+// Repository and as managed by Pipelines as Code too. The namespace of the
+// Repository is never deleted.
+func (p *PacRun) cleanupBranchNamespaces(ctx context.Context, repo *v1alpha1.Repository, branch string) error {
+	selector, err := branchSelector(repo, branch, map[string]string{
+		keys.RepositoryNamespace: repo.GetNamespace(),
+		provision.ManagedByLabel: pipelinesascode.GroupName,
+	})
+	if err != nil {
+		return err
+	}
+	namespaces, err := p.run.Clients.Kube.CoreV1().Namespaces().List(ctx, v1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	for _, ns := range namespaces.Items {
+		if ns.GetName() == repo.GetNamespace() {
+			continue
+		}
+		p.deleteBranchResource(repo, branch, "namespace", ns.GetName(), func() error {
+			return p.run.Clients.Kube.CoreV1().Namespaces().Delete(ctx, ns.GetName(), v1.DeleteOptions{})
+		})
+	}
+	return nil
+}
+
+// deleteBranchResource deletes a resource of the deleted branch, or only
+// reports it on a dry run.
+func (p *PacRun) deleteBranchResource(repo *v1alpha1.Repository, branch, kind, name string, deleteFunc func() error) {
+	if p.run.Info.Pac.BranchCleanupDryRun {
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, branchCleanupReason,
+			fmt.Sprintf("dry run: would delete %s %s of the deleted branch %s", kind, name, branch))
+		return
+	}
+	if err := deleteFunc(); err != nil && !errors.IsNotFound(err) {
+		p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, branchCleanupReason,
+			fmt.Sprintf("cannot delete %s %s of the deleted branch %s: %s", kind, name, branch, err.Error()))
+		return
+	}
+	p.eventEmitter.EmitMessage(repo, zap.InfoLevel, branchCleanupReason,
+		fmt.Sprintf("deleted %s %s of the deleted branch %s", kind, name, branch))
+}
@@ -0,0 +1,141 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provision"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestBranchLabelValues(t *testing.T) {
+	assert.DeepEqual(t, branchLabelValues("refs/heads/feature/foo"), []string{"refs-heads-feature-foo", "feature-foo"})
+	assert.DeepEqual(t, branchLabelValues("feature"), []string{"feature"})
+}
+
+func TestCleanupDeletedBranch(t *testing.T) {
+	branchLabels := func(branch string, extra map[string]string) map[string]string {
+		l := map[string]string{
+			keys.Repository: "foo",
+			keys.Branch:     branch,
+		}
+		for k, v := range extra {
+			l[k] = v
+		}
+		return l
+	}
+	tests := []struct {
+		name        string
+		cleanup     bool
+		dryRun      bool
+		wantDeleted []string
+		wantEvents  int
+	}{
+		{
+			name:    "cleanup disabled",
+			cleanup: false,
+		},
+		{
+			name:        "cleanup",
+			cleanup:     true,
+			wantDeleted: []string{"queued", "cache", "preview"},
+			wantEvents:  3,
+		},
+		{
+			name:       "dry run",
+			cleanup:    true,
+			dryRun:     true,
+			wantEvents: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			queued := map[string]string{keys.State: kubeinteraction.StateQueued}
+			started := map[string]string{keys.State: kubeinteraction.StateStarted}
+			repoNamespace := map[string]string{keys.RepositoryNamespace: "foo"}
+			managed := map[string]string{keys.RepositoryNamespace: "foo", provision.ManagedByLabel: pipelinesascode.GroupName}
+			tdata := testclient.Data{
+				PipelineRuns: []*pipelinev1beta1.PipelineRun{
+					{ObjectMeta: metav1.ObjectMeta{Name: "queued", Namespace: "foo", Labels: branchLabels("refs-heads-feature", queued)}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "started", Namespace: "foo", Labels: branchLabels("refs-heads-feature", started)}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "other-branch", Namespace: "foo", Labels: branchLabels("refs-heads-main", queued)}},
+				},
+				Namespaces: []*corev1.Namespace{
+					{ObjectMeta: metav1.ObjectMeta{Name: "foo", Labels: branchLabels("feature", managed)}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "preview", Labels: branchLabels("feature", managed)}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "not-managed", Labels: branchLabels("feature", repoNamespace)}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "no-repository-namespace", Labels: branchLabels("feature", nil)}},
+				},
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+			for name, l := range map[string]map[string]string{
+				"cache":       branchLabels("feature", nil),
+				"other-cache": branchLabels("main", nil),
+			} {
+				_, err := stdata.Kube.CoreV1().PersistentVolumeClaims("foo").Create(ctx, &corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "foo", Labels: l},
+				}, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+
+			observer, logs := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			run := &params.Run{
+				Clients: clients.Clients{Log: logger, Tekton: stdata.Pipeline, Kube: stdata.Kube},
+				Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{
+					BranchCleanup:       tt.cleanup,
+					BranchCleanupDryRun: tt.dryRun,
+				}}},
+			}
+			event := &info.Event{BaseBranch: "refs/heads/feature", TriggerTarget: "push"}
+			pac := NewPacs(event, nil, run, nil, logger)
+			pac.cleanupDeletedBranch(ctx, fooRepo)
+
+			deleted := map[string]bool{}
+			for _, name := range tt.wantDeleted {
+				deleted[name] = true
+			}
+			prs, err := stdata.Pipeline.TektonV1beta1().PipelineRuns("foo").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			pvcs, err := stdata.Kube.CoreV1().PersistentVolumeClaims("foo").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			namespaces, err := stdata.Kube.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			remaining := []string{}
+			for _, pr := range prs.Items {
+				remaining = append(remaining, pr.GetName())
+			}
+			for _, pvc := range pvcs.Items {
+				remaining = append(remaining, pvc.GetName())
+			}
+			for _, ns := range namespaces.Items {
+				remaining = append(remaining, ns.GetName())
+			}
+			assert.Equal(t, len(remaining), 9-len(tt.wantDeleted))
+			for _, name := range remaining {
+				assert.Assert(t, !deleted[name], "%s should have been deleted", name)
+			}
+
+			// the fake client does not generate the names of the events, we
+			// count their messages in the logs
+			assert.Equal(t, logs.FilterMessageSnippet("of the deleted branch refs/heads/feature").Len(), tt.wantEvents)
+			if tt.dryRun {
+				assert.Equal(t, logs.FilterMessageSnippet("dry run: would delete").Len(), tt.wantEvents)
+			}
+		})
+	}
+}
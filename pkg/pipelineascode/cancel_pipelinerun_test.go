@@ -7,6 +7,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
@@ -145,6 +146,44 @@ func TestCancelPipelinerun(t *testing.T) {
 				"pr-foo-abc-123": true,
 			},
 		},
+		{
+			name: "cancel a specific run of a push",
+			event: &info.Event{
+				Repository:    "foo",
+				SHA:           "foosha",
+				TriggerTarget: "push",
+				State: info.State{
+					CancelPipelineRuns:      true,
+					TargetCancelPipelineRun: "pr-foo-abc",
+				},
+			},
+			pipelineRuns: []*pipelinev1beta1.PipelineRun{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "push-foo-abc-123",
+						Namespace: "foo",
+						Labels: map[string]string{
+							keys.URLRepository:  formatting.K8LabelsCleanup("foo"),
+							keys.SHA:            formatting.K8LabelsCleanup("foosha"),
+							keys.OriginalPRName: "pr-foo-abc",
+						},
+					},
+					Spec: pipelinev1beta1.PipelineRunSpec{},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pr-foo",
+						Namespace: "foo",
+						Labels:    fooRepoLabels,
+					},
+					Spec: pipelinev1beta1.PipelineRunSpec{},
+				},
+			},
+			repo: fooRepo,
+			cancelledPipelineRuns: map[string]bool{
+				"push-foo-abc-123": true,
+			},
+		},
 		{
 			name: "cancelling a done pipelinerun or already cancelled pipelinerun",
 			event: &info.Event{
@@ -224,3 +263,255 @@ func TestCancelPipelinerun(t *testing.T) {
 		})
 	}
 }
+
+func TestCancelInProgressPipelineRuns(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	cancelInProgress := map[string]string{keys.CancelInProgress: "true"}
+	oldShaLabels := map[string]string{
+		keys.URLRepository: formatting.K8LabelsCleanup("foo"),
+		keys.SHA:           formatting.K8LabelsCleanup("oldsha"),
+		keys.PullRequest:   strconv.Itoa(11),
+	}
+	pipelineRuns := func() []*pipelinev1beta1.PipelineRun {
+		return []*pipelinev1beta1.PipelineRun{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "old-sha-cancel",
+					Namespace:   "foo",
+					Labels:      oldShaLabels,
+					Annotations: cancelInProgress,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "old-sha-no-annotation",
+					Namespace: "foo",
+					Labels:    oldShaLabels,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "current-sha-cancel",
+					Namespace:   "foo",
+					Labels:      fooRepoLabels,
+					Annotations: cancelInProgress,
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name                  string
+		event                 *info.Event
+		cancelSuperseded      bool
+		cancelledPipelineRuns map[string]bool
+		supersededBy          string
+	}{
+		{
+			name: "not a pull request event",
+			event: &info.Event{
+				Repository:    "foo",
+				SHA:           "foosha",
+				TriggerTarget: "push",
+			},
+			cancelledPipelineRuns: map[string]bool{},
+		},
+		{
+			name: "new push supersede old sha",
+			event: &info.Event{
+				Repository:        "foo",
+				SHA:               "foosha",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 11,
+			},
+			cancelledPipelineRuns: map[string]bool{
+				"old-sha-cancel": true,
+			},
+			supersededBy: "foosha",
+		},
+		{
+			name: "repository cancel superseded",
+			event: &info.Event{
+				Repository:        "foo",
+				SHA:               "foosha",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 11,
+			},
+			cancelSuperseded: true,
+			cancelledPipelineRuns: map[string]bool{
+				"old-sha-cancel":        true,
+				"old-sha-no-annotation": true,
+			},
+			supersededBy: "foosha",
+		},
+		{
+			name: "pull request closed with repository cancel superseded",
+			event: &info.Event{
+				Repository:        "foo",
+				SHA:               "foosha",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 11,
+				State: info.State{
+					CancelInProgress: true,
+				},
+			},
+			cancelSuperseded: true,
+			cancelledPipelineRuns: map[string]bool{
+				"old-sha-cancel":     true,
+				"current-sha-cancel": true,
+			},
+		},
+		{
+			name: "pull request closed",
+			event: &info.Event{
+				Repository:        "foo",
+				SHA:               "foosha",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 11,
+				State: info.State{
+					CancelInProgress: true,
+				},
+			},
+			cancelledPipelineRuns: map[string]bool{
+				"old-sha-cancel":     true,
+				"current-sha-cancel": true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{PipelineRuns: pipelineRuns()})
+			cs := &params.Run{
+				Clients: clients.Clients{
+					Log:    logger,
+					Tekton: stdata.Pipeline,
+					Kube:   stdata.Kube,
+				},
+			}
+			repo := fooRepo.DeepCopy()
+			repo.Spec.CancelSuperseded = tt.cancelSuperseded
+			pac := NewPacs(tt.event, nil, cs, nil, logger)
+			assert.NilError(t, pac.cancelInProgressPipelineRuns(ctx, repo))
+
+			got, err := cs.Clients.Tekton.TektonV1beta1().PipelineRuns("foo").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			for _, pr := range got.Items {
+				if _, ok := tt.cancelledPipelineRuns[pr.Name]; ok {
+					assert.Equal(t, string(pr.Spec.Status), pipelinev1beta1.PipelineRunSpecStatusCancelledRunFinally, pr.Name)
+					assert.Equal(t, pr.GetAnnotations()[keys.SupersededBy], tt.supersededBy, pr.Name)
+					continue
+				}
+				assert.Assert(t, string(pr.Spec.Status) != pipelinev1beta1.PipelineRunSpecStatusCancelledRunFinally, pr.Name)
+			}
+		})
+	}
+}
+
+func TestCancelPreviousInstances(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	withLabels := func(extra map[string]string) map[string]string {
+		l := map[string]string{keys.URLRepository: formatting.K8LabelsCleanup("foo")}
+		for k, v := range extra {
+			l[k] = v
+		}
+		return l
+	}
+	pipelineRuns := func() []*pipelinev1beta1.PipelineRun {
+		return []*pipelinev1beta1.PipelineRun{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "same-pr-same-name",
+					Namespace: "foo",
+					Labels:    withLabels(map[string]string{keys.OriginalPRName: "pr-foo", keys.PullRequest: "11"}),
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "same-pr-other-name",
+					Namespace: "foo",
+					Labels:    withLabels(map[string]string{keys.OriginalPRName: "pr-bar", keys.PullRequest: "11"}),
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "other-pr-same-name",
+					Namespace: "foo",
+					Labels:    withLabels(map[string]string{keys.OriginalPRName: "pr-foo", keys.PullRequest: "12"}),
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "push-main-same-name",
+					Namespace: "foo",
+					Labels: withLabels(map[string]string{
+						keys.OriginalPRName: "pr-foo", keys.EventType: "push", keys.Branch: "main",
+					}),
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name                  string
+		event                 *info.Event
+		cancelledPipelineRuns map[string]bool
+	}{
+		{
+			name: "same pipelinerun on pull request",
+			event: &info.Event{
+				Repository:        "foo",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 11,
+			},
+			cancelledPipelineRuns: map[string]bool{"same-pr-same-name": true},
+		},
+		{
+			name: "same pipelinerun on push branch",
+			event: &info.Event{
+				Repository:    "foo",
+				TriggerTarget: "push",
+				EventType:     "push",
+				BaseBranch:    "main",
+			},
+			cancelledPipelineRuns: map[string]bool{"push-main-same-name": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{PipelineRuns: pipelineRuns()})
+			cs := &params.Run{
+				Clients: clients.Clients{
+					Log:    logger,
+					Tekton: stdata.Pipeline,
+					Kube:   stdata.Kube,
+				},
+			}
+			pac := NewPacs(tt.event, nil, cs, nil, logger)
+			match := matcher.Match{
+				Repo: fooRepo,
+				PipelineRun: &pipelinev1beta1.PipelineRun{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels:      map[string]string{keys.OriginalPRName: "pr-foo"},
+						Annotations: map[string]string{keys.CancelInProgress: "true"},
+					},
+				},
+			}
+			assert.NilError(t, pac.cancelPreviousInstances(ctx, match))
+
+			got, err := cs.Clients.Tekton.TektonV1beta1().PipelineRuns("foo").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			for _, pr := range got.Items {
+				if _, ok := tt.cancelledPipelineRuns[pr.Name]; ok {
+					assert.Equal(t, string(pr.Spec.Status), pipelinev1beta1.PipelineRunSpecStatusCancelledRunFinally, pr.Name)
+					continue
+				}
+				assert.Assert(t, string(pr.Spec.Status) != pipelinev1beta1.PipelineRunSpecStatusCancelledRunFinally, pr.Name)
+			}
+		})
+	}
+}
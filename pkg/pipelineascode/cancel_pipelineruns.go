@@ -10,6 +10,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"go.uber.org/zap"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,19 +24,41 @@ var cancelMergePatch = map[string]interface{}{
 	},
 }
 
+// supersededMergePatch cancel the PipelineRun and record the commit which
+// superseded it, so the providers can report it as such.
+func supersededMergePatch(sha string) map[string]interface{} {
+	return map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				keys.SupersededBy: sha,
+			},
+		},
+		"spec": map[string]interface{}{
+			"status": v1beta1.PipelineRunSpecStatusCancelledRunFinally,
+		},
+	}
+}
+
+// cancelPipelineRuns cancel the PipelineRuns of the pull request commit, or
+// of the pushed commit when a PipelineRun of a push is targeted from its check
+// run.
 func (p *PacRun) cancelPipelineRuns(ctx context.Context, repo *v1alpha1.Repository) error {
-	if p.event.TriggerTarget != "pull_request" {
+	pushTarget := p.event.TriggerTarget == "push" && p.event.TargetCancelPipelineRun != ""
+	if p.event.TriggerTarget != "pull_request" && !pushTarget {
 		msg := fmt.Sprintf("not a pullRequest event, event: %v", p.event.TriggerTarget)
 		p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryEvent", msg)
 		return nil
 	}
 
+	selector := map[string]string{
+		keys.URLRepository: formatting.K8LabelsCleanup(p.event.Repository),
+		keys.SHA:           formatting.K8LabelsCleanup(p.event.SHA),
+	}
+	if !pushTarget {
+		selector[keys.PullRequest] = strconv.Itoa(p.event.PullRequestNumber)
+	}
 	prs, err := p.run.Clients.Tekton.TektonV1beta1().PipelineRuns(repo.Namespace).List(ctx, v1.ListOptions{
-		LabelSelector: getLabelSelector(map[string]string{
-			keys.URLRepository: formatting.K8LabelsCleanup(p.event.Repository),
-			keys.SHA:           formatting.K8LabelsCleanup(p.event.SHA),
-			keys.PullRequest:   strconv.Itoa(p.event.PullRequestNumber),
-		}),
+		LabelSelector: getLabelSelector(selector),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to list pipelineRuns : %w", err)
@@ -48,13 +71,101 @@ func (p *PacRun) cancelPipelineRuns(ctx context.Context, repo *v1alpha1.Reposito
 		return nil
 	}
 
-	var wg sync.WaitGroup
+	toCancel := []v1beta1.PipelineRun{}
 	for _, pr := range prs.Items {
 		if p.event.TargetCancelPipelineRun != "" {
-			if prName, ok := pr.GetLabels()[keys.OriginalPRName]; !ok || prName != p.event.TargetCancelPipelineRun {
+			if pr.GetLabels()[keys.OriginalPRName] != p.event.TargetCancelPipelineRun &&
+				pr.GetLabels()[keys.MatrixPipelineRun] != p.event.TargetCancelPipelineRun {
 				continue
 			}
 		}
+		toCancel = append(toCancel, pr)
+	}
+	p.cancelAll(ctx, repo, toCancel, cancelMergePatch)
+
+	return nil
+}
+
+// cancelInProgressPipelineRuns cancel the running PipelineRuns of the pull
+// request which have the cancel-in-progress annotation set to true. When the
+// pull request has been closed all of them get cancelled, otherwise only the
+// ones from a previous SHA superseded by the current event. When the
+// Repository has cancel_superseded set, all the PipelineRuns of a previous SHA
+// get cancelled on a new commit regardless of the annotation.
+func (p *PacRun) cancelInProgressPipelineRuns(ctx context.Context, repo *v1alpha1.Repository) error {
+	if p.event.TriggerTarget != "pull_request" || p.event.PullRequestNumber == 0 {
+		return nil
+	}
+
+	prs, err := p.run.Clients.Tekton.TektonV1beta1().PipelineRuns(repo.Namespace).List(ctx, v1.ListOptions{
+		LabelSelector: getLabelSelector(map[string]string{
+			keys.URLRepository: formatting.K8LabelsCleanup(p.event.Repository),
+			keys.PullRequest:   strconv.Itoa(p.event.PullRequestNumber),
+		}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pipelineRuns : %w", err)
+	}
+
+	cancelSuperseded := !p.event.CancelInProgress && repo.Spec.CancelSuperseded
+	toCancel := []v1beta1.PipelineRun{}
+	for _, pr := range prs.Items {
+		if pr.IsDone() || (!cancelSuperseded && pr.GetAnnotations()[keys.CancelInProgress] != "true") {
+			continue
+		}
+		if !p.event.CancelInProgress && pr.GetLabels()[keys.SHA] == formatting.K8LabelsCleanup(p.event.SHA) {
+			continue
+		}
+		toCancel = append(toCancel, pr)
+	}
+	if len(toCancel) == 0 {
+		return nil
+	}
+
+	if p.event.CancelInProgress {
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryCancelInProgress",
+			fmt.Sprintf("cancelling in progress pipelineruns of pull request %d since the pull request has been closed", p.event.PullRequestNumber))
+		p.cancelAll(ctx, repo, toCancel, cancelMergePatch)
+		return nil
+	}
+	p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryCancelInProgress",
+		fmt.Sprintf("cancelling in progress pipelineruns of pull request %d since a new commit %s has been pushed", p.event.PullRequestNumber, p.event.SHA))
+	p.cancelAll(ctx, repo, toCancel, supersededMergePatch(p.event.SHA))
+	return nil
+}
+
+// cancelPreviousInstances cancel the still running PipelineRuns created from
+// the same PipelineRun as the match on the same pull request, or on the same
+// branch for a push, before we start the new one.
+func (p *PacRun) cancelPreviousInstances(ctx context.Context, match matcher.Match) error {
+	selector := map[string]string{
+		keys.URLRepository:  formatting.K8LabelsCleanup(p.event.Repository),
+		keys.OriginalPRName: match.PipelineRun.GetLabels()[keys.OriginalPRName],
+	}
+	switch {
+	case p.event.PullRequestNumber != 0:
+		selector[keys.PullRequest] = strconv.Itoa(p.event.PullRequestNumber)
+	case p.event.TriggerTarget == "push":
+		selector[keys.EventType] = formatting.K8LabelsCleanup(p.event.EventType)
+		selector[keys.Branch] = formatting.K8LabelsCleanup(p.event.BaseBranch)
+	default:
+		return nil
+	}
+
+	prs, err := p.run.Clients.Tekton.TektonV1beta1().PipelineRuns(match.Repo.GetNamespace()).List(ctx, v1.ListOptions{
+		LabelSelector: getLabelSelector(selector),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pipelineRuns : %w", err)
+	}
+	p.cancelAll(ctx, match.Repo, prs.Items, cancelMergePatch)
+	return nil
+}
+
+// cancelAll patch the PipelineRuns not done yet with the cancel mergePatch.
+func (p *PacRun) cancelAll(ctx context.Context, repo *v1alpha1.Repository, prs []v1beta1.PipelineRun, mergePatch map[string]interface{}) {
+	var wg sync.WaitGroup
+	for _, pr := range prs {
 		if pr.IsDone() {
 			p.logger.Infof("pipelinerun %v/%v is done, skipping cancellation", pr.GetNamespace(), pr.GetName())
 			continue
@@ -67,15 +178,13 @@ func (p *PacRun) cancelPipelineRuns(ctx context.Context, repo *v1alpha1.Reposito
 		wg.Add(1)
 		go func(ctx context.Context, pr v1beta1.PipelineRun) {
 			defer wg.Done()
-			if _, err := action.PatchPipelineRun(ctx, p.logger, "cancel patch", p.run.Clients.Tekton, &pr, cancelMergePatch); err != nil {
+			if _, err := action.PatchPipelineRun(ctx, p.logger, "cancel patch", p.run.Clients.Tekton, &pr, mergePatch); err != nil {
 				errMsg := fmt.Sprintf("failed to cancel pipelineRun %s/%s: %s", pr.GetNamespace(), pr.GetName(), err.Error())
 				p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryPipelineRun", errMsg)
 			}
 		}(ctx, pr)
 	}
 	wg.Wait()
-
-	return nil
 }
 
 func getLabelSelector(labelsMap map[string]string) string {
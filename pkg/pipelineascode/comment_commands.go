@@ -0,0 +1,55 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/acl"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"go.uber.org/zap"
+)
+
+// isAllowed checks the sender can run the CI, or the gitops command of a
+// comment with the permission the Repository requires for it.
+func (p *PacRun) isAllowed(ctx context.Context, repo *v1alpha1.Repository) (bool, error) {
+	if p.event.CommentCommand == "" {
+		return p.isAllowedOrAutoOkToTest(ctx, repo)
+	}
+
+	switch acl.CommandPermission(repo, p.event.CommentCommand) {
+	case acl.PermissionAnyone:
+		return true, nil
+	case acl.PermissionAuthor:
+		if p.event.PullRequestAuthor != "" && p.event.PullRequestAuthor == p.event.Sender {
+			return true, nil
+		}
+	case acl.PermissionMaintainer:
+		// the members allowed to run the CI are not enough
+		return p.vcx.IsMaintainer(ctx, p.event)
+	}
+	return p.isAllowedOrAutoOkToTest(ctx, repo)
+}
+
+// isAllowedOrAutoOkToTest checks the sender is allowed to run the CI, or
+// approved by the auto_ok_to_test conditions of the Repository when the
+// provider supports them.
+func (p *PacRun) isAllowedOrAutoOkToTest(ctx context.Context, repo *v1alpha1.Repository) (bool, error) {
+	allowed, err := p.vcx.IsAllowed(ctx, p.event)
+	if err != nil || allowed || repo.Spec.AutoOkToTest == nil {
+		return allowed, err
+	}
+	autoOkToTester, ok := p.vcx.(provider.AutoOkToTester)
+	if !ok {
+		return false, nil
+	}
+	allowed, reason, err := autoOkToTester.IsAutoOkToTest(ctx, p.event, repo.Spec.AutoOkToTest)
+	if err != nil {
+		return false, err
+	}
+	if allowed {
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryAutoOkToTest",
+			fmt.Sprintf("the user %s is allowed to run the CI as %s", p.event.Sender, reason))
+	}
+	return allowed, nil
+}
@@ -0,0 +1,94 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/acl"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestIsAllowedCommentCommands(t *testing.T) {
+	repo := &v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{
+		CommentCommands: []v1alpha1.CommentCommandPermission{
+			{Commands: []string{acl.CommandRetest}, Permission: acl.PermissionAuthor},
+			{Commands: []string{acl.CommandTest}, Permission: acl.PermissionAnyone},
+			{Commands: []string{acl.CommandCancel}, Permission: acl.PermissionMaintainer},
+		},
+	}}
+	tests := []struct {
+		name        string
+		command     string
+		sender      string
+		member      bool
+		wantAllowed bool
+	}{
+		{name: "not a command needs a member", sender: "contributor"},
+		{name: "not a command from a member", sender: "member", member: true, wantAllowed: true},
+		{name: "test by anyone", command: acl.CommandTest, sender: "contributor", wantAllowed: true},
+		{name: "retest by the author", command: acl.CommandRetest, sender: "author", wantAllowed: true},
+		{name: "retest by a member", command: acl.CommandRetest, sender: "member", member: true, wantAllowed: true},
+		{name: "retest by someone else", command: acl.CommandRetest, sender: "contributor"},
+		{name: "cancel by a maintainer", command: acl.CommandCancel, sender: "maintainer", wantAllowed: true},
+		{name: "cancel by a member", command: acl.CommandCancel, sender: "member", member: true},
+		{name: "ok-to-test needs a member", command: acl.CommandOkToTest, sender: "author"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			event := &info.Event{Sender: tt.sender, PullRequestAuthor: "author", CommentCommand: tt.command}
+			vcx := &testprovider.TestProviderImp{AllowIT: tt.member, Maintainers: []string{"maintainer"}}
+			p := &PacRun{event: event, vcx: vcx}
+			allowed, err := p.isAllowed(ctx, repo)
+			assert.NilError(t, err)
+			assert.Equal(t, allowed, tt.wantAllowed)
+		})
+	}
+
+	p := &PacRun{event: &info.Event{CommentCommand: acl.CommandCancel}}
+	assert.Equal(t, p.notAllowedMessage(repo, "member", ""),
+		"User member is not allowed to run /cancel on this repo, it needs the maintainer permission.")
+	p.event.CommentCommand = acl.CommandOkToTest
+	assert.Equal(t, p.notAllowedMessage(repo, "member", "123"), "User: member AccountID: 123 is not allowed to run CI on this repo.")
+}
+
+func TestIsAllowedAutoOkToTest(t *testing.T) {
+	tests := []struct {
+		name         string
+		command      string
+		sender       string
+		autoOkToTest *v1alpha1.AutoOkToTest
+		wantAllowed  bool
+	}{
+		{name: "approved", sender: "contributor", autoOkToTest: &v1alpha1.AutoOkToTest{CodeOwners: true}, wantAllowed: true},
+		{name: "approved for a command", command: acl.CommandTest, sender: "contributor", autoOkToTest: &v1alpha1.AutoOkToTest{CodeOwners: true}, wantAllowed: true},
+		{name: "not approved", sender: "stranger", autoOkToTest: &v1alpha1.AutoOkToTest{CodeOwners: true}},
+		{name: "not configured", sender: "contributor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec:       v1alpha1.RepositorySpec{AutoOkToTest: tt.autoOkToTest},
+			}
+			event := &info.Event{Sender: tt.sender, CommentCommand: tt.command}
+			vcx := &testprovider.TestProviderImp{AutoOkToTestUsers: []string{"contributor"}}
+			p := &PacRun{event: event, vcx: vcx, eventEmitter: events.NewEventEmitter(stdata.Kube, logger)}
+			allowed, err := p.isAllowed(ctx, repo)
+			assert.NilError(t, err)
+			assert.Equal(t, allowed, tt.wantAllowed)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/templates"
+	"go.uber.org/zap"
+)
+
+// processTemplates replace the {{var}} placeholders of the templates with the
+// variables of the event then with the custom params of the Repository.
+func (p *PacRun) processTemplates(ctx context.Context, repo *v1alpha1.Repository, rawTemplates string) string {
+	processed := templates.Process(p.event, repo, rawTemplates)
+	if len(repo.Spec.CustomParams) == 0 {
+		return processed
+	}
+	return templates.ReplacePlaceHoldersVariables(processed, p.customParams(ctx, repo))
+}
+
+// customParams evaluates the custom params of the Repository on the payload of
+// the webhook. The values come from the payload, they are kept on a single
+// line to not add their own fields to the PipelineRuns and the secrets are
+// redacted. A param failing to evaluate is replaced by an empty string.
+func (p *PacRun) customParams(ctx context.Context, repo *v1alpha1.Repository) map[string]string {
+	values := map[string]string{}
+	for _, param := range repo.Spec.CustomParams {
+		value, err := matcher.CELString(ctx, param.Value, p.event, p.vcx)
+		if err != nil {
+			p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryCustomParam",
+				fmt.Sprintf("cannot evaluate the custom param %s: %s", param.Name, templates.Redact(p.event, err.Error())))
+		}
+		values[param.Name] = templates.Redact(p.event, strings.Join(strings.Fields(value), " "))
+	}
+	return values
+}
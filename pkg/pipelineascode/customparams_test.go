@@ -0,0 +1,38 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestProcessTemplatesCustomParams(t *testing.T) {
+	observer, logs := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	cs := &params.Run{Clients: clients.Clients{Kube: stdata.Kube}}
+
+	event := info.NewEvent()
+	event.SHA = "abcd"
+	event.Request.Payload = []byte(`{"pull_request": {"milestone": {"title": "v1.0\nfinal"}, "labels": [{"name": "bug"}], "milestone_id": 7}}`)
+	repo := fooRepo.DeepCopy()
+	repo.Spec.CustomParams = []v1alpha1.CustomParam{
+		{Name: "milestone", Value: "body.pull_request.milestone.title"},
+		{Name: "labels", Value: "body.pull_request.labels.map(l, l.name)"},
+		{Name: "milestone_id", Value: "body.pull_request.milestone_id"},
+		{Name: "missing", Value: "body.pull_request.assignee.login"},
+	}
+	pac := NewPacs(event, nil, cs, nil, logger)
+	got := pac.processTemplates(ctx, repo, "{{ revision }} {{ milestone }} {{ labels }} {{ milestone_id }} [{{ missing }}] {{ unknown }}")
+	assert.Equal(t, got, `abcd v1.0 final ["bug"] 7 [] {{ unknown }}`)
+	assert.Equal(t, logs.FilterMessageSnippet("cannot evaluate the custom param missing").Len(), 1)
+}
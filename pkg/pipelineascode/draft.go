@@ -0,0 +1,77 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"go.uber.org/zap"
+)
+
+// draftMode return if the PipelineRun is run, skipped or queued on a draft
+// pull request, the annotation of the PipelineRun overrides the setting.
+func (p *PacRun) draftMode(repo *v1alpha1.Repository, match matcher.Match) string {
+	mode := settings.DraftPullRequestsRun
+	if p.run.Info.Pac != nil && p.run.Info.Pac.Settings != nil && p.run.Info.Pac.DraftPullRequests != "" {
+		mode = p.run.Info.Pac.DraftPullRequests
+	}
+	if annotation, ok := match.PipelineRun.GetAnnotations()[keys.DraftPRs]; ok {
+		if !settings.IsValidDraftPullRequests(annotation) {
+			p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryDraftPullRequest",
+				fmt.Sprintf("invalid %s annotation %q on pipelinerun %s, using %s", keys.DraftPRs, annotation,
+					match.PipelineRun.GetGenerateName(), mode))
+			return mode
+		}
+		mode = annotation
+	}
+	return mode
+}
+
+// gateDraftPipelineRuns report the matched PipelineRuns of a draft pull
+// request as skipped or queued and return the ones to run. They are run by the
+// event of the pull request being marked ready for review.
+func (p *PacRun) gateDraftPipelineRuns(ctx context.Context, repo *v1alpha1.Repository, matchedPRs []matcher.Match) []matcher.Match {
+	if !p.event.PullRequestDraft || p.event.CancelInProgress {
+		return matchedPRs
+	}
+
+	toRun := []matcher.Match{}
+	gated := 0
+	for _, match := range matchedPRs {
+		name := match.PipelineRun.GetLabels()[keys.OriginalPRName]
+		status := provider.StatusOpts{
+			DetailsURL:              p.run.Clients.ConsoleUI.URL(),
+			PipelineRunName:         name,
+			OriginalPipelineRunName: name,
+		}
+		switch p.draftMode(repo, match) {
+		case settings.DraftPullRequestsSkip:
+			status.Status = "completed"
+			status.Conclusion = "skipped"
+			status.Text = fmt.Sprintf(params.DraftPipelineRunText, name, "has been skipped")
+		case settings.DraftPullRequestsQueue:
+			// pending so the required checks block the pull request
+			status.Status = "queued"
+			status.Conclusion = "pending"
+			status.Text = fmt.Sprintf(params.DraftPipelineRunText, name, "has been queued")
+		default:
+			toRun = append(toRun, match)
+			continue
+		}
+		gated++
+		if err := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, status); err != nil {
+			p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryCreateStatus",
+				fmt.Sprintf("cannot create a draft status for pipelinerun %s: %s", name, err.Error()))
+		}
+	}
+	if gated > 0 {
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryDraftPullRequest",
+			fmt.Sprintf("pull request %d is a draft, %d pipelinerun(s) will run when it is ready for review", p.event.PullRequestNumber, gated))
+	}
+	return toRun
+}
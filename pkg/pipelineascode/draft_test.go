@@ -0,0 +1,106 @@
+package pipelineascode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestGateDraftPipelineRuns(t *testing.T) {
+	tests := []struct {
+		name         string
+		draft        bool
+		setting      string
+		annotations  map[string]string
+		wantRun      int
+		wantStatuses []string
+	}{
+		{
+			name:    "not a draft",
+			setting: settings.DraftPullRequestsSkip,
+			wantRun: 2,
+		},
+		{
+			name:    "draft with the default setting",
+			draft:   true,
+			wantRun: 2,
+		},
+		{
+			name:         "draft skipped by the setting",
+			draft:        true,
+			setting:      settings.DraftPullRequestsSkip,
+			wantStatuses: []string{"completed", "completed"},
+		},
+		{
+			name:         "draft queued by the annotation",
+			draft:        true,
+			annotations:  map[string]string{keys.DraftPRs: settings.DraftPullRequestsQueue},
+			wantRun:      1,
+			wantStatuses: []string{"queued"},
+		},
+		{
+			name:         "draft run by the annotation",
+			draft:        true,
+			setting:      settings.DraftPullRequestsSkip,
+			annotations:  map[string]string{keys.DraftPRs: settings.DraftPullRequestsRun},
+			wantRun:      1,
+			wantStatuses: []string{"completed"},
+		},
+		{
+			name:         "invalid annotation",
+			draft:        true,
+			setting:      settings.DraftPullRequestsSkip,
+			annotations:  map[string]string{keys.DraftPRs: "wait"},
+			wantStatuses: []string{"completed", "completed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+			cs := &params.Run{
+				Clients: clients.Clients{Log: logger, Kube: stdata.Kube, Tekton: stdata.Pipeline, ConsoleUI: consoleui.FallBackConsole{}},
+				Info:    info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{DraftPullRequests: tt.setting}}},
+			}
+			vcx := &testprovider.TestProviderImp{}
+			pac := NewPacs(&info.Event{PullRequestDraft: tt.draft, PullRequestNumber: 42}, vcx, cs, nil, logger)
+
+			// the annotations are only set on the first PipelineRun
+			matched := []matcher.Match{}
+			for i, name := range []string{"build", "test"} {
+				pr := &v1beta1.PipelineRun{
+					ObjectMeta: metav1.ObjectMeta{GenerateName: name + "-", Labels: map[string]string{keys.OriginalPRName: name}},
+				}
+				if i == 0 {
+					pr.Annotations = tt.annotations
+				}
+				matched = append(matched, matcher.Match{PipelineRun: pr})
+			}
+
+			got := pac.gateDraftPipelineRuns(ctx, fooRepo, matched)
+			assert.Equal(t, len(got), tt.wantRun)
+			assert.Equal(t, len(vcx.CreatedStatuses), len(tt.wantStatuses))
+			for i, status := range vcx.CreatedStatuses {
+				assert.Equal(t, status.Status, tt.wantStatuses[i])
+				assert.Assert(t, strings.Contains(status.Text, "until it is marked ready for review"), status.Text)
+			}
+		})
+	}
+}
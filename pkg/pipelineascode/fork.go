@@ -0,0 +1,97 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/policy"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"go.uber.org/zap"
+)
+
+// forkStatusName is the name of the status explaining what has been done
+// with the tekton directory changed by a pull request from a fork
+const forkStatusName = "tekton-fork-changes"
+
+// forkTektonChangesEvent returns the event to fetch the PipelineRuns from when
+// a pull request from a fork changes the tekton directory, following the
+// fork_tekton_changes of the Repository. The PipelineRuns of the default
+// branch are fetched instead, or nil is returned when they wait for the
+// approval of a maintainer. A status explains it on the pull request.
+func (p *PacRun) forkTektonChangesEvent(ctx context.Context, repo *v1alpha1.Repository, event *info.Event) (*info.Event, error) {
+	mode := repo.Spec.ForkTektonChanges
+	if mode == "" || mode == policy.ForkTektonChangesRun || !p.event.PullRequestFork || p.event.TriggerTarget != "pull_request" {
+		return event, nil
+	}
+	dir := repoTektonDir(repo)
+	changed, err := p.changesDir(ctx, dir)
+	if err != nil {
+		// we would rather not run definitions we haven't checked
+		p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryForkTektonChanges",
+			fmt.Sprintf("cannot get changed files, considering the %s directory as changed by the fork: %s", dir, err.Error()))
+		changed = true
+	}
+	if !changed {
+		return event, nil
+	}
+
+	switch mode {
+	case policy.ForkTektonChangesApproval:
+		maintainer, err := p.vcx.IsMaintainer(ctx, p.event)
+		if err != nil {
+			return nil, fmt.Errorf("cannot check if %s is a maintainer of the repository: %w", p.event.Sender, err)
+		}
+		if maintainer {
+			return event, nil
+		}
+		msg := fmt.Sprintf("The pull request comes from a fork and changes the %s directory, a maintainer of the repository needs to comment /ok-to-test to run its PipelineRuns.", dir)
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryForkTektonChanges", msg)
+		return nil, p.createForkStatus(ctx, "skipped", msg)
+	case policy.ForkTektonChangesDefaultBranch:
+		if p.event.DefaultBranch == "" {
+			return nil, fmt.Errorf("cannot fetch the pipelineruns from the default branch, the default branch of %s is unknown", p.event.URL)
+		}
+		msg := fmt.Sprintf("The pull request comes from a fork and changes the %s directory, the PipelineRuns of the default branch %s are run instead of its own.", dir, p.event.DefaultBranch)
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryForkTektonChanges", msg)
+		if err := p.createForkStatus(ctx, "neutral", msg); err != nil {
+			return nil, err
+		}
+		upstream := &info.Event{}
+		event.DeepCopyInto(upstream)
+		upstream.SHA = p.event.DefaultBranch
+		upstream.HeadBranch = p.event.DefaultBranch
+		return upstream, nil
+	}
+	return event, nil
+}
+
+// changesDir returns whether the pull request changes a file of a directory
+func (p *PacRun) changesDir(ctx context.Context, dir string) (bool, error) {
+	changedFiles, err := p.vcx.GetFiles(ctx, p.event)
+	if err != nil {
+		return false, err
+	}
+	for _, file := range changedFiles {
+		if strings.HasPrefix(file, dir+"/") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (p *PacRun) createForkStatus(ctx context.Context, conclusion, text string) error {
+	status := provider.StatusOpts{
+		Status:                  "completed",
+		Conclusion:              conclusion,
+		PipelineRunName:         forkStatusName,
+		OriginalPipelineRunName: forkStatusName,
+		Text:                    text,
+	}
+	if err := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, status); err != nil {
+		return fmt.Errorf("cannot create the status about the tekton directory changed by the fork: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package pipelineascode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/policy"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestForkTektonChangesEvent(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           string
+		fork           bool
+		sender         string
+		changedFiles   []string
+		wantNil        bool
+		wantSHA        string
+		wantConclusion string
+	}{
+		{
+			name:         "run the changes of the fork",
+			mode:         policy.ForkTektonChangesRun,
+			fork:         true,
+			changedFiles: []string{".tekton/pr.yaml"},
+			wantSHA:      "headsha",
+		},
+		{
+			name:         "not a fork",
+			mode:         policy.ForkTektonChangesApproval,
+			changedFiles: []string{".tekton/pr.yaml"},
+			wantSHA:      "headsha",
+		},
+		{
+			name:         "fork not changing the tekton directory",
+			mode:         policy.ForkTektonChangesApproval,
+			fork:         true,
+			changedFiles: []string{"README.md"},
+			wantSHA:      "headsha",
+		},
+		{
+			name:           "wait for the approval of a maintainer",
+			mode:           policy.ForkTektonChangesApproval,
+			fork:           true,
+			sender:         "contributor",
+			changedFiles:   []string{".tekton/pr.yaml"},
+			wantNil:        true,
+			wantConclusion: "skipped",
+		},
+		{
+			name:         "approved by a maintainer",
+			mode:         policy.ForkTektonChangesApproval,
+			fork:         true,
+			sender:       "maintainer",
+			changedFiles: []string{".tekton/pr.yaml"},
+			wantSHA:      "headsha",
+		},
+		{
+			name:           "run the definitions of the default branch",
+			mode:           policy.ForkTektonChangesDefaultBranch,
+			fork:           true,
+			changedFiles:   []string{".tekton/pr.yaml"},
+			wantSHA:        "main",
+			wantConclusion: "neutral",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+			cs := &params.Run{Clients: clients.Clients{Log: logger, Kube: stdata.Kube, Tekton: stdata.Pipeline}}
+			vcx := &testprovider.TestProviderImp{ChangedFiles: tt.changedFiles, Maintainers: []string{"maintainer"}}
+			event := &info.Event{
+				TriggerTarget:   "pull_request",
+				PullRequestFork: tt.fork,
+				Sender:          tt.sender,
+				SHA:             "headsha",
+				HeadBranch:      "feature",
+				DefaultBranch:   "main",
+			}
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec:       v1alpha1.RepositorySpec{ForkTektonChanges: tt.mode},
+			}
+			pac := NewPacs(event, vcx, cs, nil, logger)
+
+			got, err := pac.forkTektonChangesEvent(ctx, repo, event)
+			assert.NilError(t, err)
+			if tt.wantNil {
+				assert.Assert(t, got == nil)
+			} else {
+				assert.Equal(t, got.SHA, tt.wantSHA)
+				assert.Equal(t, event.SHA, "headsha")
+			}
+			if tt.wantConclusion == "" {
+				assert.Equal(t, len(vcx.CreatedStatuses), 0)
+				return
+			}
+			assert.Equal(t, len(vcx.CreatedStatuses), 1)
+			assert.Equal(t, vcx.CreatedStatuses[0].Conclusion, tt.wantConclusion)
+			assert.Equal(t, vcx.CreatedStatuses[0].PipelineRunName, forkStatusName)
+			assert.Assert(t, strings.Contains(vcx.CreatedStatuses[0].Text, "comes from a fork"))
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/freeze"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"go.uber.org/zap"
+)
+
+// frozenStatus return the active freeze window of the Repository or of the
+// global configuration, nil when there is none. The global windows are
+// checked on their own so an invalid window of the Repository can't lift
+// them, the error is returned with the global status.
+func (p *PacRun) frozenStatus(repo *v1alpha1.Repository) (*freeze.Status, error) {
+	now := time.Now()
+	var global *freeze.Status
+	if p.run.Info.Pac != nil && p.run.Info.Pac.Settings != nil {
+		windows, err := freeze.ParseWindows(p.run.Info.Pac.FreezeWindows)
+		if err != nil {
+			return nil, err
+		}
+		if global, err = freeze.Frozen(windows, now); err != nil {
+			return nil, err
+		}
+	}
+	status, err := freeze.Frozen(repo.Spec.FreezeWindows, now)
+	if err != nil {
+		return global, fmt.Errorf("invalid freeze windows of the repository: %w", err)
+	}
+	if status == nil || (global != nil && global.Until.After(status.Until)) {
+		return global, nil
+	}
+	return status, nil
+}
+
+// skipFrozenPipelineRuns report the matched PipelineRuns as skipped until the
+// end of the freeze window.
+func (p *PacRun) skipFrozenPipelineRuns(ctx context.Context, repo *v1alpha1.Repository, matchedPRs []matcher.Match, frozen *freeze.Status) {
+	until := frozen.Until.Format(time.RFC3339)
+	for _, match := range matchedPRs {
+		name := match.PipelineRun.GetLabels()[keys.OriginalPRName]
+		status := provider.StatusOpts{
+			Status:                  "completed",
+			Conclusion:              "skipped",
+			Text:                    fmt.Sprintf(params.FrozenPipelineRunText, until, name, "has been skipped"),
+			DetailsURL:              p.run.Clients.ConsoleUI.URL(),
+			PipelineRunName:         name,
+			OriginalPipelineRunName: name,
+		}
+		if err := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, status); err != nil {
+			p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryCreateStatus",
+				fmt.Sprintf("cannot create a frozen status for pipelinerun %s: %s", name, err.Error()))
+		}
+	}
+	p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryFrozen",
+		fmt.Sprintf("repository is frozen until %s, skipped %d pipelinerun(s)", until, len(matchedPRs)))
+}
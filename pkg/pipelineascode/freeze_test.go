@@ -0,0 +1,120 @@
+package pipelineascode
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/freeze"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestFrozenStatus(t *testing.T) {
+	always := v1alpha1.FreezeWindow{Schedule: "* * * * *", Duration: "1h"}
+	tests := []struct {
+		name       string
+		repo       []v1alpha1.FreezeWindow
+		global     string
+		wantAction string
+		wantErr    string
+	}{
+		{
+			name: "not frozen",
+		},
+		{
+			name:       "frozen by the repository",
+			repo:       []v1alpha1.FreezeWindow{always},
+			wantAction: freeze.ActionSkip,
+		},
+		{
+			name:       "frozen by the global settings",
+			global:     "- schedule: \"* * * * *\"\n  duration: 2h\n  action: queue\n",
+			wantAction: freeze.ActionQueue,
+		},
+		{
+			name:       "the global window ending last wins",
+			repo:       []v1alpha1.FreezeWindow{always},
+			global:     "- schedule: \"* * * * *\"\n  duration: 2h\n  action: queue\n",
+			wantAction: freeze.ActionQueue,
+		},
+		{
+			name:       "invalid repository window keeps the global ones",
+			repo:       []v1alpha1.FreezeWindow{{Schedule: "nope", Duration: "1h"}},
+			global:     "- schedule: \"* * * * *\"\n  duration: 2h\n  action: queue\n",
+			wantAction: freeze.ActionQueue,
+			wantErr:    "invalid freeze windows of the repository",
+		},
+		{
+			name:    "invalid global settings",
+			global:  "- schedule: nope\n  duration: 2h\n",
+			wantErr: "invalid freeze window schedule",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := &params.Run{
+				Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{FreezeWindows: tt.global}}},
+			}
+			repo := fooRepo.DeepCopy()
+			repo.Spec.FreezeWindows = tt.repo
+			pac := NewPacs(&info.Event{}, nil, cs, nil, nil)
+			got, err := pac.frozenStatus(repo)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			if tt.wantAction == "" {
+				assert.Assert(t, got == nil)
+				return
+			}
+			assert.Equal(t, got.Action, tt.wantAction)
+			assert.Assert(t, got.Until.After(time.Now()))
+		})
+	}
+}
+
+func TestSkipFrozenPipelineRuns(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	cs := &params.Run{
+		Clients: clients.Clients{Log: logger, Kube: stdata.Kube, Tekton: stdata.Pipeline, ConsoleUI: consoleui.FallBackConsole{}},
+		Info:    info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{}}},
+	}
+	vcx := &testprovider.TestProviderImp{}
+	pac := NewPacs(&info.Event{}, vcx, cs, nil, logger)
+
+	matched := []matcher.Match{}
+	for _, name := range []string{"deploy", "test"} {
+		matched = append(matched, matcher.Match{PipelineRun: &v1beta1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{GenerateName: name + "-", Labels: map[string]string{keys.OriginalPRName: name}},
+		}})
+	}
+	until := time.Date(2023, 1, 9, 8, 0, 0, 0, time.UTC)
+	pac.skipFrozenPipelineRuns(ctx, fooRepo, matched, &freeze.Status{Until: until, Action: freeze.ActionSkip})
+
+	assert.Equal(t, len(vcx.CreatedStatuses), 2)
+	for i, name := range []string{"deploy", "test"} {
+		status := vcx.CreatedStatuses[i]
+		assert.Equal(t, status.Conclusion, "skipped")
+		assert.Equal(t, status.OriginalPipelineRunName, name)
+		assert.Assert(t, strings.Contains(status.Text, "frozen until <b>2023-01-09T08:00:00Z</b>"), status.Text)
+	}
+}
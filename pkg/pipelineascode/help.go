@@ -0,0 +1,74 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/acl"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/resolve"
+	"go.uber.org/zap"
+)
+
+// helpStatusName is the name of the status replying to a /help comment
+const helpStatusName = "pac-help"
+
+// helpCommands are the gitops commands listed by /help with their usage
+var helpCommands = []struct {
+	command string
+	usage   string
+}{
+	{acl.CommandTest, "`/test [pipelinerun-name] [key=value ...]`: run the PipelineRuns matching the pull request, or only the named one, with the params passed as `key=value`"},
+	{acl.CommandRetest, "`/retest [pipelinerun-name] [key=value ...]`: the same as `/test`"},
+	{acl.CommandCancel, "`/cancel [pipelinerun-name]`: cancel the running PipelineRuns of the pull request, or only the named one"},
+	{acl.CommandOkToTest, "`/ok-to-test`: allow the PipelineRuns of a pull request from a user not allowed to run the CI"},
+	{acl.CommandHelp, "`/help` or `/pac help`: show this help"},
+}
+
+// help replies to a /help comment with a status listing the PipelineRuns of
+// the repository, with the events they are matched on, and the gitops
+// commands the users can comment with the permission they need.
+func (p *PacRun) help(ctx context.Context, repo *v1alpha1.Repository) error {
+	event, err := p.provenanceEvent(repo)
+	if err != nil {
+		return err
+	}
+	dir := repoTektonDir(repo)
+	rawTemplates, err := p.getTektonDir(ctx, repo, event, dir)
+	if err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryHelp",
+			fmt.Sprintf("cannot get the %s directory to list its pipelineruns: %s", dir, err.Error()))
+	}
+	explanations := resolve.ExplainPipelineRuns(ctx, p.processTemplates(ctx, repo, rawTemplates))
+
+	status := provider.StatusOpts{
+		Status:                  "completed",
+		Conclusion:              "neutral",
+		PipelineRunName:         helpStatusName,
+		OriginalPipelineRunName: helpStatusName,
+		Text:                    helpText(repo, dir, event.HeadBranch, explanations),
+	}
+	if err := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, status); err != nil {
+		return fmt.Errorf("cannot create the status replying to /help: %w", err)
+	}
+	return nil
+}
+
+func helpText(repo *v1alpha1.Repository, dir, branch string, explanations []resolve.Explanation) string {
+	var text strings.Builder
+	if len(explanations) == 0 {
+		text.WriteString(fmt.Sprintf("There is no PipelineRun in the %s directory of %s.\n", dir, branch))
+	} else {
+		text.WriteString(fmt.Sprintf("The PipelineRuns of the %s directory of %s:\n\n", dir, branch))
+		for _, explanation := range explanations {
+			text.WriteString(fmt.Sprintf("* **%s**: %s\n", explanation.PipelineRun, explanation.Match))
+		}
+	}
+	text.WriteString("\nThe gitops commands which can be commented on the pull request:\n\n")
+	for _, hc := range helpCommands {
+		text.WriteString(fmt.Sprintf("* %s, with the %s permission\n", hc.usage, acl.CommandPermission(repo, hc.command)))
+	}
+	return text.String()
+}
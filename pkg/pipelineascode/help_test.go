@@ -0,0 +1,92 @@
+package pipelineascode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/acl"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestHelp(t *testing.T) {
+	tektonDir := `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pull-request
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[pull_request]"
+    pipelinesascode.tekton.dev/on-target-branch: "[main]"
+spec:
+  pipelineSpec:
+    tasks: []
+`
+	tests := []struct {
+		name     string
+		template string
+		wantText []string
+	}{
+		{
+			name:     "pipelineruns and commands",
+			template: tektonDir,
+			wantText: []string{
+				"The PipelineRuns of the .tekton directory of feature",
+				"* **pull-request**: matched on the [pull_request] events targeting [main]",
+				"`/ok-to-test`: allow the PipelineRuns",
+				"`/cancel [pipelinerun-name]`: cancel the running PipelineRuns of the pull request, or only the named one, with the maintainer permission",
+				"show this help, with the anyone permission",
+			},
+		},
+		{
+			name: "no pipelinerun",
+			wantText: []string{
+				"There is no PipelineRun in the .tekton directory of feature",
+				"`/test [pipelinerun-name] [key=value ...]`",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+			cs := &params.Run{Clients: clients.Clients{Log: logger, Kube: stdata.Kube, Tekton: stdata.Pipeline}}
+			vcx := &testprovider.TestProviderImp{TektonDirTemplate: tt.template}
+			event := &info.Event{
+				TriggerTarget:  "pull_request",
+				CommentCommand: acl.CommandHelp,
+				HeadBranch:     "feature",
+			}
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec: v1alpha1.RepositorySpec{
+					CommentCommands: []v1alpha1.CommentCommandPermission{
+						{Commands: []string{acl.CommandCancel}, Permission: acl.PermissionMaintainer},
+						{Commands: []string{acl.CommandHelp}, Permission: acl.PermissionAnyone},
+					},
+				},
+			}
+			pac := NewPacs(event, vcx, cs, nil, logger)
+
+			assert.NilError(t, pac.help(ctx, repo))
+			assert.Equal(t, len(vcx.CreatedStatuses), 1)
+			status := vcx.CreatedStatuses[0]
+			assert.Equal(t, status.PipelineRunName, helpStatusName)
+			assert.Equal(t, status.Conclusion, "neutral")
+			for _, want := range tt.wantText {
+				assert.Assert(t, strings.Contains(status.Text, want), status.Text)
+			}
+		})
+	}
+}
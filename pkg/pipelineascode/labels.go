@@ -0,0 +1,66 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// onlyLabelsRemoved returns whether the event only removed labels from the
+// pull request, nothing is run on it.
+func (p *PacRun) onlyLabelsRemoved() bool {
+	return len(p.event.LabelsRemoved) > 0 && len(p.event.LabelsAdded) == 0
+}
+
+// cancelUnlabeledPipelineRuns cancel the running PipelineRuns of the pull
+// request gated with the on-label annotation on the labels removed by the
+// event, unless the pull request still has another one of their labels.
+func (p *PacRun) cancelUnlabeledPipelineRuns(ctx context.Context, repo *v1alpha1.Repository) error {
+	if len(p.event.LabelsRemoved) == 0 || p.event.PullRequestNumber == 0 {
+		return nil
+	}
+
+	prs, err := p.run.Clients.Tekton.TektonV1beta1().PipelineRuns(repo.Namespace).List(ctx, v1.ListOptions{
+		LabelSelector: getLabelSelector(map[string]string{
+			keys.URLRepository: formatting.K8LabelsCleanup(p.event.Repository),
+			keys.PullRequest:   strconv.Itoa(p.event.PullRequestNumber),
+		}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pipelineRuns : %w", err)
+	}
+
+	toCancel := []v1beta1.PipelineRun{}
+	for i := range prs.Items {
+		pr := &prs.Items[i]
+		if pr.IsDone() {
+			continue
+		}
+		unlabeled, err := matcher.HasLabel(pr, p.event.LabelsRemoved)
+		if err != nil || !unlabeled {
+			continue
+		}
+		if labeled, _ := matcher.HasLabel(pr, p.event.PullRequestLabels); labeled {
+			continue
+		}
+		toCancel = append(toCancel, *pr)
+	}
+	if len(toCancel) == 0 {
+		return nil
+	}
+
+	p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryCancelUnlabeled",
+		fmt.Sprintf("cancelling %d pipelinerun(s) of pull request %d since the label(s) %s have been removed",
+			len(toCancel), p.event.PullRequestNumber, strings.Join(p.event.LabelsRemoved, ", ")))
+	p.cancelAll(ctx, repo, toCancel, cancelMergePatch)
+	return nil
+}
@@ -0,0 +1,123 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	pipelinev1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCancelUnlabeledPipelineRuns(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	pipelineRuns := func() []*pipelinev1beta1.PipelineRun {
+		return []*pipelinev1beta1.PipelineRun{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "e2e",
+					Namespace:   "foo",
+					Labels:      fooRepoLabels,
+					Annotations: map[string]string{keys.OnLabel: "[e2e]"},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "e2e-or-perf",
+					Namespace:   "foo",
+					Labels:      fooRepoLabels,
+					Annotations: map[string]string{keys.OnLabel: "[e2e, perf]"},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "no-label",
+					Namespace: "foo",
+					Labels:    fooRepoLabels,
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name                  string
+		event                 *info.Event
+		cancelledPipelineRuns map[string]bool
+	}{
+		{
+			name: "no label removed",
+			event: &info.Event{
+				Repository:        "foo",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 11,
+			},
+			cancelledPipelineRuns: map[string]bool{},
+		},
+		{
+			name: "label removed",
+			event: &info.Event{
+				Repository:        "foo",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 11,
+				State:             info.State{LabelsRemoved: []string{"e2e"}},
+			},
+			cancelledPipelineRuns: map[string]bool{"e2e": true, "e2e-or-perf": true},
+		},
+		{
+			name: "label removed with another label still there",
+			event: &info.Event{
+				Repository:        "foo",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 11,
+				PullRequestLabels: []string{"perf"},
+				State:             info.State{LabelsRemoved: []string{"e2e"}},
+			},
+			cancelledPipelineRuns: map[string]bool{"e2e": true},
+		},
+		{
+			name: "label not gating any pipelinerun",
+			event: &info.Event{
+				Repository:        "foo",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 11,
+				State:             info.State{LabelsRemoved: []string{"bug"}},
+			},
+			cancelledPipelineRuns: map[string]bool{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{PipelineRuns: pipelineRuns()})
+			cs := &params.Run{
+				Clients: clients.Clients{
+					Log:    logger,
+					Tekton: stdata.Pipeline,
+					Kube:   stdata.Kube,
+				},
+			}
+			pac := NewPacs(tt.event, nil, cs, nil, logger)
+			assert.NilError(t, pac.cancelUnlabeledPipelineRuns(ctx, fooRepo.DeepCopy()))
+			assert.Equal(t, pac.onlyLabelsRemoved(), len(tt.event.LabelsRemoved) > 0)
+
+			got, err := cs.Clients.Tekton.TektonV1beta1().PipelineRuns("foo").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			for _, pr := range got.Items {
+				if _, ok := tt.cancelledPipelineRuns[pr.Name]; ok {
+					assert.Equal(t, string(pr.Spec.Status), pipelinev1beta1.PipelineRunSpecStatusCancelledRunFinally, pr.Name)
+					continue
+				}
+				assert.Assert(t, string(pr.Spec.Status) != pipelinev1beta1.PipelineRunSpecStatusCancelledRunFinally, pr.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/lint"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"go.uber.org/zap"
+)
+
+// lintStatusName is the name of the status reporting the lint problems
+const lintStatusName = "tekton-lint"
+
+// lintTektonDir lint the files of the tekton directory changed by the pull
+// request and report the problems found as a failed status, with the problems
+// attached to the offending lines on the providers supporting it.
+func (p *PacRun) lintTektonDir(ctx context.Context, repo *v1alpha1.Repository) error {
+	changedFiles, err := p.vcx.GetFiles(ctx, p.event)
+	if err != nil {
+		return fmt.Errorf("cannot get changed files: %w", err)
+	}
+
+	dir := repoTektonDir(repo)
+	customVariables := []string{}
+	for _, param := range repo.Spec.CustomParams {
+		customVariables = append(customVariables, param.Name)
+	}
+	problems := []lint.Problem{}
+	for _, file := range changedFiles {
+		if !strings.HasPrefix(file, dir+"/") || (filepath.Ext(file) != ".yaml" && filepath.Ext(file) != ".yml") {
+			continue
+		}
+		content, err := p.vcx.GetFileInsideRepo(ctx, p.event, file, "")
+		if err != nil {
+			// the file may have been removed by the pull request
+			p.logger.Infof("cannot get file %s to lint it: %v", file, err)
+			continue
+		}
+		problems = append(problems, lint.Lint(file, content, customVariables...)...)
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	text := fmt.Sprintf("Found %d problem(s) in the %s directory:\n\n", len(problems), dir)
+	annotations := make([]provider.Annotation, 0, len(problems))
+	for _, problem := range problems {
+		text += fmt.Sprintf("* `%s:%d`: %s\n", problem.File, problem.Line, problem.Message)
+		annotations = append(annotations, provider.Annotation{Path: problem.File, Line: problem.Line, Message: problem.Message})
+	}
+	p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryTektonLint",
+		fmt.Sprintf("found %d problem(s) in the %s directory", len(problems), dir))
+
+	status := provider.StatusOpts{
+		Status:                  "completed",
+		Conclusion:              "failure",
+		PipelineRunName:         lintStatusName,
+		OriginalPipelineRunName: lintStatusName,
+		Text:                    text,
+		Annotations:             annotations,
+	}
+	if err := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, status); err != nil {
+		return fmt.Errorf("failed to create lint status: %w", err)
+	}
+	return nil
+}
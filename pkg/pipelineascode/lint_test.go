@@ -0,0 +1,78 @@
+package pipelineascode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestLintTektonDir(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	invalid := `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pr
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[pull_request"
+`
+	tests := []struct {
+		name            string
+		changedFiles    []string
+		files           map[string]string
+		wantAnnotations int
+	}{
+		{
+			name:         "no tekton files changed",
+			changedFiles: []string{"README.md", "docs/pr.yaml"},
+			files:        map[string]string{"docs/pr.yaml": invalid},
+		},
+		{
+			name:         "valid tekton files",
+			changedFiles: []string{".tekton/pr.yaml"},
+			files:        map[string]string{".tekton/pr.yaml": "---\nkind: PipelineRun\n"},
+		},
+		{
+			name:            "invalid tekton file",
+			changedFiles:    []string{".tekton/pr.yaml", ".tekton/removed.yaml"},
+			files:           map[string]string{".tekton/pr.yaml": invalid},
+			wantAnnotations: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+			cs := &params.Run{
+				Clients: clients.Clients{Log: logger, Kube: stdata.Kube, Tekton: stdata.Pipeline},
+				Info:    info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{TektonLint: true}}},
+			}
+			vcx := &testprovider.TestProviderImp{ChangedFiles: tt.changedFiles, FilesInsideRepo: tt.files}
+			pac := NewPacs(&info.Event{TriggerTarget: "pull_request"}, vcx, cs, nil, logger)
+			assert.NilError(t, pac.lintTektonDir(ctx, fooRepo))
+
+			if tt.wantAnnotations == 0 {
+				assert.Equal(t, len(vcx.CreatedStatuses), 0)
+				return
+			}
+			assert.Equal(t, len(vcx.CreatedStatuses), 1)
+			status := vcx.CreatedStatuses[0]
+			assert.Equal(t, status.Conclusion, "failure")
+			assert.Equal(t, len(status.Annotations), tt.wantAnnotations)
+			assert.Equal(t, status.Annotations[0].Path, ".tekton/pr.yaml")
+			assert.Equal(t, status.Annotations[0].Line, 7)
+			assert.Assert(t, strings.Contains(status.Text, "`.tekton/pr.yaml:7`"), status.Text)
+		})
+	}
+}
@@ -7,9 +7,13 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/acl"
 	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/policy"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/resolve"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
@@ -27,10 +31,45 @@ func (p *PacRun) matchRepoPR(ctx context.Context) ([]matcher.Match, *v1alpha1.Re
 		return nil, nil, nil
 	}
 
+	// inherit the defaults of the cluster RepositoryPolicies
+	if p.policies, err = policy.List(ctx, p.run.Clients.PipelineAsCode); err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryPolicy",
+			fmt.Sprintf("cannot list the repository policies, ignoring them: %s", err.Error()))
+	}
+	repo = policy.Apply(repo, p.policies)
+
+	// the push deleted the branch, there is nothing to run on it anymore
+	if p.event.BranchDeleted {
+		p.cleanupDeletedBranch(ctx, repo)
+		return nil, repo, nil
+	}
+
 	if p.event.CancelPipelineRuns {
 		return nil, repo, p.cancelPipelineRuns(ctx, repo)
 	}
 
+	if p.event.CommentCommand == acl.CommandHelp {
+		return nil, repo, p.help(ctx, repo)
+	}
+
+	// the pull request has been closed or a new commit superseded the
+	// previous ones, cancel the running PipelineRuns asking for it.
+	if err := p.cancelInProgressPipelineRuns(ctx, repo); err != nil {
+		return nil, repo, err
+	}
+	if p.event.CancelInProgress {
+		return nil, repo, nil
+	}
+
+	// the PipelineRuns gated on the labels removed from the pull request get
+	// cancelled, they are run again when one of their labels is added back.
+	if err := p.cancelUnlabeledPipelineRuns(ctx, repo); err != nil {
+		return nil, repo, err
+	}
+	if p.onlyLabelsRemoved() {
+		return nil, repo, nil
+	}
+
 	matchedPRs, err := p.getPipelineRunsFromRepo(ctx, repo)
 	if err != nil {
 		return nil, repo, err
@@ -41,10 +80,23 @@ func (p *PacRun) matchRepoPR(ctx context.Context) ([]matcher.Match, *v1alpha1.Re
 // verifyRepoAndUser verifies if the Repo CR exists for the Git Repository,
 // if the user has permission to run CI  and also initialise provider client
 func (p *PacRun) verifyRepoAndUser(ctx context.Context) (*v1alpha1.Repository, error) {
-	// Match the Event URL to a Repository URL,
-	repo, err := matcher.MatchEventURLRepo(ctx, p.run, p.event, "")
-	if err != nil {
-		return nil, err
+	// the Event URL has been matched to a Repository URL before
+	repo := p.repo
+	var err error
+
+	// only the events of a GitHub App can be trusted before having a
+	// Repository, the other providers need its webhook secret.
+	if repo == nil && p.event.InstallationID > 0 && p.run.Info.Pac.AutoProvisionRepositories && !p.event.BranchDeleted {
+		if repo, err = p.provisionRepository(ctx); err != nil {
+			p.eventEmitter.EmitMessage(nil, zap.ErrorLevel, "RepositoryProvision",
+				fmt.Sprintf("cannot provision a repository for %s: %s", p.event.URL, err.Error()))
+			return nil, err
+		}
+	}
+
+	if repo == nil && p.event.BranchDeleted {
+		p.logger.Infof("no repository has been matched on %s, nothing to cleanup for the deleted branch %s", p.event.URL, p.event.BaseBranch)
+		return nil, nil
 	}
 
 	if repo == nil {
@@ -68,6 +120,12 @@ func (p *PacRun) verifyRepoAndUser(ctx context.Context) (*v1alpha1.Repository, e
 		return nil, nil
 	}
 
+	// another controller of the cluster has been assigned the repository
+	if !p.ownsRepository(repo) {
+		p.logger.Infof("repository %s/%s is assigned to the controller %s, skipping the event", repo.GetNamespace(), repo.GetName(), repositoryOwner(repo))
+		return nil, nil
+	}
+
 	// If we have a git_provider field in repository spec, then get all the
 	// information from there, including the webhook secret.
 	// otherwise get the secret from the current ns (i.e: pipelines-as-code/openshift-pipelines.)
@@ -78,10 +136,13 @@ func (p *PacRun) verifyRepoAndUser(ctx context.Context) (*v1alpha1.Repository, e
 	// so instead of having to specify their in Repo each time, they use a
 	// shared one from pac.
 	if p.event.InstallationID > 0 {
-		p.event.Provider.WebhookSecret, _ = GetCurrentNSWebhookSecret(ctx, p.k8int)
+		p.event.Provider.WebhookSecret, _ = GetGitHubAppWebhookSecret(ctx, p.run, p.k8int, p.event)
+		p.event.Provider.PreviousWebhookSecret = GetGitHubAppPreviousWebhookSecret(ctx, p.run, p.k8int, p.event)
 	} else {
 		err := SecretFromRepository(ctx, p.run, p.k8int, p.vcx.GetConfig(), p.event, repo, p.logger)
 		if err != nil {
+			p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositorySecret",
+				fmt.Sprintf("cannot get the git provider secret of the repository: %s", err.Error()))
 			return repo, err
 		}
 	}
@@ -89,7 +150,7 @@ func (p *PacRun) verifyRepoAndUser(ctx context.Context) (*v1alpha1.Repository, e
 	// validate payload  for webhook secret
 	// we don't need to validate it in incoming since we already do this
 	if p.event.EventType != "incoming" {
-		if err := p.vcx.Validate(ctx, p.run, p.event); err != nil {
+		if err := p.validatePayload(ctx); err != nil {
 			// check that webhook secret has no /n or space into it
 			if strings.ContainsAny(p.event.Provider.WebhookSecret, "\n ") {
 				msg := `we have failed to validate the payload with the webhook secret,
@@ -101,36 +162,66 @@ is that what you want? make sure you use -n when generating the secret, eg: echo
 		}
 	}
 
+	// only claim the repository once we know the event is genuine
+	if !p.claimRepository(ctx, repo) {
+		return nil, nil
+	}
+
+	// the deleted branch only gets cleaned up, we don't need the provider
+	if p.event.BranchDeleted {
+		return repo, nil
+	}
+
 	// Set the client, we should error out if there is a problem with
 	// token or secret or we won't be able to do much.
 	err = p.vcx.SetClient(ctx, p.run, p.event)
 	if err != nil {
+		p.emitProviderError(repo, "cannot set the client of the git provider", err)
 		return repo, err
 	}
 
 	// Get the SHA commit info, we want to get the URL and commit title
 	err = p.vcx.GetCommitInfo(ctx, p.event)
 	if err != nil {
+		p.emitProviderError(repo, fmt.Sprintf("cannot get the commit info of %s", p.event.SHA), err)
 		return repo, err
 	}
 
-	// Check if the submitter is allowed to run this.
-	if p.event.TriggerTarget != "push" {
-		allowed, err := p.vcx.IsAllowed(ctx, p.event)
+	// the protection of the target branch is exposed to the CEL expressions
+	// and select the protected_branch_params
+	if p.event.BaseBranch != "" {
+		protected, err := p.vcx.IsRefProtected(ctx, p.event, p.event.BaseBranch)
 		if err != nil {
+			p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryBranchProtection",
+				fmt.Sprintf("cannot get the protection of %s, considering it as not protected: %s", p.event.BaseBranch, err.Error()))
+		}
+		p.event.BaseBranchProtected = protected
+	}
+
+	// Check if the submitter is allowed to run this, closing a pull request
+	// or removing its labels only cancel the runs so we don't need to check it.
+	if p.event.TriggerTarget != "push" && !p.event.CancelInProgress && !p.onlyLabelsRemoved() {
+		allowed, err := p.isAllowed(ctx, repo)
+		if err != nil {
+			p.emitProviderError(repo, fmt.Sprintf("cannot check the permissions of %s", p.event.Sender), err)
 			return repo, err
 		}
 		if !allowed {
-			msg := fmt.Sprintf("User %s is not allowed to run CI on this repo.", p.event.Sender)
-			if p.event.AccountID != "" {
-				msg = fmt.Sprintf("User: %s AccountID: %s is not allowed to run CI on this repo.", p.event.Sender, p.event.AccountID)
-			}
+			// the kubernetes event and the logs keep the user, the status
+			// may have to hide it on public repositories
+			msg := p.notAllowedMessage(repo, p.event.Sender, p.event.AccountID)
 			p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryPermissionDenied", msg)
+			// the commands commented on the issues may be for other bots,
+			// the users are not replied they are not allowed
+			if p.event.IssueNumber != 0 {
+				return nil, nil
+			}
 
 			status := provider.StatusOpts{
 				Status:     "completed",
 				Conclusion: "skipped",
-				Text:       msg,
+				Text: p.notAllowedMessage(repo, p.run.Info.Pac.PublicUser(p.event, p.event.Sender),
+					p.run.Info.Pac.PublicUser(p.event, p.event.AccountID)),
 				DetailsURL: "https://tenor.com/search/police-cat-gifs",
 			}
 			if err := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, status); err != nil {
@@ -142,18 +233,69 @@ is that what you want? make sure you use -n when generating the secret, eg: echo
 	return repo, nil
 }
 
+// emitProviderError emits an event on the Repository for an error of the API
+// of the git provider
+func (p *PacRun) emitProviderError(repo *v1alpha1.Repository, msg string, err error) {
+	p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryProviderAPIError",
+		fmt.Sprintf("%s on %s: %s", msg, p.vcx.GetConfig().Name, err.Error()))
+}
+
+func (p *PacRun) notAllowedMessage(repo *v1alpha1.Repository, sender, accountID string) string {
+	user := "User " + sender
+	if accountID != "" {
+		user = fmt.Sprintf("User: %s AccountID: %s", sender, accountID)
+	}
+	if p.event.CommentCommand != "" {
+		if permission := acl.CommandPermission(repo, p.event.CommentCommand); permission != acl.PermissionMember {
+			return fmt.Sprintf("%s is not allowed to run /%s on this repo, it needs the %s permission.", user, p.event.CommentCommand, permission)
+		}
+	}
+	return user + " is not allowed to run CI on this repo."
+}
+
 // getPipelineRunsFromRepo fetches pipelineruns from git repository and prepare them for creation
 func (p *PacRun) getPipelineRunsFromRepo(ctx context.Context, repo *v1alpha1.Repository) ([]matcher.Match, error) {
-	rawTemplates, err := p.vcx.GetTektonDir(ctx, p.event, tektonDir)
+	event, err := p.provenanceEvent(repo)
+	if err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryPipelineRunProvenance", err.Error())
+		return nil, err
+	}
+	// the PipelineRuns changed by a pull request from a fork may need the
+	// approval of a maintainer or get replaced by the ones of the default
+	// branch
+	if event == p.event {
+		event, err = p.forkTektonChangesEvent(ctx, repo, event)
+		if err != nil || event == nil {
+			return nil, err
+		}
+	}
+
+	// the Repository of a monorepo only runs when its sub_path changed
+	if !p.subPathChanged(ctx, repo) {
+		p.logger.Infof("no file has changed in %s, skipping the pipelineruns of the repository %s/%s",
+			formatting.ShowSubPath(*repo), repo.GetNamespace(), repo.GetName())
+		return nil, nil
+	}
+
+	dir := repoTektonDir(repo)
+	rawTemplates, err := p.getTektonDir(ctx, repo, event, dir)
 	if err != nil || rawTemplates == "" {
-		msg := fmt.Sprintf("cannot locate templates in %s/ directory for this repository in %s", tektonDir, p.event.HeadBranch)
+		msg := fmt.Sprintf("cannot locate templates in %s/ directory for this repository in %s", dir, event.HeadBranch)
 		if err != nil {
 			msg += fmt.Sprintf(" err: %s", err.Error())
 		}
-		p.eventEmitter.EmitMessage(nil, zap.InfoLevel, "RepositoryPipelineRunNotFound", msg)
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryPipelineRunNotFound", msg)
 		return nil, nil
 	}
 
+	// lint the tekton directory files changed by the pull request so problems
+	// are reported before they get merged
+	if p.event.TriggerTarget == "pull_request" && p.run.Info.Pac.TektonLint {
+		if err := p.lintTektonDir(ctx, repo); err != nil {
+			p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryTektonLint", fmt.Sprintf("cannot lint %s directory: %s", dir, err.Error()))
+		}
+	}
+
 	// check for condition if need update the pipelinerun with regexp from the
 	// "raw" pipelinerun string
 	if msg, needUpdate := p.checkNeedUpdate(rawTemplates); needUpdate {
@@ -162,18 +304,35 @@ func (p *PacRun) getPipelineRunsFromRepo(ctx context.Context, repo *v1alpha1.Rep
 	}
 
 	// Replace those {{var}} placeholders user has in her template to the run.Info variable
-	allTemplates := templates.Process(p.event, repo, rawTemplates)
-	pipelineRuns, err := resolve.Resolve(ctx, p.run, p.logger, p.vcx, p.event, allTemplates, &resolve.Opts{
-		GenerateName: true,
-		RemoteTasks:  p.run.Info.Pac.RemoteTasks,
-	})
+	allTemplates := p.processTemplates(ctx, repo, rawTemplates)
+	ropt := &resolve.Opts{
+		GenerateName:       true,
+		RemoteTasks:        p.run.Info.Pac.RemoteTasks,
+		AllowedTaskSources: repo.Spec.AllowedTaskSources,
+		Params:             runParams(repo, p.event),
+	}
+
+	// if the repository has some rules, we need to know which files has been
+	// changed to get the scheduling hints to apply.
+	if repo.Spec.Rules != nil && len(*repo.Spec.Rules) > 0 {
+		changedFiles, err := p.vcx.GetFiles(ctx, p.event)
+		if err != nil {
+			p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryRulesChangedFiles",
+				fmt.Sprintf("cannot get changed files, skipping repository rules: %s", err.Error()))
+		} else {
+			ropt.Rules = *repo.Spec.Rules
+			ropt.ChangedFiles = changedFiles
+		}
+	}
+
+	pipelineRuns, err := resolve.Resolve(ctx, p.run, p.logger, p.vcx, event, allTemplates, ropt)
 	if err != nil {
 		p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryFailedToMatch", fmt.Sprintf("failed to match pipelineRuns: %s", err.Error()))
 		return nil, err
 	}
 	if pipelineRuns == nil {
-		msg := fmt.Sprintf("cannot locate templates in %s/ directory for this repository in %s", tektonDir, p.event.HeadBranch)
-		p.eventEmitter.EmitMessage(nil, zap.InfoLevel, "RepositoryCannotLocatePipelineRun", msg)
+		msg := fmt.Sprintf("cannot locate templates in %s/ directory for this repository in %s", dir, event.HeadBranch)
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryCannotLocatePipelineRun", msg)
 		return nil, nil
 	}
 
@@ -194,25 +353,72 @@ func (p *PacRun) getPipelineRunsFromRepo(ctx context.Context, repo *v1alpha1.Rep
 	matchedPRs, err := matcher.MatchPipelinerunByAnnotation(ctx, p.logger, pipelineRuns, p.run, p.event, p.vcx)
 	if err != nil {
 		// Don't fail when you don't have a match between pipeline and annotations
-		p.eventEmitter.EmitMessage(nil, zap.WarnLevel, "RepositoryNoMatch", err.Error())
+		p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryNoMatch", err.Error())
 		return nil, nil
 	}
 
+	names := []string{}
+	for _, match := range matchedPRs {
+		names = append(names, match.PipelineRun.GetGenerateName())
+	}
+	p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryPipelineRunMatched",
+		fmt.Sprintf("the %s event on %s has matched the pipelineruns: %s", p.event.EventType, p.event.SHA, strings.Join(names, ", ")))
 	return matchedPRs, nil
 }
 
+// provenanceEvent return the event to fetch the PipelineRuns from, a copy of
+// the event pointing to the default branch when the Repository
+// pipelinerun_provenance asks for it.
+func (p *PacRun) provenanceEvent(repo *v1alpha1.Repository) (*info.Event, error) {
+	if repo.Spec.PipelineRunProvenance != policy.ProvenanceDefaultBranch {
+		return p.event, nil
+	}
+	if p.event.DefaultBranch == "" {
+		return nil, fmt.Errorf("cannot fetch the pipelineruns from the default branch, the default branch of %s is unknown", p.event.URL)
+	}
+	event := &info.Event{}
+	p.event.DeepCopyInto(event)
+	event.SHA = p.event.DefaultBranch
+	event.HeadBranch = p.event.DefaultBranch
+	return event, nil
+}
+
+// runParams return the params of the Repository to add to the PipelineRuns,
+// the params of a /test comment override them and the protected_branch_params
+// override both when the event targets a protected branch. The params of the
+// comment come from the payload, their secrets are redacted.
+func runParams(repo *v1alpha1.Repository, event *info.Event) map[string]string {
+	protected := event.BaseBranchProtected && len(repo.Spec.ProtectedBranchParams) > 0
+	if !protected && len(event.TestParams) == 0 {
+		return repo.Spec.Params
+	}
+	params := map[string]string{}
+	for k, v := range repo.Spec.Params {
+		params[k] = v
+	}
+	for k, v := range event.TestParams {
+		params[k] = templates.Redact(event, v)
+	}
+	if protected {
+		for k, v := range repo.Spec.ProtectedBranchParams {
+			params[k] = v
+		}
+	}
+	return params
+}
+
 func filterRunningPipelineRunOnTargetTest(testPipeline string, prs []*tektonv1beta1.PipelineRun) []*tektonv1beta1.PipelineRun {
 	if testPipeline == "" {
 		return prs
 	}
+	// all the PipelineRuns of a matrix are run again with its name
+	var matched []*tektonv1beta1.PipelineRun
 	for _, pr := range prs {
-		if prName, ok := pr.GetLabels()[apipac.OriginalPRName]; ok {
-			if prName == testPipeline {
-				return []*tektonv1beta1.PipelineRun{pr}
-			}
+		if pr.GetLabels()[apipac.OriginalPRName] == testPipeline || pr.GetLabels()[apipac.MatrixPipelineRun] == testPipeline {
+			matched = append(matched, pr)
 		}
 	}
-	return nil
+	return matched
 }
 
 // changeSecret we need to go in each pipelinerun,
@@ -5,8 +5,11 @@ import (
 	"testing"
 
 	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/policy"
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"gotest.tools/v3/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -73,7 +76,73 @@ func TestFilterRunningPipelineRunOnTargetTest(t *testing.T) {
 	assert.Equal(t, prs[0].GetName(), ret[0].GetName())
 	ret = filterRunningPipelineRunOnTargetTest(testPipeline, prs)
 	assert.Equal(t, prs[0].GetName(), ret[0].GetName())
+	matrix := []*tektonv1beta1.PipelineRun{
+		{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{apipac.OriginalPRName: "build-linux", apipac.MatrixPipelineRun: "build"}}},
+		{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{apipac.OriginalPRName: "build-darwin", apipac.MatrixPipelineRun: "build"}}},
+	}
+	assert.Equal(t, len(filterRunningPipelineRunOnTargetTest("build", matrix)), 2)
+	assert.Equal(t, len(filterRunningPipelineRunOnTargetTest("build-darwin", matrix)), 1)
 	prs = []*tektonv1beta1.PipelineRun{}
 	ret = filterRunningPipelineRunOnTargetTest(testPipeline, prs)
 	assert.Assert(t, ret == nil)
 }
+
+func TestProvenanceEvent(t *testing.T) {
+	tests := []struct {
+		name       string
+		provenance string
+		event      *info.Event
+		wantSHA    string
+		wantErr    string
+	}{
+		{
+			name:    "from the source of the event",
+			event:   &info.Event{SHA: "abc", HeadBranch: "feature", DefaultBranch: "main"},
+			wantSHA: "abc",
+		},
+		{
+			name:       "from the default branch",
+			provenance: policy.ProvenanceDefaultBranch,
+			event:      &info.Event{SHA: "abc", HeadBranch: "feature", DefaultBranch: "main"},
+			wantSHA:    "main",
+		},
+		{
+			name:       "unknown default branch",
+			provenance: policy.ProvenanceDefaultBranch,
+			event:      &info.Event{SHA: "abc", HeadBranch: "feature", URL: "https://forge/org/repo"},
+			wantErr:    "the default branch of https://forge/org/repo is unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPacs(tt.event, nil, &params.Run{Clients: clients.Clients{}}, nil, nil)
+			repo := &v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{PipelineRunProvenance: tt.provenance}}
+			got, err := p.provenanceEvent(repo)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got.SHA, tt.wantSHA)
+			// the event itself is not modified
+			assert.Equal(t, tt.event.SHA, "abc")
+		})
+	}
+}
+
+func TestRunParams(t *testing.T) {
+	repo := &v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{
+		Params:                map[string]string{"registry": "quay.io/org", "signed": "false"},
+		ProtectedBranchParams: map[string]string{"signed": "true"},
+	}}
+	assert.DeepEqual(t, runParams(repo, &info.Event{}), map[string]string{"registry": "quay.io/org", "signed": "false"})
+	assert.DeepEqual(t, runParams(repo, &info.Event{BaseBranchProtected: true}),
+		map[string]string{"registry": "quay.io/org", "signed": "true"})
+	testParams := map[string]string{"registry": "ghcr.io/org", "signed": "false", "cluster": "gke"}
+	assert.DeepEqual(t, runParams(repo, &info.Event{State: info.State{TestParams: testParams}}),
+		map[string]string{"registry": "ghcr.io/org", "signed": "false", "cluster": "gke"})
+	assert.DeepEqual(t, runParams(repo, &info.Event{BaseBranchProtected: true, State: info.State{TestParams: testParams}}),
+		map[string]string{"registry": "ghcr.io/org", "signed": "true", "cluster": "gke"})
+	// the params of the repository are not modified
+	assert.Equal(t, repo.Spec.Params["signed"], "false")
+}
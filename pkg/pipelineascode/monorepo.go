@@ -0,0 +1,62 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"go.uber.org/zap"
+)
+
+// repoTektonDir returns the tekton directory of a Repository, in its sub_path
+// for a monorepo.
+func repoTektonDir(repo *v1alpha1.Repository) string {
+	return path.Join(formatting.ShowSubPath(*repo), tektonDir)
+}
+
+// runSubPathRepositories runs the event for each of the Repositories of a
+// monorepo, one after the other since they share the provider client. Each of
+// them gets its own copy of the event as it gets updated while matching it.
+func (p *PacRun) runSubPathRepositories(ctx context.Context, repos []*v1alpha1.Repository) error {
+	var errs []string
+	for _, repo := range repos {
+		event := &info.Event{}
+		p.event.DeepCopyInto(event)
+		logger := p.logger.With("namespace", repo.GetNamespace(), "repository", repo.GetName())
+		sub := NewPacs(event, p.vcx, p.run, p.k8int, logger)
+		sub.repo = repo
+		if err := sub.Run(ctx); err != nil {
+			errs = append(errs, fmt.Sprintf("%s/%s: %s", repo.GetNamespace(), repo.GetName(), err.Error()))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to run the event on the repositories %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// subPathChanged returns whether the event changed a file in the sub_path of
+// the Repository. The incoming webhooks are not about a change and always
+// match, so are the events we cannot get the changed files of.
+func (p *PacRun) subPathChanged(ctx context.Context, repo *v1alpha1.Repository) bool {
+	dir := formatting.ShowSubPath(*repo)
+	if dir == "" || p.event.EventType == "incoming" {
+		return true
+	}
+	changedFiles, err := p.vcx.GetFiles(ctx, p.event)
+	if err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositorySubPathChangedFiles",
+			fmt.Sprintf("cannot get changed files, running the pipelineruns of %s anyway: %s", dir, err.Error()))
+		return true
+	}
+	for _, file := range changedFiles {
+		if file == dir || strings.HasPrefix(file, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	kitesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func subPathRepo(name, subPath string) *v1alpha1.Repository {
+	return &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: name},
+		Spec:       v1alpha1.RepositorySpec{URL: "https://github.com/owner/monorepo", SubPath: subPath},
+	}
+}
+
+func TestRepoTektonDir(t *testing.T) {
+	assert.Equal(t, repoTektonDir(subPathRepo("root", "")), ".tekton")
+	assert.Equal(t, repoTektonDir(subPathRepo("api", "/services/api/")), "services/api/.tekton")
+}
+
+func TestSubPathChanged(t *testing.T) {
+	tests := []struct {
+		name         string
+		subPath      string
+		eventType    string
+		changedFiles []string
+		want         bool
+	}{
+		{
+			name:         "no sub path",
+			changedFiles: []string{"README.md"},
+			want:         true,
+		},
+		{
+			name:         "file changed in the sub path",
+			subPath:      "services/api",
+			changedFiles: []string{"README.md", "services/api/main.go"},
+			want:         true,
+		},
+		{
+			name:         "file changed in another sub path with the same prefix",
+			subPath:      "services/api",
+			changedFiles: []string{"services/api-gateway/main.go"},
+		},
+		{
+			name:      "incoming webhook",
+			subPath:   "services/api",
+			eventType: "incoming",
+			want:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			vcx := &testprovider.TestProviderImp{ChangedFiles: tt.changedFiles}
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+			cs := &params.Run{Clients: clients.Clients{Log: logger, Kube: stdata.Kube}}
+			pac := NewPacs(&info.Event{EventType: tt.eventType}, vcx, cs, nil, logger)
+			assert.Equal(t, pac.subPathChanged(ctx, subPathRepo("api", tt.subPath)), tt.want)
+		})
+	}
+}
+
+func TestRunSubPathRepositories(t *testing.T) {
+	observer, logs := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Repositories: []*v1alpha1.Repository{subPathRepo("api", "services/api"), subPathRepo("web", "services/web")},
+	})
+	cs := &params.Run{
+		Clients: clients.Clients{
+			Log:            logger,
+			Kube:           stdata.Kube,
+			Tekton:         stdata.Pipeline,
+			PipelineAsCode: stdata.PipelineAsCode,
+		},
+		Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{}}},
+	}
+	vcx := &testprovider.TestProviderImp{ChangedFiles: []string{"services/api/main.go"}}
+	event := &info.Event{
+		EventType:      "push",
+		TriggerTarget:  "push",
+		URL:            "https://github.com/owner/monorepo",
+		InstallationID: 1,
+		Provider:       &info.Provider{},
+		SHA:            "sha",
+		HeadBranch:     "main",
+	}
+	pac := NewPacs(event, vcx, cs, &kitesthelper.KinterfaceTest{}, logger)
+	assert.NilError(t, pac.Run(ctx))
+
+	assert.Equal(t, logs.FilterMessageSnippet("no file has changed in services/web").Len(), 1)
+	assert.Equal(t, logs.FilterMessageSnippet("no file has changed in services/api").Len(), 0)
+	assert.Equal(t, logs.FilterMessageSnippet("cannot locate templates in services/api/.tekton/ directory").Len(), 1)
+}
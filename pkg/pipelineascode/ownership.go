@@ -0,0 +1,132 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// repositoryLeaseDuration is how long a controller keeps the Repository
+	// it has claimed without receiving any event for it.
+	repositoryLeaseDuration = time.Hour
+	repositoryLeaseAttempts = 3
+)
+
+// repositoryLeaseName is the name of the Lease in the namespace of the
+// Repository recording the controller processing its events.
+func repositoryLeaseName(repo *v1alpha1.Repository) string {
+	return fmt.Sprintf("pipelines-as-code-repository-%s", repo.GetName())
+}
+
+// repositoryOwner returns the controller the Repository has been assigned to
+// with the pipelinesascode.tekton.dev/controller label, or an empty string
+// when it has not been assigned to any.
+func repositoryOwner(repo *v1alpha1.Repository) string {
+	return repo.GetLabels()[keys.Controller]
+}
+
+// ownsRepository returns whether the controller processes the events of the
+// Repository, when there are multiple controllers on the cluster a Repository
+// can be assigned to one with a label.
+func (p *PacRun) ownsRepository(repo *v1alpha1.Repository) bool {
+	owner := repositoryOwner(repo)
+	return owner == "" || owner == formatting.K8LabelsCleanup(info.ControllerName())
+}
+
+// claimRepository claims a Repository which has not been assigned to a
+// controller with a Lease, the controllers receiving the same events then
+// never process them twice. The Lease is renewed on every event and another
+// controller can claim the Repository once it has expired.
+//
+// We keep processing the events when the Lease cannot be managed, ie: the
+// controller is not allowed to, a single controller doesn't need it.
+func (p *PacRun) claimRepository(ctx context.Context, repo *v1alpha1.Repository) bool {
+	if repositoryOwner(repo) != "" {
+		return true
+	}
+	controllerName := info.ControllerName()
+	leases := p.run.Clients.Kube.CoordinationV1().Leases(repo.GetNamespace())
+	name := repositoryLeaseName(repo)
+
+	for i := 0; i < repositoryLeaseAttempts; i++ {
+		now := metav1.NewMicroTime(time.Now())
+		lease, err := leases.Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			lease = &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: repo.GetNamespace(),
+					Labels:    map[string]string{keys.Repository: formatting.K8LabelsCleanup(repo.GetName())},
+				},
+				Spec: coordinationv1.LeaseSpec{
+					HolderIdentity:       &controllerName,
+					LeaseDurationSeconds: leaseDurationSeconds(),
+					AcquireTime:          &now,
+					RenewTime:            &now,
+				},
+			}
+			_, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+			if err == nil {
+				return true
+			}
+			if errors.IsAlreadyExists(err) {
+				continue
+			}
+		}
+		if err != nil {
+			p.logger.Warnf("cannot claim the repository %s/%s with a lease, processing its event anyway: %v", repo.GetNamespace(), repo.GetName(), err)
+			return true
+		}
+		if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != controllerName {
+			if !leaseExpired(lease, now.Time) {
+				holder := ""
+				if lease.Spec.HolderIdentity != nil {
+					holder = *lease.Spec.HolderIdentity
+				}
+				p.logger.Infof("repository %s/%s has been claimed by the controller %s, skipping the event", repo.GetNamespace(), repo.GetName(), holder)
+				return false
+			}
+			lease.Spec.HolderIdentity = &controllerName
+			lease.Spec.AcquireTime = &now
+			transitions := int32(1)
+			if lease.Spec.LeaseTransitions != nil {
+				transitions += *lease.Spec.LeaseTransitions
+			}
+			lease.Spec.LeaseTransitions = &transitions
+		}
+		lease.Spec.LeaseDurationSeconds = leaseDurationSeconds()
+		lease.Spec.RenewTime = &now
+		if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+			// another event or controller updated the lease in the
+			// meantime, check who is holding it now
+			if errors.IsConflict(err) {
+				continue
+			}
+			p.logger.Warnf("cannot renew the lease of the repository %s/%s, processing its event anyway: %v", repo.GetNamespace(), repo.GetName(), err)
+		}
+		return true
+	}
+	p.logger.Warnf("cannot claim the repository %s/%s after %d attempts, processing its event anyway", repo.GetNamespace(), repo.GetName(), repositoryLeaseAttempts)
+	return true
+}
+
+func leaseDurationSeconds() *int32 {
+	seconds := int32(repositoryLeaseDuration.Seconds())
+	return &seconds
+}
+
+func leaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	return lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second).Before(now)
+}
@@ -0,0 +1,141 @@
+package pipelineascode
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestOwnsRepository(t *testing.T) {
+	tests := []struct {
+		name           string
+		controllerName string
+		owner          string
+		want           bool
+	}{
+		{
+			name: "not assigned",
+			want: true,
+		},
+		{
+			name:           "assigned to the controller",
+			controllerName: "staging",
+			owner:          "staging",
+			want:           true,
+		},
+		{
+			name:           "assigned to another controller",
+			controllerName: "staging",
+			owner:          "prod",
+		},
+		{
+			name:  "assigned to a named controller",
+			owner: "prod",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(info.ControllerNameEnv, tt.controllerName)
+			repo := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}}
+			if tt.owner != "" {
+				repo.Labels = map[string]string{keys.Controller: tt.owner}
+			}
+			p := &PacRun{}
+			assert.Equal(t, p.ownsRepository(repo), tt.want)
+		})
+	}
+}
+
+func TestClaimRepository(t *testing.T) {
+	newLease := func(holder string, renewed time.Duration) *coordinationv1.Lease {
+		renewTime := metav1.NewMicroTime(time.Now().Add(-renewed))
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: "pipelines-as-code-repository-repo", Namespace: "ns"},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holder,
+				LeaseDurationSeconds: leaseDurationSeconds(),
+				RenewTime:            &renewTime,
+			},
+		}
+	}
+	tests := []struct {
+		name            string
+		assigned        bool
+		lease           *coordinationv1.Lease
+		want            bool
+		wantHolder      string
+		wantTransitions int32
+	}{
+		{
+			name:       "no lease",
+			want:       true,
+			wantHolder: "staging",
+		},
+		{
+			name:       "renew our lease",
+			lease:      newLease("staging", time.Minute),
+			want:       true,
+			wantHolder: "staging",
+		},
+		{
+			name:       "held by another controller",
+			lease:      newLease("prod", time.Minute),
+			wantHolder: "prod",
+		},
+		{
+			name:            "expired lease of another controller",
+			lease:           newLease("prod", 2*repositoryLeaseDuration),
+			want:            true,
+			wantHolder:      "staging",
+			wantTransitions: 1,
+		},
+		{
+			name:     "assigned with a label",
+			assigned: true,
+			lease:    newLease("prod", time.Minute),
+			want:     true,
+			// the lease of an assigned repository is left alone
+			wantHolder: "prod",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(info.ControllerNameEnv, "staging")
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+			if tt.lease != nil {
+				_, err := stdata.Kube.CoordinationV1().Leases("ns").Create(ctx, tt.lease, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+			repo := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}}
+			if tt.assigned {
+				repo.Labels = map[string]string{keys.Controller: "staging"}
+			}
+
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			run := &params.Run{Clients: clients.Clients{Log: logger, Kube: stdata.Kube}}
+			p := NewPacs(&info.Event{}, nil, run, nil, logger)
+			assert.Equal(t, p.claimRepository(ctx, repo), tt.want)
+
+			lease, err := stdata.Kube.CoordinationV1().Leases("ns").Get(ctx, repositoryLeaseName(repo), metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, *lease.Spec.HolderIdentity, tt.wantHolder)
+			if tt.wantTransitions > 0 {
+				assert.Equal(t, *lease.Spec.LeaseTransitions, tt.wantTransitions)
+			}
+		})
+	}
+}
@@ -4,16 +4,22 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cloudevents"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/freeze"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/notification"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/policy"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
@@ -32,18 +38,45 @@ type PacRun struct {
 	k8int        kubeinteraction.Interface
 	logger       *zap.SugaredLogger
 	eventEmitter *events.EventEmitter
+	cloudEvents  *cloudevents.Emitter
+	notifier     *notification.Notifier
 	manager      *ConcurrencyManager
+	policies     []v1alpha1.RepositoryPolicy
+	// repo is the Repository the event is run on when it has been matched
+	// beforehand, ie: for each of the sub_path of a monorepo
+	repo *v1alpha1.Repository
 }
 
 func NewPacs(event *info.Event, vcx provider.Interface, run *params.Run, k8int kubeinteraction.Interface, logger *zap.SugaredLogger) PacRun {
+	eventEmitter := events.NewEventEmitter(run.Clients.Kube, logger)
+	if event != nil {
+		eventEmitter.SetEventType(event.EventType)
+	}
 	return PacRun{
 		event: event, run: run, vcx: vcx, k8int: k8int, logger: logger,
-		eventEmitter: events.NewEventEmitter(run.Clients.Kube, logger),
+		eventEmitter: eventEmitter,
+		cloudEvents:  cloudevents.NewEmitter(logger),
+		notifier:     notification.NewNotifier(run.Clients.Kube, logger),
 		manager:      NewConcurrencyManager(),
 	}
 }
 
 func (p *PacRun) Run(ctx context.Context) error {
+	// a monorepo has a Repository for each of its sub_path, the event is run
+	// on each of them.
+	if p.repo == nil {
+		repos, err := matcher.MatchEventURLRepos(ctx, p.run, p.event, "")
+		if err != nil {
+			return err
+		}
+		if len(repos) > 1 {
+			return p.runSubPathRepositories(ctx, repos)
+		}
+		if len(repos) == 1 {
+			p.repo = repos[0]
+		}
+	}
+
 	matchedPRs, repo, err := p.matchRepoPR(ctx)
 	if err != nil {
 		createStatusErr := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, provider.StatusOpts{
@@ -57,10 +90,39 @@ func (p *PacRun) Run(ctx context.Context) error {
 			p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryCreateStatus", fmt.Sprintf("Cannot create status: %s: %s", err, createStatusErr))
 		}
 	}
+	// skip or queue the PipelineRuns of a draft pull request until it is
+	// ready for review
+	matchedPRs = p.gateDraftPipelineRuns(ctx, repo, matchedPRs)
 	if len(matchedPRs) == 0 {
 		return nil
 	}
-	if repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit != 0 {
+
+	// skip or queue the PipelineRuns until the end of the freeze window
+	// the PipelineRuns are not run when the freeze windows can't be checked,
+	// they may be in one
+	frozen, err := p.frozenStatus(repo)
+	if err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryFreezeWindow",
+			fmt.Sprintf("cannot check the freeze windows, not running the pipelineruns: %s", err.Error()))
+		if createStatusErr := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, provider.StatusOpts{
+			Status:     "completed",
+			Conclusion: "failure",
+			Text:       fmt.Sprintf("The PipelineRuns have not been run, the freeze windows cannot be checked: %q", err),
+			DetailsURL: p.run.Clients.ConsoleUI.URL(),
+		}); createStatusErr != nil {
+			p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryCreateStatus", fmt.Sprintf("Cannot create status: %s: %s", err, createStatusErr))
+		}
+		return err
+	}
+	if frozen != nil && frozen.Action == freeze.ActionSkip {
+		p.skipFrozenPipelineRuns(ctx, repo, matchedPRs, frozen)
+		return nil
+	}
+
+	// the PipelineRuns beyond the max_parallel of the Repository are queued
+	// as well, until the ones of the event before them are done
+	fanOut := repo.Spec.MaxParallel != nil && *repo.Spec.MaxParallel > 0 && len(matchedPRs) > *repo.Spec.MaxParallel
+	if fanOut || (repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit != 0) {
 		p.manager.Enable()
 	}
 
@@ -68,6 +130,14 @@ func (p *PacRun) Run(ctx context.Context) error {
 	for _, match := range matchedPRs {
 		if match.Repo == nil {
 			match.Repo = repo
+		} else {
+			match.Repo = policy.Apply(match.Repo, p.policies)
+		}
+		if frozen != nil {
+			if match.PipelineRun.Annotations == nil {
+				match.PipelineRun.Annotations = map[string]string{}
+			}
+			match.PipelineRun.Annotations[keys.FrozenUntil] = frozen.Until.Format(time.RFC3339)
 		}
 		wg.Add(1)
 
@@ -120,16 +190,45 @@ func (p *PacRun) startPR(ctx context.Context, match matcher.Match) (*v1beta1.Pip
 		}
 
 		if err = p.k8int.CreateSecret(ctx, match.Repo.GetNamespace(), authSecret); err != nil {
+			p.eventEmitter.EmitMessage(match.Repo, zap.ErrorLevel, "RepositoryGitAuthSecret",
+				fmt.Sprintf("cannot create the git auth secret %s of pipelinerun %s: %s", gitAuthSecretName, match.PipelineRun.GetGenerateName(), err.Error()))
 			return nil, fmt.Errorf("creating basic auth secret: %s has failed: %w ", gitAuthSecretName, err)
 		}
 	}
 
+	// cancel the previous runs of this PipelineRun still running if asked
+	if match.PipelineRun.GetAnnotations()[keys.CancelInProgress] == "true" {
+		if err := p.cancelPreviousInstances(ctx, match); err != nil {
+			p.eventEmitter.EmitMessage(match.Repo, zap.WarnLevel, "RepositoryCancelInProgress",
+				fmt.Sprintf("cannot cancel previous pipelineruns of %s: %s", match.PipelineRun.GetGenerateName(), err.Error()))
+		}
+	}
+
+	// set the timeouts of the annotation, the PipelineRun keeps its own
+	// timeouts when the annotation is invalid
+	if err := kubeinteraction.ApplyTimeouts(match.PipelineRun); err != nil {
+		p.eventEmitter.EmitMessage(match.Repo, zap.WarnLevel, "RepositoryPipelineRunTimeouts",
+			fmt.Sprintf("ignoring the timeouts of pipelinerun %s: %s", match.PipelineRun.GetGenerateName(), err.Error()))
+	}
+
 	// Add labels and annotations to pipelinerun
 	kubeinteraction.AddLabelsAndAnnotations(p.event, match.PipelineRun, match.Repo, p.vcx.GetConfig())
 
-	// if concurrency is defined then start the pipelineRun in pending state and
-	// state as queued
-	if match.Repo.Spec.ConcurrencyLimit != nil && *match.Repo.Spec.ConcurrencyLimit != 0 {
+	// mint the registry credentials of the run and attach them to its
+	// ServiceAccount before creating it, so its pods have them on startup
+	var registrySecretName string
+	if p.run.Info.Pac.RegistryCredentialsBrokerURL != "" {
+		var err error
+		if registrySecretName, err = p.createRegistrySecret(ctx, match); err != nil {
+			return nil, err
+		}
+	}
+
+	// if concurrency is defined, the event matched more PipelineRuns than the
+	// max parallel or the repository is frozen then start the pipelineRun in
+	// pending state and state as queued
+	_, frozen := match.PipelineRun.GetAnnotations()[keys.FrozenUntil]
+	if frozen || p.manager.enabled || (match.Repo.Spec.ConcurrencyLimit != nil && *match.Repo.Spec.ConcurrencyLimit != 0) {
 		// pending status
 		match.PipelineRun.Spec.Status = v1beta1.PipelineRunSpecStatusPending
 		// pac state as queued
@@ -140,13 +239,16 @@ func (p *PacRun) startPR(ctx context.Context, match matcher.Match) (*v1beta1.Pip
 	pr, err := p.run.Clients.Tekton.TektonV1beta1().PipelineRuns(match.Repo.GetNamespace()).Create(ctx,
 		match.PipelineRun, metav1.CreateOptions{})
 	if err != nil {
+		if registrySecretName != "" {
+			p.deleteRegistrySecret(ctx, match.Repo, registrySecretName)
+		}
 		return nil, fmt.Errorf("creating pipelinerun %s in %s has failed: %w ", match.PipelineRun.GetGenerateName(),
 			match.Repo.GetNamespace(), err)
 	}
 
-	// Create status with the log url
-	p.logger.Infof("pipelinerun %s has been created in namespace %s for SHA: %s Target Branch: %s",
-		pr.GetName(), match.Repo.GetNamespace(), p.event.SHA, p.event.BaseBranch)
+	p.eventEmitter.EmitMessage(match.Repo, zap.InfoLevel, "RepositoryPipelineRunCreated",
+		fmt.Sprintf("pipelinerun %s has been created in namespace %s for SHA: %s Target Branch: %s",
+			pr.GetName(), match.Repo.GetNamespace(), p.event.SHA, p.event.BaseBranch))
 	consoleURL := p.run.Clients.ConsoleUI.DetailURL(match.Repo.GetNamespace(), pr.GetName())
 	// Create status with the log url
 	msg := fmt.Sprintf(params.StartingPipelineRunText,
@@ -165,10 +267,22 @@ func (p *PacRun) startPR(ctx context.Context, match matcher.Match) (*v1beta1.Pip
 		OriginalPipelineRunName: pr.GetLabels()[keys.OriginalPRName],
 	}
 
+	cloudEventType := cloudevents.PipelineRunStarted
 	// if pipelineRun is in pending state then report status as queued
 	if pr.Spec.Status == v1beta1.PipelineRunSpecStatusPending {
+		cloudEventType = cloudevents.PipelineRunQueued
 		status.Status = "queued"
 		status.Text = fmt.Sprintf(params.QueuingPipelineRunText, pr.GetName(), match.Repo.GetNamespace())
+		p.eventEmitter.EmitMessage(match.Repo, zap.InfoLevel, "RepositoryPipelineRunQueued",
+			fmt.Sprintf("pipelinerun %s has been queued in namespace %s", pr.GetName(), match.Repo.GetNamespace()))
+		if until, ok := pr.GetAnnotations()[keys.FrozenUntil]; ok {
+			status.Text = fmt.Sprintf(params.FrozenPipelineRunText, until, pr.GetName(), "has been queued") + status.Text
+		}
+	}
+
+	p.cloudEvents.Emit(ctx, p.run.Info.Pac.CloudEventsSinkURL, cloudEventType, match.Repo, pr)
+	if cloudEventType == cloudevents.PipelineRunStarted {
+		p.notifier.Notify(ctx, notification.Started, match.Repo, pr)
 	}
 
 	if err := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, status); err != nil {
@@ -185,7 +299,12 @@ func (p *PacRun) startPR(ctx context.Context, match matcher.Match) (*v1beta1.Pip
 
 	// update ownerRef of secret with pipelineRun, so that it gets cleanedUp with pipelineRun
 	if p.run.Info.Pac.SecretAutoCreation {
-		return pr, p.k8int.UpdateSecretWithOwnerRef(ctx, p.logger, pr.Namespace, gitAuthSecretName, pr)
+		if err := p.k8int.UpdateSecretWithOwnerRef(ctx, p.logger, pr.Namespace, gitAuthSecretName, pr); err != nil {
+			return pr, err
+		}
+	}
+	if registrySecretName != "" {
+		return pr, p.k8int.UpdateSecretWithOwnerRef(ctx, p.logger, pr.Namespace, registrySecretName, pr)
 	}
 	return pr, nil
 }
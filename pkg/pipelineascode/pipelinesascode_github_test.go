@@ -22,6 +22,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	ghprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provision"
 	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
 	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
 	kitesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
@@ -33,6 +34,8 @@ import (
 	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
@@ -108,6 +111,11 @@ func TestRun(t *testing.T) {
 		WebHookSecretValue           string
 		PayloadEncodedSecret         string
 		expectedLogSnippet           string
+		freezeWindows                string
+		wantFrozen                   bool
+		autoProvision                bool
+		wantProvisioned              string
+		wantEventReasons             []string
 	}{
 		{
 			name: "pull request/fail-to-start-apps",
@@ -237,6 +245,71 @@ func TestRun(t *testing.T) {
 			finalStatus: "neutral",
 		},
 
+		// Frozen
+		{
+			name: "Frozen/skip",
+			runevent: info.Event{
+				SHA:           "principale",
+				Organization:  "organizationes",
+				Repository:    "lagaffe",
+				URL:           "https://service/documentation",
+				Sender:        "fantasio",
+				HeadBranch:    "refs/heads/main",
+				BaseBranch:    "refs/heads/main",
+				EventType:     "push",
+				TriggerTarget: "push",
+			},
+			tektondir:       "testdata/push_branch",
+			finalStatus:     "skipped",
+			finalStatusText: "The Repository is frozen until",
+			freezeWindows:   "- schedule: \"* * * * *\"\n  duration: 1h\n",
+		},
+		{
+			name: "Frozen/queue",
+			runevent: info.Event{
+				SHA:           "principale",
+				Organization:  "organizationes",
+				Repository:    "lagaffe",
+				URL:           "https://service/documentation",
+				Sender:        "fantasio",
+				HeadBranch:    "refs/heads/main",
+				BaseBranch:    "refs/heads/main",
+				EventType:     "push",
+				TriggerTarget: "push",
+			},
+			tektondir:     "testdata/push_branch",
+			finalStatus:   "neutral",
+			freezeWindows: "- schedule: \"* * * * *\"\n  duration: 1h\n  action: queue\n",
+			wantFrozen:    true,
+		},
+		{
+			name: "Provision/repository without a match",
+			runevent: info.Event{
+				SHA:           "principale",
+				Organization:  "organizationes",
+				Repository:    "lagaffe",
+				URL:           "https://service/Organizationes/Lagaffe",
+				Sender:        "fantasio",
+				HeadBranch:    "refs/heads/main",
+				BaseBranch:    "refs/heads/main",
+				EventType:     "push",
+				TriggerTarget: "push",
+			},
+			tektondir:       "testdata/push_branch",
+			finalStatus:     "neutral",
+			autoProvision:   true,
+			wantProvisioned: "organizationes-lagaffe",
+			repositories: []*v1alpha1.Repository{
+				testnewrepo.NewRepo(
+					testnewrepo.RepoTestcreationOpts{
+						Name:             "test-run",
+						URL:              "https://nowhere.com",
+						InstallNamespace: "namespace",
+					},
+				),
+			},
+		},
+
 		// Skipped
 		{
 			name: "Skipped/Test no tekton dir",
@@ -250,9 +323,10 @@ func TestRun(t *testing.T) {
 				BaseBranch:   "nomatch",
 				EventType:    "pull_request",
 			},
-			tektondir:       "",
-			finalStatus:     "skipped",
-			finalStatusText: "directory for this repository",
+			tektondir:        "",
+			finalStatus:      "skipped",
+			finalStatusText:  "directory for this repository",
+			wantEventReasons: []string{"RepositoryPipelineRunNotFound"},
 		},
 		// Skipped
 		{
@@ -316,6 +390,7 @@ func TestRun(t *testing.T) {
 			finalStatus:                  "skipped",
 			finalStatusText:              "is not allowed to run CI on this repo",
 			skipReplyingOrgPublicMembers: true,
+			wantEventReasons:             []string{"RepositoryPermissionDenied"},
 		},
 		{
 			name: "allowed/push event even from non allowed user",
@@ -417,6 +492,14 @@ func TestRun(t *testing.T) {
 			}
 
 			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+			// the fake client doesn't generate the names of the events
+			generated := 0
+			stdata.Kube.PrependReactor("create", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				event := action.(k8stesting.CreateAction).GetObject().(*corev1.Event)
+				generated++
+				event.Name = fmt.Sprintf("%s%d", event.GenerateName, generated)
+				return false, nil, nil
+			})
 			cs := &params.Run{
 				Clients: clients.Clients{
 					PipelineAsCode: stdata.PipelineAsCode,
@@ -428,7 +511,11 @@ func TestRun(t *testing.T) {
 				Info: info.Info{
 					Pac: &info.PacOpts{
 						Settings: &settings.Settings{
-							SecretAutoCreation: true,
+							SecretAutoCreation:             true,
+							FreezeWindows:                  tt.freezeWindows,
+							AutoProvisionRepositories:      tt.autoProvision,
+							AutoProvisionNamespaceTemplate: "{{repo_owner}}-{{repo_name}}",
+							AutoProvisionQuota:             "pods=10",
 						},
 					},
 				},
@@ -481,6 +568,33 @@ func TestRun(t *testing.T) {
 				assert.Assert(t, len(logmsg) > 0, "log messages", logmsg, tt.expectedLogSnippet)
 			}
 
+			if len(tt.wantEventReasons) > 0 {
+				events, err := cs.Clients.Kube.CoreV1().Events("namespace").List(ctx, metav1.ListOptions{})
+				assert.NilError(t, err)
+				reasons := map[string]bool{}
+				for _, event := range events.Items {
+					reasons[event.Reason] = true
+				}
+				for _, reason := range tt.wantEventReasons {
+					assert.Assert(t, reasons[reason], "no %s event on the repository, got %v", reason, reasons)
+				}
+			}
+
+			if tt.wantProvisioned != "" {
+				repo, err := cs.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(tt.wantProvisioned).Get(ctx, tt.wantProvisioned, metav1.GetOptions{})
+				assert.NilError(t, err)
+				assert.Equal(t, repo.Spec.URL, tt.runevent.URL)
+				_, err = cs.Clients.Kube.CoreV1().ResourceQuotas(tt.wantProvisioned).Get(ctx, provision.QuotaName, metav1.GetOptions{})
+				assert.NilError(t, err)
+			}
+
+			if tt.freezeWindows != "" && !tt.wantFrozen {
+				prs, err := cs.Clients.Tekton.TektonV1beta1().PipelineRuns("").List(ctx, metav1.ListOptions{})
+				assert.NilError(t, err)
+				// only the seeded pipelinerun
+				assert.Equal(t, len(prs.Items), 1)
+			}
+
 			if tt.finalStatus != "skipped" {
 				prs, err := cs.Clients.Tekton.TektonV1beta1().PipelineRuns("").List(ctx, metav1.ListOptions{})
 				assert.NilError(t, err)
@@ -499,6 +613,12 @@ func TestRun(t *testing.T) {
 					}
 					assert.Equal(t, logURL, cs.Clients.ConsoleUI.DetailURL(pr.Namespace, pr.Name))
 
+					if tt.wantFrozen {
+						_, ok := pr.GetAnnotations()[keys.FrozenUntil]
+						assert.Assert(t, ok, "pipelinerun %s has no frozen-until annotation", pr.GetName())
+						assert.Equal(t, pr.Spec.Status, pipelinev1beta1.PipelineRunSpecStatus(pipelinev1beta1.PipelineRunSpecStatusPending))
+					}
+
 					if cs.Info.Pac.SecretAutoCreation {
 						secretName := pr.GetAnnotations()[keys.GitAuthSecret]
 						secret, err := cs.Clients.Kube.CoreV1().Secrets(pr.Namespace).Get(ctx, secretName, metav1.GetOptions{})
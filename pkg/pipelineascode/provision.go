@@ -0,0 +1,70 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provision"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/templates"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const defaultProvisionNamespaceTemplate = "{{repo_name}}-pipelines"
+
+// the characters git providers allow in the repository names which are not
+// valid in a namespace name, like the dots and the underscores
+var invalidNamespaceChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// provisionNamespaceName generate the name of the namespace of a provisioned
+// repository from the admin template, sanitized to be a valid namespace name.
+func provisionNamespaceName(nsTemplate, url string) (string, error) {
+	repoOwner, repoName, err := formatting.GetRepoOwnerSplitted(url)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse git repo url: %w", err)
+	}
+	if nsTemplate == "" {
+		nsTemplate = defaultProvisionNamespaceTemplate
+	}
+	maptemplate := map[string]string{
+		// the owner can be a group with subgroups on gitlab
+		"repo_owner": strings.ReplaceAll(repoOwner, "/", "-"),
+		"repo_name":  repoName,
+	}
+	name := strings.ToLower(templates.ReplacePlaceHoldersVariables(nsTemplate, maptemplate))
+	name = invalidNamespaceChars.ReplaceAllString(name, "-")
+	if len(name) > validation.DNS1123LabelMaxLength {
+		name = name[:validation.DNS1123LabelMaxLength]
+	}
+	return strings.Trim(name, "-"), nil
+}
+
+// provisionRepository create a namespace and a Repository for an event of a
+// GitHub App installation not matching any Repository. The payload is
+// validated with the controller webhook secret before creating anything.
+func (p *PacRun) provisionRepository(ctx context.Context) (*v1alpha1.Repository, error) {
+	p.event.Provider.WebhookSecret, _ = GetGitHubAppWebhookSecret(ctx, p.run, p.k8int, p.event)
+	p.event.Provider.PreviousWebhookSecret = GetGitHubAppPreviousWebhookSecret(ctx, p.run, p.k8int, p.event)
+	if err := p.validatePayload(ctx); err != nil {
+		return nil, fmt.Errorf("could not validate payload, check your webhook secret?: %w", err)
+	}
+
+	name, err := provisionNamespaceName(p.run.Info.Pac.AutoProvisionNamespaceTemplate, p.event.URL)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := provision.Create(ctx, p.run.Clients.Kube, p.run.Clients.PipelineAsCode, name, p.event.URL, provision.Opts{
+		Quota:              p.run.Info.Pac.AutoProvisionQuota,
+		RepositoryTemplate: p.run.Info.Pac.AutoProvisionRepositoryTemplate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryProvisioned",
+		fmt.Sprintf("provisioned repository %s/%s for %s", repo.GetNamespace(), repo.GetName(), p.event.URL))
+	return repo, nil
+}
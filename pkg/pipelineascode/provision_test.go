@@ -0,0 +1,63 @@
+package pipelineascode
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestProvisionNamespaceName(t *testing.T) {
+	tests := []struct {
+		name       string
+		nsTemplate string
+		url        string
+		want       string
+		wantErr    string
+	}{
+		{
+			name: "default template",
+			url:  "https://github.com/Gaston/Lagaffe",
+			want: "lagaffe-pipelines",
+		},
+		{
+			name:       "owner and name",
+			nsTemplate: "{{repo_owner}}-{{repo_name}}",
+			url:        "https://github.com/gaston/lagaffe",
+			want:       "gaston-lagaffe",
+		},
+		{
+			name:       "owner with subgroups",
+			nsTemplate: "{{repo_owner}}-{{repo_name}}-ci",
+			url:        "https://gitlab.com/group/subgroup/lagaffe",
+			want:       "group-subgroup-lagaffe-ci",
+		},
+		{
+			name:       "dots and underscores",
+			nsTemplate: "{{repo_owner}}-{{repo_name}}",
+			url:        "https://github.com/Gaston_Lagaffe/lagaffe.github.io",
+			want:       "gaston-lagaffe-lagaffe-github-io",
+		},
+		{
+			name: "too long",
+			url:  "https://github.com/gaston/" + strings.Repeat("a", 60),
+			want: strings.Repeat("a", 60) + "-pi",
+		},
+		{
+			name:    "bad url",
+			url:     "https://github.com/lagaffe",
+			wantErr: "failed to parse git repo url",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := provisionNamespaceName(tt.nsTemplate, tt.url)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
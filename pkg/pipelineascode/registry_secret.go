@@ -0,0 +1,58 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// createRegistrySecret asks the broker to mint the registry credentials of
+// the PipelineRun, store them in a secret and add it to the image pull secrets
+// of the pod template of the PipelineRun, the ServiceAccount it runs with may
+// be shared with other PipelineRuns. The name of the secret is kept in an
+// annotation so the reconciler can delete it once the PipelineRun is done.
+func (p *PacRun) createRegistrySecret(ctx context.Context, match matcher.Match) (string, error) {
+	ns := match.Repo.GetNamespace()
+	// the broker scopes the credentials to the ServiceAccount the PipelineRun
+	// runs with, the default one of Tekton when it doesn't specify one
+	serviceAccount := match.PipelineRun.Spec.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = p.k8int.GetDefaultServiceAccount(ctx)
+	}
+
+	secretName := secrets.GenerateRegistrySecretName()
+	secret, err := secrets.MakeRegistrySecret(ctx, p.run.Clients.HTTP, p.run.Info.Pac.RegistryCredentialsBrokerURL,
+		p.event, match.Repo, serviceAccount, secretName)
+	if err != nil {
+		return "", fmt.Errorf("minting the registry credentials of pipelinerun %s has failed: %w", match.PipelineRun.GetGenerateName(), err)
+	}
+
+	if err := p.k8int.CreateSecret(ctx, ns, secret); err != nil {
+		return "", fmt.Errorf("creating registry secret: %s has failed: %w ", secretName, err)
+	}
+
+	if match.PipelineRun.Spec.PodTemplate == nil {
+		match.PipelineRun.Spec.PodTemplate = &pod.PodTemplate{}
+	}
+	match.PipelineRun.Spec.PodTemplate.ImagePullSecrets = append(match.PipelineRun.Spec.PodTemplate.ImagePullSecrets,
+		corev1.LocalObjectReference{Name: secretName})
+	match.PipelineRun.Annotations[keys.RegistrySecret] = secretName
+	return secretName, nil
+}
+
+// deleteRegistrySecret deletes the registry secret, the errors are only
+// reported as events
+func (p *PacRun) deleteRegistrySecret(ctx context.Context, repo *v1alpha1.Repository, secretName string) {
+	ns := repo.GetNamespace()
+	if err := p.k8int.DeleteSecret(ctx, p.logger, ns, secretName); err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryRegistrySecret",
+			fmt.Sprintf("cannot delete registry secret %s/%s: %s", ns, secretName, err.Error()))
+	}
+}
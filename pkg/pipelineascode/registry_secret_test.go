@@ -0,0 +1,139 @@
+package pipelineascode
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCreateRegistrySecret(t *testing.T) {
+	var brokerServiceAccount string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mint", func(rw http.ResponseWriter, r *http.Request) {
+		request := secrets.RegistryCredentialsRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		brokerServiceAccount = request.ServiceAccount
+		fmt.Fprint(rw, `{"auths": {"registry.io": {"auth": "dXNlcjp0b2tlbg=="}}}`)
+	})
+	mux.HandleFunc("/denied", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusForbidden)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ns := "ns"
+	tests := []struct {
+		name            string
+		path            string
+		serviceAccount  string
+		podTemplate     *pod.PodTemplate
+		tektonDefaultSA string
+		wantSA          string
+		wantPullSecrets []string
+		wantErr         string
+	}{
+		{
+			name:   "default service account",
+			path:   "/mint",
+			wantSA: "default",
+		},
+		{
+			name:            "default service account of tekton",
+			path:            "/mint",
+			tektonDefaultSA: "pipeline",
+			wantSA:          "pipeline",
+		},
+		{
+			name:           "service account of the pipelinerun",
+			path:           "/mint",
+			serviceAccount: "build",
+			wantSA:         "build",
+		},
+		{
+			name:            "image pull secrets of the pipelinerun kept",
+			path:            "/mint",
+			podTemplate:     &pod.PodTemplate{ImagePullSecrets: []corev1.LocalObjectReference{{Name: "mine"}}},
+			wantSA:          "default",
+			wantPullSecrets: []string{"mine"},
+		},
+		{
+			name:    "broker denied",
+			path:    "/denied",
+			wantErr: "minting the registry credentials of pipelinerun pr- has failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			ctx, _ := rtesting.SetupFakeContext(t)
+			tdata := testclient.Data{}
+			if tt.tektonDefaultSA != "" {
+				tdata.ConfigMap = []*corev1.ConfigMap{{
+					ObjectMeta: metav1.ObjectMeta{Name: "config-defaults", Namespace: "tekton-pipelines"},
+					Data:       map[string]string{"default-service-account": tt.tektonDefaultSA},
+				}}
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+			cs := &params.Run{
+				Clients: clients.Clients{Log: logger, Kube: stdata.Kube, HTTP: *server.Client()},
+				Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{
+					RegistryCredentialsBrokerURL: server.URL + tt.path,
+				}}},
+			}
+			kint := &kubeinteraction.Interaction{Run: cs}
+			p := NewPacs(&info.Event{URL: "https://forge/owner/repo", SHA: "sha"}, &testprovider.TestProviderImp{}, cs, kint, logger)
+			match := matcher.Match{
+				Repo: &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: ns}},
+				PipelineRun: &v1beta1.PipelineRun{
+					ObjectMeta: metav1.ObjectMeta{GenerateName: "pr-", Annotations: map[string]string{}},
+					Spec:       v1beta1.PipelineRunSpec{ServiceAccountName: tt.serviceAccount, PodTemplate: tt.podTemplate},
+				},
+			}
+
+			brokerServiceAccount = ""
+			secretName, err := p.createRegistrySecret(ctx, match)
+			secretList, lerr := stdata.Kube.CoreV1().Secrets(ns).List(ctx, metav1.ListOptions{})
+			assert.NilError(t, lerr)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Equal(t, len(secretList.Items), 0)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, len(secretList.Items), 1)
+			assert.Equal(t, match.PipelineRun.GetAnnotations()[keys.RegistrySecret], secretName)
+			assert.Equal(t, brokerServiceAccount, tt.wantSA)
+			// Tekton keeps resolving the ServiceAccount of the PipelineRun
+			assert.Equal(t, match.PipelineRun.Spec.ServiceAccountName, tt.serviceAccount)
+
+			pullSecrets := []corev1.LocalObjectReference{}
+			for _, name := range append(tt.wantPullSecrets, secretName) {
+				pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: name})
+			}
+			assert.DeepEqual(t, match.PipelineRun.Spec.PodTemplate.ImagePullSecrets, pullSecrets)
+		})
+	}
+}
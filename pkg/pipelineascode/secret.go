@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
 	ktypes "github.com/openshift-pipelines/pipelines-as-code/pkg/secrets/types"
 	"go.uber.org/zap"
 )
@@ -19,8 +22,47 @@ const (
 	DefaultGitProviderWebhookSecretKey           = "webhook.secret"
 	DefaultPipelinesAscodeSecretName             = "pipelines-as-code-secret"
 	defaultPipelinesAscodeSecretWebhookSecretKey = "webhook.secret"
+
+	// the suffixes of the keys of the webhook secret before its rotation and
+	// of the time until when it's accepted
+	PreviousWebhookSecretSuffix       = ".previous"
+	PreviousWebhookSecretExpirySuffix = ".previous-expiry"
 )
 
+// previousWebhookSecret returns the webhook secret before its rotation, kept
+// in the <key>.previous key of the secret with the RFC3339 time until when the
+// payloads signed with it are accepted in the <key>.previous-expiry key. It
+// returns an empty string once it has expired or without an expiry.
+func previousWebhookSecret(fetch func(key string) string, key string, now time.Time) string {
+	previous := strings.TrimSpace(fetch(key + PreviousWebhookSecretSuffix))
+	if previous == "" {
+		return ""
+	}
+	expiry, err := time.Parse(time.RFC3339, strings.TrimSpace(fetch(key+PreviousWebhookSecretExpirySuffix)))
+	if err != nil || now.After(expiry) {
+		return ""
+	}
+	return previous
+}
+
+// validatePayload validate the payload of the event with the webhook secret,
+// or with the previous webhook secret while it has not expired so the
+// webhooks still signing with it during a rotation are not refused.
+func (p *PacRun) validatePayload(ctx context.Context) error {
+	err := p.vcx.Validate(ctx, p.run, p.event)
+	if err == nil || p.event.Provider.PreviousWebhookSecret == "" {
+		return err
+	}
+	current := p.event.Provider.WebhookSecret
+	p.event.Provider.WebhookSecret = p.event.Provider.PreviousWebhookSecret
+	defer func() { p.event.Provider.WebhookSecret = current }()
+	if perr := p.vcx.Validate(ctx, p.run, p.event); perr != nil {
+		return err
+	}
+	p.logger.Infof("the payload has been validated with the previous webhook secret, update the webhook of %s with the new one before it expires", p.event.URL)
+	return nil
+}
+
 // SecretFromRepository grab the secret from the repository CRD
 func SecretFromRepository(ctx context.Context, cs *params.Run, k8int kubeinteraction.Interface, config *info.ProviderConfig, event *info.Event, repo *apipac.Repository, logger *zap.SugaredLogger) error {
 	var err error
@@ -41,11 +83,8 @@ func SecretFromRepository(ctx context.Context, cs *params.Run, k8int kubeinterac
 		gitProviderSecretKey = DefaultGitProviderSecretKey
 	}
 
-	if event.Provider.Token, err = k8int.GetSecret(ctx, ktypes.GetSecretOpt{
-		Namespace: repo.GetNamespace(),
-		Name:      repo.Spec.GitProvider.Secret.Name,
-		Key:       gitProviderSecretKey,
-	}); err != nil {
+	if event.Provider.Token, err = secrets.GetRepositorySecret(ctx, cs, k8int, repo.GetNamespace(),
+		repo.Spec.GitProvider.Secret, gitProviderSecretKey); err != nil {
 		return err
 	}
 
@@ -72,13 +111,17 @@ func SecretFromRepository(ctx context.Context, cs *params.Run, k8int kubeinterac
 		repo.Spec.GitProvider.User,
 		repo.Spec.GitProvider.Secret.Name,
 		gitProviderSecretKey)
-	if event.Provider.WebhookSecret, err = k8int.GetSecret(ctx, ktypes.GetSecretOpt{
-		Namespace: repo.GetNamespace(),
-		Name:      repo.Spec.GitProvider.WebhookSecret.Name,
-		Key:       gitProviderWebhookSecretKey,
-	}); err != nil {
+	if event.Provider.WebhookSecret, err = secrets.GetRepositorySecret(ctx, cs, k8int, repo.GetNamespace(),
+		repo.Spec.GitProvider.WebhookSecret, gitProviderWebhookSecretKey); err != nil {
 		return err
 	}
+	event.Provider.PreviousWebhookSecret = previousWebhookSecret(func(key string) string {
+		ref := *repo.Spec.GitProvider.WebhookSecret
+		ref.Key = key
+		// the previous secret is optional, the fetchers may fail without it
+		value, _ := secrets.GetRepositorySecret(ctx, cs, k8int, repo.GetNamespace(), &ref, key)
+		return value
+	}, gitProviderWebhookSecretKey, time.Now())
 	if event.Provider.WebhookSecret != "" {
 		event.Provider.WebhookSecretFromRepo = true
 		logmsg += fmt.Sprintf(" webhook-secret=%s webhook-key=%s",
@@ -91,11 +134,22 @@ func SecretFromRepository(ctx context.Context, cs *params.Run, k8int kubeinterac
 	return nil
 }
 
-// GetCurrentNSWebhookSecret get secret from current namespace if it exists
-func GetCurrentNSWebhookSecret(ctx context.Context, k8int kubeinteraction.Interface) (string, error) {
+// gitHubAppSecretName returns the name of the secret of the GitHub App which
+// has sent the event, when the controller has multiple apps.
+func gitHubAppSecretName(ctx context.Context, cs *params.Run, event *info.Event) string {
+	if app, err := github.GetAppCredentials(ctx, cs.Clients.Kube, event.GitHubAppID, event.GHEURL); err == nil {
+		return app.SecretName
+	}
+	return DefaultPipelinesAscodeSecretName
+}
+
+// GetGitHubAppWebhookSecret get the webhook secret of the GitHub App which has
+// sent the event, from the secret of the current namespace with its
+// credentials when the controller has multiple apps.
+func GetGitHubAppWebhookSecret(ctx context.Context, cs *params.Run, k8int kubeinteraction.Interface, event *info.Event) (string, error) {
 	s, err := k8int.GetSecret(ctx, ktypes.GetSecretOpt{
 		Namespace: os.Getenv("SYSTEM_NAMESPACE"),
-		Name:      DefaultPipelinesAscodeSecretName,
+		Name:      gitHubAppSecretName(ctx, cs, event),
 		Key:       defaultPipelinesAscodeSecretWebhookSecretKey,
 	})
 	// a lot of people have problem with this secret, when encoding it to base64 which add a \n when we do :
@@ -103,3 +157,17 @@ func GetCurrentNSWebhookSecret(ctx context.Context, k8int kubeinteraction.Interf
 	// so cleanup, if someone wants to have a \n or a space in the secret, well then they can't :p
 	return strings.TrimSpace(s), err
 }
+
+// GetGitHubAppPreviousWebhookSecret get the webhook secret of the GitHub App
+// before its rotation, while it has not expired.
+func GetGitHubAppPreviousWebhookSecret(ctx context.Context, cs *params.Run, k8int kubeinteraction.Interface, event *info.Event) string {
+	name := gitHubAppSecretName(ctx, cs, event)
+	return previousWebhookSecret(func(key string) string {
+		value, _ := k8int.GetSecret(ctx, ktypes.GetSecretOpt{
+			Namespace: os.Getenv("SYSTEM_NAMESPACE"),
+			Name:      name,
+			Key:       key,
+		})
+		return value
+	}, defaultPipelinesAscodeSecretWebhookSecretKey, time.Now())
+}
@@ -4,15 +4,21 @@ import (
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
 	kitesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
 	"go.uber.org/zap"
 	zapobserver "go.uber.org/zap/zaptest/observer"
 	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
@@ -127,3 +133,149 @@ func TestSecretFromRepository(t *testing.T) {
 		})
 	}
 }
+
+func TestGetGitHubAppWebhookSecret(t *testing.T) {
+	appSecret := func(name, appID, host string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "pac"},
+			Data: map[string][]byte{
+				"github-application-id":  []byte(appID),
+				"github-enterprise-host": []byte(host),
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		secrets []*corev1.Secret
+		event   *info.Event
+		want    string
+	}{
+		{
+			name:    "single app",
+			secrets: []*corev1.Secret{appSecret(DefaultPipelinesAscodeSecretName, "1", "")},
+			event:   &info.Event{GitHubAppID: 1},
+			want:    "default-secret",
+		},
+		{
+			name: "app of the event",
+			secrets: []*corev1.Secret{
+				appSecret(DefaultPipelinesAscodeSecretName, "1", ""),
+				appSecret("ghes-app", "2", "ghe.company.com"),
+			},
+			event: &info.Event{GHEURL: "https://ghe.company.com"},
+			want:  "ghes-secret",
+		},
+		{
+			name:  "no app credentials",
+			event: &info.Event{GitHubAppID: 2},
+			want:  "default-secret",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SYSTEM_NAMESPACE", "pac")
+			t.Setenv(github.AppSecretsEnv, "ghes-app")
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{Secret: tt.secrets})
+			cs := &params.Run{Clients: clients.Clients{Kube: stdata.Kube}}
+			k8int := &kitesthelper.KinterfaceTest{
+				GetSecretResult: map[string]string{
+					DefaultPipelinesAscodeSecretName: "default-secret\n",
+					"ghes-app":                       "ghes-secret",
+				},
+			}
+			got, err := GetGitHubAppWebhookSecret(ctx, cs, k8int, tt.event)
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestPreviousWebhookSecret(t *testing.T) {
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		data map[string]string
+		want string
+	}{
+		{
+			name: "not expired",
+			data: map[string]string{
+				"webhook.secret.previous":        "old\n",
+				"webhook.secret.previous-expiry": "2023-01-02T04:04:05Z",
+			},
+			want: "old",
+		},
+		{
+			name: "expired",
+			data: map[string]string{
+				"webhook.secret.previous":        "old",
+				"webhook.secret.previous-expiry": "2023-01-02T02:04:05Z",
+			},
+		},
+		{
+			name: "without expiry",
+			data: map[string]string{"webhook.secret.previous": "old"},
+		},
+		{
+			name: "no previous secret",
+			data: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := previousWebhookSecret(func(key string) string { return tt.data[key] }, "webhook.secret", now)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestValidatePayload(t *testing.T) {
+	tests := []struct {
+		name           string
+		webhookSecret  string
+		previousSecret string
+		wantErr        bool
+	}{
+		{
+			name:          "current secret",
+			webhookSecret: "new",
+		},
+		{
+			name:           "previous secret",
+			webhookSecret:  "other",
+			previousSecret: "new",
+		},
+		{
+			name:          "no previous secret",
+			webhookSecret: "other",
+			wantErr:       true,
+		},
+		{
+			name:           "both invalid",
+			webhookSecret:  "other",
+			previousSecret: "another",
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			ctx, _ := rtesting.SetupFakeContext(t)
+			cs := &params.Run{Clients: clients.Clients{Log: logger}}
+			event := &info.Event{
+				URL:      "https://forge/owner/repo",
+				Provider: &info.Provider{WebhookSecret: tt.webhookSecret, PreviousWebhookSecret: tt.previousSecret},
+			}
+			pac := NewPacs(event, &testprovider.TestProviderImp{ValidWebhookSecret: "new"}, cs, nil, logger)
+			err := pac.validatePayload(ctx)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "payload signature check failed")
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.Equal(t, event.Provider.WebhookSecret, tt.webhookSecret)
+		})
+	}
+}
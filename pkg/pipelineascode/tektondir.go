@@ -0,0 +1,74 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"go.uber.org/zap"
+)
+
+const (
+	// how the tekton directory of a Repository is fetched
+	TektonDirFetchAPI         = "api"
+	TektonDirFetchGit         = "git"
+	TektonDirFetchGitFallback = "git_fallback"
+)
+
+// fetchTektonDir is replaced in the tests to not run git
+var fetchTektonDir = git.FetchTektonDir
+
+func tektonDirFetch(repo *v1alpha1.Repository) string {
+	if repo.Spec.Settings == nil || repo.Spec.Settings.TektonDirFetch == "" {
+		return TektonDirFetchAPI
+	}
+	return repo.Spec.Settings.TektonDirFetch
+}
+
+// gitFetchTektonDir fetch the tekton directory with a shallow and sparse git
+// fetch of the revision of the event, with the token of the provider.
+func gitFetchTektonDir(ctx context.Context, event *info.Event, dir string) (string, error) {
+	cloneURL := event.URL
+	if event.CloneURL != "" {
+		cloneURL = event.CloneURL
+	}
+	revision := event.SHA
+	if revision == "" {
+		revision = event.HeadBranch
+	}
+	opts := git.SparseFetchOpts{
+		CloneURL: cloneURL,
+		Revision: revision,
+		Path:     dir,
+		User:     provider.DefaultProviderAPIUser,
+	}
+	if event.Provider != nil {
+		opts.Token = event.Provider.Token
+		if event.Provider.User != "" {
+			opts.User = event.Provider.User
+		}
+	}
+	return fetchTektonDir(ctx, opts)
+}
+
+// getTektonDir returns the tekton directory of the event from the content API
+// of the provider or with git, according to the settings of the Repository.
+func (p *PacRun) getTektonDir(ctx context.Context, repo *v1alpha1.Repository, event *info.Event, dir string) (string, error) {
+	switch tektonDirFetch(repo) {
+	case TektonDirFetchGit:
+		return gitFetchTektonDir(ctx, event, dir)
+	case TektonDirFetchGitFallback:
+		rawTemplates, err := p.vcx.GetTektonDir(ctx, event, dir)
+		if err == nil {
+			return rawTemplates, nil
+		}
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryTektonDirGitFallback",
+			fmt.Sprintf("cannot get the %s directory from the git provider API, fetching it with git: %s", dir, err.Error()))
+		return gitFetchTektonDir(ctx, event, dir)
+	default:
+		return p.vcx.GetTektonDir(ctx, event, dir)
+	}
+}
@@ -0,0 +1,107 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestGetTektonDir(t *testing.T) {
+	defer func() { fetchTektonDir = git.FetchTektonDir }()
+	tests := []struct {
+		name           string
+		tektonDirFetch string
+		apiErroring    bool
+		gitErroring    bool
+		want           string
+		wantErr        string
+		wantFetchOpts  *git.SparseFetchOpts
+	}{
+		{
+			name: "content api by default",
+			want: "from api",
+		},
+		{
+			name:           "git",
+			tektonDirFetch: TektonDirFetchGit,
+			want:           "from git",
+			wantFetchOpts: &git.SparseFetchOpts{
+				CloneURL: "https://forge/owner/repo.git",
+				Revision: "abcdef",
+				Path:     "sub/.tekton",
+				User:     "git",
+				Token:    "token",
+			},
+		},
+		{
+			name:           "git fallback with the content api working",
+			tektonDirFetch: TektonDirFetchGitFallback,
+			want:           "from api",
+		},
+		{
+			name:           "git fallback with the content api failing",
+			tektonDirFetch: TektonDirFetchGitFallback,
+			apiErroring:    true,
+			want:           "from git",
+		},
+		{
+			name:           "git failing",
+			tektonDirFetch: TektonDirFetchGit,
+			gitErroring:    true,
+			wantErr:        "cannot fetch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetchOpts *git.SparseFetchOpts
+			fetchTektonDir = func(ctx context.Context, opts git.SparseFetchOpts) (string, error) {
+				fetchOpts = &opts
+				if tt.gitErroring {
+					return "", fmt.Errorf("cannot fetch")
+				}
+				return "from git", nil
+			}
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+			cs := &params.Run{Clients: clients.Clients{Log: logger, Kube: stdata.Kube, Tekton: stdata.Pipeline}}
+			vcx := &testprovider.TestProviderImp{TektonDirTemplate: "from api", TektonDirErroring: tt.apiErroring}
+			event := &info.Event{
+				URL:      "https://forge/owner/repo",
+				CloneURL: "https://forge/owner/repo.git",
+				SHA:      "abcdef",
+				Provider: &info.Provider{Token: "token"},
+			}
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec:       v1alpha1.RepositorySpec{Settings: &v1alpha1.Settings{TektonDirFetch: tt.tektonDirFetch}},
+			}
+			pac := NewPacs(event, vcx, cs, nil, logger)
+
+			got, err := pac.getTektonDir(ctx, repo, event, "sub/.tekton")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+			if tt.wantFetchOpts != nil {
+				assert.DeepEqual(t, fetchOpts, tt.wantFetchOpts)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package policy
+
+import (
+	"context"
+	"sort"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/generated/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ProvenanceSource        = "source"
+	ProvenanceDefaultBranch = "default_branch"
+
+	// what to do with the changes of the tekton directory of a pull request
+	// from a fork
+	ForkTektonChangesRun           = "run"
+	ForkTektonChangesApproval      = "approval"
+	ForkTektonChangesDefaultBranch = "default_branch"
+
+	// the settings of a policy which can be enforced
+	ConcurrencyLimit      = "concurrency_limit"
+	PipelineRunProvenance = "pipelinerun_provenance"
+	ForkTektonChanges     = "fork_tekton_changes"
+	AutoOkToTest          = "auto_ok_to_test"
+	AllowedTaskSources    = "allowed_task_sources"
+	Params                = "params"
+	ProtectedBranchParams = "protected_branch_params"
+)
+
+// List return the RepositoryPolicies of the cluster sorted by name, an empty
+// list when the RepositoryPolicy CRD is not installed.
+func List(ctx context.Context, pac versioned.Interface) ([]v1alpha1.RepositoryPolicy, error) {
+	list, err := pac.PipelinesascodeV1alpha1().RepositoryPolicies().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return []v1alpha1.RepositoryPolicy{}, nil
+		}
+		return nil, err
+	}
+	policies := list.Items
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].GetName() < policies[j].GetName()
+	})
+	return policies, nil
+}
+
+// ForNamespace return the policies applying to the Repositories of a
+// namespace.
+func ForNamespace(policies []v1alpha1.RepositoryPolicy, namespace string) []v1alpha1.RepositoryPolicy {
+	filtered := []v1alpha1.RepositoryPolicy{}
+	for _, policy := range policies {
+		if len(policy.Spec.Namespaces) == 0 {
+			filtered = append(filtered, policy)
+			continue
+		}
+		for _, ns := range policy.Spec.Namespaces {
+			if ns == namespace {
+				filtered = append(filtered, policy)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// Merge merge the policies in order, the settings of the later policies
+// override the earlier ones and the enforced settings add up.
+func Merge(policies []v1alpha1.RepositoryPolicy) v1alpha1.RepositoryPolicySpec {
+	merged := v1alpha1.RepositoryPolicySpec{Params: map[string]string{}, ProtectedBranchParams: map[string]string{}}
+	enforced := map[string]bool{}
+	for _, policy := range policies {
+		spec := policy.Spec
+		if spec.ConcurrencyLimit != nil {
+			limit := *spec.ConcurrencyLimit
+			merged.ConcurrencyLimit = &limit
+		}
+		if spec.PipelineRunProvenance != "" {
+			merged.PipelineRunProvenance = spec.PipelineRunProvenance
+		}
+		if spec.ForkTektonChanges != "" {
+			merged.ForkTektonChanges = spec.ForkTektonChanges
+		}
+		if spec.AutoOkToTest != nil {
+			merged.AutoOkToTest = spec.AutoOkToTest.DeepCopy()
+		}
+		if len(spec.AllowedTaskSources) > 0 {
+			merged.AllowedTaskSources = append([]string{}, spec.AllowedTaskSources...)
+		}
+		for k, v := range spec.Params {
+			merged.Params[k] = v
+		}
+		for k, v := range spec.ProtectedBranchParams {
+			merged.ProtectedBranchParams[k] = v
+		}
+		if spec.DefaultMaxKeepRuns != nil {
+			runs := *spec.DefaultMaxKeepRuns
+			merged.DefaultMaxKeepRuns = &runs
+		}
+		if spec.MaxKeepRunUpperLimit != nil {
+			limit := *spec.MaxKeepRunUpperLimit
+			merged.MaxKeepRunUpperLimit = &limit
+		}
+		for _, setting := range spec.Enforced {
+			if !enforced[setting] {
+				enforced[setting] = true
+				merged.Enforced = append(merged.Enforced, setting)
+			}
+		}
+	}
+	return merged
+}
+
+// Apply return a copy of the Repository with the settings inherited from the
+// policies, the settings set on the Repository win unless they are enforced
+// by a policy. Only the policies of the namespace of the Repository are applied.
+func Apply(repo *v1alpha1.Repository, policies []v1alpha1.RepositoryPolicy) *v1alpha1.Repository {
+	merged := Merge(ForNamespace(policies, repo.GetNamespace()))
+	enforced := map[string]bool{}
+	for _, setting := range merged.Enforced {
+		enforced[setting] = true
+	}
+
+	nrepo := repo.DeepCopy()
+	if merged.ConcurrencyLimit != nil && (nrepo.Spec.ConcurrencyLimit == nil || enforced[ConcurrencyLimit]) {
+		nrepo.Spec.ConcurrencyLimit = merged.ConcurrencyLimit
+	}
+	if merged.PipelineRunProvenance != "" && (nrepo.Spec.PipelineRunProvenance == "" || enforced[PipelineRunProvenance]) {
+		nrepo.Spec.PipelineRunProvenance = merged.PipelineRunProvenance
+	}
+	if merged.ForkTektonChanges != "" && (nrepo.Spec.ForkTektonChanges == "" || enforced[ForkTektonChanges]) {
+		nrepo.Spec.ForkTektonChanges = merged.ForkTektonChanges
+	}
+	if merged.AutoOkToTest != nil && (nrepo.Spec.AutoOkToTest == nil || enforced[AutoOkToTest]) {
+		nrepo.Spec.AutoOkToTest = merged.AutoOkToTest
+	}
+	if len(merged.AllowedTaskSources) > 0 && (len(nrepo.Spec.AllowedTaskSources) == 0 || enforced[AllowedTaskSources]) {
+		nrepo.Spec.AllowedTaskSources = merged.AllowedTaskSources
+	}
+	if len(merged.Params) > 0 {
+		nrepo.Spec.Params = mergeParams(merged.Params, nrepo.Spec.Params, enforced[Params])
+	}
+	if len(merged.ProtectedBranchParams) > 0 {
+		nrepo.Spec.ProtectedBranchParams = mergeParams(merged.ProtectedBranchParams, nrepo.Spec.ProtectedBranchParams,
+			enforced[ProtectedBranchParams])
+	}
+	return nrepo
+}
+
+// mergeParams merge the params of the repository over the params of the
+// policies, the repository can still add its own params when they are
+// enforced.
+func mergeParams(params, repoParams map[string]string, enforced bool) map[string]string {
+	for k, v := range repoParams {
+		if _, ok := params[k]; ok && enforced {
+			continue
+		}
+		params[k] = v
+	}
+	return params
+}
+
+// ApplyFromCluster apply the RepositoryPolicies of the cluster to the
+// Repository.
+func ApplyFromCluster(ctx context.Context, pac versioned.Interface, repo *v1alpha1.Repository) (*v1alpha1.Repository, error) {
+	policies, err := List(ctx, pac)
+	if err != nil {
+		return repo, err
+	}
+	return Apply(repo, policies), nil
+}
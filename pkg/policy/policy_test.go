@@ -0,0 +1,179 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func newPolicy(name string, spec v1alpha1.RepositoryPolicySpec) v1alpha1.RepositoryPolicy {
+	return v1alpha1.RepositoryPolicy{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: spec}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     v1alpha1.RepositorySpec
+		policies []v1alpha1.RepositoryPolicy
+		want     v1alpha1.RepositorySpec
+	}{
+		{
+			name: "no policies",
+			spec: v1alpha1.RepositorySpec{ConcurrencyLimit: intPtr(2)},
+			want: v1alpha1.RepositorySpec{ConcurrencyLimit: intPtr(2)},
+		},
+		{
+			name: "inherit the defaults",
+			policies: []v1alpha1.RepositoryPolicy{newPolicy("org", v1alpha1.RepositoryPolicySpec{
+				ConcurrencyLimit:      intPtr(3),
+				PipelineRunProvenance: ProvenanceDefaultBranch,
+				ForkTektonChanges:     ForkTektonChangesDefaultBranch,
+				AllowedTaskSources:    []string{"https://github.com/org/**"},
+				Params:                map[string]string{"registry": "quay.io/org"},
+			})},
+			want: v1alpha1.RepositorySpec{
+				ConcurrencyLimit:      intPtr(3),
+				PipelineRunProvenance: ProvenanceDefaultBranch,
+				ForkTektonChanges:     ForkTektonChangesDefaultBranch,
+				AllowedTaskSources:    []string{"https://github.com/org/**"},
+				Params:                map[string]string{"registry": "quay.io/org"},
+			},
+		},
+		{
+			name: "repository override the defaults",
+			spec: v1alpha1.RepositorySpec{
+				ConcurrencyLimit:      intPtr(1),
+				PipelineRunProvenance: ProvenanceSource,
+				AllowedTaskSources:    []string{"git-clone"},
+				Params:                map[string]string{"registry": "quay.io/me", "other": "value"},
+			},
+			policies: []v1alpha1.RepositoryPolicy{newPolicy("org", v1alpha1.RepositoryPolicySpec{
+				ConcurrencyLimit:      intPtr(3),
+				PipelineRunProvenance: ProvenanceDefaultBranch,
+				AllowedTaskSources:    []string{"https://github.com/org/**"},
+				Params:                map[string]string{"registry": "quay.io/org"},
+			})},
+			want: v1alpha1.RepositorySpec{
+				ConcurrencyLimit:      intPtr(1),
+				PipelineRunProvenance: ProvenanceSource,
+				AllowedTaskSources:    []string{"git-clone"},
+				Params:                map[string]string{"registry": "quay.io/me", "other": "value"},
+			},
+		},
+		{
+			name: "enforced settings cannot be overridden",
+			spec: v1alpha1.RepositorySpec{
+				ConcurrencyLimit:      intPtr(10),
+				PipelineRunProvenance: ProvenanceSource,
+				ForkTektonChanges:     ForkTektonChangesRun,
+				AllowedTaskSources:    []string{"**"},
+				Params:                map[string]string{"registry": "quay.io/me", "other": "value"},
+			},
+			policies: []v1alpha1.RepositoryPolicy{newPolicy("org", v1alpha1.RepositoryPolicySpec{
+				ConcurrencyLimit:      intPtr(3),
+				PipelineRunProvenance: ProvenanceDefaultBranch,
+				ForkTektonChanges:     ForkTektonChangesApproval,
+				AllowedTaskSources:    []string{"https://github.com/org/**"},
+				Params:                map[string]string{"registry": "quay.io/org"},
+				Enforced:              []string{ConcurrencyLimit, PipelineRunProvenance, ForkTektonChanges, AllowedTaskSources, Params},
+			})},
+			want: v1alpha1.RepositorySpec{
+				ConcurrencyLimit:      intPtr(3),
+				PipelineRunProvenance: ProvenanceDefaultBranch,
+				ForkTektonChanges:     ForkTektonChangesApproval,
+				AllowedTaskSources:    []string{"https://github.com/org/**"},
+				Params:                map[string]string{"registry": "quay.io/org", "other": "value"},
+			},
+		},
+		{
+			name: "auto ok-to-test",
+			spec: v1alpha1.RepositorySpec{
+				AutoOkToTest: &v1alpha1.AutoOkToTest{MergedPullRequests: 1},
+			},
+			policies: []v1alpha1.RepositoryPolicy{newPolicy("org", v1alpha1.RepositoryPolicySpec{
+				AutoOkToTest: &v1alpha1.AutoOkToTest{Teams: []string{"contributors"}, CodeOwners: true},
+				Enforced:     []string{AutoOkToTest},
+			})},
+			want: v1alpha1.RepositorySpec{
+				AutoOkToTest: &v1alpha1.AutoOkToTest{Teams: []string{"contributors"}, CodeOwners: true},
+			},
+		},
+		{
+			name: "protected branch params",
+			spec: v1alpha1.RepositorySpec{
+				ProtectedBranchParams: map[string]string{"signed": "false", "other": "value"},
+			},
+			policies: []v1alpha1.RepositoryPolicy{newPolicy("org", v1alpha1.RepositoryPolicySpec{
+				ProtectedBranchParams: map[string]string{"signed": "true"},
+				Enforced:              []string{ProtectedBranchParams},
+			})},
+			want: v1alpha1.RepositorySpec{
+				ProtectedBranchParams: map[string]string{"signed": "true", "other": "value"},
+			},
+		},
+		{
+			name: "later policies override the earlier ones",
+			spec: v1alpha1.RepositorySpec{ConcurrencyLimit: intPtr(10)},
+			policies: []v1alpha1.RepositoryPolicy{
+				newPolicy("a", v1alpha1.RepositoryPolicySpec{
+					ConcurrencyLimit: intPtr(3),
+					Params:           map[string]string{"registry": "quay.io/a", "a": "a"},
+				}),
+				newPolicy("b", v1alpha1.RepositoryPolicySpec{
+					ConcurrencyLimit: intPtr(5),
+					Params:           map[string]string{"registry": "quay.io/b"},
+					Enforced:         []string{ConcurrencyLimit},
+				}),
+			},
+			want: v1alpha1.RepositorySpec{
+				ConcurrencyLimit: intPtr(5),
+				Params:           map[string]string{"registry": "quay.io/b", "a": "a"},
+			},
+		},
+		{
+			name: "only the policies of the namespace",
+			policies: []v1alpha1.RepositoryPolicy{
+				newPolicy("a", v1alpha1.RepositoryPolicySpec{
+					ConcurrencyLimit: intPtr(3),
+					Namespaces:       []string{"ns"},
+				}),
+				newPolicy("b", v1alpha1.RepositoryPolicySpec{
+					ConcurrencyLimit: intPtr(5),
+					Namespaces:       []string{"other"},
+				}),
+			},
+			want: v1alpha1.RepositorySpec{ConcurrencyLimit: intPtr(3)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}, Spec: tt.spec}
+			got := Apply(repo, tt.policies)
+			assert.DeepEqual(t, got.Spec, tt.want)
+		})
+	}
+}
+
+func TestApplyFromCluster(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	b := newPolicy("b", v1alpha1.RepositoryPolicySpec{ConcurrencyLimit: intPtr(2)})
+	a := newPolicy("a", v1alpha1.RepositoryPolicySpec{ConcurrencyLimit: intPtr(1), Params: map[string]string{"a": "a"}})
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{Policies: []*v1alpha1.RepositoryPolicy{&b, &a}})
+
+	repo := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}}
+	got, err := ApplyFromCluster(ctx, stdata.PipelineAsCode, repo)
+	assert.NilError(t, err)
+	// policies are applied by name, b wins over a
+	assert.Equal(t, *got.Spec.ConcurrencyLimit, 2)
+	assert.DeepEqual(t, got.Spec.Params, map[string]string{"a": "a"})
+	// the repository itself is not modified
+	assert.Assert(t, repo.Spec.ConcurrencyLimit == nil)
+}
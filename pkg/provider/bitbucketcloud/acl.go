@@ -2,6 +2,11 @@ package bitbucketcloud
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/ktrysmt/go-bitbucket"
@@ -11,9 +16,9 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud/types"
 )
 
-func (v *Provider) IsAllowed(_ context.Context, event *info.Event) (bool, error) {
-	// Check first if the user is in the owner file or part of the workspace
-	allowed, err := v.checkMember(event)
+func (v *Provider) IsAllowed(ctx context.Context, event *info.Event) (bool, error) {
+	// Check first if the user is in the owner file or can write to the repository
+	allowed, err := v.checkMember(ctx, event)
 	if err != nil {
 		return false, err
 	}
@@ -22,7 +27,33 @@ func (v *Provider) IsAllowed(_ context.Context, event *info.Event) (bool, error)
 	}
 
 	// Check then from comment if there is a approved user that has done a /ok-to-test
-	return v.checkOkToTestCommentFromApprovedMember(event)
+	return v.checkOkToTestCommentFromApprovedMember(ctx, event)
+}
+
+// errPermissionsForbidden is returned when the credentials cannot read the
+// repository permissions of the workspace, it needs an app password with the
+// Repositories Admin permission.
+var errPermissionsForbidden = errors.New("the repository permissions of the workspace cannot be read")
+
+// IsMaintainer checks the sender is an admin of the repository
+func (v *Provider) IsMaintainer(ctx context.Context, event *info.Event) (bool, error) {
+	allowed, err := v.hasPermission(ctx, event, "admin")
+	if errors.Is(err, errPermissionsForbidden) {
+		return false, nil
+	}
+	return allowed, err
+}
+
+// hasWritePermission check from the repository permissions of the workspace
+// that the user can write to the repository, the permissions given through the
+// groups of the workspace are included. When the credentials cannot read
+// the permissions the members of the workspace are allowed instead.
+func (v *Provider) hasWritePermission(ctx context.Context, event *info.Event) (bool, error) {
+	allowed, err := v.hasPermission(ctx, event, "write", "admin")
+	if errors.Is(err, errPermissionsForbidden) {
+		return v.isWorkspaceMember(event)
+	}
+	return allowed, err
 }
 
 func (v *Provider) isWorkspaceMember(event *info.Event) (bool, error) {
@@ -39,9 +70,53 @@ func (v *Provider) isWorkspaceMember(event *info.Event) (bool, error) {
 	return false, nil
 }
 
+// hasPermission check the user has one of the permissions on the repository
+func (v *Provider) hasPermission(ctx context.Context, event *info.Event, allowed ...string) (bool, error) {
+	if event.AccountID == "" {
+		return false, nil
+	}
+	query := url.Values{"q": []string{fmt.Sprintf("user.account_id=\"%s\"", event.AccountID)}}
+	permissionsURL := fmt.Sprintf("%s/workspaces/%s/permissions/repositories/%s?%s",
+		v.Client.GetApiBaseURL(), event.Organization, event.Repository, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, permissionsURL, nil)
+	if err != nil {
+		return false, err
+	}
+	if v.Username != nil && v.Token != nil {
+		req.SetBasicAuth(*v.Username, *v.Token)
+	}
+	resp, err := v.Client.HttpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return false, errPermissionsForbidden
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("cannot get the permissions of %s on %s/%s: %s", event.AccountID, event.Organization, event.Repository, resp.Status)
+	}
+
+	permissions := &types.RepositoryPermissions{}
+	if err := json.NewDecoder(resp.Body).Decode(permissions); err != nil {
+		return false, err
+	}
+	for _, permission := range permissions.Values {
+		if permission.User.AccountID != event.AccountID {
+			continue
+		}
+		for _, p := range allowed {
+			if permission.Permission == p {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 // get the owner file from main branch and check if we are allowing there
-func (v *Provider) isAllowedFromOwnerFile(event *info.Event) (bool, error) {
-	ownerContent, err := v.GetFileInsideRepo(context.TODO(), event, "OWNERS", event.DefaultBranch)
+func (v *Provider) isAllowedFromOwnerFile(ctx context.Context, event *info.Event) (bool, error) {
+	ownerContent, err := v.GetFileInsideRepo(ctx, event, "OWNERS", event.DefaultBranch)
 	if err != nil {
 		return false, err
 	}
@@ -49,9 +124,9 @@ func (v *Provider) isAllowedFromOwnerFile(event *info.Event) (bool, error) {
 	return acl.UserInOwnerFile(ownerContent, event.AccountID)
 }
 
-func (v *Provider) checkMember(event *info.Event) (bool, error) {
-	// If sender is a member that can write to the workspace then allow it.
-	allowed, err := v.isWorkspaceMember(event)
+func (v *Provider) checkMember(ctx context.Context, event *info.Event) (bool, error) {
+	// If sender can write to the repository then allow it.
+	allowed, err := v.hasWritePermission(ctx, event)
 	if err != nil {
 		return false, err
 	} else if allowed {
@@ -62,7 +137,7 @@ func (v *Provider) checkMember(event *info.Event) (bool, error) {
 	// in the 'main' branch Silently ignore error, which should be fine it
 	// probably means the OWNERS file is not created. If we had another error
 	// (ie: like API) we probably would have hit it already.
-	allowed, _ = v.isAllowedFromOwnerFile(event)
+	allowed, _ = v.isAllowedFromOwnerFile(ctx, event)
 	if allowed {
 		return true, err
 	}
@@ -70,7 +145,7 @@ func (v *Provider) checkMember(event *info.Event) (bool, error) {
 	return false, nil
 }
 
-func (v *Provider) checkOkToTestCommentFromApprovedMember(event *info.Event) (bool, error) {
+func (v *Provider) checkOkToTestCommentFromApprovedMember(ctx context.Context, event *info.Event) (bool, error) {
 	commentsIntf, err := v.Client.Repositories.PullRequests.GetComments(&bitbucket.PullRequestsOptions{
 		Owner:    event.Organization,
 		RepoSlug: event.Repository,
@@ -95,7 +170,7 @@ func (v *Provider) checkOkToTestCommentFromApprovedMember(event *info.Event) (bo
 			commenterEvent.Repository = event.Repository
 			commenterEvent.Organization = event.Organization
 			commenterEvent.DefaultBranch = event.DefaultBranch
-			allowed, err := v.checkMember(commenterEvent)
+			allowed, err := v.checkMember(ctx, commenterEvent)
 			if err != nil {
 				return false, err
 			}
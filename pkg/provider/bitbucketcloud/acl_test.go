@@ -11,9 +11,11 @@ import (
 
 func TestIsAllowed(t *testing.T) {
 	type fields struct {
-		workspaceMembers []types.Member
-		comments         []types.Comment
-		filescontents    map[string]string
+		repoPermissions      []types.RepositoryPermission
+		permissionsForbidden bool
+		workspaceMembers     []string
+		comments             []types.Comment
+		filescontents        map[string]string
 	}
 	tests := []struct {
 		name    string
@@ -26,8 +28,9 @@ func TestIsAllowed(t *testing.T) {
 			name:  "allowed/user is owner",
 			event: bbcloudtest.MakeEvent(&info.Event{Sender: "member", AccountID: "IsaMember"}),
 			fields: fields{
-				workspaceMembers: []types.Member{
+				repoPermissions: []types.RepositoryPermission{
 					{
+						Permission: "admin",
 						User: types.User{
 							Nickname:  "member",
 							AccountID: "IsaMember",
@@ -41,8 +44,9 @@ func TestIsAllowed(t *testing.T) {
 			name:  "allowed/from a comment owner",
 			event: bbcloudtest.MakeEvent(&info.Event{Sender: "NotAllowedAtFirst"}),
 			fields: fields{
-				workspaceMembers: []types.Member{
+				repoPermissions: []types.RepositoryPermission{
 					{
+						Permission: "write",
 						User: types.User{
 							AccountID: "Owner",
 						},
@@ -66,8 +70,9 @@ func TestIsAllowed(t *testing.T) {
 				Sender: "NotAllowedAtFirst",
 			}),
 			fields: fields{
-				workspaceMembers: []types.Member{
+				repoPermissions: []types.RepositoryPermission{
 					{
+						Permission: "write",
 						User: types.User{
 							AccountID: "Randomweirdo",
 						},
@@ -91,8 +96,9 @@ func TestIsAllowed(t *testing.T) {
 			name:  "allowed/from an ownerfile who is a workspace member",
 			event: bbcloudtest.MakeEvent(&info.Event{Sender: "NotAllowedAtFirst"}),
 			fields: fields{
-				workspaceMembers: []types.Member{
+				repoPermissions: []types.RepositoryPermission{
 					{
+						Permission: "write",
 						User: types.User{
 							AccountID: "Owner",
 						},
@@ -109,12 +115,43 @@ func TestIsAllowed(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name:  "allowed/user can write to the repository",
+			event: bbcloudtest.MakeEvent(&info.Event{Sender: "writer", AccountID: "AccWriter"}),
+			fields: fields{
+				repoPermissions: []types.RepositoryPermission{
+					{
+						Permission: "write",
+						User: types.User{
+							AccountID: "AccWriter",
+						},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name:  "disallowed/user can only read the repository",
+			event: bbcloudtest.MakeEvent(&info.Event{Sender: "reader", AccountID: "AccReader"}),
+			fields: fields{
+				repoPermissions: []types.RepositoryPermission{
+					{
+						Permission: "read",
+						User: types.User{
+							AccountID: "AccReader",
+						},
+					},
+				},
+			},
+			want: false,
+		},
 		{
 			name:  "disallowed/same nickname different account id",
 			event: bbcloudtest.MakeEvent(&info.Event{Sender: "Bouffon", AccountID: "AccBouffon"}),
 			fields: fields{
-				workspaceMembers: []types.Member{
+				repoPermissions: []types.RepositoryPermission{
 					{
+						Permission: "write",
 						User: types.User{
 							Nickname:  "Bouffon",
 							AccountID: "NottheSameAccountID",
@@ -128,8 +165,9 @@ func TestIsAllowed(t *testing.T) {
 			name:  "disallowed/not a valid ok-to-test comment",
 			event: bbcloudtest.MakeEvent(&info.Event{Sender: "Bouffon", AccountID: "AccBouffon"}),
 			fields: fields{
-				workspaceMembers: []types.Member{
+				repoPermissions: []types.RepositoryPermission{
 					{
+						Permission: "write",
 						User: types.User{
 							AccountID: "Owner",
 						},
@@ -150,8 +188,9 @@ func TestIsAllowed(t *testing.T) {
 			name:  "allowed/ok-to-test on new line",
 			event: bbcloudtest.MakeEvent(&info.Event{Sender: "Bouffon", AccountID: "AccBouffon"}),
 			fields: fields{
-				workspaceMembers: []types.Member{
+				repoPermissions: []types.RepositoryPermission{
 					{
+						Permission: "write",
 						User: types.User{
 							AccountID: "Owner",
 						},
@@ -168,6 +207,39 @@ func TestIsAllowed(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "allowed/from owner file when the permissions cannot be read",
+			event: bbcloudtest.MakeEvent(&info.Event{
+				SHA:       "abcd",
+				Sender:    "owner",
+				AccountID: "accountid",
+			}),
+			fields: fields{
+				permissionsForbidden: true,
+				filescontents: map[string]string{
+					"OWNERS": "---\n approvers:\n  - accountid\n",
+				},
+			},
+			want: true,
+		},
+		{
+			name:  "allowed/workspace member when the permissions cannot be read",
+			event: bbcloudtest.MakeEvent(&info.Event{Sender: "member", AccountID: "IsaMember"}),
+			fields: fields{
+				permissionsForbidden: true,
+				workspaceMembers:     []string{"IsaMember"},
+			},
+			want: true,
+		},
+		{
+			name:  "disallowed/not a workspace member when the permissions cannot be read",
+			event: bbcloudtest.MakeEvent(&info.Event{Sender: "outsider", AccountID: "AccOutsider"}),
+			fields: fields{
+				permissionsForbidden: true,
+				workspaceMembers:     []string{"IsaMember"},
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -175,6 +247,11 @@ func TestIsAllowed(t *testing.T) {
 			bbclient, mux, tearDown := bbcloudtest.SetupBBCloudClient(t)
 			defer tearDown()
 
+			if tt.fields.permissionsForbidden {
+				bbcloudtest.MuxRepositoryPermissionsForbidden(t, mux, tt.event)
+			} else {
+				bbcloudtest.MuxRepositoryPermissions(t, mux, tt.event, tt.fields.repoPermissions)
+			}
 			bbcloudtest.MuxOrgMember(t, mux, tt.event, tt.fields.workspaceMembers)
 			bbcloudtest.MuxComments(t, mux, tt.event, tt.fields.comments)
 			bbcloudtest.MuxFiles(t, mux, tt.event, tt.fields.filescontents)
@@ -2,12 +2,14 @@ package bitbucketcloud
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/ktrysmt/go-bitbucket"
 	"github.com/mitchellh/mapstructure"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/metrics"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
@@ -16,11 +18,26 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	// tokenHeader is the header holding the shared token of the webhook,
+	// bitbucket cloud doesn't sign its payloads
+	tokenHeader = "X-Pipelines-As-Code-Token"
+	// tokenQueryParameter holds the shared token when it has been added to
+	// the url of the webhook
+	tokenQueryParameter = "token"
+
+	// the verifications of the webhooks recorded in the metrics
+	checkSourceIP = "source-ip"
+	checkToken    = "token"
+)
+
 type Provider struct {
 	Client        *bitbucket.Client
 	Logger        *zap.SugaredLogger
 	Token, APIURL *string
 	Username      *string
+	// Metrics records the verifications of the webhooks when set
+	Metrics *metrics.Recorder
 }
 
 // GetTaskURI TODO: Implement ME
@@ -28,15 +45,51 @@ func (v *Provider) GetTaskURI(ctx context.Context, params *params.Run, event *in
 	return false, "", nil
 }
 
+// IsRefProtected TODO: Implement ME
+func (v *Provider) IsRefProtected(ctx context.Context, event *info.Event, ref string) (bool, error) {
+	return false, nil
+}
+
 const taskStatusTemplate = `| **Status** | **Duration** | **Name** |
 | --- | --- | --- |
 {{range $taskrun := .TaskRunList }}|{{ formatCondition $taskrun.PipelineRunTaskRunStatus.Status.Conditions }}|{{ formatDuration $taskrun.PipelineRunTaskRunStatus.Status.StartTime $taskrun.PipelineRunTaskRunStatus.Status.CompletionTime }}|{{ $taskrun.ConsoleLogURL }}|
 {{ end }}`
 
-func (v *Provider) Validate(ctx context.Context, params *params.Run, event *info.Event) error {
+// Validate checks the shared token of the webhook against the webhook secret
+// of the Repository when there is one, the token is passed in the
+// X-Pipelines-As-Code-Token header or the token query parameter of the url of
+// the webhook.
+func (v *Provider) Validate(_ context.Context, _ *params.Run, event *info.Event) error {
+	if event.Provider.WebhookSecret == "" {
+		return nil
+	}
+
+	token := ""
+	if event.Request != nil {
+		token = event.Request.Header.Get(tokenHeader)
+		if token == "" && event.Request.Query != nil {
+			token = event.Request.Query.Get(tokenQueryParameter)
+		}
+	}
+	valid := subtle.ConstantTimeCompare([]byte(event.Provider.WebhookSecret), []byte(token)) == 1
+	v.recordVerification(checkToken, valid)
+	if !valid {
+		return fmt.Errorf("bitbucket cloud failed validation: the token of the webhook doesn't match with the webhook secret")
+	}
 	return nil
 }
 
+// recordVerification records the result of a verification of a webhook in
+// the metrics
+func (v *Provider) recordVerification(check string, valid bool) {
+	if v.Metrics == nil {
+		return
+	}
+	if err := v.Metrics.WebhookVerification(v.GetConfig().Name, check, valid); err != nil && v.Logger != nil {
+		v.Logger.Warnf("cannot record the %s verification of the webhook: %v", check, err)
+	}
+}
+
 func (v *Provider) SetLogger(logger *zap.SugaredLogger) {
 	v.Logger = logger
 }
@@ -91,17 +144,18 @@ func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event
 		return fmt.Errorf("no token has been set, cannot set status")
 	}
 
-	_, err := v.Client.Repositories.Commits.CreateCommitStatus(cmo, cso)
-	if err != nil {
-		return err
+	if !pacopts.DisableCommitStatuses {
+		if _, err := v.Client.Repositories.Commits.CreateCommitStatus(cmo, cso); err != nil {
+			return err
+		}
 	}
-	if statusopts.Conclusion != "STOPPED" && statusopts.Status == "completed" &&
+	if !pacopts.DisablePullRequestComments && statusopts.Conclusion != "STOPPED" && statusopts.Status == "completed" &&
 		statusopts.Text != "" && event.EventType == "pull_request" {
 		onPr := ""
 		if statusopts.OriginalPipelineRunName != "" {
 			onPr = "/" + statusopts.OriginalPipelineRunName
 		}
-		_, err = v.Client.Repositories.PullRequests.AddComment(
+		_, err := v.Client.Repositories.PullRequests.AddComment(
 			&bitbucket.PullRequestCommentOptions{
 				Owner:         event.Organization,
 				RepoSlug:      event.Repository,
@@ -115,6 +169,11 @@ func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event
 	return nil
 }
 
+// CreateStatuses sets the last build status of each PipelineRun of a batch
+func (v *Provider) CreateStatuses(ctx context.Context, tekton versioned.Interface, event *info.Event, opts *info.PacOpts, statuses []provider.StatusOpts) error {
+	return provider.CreateStatuses(ctx, v, tekton, event, opts, statuses)
+}
+
 func (v *Provider) GetTektonDir(_ context.Context, event *info.Event, path string) (string, error) {
 	repositoryFiles, err := v.getDir(event, path)
 	if err != nil {
@@ -1,10 +1,13 @@
 package bitbucketcloud
 
 import (
+	"net/http"
+	"net/url"
 	"strings"
 	"testing"
 
 	"github.com/ktrysmt/go-bitbucket"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
@@ -20,6 +23,65 @@ func TestGetConfig(t *testing.T) {
 	assert.Equal(t, config.APIURL, bitbucket.DEFAULT_BITBUCKET_API_BASE_URL)
 }
 
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		webhookSecret string
+		header        string
+		query         string
+		wantErrSubstr string
+	}{
+		{
+			name: "no webhook secret",
+		},
+		{
+			name:          "token in header",
+			webhookSecret: "secret",
+			header:        "secret",
+		},
+		{
+			name:          "token in query",
+			webhookSecret: "secret",
+			query:         "secret",
+		},
+		{
+			name:          "bad token",
+			webhookSecret: "secret",
+			header:        "notsecret",
+			wantErrSubstr: "doesn't match",
+		},
+		{
+			name:          "no token",
+			webhookSecret: "secret",
+			wantErrSubstr: "doesn't match",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			header := http.Header{}
+			if tt.header != "" {
+				header.Set(tokenHeader, tt.header)
+			}
+			query := url.Values{}
+			if tt.query != "" {
+				query.Set(tokenQueryParameter, tt.query)
+			}
+			event := info.NewEvent()
+			event.Provider = &info.Provider{WebhookSecret: tt.webhookSecret}
+			event.Request = &info.Request{Header: header, Query: query}
+
+			v := &Provider{}
+			err := v.Validate(ctx, &params.Run{}, event)
+			if tt.wantErrSubstr != "" {
+				assert.ErrorContains(t, err, tt.wantErrSubstr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
 func TestGetTektonDir(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -37,7 +37,7 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 
 	switch e := eventInt.(type) {
 	case *types.PullRequestEvent:
-		if provider.Valid(event, []string{"pullrequest:created", "pullrequest:updated"}) {
+		if provider.Valid(event, []string{"pullrequest:created", "pullrequest:updated", "pullrequest:fulfilled", "pullrequest:rejected"}) {
 			return setLoggerAndProceed(true, "", nil)
 		}
 		if provider.Valid(event, []string{"pullrequest:comment_created"}) {
@@ -50,6 +50,9 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 			if provider.IsCancelComment(e.Comment.Content.Raw) {
 				return setLoggerAndProceed(true, "", nil)
 			}
+			if provider.IsHelpComment(e.Comment.Content.Raw) {
+				return setLoggerAndProceed(true, "", nil)
+			}
 		}
 		return setLoggerAndProceed(false, fmt.Sprintf("not a valid gitops comment: \"%s\"", event), nil)
 
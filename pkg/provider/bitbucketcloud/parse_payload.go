@@ -7,6 +7,8 @@ import (
 	"net"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
@@ -23,28 +25,70 @@ func lastForwarderForIP(xff string) string {
 	return splitted[len(splitted)-1]
 }
 
+// ipRangesRefresh is how long the ip ranges of bitbucket cloud are used
+// before being fetched again
+const ipRangesRefresh = time.Hour
+
+// ipRangesCache keeps the ip ranges of bitbucket cloud between the webhooks,
+// the last ones are kept when they cannot be refreshed
+type ipRangesCache struct {
+	sync.Mutex
+	items   []types.IPRangesItem
+	fetched time.Time
+}
+
+var bitbucketCloudIPRanges = &ipRangesCache{}
+
+func (c *ipRangesCache) get(ctx context.Context, run *params.Run) ([]types.IPRangesItem, error) {
+	c.Lock()
+	defer c.Unlock()
+	if c.items != nil && time.Since(c.fetched) < ipRangesRefresh {
+		return c.items, nil
+	}
+
+	data, err := run.Clients.GetURL(ctx, bitbucketCloudIPrangesList)
+	if err == nil {
+		ipranges := &types.IPRanges{}
+		if err = json.Unmarshal(data, &ipranges); err == nil {
+			c.items = ipranges.Items
+			c.fetched = time.Now()
+			return c.items, nil
+		}
+	}
+	if c.items == nil {
+		return nil, err
+	}
+	if run.Clients.Log != nil {
+		run.Clients.Log.Warnf("cannot refresh the bitbucket cloud ip ranges from %s, using the ones fetched at %s: %v",
+			bitbucketCloudIPrangesList, c.fetched.Format(time.RFC3339), err)
+	}
+	return c.items, nil
+}
+
 // checkFromPublicCloudIPS Grab public IP from public cloud and make sure we match it
 func (v *Provider) checkFromPublicCloudIPS(ctx context.Context, run *params.Run, sourceIP string) (bool, error) {
 	if !run.Info.Pac.BitbucketCloudCheckSourceIP {
 		return true, nil
 	}
 
+	allowed, err := matchPublicCloudIPS(ctx, run, sourceIP)
+	v.recordVerification(checkSourceIP, allowed)
+	return allowed, err
+}
+
+func matchPublicCloudIPS(ctx context.Context, run *params.Run, sourceIP string) (bool, error) {
 	if sourceIP == "" {
 		return false, fmt.Errorf("we need to check the source_ip but no source_ip has been passed")
 	}
 	sourceIP = lastForwarderForIP(sourceIP)
 
 	netsourceIP := net.ParseIP(sourceIP)
-	data, err := run.Clients.GetURL(ctx, bitbucketCloudIPrangesList)
-	if err != nil {
-		return false, err
-	}
-
-	ipranges := &types.IPRanges{}
-	err = json.Unmarshal(data, &ipranges)
+	items, err := bitbucketCloudIPRanges.get(ctx, run)
 	if err != nil {
 		return false, err
 	}
+	// the additional ips are not added to the cached ranges
+	items = append([]types.IPRangesItem{}, items...)
 
 	extraIPEnv := run.Info.Pac.BitbucketCloudAdditionalSourceIP
 	if extraIPEnv != "" {
@@ -52,12 +96,12 @@ func (v *Provider) checkFromPublicCloudIPS(ctx context.Context, run *params.Run,
 			if !strings.Contains(value, "/") {
 				value = fmt.Sprintf("%s/32", value)
 			}
-			ipranges.Items = append(ipranges.Items, types.IPRangesItem{
+			items = append(items, types.IPRangesItem{
 				CIDR: strings.TrimSpace(value),
 			})
 		}
 	}
-	for _, value := range ipranges.Items {
+	for _, value := range items {
 		_, cidr, err := net.ParseCIDR(value.CIDR)
 		if err != nil {
 			return false, err
@@ -78,6 +122,7 @@ func parsePayloadType(event, rawPayload string) (interface{}, error) {
 	if strings.HasPrefix(event, "pullrequest:") {
 		if !provider.Valid(event, []string{
 			"pullrequest:created", "pullrequest:updated", "pullrequest:comment_created",
+			"pullrequest:fulfilled", "pullrequest:rejected",
 		}) {
 			return nil, fmt.Errorf("event %s is not supported", event)
 		}
@@ -129,7 +174,12 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 		if provider.Valid(event, []string{"pullrequest:created", "pullrequest:updated"}) {
 			processedEvent.TriggerTarget = "pull_request"
 			processedEvent.EventType = "pull_request"
+		} else if provider.Valid(event, []string{"pullrequest:fulfilled", "pullrequest:rejected"}) {
+			processedEvent.TriggerTarget = "pull_request"
+			processedEvent.EventType = "pull_request"
+			processedEvent.CancelInProgress = true
 		} else if provider.Valid(event, []string{"pullrequest:comment_created"}) {
+			processedEvent.CommentCommand = provider.CommentCommand(e.Comment.Content.Raw)
 			switch {
 			case provider.IsTestRetestComment(e.Comment.Content.Raw):
 				processedEvent.TriggerTarget = "pull_request"
@@ -139,6 +189,7 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 					processedEvent.EventType = "retest-comment"
 				}
 				processedEvent.TargetTestPipelineRun = provider.GetPipelineRunFromTestComment(e.Comment.Content.Raw)
+				processedEvent.TestParams = provider.GetParamsFromTestComment(e.Comment.Content.Raw)
 			case provider.IsOkToTestComment(e.Comment.Content.Raw):
 				processedEvent.TriggerTarget = "pull_request"
 				processedEvent.EventType = "ok-to-test-comment"
@@ -147,6 +198,9 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 				processedEvent.EventType = "cancel-comment"
 				processedEvent.CancelPipelineRuns = true
 				processedEvent.TargetCancelPipelineRun = provider.GetPipelineRunFromCancelComment(e.Comment.Content.Raw)
+			case provider.IsHelpComment(e.Comment.Content.Raw):
+				processedEvent.TriggerTarget = "pull_request"
+				processedEvent.EventType = "help-comment"
 			}
 		}
 		processedEvent.Organization = e.Repository.Workspace.Slug
@@ -157,8 +211,15 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 		processedEvent.HeadBranch = e.PullRequest.Source.Branch.Name
 		processedEvent.AccountID = e.PullRequest.Author.AccountID
 		processedEvent.Sender = e.PullRequest.Author.Nickname
+		processedEvent.PullRequestAuthor = e.PullRequest.Author.Nickname
 		processedEvent.PullRequestNumber = e.PullRequest.ID
 		processedEvent.PullRequestTitle = e.PullRequest.Title
+		// the commenter is the one running the gitops command, the ACL are
+		// checked against it
+		if event == "pullrequest:comment_created" {
+			processedEvent.AccountID = e.Comment.User.AccountID
+			processedEvent.Sender = e.Comment.User.Nickname
+		}
 	case *types.PushRequestEvent:
 		processedEvent.Event = "push"
 		processedEvent.TriggerTarget = "push"
@@ -168,6 +229,11 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 		processedEvent.URL = e.Repository.Links.HTML.HRef
 		processedEvent.BaseBranch = e.Push.Changes[0].New.Name
 		processedEvent.HeadBranch = e.Push.Changes[0].Old.Name
+		if e.Push.Changes[0].Closed && e.Push.Changes[0].Old.Type == "branch" {
+			processedEvent.SHA = e.Push.Changes[0].Old.Target.Hash
+			processedEvent.BaseBranch = e.Push.Changes[0].Old.Name
+			processedEvent.BranchDeleted = true
+		}
 		processedEvent.AccountID = e.Actor.AccountID
 		processedEvent.Sender = e.Actor.Nickname
 	default:
@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	bbcloudtest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud/test"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud/types"
 	httptesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/http"
 	"gotest.tools/v3/assert"
 	rtesting "knative.dev/pkg/reconciler/testing"
@@ -159,6 +161,18 @@ func TestParsePayload(t *testing.T) {
 			expectedSHA:       "sha",
 			targetPipelinerun: "dummy",
 		},
+		{
+			name: "retest comment from another user",
+			payloadEvent: func() types.PullRequestEvent {
+				pr := bbcloudtest.MakePREvent("account", "sender", "sha", "/retest")
+				pr.Comment.User = types.User{AccountID: "commenterAccount", Nickname: "commenter"}
+				return pr
+			}(),
+			eventType:         "pullrequest:comment_created",
+			expectedAccountID: "commenterAccount",
+			expectedSender:    "commenter",
+			expectedSHA:       "sha",
+		},
 		{
 			name:              "ok-to-test comment",
 			payloadEvent:      bbcloudtest.MakePREvent("account", "sender", "sha", "/ok-to-test"),
@@ -220,6 +234,7 @@ func TestParsePayload(t *testing.T) {
 				httpTestClient := httptesthelper.MakeHTTPTestClient(t, tt.allowedConfig)
 				run.Clients.HTTP = *httpTestClient
 			}
+			bitbucketCloudIPRanges = &ipRangesCache{}
 
 			payload, err := json.Marshal(tt.payloadEvent)
 			assert.NilError(t, err)
@@ -242,3 +257,49 @@ func TestParsePayload(t *testing.T) {
 		})
 	}
 }
+
+func TestIPRangesCache(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	run := &params.Run{}
+	run.Clients.HTTP = *httptesthelper.MakeHTTPTestClient(t, map[string]map[string]string{
+		bitbucketCloudIPrangesList: {
+			"body": `{"items": [{"cidr": "1.2.3.10/16"}]}`,
+			"code": "200",
+		},
+	})
+	cache := &ipRangesCache{}
+	items, err := cache.get(ctx, run)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, items, []types.IPRangesItem{{CIDR: "1.2.3.10/16"}})
+
+	// the ranges are not fetched again until they are expired
+	run.Clients.HTTP = *httptesthelper.MakeHTTPTestClient(t, map[string]map[string]string{
+		bitbucketCloudIPrangesList: {
+			"body": `{"items": [{"cidr": "4.5.6.10/16"}]}`,
+			"code": "200",
+		},
+	})
+	items, err = cache.get(ctx, run)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, items, []types.IPRangesItem{{CIDR: "1.2.3.10/16"}})
+
+	cache.fetched = time.Now().Add(-ipRangesRefresh)
+	items, err = cache.get(ctx, run)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, items, []types.IPRangesItem{{CIDR: "4.5.6.10/16"}})
+
+	// the stale ranges are used when they cannot be refreshed
+	cache.fetched = time.Now().Add(-ipRangesRefresh)
+	run.Clients.HTTP = *httptesthelper.MakeHTTPTestClient(t, map[string]map[string]string{
+		bitbucketCloudIPrangesList: {
+			"body": "not json",
+			"code": "200",
+		},
+	})
+	items, err = cache.get(ctx, run)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, items, []types.IPRangesItem{{CIDR: "4.5.6.10/16"}})
+
+	_, err = (&ipRangesCache{}).get(ctx, run)
+	assert.Assert(t, err != nil)
+}
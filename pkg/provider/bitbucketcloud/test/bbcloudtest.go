@@ -71,14 +71,42 @@ func MuxComments(t *testing.T, mux *http.ServeMux, event *info.Event, comments [
 		})
 }
 
-func MuxOrgMember(t *testing.T, mux *http.ServeMux, event *info.Event, members []types.Member) {
+func MuxRepositoryPermissions(t *testing.T, mux *http.ServeMux, event *info.Event, permissions []types.RepositoryPermission) {
+	t.Helper()
+	mux.HandleFunc("/workspaces/"+event.Organization+"/permissions/repositories/"+event.Repository,
+		func(rw http.ResponseWriter, r *http.Request) {
+			values := &types.RepositoryPermissions{Values: []types.RepositoryPermission{}}
+			for _, permission := range permissions {
+				if r.URL.Query().Get("q") == fmt.Sprintf("user.account_id=\"%s\"", permission.User.AccountID) {
+					values.Values = append(values.Values, permission)
+				}
+			}
+			b, err := json.Marshal(values)
+			assert.NilError(t, err)
+			fmt.Fprint(rw, string(b))
+		})
+}
+
+// MuxRepositoryPermissionsForbidden refuses to list the repository
+// permissions, like Bitbucket Cloud does for an app password without the
+// Repositories Admin permission.
+func MuxRepositoryPermissionsForbidden(t *testing.T, mux *http.ServeMux, event *info.Event) {
+	t.Helper()
+	mux.HandleFunc("/workspaces/"+event.Organization+"/permissions/repositories/"+event.Repository,
+		func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusForbidden)
+		})
+}
+
+func MuxOrgMember(t *testing.T, mux *http.ServeMux, event *info.Event, accountIDs []string) {
 	t.Helper()
 	mux.HandleFunc("/workspaces/"+event.Organization+"/members",
 		func(rw http.ResponseWriter, r *http.Request) {
-			members := &types.Members{
-				Values: members,
+			values := []map[string]interface{}{}
+			for _, accountID := range accountIDs {
+				values = append(values, map[string]interface{}{"user": map[string]string{"account_id": accountID}})
 			}
-			b, err := json.Marshal(members)
+			b, err := json.Marshal(map[string]interface{}{"values": values})
 			assert.NilError(t, err)
 			fmt.Fprint(rw, string(b))
 		})
@@ -266,7 +294,10 @@ func MakePREvent(accountid, nickname, sha, comment string) types.PullRequestEven
 			Content: types.Content{
 				Raw: comment,
 			},
-			User: types.User{},
+			User: types.User{
+				AccountID: accountid,
+				Nickname:  nickname,
+			},
 		}
 	}
 	return pr
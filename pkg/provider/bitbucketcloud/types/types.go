@@ -71,12 +71,15 @@ type PushRequestEvent struct {
 
 type ChangeType struct {
 	Name   string
+	Type   string
 	Target Commit
 }
 
 type Change struct {
 	New ChangeType
 	Old ChangeType
+	// Closed is set when the push deleted the branch, New is then empty
+	Closed bool
 }
 
 type User struct {
@@ -97,12 +100,15 @@ type IPRanges struct {
 	Items []IPRangesItem
 }
 
-type Member struct {
-	User User `json:"user"`
+// RepositoryPermission is the permission of a user on a repository of the
+// workspace, ie: read, write or admin
+type RepositoryPermission struct {
+	Permission string `json:"permission"`
+	User       User   `json:"user"`
 }
 
-type Members struct {
-	Values []Member
+type RepositoryPermissions struct {
+	Values []RepositoryPermission `json:"values"`
 }
 
 type Content struct {
@@ -85,7 +85,40 @@ func (v *Provider) checkOkToTestCommentFromApprovedMember(event *info.Event) (bo
 	return false, nil
 }
 
-func (v *Provider) checkMemberShipResults(results []interface{}, event *info.Event) (bool, error) {
+// IsMaintainer checks the sender is an admin of the project or of the
+// repository
+func (v *Provider) IsMaintainer(_ context.Context, event *info.Event) (bool, error) {
+	allValues, err := paginate(func(nextPage int) (*bbv1.APIResponse, error) {
+		localVarOptionals := map[string]interface{}{}
+		if nextPage > 0 {
+			localVarOptionals["start"] = int(nextPage)
+		}
+		return v.Client.DefaultApi.GetUsersWithAnyPermission_23(v.projectKey, localVarOptionals)
+	})
+	if err != nil {
+		return false, err
+	}
+	allowed, err := v.checkMemberShipResults(allValues, event, "PROJECT_ADMIN")
+	if err != nil || allowed {
+		return allowed, err
+	}
+
+	allValues, err = paginate(func(nextPage int) (*bbv1.APIResponse, error) {
+		localVarOptionals := map[string]interface{}{}
+		if nextPage > 0 {
+			localVarOptionals["start"] = int(nextPage)
+		}
+		return v.Client.DefaultApi.GetUsersWithAnyPermission_24(v.projectKey, event.Repository, localVarOptionals)
+	})
+	if err != nil {
+		return false, err
+	}
+	return v.checkMemberShipResults(allValues, event, "REPO_ADMIN")
+}
+
+// checkMemberShipResults checks the sender is in the users with a permission,
+// with one of the permissions when some are given
+func (v *Provider) checkMemberShipResults(results []interface{}, event *info.Event, permissions ...string) (bool, error) {
 	accountintid, err := strconv.Atoi(event.AccountID)
 	if err != nil {
 		return false, err
@@ -97,9 +130,17 @@ func (v *Provider) checkMemberShipResults(results []interface{}, event *info.Eve
 			return false, err
 		}
 
-		if user.User.ID == accountintid {
+		if user.User.ID != accountintid {
+			continue
+		}
+		if len(permissions) == 0 {
 			return true, nil
 		}
+		for _, permission := range permissions {
+			if user.Permission == permission {
+				return true, nil
+			}
+		}
 	}
 	return false, nil
 }
@@ -35,6 +35,11 @@ func (v *Provider) GetTaskURI(ctx context.Context, params *params.Run, event *in
 	return false, "", nil
 }
 
+// IsRefProtected TODO: Implement ME
+func (v *Provider) IsRefProtected(ctx context.Context, event *info.Event, ref string) (bool, error) {
+	return false, nil
+}
+
 func (v *Provider) SetLogger(logger *zap.SugaredLogger) {
 	v.Logger = logger
 }
@@ -86,18 +91,20 @@ func (v *Provider) CreateStatus(ctx context.Context, _ versioned.Interface, even
 		key = statusOpts.Conclusion
 	}
 
-	_, err := v.Client.DefaultApi.SetCommitStatus(
-		event.SHA,
-		bbv1.BuildStatus{
-			State:       statusOpts.Conclusion,
-			Name:        pacOpts.ApplicationName,
-			Key:         key,
-			Description: statusOpts.Title,
-			Url:         detailsURL,
-		},
-	)
-	if err != nil {
-		return err
+	if !pacOpts.DisableCommitStatuses {
+		_, err := v.Client.DefaultApi.SetCommitStatus(
+			event.SHA,
+			bbv1.BuildStatus{
+				State:       statusOpts.Conclusion,
+				Name:        pacOpts.ApplicationName,
+				Key:         key,
+				Description: statusOpts.Title,
+				Url:         detailsURL,
+			},
+		)
+		if err != nil {
+			return err
+		}
 	}
 
 	onPr := ""
@@ -109,7 +116,7 @@ func (v *Provider) CreateStatus(ctx context.Context, _ versioned.Interface, even
 			statusOpts.Title, statusOpts.Text),
 	}
 
-	if statusOpts.Conclusion == "SUCCESSFUL" && statusOpts.Status == "completed" &&
+	if !pacOpts.DisablePullRequestComments && statusOpts.Conclusion == "SUCCESSFUL" && statusOpts.Status == "completed" &&
 		statusOpts.Text != "" && event.EventType == "pull_request" && v.pullRequestNumber > 0 {
 		_, err := v.Client.DefaultApi.CreatePullRequestComment(
 			v.projectKey, event.Repository, v.pullRequestNumber,
@@ -123,6 +130,11 @@ func (v *Provider) CreateStatus(ctx context.Context, _ versioned.Interface, even
 	return nil
 }
 
+// CreateStatuses sets the last build status of each PipelineRun of a batch
+func (v *Provider) CreateStatuses(ctx context.Context, tekton versioned.Interface, event *info.Event, opts *info.PacOpts, statuses []provider.StatusOpts) error {
+	return provider.CreateStatuses(ctx, v, tekton, event, opts, statuses)
+}
+
 func (v *Provider) concatAllYamlFiles(objects []string, runevent *info.Event) (string, error) {
 	var allTemplates string
 	for _, value := range objects {
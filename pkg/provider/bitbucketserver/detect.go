@@ -38,7 +38,7 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 
 	switch e := eventPayload.(type) {
 	case *types.PullRequestEvent:
-		if provider.Valid(event, []string{"pr:from_ref_updated", "pr:opened"}) {
+		if provider.Valid(event, []string{"pr:from_ref_updated", "pr:opened", "pr:merged", "pr:declined"}) {
 			return setLoggerAndProceed(true, "", nil)
 		}
 		if provider.Valid(event, []string{"pr:comment:added"}) {
@@ -51,6 +51,9 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 			if provider.IsCancelComment(e.Comment.Text) {
 				return setLoggerAndProceed(true, "", nil)
 			}
+			if provider.IsHelpComment(e.Comment.Text) {
+				return setLoggerAndProceed(true, "", nil)
+			}
 		}
 		return setLoggerAndProceed(false, fmt.Sprintf("not a recognized bitbucket event: \"%s\"", event), nil)
 
@@ -50,7 +50,12 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		if provider.Valid(eventType, []string{"pr:from_ref_updated", "pr:opened"}) {
 			processedEvent.TriggerTarget = "pull_request"
 			processedEvent.EventType = "pull_request"
+		} else if provider.Valid(eventType, []string{"pr:merged", "pr:declined"}) {
+			processedEvent.TriggerTarget = "pull_request"
+			processedEvent.EventType = "pull_request"
+			processedEvent.CancelInProgress = true
 		} else if provider.Valid(eventType, []string{"pr:comment:added", "pr:comment:edited"}) {
+			processedEvent.CommentCommand = provider.CommentCommand(e.Comment.Text)
 			switch {
 			case provider.IsTestRetestComment(e.Comment.Text):
 				processedEvent.TriggerTarget = "pull_request"
@@ -60,6 +65,7 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 					processedEvent.EventType = "retest-comment"
 				}
 				processedEvent.TargetTestPipelineRun = provider.GetPipelineRunFromTestComment(e.Comment.Text)
+				processedEvent.TestParams = provider.GetParamsFromTestComment(e.Comment.Text)
 			case provider.IsOkToTestComment(e.Comment.Text):
 				processedEvent.TriggerTarget = "pull_request"
 				processedEvent.EventType = "ok-to-test-comment"
@@ -68,6 +74,9 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 				processedEvent.EventType = "cancel-comment"
 				processedEvent.CancelPipelineRuns = true
 				processedEvent.TargetCancelPipelineRun = provider.GetPipelineRunFromCancelComment(e.Comment.Text)
+			case provider.IsHelpComment(e.Comment.Text):
+				processedEvent.TriggerTarget = "pull_request"
+				processedEvent.EventType = "help-comment"
 			}
 		}
 		// TODO: It's Really not an OWNER but a PROJECT
@@ -80,6 +89,9 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.HeadBranch = e.PulRequest.FromRef.DisplayID
 		processedEvent.AccountID = fmt.Sprintf("%d", e.Actor.ID)
 		processedEvent.Sender = e.Actor.Name
+		if e.PulRequest.Author != nil {
+			processedEvent.PullRequestAuthor = e.PulRequest.Author.User.Name
+		}
 		for _, value := range e.PulRequest.FromRef.Repository.Links.Clone {
 			if value.Name == "http" {
 				processedEvent.CloneURL = value.Href
@@ -95,6 +107,10 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.URL = e.Repository.Links.Self[0].Href
 		processedEvent.BaseBranch = e.Changes[0].RefID
 		processedEvent.HeadBranch = e.Changes[0].RefID
+		if e.Changes[0].Type == "DELETE" && strings.HasPrefix(e.Changes[0].RefID, "refs/heads/") {
+			processedEvent.SHA = e.Changes[0].FromHash
+			processedEvent.BranchDeleted = true
+		}
 		processedEvent.AccountID = fmt.Sprintf("%d", e.Actor.ID)
 		processedEvent.Sender = e.Actor.Name
 		// Should we care about clone via SSH or just only do HTTP clones?
@@ -131,6 +147,7 @@ func parsePayloadType(event string) (interface{}, error) {
 	if strings.HasPrefix(event, "pr:") {
 		if !provider.Valid(event, []string{
 			"pr:from_ref_updated", "pr:opened", "pr:comment:added", "pr:comment:edited",
+			"pr:merged", "pr:declined",
 		}) {
 			return nil, fmt.Errorf("event \"%s\" is not supported", event)
 		}
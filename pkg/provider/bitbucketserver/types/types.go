@@ -16,8 +16,11 @@ type PullRequestEvent struct {
 }
 
 type PushRequestEventChange struct {
-	ToHash string `json:"toHash"`
-	RefID  string `json:"refId"`
+	FromHash string `json:"fromHash"`
+	ToHash   string `json:"toHash"`
+	RefID    string `json:"refId"`
+	// Type is ADD, UPDATE or DELETE
+	Type string `json:"type"`
 }
 
 type PushRequestEvent struct {
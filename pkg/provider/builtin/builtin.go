@@ -0,0 +1,46 @@
+// Package builtin registers the git providers of Pipelines as Code, it is
+// imported for its side effect by the controller and the watcher.
+package builtin
+
+import (
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/metrics"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketserver"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab"
+)
+
+// the providers are registered in the order the webhooks have always been
+// detected in
+func init() {
+	provider.Register(provider.Registration{
+		Name:    "github",
+		Aliases: []string{"github-enterprise"},
+		New: func(recorder *metrics.Recorder) provider.Interface {
+			gh := github.New()
+			gh.Metrics = recorder
+			return gh
+		},
+	})
+	provider.Register(provider.Registration{
+		Name:    "gitea",
+		Aliases: []string{"forgejo"},
+		New:     func(*metrics.Recorder) provider.Interface { return &gitea.Provider{} },
+	})
+	provider.Register(provider.Registration{
+		Name: "bitbucket-server",
+		New:  func(*metrics.Recorder) provider.Interface { return &bitbucketserver.Provider{} },
+	})
+	provider.Register(provider.Registration{
+		Name: "gitlab",
+		New:  func(*metrics.Recorder) provider.Interface { return &gitlab.Provider{} },
+	})
+	provider.Register(provider.Registration{
+		Name: "bitbucket-cloud",
+		New: func(recorder *metrics.Recorder) provider.Interface {
+			return &bitbucketcloud.Provider{Metrics: recorder}
+		},
+	})
+}
@@ -0,0 +1,36 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/metrics"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	"gotest.tools/v3/assert"
+)
+
+func TestBuiltinProviders(t *testing.T) {
+	names := []string{}
+	for _, registration := range provider.Registrations() {
+		names = append(names, registration.Name)
+	}
+	assert.DeepEqual(t, names, []string{"github", "gitea", "bitbucket-server", "gitlab", "bitbucket-cloud"})
+
+	recorder := &metrics.Recorder{}
+	registration, ok := provider.Lookup("github-enterprise")
+	assert.Assert(t, ok)
+	gh, ok := registration.New(recorder).(*github.Provider)
+	assert.Assert(t, ok)
+	assert.Equal(t, gh.Metrics, recorder)
+
+	registration, ok = provider.Lookup("forgejo")
+	assert.Assert(t, ok)
+	assert.Equal(t, registration.Name, "gitea")
+
+	registration, ok = provider.Lookup("bitbucket-cloud")
+	assert.Assert(t, ok)
+	bb, ok := registration.New(recorder).(*bitbucketcloud.Provider)
+	assert.Assert(t, ok)
+	assert.Equal(t, bb.Metrics, recorder)
+}
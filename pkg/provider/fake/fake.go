@@ -0,0 +1,352 @@
+// Package fake is a hermetic simulator of the APIs of GitHub and GitLab used
+// by Pipelines as Code. It serves the repositories, the pull requests and the
+// files it has been given and records the check runs, the commit statuses and
+// the comments created on it, so the e2e tests and the users can test their
+// setup without a real git provider.
+//
+// The GitHub API is served on <URL>/api/v3 and the GitLab API on
+// <URL>/api/v4, the URL of the server is used as the git_provider.url of the
+// Repository.
+package fake
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	githubAPIPath = "/api/v3"
+	gitlabAPIPath = "/api/v4"
+
+	// PermissionAdmin is the permission of the administrators of the
+	// repository.
+	PermissionAdmin = "admin"
+	// PermissionWrite is the permission of the users allowed to push to the
+	// repository.
+	PermissionWrite = "write"
+	// PermissionRead is the permission of the users only allowed to read the
+	// repository.
+	PermissionRead = "read"
+)
+
+// Repository is a repository served by the simulator, a project on GitLab.
+type Repository struct {
+	// Organization is the owner of the repository, the namespace on GitLab
+	Organization string
+	// Name is the name of the repository
+	Name string
+	// ID is the ID of the repository, the project ID on GitLab
+	ID int
+	// DefaultBranch is the default branch of the repository
+	DefaultBranch string
+	// Private makes the repository private
+	Private bool
+	// Branches are the SHA of the head of each branch
+	Branches map[string]string
+	// ProtectedBranches are the branches reported as protected
+	ProtectedBranches []string
+	// Commits are the commits of the repository by SHA
+	Commits map[string]*Commit
+	// PullRequests are the pull requests, the merge requests on GitLab, by
+	// number
+	PullRequests map[int]*PullRequest
+	// Collaborators are the users with a permission on the repository
+	Collaborators []Collaborator
+}
+
+// Commit is a commit of a repository.
+type Commit struct {
+	// Message is the message of the commit, its first line is its title
+	Message string
+	// Files are the content of the files of the repository at this commit by
+	// path
+	Files map[string]string
+	// ChangedFiles are the files changed by the commit
+	ChangedFiles []string
+}
+
+// PullRequest is a pull request, a merge request on GitLab.
+type PullRequest struct {
+	Title      string
+	Author     string
+	HeadBranch string
+	HeadSHA    string
+	BaseBranch string
+	Labels     []string
+	// ChangedFiles are the files changed by the pull request
+	ChangedFiles []string
+}
+
+// Collaborator is a user with a permission on the repository.
+type Collaborator struct {
+	Login string
+	// ID is the ID of the user, GitLab checks the members by ID
+	ID int
+	// Permission is one of PermissionAdmin, PermissionWrite or PermissionRead
+	Permission string
+}
+
+// CheckRun is a check run created on GitHub.
+type CheckRun struct {
+	ID         int64
+	SHA        string
+	Name       string
+	ExternalID string
+	Status     string
+	Conclusion string
+	Title      string
+	Summary    string
+	Text       string
+	DetailsURL string
+}
+
+// Status is a commit status.
+type Status struct {
+	SHA         string
+	State       string
+	Context     string
+	Description string
+	TargetURL   string
+}
+
+// Comment is a comment on a pull request, a note on a merge request.
+type Comment struct {
+	ID     int64
+	Number int
+	Author string
+	Body   string
+}
+
+type repositoryState struct {
+	*Repository
+	checkRuns []*CheckRun
+	statuses  []*Status
+	comments  []*Comment
+}
+
+// Server is the simulator, it is safe to use from multiple goroutines.
+type Server struct {
+	// URL is the URL of the simulator
+	URL string
+	// User is the login of the user owning the token, the author of the
+	// comments created through the API
+	User string
+
+	server *httptest.Server
+	mutex  sync.Mutex
+	repos  map[string]*repositoryState
+	lastID int64
+}
+
+// NewServer starts a simulator, it has to be closed.
+func NewServer() *Server {
+	s := &Server{
+		User:  "pipelines-as-code[bot]",
+		repos: map[string]*repositoryState{},
+	}
+	mux := http.NewServeMux()
+	mux.Handle(githubAPIPath+"/", http.StripPrefix(githubAPIPath, http.HandlerFunc(s.serveGitHub)))
+	mux.Handle(gitlabAPIPath+"/", http.StripPrefix(gitlabAPIPath, http.HandlerFunc(s.serveGitLab)))
+	s.server = httptest.NewServer(mux)
+	s.URL = s.server.URL
+	return s
+}
+
+// Close stops the simulator.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// AddRepository adds or replaces a repository, the repository should not be
+// changed once added.
+func (s *Server) AddRepository(repo *Repository) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if repo.ID == 0 {
+		repo.ID = len(s.repos) + 1
+	}
+	s.repos[repo.Organization+"/"+repo.Name] = &repositoryState{Repository: repo}
+}
+
+// CheckRuns returns the check runs created on a repository.
+func (s *Server) CheckRuns(org, name string) []CheckRun {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	checkRuns := []CheckRun{}
+	if repo, ok := s.repos[org+"/"+name]; ok {
+		for _, checkRun := range repo.checkRuns {
+			checkRuns = append(checkRuns, *checkRun)
+		}
+	}
+	return checkRuns
+}
+
+// Statuses returns the commit statuses created on a repository, in the order
+// they have been created.
+func (s *Server) Statuses(org, name string) []Status {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	statuses := []Status{}
+	if repo, ok := s.repos[org+"/"+name]; ok {
+		for _, status := range repo.statuses {
+			statuses = append(statuses, *status)
+		}
+	}
+	return statuses
+}
+
+// Comments returns the comments of a pull request of a repository.
+func (s *Server) Comments(org, name string, number int) []Comment {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	comments := []Comment{}
+	if repo, ok := s.repos[org+"/"+name]; ok {
+		for _, comment := range repo.comments {
+			if comment.Number == number {
+				comments = append(comments, *comment)
+			}
+		}
+	}
+	return comments
+}
+
+// AddComment adds a comment of a user on a pull request, ie: a /retest.
+func (s *Server) AddComment(org, name string, number int, author, body string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if repo, ok := s.repos[org+"/"+name]; ok {
+		repo.comments = append(repo.comments, &Comment{ID: s.nextID(), Number: number, Author: author, Body: body})
+	}
+}
+
+// nextID returns a new ID for the check runs and the comments, called with
+// the mutex held.
+func (s *Server) nextID() int64 {
+	s.lastID++
+	return s.lastID
+}
+
+func (s *Server) repository(org, name string) *repositoryState {
+	return s.repos[org+"/"+name]
+}
+
+func (s *Server) repositoryByID(id int) *repositoryState {
+	for _, repo := range s.repos {
+		if repo.ID == id {
+			return repo
+		}
+	}
+	return nil
+}
+
+// resolve returns the SHA of a branch or a SHA.
+func (r *repositoryState) resolve(ref string) string {
+	ref = strings.TrimPrefix(ref, "refs/heads/")
+	if sha, ok := r.Branches[ref]; ok {
+		return sha
+	}
+	return ref
+}
+
+func (r *repositoryState) commit(ref string) (string, *Commit) {
+	sha := r.resolve(ref)
+	return sha, r.Commits[sha]
+}
+
+func (r *repositoryState) collaborator(login string) *Collaborator {
+	for i := range r.Collaborators {
+		if strings.EqualFold(r.Collaborators[i].Login, login) {
+			return &r.Collaborators[i]
+		}
+	}
+	return nil
+}
+
+func (r *repositoryState) isProtected(branch string) bool {
+	for _, protected := range r.ProtectedBranches {
+		if protected == branch {
+			return true
+		}
+	}
+	return false
+}
+
+// treeEntry is a file or a directory of a commit.
+type treeEntry struct {
+	path  string
+	isDir bool
+}
+
+// tree returns the files and the directories under a directory of a commit,
+// with their paths relative to the directory, only its direct children when
+// not recursive.
+func (c *Commit) tree(dir string, recursive bool) []treeEntry {
+	prefix := ""
+	if dir = strings.Trim(dir, "/"); dir != "" {
+		prefix = dir + "/"
+	}
+	seen := map[string]bool{}
+	entries := []treeEntry{}
+	for path := range c.Files {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		parts := strings.Split(strings.TrimPrefix(path, prefix), "/")
+		for i := range parts {
+			if i > 0 && !recursive {
+				break
+			}
+			entry := strings.Join(parts[:i+1], "/")
+			if !seen[entry] {
+				seen[entry] = true
+				entries = append(entries, treeEntry{path: entry, isDir: i < len(parts)-1})
+			}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].path < entries[j].path })
+	return entries
+}
+
+// file returns the content of a file of the commit.
+func (c *Commit) file(path string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	content, ok := c.Files[strings.Trim(path, "/")]
+	return content, ok
+}
+
+// isDir returns whether the path is a directory of the commit, the root is a
+// directory.
+func (c *Commit) isDir(dir string) bool {
+	if c == nil {
+		return false
+	}
+	if dir = strings.Trim(dir, "/"); dir == "" {
+		return true
+	}
+	for path := range c.Files {
+		if strings.HasPrefix(path, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// objectSHA encodes the commit and the path of a tree or a blob in its SHA,
+// the objects don't need to be stored.
+func objectSHA(sha, path string) string {
+	return hex.EncodeToString([]byte(sha + ":" + path))
+}
+
+func parseObjectSHA(object string) (string, string, bool) {
+	decoded, err := hex.DecodeString(object)
+	if err != nil {
+		return "", "", false
+	}
+	return strings.Cut(string(decoded), ":")
+}
@@ -0,0 +1,198 @@
+package fake
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab"
+	"go.uber.org/zap"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+const (
+	pullRequestYaml = "kind: PipelineRun\nmetadata:\n  name: pull-request\n"
+	pushYaml        = "kind: PipelineRun\nmetadata:\n  name: push\n"
+)
+
+func newTestServer() *Server {
+	srv := NewServer()
+	srv.AddRepository(&Repository{
+		Organization:  "owner",
+		Name:          "repo",
+		DefaultBranch: "main",
+		Branches:      map[string]string{"main": "mainsha", "feature": "featuresha"},
+		Commits: map[string]*Commit{
+			"mainsha": {
+				Message: "Initial commit",
+				Files:   map[string]string{"README.md": "hello"},
+			},
+			"featuresha": {
+				Message: "Add the pipelines\n\nWith a pull request and a push one.",
+				Files: map[string]string{
+					"README.md":              "hello",
+					".tekton/pr.yaml":        pullRequestYaml,
+					".tekton/push/push.yaml": pushYaml,
+					".tekton/README.md":      "not a pipelinerun",
+				},
+				ChangedFiles: []string{".tekton/pr.yaml", ".tekton/push/push.yaml"},
+			},
+		},
+		PullRequests: map[int]*PullRequest{
+			1: {
+				Title:        "Add the pipelines",
+				Author:       "contributor",
+				HeadBranch:   "feature",
+				HeadSHA:      "featuresha",
+				BaseBranch:   "main",
+				ChangedFiles: []string{".tekton/pr.yaml", ".tekton/push/push.yaml"},
+			},
+		},
+		Collaborators: []Collaborator{
+			{Login: "admin", ID: 1, Permission: PermissionAdmin},
+			{Login: "contributor", ID: 2, Permission: PermissionRead},
+		},
+	})
+	return srv
+}
+
+func newEvent(srv *Server) *info.Event {
+	event := info.NewEvent()
+	event.Organization = "owner"
+	event.Repository = "repo"
+	event.DefaultBranch = "main"
+	event.BaseBranch = "main"
+	event.HeadBranch = "feature"
+	event.SHA = "featuresha"
+	event.EventType = "pull_request"
+	event.TriggerTarget = "pull_request"
+	event.PullRequestNumber = 1
+	event.Sender = "admin"
+	event.Provider = &info.Provider{URL: srv.URL, Token: "token"}
+	return event
+}
+
+func TestGitHub(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	ctx, _ := rtesting.SetupFakeContext(t)
+	pacopts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "CI"}}
+
+	gprovider := &github.Provider{}
+	gprovider.SetLogger(zap.NewNop().Sugar())
+	event := newEvent(srv)
+	event.SHA = ""
+	assert.NilError(t, gprovider.SetClient(ctx, &params.Run{}, event))
+
+	assert.NilError(t, gprovider.GetCommitInfo(ctx, event))
+	assert.Equal(t, event.SHA, "featuresha")
+	assert.Equal(t, event.SHATitle, "Add the pipelines")
+
+	tektonDir, err := gprovider.GetTektonDir(ctx, event, ".tekton")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(tektonDir, "name: pull-request"), tektonDir)
+	assert.Assert(t, strings.Contains(tektonDir, "name: push"), tektonDir)
+	assert.Assert(t, !strings.Contains(tektonDir, "not a pipelinerun"), tektonDir)
+
+	readme, err := gprovider.GetFileInsideRepo(ctx, event, "README.md", "")
+	assert.NilError(t, err)
+	assert.Equal(t, readme, "hello")
+	_, err = gprovider.GetFileInsideRepo(ctx, event, "OWNERS", "")
+	assert.ErrorContains(t, err, "404")
+
+	files, err := gprovider.GetFiles(ctx, event)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, files, []string{".tekton/pr.yaml", ".tekton/push/push.yaml"})
+
+	allowed, err := gprovider.IsAllowed(ctx, event)
+	assert.NilError(t, err)
+	assert.Assert(t, allowed)
+	stranger := newEvent(srv)
+	stranger.Sender = "stranger"
+	allowed, err = gprovider.IsAllowed(ctx, stranger)
+	assert.NilError(t, err)
+	assert.Assert(t, !allowed)
+
+	// without a github app the statuses and the comments are used
+	assert.NilError(t, gprovider.CreateStatus(ctx, nil, event, pacopts, provider.StatusOpts{
+		Status:                  "completed",
+		Conclusion:              "success",
+		Text:                    "all good",
+		DetailsURL:              "https://logs/pull-request",
+		OriginalPipelineRunName: "pull-request",
+	}))
+	statuses := srv.Statuses("owner", "repo")
+	assert.Equal(t, len(statuses), 1)
+	assert.DeepEqual(t, statuses[0], Status{
+		SHA: "featuresha", State: "success", Context: "CI / pull-request",
+		Description: "Success", TargetURL: "https://logs/pull-request",
+	})
+	comments := srv.Comments("owner", "repo", 1)
+	assert.Equal(t, len(comments), 1)
+	assert.Assert(t, strings.Contains(comments[0].Body, "all good"), comments[0].Body)
+	assert.Equal(t, comments[0].Author, srv.User)
+
+	// with a github app the check run is created then updated
+	event.InstallationID = 1
+	for _, status := range []provider.StatusOpts{
+		{Status: "in_progress", Conclusion: "pending", PipelineRunName: "pull-request-abcde", OriginalPipelineRunName: "pull-request"},
+		{Status: "completed", Conclusion: "failure", Text: "oops", PipelineRunName: "pull-request-abcde", OriginalPipelineRunName: "pull-request"},
+	} {
+		assert.NilError(t, gprovider.CreateStatus(ctx, nil, event, pacopts, status))
+	}
+	checkRuns := srv.CheckRuns("owner", "repo")
+	assert.Equal(t, len(checkRuns), 1)
+	assert.Equal(t, checkRuns[0].Name, "CI / pull-request")
+	assert.Equal(t, checkRuns[0].ExternalID, "pull-request-abcde")
+	assert.Equal(t, checkRuns[0].Status, "completed")
+	assert.Equal(t, checkRuns[0].Conclusion, "failure")
+	assert.Equal(t, checkRuns[0].Text, "oops")
+}
+
+func TestGitLab(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	ctx, _ := rtesting.SetupFakeContext(t)
+	pacopts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "CI"}}
+
+	gprovider := &gitlab.Provider{}
+	gprovider.SetLogger(zap.NewNop().Sugar())
+	event := newEvent(srv)
+	event.SHA = ""
+	assert.NilError(t, gprovider.SetClient(ctx, &params.Run{}, event))
+	assert.Equal(t, event.SourceProjectID, 1)
+
+	assert.NilError(t, gprovider.GetCommitInfo(ctx, event))
+	assert.Equal(t, event.SHA, "featuresha")
+	assert.Equal(t, event.SHATitle, "Add the pipelines")
+
+	tektonDir, err := gprovider.GetTektonDir(ctx, event, ".tekton")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(tektonDir, "name: pull-request"), tektonDir)
+	assert.Assert(t, strings.Contains(tektonDir, "name: push"), tektonDir)
+
+	files, err := gprovider.GetFiles(ctx, event)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, files, []string{".tekton/pr.yaml", ".tekton/push/push.yaml"})
+
+	// the note of a status is updated by the next one
+	for _, status := range []provider.StatusOpts{
+		{Status: "in_progress", Conclusion: "pending", DetailsURL: "https://logs/pull-request", OriginalPipelineRunName: "pull-request"},
+		{Status: "completed", Conclusion: "success", Text: "all good", DetailsURL: "https://logs/pull-request", OriginalPipelineRunName: "pull-request"},
+	} {
+		assert.NilError(t, gprovider.CreateStatus(ctx, nil, event, pacopts, status))
+	}
+	statuses := srv.Statuses("owner", "repo")
+	assert.Equal(t, len(statuses), 2)
+	assert.Equal(t, statuses[0].State, "running")
+	assert.Equal(t, statuses[1].State, "success")
+	assert.Equal(t, statuses[1].Context, "CI / pull-request")
+	comments := srv.Comments("owner", "repo", 1)
+	assert.Equal(t, len(comments), 1)
+	assert.Assert(t, strings.Contains(comments[0].Body, "all good"), comments[0].Body)
+}
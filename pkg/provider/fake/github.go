@@ -0,0 +1,386 @@
+package fake
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// githubVersion is the version of GitHub Enterprise reported by the
+// simulator, it supports all the features used by Pipelines as Code.
+const githubVersion = "3.10.0"
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func githubError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"message": message})
+}
+
+// serveGitHub serves the GitHub API, the path is relative to /api/v3.
+func (s *Server) serveGitHub(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "meta":
+		writeJSON(w, http.StatusOK, map[string]string{"installed_version": githubVersion})
+		return
+	case len(parts) == 3 && parts[0] == "orgs" && parts[2] == "members":
+		// the members of the organizations are not simulated, the
+		// collaborators of the repositories are
+		writeJSON(w, http.StatusOK, []interface{}{})
+		return
+	case len(parts) < 3 || parts[0] != "repos":
+		githubError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	repo := s.repository(parts[1], parts[2])
+	if repo == nil {
+		githubError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	s.serveGitHubRepository(w, r, repo, parts[3:])
+}
+
+func (s *Server) serveGitHubRepository(w http.ResponseWriter, r *http.Request, repo *repositoryState, parts []string) {
+	route := strings.Join(parts, "/")
+	get := r.Method == http.MethodGet
+	switch {
+	case route == "" && get:
+		writeJSON(w, http.StatusOK, githubRepository(repo))
+	case len(parts) == 2 && parts[0] == "branches" && get:
+		sha, commit := repo.commit(parts[1])
+		if commit == nil {
+			githubError(w, http.StatusNotFound, "Branch not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"name":      parts[1],
+			"protected": repo.isProtected(parts[1]),
+			"commit":    map[string]interface{}{"sha": sha},
+		})
+	case len(parts) == 3 && parts[0] == "git" && parts[1] == "commits" && get:
+		sha, commit := repo.commit(parts[2])
+		if commit == nil {
+			githubError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"sha":      sha,
+			"message":  commit.Message,
+			"html_url": fmt.Sprintf("%s/%s/%s/commit/%s", s.URL, repo.Organization, repo.Name, sha),
+		})
+	case len(parts) == 2 && parts[0] == "commits" && get:
+		sha, commit := repo.commit(parts[1])
+		if commit == nil {
+			githubError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"sha":      sha,
+			"html_url": fmt.Sprintf("%s/%s/%s/commit/%s", s.URL, repo.Organization, repo.Name, sha),
+			"commit":   map[string]interface{}{"message": commit.Message},
+			"files":    githubFiles(commit.ChangedFiles),
+		})
+	case len(parts) == 3 && parts[0] == "git" && parts[1] == "trees" && get:
+		s.serveGitHubTree(w, r, repo, parts[2])
+	case len(parts) == 3 && parts[0] == "git" && parts[1] == "blobs" && get:
+		sha, file, ok := parseObjectSHA(parts[2])
+		if !ok {
+			githubError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		content, ok := repo.Commits[sha].file(file)
+		if !ok {
+			githubError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"sha":      parts[2],
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+		})
+	case len(parts) >= 2 && parts[0] == "contents" && get:
+		s.serveGitHubContents(w, r, repo, strings.Join(parts[1:], "/"))
+	case route == "check-runs" && r.Method == http.MethodPost:
+		checkRun := &CheckRun{ID: s.nextID()}
+		if !decodeCheckRun(w, r, checkRun) {
+			return
+		}
+		repo.checkRuns = append(repo.checkRuns, checkRun)
+		writeJSON(w, http.StatusCreated, githubCheckRun(checkRun))
+	case len(parts) == 2 && parts[0] == "check-runs" && r.Method == http.MethodPatch:
+		id, _ := strconv.ParseInt(parts[1], 10, 64)
+		for _, checkRun := range repo.checkRuns {
+			if checkRun.ID == id {
+				if decodeCheckRun(w, r, checkRun) {
+					writeJSON(w, http.StatusOK, githubCheckRun(checkRun))
+				}
+				return
+			}
+		}
+		githubError(w, http.StatusNotFound, "Not Found")
+	case len(parts) == 3 && parts[0] == "commits" && parts[2] == "check-runs" && get:
+		sha := repo.resolve(parts[1])
+		checkRuns := []interface{}{}
+		for _, checkRun := range repo.checkRuns {
+			if checkRun.SHA == sha {
+				checkRuns = append(checkRuns, githubCheckRun(checkRun))
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"total_count": len(checkRuns), "check_runs": checkRuns})
+	case len(parts) == 2 && parts[0] == "statuses" && r.Method == http.MethodPost:
+		status := struct {
+			State       string `json:"state"`
+			TargetURL   string `json:"target_url"`
+			Description string `json:"description"`
+			Context     string `json:"context"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&status); err != nil {
+			githubError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		repo.statuses = append(repo.statuses, &Status{
+			SHA: parts[1], State: status.State, Context: status.Context,
+			Description: status.Description, TargetURL: status.TargetURL,
+		})
+		writeJSON(w, http.StatusCreated, status)
+	case len(parts) == 3 && parts[0] == "issues" && parts[2] == "comments":
+		s.serveGitHubComments(w, r, repo, parts[1])
+	case len(parts) == 2 && parts[0] == "pulls" && get:
+		number, _ := strconv.Atoi(parts[1])
+		pr, ok := repo.PullRequests[number]
+		if !ok {
+			githubError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		writeJSON(w, http.StatusOK, s.githubPullRequest(repo, number, pr))
+	case len(parts) == 3 && parts[0] == "pulls" && parts[2] == "files" && get:
+		number, _ := strconv.Atoi(parts[1])
+		pr, ok := repo.PullRequests[number]
+		if !ok {
+			githubError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		writeJSON(w, http.StatusOK, githubFiles(pr.ChangedFiles))
+	case route == "collaborators" && get:
+		users := []interface{}{}
+		for _, collaborator := range repo.Collaborators {
+			users = append(users, map[string]interface{}{"login": collaborator.Login, "id": collaborator.ID})
+		}
+		writeJSON(w, http.StatusOK, users)
+	case len(parts) == 3 && parts[0] == "collaborators" && parts[2] == "permission" && get:
+		permission := "none"
+		if collaborator := repo.collaborator(parts[1]); collaborator != nil {
+			permission = collaborator.Permission
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"permission": permission,
+			"user":       map[string]interface{}{"login": parts[1]},
+		})
+	default:
+		githubError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) serveGitHubTree(w http.ResponseWriter, r *http.Request, repo *repositoryState, object string) {
+	// the trees of the directories are encoded, the root is the commit
+	sha, dir, ok := parseObjectSHA(object)
+	if !ok {
+		sha, dir = repo.resolve(object), ""
+	}
+	commit := repo.Commits[sha]
+	if commit == nil || !commit.isDir(dir) {
+		githubError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	entries := []interface{}{}
+	for _, entry := range commit.tree(dir, r.URL.Query().Get("recursive") != "") {
+		entryType, mode := "blob", "100644"
+		if entry.isDir {
+			entryType, mode = "tree", "040000"
+		}
+		entries = append(entries, map[string]interface{}{
+			"path": entry.path,
+			"type": entryType,
+			"mode": mode,
+			"sha":  objectSHA(sha, path.Join(dir, entry.path)),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"sha": object, "tree": entries})
+}
+
+func (s *Server) serveGitHubContents(w http.ResponseWriter, r *http.Request, repo *repositoryState, file string) {
+	ref := r.URL.Query().Get("ref")
+	if ref == "" {
+		ref = repo.DefaultBranch
+	}
+	sha, commit := repo.commit(ref)
+	if _, ok := commit.file(file); ok {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"type": "file",
+			"name": path.Base(file),
+			"path": file,
+			"sha":  objectSHA(sha, file),
+		})
+		return
+	}
+	if commit == nil || !commit.isDir(file) {
+		githubError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	entries := []interface{}{}
+	for _, entry := range commit.tree(file, false) {
+		entryType := "file"
+		if entry.isDir {
+			entryType = "dir"
+		}
+		entries = append(entries, map[string]interface{}{
+			"type": entryType,
+			"name": entry.path,
+			"path": path.Join(file, entry.path),
+			"sha":  objectSHA(sha, path.Join(file, entry.path)),
+		})
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+func (s *Server) serveGitHubComments(w http.ResponseWriter, r *http.Request, repo *repositoryState, issue string) {
+	number, _ := strconv.Atoi(issue)
+	switch r.Method {
+	case http.MethodGet:
+		comments := []interface{}{}
+		for _, comment := range repo.comments {
+			if comment.Number == number {
+				comments = append(comments, githubComment(comment))
+			}
+		}
+		writeJSON(w, http.StatusOK, comments)
+	case http.MethodPost:
+		body := struct {
+			Body string `json:"body"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			githubError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		comment := &Comment{ID: s.nextID(), Number: number, Author: s.User, Body: body.Body}
+		repo.comments = append(repo.comments, comment)
+		writeJSON(w, http.StatusCreated, githubComment(comment))
+	default:
+		githubError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// decodeCheckRun updates a check run with the fields of a request creating
+// or updating it.
+func decodeCheckRun(w http.ResponseWriter, r *http.Request, checkRun *CheckRun) bool {
+	opts := struct {
+		Name       *string `json:"name"`
+		HeadSHA    *string `json:"head_sha"`
+		ExternalID *string `json:"external_id"`
+		Status     *string `json:"status"`
+		Conclusion *string `json:"conclusion"`
+		DetailsURL *string `json:"details_url"`
+		Output     *struct {
+			Title   *string `json:"title"`
+			Summary *string `json:"summary"`
+			Text    *string `json:"text"`
+		} `json:"output"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+		githubError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	set := func(field *string, value *string) {
+		if value != nil {
+			*field = *value
+		}
+	}
+	set(&checkRun.Name, opts.Name)
+	set(&checkRun.SHA, opts.HeadSHA)
+	set(&checkRun.ExternalID, opts.ExternalID)
+	set(&checkRun.Status, opts.Status)
+	set(&checkRun.Conclusion, opts.Conclusion)
+	set(&checkRun.DetailsURL, opts.DetailsURL)
+	if opts.Output != nil {
+		set(&checkRun.Title, opts.Output.Title)
+		set(&checkRun.Summary, opts.Output.Summary)
+		set(&checkRun.Text, opts.Output.Text)
+	}
+	if checkRun.Conclusion != "" {
+		checkRun.Status = "completed"
+	}
+	return true
+}
+
+func githubRepository(repo *repositoryState) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             repo.ID,
+		"name":           repo.Name,
+		"full_name":      repo.Organization + "/" + repo.Name,
+		"default_branch": repo.DefaultBranch,
+		"private":        repo.Private,
+		"owner":          map[string]interface{}{"login": repo.Organization},
+	}
+}
+
+func (s *Server) githubPullRequest(repo *repositoryState, number int, pr *PullRequest) map[string]interface{} {
+	labels := []interface{}{}
+	for _, label := range pr.Labels {
+		labels = append(labels, map[string]interface{}{"name": label})
+	}
+	htmlRepo := githubRepository(repo)
+	htmlRepo["html_url"] = fmt.Sprintf("%s/%s/%s", s.URL, repo.Organization, repo.Name)
+	return map[string]interface{}{
+		"number":   number,
+		"title":    pr.Title,
+		"html_url": fmt.Sprintf("%s/%s/%s/pull/%d", s.URL, repo.Organization, repo.Name, number),
+		"user":     map[string]interface{}{"login": pr.Author},
+		"labels":   labels,
+		"head":     map[string]interface{}{"ref": pr.HeadBranch, "sha": pr.HeadSHA, "repo": htmlRepo},
+		"base":     map[string]interface{}{"ref": pr.BaseBranch, "sha": repo.resolve(pr.BaseBranch), "repo": htmlRepo},
+	}
+}
+
+func githubCheckRun(checkRun *CheckRun) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          checkRun.ID,
+		"name":        checkRun.Name,
+		"head_sha":    checkRun.SHA,
+		"external_id": checkRun.ExternalID,
+		"status":      checkRun.Status,
+		"conclusion":  checkRun.Conclusion,
+		"details_url": checkRun.DetailsURL,
+		"output": map[string]interface{}{
+			"title":   checkRun.Title,
+			"summary": checkRun.Summary,
+			"text":    checkRun.Text,
+		},
+	}
+}
+
+func githubComment(comment *Comment) map[string]interface{} {
+	return map[string]interface{}{
+		"id":   comment.ID,
+		"body": comment.Body,
+		"user": map[string]interface{}{"login": comment.Author},
+	}
+}
+
+func githubFiles(files []string) []interface{} {
+	result := []interface{}{}
+	for _, file := range files {
+		result = append(result, map[string]interface{}{"filename": file})
+	}
+	return result
+}
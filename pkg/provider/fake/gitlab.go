@@ -0,0 +1,282 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// the access levels of the members of the GitLab projects
+const (
+	gitlabReporterAccess   = 20
+	gitlabDeveloperAccess  = 30
+	gitlabMaintainerAccess = 40
+)
+
+func gitlabError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"message": message})
+}
+
+// serveGitLab serves the GitLab API, the path is relative to /api/v4. The
+// projects are referenced by ID or by their escaped path.
+func (s *Server) serveGitLab(w http.ResponseWriter, r *http.Request) {
+	parts := []string{}
+	for _, part := range strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/") {
+		unescaped, err := url.PathUnescape(part)
+		if err != nil {
+			gitlabError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		parts = append(parts, unescaped)
+	}
+	if len(parts) < 2 || parts[0] != "projects" {
+		gitlabError(w, http.StatusNotFound, "404 Not Found")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var repo *repositoryState
+	if id, err := strconv.Atoi(parts[1]); err == nil {
+		repo = s.repositoryByID(id)
+	} else if org, name, ok := cutLast(parts[1], "/"); ok {
+		repo = s.repository(org, name)
+	}
+	if repo == nil {
+		gitlabError(w, http.StatusNotFound, "404 Project Not Found")
+		return
+	}
+	s.serveGitLabProject(w, r, repo, parts[2:])
+}
+
+func (s *Server) serveGitLabProject(w http.ResponseWriter, r *http.Request, repo *repositoryState, parts []string) {
+	get := r.Method == http.MethodGet
+	switch {
+	case len(parts) == 0 && get:
+		visibility := "public"
+		if repo.Private {
+			visibility = "private"
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id":                  repo.ID,
+			"path_with_namespace": repo.Organization + "/" + repo.Name,
+			"default_branch":      repo.DefaultBranch,
+			"web_url":             fmt.Sprintf("%s/%s/%s", s.URL, repo.Organization, repo.Name),
+			"visibility":          visibility,
+		})
+	case len(parts) == 2 && parts[0] == "repository" && parts[1] == "tree" && get:
+		s.serveGitLabTree(w, r, repo)
+	case len(parts) == 4 && parts[0] == "repository" && parts[1] == "files" && parts[3] == "raw" && get:
+		_, commit := repo.commit(r.URL.Query().Get("ref"))
+		content, ok := commit.file(parts[2])
+		if !ok {
+			gitlabError(w, http.StatusNotFound, "404 File Not Found")
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, content)
+	case len(parts) == 3 && parts[0] == "repository" && parts[1] == "commits" && get:
+		sha, commit := repo.commit(parts[2])
+		if commit == nil {
+			gitlabError(w, http.StatusNotFound, "404 Commit Not Found")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id":      sha,
+			"title":   strings.SplitN(commit.Message, "\n", 2)[0],
+			"message": commit.Message,
+			"web_url": fmt.Sprintf("%s/%s/%s/-/commit/%s", s.URL, repo.Organization, repo.Name, sha),
+		})
+	case len(parts) == 4 && parts[0] == "repository" && parts[1] == "commits" && parts[3] == "diff" && get:
+		_, commit := repo.commit(parts[2])
+		if commit == nil {
+			gitlabError(w, http.StatusNotFound, "404 Commit Not Found")
+			return
+		}
+		writeJSON(w, http.StatusOK, gitlabDiffs(commit.ChangedFiles))
+	case len(parts) == 3 && parts[0] == "repository" && parts[1] == "branches" && get:
+		sha, commit := repo.commit(parts[2])
+		if commit == nil {
+			gitlabError(w, http.StatusNotFound, "404 Branch Not Found")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"name":      parts[2],
+			"protected": repo.isProtected(parts[2]),
+			"commit":    map[string]interface{}{"id": sha},
+		})
+	case len(parts) == 1 && parts[0] == "protected_tags" && get:
+		writeJSON(w, http.StatusOK, []interface{}{})
+	case len(parts) == 2 && parts[0] == "statuses" && r.Method == http.MethodPost:
+		status := struct {
+			State       string `json:"state"`
+			Name        string `json:"name"`
+			TargetURL   string `json:"target_url"`
+			Description string `json:"description"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&status); err != nil {
+			gitlabError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		repo.statuses = append(repo.statuses, &Status{
+			SHA: parts[1], State: status.State, Context: status.Name,
+			Description: status.Description, TargetURL: status.TargetURL,
+		})
+		writeJSON(w, http.StatusCreated, status)
+	case len(parts) >= 3 && parts[0] == "merge_requests":
+		s.serveGitLabMergeRequest(w, r, repo, parts[1], parts[2:])
+	case len(parts) == 3 && parts[0] == "members" && parts[1] == "all" && get:
+		id, _ := strconv.Atoi(parts[2])
+		for _, collaborator := range repo.Collaborators {
+			if collaborator.ID == id {
+				writeJSON(w, http.StatusOK, map[string]interface{}{
+					"id":           collaborator.ID,
+					"username":     collaborator.Login,
+					"access_level": gitlabAccessLevel(collaborator.Permission),
+				})
+				return
+			}
+		}
+		gitlabError(w, http.StatusNotFound, "404 Not found")
+	default:
+		gitlabError(w, http.StatusNotFound, "404 Not Found")
+	}
+}
+
+func (s *Server) serveGitLabTree(w http.ResponseWriter, r *http.Request, repo *repositoryState) {
+	query := r.URL.Query()
+	ref := query.Get("ref")
+	if ref == "" {
+		ref = repo.DefaultBranch
+	}
+	dir := strings.Trim(query.Get("path"), "/")
+	_, commit := repo.commit(ref)
+	if !commit.isDir(dir) {
+		gitlabError(w, http.StatusNotFound, "404 Tree Not Found")
+		return
+	}
+	nodes := []interface{}{}
+	for _, entry := range commit.tree(dir, query.Get("recursive") == "true") {
+		nodeType, mode := "blob", "100644"
+		if entry.isDir {
+			nodeType, mode = "tree", "040000"
+		}
+		nodes = append(nodes, map[string]interface{}{
+			"name": path.Base(entry.path),
+			"path": path.Join(dir, entry.path),
+			"type": nodeType,
+			"mode": mode,
+		})
+	}
+	writeJSON(w, http.StatusOK, nodes)
+}
+
+func (s *Server) serveGitLabMergeRequest(w http.ResponseWriter, r *http.Request, repo *repositoryState, iid string, parts []string) {
+	number, _ := strconv.Atoi(iid)
+	mr, ok := repo.PullRequests[number]
+	if !ok {
+		gitlabError(w, http.StatusNotFound, "404 Merge Request Not Found")
+		return
+	}
+	switch {
+	case len(parts) == 1 && parts[0] == "diffs" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, gitlabDiffs(mr.ChangedFiles))
+	case len(parts) == 1 && parts[0] == "discussions" && r.Method == http.MethodGet:
+		discussions := []interface{}{}
+		for _, comment := range repo.comments {
+			if comment.Number == number {
+				discussions = append(discussions, map[string]interface{}{
+					"id":    strconv.FormatInt(comment.ID, 10),
+					"notes": []interface{}{s.gitlabNote(repo, comment)},
+				})
+			}
+		}
+		writeJSON(w, http.StatusOK, discussions)
+	case len(parts) == 1 && parts[0] == "notes" && r.Method == http.MethodGet:
+		notes := []interface{}{}
+		for _, comment := range repo.comments {
+			if comment.Number == number {
+				notes = append(notes, s.gitlabNote(repo, comment))
+			}
+		}
+		writeJSON(w, http.StatusOK, notes)
+	case len(parts) == 1 && parts[0] == "notes" && r.Method == http.MethodPost:
+		body, ok := decodeNote(w, r)
+		if !ok {
+			return
+		}
+		comment := &Comment{ID: s.nextID(), Number: number, Author: s.User, Body: body}
+		repo.comments = append(repo.comments, comment)
+		writeJSON(w, http.StatusCreated, s.gitlabNote(repo, comment))
+	case len(parts) == 2 && parts[0] == "notes" && r.Method == http.MethodPut:
+		id, _ := strconv.ParseInt(parts[1], 10, 64)
+		for _, comment := range repo.comments {
+			if comment.ID == id && comment.Number == number {
+				body, ok := decodeNote(w, r)
+				if !ok {
+					return
+				}
+				comment.Body = body
+				writeJSON(w, http.StatusOK, s.gitlabNote(repo, comment))
+				return
+			}
+		}
+		gitlabError(w, http.StatusNotFound, "404 Note Not Found")
+	default:
+		gitlabError(w, http.StatusNotFound, "404 Not Found")
+	}
+}
+
+func decodeNote(w http.ResponseWriter, r *http.Request) (string, bool) {
+	note := struct {
+		Body string `json:"body"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&note); err != nil {
+		gitlabError(w, http.StatusBadRequest, err.Error())
+		return "", false
+	}
+	return note.Body, true
+}
+
+func (s *Server) gitlabNote(repo *repositoryState, comment *Comment) map[string]interface{} {
+	author := map[string]interface{}{"username": comment.Author}
+	if collaborator := repo.collaborator(comment.Author); collaborator != nil {
+		author["id"] = collaborator.ID
+	}
+	return map[string]interface{}{
+		"id":     comment.ID,
+		"body":   comment.Body,
+		"author": author,
+	}
+}
+
+func gitlabAccessLevel(permission string) int {
+	switch permission {
+	case PermissionAdmin:
+		return gitlabMaintainerAccess
+	case PermissionWrite:
+		return gitlabDeveloperAccess
+	default:
+		return gitlabReporterAccess
+	}
+}
+
+func gitlabDiffs(files []string) []interface{} {
+	diffs := []interface{}{}
+	for _, file := range files {
+		diffs = append(diffs, map[string]interface{}{"old_path": file, "new_path": file})
+	}
+	return diffs
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (string, string, bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
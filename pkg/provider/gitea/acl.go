@@ -3,6 +3,7 @@ package gitea
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 
 	giteaStruct "code.gitea.io/gitea/modules/structs"
@@ -25,6 +26,36 @@ func (v *Provider) IsAllowed(ctx context.Context, event *info.Event) (bool, erro
 	return v.aclAllowedOkToTestFromAnOwner(ctx, event)
 }
 
+// IsMaintainer checks the sender owns the repository or is a member of one of
+// its teams with the admin or the owner permission. The sdk cannot get the
+// permission of the collaborators, they have to be in such a team.
+func (v *Provider) IsMaintainer(_ context.Context, event *info.Event) (bool, error) {
+	if event.Organization == event.Sender {
+		return true, nil
+	}
+	teams, resp, err := v.Client.GetRepoTeams(event.Organization, event.Repository)
+	if err != nil {
+		// the repositories of a user have no team
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, team := range teams {
+		if team.Permission != gitea.AccessModeAdmin && team.Permission != gitea.AccessModeOwner {
+			continue
+		}
+		_, resp, err := v.Client.GetTeamMember(team.ID, event.Sender)
+		if err == nil {
+			return true, nil
+		}
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
 // allowedOkToTestFromAnOwner Go over every comments in a pull request and check
 // if there is a /ok-to-test in there running an aclCheck again on the comment
 // Sender if she is an OWNER and then allow it to run CI.
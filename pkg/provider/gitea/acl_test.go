@@ -218,3 +218,88 @@ func TestAclCheckAll(t *testing.T) {
 		})
 	}
 }
+
+func TestIsMaintainer(t *testing.T) {
+	tests := []struct {
+		name    string
+		sender  string
+		teams   string
+		wantErr string
+		want    bool
+	}{
+		{
+			name:   "owner of the repository",
+			sender: "owner",
+			want:   true,
+		},
+		{
+			name:   "member of an admin team",
+			sender: "admin",
+			teams:  `[{"id": 1, "permission": "write"}, {"id": 2, "permission": "admin"}]`,
+			want:   true,
+		},
+		{
+			name:   "member of an owner team",
+			sender: "admin",
+			teams:  `[{"id": 3, "permission": "owner"}]`,
+			want:   true,
+		},
+		{
+			name:   "member of a write team",
+			sender: "writer",
+			teams:  `[{"id": 1, "permission": "write"}, {"id": 2, "permission": "admin"}]`,
+			want:   false,
+		},
+		{
+			name:   "repository of a user",
+			sender: "collaborator",
+			want:   false,
+		},
+		{
+			name:    "cannot get the teams",
+			sender:  "admin",
+			teams:   "error",
+			wantErr: "500",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeclient, mux, teardown := tgitea.Setup(t)
+			defer teardown()
+
+			mux.HandleFunc("/repos/owner/repo/teams", func(rw http.ResponseWriter, r *http.Request) {
+				switch tt.teams {
+				case "":
+					rw.WriteHeader(http.StatusNotFound)
+				case "error":
+					rw.WriteHeader(http.StatusInternalServerError)
+				default:
+					fmt.Fprint(rw, tt.teams)
+				}
+			})
+			for _, id := range []int{2, 3} {
+				id := id
+				mux.HandleFunc(fmt.Sprintf("/teams/%d/members/", id), func(rw http.ResponseWriter, r *http.Request) {
+					if r.URL.Path != fmt.Sprintf("/teams/%d/members/admin", id) {
+						rw.WriteHeader(http.StatusNotFound)
+						return
+					}
+					fmt.Fprint(rw, `{"login": "admin"}`)
+				})
+			}
+			mux.HandleFunc("/teams/1/members/", func(rw http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(rw, `{"login": "writer"}`)
+			})
+
+			ctx, _ := rtesting.SetupFakeContext(t)
+			gprovider := Provider{Client: fakeclient}
+			got, err := gprovider.IsMaintainer(ctx, &info.Event{Organization: "owner", Repository: "repo", Sender: tt.sender})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
@@ -14,16 +14,18 @@ import (
 // returns (if is a Gitea event, whether to process or reject, logger with event metadata,, error if any occurred)
 func (v *Provider) Detect(req *http.Request, payload string, logger *zap.SugaredLogger) (bool, bool, *zap.SugaredLogger, string, error) {
 	isGitea := false
-	event := req.Header.Get("X-Gitea-Event-Type")
+	event, forgejo := webhookHeader(req.Header, giteaEventTypeHeader, forgejoEventTypeHeader)
 	if event == "" {
 		return false, false, logger, "no gitea event", nil
 	}
 
 	isGitea = true
+	v.forgejo = forgejo
 	setLoggerAndProceed := func(processEvent bool, reason string, err error) (bool, bool, *zap.SugaredLogger,
 		string, error,
 	) {
-		logger = logger.With("provider", "gitea", "event-id", req.Header.Get("X-Gitea-Delivery"))
+		delivery, _ := webhookHeader(req.Header, giteaDeliveryHeader, forgejoDeliveryHeader)
+		logger = logger.With("provider", v.GetConfig().Name, "event-id", delivery)
 		return isGitea, processEvent, logger, reason, err
 	}
 
@@ -47,11 +49,14 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 			if provider.IsCancelComment(gitEvent.Comment.Body) {
 				return setLoggerAndProceed(true, "", nil)
 			}
+			if provider.IsHelpComment(gitEvent.Comment.Body) {
+				return setLoggerAndProceed(true, "", nil)
+			}
 			return setLoggerAndProceed(false, "", nil)
 		}
 		return setLoggerAndProceed(false, "not a issue comment we care about", nil)
 	case *giteastruct.PullRequestPayload:
-		if provider.Valid(string(gitEvent.Action), []string{"opened", "synchronize", "synchronized", "reopened"}) {
+		if provider.Valid(string(gitEvent.Action), []string{"opened", "synchronize", "synchronized", "reopened", "closed"}) {
 			return setLoggerAndProceed(true, "", nil)
 		}
 		return setLoggerAndProceed(false, fmt.Sprintf("not a merge event we care about: \"%s\"",
@@ -61,6 +66,11 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 			return setLoggerAndProceed(true, "", nil)
 		}
 		return setLoggerAndProceed(false, "push: no pusher in event", nil)
+	case *giteastruct.DeletePayload:
+		if gitEvent.RefType == "branch" {
+			return setLoggerAndProceed(true, "", nil)
+		}
+		return setLoggerAndProceed(false, fmt.Sprintf("delete: unsupported ref type \"%s\"", gitEvent.RefType), nil)
 	default:
 		return setLoggerAndProceed(false, "", fmt.Errorf("gitea: event \"%s\" is not supported", event))
 	}
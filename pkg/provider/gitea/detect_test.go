@@ -22,6 +22,7 @@ func TestProviderDetect(t *testing.T) {
 		wantErrSubstr string
 		isGitea       bool
 		processEvent  bool
+		forgejo       bool
 	}{
 		{
 			name:       "bad/test not a gitea request",
@@ -112,6 +113,47 @@ func TestProviderDetect(t *testing.T) {
 			isGitea:      true,
 			processEvent: true,
 		},
+		{
+			name: "good/forgejo push",
+			args: args{
+				req: &http.Request{
+					Header: http.Header{
+						"X-Forgejo-Event-Type": []string{"push"},
+					},
+				},
+				payload: `{"pusher": {"id": 1}}`,
+			},
+			isGitea:      true,
+			processEvent: true,
+			forgejo:      true,
+		},
+		{
+			name: "good/branch deleted",
+			args: args{
+				req: &http.Request{
+					Header: http.Header{
+						"X-Gitea-Event-Type": []string{"delete"},
+					},
+				},
+				payload: `{"ref": "feature", "ref_type": "branch"}`,
+			},
+			isGitea:      true,
+			processEvent: true,
+		},
+		{
+			name: "bad/tag deleted",
+			args: args{
+				req: &http.Request{
+					Header: http.Header{
+						"X-Gitea-Event-Type": []string{"delete"},
+					},
+				},
+				payload: `{"ref": "v1", "ref_type": "tag"}`,
+			},
+			isGitea:      true,
+			processEvent: false,
+			wantReason:   `delete: unsupported ref type "tag"`,
+		},
 		{
 			name: "good/retest comment",
 			args: args{
@@ -204,6 +246,7 @@ func TestProviderDetect(t *testing.T) {
 			assert.NilError(t, err)
 			assert.Assert(t, isGitea == tt.isGitea)
 			assert.Assert(t, processEvent == tt.processEvent)
+			assert.Equal(t, v.forgejo, tt.forgejo)
 		})
 	}
 }
@@ -0,0 +1,120 @@
+package gitea
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+const (
+	providerForgejo = "forgejo"
+
+	giteaEventTypeHeader   = "X-Gitea-Event-Type"
+	forgejoEventTypeHeader = "X-Forgejo-Event-Type"
+	giteaDeliveryHeader    = "X-Gitea-Delivery"
+	forgejoDeliveryHeader  = "X-Forgejo-Delivery"
+	giteaSignatureHeader   = "X-Gitea-Signature"
+	forgejoSignatureHeader = "X-Forgejo-Signature"
+
+	// forgejoGiteaVersionSep separates the version of Forgejo from the
+	// version of Gitea its API is compatible with, ie: 7.0.0+gitea-1.21.0
+	forgejoGiteaVersionSep = "+gitea-"
+
+	// serverVersionTTL is how long we keep the version of a server before
+	// asking it again, it only changes on upgrades
+	serverVersionTTL = time.Hour
+)
+
+// webhookHeader returns the value of a header of a webhook, Forgejo sends
+// its own headers and keeps sending the Gitea ones for compatibility, and if
+// it has come from Forgejo.
+func webhookHeader(header http.Header, giteaName, forgejoName string) (string, bool) {
+	if value := header.Get(forgejoName); value != "" {
+		return value, true
+	}
+	return header.Get(giteaName), false
+}
+
+// serverVersion is what a Gitea or Forgejo server reports about itself.
+type serverVersion struct {
+	forgejo bool
+	// version is the version of Gitea or Forgejo
+	version string
+	// giteaVersion is the version of Gitea the API is compatible with, empty
+	// when a Forgejo server doesn't say
+	giteaVersion string
+}
+
+// parseServerVersion parses the version reported by the server, Forgejo
+// reports its own version with the version of Gitea it has been forked from
+// while the Gitea SDK expects a Gitea version to find out which API it can
+// use.
+func parseServerVersion(raw string) serverVersion {
+	raw = strings.TrimSpace(raw)
+	if i := strings.Index(raw, forgejoGiteaVersionSep); i >= 0 {
+		return serverVersion{
+			forgejo:      true,
+			version:      raw[:i],
+			giteaVersion: raw[i+len(forgejoGiteaVersionSep):],
+		}
+	}
+	return serverVersion{version: raw, giteaVersion: raw}
+}
+
+type cachedVersion struct {
+	// version is nil when it cannot be detected
+	version *serverVersion
+	checked time.Time
+}
+
+// serverVersions caches the version of the servers by API URL, a provider is
+// created for every event.
+var serverVersions = struct {
+	sync.Mutex
+	versions map[string]cachedVersion
+}{versions: map[string]cachedVersion{}}
+
+// detectServerVersion returns the version of the server at an API URL, asked
+// with the options of the client, nil when it cannot be detected and the
+// Gitea SDK has to ask for it again.
+func (v *Provider) detectServerVersion(apiURL string, options ...gitea.ClientOption) *serverVersion {
+	serverVersions.Lock()
+	defer serverVersions.Unlock()
+	if cached, ok := serverVersions.versions[apiURL]; ok && time.Since(cached.checked) < serverVersionTTL {
+		return cached.version
+	}
+
+	cached := cachedVersion{checked: time.Now()}
+	client, err := gitea.NewClient(apiURL, append(options, gitea.SetGiteaVersion(""))...)
+	if err == nil {
+		var raw string
+		if raw, _, err = client.ServerVersion(); err == nil {
+			version := parseServerVersion(raw)
+			cached.version = &version
+		}
+	}
+	switch {
+	case v.Logger == nil:
+	case err != nil:
+		v.Logger.Warnf("cannot detect the version of the gitea server %s: %v", apiURL, err)
+	case cached.version.forgejo:
+		v.Logger.Debugf("%s is forgejo at version %s, compatible with gitea %s", apiURL, cached.version.version, cached.version.giteaVersion)
+	}
+	serverVersions.versions[apiURL] = cached
+	return cached.version
+}
+
+// versionOption returns the option telling the Gitea SDK the version of the
+// server, so it picks the API calls a Forgejo server supports from the version
+// of Gitea it is compatible with rather than from the version of Forgejo.
+func versionOption(version *serverVersion) []gitea.ClientOption {
+	if version == nil {
+		return nil
+	}
+	// an empty version makes the SDK assume the latest API, which Forgejo
+	// keeps up with
+	return []gitea.ClientOption{gitea.SetGiteaVersion(version.giteaVersion)}
+}
@@ -0,0 +1,113 @@
+package gitea
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	assert.Equal(t, parseServerVersion("1.19.0"), serverVersion{version: "1.19.0", giteaVersion: "1.19.0"})
+	assert.Equal(t, parseServerVersion("7.0.0+gitea-1.21.0"),
+		serverVersion{forgejo: true, version: "7.0.0", giteaVersion: "1.21.0"})
+	assert.Equal(t, parseServerVersion("9.0.0-dev-1234-abcdef+gitea-1.22.0\n"),
+		serverVersion{forgejo: true, version: "9.0.0-dev-1234-abcdef", giteaVersion: "1.22.0"})
+}
+
+func TestSetClientForgejo(t *testing.T) {
+	versionCalls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/version", func(w http.ResponseWriter, r *http.Request) {
+		versionCalls++
+		assert.Equal(t, r.Header.Get("Authorization"), "token secret")
+		fmt.Fprint(w, `{"version": "7.0.0+gitea-1.21.0"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		v := &Provider{}
+		v.Logger, _ = logger.GetLogger()
+		event := &info.Event{Provider: &info.Provider{URL: server.URL, Token: "secret"}}
+		assert.NilError(t, v.SetClient(context.TODO(), nil, event))
+		assert.Equal(t, v.GetConfig().Name, "forgejo")
+		assert.NilError(t, v.Client.CheckServerVersionConstraint(">= 1.21.0, < 1.22.0"))
+	}
+	assert.Equal(t, versionCalls, 1, "the version of the server should be cached")
+}
+
+func TestValidate(t *testing.T) {
+	payload := []byte(`{"action": "opened"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	_, _ = mac.Write(payload)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name          string
+		header        string
+		signature     string
+		secret        string
+		wantErrSubstr string
+	}{
+		{
+			name: "no secret no signature",
+		},
+		{
+			name:      "gitea signature",
+			header:    giteaSignatureHeader,
+			signature: signature,
+			secret:    "secret",
+		},
+		{
+			name:      "forgejo signature",
+			header:    forgejoSignatureHeader,
+			signature: signature,
+			secret:    "secret",
+		},
+		{
+			name:          "bad signature",
+			header:        forgejoSignatureHeader,
+			signature:     signature,
+			secret:        "other",
+			wantErrSubstr: "doesn't match",
+		},
+		{
+			name:          "no signature",
+			secret:        "secret",
+			wantErrSubstr: "no signature",
+		},
+		{
+			name:          "no secret",
+			header:        giteaSignatureHeader,
+			signature:     signature,
+			wantErrSubstr: "failed to find webhook secret",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Provider{}
+			v.Logger, _ = logger.GetLogger()
+			event := info.NewEvent()
+			event.Request = &info.Request{Header: http.Header{}, Payload: payload}
+			if tt.header != "" {
+				event.Request.Header.Set(tt.header, tt.signature)
+			}
+			event.Provider = &info.Provider{WebhookSecret: tt.secret}
+			err := v.Validate(context.TODO(), nil, event)
+			if tt.wantErrSubstr != "" {
+				assert.ErrorContains(t, err, tt.wantErrSubstr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
@@ -2,7 +2,11 @@ package gitea
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"path"
 	"strconv"
@@ -39,6 +43,8 @@ type Provider struct {
 	Logger           *zap.SugaredLogger
 	Token            *string
 	giteaInstanceURL string
+	// forgejo is set when the webhook or the server is from Forgejo
+	forgejo bool
 	// only exposed for e2e tests
 	Password string
 }
@@ -48,13 +54,35 @@ func (v *Provider) GetTaskURI(ctx context.Context, params *params.Run, event *in
 	return false, "", nil
 }
 
+// IsRefProtected TODO: Implement ME
+func (v *Provider) IsRefProtected(ctx context.Context, event *info.Event, ref string) (bool, error) {
+	return false, nil
+}
+
 func (v *Provider) SetLogger(logger *zap.SugaredLogger) {
 	v.Logger = logger
 }
 
+// Validate checks the signature of the webhook, the hex encoded HMAC SHA256
+// of the payload with the webhook secret, as sent by Gitea and Forgejo when
+// the webhook has a secret.
 func (v *Provider) Validate(_ context.Context, _ *params.Run, event *info.Event) error {
-	// TODO: figure out why gitea doesn't work with mac validation as github which seems to be the same
-	v.Logger.Debug("no secret and signature found, skipping validation for gitea")
+	signature, _ := webhookHeader(event.Request.Header, giteaSignatureHeader, forgejoSignatureHeader)
+	if signature == "" && event.Provider.WebhookSecret == "" {
+		v.Logger.Debug("no secret and signature found, skipping validation for gitea")
+		return nil
+	}
+	if signature == "" {
+		return fmt.Errorf("gitea failed validation: no signature has been sent with the webhook while the repository has a webhook secret")
+	}
+	if event.Provider.WebhookSecret == "" {
+		return fmt.Errorf("gitea failed validation: failed to find webhook secret")
+	}
+	mac := hmac.New(sha256.New, []byte(event.Provider.WebhookSecret))
+	_, _ = mac.Write(event.Request.Payload)
+	if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(strings.ToLower(signature))) == 0 {
+		return fmt.Errorf("gitea failed validation: the signature of the event doesn't match with the webhook secret")
+	}
 	return nil
 }
 
@@ -67,30 +95,40 @@ func convertPullRequestURLtoNumber(pullRequest string) (int, error) {
 }
 
 func (v *Provider) GetConfig() *info.ProviderConfig {
+	name := "gitea"
+	if v.forgejo {
+		name = providerForgejo
+	}
 	return &info.ProviderConfig{
 		TaskStatusTMPL: taskStatusTemplate,
 		APIURL:         v.giteaInstanceURL,
-		Name:           "gitea",
+		Name:           name,
 		SkipEmoji:      true,
 	}
 }
 
 func (v *Provider) SetClient(_ context.Context, run *params.Run, runevent *info.Event) error {
-	var err error
+	var options []gitea.ClientOption
 	apiURL := runevent.Provider.URL
 	// password is not exposed to CRD, it's only used from the e2e tests
 	if v.Password != "" && runevent.Provider.User != "" {
-		v.Client, err = gitea.NewClient(apiURL, gitea.SetBasicAuth(runevent.Provider.User, v.Password))
+		options = append(options, gitea.SetBasicAuth(runevent.Provider.User, v.Password))
 	} else {
 		if runevent.Provider.Token == "" {
 			return fmt.Errorf("no git_provider.secret has been set in the repo crd")
 		}
-		v.Client, err = gitea.NewClient(apiURL, gitea.SetToken(runevent.Provider.Token))
+		options = append(options, gitea.SetToken(runevent.Provider.Token))
 	}
+	version := v.detectServerVersion(apiURL, options...)
+	client, err := gitea.NewClient(apiURL, append(options, versionOption(version)...)...)
 	if err != nil {
 		return err
 	}
+	v.Client = client
 	v.giteaInstanceURL = runevent.Provider.URL
+	if version != nil && version.forgejo {
+		v.forgejo = true
+	}
 	return nil
 }
 
@@ -130,6 +168,12 @@ func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event
 	return v.createStatusCommit(event, pacOpts, statusOpts)
 }
 
+// CreateStatuses sets the last commit status of each PipelineRun of a batch,
+// Gitea takes them one at a time
+func (v *Provider) CreateStatuses(ctx context.Context, tekton versioned.Interface, event *info.Event, opts *info.PacOpts, statuses []provider.StatusOpts) error {
+	return provider.CreateStatuses(ctx, v, tekton, event, opts, statuses)
+}
+
 func (v *Provider) createStatusCommit(event *info.Event, pacopts *info.PacOpts, status provider.StatusOpts) error {
 	state := gitea.StatusState(status.Conclusion)
 	switch status.Conclusion {
@@ -146,11 +190,13 @@ func (v *Provider) createStatusCommit(event *info.Event, pacopts *info.PacOpts,
 		Description: status.Title,
 		Context:     getCheckName(status, pacopts),
 	}
-	if _, _, err := v.Client.CreateStatus(event.Organization, event.Repository, event.SHA, gStatus); err != nil {
-		return err
+	if !pacopts.DisableCommitStatuses {
+		if _, _, err := v.Client.CreateStatus(event.Organization, event.Repository, event.SHA, gStatus); err != nil {
+			return err
+		}
 	}
 
-	if status.Text != "" && event.EventType == "pull_request" {
+	if !pacopts.DisablePullRequestComments && status.Text != "" && event.EventType == "pull_request" {
 		status.Text = strings.ReplaceAll(strings.TrimSpace(status.Text), "<br>", "\n")
 		_, _, err := v.Client.CreateIssueComment(event.Organization, event.Repository,
 			int64(event.PullRequestNumber), gitea.CreateIssueCommentOption{
@@ -176,17 +222,25 @@ func getCheckName(status provider.StatusOpts, pacopts *info.PacOpts) string {
 }
 
 func (v *Provider) GetTektonDir(_ context.Context, event *info.Event, path string) (string, error) {
-	tektonDirSha := ""
-	rootobjects, _, err := v.Client.GetTrees(event.Organization, event.Repository, event.SHA, false)
-	if err != nil {
-		return "", err
-	}
-	for _, object := range rootobjects.Entries {
-		if object.Path == path {
-			if object.Type != "tree" {
-				return "", fmt.Errorf("%s has been found but is not a directory", path)
+	// walk down the trees to the directory, it may be in a sub directory
+	// of the repository
+	tektonDirSha := event.SHA
+	for _, name := range strings.Split(strings.Trim(path, "/"), "/") {
+		objects, _, err := v.Client.GetTrees(event.Organization, event.Repository, tektonDirSha, false)
+		if err != nil {
+			return "", err
+		}
+		tektonDirSha = ""
+		for _, object := range objects.Entries {
+			if object.Path == name {
+				if object.Type != "tree" {
+					return "", fmt.Errorf("%s has been found but is not a directory", path)
+				}
+				tektonDirSha = object.SHA
 			}
-			tektonDirSha = object.SHA
+		}
+		if tektonDirSha == "" {
+			break
 		}
 	}
 
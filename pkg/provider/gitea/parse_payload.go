@@ -18,10 +18,11 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 	// TODO: parse request to figure out which event
 	var processedEvent *info.Event
 
-	eventType := request.Header.Get("X-Gitea-Event-Type")
+	eventType, forgejo := webhookHeader(request.Header, giteaEventTypeHeader, forgejoEventTypeHeader)
 	if eventType == "" {
 		return nil, fmt.Errorf("failed to find event type in request header")
 	}
+	v.forgejo = forgejo
 
 	payloadB := []byte(payload)
 	eventInt, err := parseWebhook(whEventType(eventType), payloadB)
@@ -43,10 +44,17 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.BaseBranch = gitEvent.PullRequest.Base.Ref
 		processedEvent.PullRequestNumber = int(gitEvent.Index)
 		processedEvent.PullRequestTitle = gitEvent.PullRequest.Title
+		if gitEvent.PullRequest.Poster != nil {
+			processedEvent.PullRequestAuthor = gitEvent.PullRequest.Poster.UserName
+		}
+		for _, assignee := range gitEvent.PullRequest.Assignees {
+			processedEvent.PullRequestAssignees = append(processedEvent.PullRequestAssignees, assignee.UserName)
+		}
 		processedEvent.Organization = gitEvent.Repository.Owner.UserName
 		processedEvent.Repository = gitEvent.Repository.Name
 		processedEvent.TriggerTarget = "pull_request"
 		processedEvent.EventType = "pull_request"
+		processedEvent.CancelInProgress = gitEvent.Action == giteastruct.HookIssueClosed
 	case *giteastruct.PushPayload:
 		if len(gitEvent.Commits) == 0 {
 			return nil, fmt.Errorf("no commits attached to this push event")
@@ -67,6 +75,19 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.EventType = eventType
 		processedEvent.HeadBranch = processedEvent.BaseBranch // in push events Head Branch is the same as Basebranch
 		processedEvent.TriggerTarget = "push"
+	case *giteastruct.DeletePayload:
+		// the branch has been deleted, its ref is only the name of the branch
+		processedEvent = info.NewEvent()
+		processedEvent.Organization = gitEvent.Repo.Owner.UserName
+		processedEvent.Repository = gitEvent.Repo.Name
+		processedEvent.DefaultBranch = gitEvent.Repo.DefaultBranch
+		processedEvent.URL = gitEvent.Repo.HTMLURL
+		processedEvent.Sender = gitEvent.Sender.UserName
+		processedEvent.BaseBranch = "refs/heads/" + gitEvent.Ref
+		processedEvent.HeadBranch = processedEvent.BaseBranch
+		processedEvent.EventType = "push"
+		processedEvent.TriggerTarget = "push"
+		processedEvent.BranchDeleted = true
 	case *giteastruct.IssueCommentPayload:
 		if gitEvent.Issue.PullRequest == nil {
 			return info.NewEvent(), fmt.Errorf("issue comment is not coming from a pull_request")
@@ -77,9 +98,14 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.Sender = gitEvent.Sender.UserName
 		processedEvent.TriggerTarget = "pull_request"
 		processedEvent.EventType = "pull_request"
+		processedEvent.CommentCommand = provider.CommentCommand(gitEvent.Comment.Body)
+		if gitEvent.Issue.Poster != nil {
+			processedEvent.PullRequestAuthor = gitEvent.Issue.Poster.UserName
+		}
 
 		if provider.IsTestRetestComment(gitEvent.Comment.Body) {
 			processedEvent.TargetTestPipelineRun = provider.GetPipelineRunFromTestComment(gitEvent.Comment.Body)
+			processedEvent.TestParams = provider.GetParamsFromTestComment(gitEvent.Comment.Body)
 		}
 		if provider.IsCancelComment(gitEvent.Comment.Body) {
 			processedEvent.CancelPipelineRuns = true
@@ -96,5 +122,19 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 	}
 
 	processedEvent.Event = eventInt
+	processedEvent.RepositoryPrivate = isPrivateRepository(payloadB)
 	return processedEvent, nil
 }
+
+// isPrivateRepository returns whether the repository of the payload is private
+func isPrivateRepository(payload []byte) bool {
+	repo := struct {
+		Repository struct {
+			Private bool `json:"private"`
+		} `json:"repository"`
+	}{}
+	if err := json.Unmarshal(payload, &repo); err != nil {
+		return false
+	}
+	return repo.Repository.Private
+}
@@ -25,6 +25,22 @@ func (v *Provider) IsAllowed(ctx context.Context, event *info.Event) (bool, erro
 	return v.aclAllowedOkToTestFromAnOwner(ctx, event)
 }
 
+// IsMaintainer checks the sender is an admin or a maintainer of the
+// repository, the collaborators with the write permission are not.
+func (v *Provider) IsMaintainer(ctx context.Context, event *info.Event) (bool, error) {
+	if event.Organization == event.Sender {
+		return true, nil
+	}
+	level, resp, err := v.Client.Repositories.GetPermissionLevel(ctx, event.Organization, event.Repository, event.Sender)
+	if resp != nil && resp.Response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return level.GetPermission() == "admin" || level.GetUser().GetRoleName() == "maintain", nil
+}
+
 // allowedOkToTestFromAnOwner Go over every comments in a pull request and check
 // if there is a /ok-to-test in there running an aclCheck again on the comment
 // Sender if she is an OWNER and then allow it to run CI.
@@ -40,6 +56,10 @@ func (v *Provider) aclAllowedOkToTestFromAnOwner(ctx context.Context, event *inf
 
 	switch event := revent.Event.(type) {
 	case *github.IssueCommentEvent:
+		// an issue has no /ok-to-test
+		if !event.GetIssue().IsPullRequest() {
+			return false, nil
+		}
 		revent.URL = event.Issue.GetPullRequestLinks().GetHTMLURL()
 	case *github.PullRequestEvent:
 		revent.URL = event.GetPullRequest().GetHTMLURL()
@@ -114,6 +134,11 @@ func (v *Provider) checkSenderOrgMembership(ctx context.Context, runevent *info.
 	if resp != nil && resp.Response.StatusCode == http.StatusNotFound {
 		return false, nil
 	}
+	// a fine-grained token without access to the organization is refused,
+	// the sender is then checked as a collaborator of the repository
+	if resp != nil && resp.Response.StatusCode == http.StatusForbidden {
+		return false, nil
+	}
 
 	if err != nil {
 		return false, err
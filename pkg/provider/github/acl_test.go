@@ -146,6 +146,15 @@ func TestAclCheckAll(t *testing.T) {
 	repoOwnerFileAllowed := "repoOwnerAllowed"
 
 	errit := "err"
+	fineGrained := "fineGrained"
+
+	mux.HandleFunc("/orgs/"+fineGrained+"/public_members", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(rw, `{"message": "Resource not accessible by personal access token"}`)
+	})
+	mux.HandleFunc("/repos/"+fineGrained+"/repo/collaborators", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(rw, `[{"login": "%s"}]`, collaborator)
+	})
 
 	mux.HandleFunc("/orgs/"+orgallowed+"/public_members", func(rw http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(rw, `[{"login": "login_%s"}]`, orgallowed)
@@ -238,6 +247,16 @@ func TestAclCheckAll(t *testing.T) {
 			allowed: false,
 			wantErr: false,
 		},
+		{
+			name: "sender allowed as collaborator when the token cannot list the org members",
+			runevent: info.Event{
+				Organization: fineGrained,
+				Repository:   "repo",
+				Sender:       collaborator,
+			},
+			allowed: true,
+			wantErr: false,
+		},
 		{
 			name: "err it",
 			runevent: info.Event{
@@ -0,0 +1,21 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v49/github"
+)
+
+// VerifyCredentials checks GitHub accepts the credentials of the GitHub App,
+// the client needs to be authenticated as the app itself with NewAppClient.
+func VerifyCredentials(ctx context.Context, client *github.Client) error {
+	ghApp, _, err := client.Apps.Get(ctx, "")
+	if err != nil {
+		return fmt.Errorf("github has rejected the credentials of the github app: %w", err)
+	}
+	if ghApp.GetID() == 0 {
+		return fmt.Errorf("github has not returned the github app of the credentials")
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestVerifyCredentials(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    int
+		body    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			code: http.StatusOK,
+			body: `{"id": 12345, "slug": "pipelines-as-code"}`,
+		},
+		{
+			name:    "rejected",
+			code:    http.StatusUnauthorized,
+			body:    `{"message": "A JSON web token could not be decoded"}`,
+			wantErr: "github has rejected the credentials",
+		},
+		{
+			name:    "no app",
+			code:    http.StatusOK,
+			body:    `{}`,
+			wantErr: "github has not returned the github app",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			mux.HandleFunc("/app", func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(tt.code)
+				fmt.Fprint(rw, tt.body)
+			})
+			err := VerifyCredentials(ctx, fakeclient)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
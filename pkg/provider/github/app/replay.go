@@ -0,0 +1,75 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+)
+
+// NewAppClient return a client authenticated as the GitHub App itself and not
+// as one of its installation, the endpoints of the app like its webhook
+// deliveries need a JWT.
+func NewAppClient(ctx context.Context, run *params.Run) (*github.Client, error) {
+	jwtToken, err := generateJWT(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: jwtToken})
+	return github.NewClient(oauth2.NewClient(ctx, ts)), nil
+}
+
+// RedeliverMissedDeliveries ask GitHub to redeliver the webhook deliveries of
+// the app that failed since a time, ie: while the controller was down. The
+// deliveries are listed from the newest, a delivery already successfully
+// delivered or redelivered is not redelivered again. It returns the number of
+// redelivered deliveries.
+//
+// The unfinished deliveries are the GUID of the deliveries accepted by the
+// controller but not processed before it stopped, they are redelivered even if
+// they have been successfully delivered.
+func RedeliverMissedDeliveries(ctx context.Context, client *github.Client, since time.Time, unfinished []string, logger *zap.SugaredLogger) (int, error) {
+	replay := map[string]bool{}
+	for _, guid := range unfinished {
+		replay[guid] = true
+	}
+	delivered := map[string]bool{}
+	redelivered := 0
+	opts := &github.ListCursorOptions{PerPage: 100}
+	for {
+		deliveries, resp, err := client.Apps.ListHookDeliveries(ctx, opts)
+		if err != nil {
+			return redelivered, fmt.Errorf("failed to list the webhook deliveries of the github app: %w", err)
+		}
+		for _, delivery := range deliveries {
+			if delivery.GetDeliveredAt().Before(since) {
+				return redelivered, nil
+			}
+			if delivered[delivery.GetGUID()] {
+				continue
+			}
+			delivered[delivery.GetGUID()] = true
+			if code := delivery.GetStatusCode(); code >= 200 && code < 300 && !replay[delivery.GetGUID()] {
+				continue
+			}
+			// github answers with a 202 when the redelivery is scheduled
+			_, _, err := client.Apps.RedeliverHookDelivery(ctx, delivery.GetID())
+			var accepted *github.AcceptedError
+			if err != nil && !errors.As(err, &accepted) {
+				return redelivered, fmt.Errorf("failed to redeliver the webhook delivery %s: %w", delivery.GetGUID(), err)
+			}
+			logger.Infof("redelivered the missed %s webhook delivery %s from %s", delivery.GetEvent(), delivery.GetGUID(),
+				delivery.GetDeliveredAt().Format(time.RFC3339))
+			redelivered++
+		}
+		if resp.Cursor == "" {
+			return redelivered, nil
+		}
+		opts.Cursor = resp.Cursor
+	}
+}
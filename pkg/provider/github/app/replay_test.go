@@ -0,0 +1,75 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestRedeliverMissedDeliveries(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, serverURL, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	since := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) string {
+		return since.Add(time.Duration(minutes) * time.Minute).Format(time.RFC3339)
+	}
+
+	// newest first, the second page is after the cursor
+	mux.HandleFunc("/app/hook/deliveries", func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			rw.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/app/hook/deliveries?cursor=page2>; rel="next"`, serverURL))
+			fmt.Fprintf(rw, `[
+				{"id": 7, "guid": "unfinished", "status_code": 202, "event": "push", "delivered_at": %q},
+				{"id": 6, "guid": "redelivered", "status_code": 202, "event": "push", "delivered_at": %q},
+				{"id": 5, "guid": "missed-push", "status_code": 502, "event": "push", "delivered_at": %q}
+			]`, at(40), at(30), at(20))
+			return
+		}
+		fmt.Fprintf(rw, `[
+			{"id": 4, "guid": "missed-pr", "status_code": 0, "event": "pull_request", "delivered_at": %q},
+			{"id": 3, "guid": "redelivered", "status_code": 502, "event": "push", "delivered_at": %q},
+			{"id": 2, "guid": "delivered", "status_code": 202, "event": "push", "delivered_at": %q},
+			{"id": 1, "guid": "before-since", "status_code": 502, "event": "push", "delivered_at": %q}
+		]`, at(10), at(5), at(2), at(-1))
+	})
+	redelivered := []string{}
+	for _, id := range []string{"1", "2", "3", "4", "5", "6", "7"} {
+		id := id
+		mux.HandleFunc("/app/hook/deliveries/"+id+"/attempts", func(rw http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, r.Method, http.MethodPost)
+			redelivered = append(redelivered, id)
+			rw.WriteHeader(http.StatusAccepted)
+			fmt.Fprint(rw, `{}`)
+		})
+	}
+
+	got, err := RedeliverMissedDeliveries(ctx, fakeclient, since, []string{"unfinished"}, logger)
+	assert.NilError(t, err)
+	assert.Equal(t, got, 3)
+	assert.DeepEqual(t, redelivered, []string{"7", "5", "4"})
+}
+
+func TestRedeliverMissedDeliveriesError(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	mux.HandleFunc("/app/hook/deliveries", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusUnauthorized)
+	})
+	_, err := RedeliverMissedDeliveries(ctx, fakeclient, time.Now(), nil, logger)
+	assert.ErrorContains(t, err, "failed to list the webhook deliveries of the github app")
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -23,24 +24,18 @@ func GetAndUpdateInstallationID(ctx context.Context, req *http.Request, run *par
 		installationID       int64
 	)
 
-	jwtToken, err := generateJWT(ctx, run)
-	if err != nil {
-		return "", "", 0, err
-	}
-
 	installationURL := keys.APIURL + keys.InstallationURL
 	enterpriseURL = req.Header.Get("X-GitHub-Enterprise-Host")
 	if enterpriseURL != "" {
 		installationURL = enterpriseURL + keys.InstallationURL
 	}
 
-	res, err := getResponse(ctx, http.MethodGet, installationURL, jwtToken, run)
+	// the app configured for the host when the controller has multiple apps
+	ghApp, err := github.GetAppCredentials(ctx, run.Clients.Kube, 0, enterpriseURL)
 	if err != nil {
 		return "", "", 0, err
 	}
-
-	defer res.Body.Close()
-	data, err := io.ReadAll(res.Body)
+	data, err := getInstallations(ctx, installationURL, ghApp, run, gh)
 	if err != nil {
 		return "", "", 0, err
 	}
@@ -58,7 +53,7 @@ func GetAndUpdateInstallationID(ctx context.Context, req *http.Request, run *par
 			return "", "", 0, fmt.Errorf("installation ID is nil")
 		}
 		if *installationData[i].ID != 0 {
-			token, err = gh.GetAppToken(ctx, run.Clients.Kube, enterpriseURL, *installationData[i].ID)
+			token, err = gh.GetAppToken(ctx, run.Clients.Kube, enterpriseURL, ghApp.ID, *installationData[i].ID)
 			if err != nil {
 				return "", "", 0, err
 			}
@@ -74,6 +69,42 @@ func GetAndUpdateInstallationID(ctx context.Context, req *http.Request, run *par
 	return enterpriseURL, token, installationID, nil
 }
 
+// getInstallations lists the installations of the app with a JWT signed by
+// the private keys of its key ring in order, a key which cannot be parsed or
+// is rejected by GitHub is skipped for the next one.
+func getInstallations(ctx context.Context, installationURL string, ghApp *github.AppCredentials, run *params.Run, gh *github.Provider) ([]byte, error) {
+	if len(ghApp.PrivateKeys) == 0 {
+		return nil, fmt.Errorf("no private key for the github app %d in the secret %s", ghApp.ID, ghApp.SecretName)
+	}
+	var errs []string
+	for _, privateKey := range ghApp.PrivateKeys {
+		jwtToken, err := signJWT(ghApp.ID, privateKey.PEM)
+		if err != nil {
+			gh.RecordAppKey(ghApp.ID, privateKey.Name, false)
+			errs = append(errs, fmt.Sprintf("%s: %v", privateKey.Name, err))
+			continue
+		}
+
+		res, err := getResponse(ctx, http.MethodGet, installationURL, jwtToken, run)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode == http.StatusUnauthorized {
+			gh.RecordAppKey(ghApp.ID, privateKey.Name, false)
+			errs = append(errs, fmt.Sprintf("%s: rejected by github", privateKey.Name))
+			continue
+		}
+		gh.RecordAppKey(ghApp.ID, privateKey.Name, true)
+		return data, nil
+	}
+	return nil, fmt.Errorf("no private key of the github app %d could authenticate: %s", ghApp.ID, strings.Join(errs, ", "))
+}
+
 func listRepos(ctx context.Context, repo *v1alpha1.Repository, gh *github.Provider) (bool, error) {
 	repoList, err := github.ListRepos(ctx, gh)
 	if err != nil {
@@ -98,7 +129,11 @@ func generateJWT(ctx context.Context, run *params.Run) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return signJWT(applicationID, privateKey)
+}
 
+// signJWT generates a JWT authenticating as the GitHub App itself
+func signJWT(applicationID int64, privateKey []byte) (string, error) {
 	// The expirationTime claim identifies the expiration time on or after which the JWT MUST NOT be accepted for processing.
 	// Value cannot be longer duration.
 	// See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.4
@@ -93,6 +93,17 @@ func Test_GenerateJWT(t *testing.T) {
 			"github-private-key":    []byte("invalidprivatekey"),
 		},
 	}
+	secretWithRotatedPrivateKey := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pipelineascode.DefaultPipelinesAscodeSecretName,
+			Namespace: testNamespace.Name,
+		},
+		Data: map[string][]byte{
+			"github-application-id": []byte("12345"),
+			"github-private-key":    []byte("invalidprivatekey"),
+			"github-private-key-2":  []byte(fakePrivateKey),
+		},
+	}
 
 	tests := []struct {
 		name      string
@@ -114,6 +125,11 @@ func Test_GenerateJWT(t *testing.T) {
 		namespace: []*corev1.Namespace{testNamespace},
 		secrets:   []*corev1.Secret{secretWithInvalidPrivateKey},
 		wantErr:   true,
+	}, {
+		name:      "invalid private key with a valid key in the key ring",
+		namespace: []*corev1.Namespace{testNamespace},
+		secrets:   []*corev1.Secret{secretWithRotatedPrivateKey},
+		wantErr:   false,
 	}, {
 		name:      "valid secret found",
 		namespace: []*corev1.Namespace{testNamespace},
@@ -288,3 +304,36 @@ func Test_ListRepos(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, exist, true)
 }
+
+func TestGetInstallationsKeyRing(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// the key of the first authenticated request has been revoked
+		if requests == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = fmt.Fprint(w, `[{"id": 120}]`)
+	}))
+	defer ts.Close()
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	run := &params.Run{Clients: clients.Clients{HTTP: *ts.Client()}}
+	ghApp := &github.AppCredentials{
+		ID: 12345,
+		PrivateKeys: []github.AppPrivateKey{
+			{Name: "github-private-key", PEM: []byte("invalid")},
+			{Name: "github-private-key-2", PEM: []byte(fakePrivateKey)},
+			{Name: "github-private-key-3", PEM: []byte(fakePrivateKey)},
+		},
+	}
+	data, err := getInstallations(ctx, ts.URL, ghApp, run, github.New())
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `[{"id": 120}]`)
+	assert.Equal(t, requests, 2)
+
+	ghApp.PrivateKeys = ghApp.PrivateKeys[:1]
+	_, err = getInstallations(ctx, ts.URL, ghApp, run, github.New())
+	assert.ErrorContains(t, err, "no private key of the github app 12345 could authenticate: github-private-key: failed to parse private key")
+}
@@ -0,0 +1,184 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// AppSecretsEnv lists the Secrets of the controller namespace with the
+	// credentials of the other GitHub Apps, beside the pipelines-as-code-secret
+	AppSecretsEnv = "PAC_GITHUB_APP_SECRETS"
+
+	appIDKey         = "github-application-id"
+	appPrivateKeyKey = "github-private-key"
+	// appPrivateKeyPrefix prefixes the other private keys of the key ring of
+	// an app, ie: github-private-key-2, while a key is rotated
+	appPrivateKeyPrefix = appPrivateKeyKey + "-"
+	appWebhookKey       = "webhook.secret"
+	appEnterpriseHost   = "github-enterprise-host"
+
+	publicGitHubHost = "github.com"
+)
+
+// AppCredentials are the credentials of a GitHub App
+type AppCredentials struct {
+	// SecretName is the Secret of the controller namespace they come from
+	SecretName string
+	ID         int64
+	// PrivateKey is the first private key of the key ring
+	PrivateKey []byte
+	// PrivateKeys is the key ring of the app, the keys are tried in order
+	PrivateKeys   []AppPrivateKey
+	WebhookSecret string
+	// EnterpriseHost is the GitHub Enterprise host the app is installed on,
+	// empty for github.com or when the app serves any host
+	EnterpriseHost string
+}
+
+// AppPrivateKey is a private key of the key ring of a GitHub App
+type AppPrivateKey struct {
+	// Name is the key of the Secret it comes from
+	Name string
+	PEM  []byte
+}
+
+// privateKeyRing returns the private keys of a Secret, the github-private-key
+// first then the keys prefixed by github-private-key- sorted by name.
+func privateKeyRing(data map[string][]byte) []AppPrivateKey {
+	keys := []AppPrivateKey{}
+	if pem, ok := data[appPrivateKeyKey]; ok && len(pem) > 0 {
+		keys = append(keys, AppPrivateKey{Name: appPrivateKeyKey, PEM: pem})
+	}
+	names := []string{}
+	for name, pem := range data {
+		if strings.HasPrefix(name, appPrivateKeyPrefix) && len(pem) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		keys = append(keys, AppPrivateKey{Name: name, PEM: data[name]})
+	}
+	return keys
+}
+
+// appSecretNames returns the Secrets with the credentials of the GitHub Apps,
+// the pipelines-as-code-secret first.
+func appSecretNames() []string {
+	names := []string{secretName}
+	for _, name := range strings.Split(os.Getenv(AppSecretsEnv), ",") {
+		if name = strings.TrimSpace(name); name != "" && name != secretName {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// normalizeHost returns the host of a GitHub Enterprise URL or host, github.com
+// is returned as an empty host
+func normalizeHost(host string) string {
+	host = strings.TrimSpace(host)
+	if strings.Contains(host, "://") {
+		if parsed, err := url.Parse(host); err == nil {
+			host = parsed.Host
+		}
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), "/")
+	if host == publicGitHubHost || host == "api."+publicGitHubHost {
+		return ""
+	}
+	return host
+}
+
+func readAppCredentials(ctx context.Context, kube kubernetes.Interface, ns, name string) (*AppCredentials, error) {
+	secret, err := kube.CoreV1().Secrets(ns).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	applicationID, err := strconv.ParseInt(strings.TrimSpace(string(secret.Data[appIDKey])), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the github application_id number from secret %s: %w", name, err)
+	}
+	privateKeys := privateKeyRing(secret.Data)
+	var privateKey []byte
+	if len(privateKeys) > 0 {
+		privateKey = privateKeys[0].PEM
+	}
+	return &AppCredentials{
+		SecretName:     name,
+		ID:             applicationID,
+		PrivateKey:     privateKey,
+		PrivateKeys:    privateKeys,
+		WebhookSecret:  strings.TrimSpace(string(secret.Data[appWebhookKey])),
+		EnterpriseHost: normalizeHost(string(secret.Data[appEnterpriseHost])),
+	}, nil
+}
+
+// ListAppCredentials returns the credentials of the GitHub Apps of the
+// controller, the Secrets which cannot be read are skipped.
+func ListAppCredentials(ctx context.Context, kube kubernetes.Interface) ([]*AppCredentials, error) {
+	ns := os.Getenv("SYSTEM_NAMESPACE")
+	apps := []*AppCredentials{}
+	var errs []string
+	for _, name := range appSecretNames() {
+		app, err := readAppCredentials(ctx, kube, ns, name)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		apps = append(apps, app)
+	}
+	if len(apps) == 0 {
+		return nil, fmt.Errorf("no github app credentials: %s", strings.Join(errs, ", "))
+	}
+	return apps, nil
+}
+
+// GetAppCredentials returns the credentials of the GitHub App which has sent
+// an event. The app is selected by its id when we know it from the webhook,
+// otherwise by the GitHub Enterprise host of the event, the credentials of the
+// pipelines-as-code-secret are used when no app is configured for the host.
+func GetAppCredentials(ctx context.Context, kube kubernetes.Interface, appID int64, host string) (*AppCredentials, error) {
+	apps, err := ListAppCredentials(ctx, kube)
+	if err != nil {
+		return nil, err
+	}
+	if appID > 0 {
+		for _, app := range apps {
+			if app.ID == appID {
+				return app, nil
+			}
+		}
+		return nil, fmt.Errorf("no credentials for the github app %d in the secrets %s", appID, strings.Join(appSecretNames(), ", "))
+	}
+	host = normalizeHost(host)
+	for _, app := range apps {
+		if app.EnterpriseHost == host {
+			return app, nil
+		}
+	}
+	if apps[0].SecretName == secretName {
+		return apps[0], nil
+	}
+	return nil, fmt.Errorf("no credentials for a github app on %s", host)
+}
+
+// RecordAppKey records the result of an authentication of a GitHub App with
+// a private key of its key ring in the metrics
+func (v *Provider) RecordAppKey(appID int64, key string, valid bool) {
+	if v.Metrics == nil {
+		return
+	}
+	if err := v.Metrics.GitHubAppKey(strconv.FormatInt(appID, 10), key, valid); err != nil && v.Logger != nil {
+		v.Logger.Warnf("cannot record the authentication of the github app %d with the private key %s: %v", appID, key, err)
+	}
+}
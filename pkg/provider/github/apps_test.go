@@ -0,0 +1,236 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func appSecret(ns, name, appID, host string) *corev1.Secret {
+	data := map[string][]byte{
+		"github-application-id": []byte(appID),
+		"github-private-key":    []byte(fakePrivateKey),
+		"webhook.secret":        []byte(name + "-webhook\n"),
+	}
+	if host != "" {
+		data["github-enterprise-host"] = []byte(host)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+		Data:       data,
+	}
+}
+
+func TestGetAppCredentials(t *testing.T) {
+	tests := []struct {
+		name       string
+		secrets    []*corev1.Secret
+		appSecrets string
+		appID      int64
+		host       string
+		wantSecret string
+		wantErr    string
+	}{
+		{
+			name:       "single app for any host",
+			secrets:    []*corev1.Secret{appSecret("pac", "pipelines-as-code-secret", "1", "")},
+			host:       "ghe.company.com",
+			wantSecret: "pipelines-as-code-secret",
+		},
+		{
+			name: "app selected by its id",
+			secrets: []*corev1.Secret{
+				appSecret("pac", "pipelines-as-code-secret", "1", ""),
+				appSecret("pac", "ghes-app", "2", "ghe.company.com"),
+			},
+			appSecrets: "ghes-app",
+			appID:      2,
+			wantSecret: "ghes-app",
+		},
+		{
+			name: "app selected by the enterprise host",
+			secrets: []*corev1.Secret{
+				appSecret("pac", "pipelines-as-code-secret", "1", ""),
+				appSecret("pac", "ghes-app", "2", "https://GHE.company.com/"),
+			},
+			appSecrets: "ghes-app, other-app",
+			host:       "ghe.company.com",
+			wantSecret: "ghes-app",
+		},
+		{
+			name: "github.com app",
+			secrets: []*corev1.Secret{
+				appSecret("pac", "pipelines-as-code-secret", "1", "ghe.company.com"),
+				appSecret("pac", "public-app", "2", "github.com"),
+			},
+			appSecrets: "public-app",
+			wantSecret: "public-app",
+		},
+		{
+			name: "default app when no app for the host",
+			secrets: []*corev1.Secret{
+				appSecret("pac", "pipelines-as-code-secret", "1", ""),
+				appSecret("pac", "ghes-app", "2", "ghe.company.com"),
+			},
+			appSecrets: "ghes-app",
+			host:       "ghe.other.com",
+			wantSecret: "pipelines-as-code-secret",
+		},
+		{
+			name:       "no default app",
+			secrets:    []*corev1.Secret{appSecret("pac", "ghes-app", "2", "ghe.company.com")},
+			appSecrets: "ghes-app",
+			wantErr:    "no credentials for a github app",
+		},
+		{
+			name:    "unknown app id",
+			secrets: []*corev1.Secret{appSecret("pac", "pipelines-as-code-secret", "1", "")},
+			appID:   3,
+			wantErr: "no credentials for the github app 3",
+		},
+		{
+			name:    "no secret",
+			wantErr: "no github app credentials",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SYSTEM_NAMESPACE", "pac")
+			t.Setenv(AppSecretsEnv, tt.appSecrets)
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{Secret: tt.secrets})
+			got, err := GetAppCredentials(ctx, stdata.Kube, tt.appID, tt.host)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got.SecretName, tt.wantSecret)
+			assert.Equal(t, got.WebhookSecret, tt.wantSecret+"-webhook")
+		})
+	}
+}
+
+func TestPrivateKeyRing(t *testing.T) {
+	keys := privateKeyRing(map[string][]byte{
+		"github-private-key-b": []byte("b"),
+		"github-private-key":   []byte("primary"),
+		"github-private-key-a": []byte("a"),
+		"github-private-key-c": []byte(""),
+		"webhook.secret":       []byte("secret"),
+	})
+	names := []string{}
+	for _, key := range keys {
+		names = append(names, key.Name)
+	}
+	assert.DeepEqual(t, names, []string{"github-private-key", "github-private-key-a", "github-private-key-b"})
+	assert.Equal(t, string(keys[0].PEM), "primary")
+}
+
+func TestGetAppTokenKeyRing(t *testing.T) {
+	installationID := int64(120)
+	tests := []struct {
+		name         string
+		keys         map[string]string
+		rejected     int
+		wantToken    string
+		wantRequests int
+		wantErr      string
+	}{
+		{
+			name:         "primary key",
+			keys:         map[string]string{"github-private-key": fakePrivateKey},
+			wantToken:    "token-1",
+			wantRequests: 1,
+		},
+		{
+			name: "invalid primary key",
+			keys: map[string]string{
+				"github-private-key":   "invalid",
+				"github-private-key-2": fakePrivateKey,
+			},
+			wantToken:    "token-1",
+			wantRequests: 1,
+		},
+		{
+			name: "primary key rejected by github",
+			keys: map[string]string{
+				"github-private-key":   fakePrivateKey,
+				"github-private-key-2": fakePrivateKey,
+			},
+			rejected:     1,
+			wantToken:    "token-2",
+			wantRequests: 2,
+		},
+		{
+			name: "all keys rejected",
+			keys: map[string]string{
+				"github-private-key":   fakePrivateKey,
+				"github-private-key-2": "invalid",
+			},
+			rejected:     1,
+			wantRequests: 1,
+			wantErr:      "no private key of the github app 1 could generate a token",
+		},
+		{
+			name:    "no private key",
+			keys:    map[string]string{},
+			wantErr: "no private key for the github app 1 in the secret pipelines-as-code-secret",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mux, serverURL, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			requests := 0
+			mux.HandleFunc(fmt.Sprintf("/app/installations/%d/access_tokens", installationID), func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests <= tt.rejected {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				_, _ = fmt.Fprintf(w, `{"token": "token-%d"}`, requests)
+			})
+			t.Setenv("SYSTEM_NAMESPACE", "pac")
+			t.Setenv("PAC_GIT_PROVIDER_TOKEN_APIURL", serverURL+"/api/v3")
+
+			secret := appSecret("pac", "pipelines-as-code-secret", "1", "")
+			delete(secret.Data, "github-private-key")
+			for name, key := range tt.keys {
+				secret.Data[name] = []byte(key)
+			}
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{Secret: []*corev1.Secret{secret}})
+
+			v := New()
+			token, err := v.GetAppToken(ctx, stdata.Kube, "", 0, installationID)
+			assert.Equal(t, requests, tt.wantRequests)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, token, tt.wantToken)
+			assert.Equal(t, *v.Token, tt.wantToken)
+		})
+	}
+}
+
+func TestGetAppIDFromHeaders(t *testing.T) {
+	request := &http.Request{Header: map[string][]string{}}
+	assert.Equal(t, getAppIDFromHeaders(request), int64(0))
+
+	request.Header.Set("X-GitHub-Hook-Installation-Target-Type", "repository")
+	request.Header.Set("X-GitHub-Hook-Installation-Target-ID", "1234")
+	assert.Equal(t, getAppIDFromHeaders(request), int64(0))
+
+	request.Header.Set("X-GitHub-Hook-Installation-Target-Type", "integration")
+	assert.Equal(t, getAppIDFromHeaders(request), int64(1234))
+}
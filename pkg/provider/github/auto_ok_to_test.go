@@ -0,0 +1,133 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/acl"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// IsAutoOkToTest checks the sender not allowed to run the CI against the
+// auto_ok_to_test conditions of the Repository, it returns the first one
+// approving the sender.
+func (v *Provider) IsAutoOkToTest(ctx context.Context, event *info.Event, auto *v1alpha1.AutoOkToTest) (bool, string, error) {
+	for _, team := range auto.Teams {
+		member, err := v.isTeamMember(ctx, event.Organization, team, event.Sender)
+		if err != nil {
+			return false, "", err
+		}
+		if member {
+			return true, fmt.Sprintf("member of the team %s", team), nil
+		}
+	}
+
+	if auto.CodeOwners {
+		owner, err := v.isCodeOwner(ctx, event)
+		if err != nil {
+			return false, "", err
+		}
+		if owner {
+			return true, "code owner of the changed files", nil
+		}
+	}
+
+	if auto.MergedPullRequests > 0 {
+		merged, err := v.mergedPullRequests(ctx, event)
+		if err != nil {
+			return false, "", err
+		}
+		if merged >= auto.MergedPullRequests {
+			return true, fmt.Sprintf("author of %d merged pull requests", merged), nil
+		}
+	}
+	return false, "", nil
+}
+
+// isTeamMember checks the user is an active member of a team of the
+// organization, the pending invitations are not enough.
+func (v *Provider) isTeamMember(ctx context.Context, org, team, user string) (bool, error) {
+	membership, resp, err := v.Client.Teams.GetTeamMembershipBySlug(ctx, org, team, user)
+	// a 404 means the user is not in the team or the team is not there
+	if resp != nil && resp.Response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return membership.GetState() == "active", nil
+}
+
+// isCodeOwner checks the sender owns every file changed by the event in the
+// CODEOWNERS file of the default branch, directly or from one of their teams.
+func (v *Provider) isCodeOwner(ctx context.Context, event *info.Event) (bool, error) {
+	var content string
+	for _, path := range acl.CodeOwnersPaths {
+		var err error
+		content, err = v.getFileFromDefaultBranch(ctx, path, event)
+		if err == nil {
+			break
+		}
+		if !strings.Contains(err.Error(), "cannot find") {
+			return false, err
+		}
+	}
+	if content == "" {
+		// no codeowners file, skipping
+		return false, nil
+	}
+
+	files, err := v.GetFiles(ctx, event)
+	if err != nil {
+		return false, err
+	}
+	if len(files) == 0 {
+		return false, nil
+	}
+
+	// the teams are checked once for all the files
+	teams := map[string]bool{}
+	for _, file := range files {
+		owned := false
+		for _, owner := range acl.CodeOwners(content, file) {
+			if strings.EqualFold(owner, "@"+event.Sender) {
+				owned = true
+				break
+			}
+			org, team, ok := strings.Cut(strings.TrimPrefix(owner, "@"), "/")
+			if !ok || !strings.HasPrefix(owner, "@") {
+				continue
+			}
+			member, checked := teams[owner]
+			if !checked {
+				if member, err = v.isTeamMember(ctx, org, team, event.Sender); err != nil {
+					return false, err
+				}
+				teams[owner] = member
+			}
+			if member {
+				owned = true
+				break
+			}
+		}
+		if !owned {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// mergedPullRequests returns the number of pull requests of the sender merged
+// in the repository.
+func (v *Provider) mergedPullRequests(ctx context.Context, event *info.Event) (int, error) {
+	query := fmt.Sprintf("repo:%s/%s is:pr is:merged author:%s", event.Organization, event.Repository, event.Sender)
+	result, _, err := v.Client.Search.Issues(ctx, query, &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 1}})
+	if err != nil {
+		return 0, err
+	}
+	return result.GetTotal(), nil
+}
@@ -0,0 +1,142 @@
+package github
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestIsAutoOkToTest(t *testing.T) {
+	codeowners := "* @owner/maintainers\n/docs/ @writer @owner/docs\n"
+	tests := []struct {
+		name         string
+		sender       string
+		auto         v1alpha1.AutoOkToTest
+		codeowners   string
+		changedFiles []string
+		mergedTotal  int
+		want         bool
+		wantReason   string
+	}{
+		{
+			name:       "member of a team",
+			sender:     "teammember",
+			auto:       v1alpha1.AutoOkToTest{Teams: []string{"other", "contributors"}},
+			want:       true,
+			wantReason: "member of the team contributors",
+		},
+		{
+			name:   "pending member of a team",
+			sender: "pending",
+			auto:   v1alpha1.AutoOkToTest{Teams: []string{"contributors"}},
+		},
+		{
+			name:         "code owner of the changed files",
+			sender:       "writer",
+			auto:         v1alpha1.AutoOkToTest{CodeOwners: true},
+			codeowners:   codeowners,
+			changedFiles: []string{"docs/index.md", "docs/install.md"},
+			want:         true,
+			wantReason:   "code owner of the changed files",
+		},
+		{
+			name:         "code owner from a team",
+			sender:       "teammember",
+			auto:         v1alpha1.AutoOkToTest{CodeOwners: true},
+			codeowners:   codeowners,
+			changedFiles: []string{"docs/index.md"},
+			want:         true,
+			wantReason:   "code owner of the changed files",
+		},
+		{
+			name:         "code owner of some changed files only",
+			sender:       "writer",
+			auto:         v1alpha1.AutoOkToTest{CodeOwners: true},
+			codeowners:   codeowners,
+			changedFiles: []string{"docs/index.md", "main.go"},
+		},
+		{
+			name:         "no codeowners file",
+			sender:       "writer",
+			auto:         v1alpha1.AutoOkToTest{CodeOwners: true},
+			changedFiles: []string{"docs/index.md"},
+		},
+		{
+			name:        "enough merged pull requests",
+			sender:      "contributor",
+			auto:        v1alpha1.AutoOkToTest{MergedPullRequests: 3},
+			mergedTotal: 5,
+			want:        true,
+			wantReason:  "author of 5 merged pull requests",
+		},
+		{
+			name:        "not enough merged pull requests",
+			sender:      "contributor",
+			auto:        v1alpha1.AutoOkToTest{MergedPullRequests: 3},
+			mergedTotal: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			for _, team := range []string{"contributors", "docs"} {
+				team := team
+				mux.HandleFunc(fmt.Sprintf("/orgs/owner/teams/%s/memberships/", team), func(rw http.ResponseWriter, r *http.Request) {
+					switch r.URL.Path {
+					case fmt.Sprintf("/orgs/owner/teams/%s/memberships/teammember", team):
+						fmt.Fprint(rw, `{"state": "active"}`)
+					case fmt.Sprintf("/orgs/owner/teams/%s/memberships/pending", team):
+						fmt.Fprint(rw, `{"state": "pending"}`)
+					default:
+						rw.WriteHeader(http.StatusNotFound)
+					}
+				})
+			}
+			if tt.codeowners != "" {
+				mux.HandleFunc("/repos/owner/repo/contents/.github/CODEOWNERS", func(rw http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(rw, `{"name": "CODEOWNERS", "path": ".github/CODEOWNERS", "sha": "codeownerssha"}`)
+				})
+				mux.HandleFunc("/repos/owner/repo/git/blobs/codeownerssha", func(rw http.ResponseWriter, r *http.Request) {
+					fmt.Fprintf(rw, `{"content": "%s"}`, base64.RawStdEncoding.EncodeToString([]byte(tt.codeowners)))
+				})
+			}
+			mux.HandleFunc("/repos/owner/repo/pulls/1/files", func(rw http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(rw, "[")
+				for i, file := range tt.changedFiles {
+					if i > 0 {
+						fmt.Fprint(rw, ",")
+					}
+					fmt.Fprintf(rw, `{"filename": "%s"}`, file)
+				}
+				fmt.Fprint(rw, "]")
+			})
+			mux.HandleFunc("/search/issues", func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.URL.Query().Get("q"), "repo:owner/repo is:pr is:merged author:"+tt.sender)
+				fmt.Fprintf(rw, `{"total_count": %d}`, tt.mergedTotal)
+			})
+
+			ctx, _ := rtesting.SetupFakeContext(t)
+			gprovider := Provider{Client: fakeclient}
+			event := &info.Event{
+				Organization:      "owner",
+				Repository:        "repo",
+				Sender:            tt.sender,
+				DefaultBranch:     "main",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 1,
+			}
+			got, reason, err := gprovider.IsAutoOkToTest(ctx, event, &tt.auto)
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+			assert.Equal(t, reason, tt.wantReason)
+		})
+	}
+}
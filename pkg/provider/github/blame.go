@@ -0,0 +1,194 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// the REST API has no blame, it is only available with GraphQL
+const blameQuery = `query($owner: String!, $name: String!, $oid: GitObjectID!, $path: String!) {
+  repository(owner: $owner, name: $name) {
+    object(oid: $oid) {
+      ... on Commit {
+        blame(path: $path) {
+          ranges {
+            startingLine
+            endingLine
+            commit {
+              oid
+              author {
+                name
+                user {
+                  login
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// maxBlamedFiles is the maximum number of files blamed for a check run, each
+// file is a query to the GraphQL API
+const maxBlamedFiles = 10
+
+type blameRange struct {
+	StartingLine int `json:"startingLine"`
+	EndingLine   int `json:"endingLine"`
+	Commit       struct {
+		OID    string `json:"oid"`
+		Author struct {
+			Name string `json:"name"`
+			User *struct {
+				Login string `json:"login"`
+			} `json:"user"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+type blameResponse struct {
+	Data struct {
+		Repository struct {
+			Object struct {
+				Blame struct {
+					Ranges []blameRange `json:"ranges"`
+				} `json:"blame"`
+			} `json:"object"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphQLURL returns the URL of the GraphQL API, on GitHub Enterprise it is
+// next to the REST API and not under it
+func graphQLURL(baseURL *url.URL) string {
+	u := *baseURL
+	if strings.HasSuffix(u.Path, "/api/v3/") {
+		u.Path = strings.TrimSuffix(u.Path, "v3/") + "graphql"
+	} else {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/graphql"
+	}
+	return u.String()
+}
+
+// blame returns the blame of the file at the SHA of the event
+func (v *Provider) blame(ctx context.Context, runevent *info.Event, path string) ([]blameRange, error) {
+	body := map[string]interface{}{
+		"query": blameQuery,
+		"variables": map[string]string{
+			"owner": runevent.Organization,
+			"name":  runevent.Repository,
+			"oid":   runevent.SHA,
+			"path":  path,
+		},
+	}
+	req, err := v.Client.NewRequest(http.MethodPost, graphQLURL(v.Client.BaseURL), body)
+	if err != nil {
+		return nil, err
+	}
+	resp := &blameResponse{}
+	if _, err := v.Client.Do(ctx, req, resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("%s", resp.Errors[0].Message)
+	}
+	return resp.Data.Repository.Object.Blame.Ranges, nil
+}
+
+// blameAnnotations add the last author of the line to the message of the
+// annotations and returns the logins of these authors, the authors shown are
+// hidden according to the sender-privacy setting
+func (v *Provider) blameAnnotations(ctx context.Context, runevent *info.Event, pacopts *info.PacOpts, annotations []*github.CheckRunAnnotation) []string {
+	blames := map[string][]blameRange{}
+	authors := []string{}
+	seen := map[string]bool{}
+	for _, annotation := range annotations {
+		path := annotation.GetPath()
+		ranges, ok := blames[path]
+		if !ok {
+			if len(blames) >= maxBlamedFiles {
+				continue
+			}
+			var err error
+			if ranges, err = v.blame(ctx, runevent, path); err != nil {
+				v.Logger.Warnf("cannot blame %s on %s/%s: %v", path, runevent.Organization, runevent.Repository, err)
+			}
+			blames[path] = ranges
+		}
+
+		line := annotation.GetStartLine()
+		for _, r := range ranges {
+			if line < r.StartingLine || line > r.EndingLine {
+				continue
+			}
+			author := pacopts.PublicUser(runevent, r.Commit.Author.Name)
+			if r.Commit.Author.User != nil && r.Commit.Author.User.Login != "" {
+				login := r.Commit.Author.User.Login
+				author = "@" + login
+				if pacopts.HidesUsers(runevent) {
+					author = pacopts.PublicUser(runevent, login)
+				}
+				if !seen[login] {
+					seen[login] = true
+					authors = append(authors, login)
+				}
+			}
+			sha := r.Commit.OID
+			if len(sha) > 7 {
+				sha = sha[:7]
+			}
+			annotation.Message = github.String(fmt.Sprintf("%s\n\nLast changed by %s in %s", annotation.GetMessage(), author, sha))
+			break
+		}
+	}
+	return authors
+}
+
+// blameText is the text added to the check run to mention the last authors of
+// the lines with errors
+func blameText(authors []string) string {
+	mentions := []string{}
+	for _, author := range authors {
+		mentions = append(mentions, "@"+author)
+	}
+	return fmt.Sprintf("\n\nThe lines with errors have been last changed by %s.", strings.Join(mentions, ", "))
+}
+
+// requestBlamedReviews request the review of the last authors of the lines
+// with errors on the pull request, the author of the pull request cannot be
+// asked for a review
+func (v *Provider) requestBlamedReviews(ctx context.Context, runevent *info.Event, authors []string) error {
+	if runevent.PullRequestNumber == 0 || len(authors) == 0 {
+		return nil
+	}
+	pr, _, err := v.Client.PullRequests.Get(ctx, runevent.Organization, runevent.Repository, runevent.PullRequestNumber)
+	if err != nil {
+		return err
+	}
+	if pr.GetState() != "open" {
+		return nil
+	}
+	reviewers := []string{}
+	for _, author := range authors {
+		if author != pr.GetUser().GetLogin() {
+			reviewers = append(reviewers, author)
+		}
+	}
+	if len(reviewers) == 0 {
+		return nil
+	}
+	_, _, err = v.Client.PullRequests.RequestReviewers(ctx, runevent.Organization, runevent.Repository, runevent.PullRequestNumber,
+		github.ReviewersRequest{Reviewers: reviewers})
+	return err
+}
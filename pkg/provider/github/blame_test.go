@@ -0,0 +1,98 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestGraphQLURL(t *testing.T) {
+	for base, want := range map[string]string{
+		"https://api.github.com/":      "https://api.github.com/graphql",
+		"https://ghe.example/api/v3/":  "https://ghe.example/api/graphql",
+		"http://127.0.0.1:8080/proxy/": "http://127.0.0.1:8080/proxy/graphql",
+	} {
+		u, err := url.Parse(base)
+		assert.NilError(t, err)
+		assert.Equal(t, graphQLURL(u), want)
+	}
+}
+
+func TestBlameAnnotations(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graphql", func(rw http.ResponseWriter, r *http.Request) {
+		body := struct {
+			Variables map[string]string `json:"variables"`
+		}{}
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, body.Variables["oid"], "sha")
+		switch body.Variables["path"] {
+		case "main.go":
+			fmt.Fprint(rw, `{"data": {"repository": {"object": {"blame": {"ranges": [
+{"startingLine": 1, "endingLine": 9, "commit": {"oid": "1234567890", "author": {"name": "Alice", "user": {"login": "alice"}}}},
+{"startingLine": 10, "endingLine": 20, "commit": {"oid": "abcdef1234", "author": {"name": "Bob", "user": null}}}
+]}}}}}`)
+		default:
+			fmt.Fprint(rw, `{"errors": [{"message": "Could not resolve file"}]}`)
+		}
+	})
+	requested := []string{}
+	mux.HandleFunc("/repos/owner/repo/pulls/6", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"number": 6, "state": "open", "user": {"login": "alice"}}`)
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls/6/requested_reviewers", func(rw http.ResponseWriter, r *http.Request) {
+		reviewers := github.ReviewersRequest{}
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&reviewers))
+		requested = append(requested, reviewers.Reviewers...)
+		fmt.Fprint(rw, `{}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	v := &Provider{Client: client, Logger: zap.New(observer).Sugar()}
+	runevent := &info.Event{Organization: "owner", Repository: "repo", SHA: "sha", PullRequestNumber: 6}
+
+	annotations := []*github.CheckRunAnnotation{
+		{Path: github.String("main.go"), StartLine: github.Int(3), Message: github.String("undefined: foo")},
+		{Path: github.String("main.go"), StartLine: github.Int(12), Message: github.String("unused variable")},
+		{Path: github.String("main.go"), StartLine: github.Int(4), Message: github.String("undefined: bar")},
+		{Path: github.String("gone.go"), StartLine: github.Int(1), Message: github.String("syntax error")},
+	}
+	pacopts := &info.PacOpts{Settings: &settings.Settings{SenderPrivacy: settings.SenderPrivacyNone}}
+	authors := v.blameAnnotations(ctx, runevent, pacopts, annotations)
+	assert.DeepEqual(t, authors, []string{"alice"})
+	assert.Equal(t, annotations[0].GetMessage(), "undefined: foo\n\nLast changed by @alice in 1234567")
+	assert.Equal(t, annotations[1].GetMessage(), "unused variable\n\nLast changed by Bob in abcdef1")
+	assert.Equal(t, annotations[3].GetMessage(), "syntax error")
+
+	// the authors are not shown on public repositories with sender-privacy
+	hidden := []*github.CheckRunAnnotation{
+		{Path: github.String("main.go"), StartLine: github.Int(3), Message: github.String("undefined: foo")},
+		{Path: github.String("main.go"), StartLine: github.Int(12), Message: github.String("unused variable")},
+	}
+	pacopts.SenderPrivacy = settings.SenderPrivacyHash
+	authors = v.blameAnnotations(ctx, runevent, pacopts, hidden)
+	assert.DeepEqual(t, authors, []string{"alice"})
+	assert.Equal(t, hidden[0].GetMessage(), "undefined: foo\n\nLast changed by "+pacopts.PublicUser(runevent, "alice")+" in 1234567")
+	assert.Equal(t, hidden[1].GetMessage(), "unused variable\n\nLast changed by "+pacopts.PublicUser(runevent, "Bob")+" in abcdef1")
+	assert.Equal(t, blameText([]string{"alice", "carol"}), "\n\nThe lines with errors have been last changed by @alice, @carol.")
+
+	// the author of the pull request cannot review it
+	assert.NilError(t, v.requestBlamedReviews(ctx, runevent, []string{"alice", "carol"}))
+	assert.DeepEqual(t, requested, []string{"carol"})
+}
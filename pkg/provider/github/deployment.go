@@ -0,0 +1,115 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// getDeploymentState convert our status/conclusion to a GitHub deployment state
+func getDeploymentState(statusOpts provider.StatusOpts) string {
+	switch statusOpts.Status {
+	case "queued":
+		return "queued"
+	case "in_progress":
+		return "in_progress"
+	}
+	switch statusOpts.Conclusion {
+	case "success":
+		return "success"
+	case "failure":
+		return "failure"
+	}
+	return "error"
+}
+
+// createDeployment create a deployment for the environment targeted by the
+// PipelineRun and store its ID in an annotation so the next statuses can get
+// attached to it.
+func (v *Provider) createDeployment(ctx context.Context, tekton versioned.Interface, runevent *info.Event, pacopts *info.PacOpts, statusOpts provider.StatusOpts, environment string) (int64, error) {
+	deployment, _, err := v.Client.Repositories.CreateDeployment(ctx, runevent.Organization, runevent.Repository,
+		&github.DeploymentRequest{
+			Ref:              github.String(runevent.SHA),
+			Environment:      github.String(environment),
+			AutoMerge:        github.Bool(false),
+			RequiredContexts: &[]string{},
+			Description:      github.String(getCheckName(statusOpts, pacopts)),
+		})
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := action.PatchPipelineRun(ctx, v.Logger, "deploymentID", tekton, statusOpts.PipelineRun, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				keys.DeploymentID: strconv.FormatInt(deployment.GetID(), 10),
+			},
+		},
+	}); err != nil {
+		return 0, err
+	}
+	return deployment.GetID(), nil
+}
+
+// createOrUpdateDeploymentStatus report to the GitHub deployments API the
+// status of a PipelineRun on push targeting an environment via the
+// pipelinesascode.tekton.dev/environment annotation.
+func (v *Provider) createOrUpdateDeploymentStatus(ctx context.Context, tekton versioned.Interface, runevent *info.Event, pacopts *info.PacOpts, statusOpts provider.StatusOpts) error {
+	if statusOpts.PipelineRun == nil || runevent.EventType != "push" {
+		return nil
+	}
+	environment, ok := statusOpts.PipelineRun.GetAnnotations()[keys.Environment]
+	if !ok || environment == "" {
+		return nil
+	}
+
+	id, ok := statusOpts.PipelineRun.GetAnnotations()[keys.DeploymentID]
+	if !ok {
+		// the PipelineRun we are given may be older than the patch of the
+		// deployment ID by the previous status, a second deployment would be
+		// created for it
+		latest, err := tekton.TektonV1beta1().PipelineRuns(statusOpts.PipelineRun.GetNamespace()).Get(ctx,
+			statusOpts.PipelineRun.GetName(), metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("cannot get pipelinerun %s: %w", statusOpts.PipelineRun.GetName(), err)
+		}
+		if err == nil {
+			id, ok = latest.GetAnnotations()[keys.DeploymentID]
+		}
+	}
+
+	var deploymentID int64
+	if ok {
+		var err error
+		if deploymentID, err = strconv.ParseInt(id, 10, 64); err != nil {
+			return fmt.Errorf("api error: cannot convert deployment id %s: %w", id, err)
+		}
+	} else {
+		var err error
+		if deploymentID, err = v.createDeployment(ctx, tekton, runevent, pacopts, statusOpts, environment); err != nil {
+			return fmt.Errorf("cannot create deployment for environment %s: %w", environment, err)
+		}
+	}
+
+	state := getDeploymentState(statusOpts)
+	if (state == "queued" || state == "in_progress") && !v.supports(ctx, featureDeploymentStates) {
+		state = "pending"
+	}
+	_, _, err := v.Client.Repositories.CreateDeploymentStatus(ctx, runevent.Organization, runevent.Repository, deploymentID,
+		&github.DeploymentStatusRequest{
+			State:       github.String(state),
+			LogURL:      github.String(statusOpts.DetailsURL),
+			Description: github.String(statusOpts.Title),
+			Environment: github.String(environment),
+		})
+	return v.explainEnterpriseError(ctx, err, "create the deployment status")
+}
@@ -0,0 +1,204 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestGetDeploymentState(t *testing.T) {
+	tests := []struct {
+		status     string
+		conclusion string
+		want       string
+	}{
+		{status: "queued", want: "queued"},
+		{status: "in_progress", want: "in_progress"},
+		{status: "completed", conclusion: "success", want: "success"},
+		{status: "completed", conclusion: "failure", want: "failure"},
+		{status: "completed", conclusion: "neutral", want: "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got := getDeploymentState(provider.StatusOpts{Status: tt.status, Conclusion: tt.conclusion})
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestCreateOrUpdateDeploymentStatus(t *testing.T) {
+	deploymentID := int64(4242)
+	tests := []struct {
+		name               string
+		eventType          string
+		annotations        map[string]string
+		clusterAnnotations map[string]string
+		status             string
+		conclusion         string
+		wantCreate         bool
+		wantStatusState    string
+		wantAnnotationID   string
+		wantErr            string
+		wantNoStatusReport bool
+		enterpriseVersion  string
+	}{
+		{
+			name:               "no environment annotation",
+			eventType:          "push",
+			status:             "in_progress",
+			wantNoStatusReport: true,
+		},
+		{
+			name:               "not a push event",
+			eventType:          "pull_request",
+			annotations:        map[string]string{keys.Environment: "production"},
+			status:             "in_progress",
+			wantNoStatusReport: true,
+		},
+		{
+			name:             "create deployment",
+			eventType:        "push",
+			annotations:      map[string]string{keys.Environment: "production"},
+			status:           "in_progress",
+			wantCreate:       true,
+			wantStatusState:  "in_progress",
+			wantAnnotationID: "4242",
+		},
+		{
+			name:              "old github enterprise",
+			eventType:         "push",
+			annotations:       map[string]string{keys.Environment: "production", keys.DeploymentID: "4242"},
+			status:            "in_progress",
+			wantStatusState:   "pending",
+			enterpriseVersion: "2.22.8",
+		},
+		{
+			name:              "recent github enterprise",
+			eventType:         "push",
+			annotations:       map[string]string{keys.Environment: "production", keys.DeploymentID: "4242"},
+			status:            "in_progress",
+			wantStatusState:   "in_progress",
+			enterpriseVersion: "3.7.1",
+		},
+		{
+			name:            "update existing deployment",
+			eventType:       "push",
+			annotations:     map[string]string{keys.Environment: "production", keys.DeploymentID: "4242"},
+			status:          "completed",
+			conclusion:      "failure",
+			wantStatusState: "failure",
+		},
+		{
+			name:               "deployment id only on the cluster",
+			eventType:          "push",
+			annotations:        map[string]string{keys.Environment: "production"},
+			clusterAnnotations: map[string]string{keys.Environment: "production", keys.DeploymentID: "4242"},
+			status:             "completed",
+			conclusion:         "success",
+			wantStatusState:    "success",
+		},
+		{
+			name:        "bad deployment id",
+			eventType:   "push",
+			annotations: map[string]string{keys.Environment: "production", keys.DeploymentID: "foo"},
+			status:      "completed",
+			conclusion:  "success",
+			wantErr:     "cannot convert deployment id foo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+
+			runevent := info.NewEvent()
+			runevent.Organization = "owner"
+			runevent.Repository = "repo"
+			runevent.SHA = "sha"
+			runevent.EventType = tt.eventType
+
+			pr := &v1beta1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pr",
+					Namespace:   "ns",
+					Annotations: tt.annotations,
+				},
+			}
+
+			created := false
+			mux.HandleFunc("/repos/owner/repo/deployments", func(rw http.ResponseWriter, r *http.Request) {
+				created = true
+				bit, _ := io.ReadAll(r.Body)
+				req := &github.DeploymentRequest{}
+				assert.NilError(t, json.Unmarshal(bit, req))
+				assert.Equal(t, req.GetRef(), "sha")
+				assert.Equal(t, req.GetEnvironment(), "production")
+				fmt.Fprintf(rw, `{"id": %d}`, deploymentID)
+			})
+			reported := false
+			mux.HandleFunc(fmt.Sprintf("/repos/owner/repo/deployments/%d/statuses", deploymentID), func(rw http.ResponseWriter, r *http.Request) {
+				reported = true
+				bit, _ := io.ReadAll(r.Body)
+				req := &github.DeploymentStatusRequest{}
+				assert.NilError(t, json.Unmarshal(bit, req))
+				assert.Equal(t, req.GetState(), tt.wantStatusState)
+				assert.Equal(t, req.GetEnvironment(), "production")
+				assert.Equal(t, req.GetLogURL(), "https://logs")
+				fmt.Fprint(rw, `{}`)
+			})
+
+			if tt.enterpriseVersion != "" {
+				mux.HandleFunc("/meta", func(rw http.ResponseWriter, r *http.Request) {
+					rw.Header().Set(enterpriseVersionHeader, tt.enterpriseVersion)
+					fmt.Fprint(rw, `{}`)
+				})
+			}
+
+			clusterPR := pr.DeepCopy()
+			if tt.clusterAnnotations != nil {
+				clusterPR.SetAnnotations(tt.clusterAnnotations)
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{PipelineRuns: []*v1beta1.PipelineRun{clusterPR}})
+			v := &Provider{Client: fakeclient}
+			if tt.enterpriseVersion != "" {
+				v.providerName = providerEnterprise
+			}
+			v.Logger, _ = logger.GetLogger()
+			err := v.createOrUpdateDeploymentStatus(ctx, stdata.Pipeline, runevent, &info.PacOpts{Settings: &settings.Settings{}}, provider.StatusOpts{
+				PipelineRun: pr,
+				Status:      tt.status,
+				Conclusion:  tt.conclusion,
+				DetailsURL:  "https://logs",
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, created, tt.wantCreate)
+			assert.Equal(t, reported, !tt.wantNoStatusReport)
+
+			if tt.wantAnnotationID != "" {
+				got, err := stdata.Pipeline.TektonV1beta1().PipelineRuns("ns").Get(ctx, "pr", metav1.GetOptions{})
+				assert.NilError(t, err)
+				assert.Equal(t, got.GetAnnotations()[keys.DeploymentID], tt.wantAnnotationID)
+			}
+		})
+	}
+}
@@ -13,8 +13,8 @@ import (
 // Detect processes event and detect if it is a github event, whether to process or reject it
 // returns (if is a GH event, whether to process or reject, error if any occurred)
 func (v *Provider) Detect(req *http.Request, payload string, logger *zap.SugaredLogger) (bool, bool, *zap.SugaredLogger, string, error) {
-	// gitea set x-github-event too, so skip it for the gitea driver
-	if h := req.Header.Get("X-Gitea-Event-Type"); h != "" {
+	// gitea and forgejo set x-github-event too, so skip it for the gitea driver
+	if req.Header.Get("X-Gitea-Event-Type") != "" || req.Header.Get("X-Forgejo-Event-Type") != "" {
 		return false, false, logger, "", nil
 	}
 	isGH := false
@@ -45,8 +45,30 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 		if gitEvent.GetAction() == "rerequested" && gitEvent.GetCheckRun() != nil {
 			return setLoggerAndProceed(true, "", nil)
 		}
+		if gitEvent.GetAction() == "requested_action" && gitEvent.GetCheckRun() != nil {
+			identifier := requestedActionIdentifier(gitEvent)
+			if identifier == checkRunActionCancel || identifier == checkRunActionRerun {
+				return setLoggerAndProceed(true, "", nil)
+			}
+			return setLoggerAndProceed(false, fmt.Sprintf("check_run: unsupported requested action \"%s\"", identifier), nil)
+		}
+		if gitEvent.GetAction() == "completed" && gitEvent.GetCheckRun() != nil {
+			if gitEvent.GetCheckRun().GetConclusion() == "success" {
+				return setLoggerAndProceed(true, "", nil)
+			}
+			return setLoggerAndProceed(false, fmt.Sprintf("check_run: unsupported conclusion \"%s\"", gitEvent.GetCheckRun().GetConclusion()), nil)
+		}
 		return setLoggerAndProceed(false, fmt.Sprintf("check_run: unsupported action \"%s\"", gitEvent.GetAction()), nil)
 
+	case *github.WorkflowRunEvent:
+		if gitEvent.GetAction() == "completed" && gitEvent.GetWorkflowRun() != nil {
+			if gitEvent.GetWorkflowRun().GetConclusion() == "success" {
+				return setLoggerAndProceed(true, "", nil)
+			}
+			return setLoggerAndProceed(false, fmt.Sprintf("workflow_run: unsupported conclusion \"%s\"", gitEvent.GetWorkflowRun().GetConclusion()), nil)
+		}
+		return setLoggerAndProceed(false, fmt.Sprintf("workflow_run: unsupported action \"%s\"", gitEvent.GetAction()), nil)
+
 	case *github.IssueCommentEvent:
 		if gitEvent.GetAction() == "created" &&
 			gitEvent.GetIssue().IsPullRequest() &&
@@ -60,8 +82,19 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 			if provider.IsCancelComment(gitEvent.GetComment().GetBody()) {
 				return setLoggerAndProceed(true, "", nil)
 			}
+			if provider.IsHelpComment(gitEvent.GetComment().GetBody()) {
+				return setLoggerAndProceed(true, "", nil)
+			}
 			return setLoggerAndProceed(false, "", nil)
 		}
+		if gitEvent.GetAction() == "created" &&
+			!gitEvent.GetIssue().IsPullRequest() &&
+			gitEvent.GetIssue().GetState() == "open" {
+			if command, _ := provider.IssueCommand(gitEvent.GetComment().GetBody()); command != "" {
+				return setLoggerAndProceed(true, "", nil)
+			}
+			return setLoggerAndProceed(false, "issue: not a command comment", nil)
+		}
 		return setLoggerAndProceed(false, "issue: not a gitops pull request comment", nil)
 	case *github.PushEvent:
 		if gitEvent.GetPusher() != nil {
@@ -69,12 +102,38 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 		}
 		return setLoggerAndProceed(false, "push: no pusher in event", nil)
 
+	case *github.ReleaseEvent:
+		// GitHub sends the published action for the prereleases as well, the
+		// prereleased one is skipped to not run the PipelineRuns twice
+		if gitEvent.GetAction() == "published" && gitEvent.GetRelease() != nil {
+			return setLoggerAndProceed(true, "", nil)
+		}
+		return setLoggerAndProceed(false, fmt.Sprintf("release: unsupported action \"%s\"", gitEvent.GetAction()), nil)
+
+	case *github.PullRequestReviewEvent:
+		if gitEvent.GetAction() == "submitted" {
+			return setLoggerAndProceed(true, "", nil)
+		}
+		return setLoggerAndProceed(false, fmt.Sprintf("pull_request_review: unsupported action \"%s\"", gitEvent.GetAction()), nil)
+
+	case *github.PullRequestReviewCommentEvent:
+		if gitEvent.GetAction() == "created" {
+			return setLoggerAndProceed(true, "", nil)
+		}
+		return setLoggerAndProceed(false, fmt.Sprintf("pull_request_review_comment: unsupported action \"%s\"", gitEvent.GetAction()), nil)
+
 	case *github.PullRequestEvent:
-		if provider.Valid(gitEvent.GetAction(), []string{"opened", "synchronize", "synchronized", "reopened"}) {
+		if provider.Valid(gitEvent.GetAction(), []string{"opened", "synchronize", "synchronized", "reopened", "closed", "ready_for_review", "labeled", "unlabeled"}) {
 			return setLoggerAndProceed(true, "", nil)
 		}
 		return setLoggerAndProceed(false, fmt.Sprintf("pull_request: unsupported action \"%s\"", gitEvent.GetAction()), nil)
 
+	case *github.MergeGroupEvent:
+		if gitEvent.GetAction() == "checks_requested" {
+			return setLoggerAndProceed(true, "", nil)
+		}
+		return setLoggerAndProceed(false, fmt.Sprintf("merge_group: unsupported action \"%s\"", gitEvent.GetAction()), nil)
+
 	default:
 		return setLoggerAndProceed(false, fmt.Sprintf("github: event \"%v\" is not supported", event), nil)
 	}
@@ -46,6 +46,128 @@ func TestProvider_Detect(t *testing.T) {
 			isGH:       true,
 			processReq: true,
 		},
+		{
+			name: "check run requested action Event",
+			event: github.CheckRunEvent{
+				Action:          github.String("requested_action"),
+				RequestedAction: &github.RequestedAction{Identifier: "cancel"},
+				CheckRun:        &github.CheckRun{ID: github.Int64(123)},
+			},
+			eventType:  "check_run",
+			isGH:       true,
+			processReq: true,
+		},
+		{
+			name: "check run unsupported requested action Event",
+			event: github.CheckRunEvent{
+				Action:          github.String("requested_action"),
+				RequestedAction: &github.RequestedAction{Identifier: "delete"},
+				CheckRun:        &github.CheckRun{ID: github.Int64(123)},
+			},
+			eventType:  "check_run",
+			wantReason: "check_run: unsupported requested action \"delete\"",
+			isGH:       true,
+			processReq: false,
+		},
+		{
+			name: "successful check run Event",
+			event: github.CheckRunEvent{
+				Action: github.String("completed"),
+				CheckRun: &github.CheckRun{
+					Conclusion: github.String("success"),
+				},
+			},
+			eventType:  "check_run",
+			isGH:       true,
+			processReq: true,
+		},
+		{
+			name: "failed check run Event",
+			event: github.CheckRunEvent{
+				Action: github.String("completed"),
+				CheckRun: &github.CheckRun{
+					Conclusion: github.String("failure"),
+				},
+			},
+			eventType:  "check_run",
+			isGH:       true,
+			processReq: false,
+		},
+		{
+			name: "successful workflow run Event",
+			event: github.WorkflowRunEvent{
+				Action: github.String("completed"),
+				WorkflowRun: &github.WorkflowRun{
+					Conclusion: github.String("success"),
+				},
+			},
+			eventType:  "workflow_run",
+			isGH:       true,
+			processReq: true,
+		},
+		{
+			name: "failed workflow run Event",
+			event: github.WorkflowRunEvent{
+				Action: github.String("completed"),
+				WorkflowRun: &github.WorkflowRun{
+					Conclusion: github.String("failure"),
+				},
+			},
+			eventType:  "workflow_run",
+			isGH:       true,
+			processReq: false,
+		},
+		{
+			name: "requested workflow run Event",
+			event: github.WorkflowRunEvent{
+				Action: github.String("requested"),
+			},
+			eventType:  "workflow_run",
+			isGH:       true,
+			processReq: false,
+		},
+		{
+			name: "command commented on an issue",
+			event: github.IssueCommentEvent{
+				Action:  github.String("created"),
+				Issue:   &github.Issue{State: github.String("open")},
+				Comment: &github.IssueComment{Body: github.String("/deploy staging")},
+			},
+			eventType:  "issue_comment",
+			isGH:       true,
+			processReq: true,
+		},
+		{
+			name: "comment on an issue without a command",
+			event: github.IssueCommentEvent{
+				Action:  github.String("created"),
+				Issue:   &github.Issue{State: github.String("open")},
+				Comment: &github.IssueComment{Body: github.String("please deploy")},
+			},
+			eventType:  "issue_comment",
+			isGH:       true,
+			processReq: false,
+		},
+		{
+			name: "published release Event",
+			event: github.ReleaseEvent{
+				Action:  github.String("published"),
+				Release: &github.RepositoryRelease{TagName: github.String("v1.0.0")},
+			},
+			eventType:  "release",
+			isGH:       true,
+			processReq: true,
+		},
+		{
+			name: "prereleased release Event",
+			event: github.ReleaseEvent{
+				Action:  github.String("prereleased"),
+				Release: &github.RepositoryRelease{TagName: github.String("v1.0.0-rc1")},
+			},
+			eventType:  "release",
+			isGH:       true,
+			processReq: false,
+		},
 		{
 			name: "unsupported Event",
 			event: github.CommitCommentEvent{
@@ -187,6 +309,69 @@ func TestProvider_Detect(t *testing.T) {
 			isGH:       true,
 			processReq: true,
 		},
+		{
+			name: "pull request review submitted",
+			event: github.PullRequestReviewEvent{
+				Action: github.String("submitted"),
+			},
+			eventType:  "pull_request_review",
+			isGH:       true,
+			processReq: true,
+		},
+		{
+			name: "pull request review dismissed",
+			event: github.PullRequestReviewEvent{
+				Action: github.String("dismissed"),
+			},
+			eventType:  "pull_request_review",
+			isGH:       true,
+			processReq: false,
+		},
+		{
+			name: "merge group checks requested",
+			event: github.MergeGroupEvent{
+				Action: github.String("checks_requested"),
+			},
+			eventType:  "merge_group",
+			isGH:       true,
+			processReq: true,
+		},
+		{
+			name: "merge group not supported action",
+			event: github.MergeGroupEvent{
+				Action: github.String("destroyed"),
+			},
+			eventType:  "merge_group",
+			isGH:       true,
+			processReq: false,
+		},
+		{
+			name: "pull request ready for review event",
+			event: github.PullRequestEvent{
+				Action: github.String("ready_for_review"),
+			},
+			eventType:  "pull_request",
+			isGH:       true,
+			processReq: true,
+		},
+		{
+			name: "pull request labeled event",
+			event: github.PullRequestEvent{
+				Action: github.String("labeled"),
+			},
+			eventType:  "pull_request",
+			isGH:       true,
+			processReq: true,
+		},
+		{
+			name: "pull request closed event",
+			event: github.PullRequestEvent{
+				Action: github.String("closed"),
+			},
+			eventType:  "pull_request",
+			isGH:       true,
+			processReq: true,
+		},
 		{
 			name: "pull request event not supported action",
 			event: github.PullRequestEvent{
@@ -234,6 +419,25 @@ func TestProvider_Detect(t *testing.T) {
 			isGH:       true,
 			processReq: true,
 		},
+		{
+			name: "issue comment event with help comment",
+			event: github.IssueCommentEvent{
+				Action: github.String("created"),
+				Issue: &github.Issue{
+					PullRequestLinks: &github.PullRequestLinks{
+						URL: github.String("url"),
+					},
+					State: github.String("open"),
+				},
+				Installation: &github.Installation{
+					ID: github.Int64(123),
+				},
+				Comment: &github.IssueComment{Body: github.String("/pac help")},
+			},
+			eventType:  "issue_comment",
+			isGH:       true,
+			processReq: true,
+		},
 	}
 
 	for _, tt := range tests {
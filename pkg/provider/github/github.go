@@ -10,8 +10,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gobwas/glob"
 	"github.com/google/go-github/v49/github"
 	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/metrics"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
@@ -37,6 +39,7 @@ type Provider struct {
 	ApplicationID *int64
 	providerName  string
 	Run           *params.Run
+	Metrics       *metrics.Recorder
 	repositoryIDs []int64
 
 	skippedRun
@@ -112,7 +115,7 @@ func (v *Provider) GetTaskURI(ctx context.Context, _ *params.Run, event *info.Ev
 
 func (v *Provider) InitAppClient(ctx context.Context, kube kubernetes.Interface, event *info.Event) error {
 	var err error
-	event.Provider.Token, err = v.GetAppToken(ctx, kube, event.GHEURL, event.InstallationID)
+	event.Provider.Token, err = v.GetAppToken(ctx, kube, event.GHEURL, event.GitHubAppID, event.InstallationID)
 	if err != nil {
 		return err
 	}
@@ -163,7 +166,7 @@ func makeClient(ctx context.Context, apiURL, token string) (*github.Client, stri
 
 	providerName := "github"
 	if apiURL != "" && apiURL != apiPublicURL {
-		providerName = "github-enterprise"
+		providerName = providerEnterprise
 		client, _ = github.NewEnterpriseClient(apiURL, apiURL, tc)
 	} else {
 		client = github.NewClient(tc)
@@ -251,18 +254,25 @@ func (v *Provider) SetClient(ctx context.Context, run *params.Run, event *info.E
 
 // GetTektonDir Get all yaml files in tekton directory return as a single concated file
 func (v *Provider) GetTektonDir(ctx context.Context, runevent *info.Event, path string) (string, error) {
-	tektonDirSha := ""
-
-	rootobjects, _, err := v.Client.Git.GetTree(ctx, runevent.Organization, runevent.Repository, runevent.SHA, false)
-	if err != nil {
-		return "", err
-	}
-	for _, object := range rootobjects.Entries {
-		if object.GetPath() == path {
-			if object.GetType() != "tree" {
-				return "", fmt.Errorf("%s has been found but is not a directory", path)
+	// walk down the trees to the directory, it may be in a sub directory
+	// of the repository
+	tektonDirSha := runevent.SHA
+	for _, name := range strings.Split(strings.Trim(path, "/"), "/") {
+		objects, _, err := v.Client.Git.GetTree(ctx, runevent.Organization, runevent.Repository, tektonDirSha, false)
+		if err != nil {
+			return "", err
+		}
+		tektonDirSha = ""
+		for _, object := range objects.Entries {
+			if object.GetPath() == name {
+				if object.GetType() != "tree" {
+					return "", fmt.Errorf("%s has been found but is not a directory", path)
+				}
+				tektonDirSha = object.GetSHA()
 			}
-			tektonDirSha = object.GetSHA()
+		}
+		if tektonDirSha == "" {
+			break
 		}
 	}
 
@@ -295,7 +305,14 @@ func (v *Provider) GetCommitInfo(ctx context.Context, runevent *info.Event) erro
 	// use the branch as sha since github supports it
 	var commit *github.Commit
 	sha := runevent.SHA
-	if runevent.SHA == "" && runevent.HeadBranch != "" {
+	if runevent.SHA == "" && strings.HasPrefix(runevent.HeadBranch, "refs/tags/") {
+		// ie: a release, the commit api resolves the annotated tags as well
+		tagSHA, _, err := v.Client.Repositories.GetCommitSHA1(ctx, runevent.Organization, runevent.Repository, runevent.HeadBranch, "")
+		if err != nil {
+			return err
+		}
+		sha = tagSHA
+	} else if runevent.SHA == "" && runevent.HeadBranch != "" {
 		branchinfo, _, err := v.Client.Repositories.GetBranch(ctx, runevent.Organization, runevent.Repository, runevent.HeadBranch, true)
 		if err != nil {
 			return err
@@ -373,6 +390,10 @@ func (v *Provider) getPullRequest(ctx context.Context, runevent *info.Event) (*i
 	runevent.SHA = pr.GetHead().GetSHA()
 	runevent.SHAURL = fmt.Sprintf("%s/commit/%s", pr.GetHTMLURL(), pr.GetHead().GetSHA())
 	runevent.PullRequestTitle = pr.GetTitle()
+	runevent.PullRequestAuthor = pr.GetUser().GetLogin()
+	runevent.PullRequestFork = isForkPullRequest(pr)
+	runevent.PullRequestLabels = labelNames(pr.Labels)
+	runevent.PullRequestAssignees = userLogins(pr.Assignees)
 
 	// TODO: check if we really need this
 	if runevent.Sender == "" {
@@ -390,30 +411,98 @@ func (v *Provider) getPullRequest(ctx context.Context, runevent *info.Event) (*i
 
 // GetFiles get a files from pull request
 func (v *Provider) GetFiles(ctx context.Context, runevent *info.Event) ([]string, error) {
-	if runevent.TriggerTarget == "pull_request" {
-		repoCommit, _, err := v.Client.PullRequests.ListFiles(ctx, runevent.Organization, runevent.Repository, runevent.PullRequestNumber, &github.ListOptions{})
+	// a merge group has no pull request, its files are the changes of the
+	// pull requests of the group on top of the target branch
+	if mergeGroup, ok := runevent.Event.(*github.MergeGroupEvent); ok {
+		comparison, _, err := v.Client.Repositories.CompareCommits(ctx, runevent.Organization, runevent.Repository,
+			mergeGroup.GetMergeGroup().GetBaseSHA(), runevent.SHA, &github.ListOptions{})
 		if err != nil {
 			return []string{}, err
 		}
 		result := []string{}
-		for j := range repoCommit {
-			result = append(result, *repoCommit[j].Filename)
+		for _, file := range comparison.Files {
+			result = append(result, file.GetFilename())
 		}
 		return result, nil
 	}
 
+	if runevent.TriggerTarget == "pull_request" {
+		return v.listChangedFiles(fmt.Sprintf("pull request %d", runevent.PullRequestNumber),
+			func(opt *github.ListOptions) ([]*github.CommitFile, *github.Response, error) {
+				return v.Client.PullRequests.ListFiles(ctx, runevent.Organization, runevent.Repository, runevent.PullRequestNumber, opt)
+			})
+	}
+
 	if runevent.TriggerTarget == "push" {
-		result := []string{}
-		rC, _, err := v.Client.Repositories.GetCommit(ctx, runevent.Organization, runevent.Repository, runevent.SHA, &github.ListOptions{})
+		return v.listChangedFiles(fmt.Sprintf("commit %s", runevent.SHA),
+			func(opt *github.ListOptions) ([]*github.CommitFile, *github.Response, error) {
+				rC, resp, err := v.Client.Repositories.GetCommit(ctx, runevent.Organization, runevent.Repository, runevent.SHA, opt)
+				if err != nil {
+					return nil, resp, err
+				}
+				return rC.Files, resp, nil
+			})
+	}
+	return []string{}, nil
+}
+
+// listChangedFiles goes through the pages of the changed files of a pull
+// request or a commit, up to provider.MaxChangedFiles files.
+func (v *Provider) listChangedFiles(what string, list func(*github.ListOptions) ([]*github.CommitFile, *github.Response, error)) ([]string, error) {
+	result := []string{}
+	opt := &github.ListOptions{PerPage: provider.ChangedFilesPerPage}
+	for {
+		files, resp, err := list(opt)
 		if err != nil {
 			return []string{}, err
 		}
-		for i := range rC.Files {
-			result = append(result, *rC.Files[i].Filename)
+		for _, file := range files {
+			result = append(result, file.GetFilename())
 		}
-		return result, nil
+		if resp == nil || resp.NextPage == 0 {
+			return result, nil
+		}
+		if len(result) >= provider.MaxChangedFiles {
+			if v.Logger != nil {
+				v.Logger.Warnf("%s has more than %d changed files, only the first ones are matched", what, provider.MaxChangedFiles)
+			}
+			return result[:provider.MaxChangedFiles], nil
+		}
+		opt.Page = resp.NextPage
 	}
-	return []string{}, nil
+}
+
+// IsRefProtected check if the branch or the tag (refs/tags/) is protected on
+// the repository, tag protection rules are matched as globs.
+func (v *Provider) IsRefProtected(ctx context.Context, runevent *info.Event, ref string) (bool, error) {
+	if v.Client == nil {
+		return false, fmt.Errorf("no github client has been initiliazed, " +
+			"exiting... (hint: did you forget setting a secret on your repo?)")
+	}
+	if strings.HasPrefix(ref, "refs/tags/") {
+		tag := strings.TrimPrefix(ref, "refs/tags/")
+		protections, _, err := v.Client.Repositories.ListTagProtection(ctx, runevent.Organization, runevent.Repository)
+		if err != nil {
+			return false, err
+		}
+		for _, protection := range protections {
+			g, err := glob.Compile(protection.GetPattern())
+			if err != nil {
+				continue
+			}
+			if g.Match(tag) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	branch, _, err := v.Client.Repositories.GetBranch(ctx, runevent.Organization, runevent.Repository,
+		strings.TrimPrefix(ref, "refs/heads/"), true)
+	if err != nil {
+		return false, err
+	}
+	return branch.GetProtected(), nil
 }
 
 // getObject Get an object from a repository
@@ -7,12 +7,14 @@ import (
 	//nolint: gosec
 	"crypto/sha1"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"hash"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -20,8 +22,11 @@ import (
 	"github.com/google/go-github/v49/github"
 	"github.com/jonboulle/clockwork"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
 	"gotest.tools/v3/assert"
 	"knative.dev/pkg/ptr"
 	rtesting "knative.dev/pkg/reconciler/testing"
@@ -173,6 +178,41 @@ func TestGetTektonDir(t *testing.T) {
 	}
 }
 
+func TestGetTektonDirSubPath(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+	gvcs := Provider{Client: fakeclient}
+	event := &info.Event{Organization: "tekton", Repository: "cat", SHA: "123"}
+
+	trees := map[string]string{
+		"123":      `{"sha": "123", "tree": [{"path": "README.md", "type": "blob", "sha": "readme"}, {"path": "services", "type": "tree", "sha": "services"}]}`,
+		"services": `{"sha": "services", "tree": [{"path": "api", "type": "tree", "sha": "api"}]}`,
+		"api":      `{"sha": "api", "tree": [{"path": ".tekton", "type": "tree", "sha": "tekton"}]}`,
+		"tekton":   `{"sha": "tekton", "tree": [{"path": "pipelinerun.yaml", "type": "blob", "sha": "pipelinerun"}]}`,
+	}
+	for sha, tree := range trees {
+		tree := tree
+		mux.HandleFunc(fmt.Sprintf("/repos/tekton/cat/git/trees/%s", sha), func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, tree)
+		})
+	}
+	mux.HandleFunc("/repos/tekton/cat/git/blobs/pipelinerun", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"sha": "pipelinerun", "content": "%s"}`, base64.StdEncoding.EncodeToString([]byte("kind: PipelineRun")))
+	})
+
+	got, err := gvcs.GetTektonDir(ctx, event, "services/api/.tekton")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(got, "kind: PipelineRun"), "got %q", got)
+
+	got, err = gvcs.GetTektonDir(ctx, event, "services/web/.tekton")
+	assert.NilError(t, err)
+	assert.Equal(t, got, "")
+
+	_, err = gvcs.GetTektonDir(ctx, event, "README.md/.tekton")
+	assert.ErrorContains(t, err, "is not a directory")
+}
+
 func TestGetFileInsideRepo(t *testing.T) {
 	testGetTektonDir := []struct {
 		name       string
@@ -371,6 +411,7 @@ func TestGithubGetCommitInfo(t *testing.T) {
 		noclient          bool
 		apiReply, wantErr string
 		shaurl, shatitle  string
+		tagSHA            string
 	}{
 		{
 			name: "good",
@@ -391,6 +432,17 @@ func TestGithubGetCommitInfo(t *testing.T) {
 			},
 			apiReply: "hello moto",
 		},
+		{
+			name: "tag",
+			event: &info.Event{
+				Organization: "owner",
+				Repository:   "repository",
+				HeadBranch:   "refs/tags/v1.0.0",
+			},
+			tagSHA:   "shaoftag",
+			shaurl:   "https://git.provider/commit/tag",
+			shatitle: "Release 1.0.0",
+		},
 		{
 			name:     "noclient",
 			event:    &info.Event{},
@@ -402,13 +454,21 @@ func TestGithubGetCommitInfo(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
 			defer teardown()
+			sha := tt.event.SHA
+			if tt.tagSHA != "" {
+				sha = tt.tagSHA
+				mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/commits/%s",
+					tt.event.Organization, tt.event.Repository, tt.event.HeadBranch), func(rw http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(rw, tt.tagSHA)
+				})
+			}
 			mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/git/commits/%s",
-				tt.event.Organization, tt.event.Repository, tt.event.SHA), func(rw http.ResponseWriter, r *http.Request) {
+				tt.event.Organization, tt.event.Repository, sha), func(rw http.ResponseWriter, r *http.Request) {
 				if tt.apiReply != "" {
 					fmt.Fprintf(rw, tt.apiReply)
 					return
 				}
-				fmt.Fprintf(rw, `{"html_url": "%s", "message": "%s"}`, tt.shaurl, tt.shatitle)
+				fmt.Fprintf(rw, `{"sha": "%s", "html_url": "%s", "message": "%s"}`, sha, tt.shaurl, tt.shatitle)
 			})
 			ctx, _ := rtesting.SetupFakeContext(t)
 			provider := &Provider{Client: fakeclient}
@@ -422,6 +482,9 @@ func TestGithubGetCommitInfo(t *testing.T) {
 			}
 			assert.Equal(t, tt.shatitle, tt.event.SHATitle)
 			assert.Equal(t, tt.shaurl, tt.event.SHAURL)
+			if tt.tagSHA != "" {
+				assert.Equal(t, tt.tagSHA, tt.event.SHA)
+			}
 		})
 	}
 }
@@ -620,6 +683,93 @@ func TestGetFiles(t *testing.T) {
 	}
 }
 
+func TestGetFilesMergeGroup(t *testing.T) {
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+	mux.HandleFunc("/repos/owner/repo/compare/baseSHA...mergeGroupSHA", func(rw http.ResponseWriter, r *http.Request) {
+		b, _ := json.Marshal(&github.CommitsComparison{
+			Files: []*github.CommitFile{
+				{Filename: ptr.String("first.yaml")},
+				{Filename: ptr.String("second.doc")},
+			},
+		})
+		fmt.Fprint(rw, string(b))
+	})
+
+	event := &info.Event{
+		TriggerTarget: "pull_request",
+		EventType:     "merge_group",
+		Organization:  "owner",
+		Repository:    "repo",
+		SHA:           "mergeGroupSHA",
+		Event: &github.MergeGroupEvent{
+			MergeGroup: &github.MergeGroup{BaseSHA: github.String("baseSHA")},
+		},
+	}
+	ctx, _ := rtesting.SetupFakeContext(t)
+	provider := &Provider{Client: fakeclient}
+	fileData, err := provider.GetFiles(ctx, event)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, fileData, []string{"first.yaml", "second.doc"})
+}
+
+func TestGetFilesPagination(t *testing.T) {
+	tests := []struct {
+		name      string
+		lastPage  int
+		wantFiles int
+		wantWarn  bool
+	}{
+		{
+			name:      "all the pages",
+			lastPage:  3,
+			wantFiles: 3 * provider.ChangedFilesPerPage,
+		},
+		{
+			name:      "too many files",
+			lastPage:  -1,
+			wantFiles: provider.MaxChangedFiles,
+			wantWarn:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			mux.HandleFunc("/repos/owner/repo/pulls/10/files", func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.URL.Query().Get("per_page"), fmt.Sprintf("%d", provider.ChangedFilesPerPage))
+				page := 1
+				if p := r.URL.Query().Get("page"); p != "" {
+					page, _ = strconv.Atoi(p)
+				}
+				if tt.lastPage == -1 || page < tt.lastPage {
+					rw.Header().Set("Link", fmt.Sprintf(`<%s?page=%d>; rel="next"`, r.URL.Path, page+1))
+				}
+				files := []*github.CommitFile{}
+				for i := 0; i < provider.ChangedFilesPerPage; i++ {
+					files = append(files, &github.CommitFile{Filename: github.String(fmt.Sprintf("%d-%d.yaml", page, i))})
+				}
+				b, _ := json.Marshal(files)
+				fmt.Fprint(rw, string(b))
+			})
+
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, logs := zapobserver.New(zap.InfoLevel)
+			v := &Provider{Client: fakeclient, Logger: zap.New(observer).Sugar()}
+			fileData, err := v.GetFiles(ctx, &info.Event{
+				TriggerTarget:     "pull_request",
+				Organization:      "owner",
+				Repository:        "repo",
+				PullRequestNumber: 10,
+			})
+			assert.NilError(t, err)
+			assert.Equal(t, len(fileData), tt.wantFiles)
+			assert.Equal(t, fileData[len(fileData)-1], fmt.Sprintf("%d-%d.yaml", tt.wantFiles/provider.ChangedFilesPerPage, provider.ChangedFilesPerPage-1))
+			assert.Equal(t, logs.FilterMessageSnippet("only the first ones are matched").Len() == 1, tt.wantWarn)
+		})
+	}
+}
+
 func TestProvider_checkWebhookSecretValidity(t *testing.T) {
 	cw := clockwork.NewFakeClock()
 	tests := []struct {
@@ -767,3 +917,61 @@ func TestListRepos(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, data[0], "https://matched/by/incoming")
 }
+
+func TestIsRefProtected(t *testing.T) {
+	tests := []struct {
+		name          string
+		ref           string
+		wantProtected bool
+		wantErr       string
+	}{
+		{
+			name:          "protected branch",
+			ref:           "refs/heads/main",
+			wantProtected: true,
+		},
+		{
+			name: "unprotected branch",
+			ref:  "feature",
+		},
+		{
+			name:          "protected tag",
+			ref:           "refs/tags/v1.0.0",
+			wantProtected: true,
+		},
+		{
+			name: "unprotected tag",
+			ref:  "refs/tags/nightly",
+		},
+		{
+			name:    "unknown branch",
+			ref:     "nowhere",
+			wantErr: "404",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			mux.HandleFunc("/repos/owner/repository/branches/main", func(rw http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(rw, `{"name": "main", "protected": true}`)
+			})
+			mux.HandleFunc("/repos/owner/repository/branches/feature", func(rw http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(rw, `{"name": "feature", "protected": false}`)
+			})
+			mux.HandleFunc("/repos/owner/repository/tags/protection", func(rw http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(rw, `[{"id": 1, "pattern": "v*"}]`)
+			})
+			ctx, _ := rtesting.SetupFakeContext(t)
+			provider := &Provider{Client: fakeclient}
+			event := &info.Event{Organization: "owner", Repository: "repository"}
+			protected, err := provider.IsRefProtected(ctx, event, tt.ref)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, protected, tt.wantProtected)
+		})
+	}
+}
@@ -12,12 +12,13 @@ import (
 	"strings"
 
 	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/golang-jwt/jwt/v4"
 	ogh "github.com/google/go-github/v48/github"
 	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/acl"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -25,30 +26,59 @@ const (
 	secretName = "pipelines-as-code-secret"
 )
 
+// GetAppIDAndPrivateKey returns the id and the private key of the GitHub App
+// of the pipelines-as-code-secret, the first key of its key ring which can be
+// parsed
 func GetAppIDAndPrivateKey(ctx context.Context, kube kubernetes.Interface) (int64, []byte, error) {
 	// TODO: move this out of here
 	ns := os.Getenv("SYSTEM_NAMESPACE")
-	secret, err := kube.CoreV1().Secrets(ns).Get(ctx, secretName, v1.GetOptions{})
+	app, err := readAppCredentials(ctx, kube, ns, secretName)
 	if err != nil {
 		return 0, []byte{}, err
 	}
-
-	appID := secret.Data["github-application-id"]
-	applicationID, err := strconv.ParseInt(strings.TrimSpace(string(appID)), 10, 64)
-	if err != nil {
-		return 0, []byte{}, fmt.Errorf("could not parse the github application_id number from secret: %w", err)
+	for _, privateKey := range app.PrivateKeys {
+		if _, err := jwt.ParseRSAPrivateKeyFromPEM(privateKey.PEM); err == nil {
+			return app.ID, privateKey.PEM, nil
+		}
 	}
-
-	privateKey := secret.Data["github-private-key"]
-	return applicationID, privateKey, nil
+	return app.ID, app.PrivateKey, nil
 }
 
-func (v *Provider) GetAppToken(ctx context.Context, kube kubernetes.Interface, gheURL string, installationID int64) (string, error) {
-	applicationID, privateKey, err := GetAppIDAndPrivateKey(ctx, kube)
+// GetAppToken generates a token for an installation of the GitHub App with
+// the id appID, or of the app configured for the GitHub Enterprise host when
+// we don't know its id. The private keys of the key ring of the app are tried
+// in order, a key rejected by GitHub is skipped for the next one.
+func (v *Provider) GetAppToken(ctx context.Context, kube kubernetes.Interface, gheURL string, appID, installationID int64) (string, error) {
+	app, err := GetAppCredentials(ctx, kube, appID, gheURL)
 	if err != nil {
 		return "", err
 	}
+	if len(app.PrivateKeys) == 0 {
+		return "", fmt.Errorf("no private key for the github app %d in the secret %s", app.ID, app.SecretName)
+	}
+	applicationID := app.ID
 	v.ApplicationID = &applicationID
+
+	var errs []string
+	for _, privateKey := range app.PrivateKeys {
+		token, err := v.getInstallationToken(ctx, gheURL, applicationID, installationID, privateKey.PEM)
+		v.RecordAppKey(applicationID, privateKey.Name, err == nil)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", privateKey.Name, err))
+			continue
+		}
+		if len(errs) > 0 && v.Logger != nil {
+			v.Logger.Warnf("the github app %d authenticated with the private key %s, the previous keys failed: %s",
+				applicationID, privateKey.Name, strings.Join(errs, ", "))
+		}
+		return token, nil
+	}
+	return "", fmt.Errorf("no private key of the github app %d could generate a token: %s", applicationID, strings.Join(errs, ", "))
+}
+
+// getInstallationToken sets the client of the provider to the installation
+// of the app and generates its token with a private key.
+func (v *Provider) getInstallationToken(ctx context.Context, gheURL string, applicationID, installationID int64, privateKey []byte) (string, error) {
 	tr := http.DefaultTransport
 
 	itr, err := ghinstallation.New(tr, applicationID, installationID, privateKey)
@@ -85,6 +115,16 @@ func (v *Provider) GetAppToken(ctx context.Context, kube kubernetes.Interface, g
 	return token, err
 }
 
+// getAppIDFromHeaders returns the id of the GitHub App which has sent the
+// webhook, or 0 when it has been sent by a repository or organization webhook
+func getAppIDFromHeaders(request *http.Request) int64 {
+	if request.Header.Get("X-GitHub-Hook-Installation-Target-Type") != "integration" {
+		return 0
+	}
+	appID, _ := strconv.ParseInt(request.Header.Get("X-GitHub-Hook-Installation-Target-ID"), 10, 64)
+	return appID
+}
+
 func (v *Provider) parseEventType(request *http.Request, event *info.Event) error {
 	event.EventType = request.Header.Get("X-GitHub-Event")
 	if event.EventType == "" {
@@ -92,8 +132,10 @@ func (v *Provider) parseEventType(request *http.Request, event *info.Event) erro
 	}
 
 	event.Provider.URL = request.Header.Get("X-GitHub-Enterprise-Host")
+	event.GitHubAppID = getAppIDFromHeaders(request)
 
-	if event.EventType == "push" {
+	// releases are run like the pushes of their tag, by the maintainers
+	if event.EventType == "push" || event.EventType == "release" {
 		event.TriggerTarget = "push"
 	} else {
 		event.TriggerTarget = "pull_request"
@@ -144,7 +186,7 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 	installationIDFrompayload := getInstallationIDFromPayload(payload)
 	if installationIDFrompayload != -1 {
 		var err error
-		if event.Provider.Token, err = v.GetAppToken(ctx, run.Clients.Kube, event.Provider.URL, installationIDFrompayload); err != nil {
+		if event.Provider.Token, err = v.GetAppToken(ctx, run.Clients.Kube, event.Provider.URL, event.GitHubAppID, installationIDFrompayload); err != nil {
 			return nil, err
 		}
 	}
@@ -184,17 +226,37 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 		}
 		var err error
 		if processedEvent.Provider.Token, err = v.GetAppToken(ctx, run.Clients.Kube, event.Provider.URL,
-			installationIDFrompayload); err != nil {
+			event.GitHubAppID, installationIDFrompayload); err != nil {
 			return nil, err
 		}
 	}
 
 	processedEvent.InstallationID = installationIDFrompayload
 	processedEvent.GHEURL = event.Provider.URL
+	processedEvent.RepositoryPrivate = isPrivateRepository(payload)
+	// record the app the token has been generated with, when there are
+	// multiple apps the watcher needs the same one
+	processedEvent.GitHubAppID = event.GitHubAppID
+	if installationIDFrompayload != -1 && v.ApplicationID != nil {
+		processedEvent.GitHubAppID = *v.ApplicationID
+	}
 
 	return processedEvent, nil
 }
 
+// isPrivateRepository returns whether the repository of the payload is private
+func isPrivateRepository(payload string) bool {
+	repo := struct {
+		Repository struct {
+			Private bool `json:"private"`
+		} `json:"repository"`
+	}{}
+	if err := json.Unmarshal([]byte(payload), &repo); err != nil {
+		return false
+	}
+	return repo.Repository.Private
+}
+
 func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt interface{}) (*info.Event, error) {
 	var processedEvent *info.Event
 	var err error
@@ -205,14 +267,37 @@ func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt
 			return nil, fmt.Errorf("check run rerequest is only supported with github apps integration")
 		}
 
-		if *gitEvent.Action != "rerequested" {
-			return nil, fmt.Errorf("only issue recheck is supported in checkrunevent")
+		switch gitEvent.GetAction() {
+		case "rerequested":
+			processedEvent, err = v.handleReRequestEvent(ctx, gitEvent)
+		case "completed":
+			processedEvent, err = v.handleCheckRunCompletedEvent(ctx, event, gitEvent)
+		case "requested_action":
+			processedEvent, err = v.handleRequestedActionEvent(ctx, event, gitEvent)
+		default:
+			return nil, fmt.Errorf("only issue recheck, requested actions and completed check runs are supported in checkrunevent")
 		}
-		processedEvent, err = v.handleReRequestEvent(ctx, gitEvent)
+		if err != nil {
+			return nil, err
+		}
+	case *github.WorkflowRunEvent:
+		if v.Client == nil {
+			return nil, fmt.Errorf("completed workflow runs are only supported with github apps integration")
+		}
+		if gitEvent.GetAction() != "completed" {
+			return nil, fmt.Errorf("only completed workflow runs are supported in workflowrunevent")
+		}
+		processedEvent, err = v.handleWorkflowRunCompletedEvent(ctx, event, gitEvent)
 		if err != nil {
 			return nil, err
 		}
 	case *github.IssueCommentEvent:
+		if gitEvent.GetIssue() != nil && !gitEvent.GetIssue().IsPullRequest() {
+			if command, _ := provider.IssueCommand(gitEvent.GetComment().GetBody()); command != "" {
+				processedEvent = v.handleIssueCommandEvent(event, gitEvent)
+				break
+			}
+		}
 		if v.Client == nil {
 			return nil, fmt.Errorf("gitops style comments operation is only supported with github apps integration")
 		}
@@ -239,6 +324,7 @@ func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt
 		processedEvent.BaseBranch = gitEvent.GetRef()
 		processedEvent.EventType = event.TriggerTarget
 		processedEvent.HeadBranch = processedEvent.BaseBranch // in push events Head Branch is the same as Basebranch
+		processedEvent.BranchDeleted = gitEvent.GetDeleted() && strings.HasPrefix(gitEvent.GetRef(), "refs/heads/")
 	case *github.PullRequestEvent:
 		processedEvent = info.NewEvent()
 		processedEvent.Repository = gitEvent.GetRepo().GetName()
@@ -249,13 +335,70 @@ func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt
 		processedEvent.BaseBranch = gitEvent.GetPullRequest().Base.GetRef()
 		processedEvent.HeadBranch = gitEvent.GetPullRequest().Head.GetRef()
 		processedEvent.Sender = gitEvent.GetPullRequest().GetUser().GetLogin()
+		processedEvent.PullRequestAuthor = processedEvent.Sender
 		processedEvent.EventType = event.EventType
 		processedEvent.PullRequestNumber = gitEvent.GetPullRequest().GetNumber()
+		processedEvent.CancelInProgress = gitEvent.GetAction() == "closed"
+		processedEvent.PullRequestDraft = gitEvent.GetPullRequest().GetDraft()
+		processedEvent.PullRequestFork = isForkPullRequest(gitEvent.GetPullRequest())
+		processedEvent.PullRequestLabels = labelNames(gitEvent.GetPullRequest().Labels)
+		processedEvent.PullRequestAssignees = userLogins(gitEvent.GetPullRequest().Assignees)
+		switch gitEvent.GetAction() {
+		case "labeled":
+			processedEvent.LabelsAdded = []string{gitEvent.GetLabel().GetName()}
+		case "unlabeled":
+			processedEvent.LabelsRemoved = []string{gitEvent.GetLabel().GetName()}
+		}
 		// getting the repository ids of the base and head of the pull request
 		// to scope the token to
 		v.repositoryIDs = []int64{
 			gitEvent.GetPullRequest().GetBase().GetRepo().GetID(),
 		}
+	case *github.ReleaseEvent:
+		if gitEvent.GetAction() != "published" {
+			return nil, fmt.Errorf("only published releases are supported in releaseevent")
+		}
+		processedEvent = info.NewEvent()
+		processedEvent.Organization = gitEvent.GetRepo().GetOwner().GetLogin()
+		processedEvent.Repository = gitEvent.GetRepo().GetName()
+		processedEvent.DefaultBranch = gitEvent.GetRepo().GetDefaultBranch()
+		processedEvent.URL = gitEvent.GetRepo().GetHTMLURL()
+		processedEvent.Sender = gitEvent.GetSender().GetLogin()
+		// the sha of the tag is resolved with the commit info
+		processedEvent.BaseBranch = "refs/tags/" + gitEvent.GetRelease().GetTagName()
+		processedEvent.HeadBranch = processedEvent.BaseBranch
+		processedEvent.EventType = event.EventType
+		processedEvent.ReleaseTag = gitEvent.GetRelease().GetTagName()
+		processedEvent.ReleaseName = gitEvent.GetRelease().GetName()
+		processedEvent.ReleasePrerelease = gitEvent.GetRelease().GetPrerelease()
+		v.repositoryIDs = []int64{gitEvent.GetRepo().GetID()}
+	case *github.PullRequestReviewEvent:
+		processedEvent = newPullRequestReviewEvent(event, gitEvent.GetRepo(), gitEvent.GetPullRequest())
+		// the reviewer is the one triggering the run, the ACL are checked against it
+		processedEvent.Sender = gitEvent.GetReview().GetUser().GetLogin()
+		processedEvent.Reviewer = gitEvent.GetReview().GetUser().GetLogin()
+		processedEvent.ReviewState = strings.ToLower(gitEvent.GetReview().GetState())
+		v.repositoryIDs = []int64{gitEvent.GetPullRequest().GetBase().GetRepo().GetID()}
+	case *github.PullRequestReviewCommentEvent:
+		processedEvent = newPullRequestReviewEvent(event, gitEvent.GetRepo(), gitEvent.GetPullRequest())
+		processedEvent.Sender = gitEvent.GetComment().GetUser().GetLogin()
+		processedEvent.Reviewer = gitEvent.GetComment().GetUser().GetLogin()
+		v.repositoryIDs = []int64{gitEvent.GetPullRequest().GetBase().GetRepo().GetID()}
+	case *github.MergeGroupEvent:
+		// the merge queue tests the pull requests merged on top of the target
+		// branch on a temporary branch, the checks are reported on its sha
+		processedEvent = info.NewEvent()
+		processedEvent.Organization = gitEvent.GetRepo().GetOwner().GetLogin()
+		processedEvent.Repository = gitEvent.GetRepo().GetName()
+		processedEvent.DefaultBranch = gitEvent.GetRepo().GetDefaultBranch()
+		processedEvent.URL = gitEvent.GetRepo().GetHTMLURL()
+		processedEvent.SHA = gitEvent.GetMergeGroup().GetHeadSHA()
+		processedEvent.SHATitle = gitEvent.GetMergeGroup().GetHeadCommit().GetMessage()
+		processedEvent.BaseBranch = strings.TrimPrefix(gitEvent.GetMergeGroup().GetBaseRef(), "refs/heads/")
+		processedEvent.HeadBranch = strings.TrimPrefix(gitEvent.GetMergeGroup().GetHeadRef(), "refs/heads/")
+		processedEvent.Sender = gitEvent.GetSender().GetLogin()
+		processedEvent.EventType = event.EventType
+		v.repositoryIDs = []int64{gitEvent.GetRepo().GetID()}
 	default:
 		return nil, errors.New("this event is not supported")
 	}
@@ -267,6 +410,50 @@ func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt
 	return processedEvent, nil
 }
 
+// newPullRequestReviewEvent create an event from the pull request of a review
+// or review comment event
+func newPullRequestReviewEvent(event *info.Event, repo *github.Repository, pr *github.PullRequest) *info.Event {
+	processedEvent := info.NewEvent()
+	processedEvent.Repository = repo.GetName()
+	processedEvent.Organization = repo.GetOwner().GetLogin()
+	processedEvent.DefaultBranch = repo.GetDefaultBranch()
+	processedEvent.URL = repo.GetHTMLURL()
+	processedEvent.SHA = pr.GetHead().GetSHA()
+	processedEvent.BaseBranch = pr.GetBase().GetRef()
+	processedEvent.HeadBranch = pr.GetHead().GetRef()
+	processedEvent.PullRequestNumber = pr.GetNumber()
+	processedEvent.PullRequestTitle = pr.GetTitle()
+	processedEvent.PullRequestFork = isForkPullRequest(pr)
+	processedEvent.PullRequestLabels = labelNames(pr.Labels)
+	processedEvent.PullRequestAssignees = userLogins(pr.Assignees)
+	processedEvent.EventType = event.EventType
+	return processedEvent
+}
+
+// labelNames returns the names of the labels of a pull request
+func labelNames(labels []*github.Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		names = append(names, label.GetName())
+	}
+	return names
+}
+
+func userLogins(users []*github.User) []string {
+	logins := make([]string, 0, len(users))
+	for _, user := range users {
+		logins = append(logins, user.GetLogin())
+	}
+	return logins
+}
+
+// isForkPullRequest returns whether the head of a pull request is in another
+// repository than its base, the head repository is gone when the fork has
+// been deleted.
+func isForkPullRequest(pr *github.PullRequest) bool {
+	return pr.GetHead().GetRepo() == nil || pr.GetHead().GetRepo().GetID() != pr.GetBase().GetRepo().GetID()
+}
+
 func (v *Provider) handleReRequestEvent(ctx context.Context, event *github.CheckRunEvent) (*info.Event, error) {
 	runevent := info.NewEvent()
 	runevent.Organization = event.GetRepo().GetOwner().GetLogin()
@@ -289,6 +476,165 @@ func (v *Provider) handleReRequestEvent(ctx context.Context, event *github.Check
 	return v.getPullRequest(ctx, runevent)
 }
 
+// handleCheckRunCompletedEvent create the event of the pull request or of the
+// push a check run of another app has successfully completed on, only the
+// PipelineRuns waiting on that check run are matched to it.
+func (v *Provider) handleCheckRunCompletedEvent(ctx context.Context, event *info.Event, checkRunEvent *github.CheckRunEvent) (*info.Event, error) {
+	checkRun := checkRunEvent.GetCheckRun()
+	if v.ApplicationID != nil && checkRun.GetApp().GetID() == *v.ApplicationID {
+		return nil, fmt.Errorf("check run %s has been created by Pipelines as Code, skipping", checkRun.GetName())
+	}
+
+	runevent := info.NewEvent()
+	runevent.Organization = checkRunEvent.GetRepo().GetOwner().GetLogin()
+	runevent.Repository = checkRunEvent.GetRepo().GetName()
+	runevent.URL = checkRunEvent.GetRepo().GetHTMLURL()
+	runevent.DefaultBranch = checkRunEvent.GetRepo().GetDefaultBranch()
+	runevent.SHA = checkRun.GetCheckSuite().GetHeadSHA()
+	runevent.HeadBranch = checkRun.GetCheckSuite().GetHeadBranch()
+	v.repositoryIDs = []int64{checkRunEvent.GetRepo().GetID()}
+	var err error
+	if len(checkRun.GetCheckSuite().PullRequests) == 0 {
+		if runevent, err = v.resolveUnlinkedCheckSuite(ctx, event, runevent); err != nil {
+			return nil, err
+		}
+		if runevent.EventType == "push" {
+			runevent.Sender = checkRunEvent.GetSender().GetLogin()
+		}
+	} else {
+		runevent.PullRequestNumber = checkRun.GetCheckSuite().PullRequests[0].GetNumber()
+		if runevent, err = v.getPullRequest(ctx, runevent); err != nil {
+			return nil, err
+		}
+	}
+	runevent.CheckRunName = checkRun.GetName()
+	v.Logger.Infof("Check run %s has completed successfully on %s/%s@%s", runevent.CheckRunName, runevent.Organization, runevent.Repository, runevent.SHA)
+	return runevent, nil
+}
+
+// resolveUnlinkedCheckSuite create the event of a check suite GitHub has not
+// linked to a pull request. GitHub never links the check suites of the pull
+// requests from forks, the check suite is a push only when its commit is the
+// head of its branch in the repository, otherwise it is the open pull request
+// whose head is the commit.
+func (v *Provider) resolveUnlinkedCheckSuite(ctx context.Context, event, runevent *info.Event) (*info.Event, error) {
+	branch, resp, err := v.Client.Repositories.GetBranch(ctx, runevent.Organization, runevent.Repository, runevent.HeadBranch, false)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return nil, err
+	}
+	if err == nil && branch.GetCommit().GetSHA() == runevent.SHA {
+		runevent.BaseBranch = runevent.HeadBranch
+		runevent.EventType = "push"
+		event.TriggerTarget = "push"
+		return runevent, nil
+	}
+
+	pulls, _, err := v.Client.PullRequests.ListPullRequestsWithCommit(ctx, runevent.Organization, runevent.Repository, runevent.SHA,
+		&github.PullRequestListOptions{State: "open"})
+	if err != nil {
+		return nil, err
+	}
+	for _, pr := range pulls {
+		if pr.GetHead().GetSHA() == runevent.SHA {
+			runevent.PullRequestNumber = pr.GetNumber()
+			return v.getPullRequest(ctx, runevent)
+		}
+	}
+	return nil, fmt.Errorf("cannot find the pull request or the branch of the commit %s of the check suite, skipping", runevent.SHA)
+}
+
+// requestedActionIdentifier return the identifier of the action requested on a
+// check run.
+func requestedActionIdentifier(event *github.CheckRunEvent) string {
+	if event.GetRequestedAction() == nil {
+		return ""
+	}
+	return event.GetRequestedAction().Identifier
+}
+
+// handleRequestedActionEvent create the event of an action requested on a
+// check run of Pipelines as Code from the GitHub Checks UI, it cancels or runs
+// again the PipelineRun of the check run on behalf of the user who requested
+// it.
+func (v *Provider) handleRequestedActionEvent(ctx context.Context, event *info.Event, checkRunEvent *github.CheckRunEvent) (*info.Event, error) {
+	checkRun := checkRunEvent.GetCheckRun()
+	identifier := requestedActionIdentifier(checkRunEvent)
+	if identifier != checkRunActionCancel && identifier != checkRunActionRerun {
+		return nil, fmt.Errorf("check run action %s is not supported", identifier)
+	}
+
+	runevent := info.NewEvent()
+	runevent.Organization = checkRunEvent.GetRepo().GetOwner().GetLogin()
+	runevent.Repository = checkRunEvent.GetRepo().GetName()
+	runevent.URL = checkRunEvent.GetRepo().GetHTMLURL()
+	runevent.DefaultBranch = checkRunEvent.GetRepo().GetDefaultBranch()
+	runevent.SHA = checkRun.GetHeadSHA()
+	runevent.HeadBranch = checkRun.GetCheckSuite().GetHeadBranch()
+	runevent.Sender = checkRunEvent.GetSender().GetLogin()
+	v.repositoryIDs = []int64{checkRunEvent.GetRepo().GetID()}
+	var err error
+	if len(checkRun.GetCheckSuite().PullRequests) == 0 {
+		if runevent, err = v.resolveUnlinkedCheckSuite(ctx, event, runevent); err != nil {
+			return nil, err
+		}
+	} else {
+		runevent.PullRequestNumber = checkRun.GetCheckSuite().PullRequests[0].GetNumber()
+		if runevent, err = v.getPullRequest(ctx, runevent); err != nil {
+			return nil, err
+		}
+	}
+
+	pipelineRun := checkRunPipelineRunName(checkRun.GetName())
+	if identifier == checkRunActionCancel {
+		// the pull request may have new commits, cancel the PipelineRun
+		// of the commit of the check run
+		runevent.SHA = checkRun.GetHeadSHA()
+		runevent.CancelPipelineRuns = true
+		runevent.TargetCancelPipelineRun = pipelineRun
+	} else {
+		runevent.TargetTestPipelineRun = pipelineRun
+	}
+	v.Logger.Infof("check_run: %s of pipelinerun %s on %s/%s@%s has been requested by %s", identifier, pipelineRun,
+		runevent.Organization, runevent.Repository, runevent.SHA, runevent.Sender)
+	return runevent, nil
+}
+
+// handleWorkflowRunCompletedEvent create the event of the pull request or of
+// the push a GitHub Actions workflow has successfully completed on, only the
+// PipelineRuns waiting on that workflow are matched to it.
+func (v *Provider) handleWorkflowRunCompletedEvent(ctx context.Context, event *info.Event, workflowRunEvent *github.WorkflowRunEvent) (*info.Event, error) {
+	workflowRun := workflowRunEvent.GetWorkflowRun()
+
+	runevent := info.NewEvent()
+	runevent.Organization = workflowRunEvent.GetRepo().GetOwner().GetLogin()
+	runevent.Repository = workflowRunEvent.GetRepo().GetName()
+	runevent.URL = workflowRunEvent.GetRepo().GetHTMLURL()
+	runevent.DefaultBranch = workflowRunEvent.GetRepo().GetDefaultBranch()
+	runevent.SHA = workflowRun.GetHeadSHA()
+	runevent.HeadBranch = workflowRun.GetHeadBranch()
+	v.repositoryIDs = []int64{workflowRunEvent.GetRepo().GetID()}
+	if len(workflowRun.PullRequests) == 0 {
+		// GitHub does not link the workflow runs of the pull requests from
+		// forks to them, we cannot tell which pull request it ran on
+		if strings.HasPrefix(workflowRun.GetEvent(), "pull_request") {
+			return nil, fmt.Errorf("cannot find the pull request workflow run %s has completed on, skipping", workflowRun.GetName())
+		}
+		runevent.BaseBranch = runevent.HeadBranch
+		runevent.EventType = "push"
+		event.TriggerTarget = "push"
+		runevent.Sender = workflowRunEvent.GetSender().GetLogin()
+	} else {
+		runevent.PullRequestNumber = workflowRun.PullRequests[0].GetNumber()
+		var err error
+		if runevent, err = v.getPullRequest(ctx, runevent); err != nil {
+			return nil, err
+		}
+	}
+	runevent.WorkflowRunName = workflowRun.GetName()
+	v.Logger.Infof("Workflow run %s has completed successfully on %s/%s@%s", runevent.WorkflowRunName, runevent.Organization, runevent.Repository, runevent.SHA)
+	return runevent, nil
+}
+
 func convertPullRequestURLtoNumber(pullRequest string) (int, error) {
 	prNumber, err := strconv.Atoi(path.Base(pullRequest))
 	if err != nil {
@@ -297,6 +643,34 @@ func convertPullRequestURLtoNumber(pullRequest string) (int, error) {
 	return prNumber, nil
 }
 
+// handleIssueCommandEvent create the event of a command commented on an issue,
+// not a pull request. The PipelineRuns waiting on the command with the
+// on-issue-comment annotation are run on the default branch.
+func (v *Provider) handleIssueCommandEvent(event *info.Event, issueCommentEvent *github.IssueCommentEvent) *info.Event {
+	runevent := info.NewEvent()
+	runevent.Organization = issueCommentEvent.GetRepo().GetOwner().GetLogin()
+	runevent.Repository = issueCommentEvent.GetRepo().GetName()
+	runevent.DefaultBranch = issueCommentEvent.GetRepo().GetDefaultBranch()
+	runevent.URL = issueCommentEvent.GetRepo().GetHTMLURL()
+	// the sha of the default branch is resolved with the commit info
+	runevent.BaseBranch = runevent.DefaultBranch
+	runevent.HeadBranch = runevent.DefaultBranch
+	runevent.Sender = issueCommentEvent.GetComment().GetUser().GetLogin()
+	runevent.EventType = event.EventType
+	event.TriggerTarget = "issue_comment"
+	runevent.IssueNumber = issueCommentEvent.GetIssue().GetNumber()
+	runevent.IssueTitle = issueCommentEvent.GetIssue().GetTitle()
+	// the permission of the command is set in the comment_commands of the
+	// Repository, like the gitops commands of the pull requests
+	runevent.CommentCommand, runevent.IssueCommentArgs = provider.IssueCommand(issueCommentEvent.GetComment().GetBody())
+	if provider.IsHelpComment(issueCommentEvent.GetComment().GetBody()) {
+		runevent.CommentCommand = acl.CommandHelp
+	}
+	v.repositoryIDs = []int64{issueCommentEvent.GetRepo().GetID()}
+	v.Logger.Infof("issue_comment: /%s has been commented on %s/%s#%d", runevent.CommentCommand, runevent.Organization, runevent.Repository, runevent.IssueNumber)
+	return runevent
+}
+
 func (v *Provider) handleIssueCommentEvent(ctx context.Context, event *github.IssueCommentEvent) (*info.Event, error) {
 	action := "recheck"
 	runevent := info.NewEvent()
@@ -309,9 +683,11 @@ func (v *Provider) handleIssueCommentEvent(ctx context.Context, event *github.Is
 		return info.NewEvent(), fmt.Errorf("issue comment is not coming from a pull_request")
 	}
 
+	runevent.CommentCommand = provider.CommentCommand(event.GetComment().GetBody())
 	// if it is a /test or /retest comment with pipelinerun name figure out the pipelinerun name
 	if provider.IsTestRetestComment(event.GetComment().GetBody()) {
 		runevent.TargetTestPipelineRun = provider.GetPipelineRunFromTestComment(event.GetComment().GetBody())
+		runevent.TestParams = provider.GetParamsFromTestComment(event.GetComment().GetBody())
 	}
 	if provider.IsCancelComment(event.GetComment().GetBody()) {
 		action = "cancellation"
@@ -93,6 +93,28 @@ func TestParsePayLoad(t *testing.T) {
 		shaRet                  string
 		targetPipelinerun       string
 		targetCancelPipelinerun string
+		wantTestParams          map[string]string
+		wantCancelInProgress    bool
+		wantBranchDeleted       bool
+		wantReviewer            string
+		wantReviewState         string
+		wantBaseBranch          string
+		wantDraft               bool
+		wantLabels              []string
+		wantAssignees           []string
+		wantLabelsAdded         []string
+		wantLabelsRemoved       []string
+		wantCheckRunName        string
+		wantWorkflowRunName     string
+		wantReleaseTag          string
+		wantReleaseName         string
+		wantReleasePrerelease   bool
+		wantIssueNumber         int
+		wantCommentCommand      string
+		wantIssueCommentArgs    string
+		applicationID           *int64
+		wantHeadBranch          string
+		wantEventType           string
 	}{
 		{
 			name:          "bad/unknow event",
@@ -110,13 +132,13 @@ func TestParsePayLoad(t *testing.T) {
 		{
 			name:               "bad/not supported",
 			wantErrString:      "this event is not supported",
-			eventType:          "pull_request_review_comment",
+			eventType:          "commit_comment",
 			triggerTarget:      "pull_request",
-			payloadEventStruct: github.PullRequestReviewCommentEvent{Action: github.String("created")},
+			payloadEventStruct: github.CommitCommentEvent{Action: github.String("created")},
 		},
 		{
 			name:               "bad/check run only issue recheck supported",
-			wantErrString:      "only issue recheck is supported",
+			wantErrString:      "only issue recheck, requested actions and completed check runs are supported",
 			eventType:          "check_run",
 			triggerTarget:      "nonopetitrobot",
 			payloadEventStruct: github.CheckRunEvent{Action: github.String("created")},
@@ -208,6 +230,206 @@ func TestParsePayLoad(t *testing.T) {
 			},
 			shaRet: "headSHACheckSuite",
 		},
+		{
+			name:          "good/completed check run on push",
+			eventType:     "check_run",
+			triggerTarget: "push",
+			githubClient:  fakeclient,
+			payloadEventStruct: github.CheckRunEvent{
+				Action: github.String("completed"),
+				Repo:   sampleRepo,
+				CheckRun: &github.CheckRun{
+					Name:       github.String("security-scan"),
+					Conclusion: github.String("success"),
+					CheckSuite: &github.CheckSuite{
+						HeadSHA:    github.String("headSHACheckSuite"),
+						HeadBranch: github.String("main"),
+					},
+				},
+			},
+			muxReplies: map[string]interface{}{"/repos/owner/reponame/branches/main": github.Branch{
+				Commit: &github.RepositoryCommit{SHA: github.String("headSHACheckSuite")},
+			}},
+			shaRet:           "headSHACheckSuite",
+			wantBaseBranch:   "main",
+			wantHeadBranch:   "main",
+			wantCheckRunName: "security-scan",
+			wantEventType:    "push",
+		},
+		{
+			name:          "good/completed check run on pull request",
+			eventType:     "check_run",
+			triggerTarget: "pull_request",
+			githubClient:  fakeclient,
+			payloadEventStruct: github.CheckRunEvent{
+				Action: github.String("completed"),
+				Repo:   sampleRepo,
+				CheckRun: &github.CheckRun{
+					Name:       github.String("security-scan"),
+					Conclusion: github.String("success"),
+					CheckSuite: &github.CheckSuite{
+						PullRequests: []*github.PullRequest{{Number: github.Int(6666)}},
+					},
+				},
+			},
+			muxReplies: map[string]interface{}{"/repos/owner/reponame/pulls/6666": github.PullRequest{
+				Number: github.Int(6666),
+				Head:   &github.PullRequestBranch{SHA: github.String("checkRunPRsha"), Ref: github.String("feature")},
+				Base:   &github.PullRequestBranch{Ref: github.String("main"), Repo: sampleRepo},
+			}},
+			shaRet:           "checkRunPRsha",
+			wantBaseBranch:   "main",
+			wantHeadBranch:   "feature",
+			wantCheckRunName: "security-scan",
+		},
+		{
+			name:          "good/completed check run on pull request from a fork",
+			eventType:     "check_run",
+			triggerTarget: "pull_request",
+			githubClient:  fakeclient,
+			payloadEventStruct: github.CheckRunEvent{
+				Action: github.String("completed"),
+				Repo:   sampleRepo,
+				CheckRun: &github.CheckRun{
+					Name:       github.String("security-scan"),
+					Conclusion: github.String("success"),
+					CheckSuite: &github.CheckSuite{
+						HeadSHA:    github.String("forkCheckRunSHA"),
+						HeadBranch: github.String("fork-feature"),
+					},
+				},
+			},
+			muxReplies: map[string]interface{}{
+				"/repos/owner/reponame/commits/forkCheckRunSHA/pulls": []github.PullRequest{{
+					Number: github.Int(6667),
+					Head:   &github.PullRequestBranch{SHA: github.String("forkCheckRunSHA")},
+				}},
+				"/repos/owner/reponame/pulls/6667": github.PullRequest{
+					Number: github.Int(6667),
+					User:   &github.User{Login: github.String("forker")},
+					Head: &github.PullRequestBranch{
+						SHA:  github.String("forkCheckRunSHA"),
+						Ref:  github.String("fork-feature"),
+						Repo: &github.Repository{ID: github.Int64(2), Name: github.String("reponame")},
+					},
+					Base: &github.PullRequestBranch{Ref: github.String("main"), Repo: sampleRepo},
+				},
+			},
+			shaRet:           "forkCheckRunSHA",
+			wantBaseBranch:   "main",
+			wantHeadBranch:   "fork-feature",
+			wantCheckRunName: "security-scan",
+			wantEventType:    "pull_request",
+		},
+		{
+			name:          "bad/completed check run on a commit of no branch or pull request",
+			wantErrString: "cannot find the pull request or the branch of the commit orphanSHA",
+			eventType:     "check_run",
+			triggerTarget: "pull_request",
+			githubClient:  fakeclient,
+			payloadEventStruct: github.CheckRunEvent{
+				Action: github.String("completed"),
+				Repo:   sampleRepo,
+				CheckRun: &github.CheckRun{
+					Name:       github.String("security-scan"),
+					Conclusion: github.String("success"),
+					CheckSuite: &github.CheckSuite{
+						HeadSHA:    github.String("orphanSHA"),
+						HeadBranch: github.String("closed-feature"),
+					},
+				},
+			},
+			muxReplies: map[string]interface{}{"/repos/owner/reponame/commits/orphanSHA/pulls": []github.PullRequest{}},
+		},
+		{
+			name:          "bad/completed check run of pipelines as code",
+			wantErrString: "has been created by Pipelines as Code",
+			eventType:     "check_run",
+			triggerTarget: "pull_request",
+			githubClient:  fakeclient,
+			applicationID: github.Int64(1234),
+			payloadEventStruct: github.CheckRunEvent{
+				Action: github.String("completed"),
+				Repo:   sampleRepo,
+				CheckRun: &github.CheckRun{
+					Name:       github.String("Pipelines as Code CI / pipeline"),
+					Conclusion: github.String("success"),
+					App:        &github.App{ID: github.Int64(1234)},
+				},
+			},
+		},
+		{
+			name:          "good/completed workflow run on push",
+			eventType:     "workflow_run",
+			triggerTarget: "push",
+			githubClient:  fakeclient,
+			payloadEventStruct: github.WorkflowRunEvent{
+				Action: github.String("completed"),
+				Repo:   sampleRepo,
+				WorkflowRun: &github.WorkflowRun{
+					Name:       github.String("build"),
+					Event:      github.String("push"),
+					Conclusion: github.String("success"),
+					HeadSHA:    github.String("workflowRunSHA"),
+					HeadBranch: github.String("main"),
+				},
+			},
+			shaRet:              "workflowRunSHA",
+			wantBaseBranch:      "main",
+			wantHeadBranch:      "main",
+			wantWorkflowRunName: "build",
+		},
+		{
+			name:          "good/completed workflow run on pull request",
+			eventType:     "workflow_run",
+			triggerTarget: "pull_request",
+			githubClient:  fakeclient,
+			payloadEventStruct: github.WorkflowRunEvent{
+				Action: github.String("completed"),
+				Repo:   sampleRepo,
+				WorkflowRun: &github.WorkflowRun{
+					Name:         github.String("build"),
+					Event:        github.String("pull_request"),
+					Conclusion:   github.String("success"),
+					PullRequests: []*github.PullRequest{{Number: github.Int(7777)}},
+				},
+			},
+			muxReplies: map[string]interface{}{"/repos/owner/reponame/pulls/7777": github.PullRequest{
+				Number: github.Int(7777),
+				Head:   &github.PullRequestBranch{SHA: github.String("workflowRunPRsha"), Ref: github.String("feature")},
+				Base:   &github.PullRequestBranch{Ref: github.String("main"), Repo: sampleRepo},
+			}},
+			shaRet:              "workflowRunPRsha",
+			wantBaseBranch:      "main",
+			wantHeadBranch:      "feature",
+			wantWorkflowRunName: "build",
+		},
+		{
+			name:          "bad/completed workflow run on a pull request from a fork",
+			wantErrString: "cannot find the pull request workflow run build has completed on",
+			eventType:     "workflow_run",
+			triggerTarget: "pull_request",
+			githubClient:  fakeclient,
+			payloadEventStruct: github.WorkflowRunEvent{
+				Action: github.String("completed"),
+				Repo:   sampleRepo,
+				WorkflowRun: &github.WorkflowRun{
+					Name:       github.String("build"),
+					Event:      github.String("pull_request"),
+					Conclusion: github.String("success"),
+				},
+			},
+		},
+		{
+			name:          "bad/completed workflow run without github app",
+			wantErrString: "completed workflow runs are only supported with github apps integration",
+			eventType:     "workflow_run",
+			triggerTarget: "push",
+			payloadEventStruct: github.WorkflowRunEvent{
+				Action: github.String("completed"),
+				Repo:   sampleRepo,
+			},
+		},
 		{
 			name:          "good/issue comment",
 			eventType:     "issue_comment",
@@ -231,6 +453,121 @@ func TestParsePayLoad(t *testing.T) {
 			payloadEventStruct: samplePRevent,
 			shaRet:             "sampleHeadsha",
 		},
+		{
+			name:          "good/pull request closed",
+			eventType:     "pull_request",
+			triggerTarget: "pull_request",
+			payloadEventStruct: github.PullRequestEvent{
+				Action:      github.String("closed"),
+				PullRequest: samplePRevent.PullRequest,
+				Repo:        sampleRepo,
+			},
+			shaRet:               "sampleHeadsha",
+			wantCancelInProgress: true,
+		},
+		{
+			name:          "good/draft pull request",
+			eventType:     "pull_request",
+			triggerTarget: "pull_request",
+			payloadEventStruct: github.PullRequestEvent{
+				Action: github.String("opened"),
+				PullRequest: &github.PullRequest{
+					Head:  samplePRevent.PullRequest.Head,
+					Base:  samplePRevent.PullRequest.Base,
+					Draft: github.Bool(true),
+				},
+				Repo: sampleRepo,
+			},
+			shaRet:    "sampleHeadsha",
+			wantDraft: true,
+		},
+		{
+			name:          "good/pull request labeled",
+			eventType:     "pull_request",
+			triggerTarget: "pull_request",
+			payloadEventStruct: github.PullRequestEvent{
+				Action: github.String("labeled"),
+				Label:  &github.Label{Name: github.String("e2e")},
+				PullRequest: &github.PullRequest{
+					Head:      samplePRevent.PullRequest.Head,
+					Base:      samplePRevent.PullRequest.Base,
+					Labels:    []*github.Label{{Name: github.String("bug")}, {Name: github.String("e2e")}},
+					Assignees: []*github.User{{Login: github.String("reviewer")}},
+				},
+				Repo: sampleRepo,
+			},
+			shaRet:          "sampleHeadsha",
+			wantLabels:      []string{"bug", "e2e"},
+			wantAssignees:   []string{"reviewer"},
+			wantLabelsAdded: []string{"e2e"},
+		},
+		{
+			name:          "good/pull request unlabeled",
+			eventType:     "pull_request",
+			triggerTarget: "pull_request",
+			payloadEventStruct: github.PullRequestEvent{
+				Action: github.String("unlabeled"),
+				Label:  &github.Label{Name: github.String("e2e")},
+				PullRequest: &github.PullRequest{
+					Head: samplePRevent.PullRequest.Head,
+					Base: samplePRevent.PullRequest.Base,
+				},
+				Repo: sampleRepo,
+			},
+			shaRet:            "sampleHeadsha",
+			wantLabels:        []string{},
+			wantLabelsRemoved: []string{"e2e"},
+		},
+		{
+			name:          "good/pull request review",
+			eventType:     "pull_request_review",
+			triggerTarget: "pull_request",
+			payloadEventStruct: github.PullRequestReviewEvent{
+				Action:      github.String("submitted"),
+				PullRequest: samplePRevent.PullRequest,
+				Repo:        sampleRepo,
+				Review: &github.PullRequestReview{
+					User:  &github.User{Login: github.String("reviewer")},
+					State: github.String("APPROVED"),
+				},
+			},
+			shaRet:          "sampleHeadsha",
+			wantReviewer:    "reviewer",
+			wantReviewState: "approved",
+		},
+		{
+			name:          "good/pull request review comment",
+			eventType:     "pull_request_review_comment",
+			triggerTarget: "pull_request",
+			payloadEventStruct: github.PullRequestReviewCommentEvent{
+				Action:      github.String("created"),
+				PullRequest: samplePRevent.PullRequest,
+				Repo:        sampleRepo,
+				Comment: &github.PullRequestComment{
+					User: &github.User{Login: github.String("commenter")},
+				},
+			},
+			shaRet:       "sampleHeadsha",
+			wantReviewer: "commenter",
+		},
+		{
+			name:          "good/merge group",
+			eventType:     "merge_group",
+			triggerTarget: "pull_request",
+			payloadEventStruct: github.MergeGroupEvent{
+				Action: github.String("checks_requested"),
+				Repo:   sampleRepo,
+				MergeGroup: &github.MergeGroup{
+					HeadSHA: github.String("mergeGroupSHA"),
+					HeadRef: github.String("refs/heads/gh-readonly-queue/main/pr-42-baseSHA"),
+					BaseSHA: github.String("baseSHA"),
+					BaseRef: github.String("refs/heads/main"),
+				},
+			},
+			shaRet:         "mergeGroupSHA",
+			wantBaseBranch: "main",
+			wantHeadBranch: "gh-readonly-queue/main/pr-42-baseSHA",
+		},
 		{
 			name:          "good/push",
 			eventType:     "push",
@@ -244,6 +581,89 @@ func TestParsePayLoad(t *testing.T) {
 			},
 			shaRet: "SHAPush",
 		},
+		{
+			name:          "good/push deleting a branch",
+			eventType:     "push",
+			triggerTarget: "push",
+			payloadEventStruct: github.PushEvent{
+				Repo: &github.PushEventRepository{
+					Owner: &github.User{Login: github.String("owner")},
+					Name:  github.String("pushRepo"),
+				},
+				Ref:     github.String("refs/heads/feature"),
+				Before:  github.String("SHABefore"),
+				Deleted: github.Bool(true),
+			},
+			shaRet:            "SHABefore",
+			wantBranchDeleted: true,
+		},
+		{
+			name:          "good/command commented on an issue",
+			eventType:     "issue_comment",
+			triggerTarget: "issue_comment",
+			payloadEventStruct: github.IssueCommentEvent{
+				Action: github.String("created"),
+				Repo:   sampleRepo,
+				Issue: &github.Issue{
+					Number: github.Int(42),
+					Title:  github.String("Deploy to staging"),
+					State:  github.String("open"),
+				},
+				Comment: &github.IssueComment{
+					Body: github.String("/deploy staging"),
+					User: &github.User{Login: github.String("operator")},
+				},
+			},
+			wantBaseBranch:       "defaultbranch",
+			wantHeadBranch:       "defaultbranch",
+			wantIssueNumber:      42,
+			wantCommentCommand:   "deploy",
+			wantIssueCommentArgs: "staging",
+		},
+		{
+			name:          "good/published release",
+			eventType:     "release",
+			triggerTarget: "push",
+			payloadEventStruct: github.ReleaseEvent{
+				Action: github.String("published"),
+				Repo:   sampleRepo,
+				Release: &github.RepositoryRelease{
+					TagName:    github.String("v1.0.0-rc1"),
+					Name:       github.String("First release candidate"),
+					Prerelease: github.Bool(true),
+				},
+			},
+			wantBaseBranch:        "refs/tags/v1.0.0-rc1",
+			wantHeadBranch:        "refs/tags/v1.0.0-rc1",
+			wantReleaseTag:        "v1.0.0-rc1",
+			wantReleaseName:       "First release candidate",
+			wantReleasePrerelease: true,
+		},
+		{
+			name:          "bad/release not published",
+			wantErrString: "only published releases are supported",
+			eventType:     "release",
+			triggerTarget: "push",
+			payloadEventStruct: github.ReleaseEvent{
+				Action: github.String("prereleased"),
+				Repo:   sampleRepo,
+			},
+		},
+		{
+			name:          "good/push deleting a tag",
+			eventType:     "push",
+			triggerTarget: "push",
+			payloadEventStruct: github.PushEvent{
+				Repo: &github.PushEventRepository{
+					Owner: &github.User{Login: github.String("owner")},
+					Name:  github.String("pushRepo"),
+				},
+				Ref:     github.String("refs/tags/v1"),
+				Before:  github.String("SHABefore"),
+				Deleted: github.Bool(true),
+			},
+			shaRet: "SHABefore",
+		},
 		{
 			name:          "good/issue comment for retest",
 			eventType:     "issue_comment",
@@ -264,6 +684,27 @@ func TestParsePayLoad(t *testing.T) {
 			shaRet:            "samplePRsha",
 			targetPipelinerun: "dummy",
 		},
+		{
+			name:          "good/issue comment for test with params",
+			eventType:     "issue_comment",
+			triggerTarget: "pull_request",
+			githubClient:  fakeclient,
+			payloadEventStruct: github.IssueCommentEvent{
+				Issue: &github.Issue{
+					PullRequestLinks: &github.PullRequestLinks{
+						HTMLURL: github.String("/555"),
+					},
+				},
+				Repo: sampleRepo,
+				Comment: &github.IssueComment{
+					Body: github.String("/test dummy cluster=gke region=eu"),
+				},
+			},
+			muxReplies:        map[string]interface{}{"/repos/owner/reponame/pulls/555": samplePR},
+			shaRet:            "samplePRsha",
+			targetPipelinerun: "dummy",
+			wantTestParams:    map[string]string{"cluster": "gke", "region": "eu"},
+		},
 		{
 			name:          "good/issue comment for cancel all",
 			eventType:     "issue_comment",
@@ -303,12 +744,112 @@ func TestParsePayLoad(t *testing.T) {
 			shaRet:                  "samplePRsha",
 			targetCancelPipelinerun: "dummy",
 		},
+		{
+			name:          "good/cancel requested from the check run of a pull request",
+			eventType:     "check_run",
+			triggerTarget: "pull_request",
+			githubClient:  fakeclient,
+			payloadEventStruct: github.CheckRunEvent{
+				Action:          github.String("requested_action"),
+				Repo:            sampleRepo,
+				RequestedAction: &github.RequestedAction{Identifier: "cancel"},
+				CheckRun: &github.CheckRun{
+					Name:    github.String("Pipelines as Code CI / pr-foo"),
+					HeadSHA: github.String("checkRunSHA"),
+					CheckSuite: &github.CheckSuite{
+						PullRequests: []*github.PullRequest{{Number: github.Int(7778)}},
+					},
+				},
+			},
+			muxReplies:              map[string]interface{}{"/repos/owner/reponame/pulls/7778": samplePR},
+			shaRet:                  "checkRunSHA",
+			targetCancelPipelinerun: "pr-foo",
+		},
+		{
+			name:          "good/rerun requested from the check run of a push",
+			eventType:     "check_run",
+			triggerTarget: "push",
+			githubClient:  fakeclient,
+			payloadEventStruct: github.CheckRunEvent{
+				Action:          github.String("requested_action"),
+				Repo:            sampleRepo,
+				RequestedAction: &github.RequestedAction{Identifier: "rerun"},
+				CheckRun: &github.CheckRun{
+					Name:    github.String("push-foo"),
+					HeadSHA: github.String("checkRunSHA"),
+					CheckSuite: &github.CheckSuite{
+						HeadBranch: github.String("release-1"),
+					},
+				},
+			},
+			muxReplies: map[string]interface{}{"/repos/owner/reponame/branches/release-1": github.Branch{
+				Commit: &github.RepositoryCommit{SHA: github.String("checkRunSHA")},
+			}},
+			shaRet:            "checkRunSHA",
+			targetPipelinerun: "push-foo",
+			wantBaseBranch:    "release-1",
+			wantHeadBranch:    "release-1",
+			wantEventType:     "push",
+		},
+		{
+			name:          "good/rerun requested from the check run of a pull request from a fork",
+			eventType:     "check_run",
+			triggerTarget: "pull_request",
+			githubClient:  fakeclient,
+			payloadEventStruct: github.CheckRunEvent{
+				Action:          github.String("requested_action"),
+				Repo:            sampleRepo,
+				Sender:          &github.User{Login: github.String("forker")},
+				RequestedAction: &github.RequestedAction{Identifier: "rerun"},
+				CheckRun: &github.CheckRun{
+					Name:    github.String("Pipelines as Code CI / pr-foo"),
+					HeadSHA: github.String("forkActionSHA"),
+					CheckSuite: &github.CheckSuite{
+						HeadBranch: github.String("main"),
+					},
+				},
+			},
+			muxReplies: map[string]interface{}{
+				"/repos/owner/reponame/commits/forkActionSHA/pulls": []github.PullRequest{{
+					Number: github.Int(7779),
+					Head:   &github.PullRequestBranch{SHA: github.String("forkActionSHA")},
+				}},
+				"/repos/owner/reponame/pulls/7779": github.PullRequest{
+					Number: github.Int(7779),
+					Head: &github.PullRequestBranch{
+						SHA:  github.String("forkActionSHA"),
+						Ref:  github.String("main"),
+						Repo: &github.Repository{ID: github.Int64(2), Name: github.String("reponame")},
+					},
+					Base: &github.PullRequestBranch{Ref: github.String("main"), Repo: sampleRepo},
+				},
+			},
+			shaRet:            "forkActionSHA",
+			targetPipelinerun: "pr-foo",
+			wantBaseBranch:    "main",
+			wantHeadBranch:    "main",
+			wantEventType:     "pull_request",
+		},
+		{
+			name:          "bad/unknown action requested from a check run",
+			wantErrString: "check run action delete is not supported",
+			eventType:     "check_run",
+			triggerTarget: "pull_request",
+			githubClient:  fakeclient,
+			payloadEventStruct: github.CheckRunEvent{
+				Action:          github.String("requested_action"),
+				Repo:            sampleRepo,
+				RequestedAction: &github.RequestedAction{Identifier: "delete"},
+				CheckRun:        &github.CheckRun{},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx, _ := rtesting.SetupFakeContext(t)
 
 			for key, value := range tt.muxReplies {
+				value := value
 				mux.HandleFunc(key, func(rw http.ResponseWriter, r *http.Request) {
 					bjeez, _ := json.Marshal(value)
 					fmt.Fprint(rw, string(bjeez))
@@ -316,8 +857,9 @@ func TestParsePayLoad(t *testing.T) {
 			}
 			logger, _ := logger.GetLogger()
 			gprovider := Provider{
-				Client: tt.githubClient,
-				Logger: logger,
+				Client:        tt.githubClient,
+				Logger:        logger,
+				ApplicationID: tt.applicationID,
 			}
 			request := &http.Request{Header: map[string][]string{}}
 			request.Header.Set("X-GitHub-Event", tt.eventType)
@@ -348,6 +890,38 @@ func TestParsePayLoad(t *testing.T) {
 			if tt.targetCancelPipelinerun != "" {
 				assert.Equal(t, tt.targetCancelPipelinerun, ret.TargetCancelPipelineRun)
 			}
+			assert.DeepEqual(t, tt.wantTestParams, ret.TestParams)
+			assert.Equal(t, tt.wantCancelInProgress, ret.CancelInProgress)
+			assert.Equal(t, tt.wantBranchDeleted, ret.BranchDeleted)
+			assert.Equal(t, tt.wantReviewer, ret.Reviewer)
+			assert.Equal(t, tt.wantReviewState, ret.ReviewState)
+			assert.Equal(t, tt.wantDraft, ret.PullRequestDraft)
+			if tt.wantLabels != nil {
+				assert.DeepEqual(t, tt.wantLabels, ret.PullRequestLabels)
+			}
+			if tt.wantAssignees != nil {
+				assert.DeepEqual(t, tt.wantAssignees, ret.PullRequestAssignees)
+			}
+			assert.DeepEqual(t, tt.wantLabelsAdded, ret.LabelsAdded)
+			assert.DeepEqual(t, tt.wantLabelsRemoved, ret.LabelsRemoved)
+			assert.Equal(t, tt.wantCheckRunName, ret.CheckRunName)
+			assert.Equal(t, tt.wantWorkflowRunName, ret.WorkflowRunName)
+			assert.Equal(t, tt.wantReleaseTag, ret.ReleaseTag)
+			assert.Equal(t, tt.wantReleaseName, ret.ReleaseName)
+			assert.Equal(t, tt.wantReleasePrerelease, ret.ReleasePrerelease)
+			assert.Equal(t, tt.wantIssueNumber, ret.IssueNumber)
+			if tt.wantCommentCommand != "" {
+				assert.Equal(t, tt.wantCommentCommand, ret.CommentCommand)
+				assert.Equal(t, tt.wantIssueCommentArgs, ret.IssueCommentArgs)
+			}
+			if tt.wantBaseBranch != "" {
+				assert.Equal(t, tt.wantBaseBranch, ret.BaseBranch)
+				assert.Equal(t, tt.wantHeadBranch, ret.HeadBranch)
+				assert.Equal(t, tt.triggerTarget, ret.TriggerTarget)
+			}
+			if tt.wantEventType != "" {
+				assert.Equal(t, tt.wantEventType, ret.EventType)
+			}
 		})
 	}
 }
@@ -406,6 +980,14 @@ func TestAppTokenGeneration(t *testing.T) {
 		},
 	})
 
+	ctxMultipleApps, _ := rtesting.SetupFakeContext(t)
+	multipleApps, _ := testclient.SeedTestData(t, ctxMultipleApps, testclient.Data{
+		Secret: []*corev1.Secret{
+			appSecret(testNamespace, "pipelines-as-code-secret", "12345", ""),
+			appSecret(testNamespace, "ghes-app", "678", ""),
+		},
+	})
+
 	fakeGithubAuthURL := "https://fake.gitub.auth/api/v3/"
 	tests := []struct {
 		ctx                 context.Context
@@ -417,6 +999,8 @@ func TestAppTokenGeneration(t *testing.T) {
 		resultBaseURL       string
 		checkInstallIDs     []int64
 		extraRepoInstallIds map[string]string
+		appID               int64
+		wantAppID           int64
 	}{
 		{
 			name: "secret not found",
@@ -459,6 +1043,27 @@ func TestAppTokenGeneration(t *testing.T) {
 			checkInstallIDs:     []int64{123},
 			extraRepoInstallIds: map[string]string{"another/one": "789", "andanother/two": "10112"},
 		},
+		{
+			ctx:  ctxMultipleApps,
+			name: "app selected from the webhook",
+			envs: map[string]string{
+				"SYSTEM_NAMESPACE": testNamespace,
+				AppSecretsEnv:      "ghes-app",
+			},
+			seedData:  multipleApps,
+			appID:     678,
+			wantAppID: 678,
+		},
+		{
+			ctx:  ctxMultipleApps,
+			name: "default app without the app id",
+			envs: map[string]string{
+				"SYSTEM_NAMESPACE": testNamespace,
+				AppSecretsEnv:      "ghes-app",
+			},
+			seedData:  multipleApps,
+			wantAppID: 12345,
+		},
 		{
 			ctx:  ctxInvalidAppID,
 			name: "invalid app id in secret",
@@ -513,6 +1118,10 @@ func TestAppTokenGeneration(t *testing.T) {
 			}
 			request := &http.Request{Header: map[string][]string{}}
 			request.Header.Set("X-GitHub-Event", "pull_request")
+			if tt.appID != 0 {
+				request.Header.Set("X-GitHub-Hook-Installation-Target-Type", "integration")
+				request.Header.Set("X-GitHub-Hook-Installation-Target-ID", strconv.FormatInt(tt.appID, 10))
+			}
 			// a bit of a pain but works
 			if tt.resultBaseURL != "" {
 				request.Header.Set("X-GitHub-Enterprise-Host", fakeGithubAuthURL)
@@ -553,12 +1162,16 @@ func TestAppTokenGeneration(t *testing.T) {
 				run.Info.Pac.SecretGhAppTokenScopedExtraRepos = extras
 			}
 
-			_, err := gprovider.ParsePayload(tt.ctx, run, request, string(jeez))
+			ret, err := gprovider.ParsePayload(tt.ctx, run, request, string(jeez))
 			if tt.wantErr {
 				assert.Assert(t, err != nil)
 				return
 			}
 			assert.NilError(t, err)
+			if tt.wantAppID != 0 {
+				assert.Equal(t, ret.GitHubAppID, tt.wantAppID)
+				assert.Equal(t, *gprovider.ApplicationID, tt.wantAppID)
+			}
 			if tt.nilClient {
 				assert.Assert(t, gprovider.Client == nil)
 				return
@@ -589,3 +1202,17 @@ func TestAppTokenGeneration(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPrivateRepository(t *testing.T) {
+	assert.Assert(t, isPrivateRepository(`{"repository": {"private": true}}`))
+	assert.Assert(t, !isPrivateRepository(`{"repository": {"private": false}}`))
+	assert.Assert(t, !isPrivateRepository(`{}`))
+}
+
+func TestIsForkPullRequest(t *testing.T) {
+	base := &github.PullRequestBranch{Repo: &github.Repository{ID: github.Int64(1)}}
+	assert.Assert(t, !isForkPullRequest(&github.PullRequest{Base: base, Head: &github.PullRequestBranch{Repo: &github.Repository{ID: github.Int64(1)}}}))
+	assert.Assert(t, isForkPullRequest(&github.PullRequest{Base: base, Head: &github.PullRequestBranch{Repo: &github.Repository{ID: github.Int64(2)}}}))
+	// the fork has been deleted
+	assert.Assert(t, isForkPullRequest(&github.PullRequest{Base: base, Head: &github.PullRequestBranch{}}))
+}
@@ -21,8 +21,8 @@ import (
 const defaultNsTemplate = "%v-pipelines"
 
 func ConfigureRepository(ctx context.Context, run *params.Run, req *http.Request, payload string, logger *zap.SugaredLogger) (bool, bool, error) {
-	// gitea set x-github-event too, so skip it for the gitea driver
-	if h := req.Header.Get("X-Gitea-Event-Type"); h != "" {
+	// gitea and forgejo set x-github-event too, so skip it for the gitea driver
+	if req.Header.Get("X-Gitea-Event-Type") != "" || req.Header.Get("X-Forgejo-Event-Type") != "" {
 		return false, false, nil
 	}
 	event := req.Header.Get("X-Github-Event")
@@ -2,6 +2,8 @@ package github
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -14,9 +16,11 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	"go.uber.org/zap"
 )
 
 const taskStatusTemplate = `
@@ -34,6 +38,42 @@ const taskStatusTemplate = `
 {{- end }}
 </table>`
 
+// the identifiers of the actions of the check runs, the buttons to cancel the
+// running PipelineRun or to run it again from the GitHub Checks UI
+const (
+	checkRunActionCancel = "cancel"
+	checkRunActionRerun  = "rerun"
+)
+
+// checkRunActions return the actions of the check run of a PipelineRun, it can
+// be cancelled while it runs and run again once it's done.
+func checkRunActions(statusOpts provider.StatusOpts) []*github.CheckRunAction {
+	if statusOpts.PipelineRun == nil || statusOpts.OriginalPipelineRunName == "" {
+		return nil
+	}
+	if statusOpts.Status == "completed" {
+		return []*github.CheckRunAction{{
+			Label:       "Re-run",
+			Description: "Run the PipelineRun again",
+			Identifier:  checkRunActionRerun,
+		}}
+	}
+	return []*github.CheckRunAction{{
+		Label:       "Cancel",
+		Description: "Cancel the running PipelineRun",
+		Identifier:  checkRunActionCancel,
+	}}
+}
+
+// checkRunPipelineRunName return the name of the PipelineRun of the .tekton
+// directory a check run has been created for, from its name.
+func checkRunPipelineRunName(checkName string) string {
+	if i := strings.LastIndex(checkName, " / "); i >= 0 {
+		return checkName[i+len(" / "):]
+	}
+	return checkName
+}
+
 func getCheckName(status provider.StatusOpts, pacopts *info.PacOpts) string {
 	if pacopts.ApplicationName != "" {
 		if status.OriginalPipelineRunName == "" {
@@ -107,7 +147,7 @@ func (v *Provider) createCheckRunStatus(ctx context.Context, runevent *info.Even
 
 	checkRun, _, err := v.Client.Checks.CreateCheckRun(ctx, runevent.Organization, runevent.Repository, checkrunoption)
 	if err != nil {
-		return nil, err
+		return nil, v.explainEnterpriseError(ctx, err, "create the check run")
 	}
 	return checkRun.ID, nil
 }
@@ -177,6 +217,8 @@ func (v *Provider) getFailuresMessageAsAnnotations(ctx context.Context, pr *tekt
 	return annotations
 }
 
+const maxCheckRunAnnotations = 50
+
 // getOrUpdateCheckRunStatus create a status via the checkRun API, which is only
 // available with Github apps tokens.
 func (v *Provider) getOrUpdateCheckRunStatus(ctx context.Context, tekton versioned.Interface, runevent *info.Event, pacopts *info.PacOpts, statusOpts provider.StatusOpts) error {
@@ -219,15 +261,45 @@ func (v *Provider) getOrUpdateCheckRunStatus(ctx context.Context, tekton version
 	if statusOpts.PipelineRun != nil {
 		if pacopts.ErrorDetection {
 			checkRunOutput.Annotations = v.getFailuresMessageAsAnnotations(ctx, statusOpts.PipelineRun, pacopts)
+			if len(checkRunOutput.Annotations) > 0 && (pacopts.ErrorDetectionBlame == settings.ErrorDetectionBlameMention ||
+				pacopts.ErrorDetectionBlame == settings.ErrorDetectionBlameReview) {
+				if authors := v.blameAnnotations(ctx, runevent, pacopts, checkRunOutput.Annotations); len(authors) > 0 {
+					// mentioning would disclose the authors
+					if !pacopts.HidesUsers(runevent) {
+						text += blameText(authors)
+					}
+					// only once the PipelineRun is done, not at every update
+					if pacopts.ErrorDetectionBlame == settings.ErrorDetectionBlameReview && statusOpts.Status == "completed" {
+						if err := v.requestBlamedReviews(ctx, runevent, authors); err != nil {
+							v.Logger.Warnf("cannot request the review of %s on pull request %d: %v", strings.Join(authors, ", "),
+								runevent.PullRequestNumber, err)
+						}
+					}
+				}
+			}
+		}
+	}
+	for _, annotation := range statusOpts.Annotations {
+		// github only accept 50 annotations per request
+		if len(checkRunOutput.Annotations) >= maxCheckRunAnnotations {
+			break
 		}
+		checkRunOutput.Annotations = append(checkRunOutput.Annotations, &github.CheckRunAnnotation{
+			Path:            github.String(annotation.Path),
+			StartLine:       github.Int(annotation.Line),
+			EndLine:         github.Int(annotation.Line),
+			AnnotationLevel: github.String("failure"),
+			Message:         github.String(annotation.Message),
+		})
 	}
 
 	checkRunOutput.Text = github.String(text)
 
 	opts := github.UpdateCheckRunOptions{
-		Name:   getCheckName(statusOpts, pacopts),
-		Status: github.String(statusOpts.Status),
-		Output: checkRunOutput,
+		Name:    getCheckName(statusOpts, pacopts),
+		Status:  github.String(statusOpts.Status),
+		Output:  checkRunOutput,
+		Actions: checkRunActions(statusOpts),
 	}
 	if statusOpts.PipelineRunName != "" {
 		opts.ExternalID = github.String(statusOpts.PipelineRunName)
@@ -244,9 +316,57 @@ func (v *Provider) getOrUpdateCheckRunStatus(ctx context.Context, tekton version
 	if isPipelineRunCancelledOrStopped(statusOpts.PipelineRun) {
 		opts.Conclusion = github.String("cancelled")
 	}
+	if _, ok := maxDurationExceeded(statusOpts.PipelineRun); ok && statusOpts.Status == "completed" {
+		opts.Conclusion = github.String("timed_out")
+	}
+	if opts.GetConclusion() == "skipped" && !v.supports(ctx, featureSkippedConclusion) {
+		opts.Conclusion = github.String("neutral")
+	}
 
-	_, _, err = v.Client.Checks.UpdateCheckRun(ctx, runevent.Organization, runevent.Repository, *checkRunID, opts)
-	return err
+	// skip the update if we already posted the same content on the check run,
+	// this happens a lot when the reconciler resync
+	hash, err := checkRunOptsHash(opts)
+	if err != nil {
+		return err
+	}
+	// the provider may not have a logger when it only reports the statuses
+	logger := v.Logger
+	if logger == nil {
+		logger = zap.NewNop().Sugar()
+	}
+	if statusOpts.PipelineRun != nil && statusOpts.PipelineRun.GetAnnotations()[keys.CheckRunHash] == hash {
+		logger.Debugf("check run %d has not changed, skipping update", *checkRunID)
+		return nil
+	}
+
+	if _, _, err = v.Client.Checks.UpdateCheckRun(ctx, runevent.Organization, runevent.Repository, *checkRunID, opts); err != nil {
+		return v.explainEnterpriseError(ctx, err, "update the check run")
+	}
+
+	if statusOpts.PipelineRun != nil {
+		if _, err := action.PatchPipelineRun(ctx, logger, "checkRunHash", tekton, statusOpts.PipelineRun, map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]string{
+					keys.CheckRunHash: hash,
+				},
+			},
+		}); err != nil {
+			// not fatal, we will just update the check run again next time
+			logger.Warnf("cannot store check run hash on pipelinerun: %v", err)
+		}
+	}
+	return nil
+}
+
+// checkRunOptsHash return a hash of the content we post on the check run,
+// without the completion time which changes on every call.
+func checkRunOptsHash(opts github.UpdateCheckRunOptions) (string, error) {
+	opts.CompletedAt = nil
+	b, err := json.Marshal(opts)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b)), nil
 }
 
 func isPipelineRunCancelledOrStopped(run *tektonv1beta1.PipelineRun) bool {
@@ -259,6 +379,25 @@ func isPipelineRunCancelledOrStopped(run *tektonv1beta1.PipelineRun) bool {
 	return false
 }
 
+// supersededBy return the commit which superseded the cancelled PipelineRun.
+func supersededBy(run *tektonv1beta1.PipelineRun) (string, bool) {
+	if !isPipelineRunCancelledOrStopped(run) {
+		return "", false
+	}
+	sha, ok := run.GetAnnotations()[keys.SupersededBy]
+	return sha, ok && sha != ""
+}
+
+// maxDurationExceeded return the max-pipelinerun-duration the PipelineRun has
+// been cancelled after by the watcher.
+func maxDurationExceeded(run *tektonv1beta1.PipelineRun) (string, bool) {
+	if run == nil {
+		return "", false
+	}
+	maxDuration, ok := run.GetAnnotations()[keys.MaxDurationExceeded]
+	return maxDuration, ok
+}
+
 func metadataPatch(checkRunID *int64, logURL string) map[string]interface{} {
 	return map[string]interface{}{
 		"metadata": map[string]interface{}{
@@ -293,11 +432,13 @@ func (v *Provider) createStatusCommit(ctx context.Context, runevent *info.Event,
 		CreatedAt:   &now,
 	}
 
-	if _, _, err := v.Client.Repositories.CreateStatus(ctx,
-		runevent.Organization, runevent.Repository, runevent.SHA, ghstatus); err != nil {
-		return err
+	if !pacopts.DisableCommitStatuses {
+		if _, _, err := v.Client.Repositories.CreateStatus(ctx,
+			runevent.Organization, runevent.Repository, runevent.SHA, ghstatus); err != nil {
+			return err
+		}
 	}
-	if status.Status == "completed" && status.Text != "" && runevent.EventType == "pull_request" {
+	if !pacopts.DisablePullRequestComments && status.Status == "completed" && status.Text != "" && runevent.EventType == "pull_request" {
 		_, _, err = v.Client.Issues.CreateComment(ctx, runevent.Organization, runevent.Repository,
 			runevent.PullRequestNumber,
 			&github.IssueComment{
@@ -312,6 +453,35 @@ func (v *Provider) createStatusCommit(ctx context.Context, runevent *info.Event,
 	return nil
 }
 
+// createIssueCommentStatus comments the status of a PipelineRun run from the
+// comment of an issue on the issue, the updates of a running PipelineRun are
+// only commented when its status changes, ie: from queued to in_progress.
+func (v *Provider) createIssueCommentStatus(ctx context.Context, tekton versioned.Interface, runevent *info.Event, statusOpts provider.StatusOpts) error {
+	if statusOpts.PipelineRun != nil && statusOpts.PipelineRun.GetAnnotations()[keys.IssueCommentStatus] == statusOpts.Status {
+		return nil
+	}
+	if _, _, err := v.Client.Issues.CreateComment(ctx, runevent.Organization, runevent.Repository, runevent.IssueNumber,
+		&github.IssueComment{
+			Body: github.String(fmt.Sprintf("%s<br>%s", statusOpts.Summary, statusOpts.Text)),
+		}); err != nil {
+		return err
+	}
+	if statusOpts.PipelineRun != nil {
+		if _, err := action.PatchPipelineRun(ctx, v.Logger, "issueCommentStatus", tekton, statusOpts.PipelineRun, map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]string{
+					keys.IssueCommentStatus: statusOpts.Status,
+					keys.LogURL:             statusOpts.DetailsURL,
+				},
+			},
+		}); err != nil {
+			// not fatal, the same status may just be commented again
+			v.Logger.Warnf("cannot store the status commented on issue %d on pipelinerun: %v", runevent.IssueNumber, err)
+		}
+	}
+	return nil
+}
+
 func (v *Provider) CreateStatus(ctx context.Context, tekton versioned.Interface, runevent *info.Event, pacopts *info.PacOpts, statusOpts provider.StatusOpts) error {
 	if v.Client == nil {
 		return fmt.Errorf("cannot set status on github no token or url set")
@@ -337,12 +507,40 @@ func (v *Provider) CreateStatus(ctx context.Context, tekton versioned.Interface,
 		statusOpts.Summary = "is running."
 	}
 
+	if statusOpts.Status == "completed" && statusOpts.PipelineRun != nil && len(statusOpts.PipelineRun.Status.Conditions) > 0 &&
+		statusOpts.PipelineRun.Status.Conditions[0].Reason == tektonv1beta1.PipelineRunReasonTimedOut.String() {
+		statusOpts.Title = "Timed out"
+		statusOpts.Summary = "has <b>timed out</b>."
+	}
+
+	// the PipelineRun has been cancelled by a newer commit of the pull request
+	if sha, ok := supersededBy(statusOpts.PipelineRun); ok && statusOpts.Status == "completed" {
+		statusOpts.Title = "Superseded"
+		statusOpts.Summary = fmt.Sprintf("has been superseded by commit %s.", sha)
+	}
+
+	// the PipelineRun has been cancelled by the watcher after running for too long
+	if maxDuration, ok := maxDurationExceeded(statusOpts.PipelineRun); ok && statusOpts.Status == "completed" {
+		statusOpts.Title = "Timed out"
+		statusOpts.Summary = fmt.Sprintf("has <b>timed out</b> after running for longer than %s.", maxDuration)
+	}
+
 	onPr := ""
 	if statusOpts.OriginalPipelineRunName != "" {
 		onPr = "/" + statusOpts.OriginalPipelineRunName
 	}
 	statusOpts.Summary = fmt.Sprintf("%s%s %s", pacopts.ApplicationName, onPr, statusOpts.Summary)
 
+	// Report to the deployments API when the PipelineRun target an environment
+	if err := v.createOrUpdateDeploymentStatus(ctx, tekton, runevent, pacopts, statusOpts); err != nil {
+		v.Logger.Warnf("cannot set deployment status on environment: %v", err)
+	}
+
+	// the PipelineRuns run from the comment of an issue report on the issue
+	if runevent.IssueNumber != 0 {
+		return v.createIssueCommentStatus(ctx, tekton, runevent, statusOpts)
+	}
+
 	// If we have an installationID which mean we have a github apps and we can use the checkRun API
 	if runevent.InstallationID > 0 {
 		return v.getOrUpdateCheckRunStatus(ctx, tekton, runevent, pacopts, statusOpts)
@@ -351,3 +549,9 @@ func (v *Provider) CreateStatus(ctx context.Context, tekton versioned.Interface,
 	// Otherwise use the update status commit API
 	return v.createStatusCommit(ctx, runevent, pacopts, statusOpts)
 }
+
+// CreateStatuses updates the check run, or the commit status, of each
+// PipelineRun of a batch with its last status only
+func (v *Provider) CreateStatuses(ctx context.Context, tekton versioned.Interface, event *info.Event, opts *info.PacOpts, statuses []provider.StatusOpts) error {
+	return provider.CreateStatuses(ctx, v, tekton, event, opts, statuses)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"gotest.tools/v3/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
@@ -270,6 +271,91 @@ func TestGithubProviderCreateStatus(t *testing.T) {
 			want:    &github.CheckRun{ID: &resultid},
 			wantErr: false,
 		},
+		{
+			name: "superseded",
+			args: args{
+				runevent:    runEvent,
+				status:      "completed",
+				conclusion:  "cancelled",
+				text:        "Cancelled",
+				detailsURL:  "https://cireport.com",
+				titleSubstr: "Superseded",
+				githubApps:  true,
+			},
+			pr: &v1beta1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: prname,
+					Labels: map[string]string{
+						keys.CheckRunID: strconv.Itoa(int(checkrunid)),
+					},
+					Annotations: map[string]string{
+						keys.SupersededBy: "newsha",
+					},
+				},
+				Spec: v1beta1.PipelineRunSpec{
+					Status: v1beta1.PipelineRunSpecStatusCancelledRunFinally,
+				},
+			},
+			want:    &github.CheckRun{ID: &resultid},
+			wantErr: false,
+		},
+		{
+			name: "timed out",
+			args: args{
+				runevent:    runEvent,
+				status:      "completed",
+				conclusion:  "failure",
+				text:        "Timed out",
+				detailsURL:  "https://cireport.com",
+				titleSubstr: "Timed out",
+				githubApps:  true,
+			},
+			pr: &v1beta1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: prname,
+					Labels: map[string]string{
+						keys.CheckRunID: strconv.Itoa(int(checkrunid)),
+					},
+				},
+				Status: v1beta1.PipelineRunStatus{
+					Status: knativeduckv1.Status{
+						Conditions: knativeduckv1.Conditions{
+							{Reason: v1beta1.PipelineRunReasonTimedOut.String()},
+						},
+					},
+				},
+			},
+			want:    &github.CheckRun{ID: &resultid},
+			wantErr: false,
+		},
+		{
+			name: "max duration exceeded",
+			args: args{
+				runevent:    runEvent,
+				status:      "completed",
+				conclusion:  "timed_out",
+				text:        "Cancelled",
+				detailsURL:  "https://cireport.com",
+				titleSubstr: "Timed out",
+				githubApps:  true,
+			},
+			pr: &v1beta1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: prname,
+					Labels: map[string]string{
+						keys.CheckRunID: strconv.Itoa(int(checkrunid)),
+					},
+					Annotations: map[string]string{
+						keys.MaxDurationExceeded: "2h0m0s",
+					},
+				},
+				Spec: v1beta1.PipelineRunSpec{
+					Status: v1beta1.PipelineRunSpecStatusCancelled,
+				},
+			},
+			want:    &github.CheckRun{ID: &resultid},
+			wantErr: false,
+		},
 		{
 			name:    "no token set",
 			wantErr: true,
@@ -386,6 +472,8 @@ func TestGithubProvidercreateStatusCommit(t *testing.T) {
 		wantErr            bool
 		status             provider.StatusOpts
 		expectedConclusion string
+		disableComments    bool
+		disableStatuses    bool
 	}{
 		{
 			name:  "completed",
@@ -422,32 +510,62 @@ func TestGithubProvidercreateStatusCommit(t *testing.T) {
 			},
 			expectedConclusion: "success",
 		},
+		{
+			name:  "completed with comments disabled",
+			event: anevent,
+			status: provider.StatusOpts{
+				Status:     "completed",
+				Summary:    "I just wanna say",
+				Text:       "Finito amigo",
+				Conclusion: "completed",
+			},
+			expectedConclusion: "completed",
+			disableComments:    true,
+		},
+		{
+			name:  "completed with statuses disabled",
+			event: anevent,
+			status: provider.StatusOpts{
+				Status:     "completed",
+				Summary:    "I just wanna say",
+				Text:       "Finito amigo",
+				Conclusion: "completed",
+			},
+			disableStatuses: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
 			defer teardown()
+			statusSet, commented := false, false
 			mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/statuses/%s",
 				tt.event.Organization, tt.event.Repository, tt.event.SHA), func(rw http.ResponseWriter, r *http.Request) {
+				statusSet = true
 				body, _ := io.ReadAll(r.Body)
 				assert.Check(t, strings.Contains(string(body), fmt.Sprintf(`"state":"%s"`, tt.expectedConclusion)))
 			})
-			if tt.status.Status == "completed" {
-				mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/issues/%d/comments",
-					tt.event.Organization, tt.event.Repository, issuenumber), func(rw http.ResponseWriter, r *http.Request) {
-					body, _ := io.ReadAll(r.Body)
-					assert.Equal(t, fmt.Sprintf(`{"body":"%s<br>%s"}`, tt.status.Summary, tt.status.Text)+"\n", string(body))
-				})
-			}
+			mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/issues/%d/comments",
+				tt.event.Organization, tt.event.Repository, issuenumber), func(rw http.ResponseWriter, r *http.Request) {
+				commented = true
+				body, _ := io.ReadAll(r.Body)
+				assert.Equal(t, fmt.Sprintf(`{"body":"%s<br>%s"}`, tt.status.Summary, tt.status.Text)+"\n", string(body))
+			})
 
 			ctx, _ := rtesting.SetupFakeContext(t)
 			provider := &Provider{
 				Client: fakeclient,
 			}
 
-			if err := provider.createStatusCommit(ctx, tt.event, &info.PacOpts{Settings: &settings.Settings{}}, tt.status); (err != nil) != tt.wantErr {
+			pacopts := &info.PacOpts{Settings: &settings.Settings{
+				DisablePullRequestComments: tt.disableComments,
+				DisableCommitStatuses:      tt.disableStatuses,
+			}}
+			if err := provider.createStatusCommit(ctx, tt.event, pacopts, tt.status); (err != nil) != tt.wantErr {
 				t.Errorf("GetCommitInfo() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			assert.Equal(t, statusSet, !tt.disableStatuses)
+			assert.Equal(t, commented, tt.status.Status == "completed" && !tt.disableComments)
 		})
 	}
 }
@@ -502,6 +620,45 @@ func TestGetCheckName(t *testing.T) {
 	}
 }
 
+func TestCheckRunActions(t *testing.T) {
+	pr := &v1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr-abcde"}}
+	tests := []struct {
+		name   string
+		status provider.StatusOpts
+		want   []string
+	}{
+		{
+			name:   "running",
+			status: provider.StatusOpts{Status: "in_progress", PipelineRun: pr, OriginalPipelineRunName: "pr"},
+			want:   []string{checkRunActionCancel},
+		},
+		{
+			name:   "done",
+			status: provider.StatusOpts{Status: "completed", PipelineRun: pr, OriginalPipelineRunName: "pr"},
+			want:   []string{checkRunActionRerun},
+		},
+		{
+			name:   "not a pipelinerun",
+			status: provider.StatusOpts{Status: "completed", OriginalPipelineRunName: "pr"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, action := range checkRunActions(tt.status) {
+				assert.Assert(t, len(action.Label) <= 20 && len(action.Description) <= 40 && len(action.Identifier) <= 20)
+				got = append(got, action.Identifier)
+			}
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+
+	// the name of the pipelinerun is taken back from the name of the check run
+	assert.Equal(t, checkRunPipelineRunName(getCheckName(provider.StatusOpts{OriginalPipelineRunName: "pr"},
+		&info.PacOpts{Settings: &settings.Settings{ApplicationName: "Pipelines as Code / CI"}})), "pr")
+	assert.Equal(t, checkRunPipelineRunName("pr"), "pr")
+}
+
 func TestProviderGetExistingCheckRunID(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -566,3 +723,101 @@ func TestProviderGetExistingCheckRunID(t *testing.T) {
 		})
 	}
 }
+
+func TestGithubProviderCreateIssueCommentStatus(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+
+	pr := &v1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr1", Namespace: "ns"}}
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{PipelineRuns: []*v1beta1.PipelineRun{pr}})
+
+	comments := []string{}
+	mux.HandleFunc("/repos/issue/command/issues/42/comments", func(rw http.ResponseWriter, r *http.Request) {
+		comment := &github.IssueComment{}
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(comment))
+		comments = append(comments, comment.GetBody())
+		fmt.Fprint(rw, `{"id": 1}`)
+	})
+	mux.HandleFunc("/repos/issue/command/check-runs", func(rw http.ResponseWriter, r *http.Request) {
+		t.Error("no check run should be created for an issue")
+	})
+
+	gcvs := New()
+	gcvs.Client = fakeclient
+	gcvs.Logger, _ = logger.GetLogger()
+	runevent := info.NewEvent()
+	runevent.Organization = "issue"
+	runevent.Repository = "command"
+	runevent.InstallationID = 12345
+	runevent.IssueNumber = 42
+	pacopts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "Pipelines as Code CI"}}
+
+	for _, status := range []string{"in_progress", "in_progress", "completed"} {
+		current, err := stdata.Pipeline.TektonV1beta1().PipelineRuns("ns").Get(ctx, "pr1", metav1.GetOptions{})
+		assert.NilError(t, err)
+		err = gcvs.CreateStatus(ctx, stdata.Pipeline, runevent, pacopts, provider.StatusOpts{
+			PipelineRunName: "pr1",
+			PipelineRun:     current,
+			Status:          status,
+			Conclusion:      "success",
+			Text:            "text",
+			DetailsURL:      "https://console/pr1",
+		})
+		assert.NilError(t, err)
+	}
+	assert.Equal(t, len(comments), 2)
+	assert.Equal(t, comments[0], "Pipelines as Code CI is running.<br>text")
+	assert.Equal(t, comments[1], "Pipelines as Code CI has <b>successfully</b> validated your commit.<br>text")
+
+	current, err := stdata.Pipeline.TektonV1beta1().PipelineRuns("ns").Get(ctx, "pr1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, current.GetAnnotations()[keys.LogURL], "https://console/pr1")
+}
+
+func TestGithubProviderCreateStatusSkipUnchanged(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+
+	checkrunid := int64(2026)
+	pr := &v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pr1",
+			Namespace: "ns",
+			Labels: map[string]string{
+				keys.CheckRunID: strconv.Itoa(int(checkrunid)),
+			},
+		},
+	}
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{PipelineRuns: []*v1beta1.PipelineRun{pr}})
+
+	updates := 0
+	mux.HandleFunc(fmt.Sprintf("/repos/check/run/check-runs/%d", checkrunid), func(rw http.ResponseWriter, r *http.Request) {
+		updates++
+		fmt.Fprintf(rw, `{"id": %d}`, checkrunid)
+	})
+
+	gcvs := New()
+	gcvs.Client = fakeclient
+	gcvs.Logger, _ = logger.GetLogger()
+	runevent := info.NewEvent()
+	runevent.Organization = "check"
+	runevent.Repository = "run"
+	runevent.InstallationID = 12345
+	pacopts := &info.PacOpts{Settings: &settings.Settings{}}
+
+	for _, conclusion := range []string{"success", "success", "failure"} {
+		current, err := stdata.Pipeline.TektonV1beta1().PipelineRuns("ns").Get(ctx, "pr1", metav1.GetOptions{})
+		assert.NilError(t, err)
+		err = gcvs.CreateStatus(ctx, stdata.Pipeline, runevent, pacopts, provider.StatusOpts{
+			PipelineRunName: "pr1",
+			PipelineRun:     current,
+			Status:          "completed",
+			Conclusion:      conclusion,
+			Text:            "text",
+		})
+		assert.NilError(t, err)
+	}
+	assert.Equal(t, updates, 2)
+}
@@ -0,0 +1,159 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+// taskCheckRunTexts are the title and the end of the summary of the check run
+// of a task, by its conclusion once completed or by its status.
+var taskCheckRunTexts = map[string][2]string{
+	"queued":      {"Queued", "is waiting to run"},
+	"in_progress": {"Running", "is running"},
+	"success":     {"Success", "has <b>successfully</b> completed"},
+	"failure":     {"Failed", "has <b>failed</b>"},
+	"cancelled":   {"Cancelled", "has been cancelled"},
+	"timed_out":   {"Timed out", "has <b>timed out</b>"},
+	"skipped":     {"Skipped", "has been skipped"},
+}
+
+// taskCheckRunName returns the name of the check run of a task, the task name
+// after the name of the check run of its PipelineRun.
+func taskCheckRunName(status provider.StatusOpts, pacopts *info.PacOpts, task string) string {
+	if name := getCheckName(status, pacopts); name != "" {
+		return fmt.Sprintf("%s / %s", name, task)
+	}
+	return task
+}
+
+// taskCheckRunExternalID returns the external ID of the check run of a task,
+// the check run of the PipelineRun has the PipelineRun name as external ID.
+func taskCheckRunExternalID(pipelineRunName, task string) string {
+	return pipelineRunName + "/" + task
+}
+
+// taskCheckRunIDs returns the ID of the check runs of the tasks of a
+// PipelineRun on the commit by their external ID.
+func (v *Provider) taskCheckRunIDs(ctx context.Context, runevent *info.Event, pipelineRunName string) (map[string]int64, error) {
+	ids := map[string]int64{}
+	opts := &github.ListCheckRunsOptions{
+		AppID:       v.ApplicationID,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		res, resp, err := v.Client.Checks.ListCheckRunsForRef(ctx, runevent.Organization, runevent.Repository, runevent.SHA, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, checkRun := range res.CheckRuns {
+			if strings.HasPrefix(checkRun.GetExternalID(), pipelineRunName+"/") {
+				ids[checkRun.GetExternalID()] = checkRun.GetID()
+			}
+		}
+		if resp.NextPage == 0 {
+			return ids, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// CreateTaskStatuses creates or updates the check run of each task of a
+// PipelineRun. The commit statuses used without a GitHub App are not reported
+// per task, it does nothing then.
+func (v *Provider) CreateTaskStatuses(ctx context.Context, runevent *info.Event, pacopts *info.PacOpts, prStatus provider.StatusOpts, tasks []provider.TaskStatusOpts) error {
+	if v.Client == nil {
+		return fmt.Errorf("cannot set status on github no token or url set")
+	}
+	if runevent.InstallationID == 0 {
+		v.Logger.Debugf("not reporting the tasks of pipelinerun %s, check runs per task need a github app", prStatus.PipelineRunName)
+		return nil
+	}
+
+	ids, err := v.taskCheckRunIDs(ctx, runevent, prStatus.PipelineRunName)
+	if err != nil {
+		return fmt.Errorf("cannot list the check runs of the tasks: %w", err)
+	}
+	failures := []string{}
+	for _, task := range tasks {
+		if err := v.createOrUpdateTaskCheckRun(ctx, runevent, pacopts, prStatus, task, ids); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", task.Name, err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("cannot report the check runs of %d tasks: %s", len(failures), strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+func (v *Provider) createOrUpdateTaskCheckRun(ctx context.Context, runevent *info.Event, pacopts *info.PacOpts, prStatus provider.StatusOpts, task provider.TaskStatusOpts, ids map[string]int64) error {
+	state := task.Status
+	if task.Status == "completed" {
+		state = task.Conclusion
+	}
+	texts, ok := taskCheckRunTexts[state]
+	if !ok {
+		return fmt.Errorf("unknown status %s", state)
+	}
+	output := &github.CheckRunOutput{
+		Title:   github.String(texts[0]),
+		Summary: github.String(fmt.Sprintf("The task <b>%s</b> of the PipelineRun %s %s.", task.Name, prStatus.PipelineRunName, texts[1])),
+	}
+	name := taskCheckRunName(prStatus, pacopts, task.Name)
+	externalID := taskCheckRunExternalID(prStatus.PipelineRunName, task.Name)
+
+	var conclusion *string
+	var completedAt *github.Timestamp
+	if task.Status == "completed" {
+		conclusion = github.String(task.Conclusion)
+		if task.Conclusion == "skipped" && !v.supports(ctx, featureSkippedConclusion) {
+			conclusion = github.String("neutral")
+		}
+		completedAt = &github.Timestamp{Time: time.Now()}
+		if task.CompletedAt != nil {
+			completedAt.Time = task.CompletedAt.Time
+		}
+	}
+	var detailsURL *string
+	if task.DetailsURL != "" {
+		detailsURL = github.String(task.DetailsURL)
+	}
+
+	if id, ok := ids[externalID]; ok {
+		_, _, err := v.Client.Checks.UpdateCheckRun(ctx, runevent.Organization, runevent.Repository, id, github.UpdateCheckRunOptions{
+			Name:        name,
+			ExternalID:  github.String(externalID),
+			Status:      github.String(task.Status),
+			Conclusion:  conclusion,
+			CompletedAt: completedAt,
+			DetailsURL:  detailsURL,
+			Output:      output,
+		})
+		return v.explainEnterpriseError(ctx, err, "update the check run")
+	}
+
+	opts := github.CreateCheckRunOptions{
+		Name:        name,
+		HeadSHA:     runevent.SHA,
+		ExternalID:  github.String(externalID),
+		Status:      github.String(task.Status),
+		Conclusion:  conclusion,
+		CompletedAt: completedAt,
+		DetailsURL:  detailsURL,
+		Output:      output,
+	}
+	if task.StartedAt != nil {
+		opts.StartedAt = &github.Timestamp{Time: task.StartedAt.Time}
+	}
+	checkRun, _, err := v.Client.Checks.CreateCheckRun(ctx, runevent.Organization, runevent.Repository, opts)
+	if err != nil {
+		return v.explainEnterpriseError(ctx, err, "create the check run")
+	}
+	ids[externalID] = checkRun.GetID()
+	return nil
+}
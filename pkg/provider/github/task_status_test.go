@@ -0,0 +1,94 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCreateTaskStatuses(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+
+	mux.HandleFunc("/repos/owner/repo/commits/sha/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"total_count": 3, "check_runs": [
+			{"id": 1, "external_id": "pr-abcde"},
+			{"id": 2, "external_id": "pr-abcde/build"},
+			{"id": 3, "external_id": "other-pr/test"}
+		]}`)
+	})
+	created := []map[string]interface{}{}
+	mux.HandleFunc("/repos/owner/repo/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPost)
+		opts := map[string]interface{}{}
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&opts))
+		created = append(created, opts)
+		_, _ = fmt.Fprint(w, `{"id": 4}`)
+	})
+	updated := map[string]interface{}{}
+	mux.HandleFunc("/repos/owner/repo/check-runs/2", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPatch)
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&updated))
+		_, _ = fmt.Fprint(w, `{"id": 2}`)
+	})
+
+	v := New()
+	v.Client = fakeclient
+	v.Logger, _ = logger.GetLogger()
+	event := &info.Event{Organization: "owner", Repository: "repo", SHA: "sha", InstallationID: 1}
+	pacopts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "CI"}}
+	prStatus := provider.StatusOpts{PipelineRunName: "pr-abcde", OriginalPipelineRunName: "pr"}
+
+	err := v.CreateTaskStatuses(ctx, event, pacopts, prStatus, []provider.TaskStatusOpts{
+		{Name: "build", Status: "completed", Conclusion: "failure", DetailsURL: "https://console/build"},
+		{Name: "test", Status: "queued"},
+	})
+	assert.NilError(t, err)
+
+	assert.Equal(t, updated["name"], "CI / pr / build")
+	assert.Equal(t, updated["conclusion"], "failure")
+	assert.Equal(t, updated["details_url"], "https://console/build")
+	assert.Assert(t, updated["completed_at"] != nil)
+
+	assert.Equal(t, len(created), 1)
+	assert.Equal(t, created[0]["name"], "CI / pr / test")
+	assert.Equal(t, created[0]["external_id"], "pr-abcde/test")
+	assert.Equal(t, created[0]["status"], "queued")
+	assert.Equal(t, created[0]["conclusion"], nil)
+	output, ok := created[0]["output"].(map[string]interface{})
+	assert.Assert(t, ok)
+	assert.Equal(t, output["title"], "Queued")
+}
+
+func TestCreateTaskStatusesWithoutGitHubApp(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+	mux.HandleFunc("/repos/owner/repo/commits/sha/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("check runs should not be listed without a github app")
+	})
+
+	v := New()
+	v.Client = fakeclient
+	v.Logger, _ = logger.GetLogger()
+	event := &info.Event{Organization: "owner", Repository: "repo", SHA: "sha"}
+	err := v.CreateTaskStatuses(ctx, event, &info.PacOpts{Settings: &settings.Settings{}}, provider.StatusOpts{PipelineRunName: "pr"},
+		[]provider.TaskStatusOpts{{Name: "build", Status: "in_progress"}})
+	assert.NilError(t, err)
+}
+
+func TestTaskCheckRunName(t *testing.T) {
+	status := provider.StatusOpts{OriginalPipelineRunName: "pr"}
+	assert.Equal(t, taskCheckRunName(status, &info.PacOpts{Settings: &settings.Settings{ApplicationName: "CI"}}, "build"), "CI / pr / build")
+	assert.Equal(t, taskCheckRunName(provider.StatusOpts{}, &info.PacOpts{Settings: &settings.Settings{}}, "build"), "build")
+}
@@ -0,0 +1,165 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v49/github"
+)
+
+const (
+	enterpriseVersionHeader = "X-GitHub-Enterprise-Version"
+	// enterpriseVersionTTL is how long we keep the version of a GitHub
+	// Enterprise server before asking it again, it only changes on upgrades
+	enterpriseVersionTTL = time.Hour
+	providerEnterprise   = "github-enterprise"
+)
+
+// serverVersion is the major and minor version of a GitHub Enterprise Server
+type serverVersion struct {
+	major, minor int
+}
+
+func (s serverVersion) String() string {
+	return fmt.Sprintf("%d.%d", s.major, s.minor)
+}
+
+func (s serverVersion) atLeast(other serverVersion) bool {
+	return s.major > other.major || (s.major == other.major && s.minor >= other.minor)
+}
+
+// parseServerVersion parses a version like 3.7.2 as reported by GitHub
+// Enterprise Server, only the major and minor versions matter for the
+// features.
+func parseServerVersion(version string) (serverVersion, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".")
+	if len(parts) < 2 {
+		return serverVersion{}, fmt.Errorf("invalid github enterprise version %q", version)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return serverVersion{}, fmt.Errorf("invalid github enterprise version %q: %w", version, err)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return serverVersion{}, fmt.Errorf("invalid github enterprise version %q: %w", version, err)
+	}
+	return serverVersion{major: major, minor: minor}, nil
+}
+
+// enterpriseFeature is a feature we use which is not available on the older
+// GitHub Enterprise Servers, and what we do instead on them.
+type enterpriseFeature struct {
+	name       string
+	minVersion serverVersion
+	fallback   string
+}
+
+var (
+	featureSkippedConclusion = enterpriseFeature{
+		name:       "the skipped conclusion of the check runs",
+		minVersion: serverVersion{major: 3, minor: 0},
+		fallback:   "using the neutral conclusion instead",
+	}
+	featureDeploymentStates = enterpriseFeature{
+		name:       "the queued and in_progress states of the deployments",
+		minVersion: serverVersion{major: 3, minor: 1},
+		fallback:   "reporting them as pending",
+	}
+)
+
+type cachedVersion struct {
+	// version is nil when it cannot be detected
+	version *serverVersion
+	checked time.Time
+}
+
+// enterpriseVersions caches the version of the GitHub Enterprise Servers by
+// API URL, a provider is created for every event.
+var enterpriseVersions = struct {
+	sync.Mutex
+	versions map[string]cachedVersion
+}{versions: map[string]cachedVersion{}}
+
+// enterpriseVersion returns the version of the GitHub Enterprise Server of the
+// client, from the header of its meta API or the installed version it
+// reports, nil when it cannot be detected.
+func (v *Provider) enterpriseVersion(ctx context.Context) *serverVersion {
+	apiURL := v.Client.BaseURL.String()
+	enterpriseVersions.Lock()
+	defer enterpriseVersions.Unlock()
+	if cached, ok := enterpriseVersions.versions[apiURL]; ok && time.Since(cached.checked) < enterpriseVersionTTL {
+		return cached.version
+	}
+
+	cached := cachedVersion{checked: time.Now()}
+	version, err := v.detectEnterpriseVersion(ctx)
+	if err != nil {
+		v.Logger.Warnf("cannot detect the version of github enterprise %s, assuming all the features are available: %v", apiURL, err)
+	} else {
+		cached.version = &version
+		v.Logger.Debugf("github enterprise %s is at version %s", apiURL, version)
+	}
+	enterpriseVersions.versions[apiURL] = cached
+	return cached.version
+}
+
+func (v *Provider) detectEnterpriseVersion(ctx context.Context) (serverVersion, error) {
+	req, err := v.Client.NewRequest(http.MethodGet, "meta", nil)
+	if err != nil {
+		return serverVersion{}, err
+	}
+	meta := &struct {
+		InstalledVersion string `json:"installed_version"`
+	}{}
+	resp, err := v.Client.Do(ctx, req, meta)
+	if err != nil {
+		return serverVersion{}, err
+	}
+	if header := resp.Header.Get(enterpriseVersionHeader); header != "" {
+		return parseServerVersion(header)
+	}
+	if meta.InstalledVersion == "" {
+		return serverVersion{}, fmt.Errorf("the server does not report its version")
+	}
+	return parseServerVersion(meta.InstalledVersion)
+}
+
+// supports returns whether the GitHub server supports a feature, always true
+// on public GitHub or when we don't know the version of GitHub Enterprise. A
+// warning is logged when we have to do without it.
+func (v *Provider) supports(ctx context.Context, feature enterpriseFeature) bool {
+	if v.providerName != providerEnterprise || v.Client == nil {
+		return true
+	}
+	version := v.enterpriseVersion(ctx)
+	if version == nil || version.atLeast(feature.minVersion) {
+		return true
+	}
+	v.Logger.Warnf("github enterprise %s at version %s doesn't support %s which needs version %s, %s",
+		v.Client.BaseURL.String(), version, feature.name, feature.minVersion, feature.fallback)
+	return false
+}
+
+// explainEnterpriseError explains the validation errors of GitHub Enterprise
+// Server, they usually mean the version of the server doesn't support a field
+// we sent.
+func (v *Provider) explainEnterpriseError(ctx context.Context, err error, action string) error {
+	var errResp *github.ErrorResponse
+	if err == nil || v.providerName != providerEnterprise || !errors.As(err, &errResp) ||
+		errResp.Response == nil || errResp.Response.StatusCode != http.StatusUnprocessableEntity {
+		return err
+	}
+	version := "unknown"
+	if detected := v.enterpriseVersion(ctx); detected != nil {
+		version = detected.String()
+	}
+	return fmt.Errorf("github enterprise %s at version %s has refused to %s, this version may not support one of its fields: %w",
+		v.Client.BaseURL.String(), version, action, err)
+}
@@ -0,0 +1,129 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v49/github"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    serverVersion
+		wantErr string
+	}{
+		{version: "3.7.2", want: serverVersion{major: 3, minor: 7}},
+		{version: "v2.22", want: serverVersion{major: 2, minor: 22}},
+		{version: "3", wantErr: "invalid github enterprise version"},
+		{version: "three.one", wantErr: "invalid github enterprise version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := parseServerVersion(tt.version)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestSupports(t *testing.T) {
+	tests := []struct {
+		name         string
+		providerName string
+		header       string
+		body         string
+		code         int
+		want         bool
+		wantWarning  string
+	}{
+		{
+			name: "public github",
+			want: true,
+		},
+		{
+			name:         "version from the header",
+			providerName: providerEnterprise,
+			header:       "2.22.1",
+			body:         `{}`,
+			want:         false,
+			wantWarning:  "at version 2.22 doesn't support the queued and in_progress states of the deployments which needs version 3.1",
+		},
+		{
+			name:         "installed version",
+			providerName: providerEnterprise,
+			body:         `{"installed_version": "3.7.0"}`,
+			want:         true,
+		},
+		{
+			name:         "unknown version",
+			providerName: providerEnterprise,
+			code:         http.StatusNotFound,
+			want:         true,
+			wantWarning:  "cannot detect the version of github enterprise",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			calls := 0
+			mux.HandleFunc("/meta", func(rw http.ResponseWriter, r *http.Request) {
+				calls++
+				if tt.header != "" {
+					rw.Header().Set(enterpriseVersionHeader, tt.header)
+				}
+				if tt.code != 0 {
+					rw.WriteHeader(tt.code)
+				}
+				fmt.Fprint(rw, tt.body)
+			})
+			observer, logs := zapobserver.New(zap.InfoLevel)
+			v := &Provider{Client: fakeclient, providerName: tt.providerName, Logger: zap.New(observer).Sugar()}
+
+			assert.Equal(t, v.supports(ctx, featureDeploymentStates), tt.want)
+			if tt.wantWarning != "" {
+				assert.Assert(t, logs.FilterMessageSnippet(tt.wantWarning).Len() > 0, logs.All())
+			}
+			// the version is cached
+			v.supports(ctx, featureDeploymentStates)
+			if tt.providerName == providerEnterprise {
+				assert.Equal(t, calls, 1)
+			}
+		})
+	}
+}
+
+func TestExplainEnterpriseError(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+	mux.HandleFunc("/meta", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"installed_version": "2.21.3"}`)
+	})
+	v := &Provider{Client: fakeclient, providerName: providerEnterprise}
+	v.Logger = zap.NewNop().Sugar()
+
+	unprocessable := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusUnprocessableEntity}, Message: "Validation Failed"}
+	err := v.explainEnterpriseError(ctx, unprocessable, "update the check run")
+	assert.ErrorContains(t, err, "at version 2.21 has refused to update the check run, this version may not support one of its fields")
+	assert.ErrorIs(t, err, unprocessable)
+
+	notFound := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	assert.Equal(t, v.explainEnterpriseError(ctx, notFound, "update the check run"), error(notFound))
+	assert.NilError(t, v.explainEnterpriseError(ctx, nil, "update the check run"))
+
+	v.providerName = "github"
+	assert.Equal(t, v.explainEnterpriseError(ctx, unprocessable, "update the check run"), error(unprocessable))
+}
@@ -3,6 +3,7 @@ package gitlab
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/acl"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
@@ -63,6 +64,23 @@ func (v *Provider) checkOkToTestCommentFromApprovedMember(event *info.Event, pag
 	return false, nil
 }
 
+// IsMaintainer checks the sender has at least the maintainer role on the
+// project, directly or from its groups.
+func (v *Provider) IsMaintainer(_ context.Context, _ *info.Event) (bool, error) {
+	if v.Client == nil {
+		return false, fmt.Errorf("no gitlab client has been initiliazed, " +
+			"exiting... (hint: did you forget setting a secret on your repo?)")
+	}
+	member, resp, err := v.Client.ProjectMembers.GetInheritedProjectMember(v.targetProjectID, v.userID)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return member.AccessLevel >= gitlab.MaintainerPermissions, nil
+}
+
 func (v *Provider) IsAllowed(ctx context.Context, event *info.Event) (bool, error) {
 	if v.Client == nil {
 		return false, fmt.Errorf("no github client has been initiliazed, " +
@@ -29,7 +29,11 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 		return isGL, processEvent, logger, reason, err
 	}
 
-	eventInt, err := gitlab.ParseWebhook(gitlab.EventType(event), []byte(payload))
+	eventType, err := hookEventType(event, []byte(payload))
+	if err != nil {
+		return setLoggerAndProceed(false, err.Error(), nil)
+	}
+	eventInt, err := gitlab.ParseWebhook(eventType, []byte(payload))
 	if err != nil {
 		return setLoggerAndProceed(false, "", err)
 	}
@@ -37,7 +41,7 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 
 	switch gitEvent := eventInt.(type) {
 	case *gitlab.MergeEvent:
-		if provider.Valid(gitEvent.ObjectAttributes.Action, []string{"open", "update", "reopen"}) {
+		if provider.Valid(gitEvent.ObjectAttributes.Action, []string{"open", "update", "reopen", "close", "merge"}) {
 			return setLoggerAndProceed(true, "", nil)
 		}
 		return setLoggerAndProceed(false, fmt.Sprintf("not a merge event we care about: \"%s\"",
@@ -55,9 +59,45 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 			if provider.IsCancelComment(gitEvent.ObjectAttributes.Note) {
 				return setLoggerAndProceed(true, "", nil)
 			}
+			if provider.IsHelpComment(gitEvent.ObjectAttributes.Note) {
+				return setLoggerAndProceed(true, "", nil)
+			}
 		}
 		return setLoggerAndProceed(false, "not a gitops style merge comment event", nil)
 	default:
 		return setLoggerAndProceed(false, "", fmt.Errorf("gitlab: event \"%s\" is not supported", event))
 	}
 }
+
+// systemHookEventTypes are the event types of the project hooks with the same
+// payload as the events of the system hooks we care about
+var systemHookEventTypes = map[string]gitlab.EventType{
+	"push":          gitlab.EventTypePush,
+	"merge_request": gitlab.EventTypeMergeRequest,
+}
+
+// hookEventType returns the event type of a webhook. The system hooks are
+// sent for the projects of the whole instance with the payload of the project
+// hooks, their event type is the one of the project hooks. The group hooks
+// already have the event type of the project hooks.
+func hookEventType(event string, payload []byte) (gitlab.EventType, error) {
+	if gitlab.EventType(event) != gitlab.EventTypeSystemHook {
+		return gitlab.EventType(event), nil
+	}
+	systemHook := struct {
+		EventName  string `json:"event_name"`
+		ObjectKind string `json:"object_kind"`
+	}{}
+	if err := json.Unmarshal(payload, &systemHook); err != nil {
+		return "", fmt.Errorf("cannot parse the system hook: %w", err)
+	}
+	name := systemHook.EventName
+	if name == "" {
+		name = systemHook.ObjectKind
+	}
+	eventType, ok := systemHookEventTypes[name]
+	if !ok {
+		return "", fmt.Errorf("not a system hook event we care about: \"%s\"", name)
+	}
+	return eventType, nil
+}
@@ -105,6 +105,28 @@ func TestProvider_Detect(t *testing.T) {
 			isGL:       true,
 			processReq: true,
 		},
+		{
+			name:       "system hook push event",
+			event:      strings.Replace(sample.PushEventAsJSON(true), "{", `{"event_name": "push",`, 1),
+			eventType:  gitlab.EventTypeSystemHook,
+			isGL:       true,
+			processReq: true,
+		},
+		{
+			name:       "system hook merge event",
+			event:      strings.Replace(sample.MREventAsJSON(), "{", `{"object_kind": "merge_request",`, 1),
+			eventType:  gitlab.EventTypeSystemHook,
+			isGL:       true,
+			processReq: true,
+		},
+		{
+			name:       "system hook event we don't care about",
+			event:      `{"event_name": "project_create", "path_with_namespace": "hello/project"}`,
+			eventType:  gitlab.EventTypeSystemHook,
+			isGL:       true,
+			processReq: false,
+			wantReason: "not a system hook event we care about: \"project_create\"",
+		},
 	}
 
 	for _, tt := range tests {
@@ -3,12 +3,14 @@ package gitlab
 import (
 	"context"
 	"crypto/subtle"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path/filepath"
 	"strings"
 
+	"github.com/gobwas/glob"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
@@ -168,40 +170,94 @@ func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event
 	case "pending":
 		statusOpts.Conclusion = "running"
 	}
+	if statusOpts.Status == "in_progress" {
+		statusOpts.Conclusion = "running"
+		statusOpts.Title = "started"
+	}
 	if statusOpts.DetailsURL != "" {
 		detailsURL = statusOpts.DetailsURL
 	}
 
-	onPr := ""
-	if statusOpts.OriginalPipelineRunName != "" {
-		onPr = "/" + statusOpts.OriginalPipelineRunName
-	}
-	body := fmt.Sprintf("**%s%s** has %s\n\n%s\n\n<small>Full log available [here](%s)</small>",
-		pacOpts.ApplicationName, onPr, statusOpts.Title, statusOpts.Text, detailsURL)
+	statusName := getStatusName(statusOpts, pacOpts)
+	body := fmt.Sprintf("%s\n**%s** has %s\n\n%s\n\n<small>Full log available [here](%s)</small>",
+		noteMarker(statusName), statusName, statusOpts.Title, statusOpts.Text, detailsURL)
 
 	// in case we have access set the commit status, typically on MR from
 	// another users we won't have it but it would work on push or MR from a
 	// branch on the same repo or if token somehow can have access by other
 	// means.
 	// if we have an error fallback to send a issue comment
+	// The status name is for each PipelineRun so they show as separate stages.
 	opt := &gitlab.SetCommitStatusOptions{
 		State:       gitlab.BuildStateValue(statusOpts.Conclusion),
-		Name:        gitlab.String(pacOpts.ApplicationName),
+		Name:        gitlab.String(statusName),
 		TargetURL:   gitlab.String(detailsURL),
 		Description: gitlab.String(statusOpts.Title),
 	}
-	//nolint: dogsled
-	_, _, _ = v.Client.Commits.SetCommitStatus(event.SourceProjectID, event.SHA, opt)
+	if !pacOpts.DisableCommitStatuses {
+		//nolint: dogsled
+		_, _, _ = v.Client.Commits.SetCommitStatus(event.SourceProjectID, event.SHA, opt)
+	}
 
 	// only add a note when we are on a MR
-	if event.EventType == "pull_request" || event.EventType == "Merge_Request" {
-		mopt := &gitlab.CreateMergeRequestNoteOptions{Body: gitlab.String(body)}
-		_, _, err := v.Client.Notes.CreateMergeRequestNote(event.TargetProjectID, event.PullRequestNumber, mopt)
-		return err
+	if !pacOpts.DisablePullRequestComments && (event.EventType == "pull_request" || event.EventType == "Merge_Request") {
+		return v.createOrUpdateNote(event, statusName, body)
 	}
 	return nil
 }
 
+// CreateStatuses sets the last commit status of each PipelineRun of a batch,
+// GitLab takes them one at a time
+func (v *Provider) CreateStatuses(ctx context.Context, tekton versioned.Interface, event *info.Event, opts *info.PacOpts, statuses []provider.StatusOpts) error {
+	return provider.CreateStatuses(ctx, v, tekton, event, opts, statuses)
+}
+
+// getStatusName return the name of the commit status for a PipelineRun, the
+// same way as the GitHub check names "Application / PipelineRun"
+func getStatusName(statusOpts provider.StatusOpts, pacOpts *info.PacOpts) string {
+	if statusOpts.OriginalPipelineRunName == "" {
+		return pacOpts.ApplicationName
+	}
+	if pacOpts.ApplicationName == "" {
+		return statusOpts.OriginalPipelineRunName
+	}
+	return fmt.Sprintf("%s / %s", pacOpts.ApplicationName, statusOpts.OriginalPipelineRunName)
+}
+
+// noteMarker is an hidden comment we add to the note so we can find the note
+// of a PipelineRun and update it in place.
+func noteMarker(statusName string) string {
+	return fmt.Sprintf("<!-- pipelines-as-code: %s -->", statusName)
+}
+
+// createOrUpdateNote update the summary note of the PipelineRun on the merge
+// request if it already exist or create a new one.
+func (v *Provider) createOrUpdateNote(event *info.Event, statusName, body string) error {
+	marker := noteMarker(statusName)
+	opt := &gitlab.ListMergeRequestNotesOptions{ListOptions: gitlab.ListOptions{PerPage: 100}}
+	for {
+		notes, resp, err := v.Client.Notes.ListMergeRequestNotes(event.TargetProjectID, event.PullRequestNumber, opt)
+		if err != nil {
+			return err
+		}
+		for _, note := range notes {
+			if strings.HasPrefix(note.Body, marker) {
+				_, _, err := v.Client.Notes.UpdateMergeRequestNote(event.TargetProjectID, event.PullRequestNumber, note.ID,
+					&gitlab.UpdateMergeRequestNoteOptions{Body: gitlab.String(body)})
+				return err
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	mopt := &gitlab.CreateMergeRequestNoteOptions{Body: gitlab.String(body)}
+	_, _, err := v.Client.Notes.CreateMergeRequestNote(event.TargetProjectID, event.PullRequestNumber, mopt)
+	return err
+}
+
 func (v *Provider) GetTektonDir(_ context.Context, event *info.Event, path string) (string, error) {
 	if v.Client == nil {
 		return "", fmt.Errorf("no gitlab client has been initiliazed, " +
@@ -289,18 +345,66 @@ func (v *Provider) GetCommitInfo(_ context.Context, runevent *info.Event) error
 	return nil
 }
 
+// IsRefProtected check if the branch or the tag (refs/tags/) is protected on
+// the target project, protected tags can be wildcards.
+func (v *Provider) IsRefProtected(ctx context.Context, runevent *info.Event, ref string) (bool, error) {
+	if v.Client == nil {
+		return false, fmt.Errorf("no gitlab client has been initiliazed, " +
+			"exiting... (hint: did you forget setting a secret on your repo?)")
+	}
+	if strings.HasPrefix(ref, "refs/tags/") {
+		tag := strings.TrimPrefix(ref, "refs/tags/")
+		protections, _, err := v.Client.ProtectedTags.ListProtectedTags(v.targetProjectID, &gitlab.ListProtectedTagsOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, protection := range protections {
+			g, err := glob.Compile(protection.Name)
+			if err != nil {
+				continue
+			}
+			if g.Match(tag) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	// gitlab resolve the wildcard protected branches for us
+	branch, _, err := v.Client.Branches.GetBranch(v.targetProjectID, strings.TrimPrefix(ref, "refs/heads/"))
+	if err != nil {
+		return false, err
+	}
+	return branch.Protected, nil
+}
+
 func (v *Provider) GetFiles(ctx context.Context, runevent *info.Event) ([]string, error) {
 	if v.Client == nil {
 		return []string{}, fmt.Errorf("no gitlab client has been initiliazed, " +
 			"exiting... (hint: did you forget setting a secret on your repo?)")
 	}
 	if runevent.TriggerTarget == "pull_request" {
-		mrchanges, _, err := v.Client.MergeRequests.GetMergeRequestChanges(v.sourceProjectID, runevent.PullRequestNumber, &gitlab.GetMergeRequestChangesOptions{})
+		result, err := v.listChangedFiles(fmt.Sprintf("merge request %d", runevent.PullRequestNumber),
+			func(opt gitlab.ListOptions) ([]*gitlab.Diff, *gitlab.Response, error) {
+				return v.listMergeRequestDiffs(runevent.PullRequestNumber, opt)
+			})
+		if err == nil {
+			return result, nil
+		}
+		var errResp *gitlab.ErrorResponse
+		if !errors.As(err, &errResp) || errResp.Response == nil || errResp.Response.StatusCode != http.StatusNotFound {
+			return []string{}, err
+		}
+
+		// the diffs API is not paginated before GitLab 15.7, the raw diffs
+		// of the changes API are not limited in size
+		mrchanges, _, err := v.Client.MergeRequests.GetMergeRequestChanges(v.sourceProjectID, runevent.PullRequestNumber,
+			&gitlab.GetMergeRequestChangesOptions{AccessRawDiffs: gitlab.Bool(true)})
 		if err != nil {
 			return []string{}, err
 		}
 
-		result := []string{}
+		result = []string{}
 		for _, change := range mrchanges.Changes {
 			result = append(result, change.NewPath)
 		}
@@ -308,15 +412,53 @@ func (v *Provider) GetFiles(ctx context.Context, runevent *info.Event) ([]string
 	}
 
 	if runevent.TriggerTarget == "push" {
-		pushChanges, _, err := v.Client.Commits.GetCommitDiff(v.sourceProjectID, runevent.SHA, &gitlab.GetCommitDiffOptions{})
+		return v.listChangedFiles(fmt.Sprintf("commit %s", runevent.SHA),
+			func(opt gitlab.ListOptions) ([]*gitlab.Diff, *gitlab.Response, error) {
+				diffOpt := gitlab.GetCommitDiffOptions(opt)
+				return v.Client.Commits.GetCommitDiff(v.sourceProjectID, runevent.SHA, &diffOpt)
+			})
+	}
+	return []string{}, nil
+}
+
+// listMergeRequestDiffs lists a page of the diffs of a merge request, the
+// client doesn't have this API yet.
+func (v *Provider) listMergeRequestDiffs(mergeRequest int, opt gitlab.ListOptions) ([]*gitlab.Diff, *gitlab.Response, error) {
+	req, err := v.Client.NewRequest(http.MethodGet,
+		fmt.Sprintf("projects/%d/merge_requests/%d/diffs", v.sourceProjectID, mergeRequest), &opt, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	diffs := []*gitlab.Diff{}
+	resp, err := v.Client.Do(req, &diffs)
+	if err != nil {
+		return nil, resp, err
+	}
+	return diffs, resp, nil
+}
+
+// listChangedFiles goes through the pages of the changed files of a merge
+// request or a commit, up to provider.MaxChangedFiles files.
+func (v *Provider) listChangedFiles(what string, list func(gitlab.ListOptions) ([]*gitlab.Diff, *gitlab.Response, error)) ([]string, error) {
+	result := []string{}
+	opt := gitlab.ListOptions{PerPage: provider.ChangedFilesPerPage}
+	for {
+		diffs, resp, err := list(opt)
 		if err != nil {
 			return []string{}, err
 		}
-		result := []string{}
-		for _, change := range pushChanges {
-			result = append(result, change.NewPath)
+		for _, diff := range diffs {
+			result = append(result, diff.NewPath)
 		}
-		return result, nil
+		if resp == nil || resp.NextPage == 0 {
+			return result, nil
+		}
+		if len(result) >= provider.MaxChangedFiles {
+			if v.Logger != nil {
+				v.Logger.Warnf("%s has more than %d changed files, only the first ones are matched", what, provider.MaxChangedFiles)
+			}
+			return result[:provider.MaxChangedFiles], nil
+		}
+		opt.Page = resp.NextPage
 	}
-	return []string{}, nil
 }
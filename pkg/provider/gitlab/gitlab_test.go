@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
@@ -174,6 +175,89 @@ func TestCreateStatus(t *testing.T) {
 	}
 }
 
+func TestCreateStatusUpdateNote(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	client, mux, tearDown := thelp.Setup(ctx, t)
+	defer tearDown()
+	v := &Provider{Client: client}
+
+	event := info.NewEvent()
+	event.EventType = "pull_request"
+	event.PullRequestNumber = 666
+	event.SHA = "sha"
+	pacOpts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "Test me"}}
+	statusOpts := provider.StatusOpts{
+		Conclusion:              "success",
+		OriginalPipelineRunName: "pr1",
+	}
+	mux.HandleFunc(fmt.Sprintf("/projects/0/statuses/%s", event.SHA), func(rw http.ResponseWriter, r *http.Request) {
+		bit, _ := io.ReadAll(r.Body)
+		assert.Assert(t, strings.Contains(string(bit), "Test me / pr1"), string(bit))
+		fmt.Fprint(rw, "{}")
+	})
+	thelp.MuxNoteUpdate(t, mux, 0, 666, 42, noteMarker("Test me / pr1")+"\n**Test me / pr1** has started", "has successfully")
+	assert.NilError(t, v.CreateStatus(ctx, nil, event, pacOpts, statusOpts))
+}
+
+func TestCreateStatusUpdateNoteNextPage(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	client, mux, tearDown := thelp.Setup(ctx, t)
+	defer tearDown()
+	v := &Provider{Client: client}
+
+	event := info.NewEvent()
+	event.EventType = "pull_request"
+	event.PullRequestNumber = 666
+	event.SHA = "sha"
+	pacOpts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "Test me"}}
+	statusOpts := provider.StatusOpts{
+		Conclusion:              "success",
+		OriginalPipelineRunName: "pr1",
+	}
+	mux.HandleFunc(fmt.Sprintf("/projects/0/statuses/%s", event.SHA), func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, "{}")
+	})
+	mux.HandleFunc("/projects/0/merge_requests/666/notes", func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodGet, "a new note should not be created")
+		notes := []map[string]interface{}{{"id": 1, "body": "lgtm"}}
+		if r.URL.Query().Get("page") == "2" {
+			notes = []map[string]interface{}{{"id": 42, "body": noteMarker("Test me / pr1") + "\n**Test me / pr1** has started"}}
+		} else {
+			rw.Header().Set("X-Next-Page", "2")
+		}
+		b, _ := json.Marshal(notes)
+		fmt.Fprint(rw, string(b))
+	})
+	updated := false
+	mux.HandleFunc("/projects/0/merge_requests/666/notes/42", func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPut)
+		updated = true
+		fmt.Fprint(rw, "{}")
+	})
+	assert.NilError(t, v.CreateStatus(ctx, nil, event, pacOpts, statusOpts))
+	assert.Assert(t, updated, "the note on the second page should have been updated")
+}
+
+func TestGetStatusName(t *testing.T) {
+	tests := []struct {
+		name            string
+		applicationName string
+		prName          string
+		want            string
+	}{
+		{name: "application and pipelinerun", applicationName: "App", prName: "pr", want: "App / pr"},
+		{name: "only application", applicationName: "App", want: "App"},
+		{name: "only pipelinerun", prName: "pr", want: "pr"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pacOpts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: tt.applicationName}}
+			got := getStatusName(provider.StatusOpts{OriginalPipelineRunName: tt.prName}, pacOpts)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
 func TestGetCommitInfo(t *testing.T) {
 	ctx, _ := rtesting.SetupFakeContext(t)
 	client, _, tearDown := thelp.Setup(ctx, t)
@@ -518,3 +602,83 @@ func TestGetFiles(t *testing.T) {
 		})
 	}
 }
+
+func TestIsRefProtected(t *testing.T) {
+	tests := []struct {
+		name          string
+		ref           string
+		wantProtected bool
+		wantErr       string
+	}{
+		{
+			name:          "protected branch",
+			ref:           "refs/heads/main",
+			wantProtected: true,
+		},
+		{
+			name: "unprotected branch",
+			ref:  "feature",
+		},
+		{
+			name:          "protected tag",
+			ref:           "refs/tags/v1.0.0",
+			wantProtected: true,
+		},
+		{
+			name: "unprotected tag",
+			ref:  "refs/tags/nightly",
+		},
+		{
+			name:    "unknown branch",
+			ref:     "nowhere",
+			wantErr: "404",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			fakeclient, mux, teardown := thelp.Setup(ctx, t)
+			defer teardown()
+			mux.HandleFunc("/projects/10/repository/branches/main", func(rw http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(rw, `{"name": "main", "protected": true}`)
+			})
+			mux.HandleFunc("/projects/10/repository/branches/feature", func(rw http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(rw, `{"name": "feature", "protected": false}`)
+			})
+			mux.HandleFunc("/projects/10/protected_tags", func(rw http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(rw, `[{"name": "v*"}]`)
+			})
+			providerInfo := &Provider{Client: fakeclient, targetProjectID: 10}
+			protected, err := providerInfo.IsRefProtected(ctx, &info.Event{}, tt.ref)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, protected, tt.wantProtected)
+		})
+	}
+}
+
+func TestGetFilesMergeRequestDiffs(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, teardown := thelp.Setup(ctx, t)
+	defer teardown()
+	mux.HandleFunc("/projects/0/merge_requests/10/diffs", func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Query().Get("per_page"), fmt.Sprintf("%d", provider.ChangedFilesPerPage))
+		diffs := []*gitlab.Diff{{NewPath: "first.txt"}}
+		if r.URL.Query().Get("page") == "2" {
+			diffs = []*gitlab.Diff{{NewPath: "second.yaml"}}
+		} else {
+			rw.Header().Set("X-Next-Page", "2")
+		}
+		jeez, err := json.Marshal(diffs)
+		assert.NilError(t, err)
+		_, _ = rw.Write(jeez)
+	})
+
+	providerInfo := &Provider{Client: fakeclient}
+	fileData, err := providerInfo.GetFiles(ctx, &info.Event{TriggerTarget: "pull_request", PullRequestNumber: 10})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, fileData, []string{"first.txt", "second.yaml"})
+}
@@ -13,6 +13,13 @@ import (
 	"github.com/xanzy/go-gitlab"
 )
 
+// zeroSHA is the after sha of the pushes deleting a branch
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+// publicVisibilityLevel is the visibility_level of the public projects in the
+// webhook payloads
+const publicVisibilityLevel = 20
+
 func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.Request,
 	payload string,
 ) (*info.Event, error) {
@@ -25,7 +32,11 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 	}
 
 	payloadB := []byte(payload)
-	eventInt, err := gitlab.ParseWebhook(gitlab.EventType(event), payloadB)
+	eventType, err := hookEventType(event, payloadB)
+	if err != nil {
+		return nil, err
+	}
+	eventInt, err := gitlab.ParseWebhook(eventType, payloadB)
 	if err != nil {
 		return nil, err
 	}
@@ -45,6 +56,15 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.BaseBranch = gitEvent.ObjectAttributes.TargetBranch
 		processedEvent.PullRequestNumber = gitEvent.ObjectAttributes.IID
 		processedEvent.PullRequestTitle = gitEvent.ObjectAttributes.Title
+		processedEvent.CancelInProgress = provider.Valid(gitEvent.ObjectAttributes.Action, []string{"close", "merge"})
+		processedEvent.PullRequestDraft = gitEvent.ObjectAttributes.WorkInProgress
+		processedEvent.PullRequestLabels = labelTitles(gitEvent.Labels)
+		processedEvent.PullRequestAssignees = usernames(gitEvent.Assignees)
+		// an update without a new commit may only have changed the labels
+		if gitEvent.ObjectAttributes.Action == "update" && gitEvent.ObjectAttributes.OldRev == "" {
+			processedEvent.LabelsAdded, processedEvent.LabelsRemoved = labelChanges(
+				gitEvent.Changes.Labels.Previous, gitEvent.Changes.Labels.Current)
+		}
 		v.targetProjectID = gitEvent.Project.ID
 		v.sourceProjectID = gitEvent.ObjectAttributes.SourceProjectID
 		v.userID = gitEvent.User.ID
@@ -55,16 +75,23 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.SourceProjectID = gitEvent.ObjectAttributes.SourceProjectID
 		processedEvent.TargetProjectID = gitEvent.Project.ID
 	case *gitlab.PushEvent:
-		if len(gitEvent.Commits) == 0 {
+		// a push deleting the branch has no commits and the null sha as after
+		branchDeleted := gitEvent.After == zeroSHA
+		if len(gitEvent.Commits) == 0 && !branchDeleted {
 			return nil, fmt.Errorf("no commits attached to this push event")
 		}
 		processedEvent = info.NewEvent()
 		processedEvent.Sender = gitEvent.UserUsername
 		processedEvent.DefaultBranch = gitEvent.Project.DefaultBranch
 		processedEvent.URL = gitEvent.Project.WebURL
-		processedEvent.SHA = gitEvent.Commits[0].ID
-		processedEvent.SHAURL = gitEvent.Commits[0].URL
-		processedEvent.SHATitle = gitEvent.Commits[0].Title
+		if branchDeleted {
+			processedEvent.SHA = gitEvent.Before
+			processedEvent.BranchDeleted = true
+		} else {
+			processedEvent.SHA = gitEvent.Commits[0].ID
+			processedEvent.SHAURL = gitEvent.Commits[0].URL
+			processedEvent.SHATitle = gitEvent.Commits[0].Title
+		}
 		processedEvent.HeadBranch = gitEvent.Ref
 		processedEvent.BaseBranch = gitEvent.Ref
 		processedEvent.TriggerTarget = "push"
@@ -86,9 +113,15 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.SHATitle = gitEvent.MergeRequest.LastCommit.Message
 		processedEvent.BaseBranch = gitEvent.MergeRequest.TargetBranch
 		processedEvent.HeadBranch = gitEvent.MergeRequest.SourceBranch
+		processedEvent.CommentCommand = provider.CommentCommand(gitEvent.ObjectAttributes.Note)
+		// the payload only has the id of the author of the merge request
+		if gitEvent.MergeRequest.AuthorID == gitEvent.User.ID {
+			processedEvent.PullRequestAuthor = gitEvent.User.Username
+		}
 		// if it is a /test or /retest comment with pipelinerun name figure out the pipelineRun name
 		if provider.IsTestRetestComment(gitEvent.ObjectAttributes.Note) {
 			processedEvent.TargetTestPipelineRun = provider.GetPipelineRunFromTestComment(gitEvent.ObjectAttributes.Note)
+			processedEvent.TestParams = provider.GetParamsFromTestComment(gitEvent.ObjectAttributes.Note)
 		}
 		if provider.IsCancelComment(gitEvent.ObjectAttributes.Note) {
 			processedEvent.TargetCancelPipelineRun = provider.GetPipelineRunFromCancelComment(gitEvent.ObjectAttributes.Note)
@@ -109,12 +142,68 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 	}
 
 	processedEvent.Event = eventInt
+	processedEvent.RepositoryPrivate = isPrivateProject(payloadB)
 
 	// Remove the " Hook" suffix so looks better in status, and since we don't
 	// really use it anymore we good to do whatever we want with it for
 	// cosmetics.
-	processedEvent.EventType = strings.ReplaceAll(event, " Hook", "")
+	processedEvent.EventType = strings.ReplaceAll(string(eventType), " Hook", "")
 
 	v.repoURL = processedEvent.URL
 	return processedEvent, nil
 }
+
+// isPrivateProject returns whether the project of the payload is not public,
+// the internal projects are only visible to the logged in users
+func isPrivateProject(payload []byte) bool {
+	project := struct {
+		Project struct {
+			VisibilityLevel *int `json:"visibility_level"`
+		} `json:"project"`
+	}{}
+	if err := json.Unmarshal(payload, &project); err != nil || project.Project.VisibilityLevel == nil {
+		return false
+	}
+	return *project.Project.VisibilityLevel != publicVisibilityLevel
+}
+
+// labelTitles returns the titles of the labels of a merge request
+func labelTitles(labels []*gitlab.EventLabel) []string {
+	titles := make([]string, 0, len(labels))
+	for _, label := range labels {
+		titles = append(titles, label.Title)
+	}
+	return titles
+}
+
+// usernames returns the usernames of the assignees of a merge request
+func usernames(users []*gitlab.EventUser) []string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Username)
+	}
+	return names
+}
+
+// labelChanges returns the labels added and removed between the previous and
+// current labels of a merge request
+func labelChanges(previous, current []*gitlab.EventLabel) ([]string, []string) {
+	var added, removed []string
+	before := map[string]bool{}
+	for _, label := range previous {
+		before[label.Title] = true
+	}
+	after := map[string]bool{}
+	for _, label := range current {
+		after[label.Title] = true
+		if !before[label.Title] {
+			added = append(added, label.Title)
+		}
+	}
+	for _, label := range previous {
+		if !after[label.Title] {
+			removed = append(removed, label.Title)
+		}
+	}
+	return added, removed
+}
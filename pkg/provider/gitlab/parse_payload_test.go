@@ -2,6 +2,7 @@ package gitlab
 
 import (
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
@@ -74,6 +75,26 @@ func TestParsePayload(t *testing.T) {
 				Repository:    "project",
 			},
 		},
+		{
+			name: "merge event changing the labels",
+			args: args{
+				event: gitlab.EventTypeMergeRequest,
+				payload: `{"object_kind": "merge_request", "user": {"username": "foo"},
+"project": {"id": 100, "web_url": "https://foo.com"},
+"object_attributes": {"action": "update", "iid": 1, "last_commit": {"id": "sha"},
+"target": {"path_with_namespace": "hello/this/is/me/ze/project"}},
+"labels": [{"title": "e2e"}, {"title": "perf"}],
+"changes": {"labels": {"previous": [{"title": "bug"}, {"title": "perf"}], "current": [{"title": "e2e"}, {"title": "perf"}]}}}`,
+			},
+			want: &info.Event{
+				EventType:         "Merge Request",
+				TriggerTarget:     "pull_request",
+				Organization:      "hello-this-is-me-ze",
+				Repository:        "project",
+				PullRequestLabels: []string{"e2e", "perf"},
+				State:             info.State{LabelsAdded: []string{"e2e"}, LabelsRemoved: []string{"bug"}},
+			},
+		},
 		{
 			name: "push event no commits",
 			args: args{
@@ -95,6 +116,57 @@ func TestParsePayload(t *testing.T) {
 				Repository:    "project",
 			},
 		},
+		{
+			name: "system hook push event",
+			args: args{
+				event:   gitlab.EventTypeSystemHook,
+				payload: strings.Replace(sample.PushEventAsJSON(true), "{", `{"event_name": "push",`, 1),
+			},
+			want: &info.Event{
+				EventType:     "Push",
+				TriggerTarget: "push",
+				Organization:  "hello-this-is-me-ze",
+				Repository:    "project",
+			},
+		},
+		{
+			name: "system hook merge event",
+			args: args{
+				event:   gitlab.EventTypeSystemHook,
+				payload: strings.Replace(sample.MREventAsJSON(), "{", `{"object_kind": "merge_request",`, 1),
+			},
+			want: &info.Event{
+				EventType:     "Merge Request",
+				TriggerTarget: "pull_request",
+				Organization:  "hello-this-is-me-ze",
+				Repository:    "project",
+			},
+		},
+		{
+			name: "system hook event not supported",
+			args: args{
+				event:   gitlab.EventTypeSystemHook,
+				payload: `{"event_name": "user_create", "username": "foo"}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "push event deleting a branch",
+			args: args{
+				event: gitlab.EventTypePush,
+				payload: `{"ref": "refs/heads/feature", "before": "beforesha",
+"after": "0000000000000000000000000000000000000000",
+"project": {"path_with_namespace": "hello/this/is/me/ze/project"}}`,
+			},
+			want: &info.Event{
+				EventType:     "Push",
+				TriggerTarget: "push",
+				Organization:  "hello-this-is-me-ze",
+				Repository:    "project",
+				SHA:           "beforesha",
+				State:         info.State{BranchDeleted: true},
+			},
+		},
 
 		{
 			name: "note event",
@@ -188,7 +260,23 @@ func TestParsePayload(t *testing.T) {
 				if tt.want.TargetCancelPipelineRun != "" {
 					assert.Equal(t, tt.want.TargetCancelPipelineRun, got.TargetCancelPipelineRun)
 				}
+				if tt.want.SHA != "" {
+					assert.Equal(t, tt.want.SHA, got.SHA)
+				}
+				assert.Equal(t, tt.want.BranchDeleted, got.BranchDeleted)
+				if tt.want.PullRequestLabels != nil {
+					assert.DeepEqual(t, tt.want.PullRequestLabels, got.PullRequestLabels)
+				}
+				assert.DeepEqual(t, tt.want.LabelsAdded, got.LabelsAdded)
+				assert.DeepEqual(t, tt.want.LabelsRemoved, got.LabelsRemoved)
 			}
 		})
 	}
 }
+
+func TestIsPrivateProject(t *testing.T) {
+	assert.Assert(t, !isPrivateProject([]byte(`{"project": {"visibility_level": 20}}`)))
+	assert.Assert(t, isPrivateProject([]byte(`{"project": {"visibility_level": 10}}`)))
+	assert.Assert(t, isPrivateProject([]byte(`{"project": {"visibility_level": 0}}`)))
+	assert.Assert(t, !isPrivateProject([]byte(`{"project": {}}`)))
+}
@@ -2,6 +2,7 @@ package test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -44,6 +45,10 @@ func Setup(ctx context.Context, t *testing.T) (*gitlab.Client, *http.ServeMux, f
 func MuxNotePost(t *testing.T, mux *http.ServeMux, projectNumber, mrID int, catchStr string) {
 	path := fmt.Sprintf("/projects/%d/merge_requests/%d/notes", projectNumber, mrID)
 	mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprintf(rw, "[]")
+			return
+		}
 		bit, _ := io.ReadAll(r.Body)
 		s := string(bit)
 		if catchStr != "" {
@@ -53,6 +58,24 @@ func MuxNotePost(t *testing.T, mux *http.ServeMux, projectNumber, mrID int, catc
 	})
 }
 
+// MuxNoteUpdate list an existing note with the body and catch the update of
+// it
+func MuxNoteUpdate(t *testing.T, mux *http.ServeMux, projectNumber, mrID, noteID int, body, catchStr string) {
+	path := fmt.Sprintf("/projects/%d/merge_requests/%d/notes", projectNumber, mrID)
+	mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodGet, "a new note should not be created")
+		b, _ := json.Marshal([]map[string]interface{}{{"id": noteID, "body": body}})
+		fmt.Fprint(rw, string(b))
+	})
+	mux.HandleFunc(fmt.Sprintf("%s/%d", path, noteID), func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPut)
+		bit, _ := io.ReadAll(r.Body)
+		s := string(bit)
+		assert.Assert(t, strings.Contains(s, catchStr), "%s is not in %s", catchStr, s)
+		fmt.Fprintf(rw, "{}")
+	})
+}
+
 func MuxAllowUserID(t *testing.T, mux *http.ServeMux, projectID, userID int) {
 	path := fmt.Sprintf("/projects/%d/members/all/%d", projectID, userID)
 	mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
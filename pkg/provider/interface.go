@@ -4,11 +4,13 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
 	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type StatusOpts struct {
@@ -21,6 +23,27 @@ type StatusOpts struct {
 	DetailsURL              string
 	Summary                 string
 	Title                   string
+	// Annotations are attached to the lines of the files of the repository
+	// on the providers supporting it
+	Annotations []Annotation
+}
+
+// TaskStatusOpts is the status of a task of a PipelineRun.
+type TaskStatusOpts struct {
+	// Name is the name of the task in the pipeline
+	Name        string
+	Status      string
+	Conclusion  string
+	DetailsURL  string
+	StartedAt   *metav1.Time
+	CompletedAt *metav1.Time
+}
+
+// Annotation is a message about a line of a file of the repository.
+type Annotation struct {
+	Path    string
+	Line    int
+	Message string
 }
 
 type Interface interface {
@@ -29,7 +52,12 @@ type Interface interface {
 	Detect(*http.Request, string, *zap.SugaredLogger) (bool, bool, *zap.SugaredLogger, string, error)
 	ParsePayload(context.Context, *params.Run, *http.Request, string) (*info.Event, error)
 	IsAllowed(context.Context, *info.Event) (bool, error)
+	IsMaintainer(context.Context, *info.Event) (bool, error) // ctx, event with the sender to check
 	CreateStatus(context.Context, versioned.Interface, *info.Event, *info.PacOpts, StatusOpts) error
+	// CreateStatuses reports a batch of statuses of the PipelineRuns of an
+	// event, the statuses superseded by a later one of the same PipelineRun
+	// are not reported
+	CreateStatuses(context.Context, versioned.Interface, *info.Event, *info.PacOpts, []StatusOpts) error
 	GetTektonDir(context.Context, *info.Event, string) (string, error)              // ctx, event, path
 	GetFileInsideRepo(context.Context, *info.Event, string, string) (string, error) // ctx, event, path, branch
 	SetClient(context.Context, *params.Run, *info.Event) error
@@ -37,6 +65,21 @@ type Interface interface {
 	GetConfig() *info.ProviderConfig
 	GetFiles(context.Context, *info.Event) ([]string, error)
 	GetTaskURI(ctx context.Context, params *params.Run, event *info.Event, uri string) (bool, string, error)
+	IsRefProtected(context.Context, *info.Event, string) (bool, error) // ctx, event, ref
+}
+
+// AutoOkToTester checks the auto_ok_to_test conditions of a Repository for
+// the sender of an event not allowed to run the CI, the providers supporting
+// it implement it. It returns the reason the sender is approved for.
+type AutoOkToTester interface {
+	IsAutoOkToTest(context.Context, *info.Event, *v1alpha1.AutoOkToTest) (bool, string, error)
+}
+
+// TaskStatusCreator reports a status for each task of a PipelineRun beside
+// the status of the PipelineRun, the providers supporting it implement it.
+// The status of the PipelineRun the tasks belong to is passed for its names.
+type TaskStatusCreator interface {
+	CreateTaskStatuses(context.Context, *info.Event, *info.PacOpts, StatusOpts, []TaskStatusOpts) error
 }
 
 const DefaultProviderAPIUser = "git"
@@ -4,6 +4,8 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/acl"
 )
 
 var (
@@ -12,6 +14,11 @@ var (
 	oktotestRegex         = regexp.MustCompile(`(?m)^/ok-to-test\s*$`)
 	cancelAllRegex        = regexp.MustCompile(`(?m)^(/cancel)\s*$`)
 	cancelSingleRegex     = regexp.MustCompile(`(?m)^(/cancel)[ \t]+\S+`)
+	retestRegex           = regexp.MustCompile(`(?m)^/retest(\s|$)`)
+	helpRegex             = regexp.MustCompile(`(?m)^(/help|/pac[ \t]+help)\s*$`)
+	issueCommandRegex     = regexp.MustCompile(`^/([a-zA-Z0-9][a-zA-Z0-9_-]*)(?:[ \t]+([^\r\n]*))?(?:\s|$)`)
+	// paramNameRegex are the param names accepted by tekton
+	paramNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.-]*$`)
 )
 
 const (
@@ -24,6 +31,16 @@ const (
 	ProviderGitHubApp = "GitHubApp"
 )
 
+const (
+	// ChangedFilesPerPage is how many changed files are asked to the
+	// providers at once
+	ChangedFilesPerPage = 100
+	// MaxChangedFiles is the most changed files fetched for an event, the
+	// matching ignores the others so we don't page forever on huge pull
+	// requests
+	MaxChangedFiles = 10000
+)
+
 func Valid(value string, validValues []string) bool {
 	for _, v := range validValues {
 		if v == value {
@@ -45,11 +62,77 @@ func IsCancelComment(comment string) bool {
 	return cancelAllRegex.MatchString(comment) || cancelSingleRegex.MatchString(comment)
 }
 
+// IsHelpComment returns true when the comment asks with /help or /pac help
+// for the PipelineRuns and the gitops commands of the repository.
+func IsHelpComment(comment string) bool {
+	return helpRegex.MatchString(comment)
+}
+
+// CommentCommand returns the gitops command of a comment with the same
+// precedence as the events, a cancel wins over a test and a help comes last.
+// Empty when the comment has no command.
+func CommentCommand(comment string) string {
+	switch {
+	case IsCancelComment(comment):
+		return acl.CommandCancel
+	case IsTestRetestComment(comment):
+		if retestRegex.MatchString(comment) {
+			return acl.CommandRetest
+		}
+		return acl.CommandTest
+	case IsOkToTestComment(comment):
+		return acl.CommandOkToTest
+	case IsHelpComment(comment):
+		return acl.CommandHelp
+	}
+	return ""
+}
+
+// IssueCommand returns the command an issue comment starts with and its
+// arguments on the same line, i.e: deploy and staging for `/deploy staging`.
+// The command is empty when the comment doesn't start with one.
+func IssueCommand(comment string) (string, string) {
+	parts := issueCommandRegex.FindStringSubmatch(strings.TrimSpace(comment))
+	if parts == nil {
+		return "", ""
+	}
+	return parts[1], strings.TrimSpace(parts[2])
+}
+
 func GetPipelineRunFromTestComment(comment string) string {
+	for _, field := range testCommentFields(comment) {
+		if !strings.Contains(field, "=") {
+			return field
+		}
+	}
+	return ""
+}
+
+// GetParamsFromTestComment returns the key=value params following the /test
+// or /retest command of a comment, i.e: `/test e2e cluster=gke region=eu`.
+// The fields not being valid param names are ignored.
+func GetParamsFromTestComment(comment string) map[string]string {
+	params := map[string]string{}
+	for _, field := range testCommentFields(comment) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || !paramNameRegex.MatchString(key) {
+			continue
+		}
+		params[key] = value
+	}
+	if len(params) == 0 {
+		return nil
+	}
+	return params
+}
+
+// testCommentFields returns the fields following the /test or /retest command
+// of a comment, the pipelinerun name and the params.
+func testCommentFields(comment string) []string {
 	if strings.Contains(comment, testComment) {
-		return getNameFromComment(testComment, comment)
+		return strings.Fields(getNameFromComment(testComment, comment))
 	}
-	return getNameFromComment(retestComment, comment)
+	return strings.Fields(getNameFromComment(retestComment, comment))
 }
 
 func GetPipelineRunFromCancelComment(comment string) string {
@@ -3,6 +3,7 @@ package provider
 import (
 	"testing"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/acl"
 	"gotest.tools/v3/assert"
 )
 
@@ -230,6 +231,16 @@ func TestGetPipelineRunFromComment(t *testing.T) {
 			comment: "before \n /retest abc-01-pr \n after",
 			want:    "abc-01-pr",
 		},
+		{
+			name:    "test a pipeline with params",
+			comment: "/test abc-01-pr cluster=gke region=eu",
+			want:    "abc-01-pr",
+		},
+		{
+			name:    "test all pipelines with params",
+			comment: "/test cluster=gke",
+			want:    "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -240,6 +251,45 @@ func TestGetPipelineRunFromComment(t *testing.T) {
 	}
 }
 
+func TestGetParamsFromTestComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment string
+		want    map[string]string
+	}{
+		{
+			name:    "no params",
+			comment: "/test abc-01-pr",
+		},
+		{
+			name:    "params of a pipeline",
+			comment: "before \n/test e2e cluster=gke region=eu\n after=comment",
+			want:    map[string]string{"cluster": "gke", "region": "eu"},
+		},
+		{
+			name:    "params of all the pipelines",
+			comment: "/retest cluster=gke",
+			want:    map[string]string{"cluster": "gke"},
+		},
+		{
+			name:    "empty value and value with equal",
+			comment: "/test e2e debug= selector=app=web",
+			want:    map[string]string{"debug": "", "selector": "app=web"},
+		},
+		{
+			name:    "invalid param names are ignored",
+			comment: "/test e2e =gke 1region=eu cluster=gke",
+			want:    map[string]string{"cluster": "gke"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, GetParamsFromTestComment(tt.comment), tt.want)
+		})
+	}
+}
+
 func TestGetPipelineRunFromCancelComment(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -281,6 +331,53 @@ func TestGetPipelineRunFromCancelComment(t *testing.T) {
 	}
 }
 
+func TestCommentCommand(t *testing.T) {
+	tests := []struct {
+		comment string
+		want    string
+	}{
+		{comment: "/test", want: acl.CommandTest},
+		{comment: "/test abc-01-pr", want: acl.CommandTest},
+		{comment: "/retest", want: acl.CommandRetest},
+		{comment: "lgtm\n/retest abc-01-pr", want: acl.CommandRetest},
+		{comment: "/cancel", want: acl.CommandCancel},
+		{comment: "/test\n/cancel", want: acl.CommandCancel},
+		{comment: "/ok-to-test", want: acl.CommandOkToTest},
+		{comment: "/help", want: acl.CommandHelp},
+		{comment: "what can I run?\n/pac help", want: acl.CommandHelp},
+		{comment: "/help\n/test", want: acl.CommandTest},
+		{comment: "/helpme", want: ""},
+		{comment: "looks good", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.comment, func(t *testing.T) {
+			assert.Equal(t, CommentCommand(tt.comment), tt.want)
+		})
+	}
+}
+
+func TestIssueCommand(t *testing.T) {
+	tests := []struct {
+		comment string
+		command string
+		args    string
+	}{
+		{comment: "/deploy", command: "deploy"},
+		{comment: "/deploy staging region=eu \nthanks", command: "deploy", args: "staging region=eu"},
+		{comment: "  /roll-back\tv1.2", command: "roll-back", args: "v1.2"},
+		{comment: "/deploy,staging", command: ""},
+		{comment: "please /deploy", command: ""},
+		{comment: "/", command: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.comment, func(t *testing.T) {
+			command, args := IssueCommand(tt.comment)
+			assert.Equal(t, command, tt.command)
+			assert.Equal(t, args, tt.args)
+		})
+	}
+}
+
 func TestCompareHostOfURLS(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/metrics"
+)
+
+// Factory creates a provider for an event, with the metrics recorder of the
+// controller or of the watcher, nil when the metrics are not recorded.
+type Factory func(recorder *metrics.Recorder) Interface
+
+// Registration is a git provider Pipelines as Code can detect the webhooks of
+// and report the PipelineRuns to.
+type Registration struct {
+	// Name is the git-provider label of the PipelineRuns of the provider and
+	// the git_provider type of the Repositories using it
+	Name string
+	// Aliases are the other git-provider labels of the provider, ie:
+	// github-enterprise
+	Aliases []string
+	// New creates the provider
+	New Factory
+}
+
+var registry = struct {
+	sync.RWMutex
+	registrations []Registration
+	names         map[string]int
+}{names: map[string]int{}}
+
+// Register makes a git provider available to the controller and the watcher,
+// it is meant to be called from the init function of the package of the
+// provider, like the database/sql drivers. The webhooks are detected by the
+// providers in the order they have been registered, the built-in providers
+// first. It panics when the name or an alias is already registered.
+func Register(registration Registration) {
+	if registration.Name == "" || registration.New == nil {
+		panic("provider: Register needs a name and a factory")
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	names := append([]string{registration.Name}, registration.Aliases...)
+	for _, name := range names {
+		if _, ok := registry.names[name]; ok {
+			panic(fmt.Sprintf("provider: Register called twice for provider %s", name))
+		}
+	}
+	registry.registrations = append(registry.registrations, registration)
+	for _, name := range names {
+		registry.names[name] = len(registry.registrations) - 1
+	}
+}
+
+// Registrations returns the registered providers in the order they have been
+// registered.
+func Registrations() []Registration {
+	registry.RLock()
+	defer registry.RUnlock()
+	return append([]Registration{}, registry.registrations...)
+}
+
+// Lookup returns the provider registered with a name or an alias.
+func Lookup(name string) (Registration, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	i, ok := registry.names[name]
+	if !ok {
+		return Registration{}, false
+	}
+	return registry.registrations[i], true
+}
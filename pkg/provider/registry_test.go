@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/metrics"
+	"gotest.tools/v3/assert"
+)
+
+func TestRegister(t *testing.T) {
+	registration := Registration{
+		Name:    "internal-scm",
+		Aliases: []string{"internal-scm-enterprise"},
+		New:     func(*metrics.Recorder) Interface { return nil },
+	}
+	Register(registration)
+
+	for _, name := range []string{"internal-scm", "internal-scm-enterprise"} {
+		got, ok := Lookup(name)
+		assert.Assert(t, ok, name)
+		assert.Equal(t, got.Name, "internal-scm")
+	}
+	_, ok := Lookup("unknown-scm")
+	assert.Assert(t, !ok)
+
+	registrations := Registrations()
+	assert.Equal(t, registrations[len(registrations)-1].Name, "internal-scm")
+
+	assert.Assert(t, panics(func() { Register(Registration{Name: "internal-scm-enterprise", New: registration.New}) }))
+	assert.Assert(t, panics(func() { Register(Registration{Name: "no-factory"}) }))
+	_, ok = Lookup("no-factory")
+	assert.Assert(t, !ok)
+}
+
+func panics(f func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	f()
+	return false
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+)
+
+// LatestStatuses returns the last status of each PipelineRun of a batch of
+// statuses, in the order of their last update, the earlier statuses of a
+// PipelineRun have been superseded. The statuses without a PipelineRun are
+// all kept.
+func LatestStatuses(statuses []StatusOpts) []StatusOpts {
+	last := map[string]int{}
+	for i, status := range statuses {
+		if status.PipelineRunName != "" {
+			last[status.PipelineRunName] = i
+		}
+	}
+	latest := make([]StatusOpts, 0, len(statuses))
+	for i, status := range statuses {
+		if status.PipelineRunName == "" || last[status.PipelineRunName] == i {
+			latest = append(latest, status)
+		}
+	}
+	return latest
+}
+
+// CreateStatuses reports a batch of statuses of an event with one call to
+// CreateStatus for the last status of each PipelineRun, for the providers
+// without an API to report multiple statuses at once. All the statuses are
+// tried, the errors are returned together.
+func CreateStatuses(ctx context.Context, p Interface, tekton versioned.Interface, event *info.Event, opts *info.PacOpts, statuses []StatusOpts) error {
+	var errs []string
+	for _, status := range LatestStatuses(statuses) {
+		if err := p.CreateStatus(ctx, tekton, event, opts, status); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", status.PipelineRunName, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("cannot create %d of the statuses: %s", len(errs), strings.Join(errs, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLatestStatuses(t *testing.T) {
+	statuses := []StatusOpts{
+		{PipelineRunName: "pr-a", Text: "a 1/3"},
+		{PipelineRunName: "pr-b", Text: "b 1/2"},
+		{Text: "no pipelinerun"},
+		{PipelineRunName: "pr-a", Text: "a 2/3"},
+		{PipelineRunName: "pr-a", Text: "a 3/3"},
+	}
+	texts := []string{}
+	for _, status := range LatestStatuses(statuses) {
+		texts = append(texts, status.Text)
+	}
+	assert.DeepEqual(t, texts, []string{"b 1/2", "no pipelinerun", "a 3/3"})
+	assert.Equal(t, len(LatestStatuses(nil)), 0)
+}
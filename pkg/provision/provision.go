@@ -0,0 +1,148 @@
+package provision
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/generated/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// QuotaName is the name of the ResourceQuota created in the provisioned
+// namespaces
+const QuotaName = "pipelines-as-code"
+
+// Opts are the admin defined settings of the provisioned resources.
+type Opts struct {
+	// Quota is the hard limits of the ResourceQuota of the namespace
+	Quota string
+	// RepositoryTemplate is the spec of the Repository as yaml
+	RepositoryTemplate string
+}
+
+// ParseQuota parse the hard limits of a ResourceQuota from a comma separated
+// list of resource=quantity, ie: pods=10,requests.cpu=4.
+func ParseQuota(quota string) (corev1.ResourceList, error) {
+	hard := corev1.ResourceList{}
+	for _, limit := range strings.Split(quota, ",") {
+		limit = strings.TrimSpace(limit)
+		if limit == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(limit, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid quota %q, expected resource=quantity", limit)
+		}
+		quantity, err := resource.ParseQuantity(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity for quota %q: %w", limit, err)
+		}
+		hard[corev1.ResourceName(strings.TrimSpace(name))] = quantity
+	}
+	return hard, nil
+}
+
+// ParseRepositoryTemplate parse the spec of the provisioned Repositories, the
+// url is always set from the event.
+func ParseRepositoryTemplate(template string) (*v1alpha1.RepositorySpec, error) {
+	spec := &v1alpha1.RepositorySpec{}
+	if strings.TrimSpace(template) == "" {
+		return spec, nil
+	}
+	if err := yaml.UnmarshalStrict([]byte(template), spec); err != nil {
+		return nil, fmt.Errorf("cannot parse repository template: %w", err)
+	}
+	return spec, nil
+}
+
+// ManagedByLabel marks the namespaces created by Pipelines as Code, with its
+// GroupName.
+const ManagedByLabel = "app.kubernetes.io/managed-by"
+
+// Create create the namespace, its quota and the Repository for url. Each
+// step is skipped when its resource already exists so a provisioning
+// interrupted halfway, or raced by another event of the same repository, is
+// completed by the next one. An existing namespace must have been created by
+// Pipelines as Code, we don't want to provision a Repository in a namespace we
+// don't own.
+func Create(ctx context.Context, kube kubernetes.Interface, pac versioned.Interface, name, url string, opts Opts) (*v1alpha1.Repository, error) {
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid namespace name %s: %s", name, strings.Join(errs, ", "))
+	}
+	hard, err := ParseQuota(opts.Quota)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := ParseRepositoryTemplate(opts.RepositoryTemplate)
+	if err != nil {
+		return nil, err
+	}
+	spec.URL = url
+
+	if err := createNamespace(ctx, kube, name); err != nil {
+		return nil, err
+	}
+
+	if len(hard) > 0 {
+		quota := &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: QuotaName, Namespace: name},
+			Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+		}
+		if _, err := kube.CoreV1().ResourceQuotas(name).Create(ctx, quota, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("failed to create resource quota in namespace %s: %w", name, err)
+		}
+	}
+
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: name},
+		Spec:       *spec,
+	}
+	created, err := pac.PipelinesascodeV1alpha1().Repositories(name).Create(ctx, repo, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		created, err = pac.PipelinesascodeV1alpha1().Repositories(name).Get(ctx, name, metav1.GetOptions{})
+		if err == nil && created.Spec.URL != url {
+			return nil, fmt.Errorf("repository %s/%s already exists for %s, not for %s", name, name, created.Spec.URL, url)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository for %s: %w", url, err)
+	}
+	return created, nil
+}
+
+// createNamespace create the namespace, or checks the existing one has been
+// created by Pipelines as Code.
+func createNamespace(ctx context.Context, kube kubernetes.Interface, name string) error {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				ManagedByLabel: pipelinesascode.GroupName,
+			},
+		},
+	}
+	_, err := kube.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+	if err == nil {
+		return nil
+	}
+	if !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace %s: %w", name, err)
+	}
+	existing, err := kube.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get namespace %s: %w", name, err)
+	}
+	if existing.GetLabels()[ManagedByLabel] != pipelinesascode.GroupName {
+		return fmt.Errorf("namespace %s already exists and is not managed by %s, not provisioning a repository in it", name, pipelinesascode.GroupName)
+	}
+	return nil
+}
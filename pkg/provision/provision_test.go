@@ -0,0 +1,161 @@
+package provision
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestParseQuota(t *testing.T) {
+	tests := []struct {
+		name    string
+		quota   string
+		want    corev1.ResourceList
+		wantErr string
+	}{
+		{
+			name:  "empty",
+			quota: "",
+			want:  corev1.ResourceList{},
+		},
+		{
+			name:  "limits",
+			quota: "pods=10, requests.cpu=4,limits.memory=8Gi",
+			want: corev1.ResourceList{
+				corev1.ResourcePods:         resource.MustParse("10"),
+				corev1.ResourceRequestsCPU:  resource.MustParse("4"),
+				corev1.ResourceLimitsMemory: resource.MustParse("8Gi"),
+			},
+		},
+		{
+			name:    "no quantity",
+			quota:   "pods",
+			wantErr: "invalid quota \"pods\", expected resource=quantity",
+		},
+		{
+			name:    "bad quantity",
+			quota:   "pods=many",
+			wantErr: "invalid quantity for quota \"pods=many\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQuota(tt.quota)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, len(got), len(tt.want))
+			for name, quantity := range tt.want {
+				gotQuantity := got[name]
+				assert.Equal(t, gotQuantity.Cmp(quantity), 0, "quota of %s", name)
+			}
+		})
+	}
+}
+
+func TestParseRepositoryTemplate(t *testing.T) {
+	spec, err := ParseRepositoryTemplate("concurrency_limit: 2\nparams:\n  registry: quay.io/org\n")
+	assert.NilError(t, err)
+	assert.Equal(t, *spec.ConcurrencyLimit, 2)
+	assert.Equal(t, spec.Params["registry"], "quay.io/org")
+
+	_, err = ParseRepositoryTemplate("concurrency: 2\n")
+	assert.ErrorContains(t, err, "cannot parse repository template")
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name       string
+		nsName     string
+		opts       Opts
+		namespaces []*corev1.Namespace
+		wantQuota  bool
+		wantErr    string
+	}{
+		{
+			name:   "namespace and repository",
+			nsName: "lagaffe-pipelines",
+		},
+		{
+			name:      "with a quota and a template",
+			nsName:    "lagaffe-pipelines",
+			opts:      Opts{Quota: "pods=10", RepositoryTemplate: "concurrency_limit: 1\n"},
+			wantQuota: true,
+		},
+		{
+			name:   "namespace not managed by pipelines as code",
+			nsName: "lagaffe-pipelines",
+			namespaces: []*corev1.Namespace{
+				{ObjectMeta: metav1.ObjectMeta{Name: "lagaffe-pipelines"}},
+			},
+			wantErr: "namespace lagaffe-pipelines already exists and is not managed by pipelinesascode.tekton.dev",
+		},
+		{
+			name:   "namespace already provisioned",
+			nsName: "lagaffe-pipelines",
+			opts:   Opts{Quota: "pods=10"},
+			namespaces: []*corev1.Namespace{
+				{ObjectMeta: metav1.ObjectMeta{
+					Name:   "lagaffe-pipelines",
+					Labels: map[string]string{"app.kubernetes.io/managed-by": pipelinesascode.GroupName},
+				}},
+			},
+			wantQuota: true,
+		},
+		{
+			name:    "invalid namespace name",
+			nsName:  "Lagaffe_Pipelines",
+			wantErr: "invalid namespace name Lagaffe_Pipelines",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{Namespaces: tt.namespaces})
+
+			url := "https://github.com/gaston/lagaffe"
+			repo, err := Create(ctx, stdata.Kube, stdata.PipelineAsCode, tt.nsName, url, tt.opts)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, repo.GetNamespace(), tt.nsName)
+			assert.Equal(t, repo.Spec.URL, url)
+			if tt.opts.RepositoryTemplate != "" {
+				assert.Equal(t, *repo.Spec.ConcurrencyLimit, 1)
+			}
+
+			ns, err := stdata.Kube.CoreV1().Namespaces().Get(ctx, tt.nsName, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, ns.GetLabels()["app.kubernetes.io/managed-by"], pipelinesascode.GroupName)
+
+			_, err = stdata.Kube.CoreV1().ResourceQuotas(tt.nsName).Get(ctx, QuotaName, metav1.GetOptions{})
+			assert.Equal(t, err == nil, tt.wantQuota)
+		})
+	}
+}
+
+func TestCreateIdempotent(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+
+	url := "https://github.com/gaston/lagaffe"
+	opts := Opts{Quota: "pods=10"}
+	first, err := Create(ctx, stdata.Kube, stdata.PipelineAsCode, "lagaffe-pipelines", url, opts)
+	assert.NilError(t, err)
+	second, err := Create(ctx, stdata.Kube, stdata.PipelineAsCode, "lagaffe-pipelines", url, opts)
+	assert.NilError(t, err)
+	assert.Equal(t, second.GetName(), first.GetName())
+
+	_, err = Create(ctx, stdata.Kube, stdata.PipelineAsCode, "lagaffe-pipelines", "https://github.com/gaston/other", opts)
+	assert.ErrorContains(t, err, "repository lagaffe-pipelines/lagaffe-pipelines already exists for "+url)
+}
@@ -6,37 +6,70 @@ import (
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/policy"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"go.uber.org/zap"
 )
 
+// maxKeepRunsLimits return the default max-keep-runs and its upper limit from
+// the config, the RepositoryPolicies of the namespace of the repository
+// override them.
+func (r *Reconciler) maxKeepRunsLimits(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository) (int, int) {
+	defaultMax, upperLimit := r.run.Info.Pac.DefaultMaxKeepRuns, r.run.Info.Pac.MaxKeepRunsUpperLimit
+	policies, err := policy.List(ctx, r.run.Clients.PipelineAsCode)
+	if err != nil {
+		logger.Warnf("cannot list the repository policies, ignoring their max-keep-runs: %v", err)
+		return defaultMax, upperLimit
+	}
+	merged := policy.Merge(policy.ForNamespace(policies, repo.GetNamespace()))
+	if merged.DefaultMaxKeepRuns != nil {
+		defaultMax = *merged.DefaultMaxKeepRuns
+	}
+	if merged.MaxKeepRunUpperLimit != nil {
+		upperLimit = *merged.MaxKeepRunUpperLimit
+	}
+	return defaultMax, upperLimit
+}
+
 func (r *Reconciler) cleanupPipelineRuns(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, pr *v1beta1.PipelineRun) error {
-	keepMaxPipeline, ok := pr.Annotations[keys.MaxKeepRuns]
-	if ok {
-		max, err := strconv.Atoi(keepMaxPipeline)
-		if err != nil {
+	defaultMax, upperLimit := r.maxKeepRunsLimits(ctx, logger, repo)
+
+	// if annotation is not defined but default max-keep-run value is defined then use that
+	max, found := defaultMax, defaultMax > 0
+	if keepMaxPipeline, ok := pr.Annotations[keys.MaxKeepRuns]; ok {
+		var err error
+		if max, err = strconv.Atoi(keepMaxPipeline); err != nil {
 			return err
 		}
-		// if annotation value is more than max limit defined in config then use from config
-		if r.run.Info.Pac.MaxKeepRunsUpperLimit > 0 && max > r.run.Info.Pac.MaxKeepRunsUpperLimit {
-			logger.Infof("max-keep-run value in annotation (%v) is more than max-keep-run-upper-limit (%v), so using upper-limit", max, r.run.Info.Pac.MaxKeepRunsUpperLimit)
-			max = r.run.Info.Pac.MaxKeepRunsUpperLimit
-		}
-		err = r.kinteract.CleanupPipelines(ctx, logger, repo, pr, max)
-		if err != nil {
-			return err
+		found = true
+	}
+
+	// the upper limit is enforced, the PipelineRuns without a max-keep-run
+	// are cleaned up to the upper limit so pruning cannot be disabled
+	if upperLimit > 0 {
+		switch {
+		case !found:
+			logger.Infof("no max-keep-run value set, using max-keep-run-upper-limit (%v)", upperLimit)
+			max, found = upperLimit, true
+		case max > upperLimit:
+			logger.Infof("max-keep-run value (%v) is more than max-keep-run-upper-limit (%v), so using upper-limit", max, upperLimit)
+			max = upperLimit
 		}
+	}
+	if !found {
 		return nil
 	}
+	return r.kinteract.CleanupPipelines(ctx, logger, repo, pr, max)
+}
 
-	// if annotation is not defined but default max-keep-run value is defined then use that
-	if r.run.Info.Pac.DefaultMaxKeepRuns > 0 {
-		max := r.run.Info.Pac.DefaultMaxKeepRuns
-
-		err := r.kinteract.CleanupPipelines(ctx, logger, repo, pr, max)
-		if err != nil {
-			return err
-		}
+// cleanupRegistrySecret deletes the registry credentials minted for the
+// PipelineRun, they are not needed anymore once the PipelineRun is done.
+func (r *Reconciler) cleanupRegistrySecret(ctx context.Context, logger *zap.SugaredLogger, pr *v1beta1.PipelineRun) {
+	secretName := pr.GetAnnotations()[keys.RegistrySecret]
+	if secretName == "" {
+		return
+	}
+	if err := r.kinteract.DeleteSecret(ctx, logger, pr.GetNamespace(), secretName); err != nil {
+		logger.Warnf("cannot delete registry secret %s/%s: %v", pr.GetNamespace(), secretName, err)
 	}
-	return nil
 }
@@ -22,6 +22,10 @@ import (
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
+func intPtr(i int) *int {
+	return &i
+}
+
 func TestCleanupPipelineRuns(t *testing.T) {
 	ns := "namespace"
 	cleanupRepoName := "clean-me-up-before-you-go-go-go-go"
@@ -46,6 +50,7 @@ func TestCleanupPipelineRuns(t *testing.T) {
 		defaultmaxkeepruns int
 		repoNs             string
 		repoName           string
+		policies           []*v1alpha1.RepositoryPolicy
 		afterCleanup       int
 	}{
 		{
@@ -111,6 +116,101 @@ func TestCleanupPipelineRuns(t *testing.T) {
 			afterCleanup:       2,
 			defaultmaxkeepruns: 2,
 		},
+		{
+			name: "no annotation, using the upper limit from config",
+			pruns: []*v1beta1.PipelineRun{
+				tektontest.MakePRCompletion(clock, "pipeline-newest", ns,
+					v1beta1.PipelineRunReasonSuccessful.String(), cleanupLabels, 10),
+				tektontest.MakePRCompletion(clock, "pipeline-middest", ns,
+					v1beta1.PipelineRunReasonSuccessful.String(), cleanupLabels, 20),
+				tektontest.MakePRCompletion(clock, "pipeline-oldest", ns,
+					v1beta1.PipelineRunReasonSuccessful.String(), cleanupLabels, 30),
+			},
+			repoNs:       ns,
+			repoName:     cleanupRepoName,
+			currentpr:    &v1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Labels: cleanupLabels}},
+			afterCleanup: 1,
+			maxkeepruns:  1,
+		},
+		{
+			name: "no annotation, nothing set",
+			pruns: []*v1beta1.PipelineRun{
+				tektontest.MakePRCompletion(clock, "pipeline-newest", ns,
+					v1beta1.PipelineRunReasonSuccessful.String(), cleanupLabels, 10),
+				tektontest.MakePRCompletion(clock, "pipeline-middest", ns,
+					v1beta1.PipelineRunReasonSuccessful.String(), cleanupLabels, 20),
+				tektontest.MakePRCompletion(clock, "pipeline-oldest", ns,
+					v1beta1.PipelineRunReasonSuccessful.String(), cleanupLabels, 30),
+			},
+			repoNs:       ns,
+			repoName:     cleanupRepoName,
+			currentpr:    &v1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Labels: cleanupLabels}},
+			afterCleanup: 3,
+		},
+		{
+			name: "no annotation, using default from the policy of the namespace",
+			pruns: []*v1beta1.PipelineRun{
+				tektontest.MakePRCompletion(clock, "pipeline-newest", ns,
+					v1beta1.PipelineRunReasonSuccessful.String(), cleanupLabels, 10),
+				tektontest.MakePRCompletion(clock, "pipeline-middest", ns,
+					v1beta1.PipelineRunReasonSuccessful.String(), cleanupLabels, 20),
+				tektontest.MakePRCompletion(clock, "pipeline-oldest", ns,
+					v1beta1.PipelineRunReasonSuccessful.String(), cleanupLabels, 30),
+			},
+			repoNs:    ns,
+			repoName:  cleanupRepoName,
+			currentpr: &v1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Labels: cleanupLabels}},
+			policies: []*v1alpha1.RepositoryPolicy{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "namespace"},
+					Spec:       v1alpha1.RepositoryPolicySpec{Namespaces: []string{ns}, DefaultMaxKeepRuns: intPtr(1)},
+				},
+			},
+			afterCleanup:       1,
+			defaultmaxkeepruns: 2,
+		},
+		{
+			name: "annotation clamped to the upper limit of the policy of the namespace",
+			pruns: []*v1beta1.PipelineRun{
+				tektontest.MakePRCompletion(clock, "pipeline-newest", ns,
+					v1beta1.PipelineRunReasonSuccessful.String(), cleanupLabels, 10),
+				tektontest.MakePRCompletion(clock, "pipeline-middest", ns,
+					v1beta1.PipelineRunReasonSuccessful.String(), cleanupLabels, 20),
+				tektontest.MakePRCompletion(clock, "pipeline-oldest", ns,
+					v1beta1.PipelineRunReasonSuccessful.String(), cleanupLabels, 30),
+			},
+			repoNs:    ns,
+			repoName:  cleanupRepoName,
+			currentpr: &v1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Labels: cleanupLabels, Annotations: maxRunsAnno(3)}},
+			policies: []*v1alpha1.RepositoryPolicy{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "namespace"},
+					Spec:       v1alpha1.RepositoryPolicySpec{Namespaces: []string{ns}, MaxKeepRunUpperLimit: intPtr(2)},
+				},
+			},
+			afterCleanup: 2,
+		},
+		{
+			name: "policy of another namespace",
+			pruns: []*v1beta1.PipelineRun{
+				tektontest.MakePRCompletion(clock, "pipeline-newest", ns,
+					v1beta1.PipelineRunReasonSuccessful.String(), cleanupLabels, 10),
+				tektontest.MakePRCompletion(clock, "pipeline-middest", ns,
+					v1beta1.PipelineRunReasonSuccessful.String(), cleanupLabels, 20),
+				tektontest.MakePRCompletion(clock, "pipeline-oldest", ns,
+					v1beta1.PipelineRunReasonSuccessful.String(), cleanupLabels, 30),
+			},
+			repoNs:    ns,
+			repoName:  cleanupRepoName,
+			currentpr: &v1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Labels: cleanupLabels, Annotations: maxRunsAnno(3)}},
+			policies: []*v1alpha1.RepositoryPolicy{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "other"},
+					Spec:       v1alpha1.RepositoryPolicySpec{Namespaces: []string{"other"}, MaxKeepRunUpperLimit: intPtr(1)},
+				},
+			},
+			afterCleanup: 3,
+		},
 	}
 
 	for _, tt := range tests {
@@ -125,6 +225,7 @@ func TestCleanupPipelineRuns(t *testing.T) {
 
 			tdata := testclient.Data{
 				PipelineRuns: tt.pruns,
+				Policies:     tt.policies,
 				Namespaces: []*corev1.Namespace{
 					{
 						ObjectMeta: metav1.ObjectMeta{
@@ -147,6 +248,9 @@ func TestCleanupPipelineRuns(t *testing.T) {
 
 			r := &Reconciler{
 				run: &params.Run{
+					Clients: clients.Clients{
+						PipelineAsCode: stdata.PipelineAsCode,
+					},
 					Info: info.Info{
 						Pac: &info.PacOpts{
 							Settings: &settings.Settings{
@@ -6,11 +6,15 @@ import (
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cloudevents"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/generated/injection/informers/pipelinesascode/v1alpha1/repository"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/health"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/metrics"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/notification"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/sync"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	pipelineruninformer "github.com/tektoncd/pipeline/pkg/client/injection/informers/pipeline/v1beta1/pipelinerun"
@@ -18,10 +22,14 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection/sharedmain"
 	"knative.dev/pkg/kmeta"
 )
 
-func NewController() func(context.Context, configmap.Watcher) *controller.Impl {
+// NewController returns the constructor of the controller of the
+// PipelineRuns, its informers and its work queue are added to the health
+// checker of the watcher.
+func NewController(checker *health.Checker) func(context.Context, configmap.Watcher) *controller.Impl {
 	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
 		run := params.New()
 		err := run.Clients.NewClients(ctx, &run.Info)
@@ -42,6 +50,7 @@ func NewController() func(context.Context, configmap.Watcher) *controller.Impl {
 			}
 		}()
 		<-c
+		go run.WatchPACSettingsChanges(ctx)
 
 		pipelineRunInformer := pipelineruninformer.Get(ctx)
 
@@ -58,14 +67,74 @@ func NewController() func(context.Context, configmap.Watcher) *controller.Impl {
 			qm:                sync.NewQueueManager(run.Clients.Log),
 			metrics:           metrics,
 			eventEmitter:      events.NewEventEmitter(run.Clients.Kube, run.Clients.Log),
+			cloudEvents:       cloudevents.NewEmitter(run.Clients.Log),
+			notifier:          notification.NewNotifier(run.Clients.Kube, run.Clients.Log),
+			statuses:          newStatusBatcher(statusBatchWindow),
 		}
-		impl := pipelinerunreconciler.NewImpl(ctx, r, ctrlOpts())
+		controllerName := info.ControllerName()
+		impl := pipelinerunreconciler.NewImpl(ctx, r, ctrlOpts(controllerName))
+		// the concurrency queues are rebuilt and the credentials checked by
+		// the leader, all the PipelineRuns of a Repository need to be
+		// reconciled by the same replica
+		impl.Reconciler = newLeaderAware(impl.Reconciler, func() { r.promote(ctx) }, r.demote)
+		if cfg, err := sharedmain.GetLeaderElectionConfig(ctx); err == nil && cfg.Buckets > 1 {
+			run.Clients.Log.Warnf("the leader election of the watcher has %d buckets, the concurrency of the repositories needs a single one", cfg.Buckets)
+		}
+
+		pipelineRunInformer.Informer().AddEventHandler(controller.HandleAll(checkStateAndEnqueue(impl, controllerName)))
+
+		checker.AddInformer("pipelineruns", pipelineRunInformer.Informer().HasSynced)
+		checker.AddInformer("repositories", repository.Get(ctx).Informer().HasSynced)
+		checker.AddWorkQueue("pipelineruns", impl)
+		checker.AddCheck("kubernetes", health.KubernetesCheck(run.Clients.Kube))
+		checker.AddCheck("settings", run.CheckPACConfig)
+
+		return impl
+	}
+}
+
+// NewWebhookController returns the constructor of the controller of the
+// Repositories configuring their webhook on their git provider with
+// auto-configure-webhooks, its work queue is added to the health checker of
+// the watcher.
+func NewWebhookController(checker *health.Checker) func(context.Context, configmap.Watcher) *controller.Impl {
+	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+		run := params.New()
+		if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+			log.Fatal("failed to init clients : ", err)
+		}
+		kinteract, err := kubeinteraction.NewKubernetesInteraction(run)
+		if err != nil {
+			log.Fatal("failed to init kinit client : ", err)
+		}
+		go func() {
+			if err := run.WatchConfigMapChanges(ctx); err != nil {
+				log.Fatal("error from WatchConfigMapChanges from webhook reconciler : ", err)
+			}
+		}()
+		go run.WatchPACSettingsChanges(ctx)
 
-		if err := r.qm.InitQueues(ctx, run.Clients.Tekton, run.Clients.PipelineAsCode); err != nil {
-			log.Fatal("failed to init queues", err)
+		repositoryInformer := repository.Get(ctx)
+		r := &webhookReconciler{
+			run:          run,
+			kinteract:    kinteract,
+			repoLister:   repositoryInformer.Lister(),
+			eventEmitter: events.NewEventEmitter(run.Clients.Kube, run.Clients.Log),
 		}
+		r.PromoteFunc = r.promote
+		impl := controller.NewContext(ctx, r, controller.ControllerOptions{
+			WorkQueueName: "Webhooks",
+			Logger:        run.Clients.Log,
+		})
 
-		pipelineRunInformer.Informer().AddEventHandler(controller.HandleAll(checkStateAndEnqueue(impl)))
+		controllerName := info.ControllerName()
+		repositoryInformer.Informer().AddEventHandler(controller.HandleAll(func(obj interface{}) {
+			object, err := kmeta.DeletionHandlingAccessor(obj)
+			if err == nil && kubeinteraction.OwnedByController(object.GetLabels(), controllerName) {
+				impl.EnqueueKey(types.NamespacedName{Namespace: object.GetNamespace(), Name: object.GetName()})
+			}
+		}))
+		checker.AddWorkQueue("webhooks", impl)
 
 		return impl
 	}
@@ -73,25 +142,28 @@ func NewController() func(context.Context, configmap.Watcher) *controller.Impl {
 
 // enqueue only the pipelineruns which are in `started` state
 // pipelinerun will have a label `pipelinesascode.tekton.dev/state` to describe the state
-func checkStateAndEnqueue(impl *controller.Impl) func(obj interface{}) {
+// and which have been created by the controller we are watching for, the
+// other controllers installed on the cluster have their own watcher.
+func checkStateAndEnqueue(impl *controller.Impl, controllerName string) func(obj interface{}) {
 	return func(obj interface{}) {
 		object, err := kmeta.DeletionHandlingAccessor(obj)
 		if err == nil {
 			_, exist := object.GetLabels()[keys.State]
-			if exist {
+			if exist && kubeinteraction.OwnedByController(object.GetLabels(), controllerName) {
 				impl.EnqueueKey(types.NamespacedName{Namespace: object.GetNamespace(), Name: object.GetName()})
 			}
 		}
 	}
 }
 
-func ctrlOpts() func(impl *controller.Impl) controller.Options {
+func ctrlOpts(controllerName string) func(impl *controller.Impl) controller.Options {
 	return func(impl *controller.Impl) controller.Options {
 		return controller.Options{
 			FinalizerName: pipelinesascode.GroupName,
 			PromoteFilterFunc: func(obj interface{}) bool {
-				_, exist := obj.(*v1beta1.PipelineRun).GetLabels()[keys.State]
-				return exist
+				labels := obj.(*v1beta1.PipelineRun).GetLabels()
+				_, exist := labels[keys.State]
+				return exist && kubeinteraction.OwnedByController(labels, controllerName)
 			},
 		}
 	}
@@ -0,0 +1,147 @@
+package reconciler
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/credentials"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// credentialsCheckTick is how often we look for the Repositories whose
+	// credentials are due for a check
+	credentialsCheckTick = time.Minute
+	infoConfigMap        = "pipelines-as-code-info"
+	maxCredentialsUpdate = 3
+)
+
+// checkCredentialsPeriodically validates the credentials of the Repositories
+// every credentials-check-interval, the time of the last check is kept in the
+// status of the Repository so a restart of the watcher doesn't delay them.
+func (r *Reconciler) checkCredentialsPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(credentialsCheckTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if r.run.Info.Pac == nil || r.run.Info.Pac.CredentialsCheckInterval <= 0 {
+				continue
+			}
+			r.checkRepositoriesCredentials(ctx, now, r.run.Info.Pac.CredentialsCheckInterval)
+		}
+	}
+}
+
+// checkRepositoriesCredentials validates the credentials of the Repositories
+// of the controller which have not been checked for the interval.
+func (r *Reconciler) checkRepositoriesCredentials(ctx context.Context, now time.Time, interval time.Duration) {
+	repos, err := r.repoLister.List(labels.Everything())
+	if err != nil {
+		r.run.Clients.Log.Errorf("cannot list the repositories to check their credentials: %v", err)
+		return
+	}
+	controllerName := info.ControllerName()
+	controllerURL, gotControllerURL := "", false
+	for _, repo := range repos {
+		if !kubeinteraction.OwnedByController(repo.GetLabels(), controllerName) || !credentialsCheckDue(repo, now, interval) {
+			continue
+		}
+		if !gotControllerURL {
+			controllerURL, gotControllerURL = getControllerURL(ctx, r.run), true
+		}
+		r.checkRepositoryCredentials(ctx, repo, controllerURL, now)
+	}
+}
+
+func credentialsCheckDue(repo *v1alpha1.Repository, now time.Time, interval time.Duration) bool {
+	if repo.Spec.GitProvider == nil || repo.Spec.GitProvider.Secret == nil {
+		return false
+	}
+	// the webhooks of a wildcard Repository are on each of its git repositories
+	if _, ok := formatting.WildcardURL(repo.Spec.URL); ok {
+		return false
+	}
+	status := repo.CredentialsStatus
+	return status == nil || status.LastCheckTime == nil || now.Sub(status.LastCheckTime.Time) >= interval
+}
+
+// getControllerURL returns the URL of the controller the webhooks should point
+// to, from the pipelines-as-code-info ConfigMap
+func getControllerURL(ctx context.Context, run *params.Run) string {
+	cm, err := run.Clients.Kube.CoreV1().ConfigMaps(os.Getenv("SYSTEM_NAMESPACE")).Get(ctx, infoConfigMap, metav1.GetOptions{})
+	if err != nil {
+		run.Clients.Log.Debugf("cannot get the controller url from the configmap %s: %v", infoConfigMap, err)
+		return ""
+	}
+	return cm.Data["controller-url"]
+}
+
+// checkRepositoryCredentials validates the credentials of a Repository, the
+// result of each check is set as a condition of its credentials_status and
+// recorded in the repository credentials metric.
+func (r *Reconciler) checkRepositoryCredentials(ctx context.Context, repo *v1alpha1.Repository, controllerURL string, now time.Time) {
+	logger := r.run.Clients.Log.With("namespace", repo.GetNamespace(), "repository", repo.GetName())
+	conditions := credentials.Validate(ctx, r.run, r.kinteract, repo, controllerURL)
+	if conditions == nil {
+		return
+	}
+
+	for _, condition := range conditions {
+		if condition.Type == apis.ConditionReady && condition.IsFalse() {
+			logger.Warnf("the credentials of the repository are invalid: %s", condition.Message)
+		}
+		if condition.IsUnknown() {
+			continue
+		}
+		if err := r.metrics.CredentialsValidity(repo.GetNamespace(), repo.GetName(), string(condition.Type), condition.IsTrue()); err != nil {
+			logger.Errorf("cannot record the credentials metric: %v", err)
+		}
+	}
+
+	checkTime := metav1.NewTime(now)
+	for i := 0; i < maxCredentialsUpdate; i++ {
+		lastrepo, err := r.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).Get(ctx, repo.GetName(), metav1.GetOptions{})
+		if err != nil {
+			logger.Errorf("cannot get the repository to update its credentials status: %v", err)
+			return
+		}
+		lastrepo.CredentialsStatus = credentialsStatus(lastrepo.CredentialsStatus, conditions, checkTime)
+		_, err = r.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(lastrepo.GetNamespace()).Update(ctx, lastrepo, metav1.UpdateOptions{})
+		if err == nil {
+			return
+		}
+		if !errors.IsConflict(err) {
+			logger.Errorf("cannot update the credentials status of the repository: %v", err)
+			return
+		}
+	}
+	logger.Errorf("cannot update the credentials status of the repository after %d conflicts", maxCredentialsUpdate)
+}
+
+// credentialsStatus returns the new credentials status, the transition time of
+// the conditions is kept when their status has not changed.
+func credentialsStatus(previous *v1alpha1.RepositoryCredentialsStatus, conditions apis.Conditions, checkTime metav1.Time) *v1alpha1.RepositoryCredentialsStatus {
+	status := &v1alpha1.RepositoryCredentialsStatus{LastCheckTime: &checkTime}
+	for _, condition := range conditions {
+		condition.LastTransitionTime = apis.VolatileTime{Inner: checkTime}
+		if previous != nil {
+			if old := previous.GetCondition(condition.Type); old != nil && old.Status == condition.Status {
+				condition.LastTransitionTime = old.LastTransitionTime
+			}
+		}
+		status.Conditions = append(status.Conditions, condition)
+	}
+	return status
+}
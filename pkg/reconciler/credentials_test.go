@@ -0,0 +1,127 @@
+package reconciler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/metrics"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	kitesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func credentialsRepo(name, apiURL string) *v1alpha1.Repository {
+	return &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+		Spec: v1alpha1.RepositorySpec{
+			URL: "https://gitlab.company.com/owner/repo",
+			GitProvider: &v1alpha1.GitProvider{
+				Type:          "gitlab",
+				URL:           apiURL,
+				Secret:        &v1alpha1.Secret{Name: "token-secret"},
+				WebhookSecret: &v1alpha1.Secret{Name: "webhook-secret"},
+			},
+		},
+	}
+}
+
+func TestCheckRepositoriesCredentials(t *testing.T) {
+	t.Setenv("SYSTEM_NAMESPACE", "pac")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/user":
+			fmt.Fprint(w, `{"username": "owner"}`)
+		case "/api/v4/projects/owner/repo/hooks":
+			fmt.Fprint(w, `[{"id": 1, "url": "https://pac.company.com"}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	due := credentialsRepo("due", server.URL)
+	recent := credentialsRepo("recent", server.URL)
+	recent.CredentialsStatus = &v1alpha1.RepositoryCredentialsStatus{LastCheckTime: &metav1.Time{Time: now.Add(-time.Hour)}}
+	otherController := credentialsRepo("other-controller", server.URL)
+	otherController.Labels = map[string]string{keys.Controller: "other"}
+	wildcard := credentialsRepo("wildcard", server.URL)
+	wildcard.Spec.URL = "https://gitlab.company.com/owner/*"
+	githubApp := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "github-app", Namespace: "ns"},
+		Spec:       v1alpha1.RepositorySpec{URL: "https://github.com/owner/repo"},
+	}
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, informers := testclient.SeedTestData(t, ctx, testclient.Data{
+		Repositories: []*v1alpha1.Repository{due, recent, otherController, githubApp, wildcard},
+		ConfigMap: []*corev1.ConfigMap{{
+			ObjectMeta: metav1.ObjectMeta{Name: infoConfigMap, Namespace: "pac"},
+			Data:       map[string]string{"controller-url": "https://pac.company.com/"},
+		}},
+	})
+	recorder, err := metrics.NewRecorder()
+	assert.NilError(t, err)
+	observer, logs := zapobserver.New(zap.InfoLevel)
+	r := &Reconciler{
+		repoLister: informers.Repository.Lister(),
+		run: &params.Run{Clients: clients.Clients{
+			PipelineAsCode: stdata.PipelineAsCode,
+			Kube:           stdata.Kube,
+			Log:            zap.New(observer).Sugar(),
+		}},
+		kinteract: &kitesthelper.KinterfaceTest{GetSecretResult: map[string]string{"token-secret": "token"}},
+		metrics:   recorder,
+	}
+
+	r.checkRepositoriesCredentials(ctx, now, 24*time.Hour)
+
+	got, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "due", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, got.CredentialsStatus != nil)
+	assert.Equal(t, got.CredentialsStatus.LastCheckTime.Unix(), now.Unix())
+	assert.Assert(t, got.CredentialsStatus.GetCondition("TokenValid").IsTrue())
+	assert.Assert(t, got.CredentialsStatus.GetCondition("WebhookInstalled").IsTrue())
+	assert.Assert(t, got.CredentialsStatus.GetCondition("WebhookSecretValid").IsFalse())
+	assert.Assert(t, got.CredentialsStatus.GetCondition(apis.ConditionReady).IsFalse())
+	assert.Equal(t, logs.FilterMessageSnippet("the credentials of the repository are invalid").Len(), 1)
+
+	for _, name := range []string{"recent", "other-controller", "github-app", "wildcard"} {
+		got, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, name, metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Assert(t, got.CredentialsStatus == nil || got.CredentialsStatus.Conditions == nil, name)
+	}
+}
+
+func TestCredentialsStatus(t *testing.T) {
+	before := metav1.NewTime(time.Now().Add(-24 * time.Hour))
+	now := metav1.Now()
+	previous := &v1alpha1.RepositoryCredentialsStatus{
+		Status: duckv1.Status{Conditions: duckv1.Conditions{
+			{Type: "TokenValid", Status: corev1.ConditionTrue, LastTransitionTime: apis.VolatileTime{Inner: before}},
+			{Type: "WebhookInstalled", Status: corev1.ConditionTrue, LastTransitionTime: apis.VolatileTime{Inner: before}},
+		}},
+		LastCheckTime: &before,
+	}
+	status := credentialsStatus(previous, apis.Conditions{
+		{Type: "TokenValid", Status: corev1.ConditionTrue},
+		{Type: "WebhookInstalled", Status: corev1.ConditionFalse},
+	}, now)
+	assert.Equal(t, *status.LastCheckTime, now)
+	assert.Equal(t, status.GetCondition("TokenValid").LastTransitionTime.Inner, before)
+	assert.Equal(t, status.GetCondition("WebhookInstalled").LastTransitionTime.Inner, now)
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 )
 
@@ -21,3 +22,8 @@ func (r *Reconciler) emitMetrics(pr *v1beta1.PipelineRun) error {
 
 	return r.metrics.Count(gitProvider, eventType)
 }
+
+func (r *Reconciler) emitFootprintMetrics(pr *v1beta1.PipelineRun, footprint kstatus.Footprint) error {
+	return r.metrics.Footprint(pr.GetNamespace(), pr.GetLabels()[keys.Repository],
+		footprint.CPUCoreSeconds, footprint.MemoryByteSeconds)
+}
@@ -9,11 +9,9 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud"
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketserver"
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea"
+	// the git providers of the watcher
+	_ "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/builtin"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
-	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"go.uber.org/zap"
 )
@@ -26,29 +24,18 @@ func (r *Reconciler) detectProvider(ctx context.Context, logger *zap.SugaredLogg
 
 	event := buildEventFromPipelineRun(pr)
 
-	var provider provider.Interface
-	switch gitProvider {
-	case "github", "github-enterprise":
-		gh := github.New()
-		if event.InstallationID != 0 {
-			if err := gh.InitAppClient(ctx, r.run.Clients.Kube, event); err != nil {
-				return nil, nil, err
-			}
-		}
-		provider = gh
-	case "gitlab":
-		provider = &gitlab.Provider{}
-	case "bitbucket-cloud":
-		provider = &bitbucketcloud.Provider{}
-	case "bitbucket-server":
-		provider = &bitbucketserver.Provider{}
-	case "gitea":
-		provider = &gitea.Provider{}
-	default:
+	registration, ok := provider.Lookup(gitProvider)
+	if !ok {
 		return nil, nil, fmt.Errorf("failed to detect provider for pipelinerun: %s : unknown provider", pr.GetName())
 	}
-	provider.SetLogger(logger)
-	return provider, event, nil
+	p := registration.New(r.metrics)
+	if gh, ok := p.(*github.Provider); ok && event.InstallationID != 0 {
+		if err := gh.InitAppClient(ctx, r.run.Clients.Kube, event); err != nil {
+			return nil, nil, err
+		}
+	}
+	p.SetLogger(logger)
+	return p, event, nil
 }
 
 func buildEventFromPipelineRun(pr *v1beta1.PipelineRun) *info.Event {
@@ -74,6 +61,9 @@ func buildEventFromPipelineRun(pr *v1beta1.PipelineRun) *info.Event {
 	if prNumber != "" {
 		event.PullRequestNumber, _ = strconv.Atoi(prNumber)
 	}
+	if issueNumber := prLabels[keys.Issue]; issueNumber != "" {
+		event.IssueNumber, _ = strconv.Atoi(issueNumber)
+	}
 
 	// GitHub
 	if installationID, ok := prAnno[keys.InstallationID]; ok {
@@ -83,6 +73,9 @@ func buildEventFromPipelineRun(pr *v1beta1.PipelineRun) *info.Event {
 	if gheURL, ok := prAnno[keys.GHEURL]; ok {
 		event.GHEURL = gheURL
 	}
+	if appID, ok := prAnno[keys.GitHubAppID]; ok {
+		event.GitHubAppID, _ = strconv.ParseInt(appID, 10, 64)
+	}
 
 	// Gitlab
 	if projectID, ok := prAnno[keys.SourceProjectID]; ok {
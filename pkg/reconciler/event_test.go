@@ -22,10 +22,12 @@ func TestBuildEventFromPipelineRun(t *testing.T) {
 		SHAURL:            "sha-url",
 		SHATitle:          "sha-title",
 		PullRequestNumber: 1234,
+		IssueNumber:       42,
 		Organization:      "url-org",
 		Repository:        "repo",
 		InstallationID:    12345678,
 		GHEURL:            "http://ghe",
+		GitHubAppID:       678,
 		SourceProjectID:   1234,
 		TargetProjectID:   2345,
 	}
@@ -47,6 +49,7 @@ func TestBuildEventFromPipelineRun(t *testing.T) {
 						keys.Branch:        "branch",
 						keys.State:         kubeinteraction.StateStarted,
 						keys.PullRequest:   "1234",
+						keys.Issue:         "42",
 					},
 					Annotations: map[string]string{
 						keys.ShaTitle: "sha-title",
@@ -55,6 +58,7 @@ func TestBuildEventFromPipelineRun(t *testing.T) {
 						// github
 						keys.InstallationID: "12345678",
 						keys.GHEURL:         "http://ghe",
+						keys.GitHubAppID:    "678",
 
 						// gitlab
 						keys.SourceProjectID: "1234",
@@ -69,11 +73,13 @@ func TestBuildEventFromPipelineRun(t *testing.T) {
 			event := buildEventFromPipelineRun(tt.pipelineRun)
 			assert.Equal(t, event.InstallationID, tt.event.InstallationID)
 			assert.Equal(t, event.GHEURL, tt.event.GHEURL)
+			assert.Equal(t, event.GitHubAppID, tt.event.GitHubAppID)
 			assert.Equal(t, event.SHA, tt.event.SHA)
 			assert.Equal(t, event.SHATitle, tt.event.SHATitle)
 			assert.Equal(t, event.SourceProjectID, tt.event.SourceProjectID)
 			assert.Equal(t, event.TargetProjectID, tt.event.TargetProjectID)
 			assert.Equal(t, event.PullRequestNumber, tt.event.PullRequestNumber)
+			assert.Equal(t, event.IssueNumber, tt.event.IssueNumber)
 		})
 	}
 }
@@ -2,7 +2,6 @@ package reconciler
 
 import (
 	"context"
-	"strings"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
@@ -39,17 +38,10 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, pr *v1beta1.PipelineRun)
 		}
 
 		next := r.qm.RemoveFromQueue(repo, pr)
-		if next != "" {
-			key := strings.Split(next, "/")
-			pr, err := r.run.Clients.Tekton.TektonV1beta1().PipelineRuns(key[0]).Get(ctx, key[1], metav1.GetOptions{})
-			if err != nil {
-				return err
-			}
-			if err := r.updatePipelineRunToInProgress(ctx, logger, repo, pr); err != nil {
-				logger.Error("failed to update status: ", err)
-				return err
-			}
-			return nil
+		r.updateQueueStatus(ctx, logger, repo)
+		if err := r.startAcquiredPipelineRun(ctx, logger, repo, next); err != nil {
+			logger.Error("failed to update status: ", err)
+			return err
 		}
 	}
 	return nil
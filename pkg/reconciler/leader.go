@@ -0,0 +1,79 @@
+package reconciler
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/reconciler"
+)
+
+// leaderAware wraps the reconciler of the PipelineRuns to only do the work of
+// the watcher outside of the reconciliation, the rebuild of the concurrency
+// queues and the checks of the credentials, on the leader. The other replicas
+// of the watcher are on standby until they are promoted.
+type leaderAware struct {
+	controller.Reconciler
+	leaderAware reconciler.LeaderAware
+	promote     func()
+	demote      func()
+
+	mu      sync.Mutex
+	buckets int
+}
+
+func newLeaderAware(r controller.Reconciler, promote, demote func()) controller.Reconciler {
+	la, ok := r.(reconciler.LeaderAware)
+	if !ok {
+		return r
+	}
+	return &leaderAware{Reconciler: r, leaderAware: la, promote: promote, demote: demote}
+}
+
+// Promote is called for each bucket we become the leader of, the queues are
+// rebuilt before the PipelineRuns of the bucket are reconciled.
+func (l *leaderAware) Promote(b reconciler.Bucket, enq func(reconciler.Bucket, types.NamespacedName)) error {
+	l.mu.Lock()
+	l.buckets++
+	if l.buckets == 1 {
+		l.promote()
+	}
+	l.mu.Unlock()
+	return l.leaderAware.Promote(b, enq)
+}
+
+func (l *leaderAware) Demote(b reconciler.Bucket) {
+	l.leaderAware.Demote(b)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buckets--
+	if l.buckets == 0 {
+		l.demote()
+	}
+}
+
+// promote rebuilds the concurrency queues from the PipelineRuns once the
+// watcher is the leader, and starts checking the credentials of the
+// Repositories, reporting again the final statuses which failed to be reported
+// and cancelling the PipelineRuns running for longer than the max duration.
+func (r *Reconciler) promote(ctx context.Context) {
+	r.run.Clients.Log.Info("the watcher is the leader, rebuilding the concurrency queues")
+	r.qm.Reset()
+	if err := r.qm.InitQueues(ctx, r.run.Clients.Tekton, r.run.Clients.PipelineAsCode); err != nil {
+		r.run.Clients.Log.Errorf("failed to init queues: %v", err)
+	}
+	leaderCtx, cancel := context.WithCancel(ctx)
+	r.stopLeading = cancel
+	go r.checkCredentialsPeriodically(leaderCtx)
+	go r.resyncStatusesPeriodically(leaderCtx)
+	go r.watchMaxDurationPeriodically(leaderCtx)
+}
+
+func (r *Reconciler) demote() {
+	r.run.Clients.Log.Info("the watcher is not the leader anymore")
+	if r.stopLeading != nil {
+		r.stopLeading()
+		r.stopLeading = nil
+	}
+}
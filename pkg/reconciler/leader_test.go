@@ -0,0 +1,48 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/pkg/hash"
+	"knative.dev/pkg/reconciler"
+)
+
+type fakeLeaderAware struct {
+	promoted int
+	demoted  int
+}
+
+func (f *fakeLeaderAware) Reconcile(_ context.Context, _ string) error { return nil }
+
+func (f *fakeLeaderAware) Promote(_ reconciler.Bucket, _ func(reconciler.Bucket, types.NamespacedName)) error {
+	f.promoted++
+	return nil
+}
+
+func (f *fakeLeaderAware) Demote(_ reconciler.Bucket) { f.demoted++ }
+
+func TestLeaderAware(t *testing.T) {
+	inner := &fakeLeaderAware{}
+	promoted, demoted := 0, 0
+	r := newLeaderAware(inner, func() { promoted++ }, func() { demoted++ })
+	la, ok := r.(reconciler.LeaderAware)
+	assert.Assert(t, ok)
+
+	buckets := hash.NewBucketSet(sets.NewString("a", "b")).Buckets()
+	for _, b := range buckets {
+		assert.NilError(t, la.Promote(b, func(reconciler.Bucket, types.NamespacedName) {}))
+	}
+	// the work of the leader starts once for all the buckets
+	assert.Equal(t, promoted, 1)
+	assert.Equal(t, inner.promoted, 2)
+
+	la.Demote(buckets[0])
+	assert.Equal(t, demoted, 0)
+	la.Demote(buckets[1])
+	assert.Equal(t, demoted, 1)
+	assert.Equal(t, inner.demoted, 2)
+}
@@ -0,0 +1,64 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/credentials"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/notification"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+)
+
+// mirrorRepository returns a Repository for the git repository of a mirror,
+// its secret is in the namespace of the Repository.
+func mirrorRepository(repo *v1alpha1.Repository, mirror v1alpha1.Mirror) *v1alpha1.Repository {
+	gitProvider := mirror.GitProvider
+	return &v1alpha1.Repository{
+		ObjectMeta: *repo.ObjectMeta.DeepCopy(),
+		Spec: v1alpha1.RepositorySpec{
+			URL:         mirror.URL,
+			GitProvider: &gitProvider,
+		},
+	}
+}
+
+// mirrorStatus returns the commit status of the done PipelineRun on the
+// mirrors, named like its status on the git provider of the Repository.
+func (r *Reconciler) mirrorStatus(pr *v1beta1.PipelineRun) credentials.CommitStatus {
+	status := credentials.CommitStatus{
+		State:       credentials.StatusSuccess,
+		Name:        pr.GetLabels()[keys.OriginalPRName],
+		Description: fmt.Sprintf("PipelineRun %s has %s", pr.GetName(), notification.FinalStatus(pr)),
+		TargetURL:   r.detailURL(pr),
+	}
+	if notification.FinalStatus(pr) == notification.Failed {
+		status.State = credentials.StatusFailure
+	}
+	if r.run.Info.Pac != nil && r.run.Info.Pac.ApplicationName != "" {
+		status.Name = fmt.Sprintf("%s / %s", r.run.Info.Pac.ApplicationName, status.Name)
+	}
+	return status
+}
+
+// reportMirrorStatuses reports the final status of the PipelineRun on the
+// commit of the event on each mirror of the Repository. A mirror failing to
+// get it is reported as an event of the Repository, the others and the
+// PipelineRun are not affected.
+func (r *Reconciler) reportMirrorStatuses(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, event *info.Event, pr *v1beta1.PipelineRun) {
+	if len(repo.Spec.Mirrors) == 0 || event.SHA == "" {
+		return
+	}
+	status := r.mirrorStatus(pr)
+	for _, mirror := range repo.Spec.Mirrors {
+		if err := credentials.CreateCommitStatus(ctx, r.run, r.kinteract, mirrorRepository(repo, mirror), event.SHA, status); err != nil {
+			r.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryMirrorStatus",
+				fmt.Sprintf("cannot report the status of pipelinerun %s on the mirror %s: %v", pr.GetName(), mirror.URL, err))
+			continue
+		}
+		logger.Infof("reported the status of pipelinerun %s on the mirror %s", pr.GetName(), mirror.URL)
+	}
+}
@@ -0,0 +1,111 @@
+package reconciler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	kitesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestReportMirrorStatuses(t *testing.T) {
+	statuses := map[string]map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/owner/repo/statuses/abcdef":
+			body := map[string]string{}
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&body))
+			statuses["github"] = body
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v4/projects/owner/repo/statuses/abcdef":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "403 Forbidden"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Spec: v1alpha1.RepositorySpec{
+			URL: "https://gitea.company.com/owner/repo",
+			Mirrors: []v1alpha1.Mirror{
+				{
+					URL: "https://github.company.com/owner/repo",
+					GitProvider: v1alpha1.GitProvider{
+						Type:   "github",
+						URL:    server.URL + "/api/v3/",
+						Secret: &v1alpha1.Secret{Name: "github-token"},
+					},
+				},
+				{
+					URL: "https://gitlab.company.com/owner/repo",
+					GitProvider: v1alpha1.GitProvider{
+						Type:   "gitlab",
+						URL:    server.URL,
+						Secret: &v1alpha1.Secret{Name: "gitlab-token"},
+					},
+				},
+			},
+		},
+	}
+	pr := &v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pr-abcde",
+			Namespace: "ns",
+			Labels:    map[string]string{keys.OriginalPRName: "pr"},
+		},
+		Status: v1beta1.PipelineRunStatus{Status: duckv1.Status{Conditions: duckv1.Conditions{
+			{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse},
+		}}},
+	}
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	r := &Reconciler{
+		run: &params.Run{
+			Clients: clients.Clients{Kube: stdata.Kube, Log: logger, ConsoleUI: consoleui.FallBackConsole{}},
+			Info:    info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{ApplicationName: "Pipelines as Code CI"}}},
+		},
+		kinteract:    &kitesthelper.KinterfaceTest{GetSecretResult: map[string]string{"github-token": "token", "gitlab-token": "token"}},
+		eventEmitter: events.NewEventEmitter(stdata.Kube, logger),
+	}
+
+	r.reportMirrorStatuses(ctx, logger, repo, &info.Event{SHA: "abcdef"}, pr)
+
+	assert.DeepEqual(t, statuses["github"], map[string]string{
+		"state":       "failure",
+		"context":     "Pipelines as Code CI / pr",
+		"description": "PipelineRun pr-abcde has failed",
+		"target_url":  "https://dashboard.is.not.configured",
+	})
+	kevents, err := stdata.Kube.CoreV1().Events("ns").List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(kevents.Items), 1)
+	assert.Equal(t, kevents.Items[0].Reason, "RepositoryMirrorStatus")
+	assert.Assert(t, strings.Contains(kevents.Items[0].Message, "on the mirror https://gitlab.company.com/owner/repo"), kevents.Items[0].Message)
+}
@@ -0,0 +1,75 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/sort"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+)
+
+// reportProgress updates the status of a running PipelineRun with its task
+// table when the progress-updates setting is enabled. The number of started
+// and completed tasks already reported is kept in an annotation so the status
+// only gets updated when a task has started or completed, not on every
+// reconcile.
+func (r *Reconciler) reportProgress(ctx context.Context, logger *zap.SugaredLogger, pr *v1beta1.PipelineRun) error {
+	repo, err := r.repoLister.Repositories(pr.Namespace).Get(pr.GetLabels()[keys.Repository])
+	if err != nil {
+		return fmt.Errorf("reportProgress: %w", err)
+	}
+
+	p, event, err := r.detectProvider(ctx, logger, pr)
+	if err != nil {
+		logger.Error(err)
+		return nil
+	}
+
+	trStatus := kstatus.GetStatusFromTaskStatusOrFromAsking(ctx, pr, r.run)
+	progress, state, err := sort.TaskProgress(pr, trStatus, r.run, p.GetConfig())
+	if err != nil {
+		logger.Errorf("cannot render the progress of pipelinerun %s: %v", pr.GetName(), err)
+		return nil
+	}
+	if state == "" || pr.GetAnnotations()[keys.TaskProgress] == state {
+		return nil
+	}
+
+	if err := r.setProviderClient(ctx, logger, p, event, repo); err != nil {
+		return err
+	}
+	status := r.inProgressStatus(repo, pr, progress)
+	done := func(err error) {
+		if err != nil {
+			// the progress is reported again when the next task starts or
+			// completes, or with the final status
+			logger.Errorf("failed to report the progress of pipelinerun %s, continuing: %v", pr.GetName(), err)
+			return
+		}
+		if _, err := action.PatchPipelineRun(ctx, logger, "task progress", r.run.Clients.Tekton, pr, map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]string{
+					keys.TaskProgress: state,
+				},
+			},
+		}); err != nil {
+			// not fatal, the same progress gets reported again next time
+			logger.Warnf("cannot store the task progress on pipelinerun: %v", err)
+		}
+		logger.Infof("updated the progress of pipelinerun %s with %s tasks", pr.GetName(), state)
+	}
+
+	// the progress of the PipelineRuns of the event is debounced, the tasks
+	// starting or finishing at the same time are reported together
+	if r.statuses != nil {
+		r.statuses.add(ctx, statusBatchKey(repo.GetNamespace(), repo.GetName(), event), p, r.run.Clients.Tekton,
+			event, r.run.Info.Pac, status, done)
+		return nil
+	}
+	done(p.CreateStatus(ctx, r.run.Clients.Tekton, event, r.run.Info.Pac, status))
+	return nil
+}
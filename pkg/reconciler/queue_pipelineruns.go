@@ -3,16 +3,31 @@ package reconciler
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/policy"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
 )
 
+// maxQueueStatusUpdate is the number of conflicts tolerated when persisting
+// the queue of a repository
+const maxQueueStatusUpdate = 3
+
+// queuedCondition is the condition of the queue status of a repository
+// reporting if PipelineRuns are waiting for the concurrency limit
+const queuedCondition apis.ConditionType = "Queued"
+
 func (r *Reconciler) queuePipelineRun(ctx context.Context, logger *zap.SugaredLogger, pr *v1beta1.PipelineRun) error {
 	order, exist := pr.GetAnnotations()[keys.ExecutionOrder]
 	if !exist {
@@ -33,11 +48,16 @@ func (r *Reconciler) queuePipelineRun(ctx context.Context, logger *zap.SugaredLo
 		}
 		return fmt.Errorf("updateError: %w", err)
 	}
+	// the concurrency limit can be inherited from a RepositoryPolicy
+	if repo, err = policy.ApplyFromCluster(ctx, r.run.Clients.PipelineAsCode, repo); err != nil {
+		return fmt.Errorf("cannot apply repository policies: %w", err)
+	}
 
 	// if concurrency was set and later removed or changed to zero
 	// then remove pipelineRun from Queue and update pending state to running
 	if repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit == 0 {
 		_ = r.qm.RemoveFromQueue(repo, pr)
+		r.updateQueueStatus(ctx, logger, repo)
 		if err := r.updatePipelineRunToInProgress(ctx, logger, repo, pr); err != nil {
 			return fmt.Errorf("failed to update PipelineRun to in_progress: %w", err)
 		}
@@ -49,17 +69,156 @@ func (r *Reconciler) queuePipelineRun(ctx context.Context, logger *zap.SugaredLo
 	if err != nil {
 		return fmt.Errorf("failed to add to queue: %s: %w", pr.GetName(), err)
 	}
+	r.updateQueueStatus(ctx, logger, repo)
 
 	for _, prKeys := range acquired {
-		nsName := strings.Split(prKeys, "/")
-		pr, err = r.run.Clients.Tekton.TektonV1beta1().PipelineRuns(nsName[0]).Get(ctx, nsName[1], metav1.GetOptions{})
+		if err := r.startAcquiredPipelineRun(ctx, logger, repo, prKeys); err != nil {
+			return fmt.Errorf("failed to update pipelineRun to in_progress: %w", err)
+		}
+	}
+	return nil
+}
+
+// startAcquiredPipelineRun starts a PipelineRun the queue has moved to
+// running. A PipelineRun still in its freeze window gives its place back to
+// the next one, it joins the queue again once the window has ended.
+func (r *Reconciler) startAcquiredPipelineRun(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, acquired string) error {
+	for acquired != "" {
+		nsName := strings.Split(acquired, "/")
+		pr, err := r.run.Clients.Tekton.TektonV1beta1().PipelineRuns(nsName[0]).Get(ctx, nsName[1], metav1.GetOptions{})
 		if err != nil {
 			logger.Info("failed to get pr with namespace and name: ", nsName[0], nsName[1])
 			return err
 		}
-		if err := r.updatePipelineRunToInProgress(ctx, logger, repo, pr); err != nil {
-			return fmt.Errorf("failed to update pipelineRun to in_progress: %w", err)
+		if !isFrozen(pr, time.Now()) {
+			return r.updatePipelineRunToInProgress(ctx, logger, repo, pr)
 		}
+		logger.Infof("pipelinerun %s is frozen until %s, leaving its place in the queue", acquired, pr.GetAnnotations()[keys.FrozenUntil])
+		acquired = r.qm.RemoveFromQueue(repo, pr)
+		r.updateQueueStatus(ctx, logger, repo)
 	}
 	return nil
 }
+
+// isFrozen returns whether the freeze window of a PipelineRun has not ended
+// yet.
+func isFrozen(pr *v1beta1.PipelineRun, now time.Time) bool {
+	until, err := time.Parse(time.RFC3339, pr.GetAnnotations()[keys.FrozenUntil])
+	return err == nil && now.Before(until)
+}
+
+// unfreezePipelineRun wait for the end of the freeze window of a PipelineRun
+// before starting it, or queuing it when a concurrency limit is set.
+func (r *Reconciler) unfreezePipelineRun(ctx context.Context, logger *zap.SugaredLogger, pr *v1beta1.PipelineRun) error {
+	until, err := time.Parse(time.RFC3339, pr.GetAnnotations()[keys.FrozenUntil])
+	if err != nil {
+		return fmt.Errorf("cannot parse %s annotation: %w", keys.FrozenUntil, err)
+	}
+	if wait := time.Until(until); wait > 0 {
+		return controller.NewRequeueAfter(wait)
+	}
+	if _, ok := pr.GetAnnotations()[keys.ExecutionOrder]; ok {
+		return r.queuePipelineRun(ctx, logger, pr)
+	}
+
+	repoName := pr.GetLabels()[keys.Repository]
+	repo, err := r.repoLister.Repositories(pr.Namespace).Get(repoName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("updateError: %w", err)
+	}
+	logger.Infof("freeze window of pipelinerun %s/%s has ended, starting it", pr.GetNamespace(), pr.GetName())
+	if err := r.updatePipelineRunToInProgress(ctx, logger, repo, pr); err != nil {
+		return fmt.Errorf("failed to update PipelineRun to in_progress: %w", err)
+	}
+	return nil
+}
+
+// updateQueueStatus persists the concurrency queue of the repository in its
+// queue status, for the queue to be restored in the same order when the
+// watcher restarts.
+func (r *Reconciler) updateQueueStatus(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository) {
+	hasConcurrencyLimit := repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit != 0
+	if !hasConcurrencyLimit && repo.QueueStatus == nil {
+		return
+	}
+	var status *v1alpha1.RepositoryQueueStatus
+	if hasConcurrencyLimit {
+		status = r.qm.QueueStatus(repo)
+	} else if repo.Spec.MaxParallel == nil || *repo.Spec.MaxParallel <= 0 {
+		// the concurrency limit has been removed, the queue is cleared from
+		// the status and dropped, the queues of the events are kept while
+		// their max parallel still applies
+		r.qm.RemoveRepository(repo)
+	}
+	for i := 0; i < maxQueueStatusUpdate; i++ {
+		lastrepo, err := r.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).Get(ctx, repo.GetName(), metav1.GetOptions{})
+		if err != nil {
+			logger.Errorf("cannot get the repository to update its queue status: %v", err)
+			return
+		}
+		if status != nil {
+			now := metav1.Now()
+			status.LastUpdateTime = &now
+			setQueuedCondition(lastrepo.QueueStatus, status, *repo.Spec.ConcurrencyLimit, now)
+		}
+		if sameQueueStatus(lastrepo.QueueStatus, status) {
+			return
+		}
+		lastrepo.QueueStatus = status
+		_, err = r.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(lastrepo.GetNamespace()).Update(ctx, lastrepo, metav1.UpdateOptions{})
+		if err == nil {
+			return
+		}
+		if !errors.IsConflict(err) {
+			logger.Errorf("cannot update the queue status of the repository: %v", err)
+			return
+		}
+	}
+	logger.Errorf("cannot update the queue status of the repository after %d conflicts", maxQueueStatusUpdate)
+}
+
+// setQueuedCondition sets the Queued condition of the queue status, true when
+// PipelineRuns are waiting for the concurrency limit. The transition time is
+// kept when the status of the condition has not changed.
+func setQueuedCondition(previous, status *v1alpha1.RepositoryQueueStatus, limit int, now metav1.Time) {
+	condition := apis.Condition{
+		Type:               queuedCondition,
+		Status:             corev1.ConditionFalse,
+		Reason:             "NoPipelineRunWaiting",
+		Message:            fmt.Sprintf("no PipelineRun is waiting for the concurrency limit of %d", limit),
+		LastTransitionTime: apis.VolatileTime{Inner: now},
+	}
+	if len(status.Pending) > 0 {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "ConcurrencyLimitReached"
+		condition.Message = fmt.Sprintf("%d PipelineRuns are waiting for the concurrency limit of %d, %s is the next one",
+			len(status.Pending), limit, status.Pending[0].Name)
+	}
+	if previous != nil {
+		if old := previous.GetCondition(queuedCondition); old != nil && old.Status == condition.Status {
+			condition.LastTransitionTime = old.LastTransitionTime
+		}
+	}
+	status.Conditions = duckv1.Conditions{condition}
+}
+
+// sameQueueStatus returns true when the queues have the same PipelineRuns in
+// the same order with the same condition.
+func sameQueueStatus(a, b *v1alpha1.RepositoryQueueStatus) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if !reflect.DeepEqual(a.Running, b.Running) || len(a.Pending) != len(b.Pending) {
+		return false
+	}
+	for i := range a.Pending {
+		if a.Pending[i].Name != b.Pending[i].Name {
+			return false
+		}
+	}
+	ca, cb := a.GetCondition(queuedCondition), b.GetCondition(queuedCondition)
+	return ca != nil && cb != nil && ca.Status == cb.Status && ca.Message == cb.Message
+}
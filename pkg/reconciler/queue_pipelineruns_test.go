@@ -0,0 +1,156 @@
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/sync"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestUpdateQueueStatus(t *testing.T) {
+	limit := 1
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Spec:       v1alpha1.RepositorySpec{ConcurrencyLimit: &limit},
+	}
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{Repositories: []*v1alpha1.Repository{repo}})
+	logger := zap.NewNop().Sugar()
+	r := &Reconciler{
+		run: &params.Run{Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode, Log: logger}},
+		qm:  sync.NewQueueManager(logger),
+	}
+	getStatus := func() *v1alpha1.RepositoryQueueStatus {
+		got, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "repo", metav1.GetOptions{})
+		assert.NilError(t, err)
+		return got.QueueStatus
+	}
+
+	_, err := r.qm.AddListToQueue(repo, []string{"ns/first", "ns/second", "ns/third"})
+	assert.NilError(t, err)
+	r.updateQueueStatus(ctx, logger, repo)
+	status := getStatus()
+	assert.Assert(t, status != nil)
+	assert.DeepEqual(t, status.Running, []string{"ns/first"})
+	assert.Equal(t, len(status.Pending), 2)
+	for i, name := range []string{"ns/second", "ns/third"} {
+		assert.Equal(t, status.Pending[i].Name, name)
+		assert.Equal(t, status.Pending[i].Position, i+1)
+		assert.Assert(t, status.Pending[i].QueuedTime != nil)
+	}
+	assert.Assert(t, status.LastUpdateTime != nil)
+	queued := status.GetCondition(queuedCondition)
+	assert.Assert(t, queued.IsTrue())
+	assert.Equal(t, queued.Message, "2 PipelineRuns are waiting for the concurrency limit of 1, ns/second is the next one")
+
+	// the queue is not updated when it has not changed
+	r.updateQueueStatus(ctx, logger, repo)
+	assert.Equal(t, getStatus().LastUpdateTime.Time, status.LastUpdateTime.Time)
+
+	// the next PipelineRun moves up once the running one is done
+	first := &v1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "ns"}}
+	assert.Equal(t, r.qm.RemoveFromQueue(repo, first), "ns/second")
+	r.updateQueueStatus(ctx, logger, repo)
+	status = getStatus()
+	assert.DeepEqual(t, status.Running, []string{"ns/second"})
+	assert.Equal(t, len(status.Pending), 1)
+	assert.Equal(t, status.Pending[0].Name, "ns/third")
+	assert.Equal(t, status.Pending[0].Position, 1)
+	assert.Equal(t, status.GetCondition(queuedCondition).Message, "1 PipelineRuns are waiting for the concurrency limit of 1, ns/third is the next one")
+
+	// the queued condition is false once nothing waits
+	second := &v1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "ns"}}
+	third := &v1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "third", Namespace: "ns"}}
+	assert.Equal(t, r.qm.RemoveFromQueue(repo, second), "ns/third")
+	assert.Equal(t, r.qm.RemoveFromQueue(repo, third), "")
+	r.updateQueueStatus(ctx, logger, repo)
+	assert.Assert(t, getStatus().GetCondition(queuedCondition).IsFalse())
+
+	// the queue is cleared once the repository has none
+	r.qm.RemoveRepository(repo)
+	r.updateQueueStatus(ctx, logger, repo)
+	assert.Assert(t, getStatus() == nil)
+}
+
+func TestUpdateQueueStatusLimitRemoved(t *testing.T) {
+	limit := 1
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Spec:       v1alpha1.RepositorySpec{ConcurrencyLimit: &limit},
+	}
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{Repositories: []*v1alpha1.Repository{repo}})
+	logger := zap.NewNop().Sugar()
+	r := &Reconciler{
+		run: &params.Run{Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode, Log: logger}},
+		qm:  sync.NewQueueManager(logger),
+	}
+	_, err := r.qm.AddListToQueue(repo, []string{"ns/first", "ns/second"})
+	assert.NilError(t, err)
+	r.updateQueueStatus(ctx, logger, repo)
+
+	// the status is cleared and the queue dropped once the limit is removed
+	updated, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "repo", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, updated.QueueStatus != nil)
+	updated.Spec.ConcurrencyLimit = nil
+	r.updateQueueStatus(ctx, logger, updated)
+	got, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "repo", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, got.QueueStatus == nil)
+	assert.Equal(t, len(r.qm.QueuedPipelineRuns(repo)), 0)
+	assert.Equal(t, len(r.qm.RunningPipelineRuns(repo)), 0)
+}
+
+func TestStartAcquiredPipelineRunFrozen(t *testing.T) {
+	limit := 1
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Spec:       v1alpha1.RepositorySpec{ConcurrencyLimit: &limit},
+	}
+	frozen := func(name string) *v1beta1.PipelineRun {
+		return &v1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "ns",
+			Annotations: map[string]string{keys.FrozenUntil: time.Now().Add(time.Hour).Format(time.RFC3339)},
+		}}
+	}
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Repositories: []*v1alpha1.Repository{repo},
+		PipelineRuns: []*v1beta1.PipelineRun{frozen("first"), frozen("second")},
+	})
+	logger := zap.NewNop().Sugar()
+	r := &Reconciler{
+		run: &params.Run{Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode, Tekton: stdata.Pipeline, Log: logger}},
+		qm:  sync.NewQueueManager(logger),
+	}
+
+	// the frozen PipelineRuns give their place back instead of starting
+	acquired, err := r.qm.AddListToQueue(repo, []string{"ns/first", "ns/second"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, acquired, []string{"ns/first"})
+	assert.NilError(t, r.startAcquiredPipelineRun(ctx, logger, repo, acquired[0]))
+	assert.Equal(t, len(r.qm.RunningPipelineRuns(repo)), 0)
+	assert.Equal(t, len(r.qm.QueuedPipelineRuns(repo)), 0)
+}
+
+func TestIsFrozen(t *testing.T) {
+	now := time.Now()
+	pr := func(until string) *v1beta1.PipelineRun {
+		return &v1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{keys.FrozenUntil: until}}}
+	}
+	assert.Assert(t, isFrozen(pr(now.Add(time.Hour).Format(time.RFC3339)), now))
+	assert.Assert(t, !isFrozen(pr(now.Add(-time.Hour).Format(time.RFC3339)), now))
+	assert.Assert(t, !isFrozen(&v1beta1.PipelineRun{}, now))
+}
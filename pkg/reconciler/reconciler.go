@@ -3,15 +3,16 @@ package reconciler
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cloudevents"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
 	pipelinesascode "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/listers/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/metrics"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/notification"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
@@ -22,7 +23,6 @@ import (
 	pipelinerunreconciler "github.com/tektoncd/pipeline/pkg/client/injection/reconciler/pipeline/v1beta1/pipelinerun"
 	v1beta12 "github.com/tektoncd/pipeline/pkg/client/listers/pipeline/v1beta1"
 	"go.uber.org/zap"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 )
@@ -35,6 +35,12 @@ type Reconciler struct {
 	qm                *sync.QueueManager
 	metrics           *metrics.Recorder
 	eventEmitter      *events.EventEmitter
+	cloudEvents       *cloudevents.Emitter
+	notifier          *notification.Notifier
+	// statuses batches the progress statuses reported to the providers
+	statuses *statusBatcher
+	// stopLeading stops the work done by the leader
+	stopLeading context.CancelFunc
 }
 
 var (
@@ -61,10 +67,22 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, pr *v1beta1.PipelineRun)
 	// queue pipelines which are in queued state and pending status
 	// if status is not pending, it could be canceled so let it be reported, even if state is queued
 	if state == kubeinteraction.StateQueued && pr.Spec.Status == v1beta1.PipelineRunSpecStatusPending {
+		if _, frozen := pr.GetAnnotations()[keys.FrozenUntil]; frozen {
+			return r.unfreezePipelineRun(ctx, logger, pr)
+		}
 		return r.queuePipelineRun(ctx, logger, pr)
 	}
 
 	if !pr.IsDone() {
+		if state != kubeinteraction.StateStarted || r.run.Info.Pac == nil {
+			return nil
+		}
+		if err := r.reportRunningTaskStatuses(ctx, logger, pr); err != nil {
+			return err
+		}
+		if r.run.Info.Pac.ProgressUpdates {
+			return r.reportProgress(ctx, logger, pr)
+		}
 		return nil
 	}
 
@@ -91,6 +109,8 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, pr *v1beta1.PipelineRun)
 }
 
 func (r *Reconciler) reportFinalStatus(ctx context.Context, logger *zap.SugaredLogger, event *info.Event, pr *v1beta1.PipelineRun, provider provider.Interface) (*v1alpha1.Repository, error) {
+	r.cleanupRegistrySecret(ctx, logger, pr)
+
 	repoName := pr.GetLabels()[keys.Repository]
 	repo, err := r.repoLister.Repositories(pr.Namespace).Get(repoName)
 	if err != nil {
@@ -98,7 +118,7 @@ func (r *Reconciler) reportFinalStatus(ctx context.Context, logger *zap.SugaredL
 	}
 
 	if event.InstallationID > 0 {
-		event.Provider.WebhookSecret, _ = pipelineascode.GetCurrentNSWebhookSecret(ctx, r.kinteract)
+		event.Provider.WebhookSecret, _ = pipelineascode.GetGitHubAppWebhookSecret(ctx, r.run, r.kinteract, event)
 	} else {
 		if err := pipelineascode.SecretFromRepository(ctx, r.run, r.kinteract, provider.GetConfig(), event, repo, logger); err != nil {
 			return repo, fmt.Errorf("cannot get secret from repository: %w", err)
@@ -115,19 +135,26 @@ func (r *Reconciler) reportFinalStatus(ctx context.Context, logger *zap.SugaredL
 	}
 
 	finalState := kubeinteraction.StateCompleted
-	newPr, err := r.postFinalStatus(ctx, logger, provider, event, pr)
+	newPr, failures, err := r.postFinalStatus(ctx, logger, provider, event, pr)
 	if err != nil {
 		logger.Errorf("failed to post final status, moving on: %v", err)
 		finalState = kubeinteraction.StateFailed
+	} else {
+		r.reportFinalTaskStatuses(ctx, logger, provider, event, repo, newPr)
 	}
 
-	if err := r.updateRepoRunStatus(ctx, logger, newPr, repo, event); err != nil {
+	if err := r.updateRepoRunStatus(ctx, logger, newPr, repo, event, failures); err != nil {
 		return repo, fmt.Errorf("cannot update run status: %w", err)
 	}
 
 	if _, err := r.updatePipelineRunState(ctx, logger, pr, finalState); err != nil {
 		return repo, fmt.Errorf("cannot update state: %w", err)
 	}
+	r.cloudEvents.Emit(ctx, r.run.Info.Pac.CloudEventsSinkURL, cloudevents.FinalType(pr), repo, pr)
+	r.notifier.Notify(ctx, notification.FinalStatus(pr), repo, pr)
+	if newPr != nil {
+		r.reportMirrorStatuses(ctx, logger, repo, event, newPr)
+	}
 
 	if err := r.emitMetrics(pr); err != nil {
 		logger.Error("failed to emit metrics: ", err)
@@ -135,18 +162,10 @@ func (r *Reconciler) reportFinalStatus(ctx context.Context, logger *zap.SugaredL
 
 	// remove pipelineRun from Queue and start the next one
 	next := r.qm.RemoveFromQueue(repo, pr)
-	if next != "" {
-		key := strings.Split(next, "/")
-		pr, err := r.run.Clients.Tekton.TektonV1beta1().PipelineRuns(key[0]).Get(ctx, key[1], metav1.GetOptions{})
-		if err != nil {
-			return repo, fmt.Errorf("cannot get pipeline: %w", err)
-		}
-		if err := r.updatePipelineRunToInProgress(ctx, logger, repo, pr); err != nil {
-			return repo, fmt.Errorf("failed to update status: %w", err)
-		}
-		return repo, nil
+	r.updateQueueStatus(ctx, logger, repo)
+	if err := r.startAcquiredPipelineRun(ctx, logger, repo, next); err != nil {
+		return repo, fmt.Errorf("failed to update status: %w", err)
 	}
-
 	return repo, nil
 }
 
@@ -155,26 +174,51 @@ func (r *Reconciler) updatePipelineRunToInProgress(ctx context.Context, logger *
 	if err != nil {
 		return fmt.Errorf("cannot update state: %w", err)
 	}
+	r.cloudEvents.Emit(ctx, r.run.Info.Pac.CloudEventsSinkURL, cloudevents.PipelineRunStarted, repo, pr)
+	r.notifier.Notify(ctx, notification.Started, repo, pr)
 
 	p, event, err := r.detectProvider(ctx, logger, pr)
 	if err != nil {
 		logger.Error(err)
 		return nil
 	}
+	if err := r.setProviderClient(ctx, logger, p, event, repo); err != nil {
+		return err
+	}
+
+	status := r.inProgressStatus(repo, pr, "")
+
+	if err := createStatusWithRetry(ctx, logger, r.run.Clients.Tekton, p, event, r.run.Info.Pac, status); err != nil {
+		// if failed to report status for running state, let the pipelineRun continue,
+		// pipelineRun is already started so we will try again once it completes
+		logger.Errorf("failed to report status to running on provider continuing! error: %v", err)
+		return nil
+	}
+
+	logger.Info("updated in_progress status on provider platform for pipelineRun ", pr.GetName())
+	return nil
+}
 
+// setProviderClient sets the client of the provider detected for a
+// PipelineRun with the webhook secret of the GitHub App or of the Repository.
+func (r *Reconciler) setProviderClient(ctx context.Context, logger *zap.SugaredLogger, p provider.Interface, event *info.Event, repo *v1alpha1.Repository) error {
 	if event.InstallationID > 0 {
-		event.Provider.WebhookSecret, _ = pipelineascode.GetCurrentNSWebhookSecret(ctx, r.kinteract)
+		event.Provider.WebhookSecret, _ = pipelineascode.GetGitHubAppWebhookSecret(ctx, r.run, r.kinteract, event)
 	} else {
 		if err := pipelineascode.SecretFromRepository(ctx, r.run, r.kinteract, p.GetConfig(), event, repo, logger); err != nil {
 			return fmt.Errorf("cannot get secret from repo: %w", err)
 		}
 	}
 
-	err = p.SetClient(ctx, r.run, event)
-	if err != nil {
+	if err := p.SetClient(ctx, r.run, event); err != nil {
 		return fmt.Errorf("cannot set client: %w", err)
 	}
+	return nil
+}
 
+// inProgressStatus returns the status of a running PipelineRun, the progress
+// of its tasks is added after the starting message.
+func (r *Reconciler) inProgressStatus(repo *v1alpha1.Repository, pr *v1beta1.PipelineRun, progress string) provider.StatusOpts {
 	consoleURL := r.run.Clients.ConsoleUI.DetailURL(repo.GetNamespace(), pr.GetName())
 	msg := fmt.Sprintf(params.StartingPipelineRunText,
 		pr.GetName(), repo.GetNamespace(),
@@ -182,25 +226,15 @@ func (r *Reconciler) updatePipelineRunToInProgress(ctx context.Context, logger *
 		settings.TknBinaryName,
 		pr.GetNamespace(),
 		pr.GetName())
-	status := provider.StatusOpts{
+	return provider.StatusOpts{
 		Status:                  "in_progress",
 		Conclusion:              "pending",
-		Text:                    msg,
+		Text:                    msg + progress,
 		DetailsURL:              consoleURL,
 		PipelineRunName:         pr.GetName(),
 		PipelineRun:             pr,
 		OriginalPipelineRunName: pr.GetLabels()[keys.OriginalPRName],
 	}
-
-	if err := createStatusWithRetry(ctx, logger, r.run.Clients.Tekton, p, event, r.run.Info.Pac, status); err != nil {
-		// if failed to report status for running state, let the pipelineRun continue,
-		// pipelineRun is already started so we will try again once it completes
-		logger.Errorf("failed to report status to running on provider continuing! error: %v", err)
-		return nil
-	}
-
-	logger.Info("updated in_progress status on provider platform for pipelineRun ", pr.GetName())
-	return nil
 }
 
 func (r *Reconciler) updatePipelineRunState(ctx context.Context, logger *zap.SugaredLogger, pr *v1beta1.PipelineRun, state string) (*v1beta1.PipelineRun, error) {
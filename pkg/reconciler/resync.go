@@ -0,0 +1,129 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+const (
+	// statusResyncTick is how often the PipelineRuns whose final status has
+	// not been reported to the git provider are looked for, a done
+	// PipelineRun still started for longer has been missed by the reconciler
+	statusResyncTick = 5 * time.Minute
+	// statusResyncMaxAge is the time after which the final status of a
+	// PipelineRun is not reported anymore
+	statusResyncMaxAge = 24 * time.Hour
+	// maxStatusResyncAttempts is the number of times the final status of a
+	// PipelineRun is reported again before giving up
+	maxStatusResyncAttempts = 5
+)
+
+// resyncStatusesPeriodically reports again the final status of the
+// PipelineRuns it has failed to be reported for, so the check runs and the
+// commit statuses on the git provider don't stay in progress.
+func (r *Reconciler) resyncStatusesPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(statusResyncTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.resyncStatuses(ctx, now)
+		}
+	}
+}
+
+// resyncStatuses reports again the final status of the done PipelineRuns of
+// the controller whose final status has failed to be reported, or has not been
+// reported at all.
+func (r *Reconciler) resyncStatuses(ctx context.Context, now time.Time) {
+	requirement, err := labels.NewRequirement(keys.State, selection.In, []string{kubeinteraction.StateStarted, kubeinteraction.StateFailed})
+	if err != nil {
+		r.run.Clients.Log.Errorf("cannot select the pipelineruns to report their status again: %v", err)
+		return
+	}
+	prs, err := r.pipelineRunLister.List(labels.NewSelector().Add(*requirement))
+	if err != nil {
+		r.run.Clients.Log.Errorf("cannot list the pipelineruns to report their status again: %v", err)
+		return
+	}
+	controllerName := info.ControllerName()
+	for _, pr := range prs {
+		if !kubeinteraction.OwnedByController(pr.GetLabels(), controllerName) || !statusResyncDue(pr, now) {
+			continue
+		}
+		logger := r.run.Clients.Log.With("pipeline-run", pr.GetName(), "namespace", pr.GetNamespace())
+		if err := r.resyncStatus(ctx, logger, pr.DeepCopy()); err != nil {
+			logger.Errorf("cannot report the final status of the pipelinerun again: %v", err)
+		}
+	}
+}
+
+// statusResyncDue returns true when the final status of a done PipelineRun
+// has to be reported again, a started PipelineRun is left to the reconciler
+// for a tick after it is done.
+func statusResyncDue(pr *v1beta1.PipelineRun, now time.Time) bool {
+	if !pr.IsDone() || pr.Status.CompletionTime == nil || now.Sub(pr.Status.CompletionTime.Time) > statusResyncMaxAge {
+		return false
+	}
+	if pr.GetLabels()[keys.State] == kubeinteraction.StateStarted && now.Sub(pr.Status.CompletionTime.Time) < statusResyncTick {
+		return false
+	}
+	return statusResyncAttempts(pr) < maxStatusResyncAttempts
+}
+
+func statusResyncAttempts(pr *v1beta1.PipelineRun) int {
+	attempts, _ := strconv.Atoi(pr.GetAnnotations()[keys.StatusResyncAttempts])
+	return attempts
+}
+
+// resyncStatus reports the final status of a PipelineRun again, the number of
+// attempts is kept on the PipelineRun.
+func (r *Reconciler) resyncStatus(ctx context.Context, logger *zap.SugaredLogger, pr *v1beta1.PipelineRun) error {
+	attempts := statusResyncAttempts(pr) + 1
+	mergePatch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				keys.StatusResyncAttempts: strconv.Itoa(attempts),
+			},
+		},
+	}
+	pr, err := action.PatchPipelineRun(ctx, logger, "status resync attempts", r.run.Clients.Tekton, pr, mergePatch)
+	if err != nil {
+		return err
+	}
+
+	p, event, err := r.detectProvider(ctx, logger, pr)
+	if err != nil {
+		return err
+	}
+	repo, err := r.repoLister.Repositories(pr.GetNamespace()).Get(pr.GetLabels()[keys.Repository])
+	if err != nil {
+		return fmt.Errorf("cannot get the repository: %w", err)
+	}
+	if err := r.setProviderClient(ctx, logger, p, event, repo); err != nil {
+		return err
+	}
+	if _, _, err := r.postFinalStatus(ctx, logger, p, event, pr); err != nil {
+		if attempts >= maxStatusResyncAttempts {
+			r.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryStatusResync",
+				fmt.Sprintf("cannot report the final status of the pipelinerun %s after %d attempts, giving up: %v", pr.GetName(), attempts, err))
+		}
+		return err
+	}
+	logger.Infof("reported the final status of the pipelinerun %s/%s again after %d attempts", pr.GetNamespace(), pr.GetName(), attempts)
+	_, err = r.updatePipelineRunState(ctx, logger, pr, kubeinteraction.StateCompleted)
+	return err
+}
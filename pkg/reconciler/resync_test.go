@@ -0,0 +1,61 @@
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestStatusResyncDue(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	newPipelineRun := func(state string, done bool, completed time.Duration, attempts string) *v1beta1.PipelineRun {
+		pr := &v1beta1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pr",
+				Namespace:   "ns",
+				Labels:      map[string]string{keys.State: state},
+				Annotations: map[string]string{},
+			},
+		}
+		if attempts != "" {
+			pr.Annotations[keys.StatusResyncAttempts] = attempts
+		}
+		if done {
+			completionTime := metav1.NewTime(now.Add(-completed))
+			pr.Status = v1beta1.PipelineRunStatus{
+				Status: duckv1.Status{Conditions: duckv1.Conditions{
+					{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue},
+				}},
+				PipelineRunStatusFields: v1beta1.PipelineRunStatusFields{CompletionTime: &completionTime},
+			}
+		}
+		return pr
+	}
+
+	tests := []struct {
+		name string
+		pr   *v1beta1.PipelineRun
+		want bool
+	}{
+		{name: "failed to be reported", pr: newPipelineRun(kubeinteraction.StateFailed, true, time.Minute, ""), want: true},
+		{name: "missed by the reconciler", pr: newPipelineRun(kubeinteraction.StateStarted, true, time.Hour, ""), want: true},
+		{name: "just done", pr: newPipelineRun(kubeinteraction.StateStarted, true, time.Minute, "")},
+		{name: "running", pr: newPipelineRun(kubeinteraction.StateStarted, false, 0, "")},
+		{name: "too old", pr: newPipelineRun(kubeinteraction.StateFailed, true, 48*time.Hour, "")},
+		{name: "retried", pr: newPipelineRun(kubeinteraction.StateFailed, true, time.Hour, "2"), want: true},
+		{name: "given up", pr: newPipelineRun(kubeinteraction.StateFailed, true, time.Hour, "5")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, statusResyncDue(tt.pr, now), tt.want)
+		})
+	}
+}
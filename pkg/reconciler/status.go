@@ -3,12 +3,14 @@ package reconciler
 import (
 	"context"
 	"fmt"
+	"html"
 	"strings"
 	"time"
 
 	"github.com/google/go-github/v49/github"
 	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	pacv1a1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
@@ -26,6 +28,11 @@ const (
 	maxPipelineRunStatusRun = 5
 	logSnippetNumLines      = 3
 	failureReasonText       = "%s<br><h4>Failure reason</h4><br>%s"
+	artifactsText           = "%s<br><h4>Artifacts</h4><br><table><tr><th>Name</th><th>Digest</th></tr>%s</table>"
+	timedOutText            = "%s<br><h4>Timeout</h4><br>The PipelineRun has timed out, its timeouts were %s."
+	maxDurationText         = "%s<br><h4>Timeout</h4><br>The PipelineRun has been cancelled after running for longer than the maximum duration of <b>%s</b>."
+	artifactRowText         = "<tr><td><a href=\"%s\">%s</a></td><td><code>%s</code></td></tr>"
+	footprintText           = "%s<br><h4>Resource footprint</h4><br>Estimated from the requests of the pods of %d tasks: <b>%.2f</b> CPU core-minutes and <b>%.2f</b> GiB-minutes of memory."
 )
 
 var backoffSchedule = []time.Duration{
@@ -34,7 +41,7 @@ var backoffSchedule = []time.Duration{
 	5 * time.Second,
 }
 
-func (r *Reconciler) updateRepoRunStatus(ctx context.Context, logger *zap.SugaredLogger, pr *tektonv1beta1.PipelineRun, repo *pacv1a1.Repository, event *info.Event) error {
+func (r *Reconciler) updateRepoRunStatus(ctx context.Context, logger *zap.SugaredLogger, pr *tektonv1beta1.PipelineRun, repo *pacv1a1.Repository, event *info.Event, failures map[string]pacv1a1.TaskInfos) error {
 	refsanitized := formatting.SanitizeBranch(event.BaseBranch)
 	repoStatus := pacv1a1.RepositoryRunStatus{
 		Status:          pr.Status.Status,
@@ -44,9 +51,11 @@ func (r *Reconciler) updateRepoRunStatus(ctx context.Context, logger *zap.Sugare
 		SHA:             &event.SHA,
 		SHAURL:          &event.SHAURL,
 		Title:           &event.SHATitle,
-		LogURL:          github.String(r.run.Clients.ConsoleUI.DetailURL(pr.GetNamespace(), pr.GetName())),
+		LogURL:          github.String(r.detailURL(pr)),
 		EventType:       &event.EventType,
 		TargetBranch:    &refsanitized,
+		TaskResults:     r.collectTaskResults(ctx, pr, failures),
+		TektonResults:   tektonResultsRecord(pr),
 	}
 
 	// Get repository again in case it was updated while we were running the CI
@@ -80,16 +89,44 @@ func (r *Reconciler) updateRepoRunStatus(ctx context.Context, logger *zap.Sugare
 	return fmt.Errorf("cannot update %s", repo.Name)
 }
 
-func (r *Reconciler) getFailureSnippet(ctx context.Context, pr *tektonv1beta1.PipelineRun) string {
+// collectFailures collects the log snippets of the failed tasks of the
+// PipelineRun with their secrets hidden, when the error log snippet setting
+// is enabled. They are shown in the final status and in the Repository one.
+func (r *Reconciler) collectFailures(ctx context.Context, pr *tektonv1beta1.PipelineRun) map[string]pacv1a1.TaskInfos {
+	failures := map[string]pacv1a1.TaskInfos{}
+	if r.run.Info.Pac == nil || !r.run.Info.Pac.ErrorLogSnippet {
+		return failures
+	}
 	intf, err := kubeinteraction.NewKubernetesInteraction(r.run)
 	if err != nil {
-		return ""
+		return failures
+	}
+	failures = kstatus.CollectFailedTasksLogSnippet(ctx, r.run, intf, pr, logSnippetNumLines)
+	if len(failures) == 0 {
+		return failures
 	}
-	taskinfos := kstatus.CollectFailedTasksLogSnippet(ctx, r.run, intf, pr, logSnippetNumLines)
-	if len(taskinfos) == 0 {
+	secretValues := secrets.GetSecretsAttachedToPipelineRun(ctx, r.kinteract, pr)
+	for name, failure := range failures {
+		failure.LogSnippet = secrets.ReplaceSecretsInText(failure.LogSnippet, secretValues)
+		failure.Message = secrets.ReplaceSecretsInText(failure.Message, secretValues)
+		failures[name] = failure
+	}
+	return failures
+}
+
+// collectTaskResults collects the result of each task of the PipelineRun for
+// the Repository status, with the failure snippets collected for its final
+// status.
+func (r *Reconciler) collectTaskResults(ctx context.Context, pr *tektonv1beta1.PipelineRun, failures map[string]pacv1a1.TaskInfos) []pacv1a1.TaskResult {
+	trStatus := kstatus.GetStatusFromTaskStatusOrFromAsking(ctx, pr, r.run)
+	return kstatus.CollectTaskResults(r.run, pr, trStatus, failures)
+}
+
+func failureSnippet(failures map[string]pacv1a1.TaskInfos) string {
+	if len(failures) == 0 {
 		return ""
 	}
-	sortedTaskInfos := sort.TaskInfos(taskinfos)
+	sortedTaskInfos := sort.TaskInfos(failures)
 	text := strings.TrimSpace(sortedTaskInfos[0].LogSnippet)
 	if text == "" {
 		text = sortedTaskInfos[0].Message
@@ -97,26 +134,40 @@ func (r *Reconciler) getFailureSnippet(ctx context.Context, pr *tektonv1beta1.Pi
 	return fmt.Sprintf("task <b>%s</b> has the status <b>\"%s\"</b>:\n<pre>%s</pre>", sortedTaskInfos[0].Name, sortedTaskInfos[0].Reason, text)
 }
 
-func (r *Reconciler) postFinalStatus(ctx context.Context, logger *zap.SugaredLogger, vcx provider.Interface, event *info.Event, createdPR *tektonv1beta1.PipelineRun) (*tektonv1beta1.PipelineRun, error) {
+func (r *Reconciler) postFinalStatus(ctx context.Context, logger *zap.SugaredLogger, vcx provider.Interface, event *info.Event, createdPR *tektonv1beta1.PipelineRun) (*tektonv1beta1.PipelineRun, map[string]pacv1a1.TaskInfos, error) {
 	pr, err := r.run.Clients.Tekton.TektonV1beta1().PipelineRuns(createdPR.GetNamespace()).Get(
 		ctx, createdPR.GetName(), metav1.GetOptions{},
 	)
 	if err != nil {
-		return pr, err
+		return pr, nil, err
 	}
 
 	trStatus := kstatus.GetStatusFromTaskStatusOrFromAsking(ctx, pr, r.run)
 	taskStatusText, err := sort.TaskStatusTmpl(pr, trStatus, r.run, vcx.GetConfig())
 	if err != nil {
-		return pr, err
+		return pr, nil, err
 	}
 
-	if r.run.Info.Pac.ErrorLogSnippet {
-		failures := r.getFailureSnippet(ctx, pr)
-		if failures != "" {
-			secretValues := secrets.GetSecretsAttachedToPipelineRun(ctx, r.kinteract, pr)
-			failures = secrets.ReplaceSecretsInText(failures, secretValues)
-			taskStatusText = fmt.Sprintf(failureReasonText, taskStatusText, failures)
+	failures := r.collectFailures(ctx, pr)
+	if snippet := failureSnippet(failures); snippet != "" {
+		taskStatusText = fmt.Sprintf(failureReasonText, taskStatusText, snippet)
+	}
+
+	if hasTimedOut(pr) {
+		taskStatusText = fmt.Sprintf(timedOutText, taskStatusText, timeoutsDescription(ctx, pr))
+	} else if maxDuration, ok := pr.GetAnnotations()[apipac.MaxDurationExceeded]; ok {
+		taskStatusText = fmt.Sprintf(maxDurationText, taskStatusText, maxDuration)
+	}
+
+	if artifacts := kstatus.CollectArtifacts(trStatus); len(artifacts) > 0 {
+		taskStatusText = fmt.Sprintf(artifactsText, taskStatusText, artifactsRows(artifacts))
+	}
+
+	if footprint := kstatus.CollectFootprint(ctx, r.run, pr, trStatus); !footprint.IsZero() {
+		taskStatusText = fmt.Sprintf(footprintText, taskStatusText, footprint.Tasks,
+			footprint.CPUCoreSeconds/60, footprint.MemoryByteSeconds/60/(1<<30))
+		if err := r.emitFootprintMetrics(pr, footprint); err != nil {
+			logger.Error("failed to emit footprint metrics: ", err)
 		}
 	}
 
@@ -126,13 +177,68 @@ func (r *Reconciler) postFinalStatus(ctx context.Context, logger *zap.SugaredLog
 		Conclusion:              formatting.PipelineRunStatus(pr),
 		Text:                    taskStatusText,
 		PipelineRunName:         pr.Name,
-		DetailsURL:              r.run.Clients.ConsoleUI.DetailURL(pr.GetNamespace(), pr.GetName()),
+		DetailsURL:              r.detailURL(pr),
 		OriginalPipelineRunName: pr.GetLabels()[apipac.OriginalPRName],
 	}
 
 	err = createStatusWithRetry(ctx, logger, r.run.Clients.Tekton, vcx, event, r.run.Info.Pac, status)
 	logger.Infof("pipelinerun %s has a status of '%s'", pr.Name, status.Conclusion)
-	return pr, err
+	return pr, failures, err
+}
+
+// tektonResultsRecord returns where Tekton Results recorded the PipelineRun,
+// from the annotations it sets on the PipelineRun.
+func tektonResultsRecord(pr *tektonv1beta1.PipelineRun) *pacv1a1.TektonResultsRecord {
+	record := pr.GetAnnotations()[consoleui.TektonResultsRecordAnnotation]
+	if record == "" {
+		return nil
+	}
+	return &pacv1a1.TektonResultsRecord{
+		Result: pr.GetAnnotations()[consoleui.TektonResultsResultAnnotation],
+		Record: record,
+	}
+}
+
+// detailURL returns the URL of the done PipelineRun, its record on Tekton
+// Results when it has one so the link keeps working once the PipelineRun is
+// pruned, or its console URL.
+func (r *Reconciler) detailURL(pr *tektonv1beta1.PipelineRun) string {
+	if record := tektonResultsRecord(pr); record != nil && r.run.Info.Pac.TektonResultsURL != "" {
+		return consoleui.TektonResultsRecordURL(r.run.Info.Pac.TektonResultsURL, record.Record)
+	}
+	return r.run.Clients.ConsoleUI.DetailURL(pr.GetNamespace(), pr.GetName())
+}
+
+func hasTimedOut(pr *tektonv1beta1.PipelineRun) bool {
+	return len(pr.Status.Conditions) > 0 && pr.Status.Conditions[0].Reason == tektonv1beta1.PipelineRunReasonTimedOut.String()
+}
+
+// timeoutsDescription describe the timeouts of the PipelineRun, a timeout of
+// 0 means no timeout.
+func timeoutsDescription(ctx context.Context, pr *tektonv1beta1.PipelineRun) string {
+	timeouts := []string{fmt.Sprintf("pipeline <b>%s</b>", pr.PipelineTimeout(ctx))}
+	if tasks := pr.TasksTimeout(); tasks != nil {
+		timeouts = append(timeouts, fmt.Sprintf("tasks <b>%s</b>", tasks.Duration))
+	}
+	if finally := pr.FinallyTimeout(); finally != nil {
+		timeouts = append(timeouts, fmt.Sprintf("finally <b>%s</b>", finally.Duration))
+	}
+	return strings.Join(timeouts, ", ")
+}
+
+// artifactsRows render the artifacts as the rows of the artifacts table, they
+// come from the task results so we escape them.
+func artifactsRows(artifacts []kstatus.Artifact) string {
+	var rows strings.Builder
+	for _, artifact := range artifacts {
+		digest := artifact.Digest
+		if digest == "" {
+			digest = "---"
+		}
+		rows.WriteString(fmt.Sprintf(artifactRowText,
+			html.EscapeString(artifact.URL), html.EscapeString(artifact.Name), html.EscapeString(digest)))
+	}
+	return rows.String()
 }
 
 func createStatusWithRetry(ctx context.Context, logger *zap.SugaredLogger, tekton versioned.Interface, vcx provider.Interface, event *info.Event, opts *info.PacOpts, status provider.StatusOpts) error {
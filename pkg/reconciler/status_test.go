@@ -3,12 +3,23 @@ package reconciler
 import (
 	"context"
 	"testing"
+	"time"
 
+	pacv1a1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	provider2 "github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"go.uber.org/zap"
 	zapobserver "go.uber.org/zap/zaptest/observer"
 	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
 func TestCreateStatusWithRetry(t *testing.T) {
@@ -29,3 +40,85 @@ func TestCreateStatusWithRetry_ErrorCase(t *testing.T) {
 	err := createStatusWithRetry(context.TODO(), fakelogger, nil, &vcx, nil, nil, provider2.StatusOpts{})
 	assert.Error(t, err, "failed to report status: some provider error occurred while reporting status")
 }
+
+func TestArtifactsRows(t *testing.T) {
+	got := artifactsRows([]kstatus.Artifact{
+		{Name: "image", URL: "https://quay.io/org/image", Digest: "sha256:1234"},
+		{Name: "<b>report</b>", URL: "https://reports/?a=1&b=2"},
+	})
+	assert.Equal(t, got,
+		`<tr><td><a href="https://quay.io/org/image">image</a></td><td><code>sha256:1234</code></td></tr>`+
+			`<tr><td><a href="https://reports/?a=1&amp;b=2">&lt;b&gt;report&lt;/b&gt;</a></td><td><code>---</code></td></tr>`)
+}
+
+func TestTimeoutsDescription(t *testing.T) {
+	pr := &tektonv1beta1.PipelineRun{
+		Spec: tektonv1beta1.PipelineRunSpec{
+			Timeouts: &tektonv1beta1.TimeoutFields{
+				Pipeline: &metav1.Duration{Duration: time.Hour},
+				Tasks:    &metav1.Duration{Duration: 50 * time.Minute},
+			},
+		},
+		Status: tektonv1beta1.PipelineRunStatus{
+			Status: knativeduckv1.Status{
+				Conditions: knativeduckv1.Conditions{
+					{Reason: tektonv1beta1.PipelineRunReasonTimedOut.String()},
+				},
+			},
+		},
+	}
+	assert.Assert(t, hasTimedOut(pr))
+	assert.Equal(t, timeoutsDescription(context.TODO(), pr), "pipeline <b>1h0m0s</b>, tasks <b>50m0s</b>, finally <b>10m0s</b>")
+}
+
+func TestDetailURL(t *testing.T) {
+	recorded := map[string]string{
+		consoleui.TektonResultsResultAnnotation: "ns/results/123",
+		consoleui.TektonResultsRecordAnnotation: "ns/results/123/records/456",
+	}
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		resultsURL  string
+		want        string
+		wantRecord  *pacv1a1.TektonResultsRecord
+	}{
+		{
+			name:        "recorded by tekton results",
+			annotations: recorded,
+			resultsURL:  "https://results.example.com",
+			want:        "https://results.example.com/apis/results.tekton.dev/v1alpha2/parents/ns/results/123/records/456",
+			wantRecord:  &pacv1a1.TektonResultsRecord{Result: "ns/results/123", Record: "ns/results/123/records/456"},
+		},
+		{
+			name:        "tekton results url not set",
+			annotations: recorded,
+			want:        "https://dashboard.example.com/#/namespaces/ns/pipelineruns/pr",
+			wantRecord:  &pacv1a1.TektonResultsRecord{Result: "ns/results/123", Record: "ns/results/123/records/456"},
+		},
+		{
+			name:       "not recorded",
+			resultsURL: "https://results.example.com",
+			want:       "https://dashboard.example.com/#/namespaces/ns/pipelineruns/pr",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &tektonv1beta1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{Name: "pr", Namespace: "ns", Annotations: tt.annotations},
+			}
+			r := &Reconciler{
+				run: &params.Run{
+					Clients: clients.Clients{
+						ConsoleUI: &consoleui.TektonDashboard{BaseURL: "https://dashboard.example.com"},
+					},
+					Info: info.Info{
+						Pac: &info.PacOpts{Settings: &settings.Settings{TektonResultsURL: tt.resultsURL}},
+					},
+				},
+			}
+			assert.Equal(t, r.detailURL(pr), tt.want)
+			assert.DeepEqual(t, tektonResultsRecord(pr), tt.wantRecord)
+		})
+	}
+}
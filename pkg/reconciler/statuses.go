@@ -0,0 +1,83 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+)
+
+// statusBatchWindow is the time the statuses of the PipelineRuns of an event
+// are collected before being reported together, when many TaskRuns finish at
+// the same time only the last status of each PipelineRun gets reported.
+const statusBatchWindow = 2 * time.Second
+
+type pendingStatus struct {
+	status provider.StatusOpts
+	// done is called with the result of the report of the batch
+	done func(error)
+}
+
+type statusBatch struct {
+	provider provider.Interface
+	event    *info.Event
+	statuses []pendingStatus
+}
+
+// statusBatcher coalesces the statuses reported for the PipelineRuns of an
+// event into batches reported with a single CreateStatuses call.
+type statusBatcher struct {
+	mu      sync.Mutex
+	window  time.Duration
+	batches map[string]*statusBatch
+}
+
+func newStatusBatcher(window time.Duration) *statusBatcher {
+	return &statusBatcher{window: window, batches: map[string]*statusBatch{}}
+}
+
+// statusBatchKey is the key of the batch of the statuses of an event, the
+// PipelineRuns of the same commit and event of a Repository.
+func statusBatchKey(namespace, repository string, event *info.Event) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%d", namespace, repository, event.SHA, event.EventType, event.PullRequestNumber)
+}
+
+// add a status to the batch of its event, the batch is reported at the end of
+// the window started by its first status. The provider and the event of the
+// first status are used to report the batch.
+func (s *statusBatcher) add(ctx context.Context, key string, p provider.Interface, tekton versioned.Interface,
+	event *info.Event, opts *info.PacOpts, status provider.StatusOpts, done func(error),
+) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	batch, ok := s.batches[key]
+	if !ok {
+		batch = &statusBatch{provider: p, event: event}
+		s.batches[key] = batch
+		time.AfterFunc(s.window, func() { s.flush(ctx, key, tekton, opts) })
+	}
+	batch.statuses = append(batch.statuses, pendingStatus{status: status, done: done})
+}
+
+func (s *statusBatcher) flush(ctx context.Context, key string, tekton versioned.Interface, opts *info.PacOpts) {
+	s.mu.Lock()
+	batch := s.batches[key]
+	delete(s.batches, key)
+	s.mu.Unlock()
+	if batch == nil {
+		return
+	}
+
+	statuses := make([]provider.StatusOpts, 0, len(batch.statuses))
+	for _, pending := range batch.statuses {
+		statuses = append(statuses, pending.status)
+	}
+	err := batch.provider.CreateStatuses(ctx, tekton, batch.event, opts, statuses)
+	for _, pending := range batch.statuses {
+		pending.done(err)
+	}
+}
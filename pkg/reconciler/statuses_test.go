@@ -0,0 +1,63 @@
+package reconciler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	"gotest.tools/v3/assert"
+)
+
+func TestStatusBatcher(t *testing.T) {
+	batcher := newStatusBatcher(20 * time.Millisecond)
+	event := &info.Event{SHA: "sha", EventType: "pull_request", PullRequestNumber: 1}
+	other := &info.Event{SHA: "sha", EventType: "push"}
+	ctx := context.Background()
+
+	vcx := &testprovider.TestProviderImp{}
+	otherVcx := &testprovider.TestProviderImp{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := map[string]int{}
+	done := func(name string) func(error) {
+		wg.Add(1)
+		return func(err error) {
+			defer wg.Done()
+			assert.NilError(t, err)
+			mu.Lock()
+			defer mu.Unlock()
+			results[name]++
+		}
+	}
+
+	key := statusBatchKey("ns", "repo", event)
+	batcher.add(ctx, key, vcx, nil, event, nil, provider.StatusOpts{PipelineRunName: "pr-a", Text: "1/3"}, done("pr-a"))
+	batcher.add(ctx, key, vcx, nil, event, nil, provider.StatusOpts{PipelineRunName: "pr-b", Text: "1/2"}, done("pr-b"))
+	batcher.add(ctx, key, vcx, nil, event, nil, provider.StatusOpts{PipelineRunName: "pr-a", Text: "2/3"}, done("pr-a"))
+	batcher.add(ctx, statusBatchKey("ns", "repo", other), otherVcx, nil, other, nil,
+		provider.StatusOpts{PipelineRunName: "pr-c", Text: "1/1"}, done("pr-c"))
+	wg.Wait()
+
+	texts := []string{}
+	for _, status := range vcx.CreatedStatuses {
+		texts = append(texts, status.PipelineRunName+" "+status.Text)
+	}
+	assert.DeepEqual(t, texts, []string{"pr-b 1/2", "pr-a 2/3"})
+	assert.Equal(t, len(otherVcx.CreatedStatuses), 1)
+	assert.DeepEqual(t, results, map[string]int{"pr-a": 2, "pr-b": 1, "pr-c": 1})
+	assert.Equal(t, len(batcher.batches), 0)
+}
+
+func TestStatusBatcherError(t *testing.T) {
+	batcher := newStatusBatcher(time.Millisecond)
+	event := &info.Event{SHA: "sha"}
+	errs := make(chan error, 1)
+	vcx := &testprovider.TestProviderImp{CreateStatusErorring: true}
+	batcher.add(context.Background(), statusBatchKey("ns", "repo", event), vcx, nil, event, nil,
+		provider.StatusOpts{PipelineRunName: "pr-a"}, func(err error) { errs <- err })
+	assert.ErrorContains(t, <-errs, "cannot create 1 of the statuses: pr-a: some provider error occurred while reporting status")
+}
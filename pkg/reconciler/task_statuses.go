@@ -0,0 +1,199 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	gosort "sort"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	"knative.dev/pkg/apis"
+)
+
+// checkRunPerTask returns if the tasks of the PipelineRuns of the Repository
+// are reported on their own.
+func checkRunPerTask(repo *v1alpha1.Repository) bool {
+	return repo.Spec.Settings != nil && repo.Spec.Settings.CheckRunPerTask
+}
+
+// taskStatuses returns the status of each task of a PipelineRun, in the order
+// of its pipeline. The tasks of the pipeline without a TaskRun are queued
+// while the PipelineRun runs and skipped once it's done.
+func (r *Reconciler) taskStatuses(pr *v1beta1.PipelineRun, trStatus map[string]*v1beta1.PipelineRunTaskRunStatus) []provider.TaskStatusOpts {
+	byTask := map[string]*v1beta1.PipelineRunTaskRunStatus{}
+	for _, taskrunStatus := range trStatus {
+		byTask[taskrunStatus.PipelineTaskName] = taskrunStatus
+	}
+
+	names := []string{}
+	inSpec := map[string]bool{}
+	if spec := pr.Status.PipelineSpec; spec != nil {
+		for _, task := range append(append([]v1beta1.PipelineTask{}, spec.Tasks...), spec.Finally...) {
+			names = append(names, task.Name)
+			inSpec[task.Name] = true
+		}
+	}
+	others := []string{}
+	for name := range byTask {
+		if !inSpec[name] {
+			others = append(others, name)
+		}
+	}
+	gosort.Strings(others)
+	names = append(names, others...)
+
+	statuses := make([]provider.TaskStatusOpts, 0, len(names))
+	for _, name := range names {
+		status := provider.TaskStatusOpts{
+			Name:       name,
+			DetailsURL: r.run.Clients.ConsoleUI.TaskLogURL(pr.GetNamespace(), pr.GetName(), name),
+		}
+		taskrunStatus, ok := byTask[name]
+		switch {
+		case ok && taskrunStatus.Status != nil:
+			status.StartedAt = taskrunStatus.Status.StartTime
+			condition := taskrunStatus.Status.GetCondition(apis.ConditionSucceeded)
+			if condition == nil || condition.IsUnknown() {
+				status.Status = "in_progress"
+				break
+			}
+			status.Status = "completed"
+			status.CompletedAt = taskrunStatus.Status.CompletionTime
+			switch {
+			case condition.IsTrue():
+				status.Conclusion = "success"
+			case condition.Reason == v1beta1.TaskRunReasonCancelled.String():
+				status.Conclusion = "cancelled"
+			case condition.Reason == v1beta1.TaskRunReasonTimedOut.String():
+				status.Conclusion = "timed_out"
+			default:
+				status.Conclusion = "failure"
+			}
+		case pr.IsDone():
+			status.Status = "completed"
+			status.Conclusion = "skipped"
+			status.CompletedAt = pr.Status.CompletionTime
+		default:
+			status.Status = "queued"
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// changedTaskStatuses returns the statuses of the tasks of the PipelineRun
+// which have changed since they were last reported, and all the statuses to
+// store on the PipelineRun once reported.
+func (r *Reconciler) changedTaskStatuses(ctx context.Context, logger *zap.SugaredLogger, pr *v1beta1.PipelineRun) ([]provider.TaskStatusOpts, map[string]string) {
+	reported := map[string]string{}
+	if value := pr.GetAnnotations()[keys.TaskStatuses]; value != "" {
+		if err := json.Unmarshal([]byte(value), &reported); err != nil {
+			logger.Warnf("cannot parse the reported task statuses of pipelinerun %s, reporting them again: %v", pr.GetName(), err)
+			reported = map[string]string{}
+		}
+	}
+
+	trStatus := kstatus.GetStatusFromTaskStatusOrFromAsking(ctx, pr, r.run)
+	changed := []provider.TaskStatusOpts{}
+	states := map[string]string{}
+	for _, status := range r.taskStatuses(pr, trStatus) {
+		state := status.Status
+		if status.Conclusion != "" {
+			state = fmt.Sprintf("%s/%s", status.Status, status.Conclusion)
+		}
+		states[status.Name] = state
+		if reported[status.Name] != state {
+			changed = append(changed, status)
+		}
+	}
+	return changed, states
+}
+
+// reportRunningTaskStatuses reports the tasks of a running PipelineRun whose
+// status has changed, when the Repository has a check run per task.
+func (r *Reconciler) reportRunningTaskStatuses(ctx context.Context, logger *zap.SugaredLogger, pr *v1beta1.PipelineRun) error {
+	repo, err := r.repoLister.Repositories(pr.Namespace).Get(pr.GetLabels()[keys.Repository])
+	if err != nil {
+		// the final status reports the missing Repository
+		logger.Warnf("cannot get the repository of pipelinerun %s to report its tasks: %v", pr.GetName(), err)
+		return nil
+	}
+	if !checkRunPerTask(repo) {
+		return nil
+	}
+	changed, states := r.changedTaskStatuses(ctx, logger, pr)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	p, event, err := r.detectProvider(ctx, logger, pr)
+	if err != nil {
+		logger.Error(err)
+		return nil
+	}
+	if _, ok := p.(provider.TaskStatusCreator); !ok {
+		return nil
+	}
+	if err := r.setProviderClient(ctx, logger, p, event, repo); err != nil {
+		return err
+	}
+	r.postTaskStatuses(ctx, logger, p, event, pr, changed, states)
+	return nil
+}
+
+// reportFinalTaskStatuses reports the tasks of a done PipelineRun whose
+// status has changed, with the client of the provider already set for its
+// final status.
+func (r *Reconciler) reportFinalTaskStatuses(ctx context.Context, logger *zap.SugaredLogger, p provider.Interface, event *info.Event, repo *v1alpha1.Repository, pr *v1beta1.PipelineRun) {
+	if !checkRunPerTask(repo) {
+		return
+	}
+	if changed, states := r.changedTaskStatuses(ctx, logger, pr); len(changed) > 0 {
+		r.postTaskStatuses(ctx, logger, p, event, pr, changed, states)
+	}
+}
+
+// postTaskStatuses reports the changed statuses of the tasks to the provider
+// and stores them on the PipelineRun, the providers not supporting it are
+// skipped. A failure is not fatal, the tasks are reported again on the next
+// reconcile.
+func (r *Reconciler) postTaskStatuses(ctx context.Context, logger *zap.SugaredLogger, p provider.Interface, event *info.Event, pr *v1beta1.PipelineRun, changed []provider.TaskStatusOpts, states map[string]string) {
+	creator, ok := p.(provider.TaskStatusCreator)
+	if !ok {
+		logger.Debugf("the git provider of pipelinerun %s does not report the status of its tasks", pr.GetName())
+		return
+	}
+	prStatus := provider.StatusOpts{
+		PipelineRun:             pr,
+		PipelineRunName:         pr.GetName(),
+		OriginalPipelineRunName: pr.GetLabels()[keys.OriginalPRName],
+	}
+	if err := creator.CreateTaskStatuses(ctx, event, r.run.Info.Pac, prStatus, changed); err != nil {
+		logger.Errorf("failed to report the status of the tasks of pipelinerun %s, continuing: %v", pr.GetName(), err)
+		return
+	}
+
+	value, err := json.Marshal(states)
+	if err != nil {
+		logger.Warnf("cannot encode the task statuses of pipelinerun %s: %v", pr.GetName(), err)
+		return
+	}
+	if _, err := action.PatchPipelineRun(ctx, logger, "task statuses", r.run.Clients.Tekton, pr, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				keys.TaskStatuses: string(value),
+			},
+		},
+	}); err != nil {
+		// not fatal, the same statuses get reported again next time
+		logger.Warnf("cannot store the task statuses on pipelinerun: %v", err)
+	}
+	logger.Infof("updated the status of %d tasks of pipelinerun %s", len(changed), pr.GetName())
+}
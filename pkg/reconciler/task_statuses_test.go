@@ -0,0 +1,129 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	pacv1a1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func taskRunStatus(task string, status corev1.ConditionStatus, reason string) *tektonv1beta1.PipelineRunTaskRunStatus {
+	return &tektonv1beta1.PipelineRunTaskRunStatus{
+		PipelineTaskName: task,
+		Status: &tektonv1beta1.TaskRunStatus{
+			Status: knativeduckv1.Status{
+				Conditions: knativeduckv1.Conditions{{Type: apis.ConditionSucceeded, Status: status, Reason: reason}},
+			},
+		},
+	}
+}
+
+func taskStatusesPipelineRun(done bool, annotations map[string]string) *tektonv1beta1.PipelineRun {
+	pr := &tektonv1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "pr", Namespace: "ns", Annotations: annotations},
+		Status: tektonv1beta1.PipelineRunStatus{
+			PipelineRunStatusFields: tektonv1beta1.PipelineRunStatusFields{
+				PipelineSpec: &tektonv1beta1.PipelineSpec{
+					Tasks: []tektonv1beta1.PipelineTask{
+						{Name: "clone"}, {Name: "build"}, {Name: "test"}, {Name: "lint"}, {Name: "deploy"},
+					},
+					Finally: []tektonv1beta1.PipelineTask{{Name: "notify"}},
+				},
+				TaskRuns: map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+					"pr-clone": taskRunStatus("clone", corev1.ConditionTrue, "Succeeded"),
+					"pr-build": taskRunStatus("build", corev1.ConditionUnknown, "Running"),
+					"pr-test":  taskRunStatus("test", corev1.ConditionFalse, tektonv1beta1.TaskRunReasonCancelled.String()),
+					"pr-lint":  taskRunStatus("lint", corev1.ConditionFalse, "Failed"),
+				},
+			},
+		},
+	}
+	if done {
+		pr.Status.Conditions = knativeduckv1.Conditions{{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse}}
+	}
+	return pr
+}
+
+func TestTaskStatuses(t *testing.T) {
+	r := &Reconciler{run: &params.Run{Clients: clients.Clients{ConsoleUI: consoleui.FallBackConsole{}}}}
+
+	tests := []struct {
+		name string
+		done bool
+		want map[string]string
+	}{
+		{
+			name: "running",
+			want: map[string]string{
+				"clone": "completed/success", "build": "in_progress", "test": "completed/cancelled",
+				"lint": "completed/failure", "deploy": "queued", "notify": "queued",
+			},
+		},
+		{
+			name: "done",
+			done: true,
+			want: map[string]string{
+				"clone": "completed/success", "build": "in_progress", "test": "completed/cancelled",
+				"lint": "completed/failure", "deploy": "completed/skipped", "notify": "completed/skipped",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := taskStatusesPipelineRun(tt.done, nil)
+			statuses := r.taskStatuses(pr, pr.Status.TaskRuns)
+			names := []string{}
+			for _, status := range statuses {
+				names = append(names, status.Name)
+				state := status.Status
+				if status.Conclusion != "" {
+					state += "/" + status.Conclusion
+				}
+				assert.Equal(t, state, tt.want[status.Name], status.Name)
+				assert.Assert(t, status.DetailsURL != "")
+			}
+			assert.DeepEqual(t, names, []string{"clone", "build", "test", "lint", "deploy", "notify"})
+		})
+	}
+}
+
+func TestChangedTaskStatuses(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	r := &Reconciler{run: &params.Run{Clients: clients.Clients{ConsoleUI: consoleui.FallBackConsole{}}}}
+
+	pr := taskStatusesPipelineRun(false, map[string]string{
+		keys.TaskStatuses: `{"clone": "completed/success", "build": "queued", "test": "completed/cancelled", "lint": "in_progress", "deploy": "queued", "notify": "queued"}`,
+	})
+	changed, states := r.changedTaskStatuses(context.TODO(), logger, pr)
+	names := []string{}
+	for _, status := range changed {
+		names = append(names, status.Name)
+	}
+	assert.DeepEqual(t, names, []string{"build", "lint"})
+	assert.Equal(t, states["build"], "in_progress")
+	assert.Equal(t, len(states), 6)
+
+	pr.Annotations[keys.TaskStatuses] = "not json"
+	changed, _ = r.changedTaskStatuses(context.TODO(), logger, pr)
+	assert.Equal(t, len(changed), 6)
+}
+
+func TestCheckRunPerTask(t *testing.T) {
+	repo := &pacv1a1.Repository{}
+	assert.Assert(t, !checkRunPerTask(repo))
+	repo.Spec.Settings = &pacv1a1.Settings{CheckRunPerTask: true}
+	assert.Assert(t, checkRunPerTask(repo))
+}
@@ -0,0 +1,86 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// maxDurationTick is how often the PipelineRuns running for longer than the
+// max-pipelinerun-duration are looked for.
+const maxDurationTick = time.Minute
+
+// watchMaxDurationPeriodically cancels the PipelineRuns running for longer
+// than the max-pipelinerun-duration, whatever their Tekton timeouts.
+func (r *Reconciler) watchMaxDurationPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(maxDurationTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if r.run.Info.Pac == nil || r.run.Info.Pac.MaxPipelineRunDuration <= 0 {
+				continue
+			}
+			r.cancelExceedingMaxDuration(ctx, now, r.run.Info.Pac.MaxPipelineRunDuration)
+		}
+	}
+}
+
+// cancelExceedingMaxDuration cancels the started PipelineRuns of the
+// controller running for longer than the max duration, they are annotated
+// with it for their final status to be reported as timed out.
+func (r *Reconciler) cancelExceedingMaxDuration(ctx context.Context, now time.Time, maxDuration time.Duration) {
+	prs, err := r.pipelineRunLister.List(labels.SelectorFromSet(labels.Set{keys.State: kubeinteraction.StateStarted}))
+	if err != nil {
+		r.run.Clients.Log.Errorf("cannot list the pipelineruns to check their duration: %v", err)
+		return
+	}
+	controllerName := info.ControllerName()
+	for _, pr := range prs {
+		if !kubeinteraction.OwnedByController(pr.GetLabels(), controllerName) || !exceedsMaxDuration(pr, now, maxDuration) {
+			continue
+		}
+		logger := r.run.Clients.Log.With("pipeline-run", pr.GetName(), "namespace", pr.GetNamespace())
+		mergePatch := map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]string{
+					keys.MaxDurationExceeded: maxDuration.String(),
+				},
+			},
+			"spec": map[string]interface{}{
+				"status": v1beta1.PipelineRunSpecStatusCancelled,
+			},
+		}
+		if _, err := action.PatchPipelineRun(ctx, logger, "max duration", r.run.Clients.Tekton, pr.DeepCopy(), mergePatch); err != nil {
+			logger.Errorf("cannot cancel the pipelinerun running for longer than %s: %v", maxDuration, err)
+			continue
+		}
+		msg := fmt.Sprintf("pipelinerun %s has been cancelled after running for longer than the max duration of %s", pr.GetName(), maxDuration)
+		logger.Info(msg)
+		if repo, err := r.repoLister.Repositories(pr.GetNamespace()).Get(pr.GetLabels()[keys.Repository]); err == nil {
+			r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "PipelineRunMaxDurationExceeded", msg)
+		}
+	}
+}
+
+// exceedsMaxDuration returns true when a running PipelineRun has started for
+// longer than the max duration and has not been cancelled already.
+func exceedsMaxDuration(pr *v1beta1.PipelineRun, now time.Time, maxDuration time.Duration) bool {
+	if pr.IsDone() || pr.IsCancelled() || pr.Status.StartTime == nil {
+		return false
+	}
+	if _, ok := pr.GetAnnotations()[keys.MaxDurationExceeded]; ok {
+		return false
+	}
+	return now.Sub(pr.Status.StartTime.Time) > maxDuration
+}
@@ -0,0 +1,62 @@
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.uber.org/zap"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCancelExceedingMaxDuration(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	newPipelineRun := func(name string, started time.Duration, annotations map[string]string) *v1beta1.PipelineRun {
+		startTime := metav1.NewTime(now.Add(-started))
+		return &v1beta1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "ns",
+				Labels:      map[string]string{keys.State: kubeinteraction.StateStarted, keys.Repository: "repo"},
+				Annotations: annotations,
+			},
+			Status: v1beta1.PipelineRunStatus{
+				PipelineRunStatusFields: v1beta1.PipelineRunStatusFields{StartTime: &startTime},
+			},
+		}
+	}
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, informers := testclient.SeedTestData(t, ctx, testclient.Data{
+		Repositories: []*v1alpha1.Repository{{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}}},
+		PipelineRuns: []*v1beta1.PipelineRun{
+			newPipelineRun("stuck", 3*time.Hour, map[string]string{}),
+			newPipelineRun("running", time.Hour, map[string]string{}),
+			newPipelineRun("cancelled", 3*time.Hour, map[string]string{keys.MaxDurationExceeded: "1h0m0s"}),
+		},
+	})
+	logger := zap.NewNop().Sugar()
+	r := &Reconciler{
+		run:               &params.Run{Clients: clients.Clients{Tekton: stdata.Pipeline, Log: logger}},
+		repoLister:        informers.Repository.Lister(),
+		pipelineRunLister: stdata.PipelineLister,
+		eventEmitter:      events.NewEventEmitter(stdata.Kube, logger),
+	}
+	r.cancelExceedingMaxDuration(ctx, now, 2*time.Hour)
+
+	for name, want := range map[string]string{"stuck": "2h0m0s", "running": "", "cancelled": "1h0m0s"} {
+		pr, err := stdata.Pipeline.TektonV1beta1().PipelineRuns("ns").Get(ctx, name, metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, pr.GetAnnotations()[keys.MaxDurationExceeded], want, name)
+		assert.Equal(t, pr.IsCancelled(), name == "stuck", name)
+	}
+}
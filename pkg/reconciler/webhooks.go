@@ -0,0 +1,197 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/credentials"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/listers/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/controller"
+	pkgreconciler "knative.dev/pkg/reconciler"
+)
+
+// webhookFinalizer keeps a Repository with a configured webhook until its
+// webhook has been deleted from the git provider
+const webhookFinalizer = pipelinesascode.GroupName + "/webhook"
+
+// webhookReconciler configures the webhook of the Repositories on their git
+// provider with auto-configure-webhooks, as they are created, changed and
+// deleted. Only the leader of the watcher touches the webhooks.
+type webhookReconciler struct {
+	pkgreconciler.LeaderAwareFuncs
+
+	run          *params.Run
+	kinteract    kubeinteraction.Interface
+	repoLister   pacv1alpha1.RepositoryLister
+	eventEmitter *events.EventEmitter
+}
+
+var _ controller.Reconciler = (*webhookReconciler)(nil)
+
+// managesWebhook returns if the webhook of a Repository is configured by the
+// watcher, the Repositories using the GitHub App have no webhook of their own
+// and the wildcard Repositories have one on each of their git repositories.
+func managesWebhook(pac *info.PacOpts, repo *v1alpha1.Repository, controllerName string) bool {
+	if pac == nil || !pac.AutoConfigureWebhooks || !kubeinteraction.OwnedByController(repo.GetLabels(), controllerName) {
+		return false
+	}
+	if repo.Spec.GitProvider == nil || repo.Spec.GitProvider.Secret == nil {
+		return false
+	}
+	_, wildcard := formatting.WildcardURL(repo.Spec.URL)
+	return !wildcard
+}
+
+// configuredWebhook returns the webhook last configured for a Repository, nil
+// when there is none.
+func configuredWebhook(repo *v1alpha1.Repository) *credentials.Webhook {
+	value := repo.GetAnnotations()[keys.Webhook]
+	if value == "" {
+		return nil
+	}
+	webhook := &credentials.Webhook{}
+	if err := json.Unmarshal([]byte(value), webhook); err != nil {
+		return nil
+	}
+	return webhook
+}
+
+func (r *webhookReconciler) Reconcile(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		r.run.Clients.Log.Errorf("invalid resource key %s: %v", key, err)
+		return nil
+	}
+	if !r.IsLeaderFor(types.NamespacedName{Namespace: namespace, Name: name}) {
+		return controller.NewSkipKey(key)
+	}
+	repo, err := r.repoLister.Repositories(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	logger := r.run.Clients.Log.With("namespace", namespace, "repository", name)
+
+	if repo.GetDeletionTimestamp() != nil {
+		return r.deleteWebhook(ctx, logger, repo)
+	}
+	if !managesWebhook(r.run.Info.Pac, repo, info.ControllerName()) {
+		return nil
+	}
+	controllerURL := getControllerURL(ctx, r.run)
+	if controllerURL == "" {
+		r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "WebhookConfigurationSkipped",
+			fmt.Sprintf("cannot configure the webhook of the repository, there is no controller-url in the %s configmap", infoConfigMap))
+		return nil
+	}
+
+	previous := configuredWebhook(repo)
+	if previous != nil && previous.Repository != repo.Spec.URL {
+		// the repository now maps another git repository
+		if err := credentials.DeleteWebhook(ctx, r.run, r.kinteract, repo, previous); err != nil {
+			r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "WebhookDeletionFailed",
+				fmt.Sprintf("cannot delete the webhook of the previous git repository %s: %v", previous.Repository, err))
+		}
+	}
+	webhook, err := credentials.ConfigureWebhook(ctx, r.run, r.kinteract, repo, controllerURL, previous)
+	if err != nil {
+		r.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "WebhookConfigurationFailed",
+			fmt.Sprintf("cannot configure the webhook of the repository: %v", err))
+		return err
+	}
+	if previous != nil && *webhook == *previous && sets.NewString(repo.GetFinalizers()...).Has(webhookFinalizer) {
+		return nil
+	}
+	if err := r.updateRepository(ctx, repo, func(repo *v1alpha1.Repository) {
+		value, _ := json.Marshal(webhook)
+		annotations := repo.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[keys.Webhook] = string(value)
+		repo.SetAnnotations(annotations)
+		if !sets.NewString(repo.GetFinalizers()...).Has(webhookFinalizer) {
+			repo.SetFinalizers(append(repo.GetFinalizers(), webhookFinalizer))
+		}
+	}); err != nil {
+		return err
+	}
+	r.eventEmitter.EmitMessage(repo, zap.InfoLevel, "WebhookConfigured",
+		fmt.Sprintf("the webhook %d to %s has been configured on %s", webhook.ID, webhook.URL, webhook.Repository))
+	return nil
+}
+
+// deleteWebhook deletes the webhook of a deleted Repository from the git
+// provider, a failure is reported but doesn't keep the Repository, its
+// secrets may be gone with its namespace.
+func (r *webhookReconciler) deleteWebhook(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository) error {
+	if !sets.NewString(repo.GetFinalizers()...).Has(webhookFinalizer) {
+		return nil
+	}
+	if webhook := configuredWebhook(repo); webhook != nil {
+		if err := credentials.DeleteWebhook(ctx, r.run, r.kinteract, repo, webhook); err != nil {
+			logger.Warnf("cannot delete the webhook %d of the repository on %s, it has to be deleted by hand: %v", webhook.ID, webhook.Repository, err)
+		} else {
+			logger.Infof("the webhook %d of the repository has been deleted from %s", webhook.ID, webhook.Repository)
+		}
+	}
+	return r.updateRepository(ctx, repo, func(repo *v1alpha1.Repository) {
+		finalizers := []string{}
+		for _, finalizer := range repo.GetFinalizers() {
+			if finalizer != webhookFinalizer {
+				finalizers = append(finalizers, finalizer)
+			}
+		}
+		repo.SetFinalizers(finalizers)
+	})
+}
+
+// updateRepository applies a change to the latest version of a Repository,
+// the credentials check updates it as well.
+func (r *webhookReconciler) updateRepository(ctx context.Context, repo *v1alpha1.Repository, change func(*v1alpha1.Repository)) error {
+	var err error
+	for i := 0; i < maxCredentialsUpdate; i++ {
+		var lastrepo *v1alpha1.Repository
+		lastrepo, err = r.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).Get(ctx, repo.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		change(lastrepo)
+		_, err = r.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(lastrepo.GetNamespace()).Update(ctx, lastrepo, metav1.UpdateOptions{})
+		if err == nil || !errors.IsConflict(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("cannot update the repository after %d conflicts: %w", maxCredentialsUpdate, err)
+}
+
+// promote enqueues all the Repositories once the watcher is the leader, their
+// webhook may have changed while it was not.
+func (r *webhookReconciler) promote(bkt pkgreconciler.Bucket, enq func(pkgreconciler.Bucket, types.NamespacedName)) error {
+	repos, err := r.repoLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, repo := range repos {
+		enq(bkt, types.NamespacedName{Namespace: repo.GetNamespace(), Name: repo.GetName()})
+	}
+	return nil
+}
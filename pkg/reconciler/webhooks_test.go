@@ -0,0 +1,120 @@
+package reconciler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	kitesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/controller"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestReconcileWebhooks(t *testing.T) {
+	t.Setenv("SYSTEM_NAMESPACE", "pac")
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/hooks") {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/owner/repo/hooks":
+			fmt.Fprint(w, `[{"id": 5, "url": "https://pac.company.com"}]`)
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v4/projects/owner/repo/hooks/5":
+			fmt.Fprint(w, `{"id": 5}`)
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v4/projects/owner/repo/hooks/5":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	managed := credentialsRepo("managed", server.URL)
+	deleted := credentialsRepo("deleted", server.URL)
+	deleted.Annotations = map[string]string{
+		keys.Webhook: `{"id": 5, "repository": "https://gitlab.company.com/owner/repo", "url": "https://pac.company.com"}`,
+	}
+	deleted.Finalizers = []string{webhookFinalizer}
+	deleted.DeletionTimestamp = &metav1.Time{}
+	githubApp := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "github-app", Namespace: "ns"},
+		Spec:       v1alpha1.RepositorySpec{URL: "https://github.com/owner/repo"},
+	}
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, informers := testclient.SeedTestData(t, ctx, testclient.Data{
+		Repositories: []*v1alpha1.Repository{managed, deleted, githubApp},
+		ConfigMap: []*corev1.ConfigMap{{
+			ObjectMeta: metav1.ObjectMeta{Name: infoConfigMap, Namespace: "pac"},
+			Data:       map[string]string{"controller-url": "https://pac.company.com"},
+		}},
+	})
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	r := &webhookReconciler{
+		run: &params.Run{
+			Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode, Kube: stdata.Kube, Log: logger},
+			Info:    info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{AutoConfigureWebhooks: true}}},
+		},
+		kinteract:    &kitesthelper.KinterfaceTest{GetSecretResult: map[string]string{"token-secret": "token", "webhook-secret": "secret"}},
+		repoLister:   informers.Repository.Lister(),
+		eventEmitter: events.NewEventEmitter(stdata.Kube, logger),
+	}
+
+	err := r.Reconcile(ctx, "ns/managed")
+	assert.Assert(t, controller.IsSkipKey(err), "only the leader configures the webhooks")
+
+	assert.NilError(t, r.Promote(pkgreconciler.UniversalBucket(), func(pkgreconciler.Bucket, types.NamespacedName) {}))
+	assert.NilError(t, r.Reconcile(ctx, "ns/managed"))
+	got, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "managed", metav1.GetOptions{})
+	assert.NilError(t, err)
+	webhook := configuredWebhook(got)
+	assert.Assert(t, webhook != nil)
+	assert.Equal(t, webhook.ID, int64(5))
+	assert.Equal(t, webhook.URL, "https://pac.company.com")
+	assert.DeepEqual(t, got.GetFinalizers(), []string{webhookFinalizer})
+	assert.DeepEqual(t, requests, []string{"GET /api/v4/projects/owner/repo/hooks", "PUT /api/v4/projects/owner/repo/hooks/5"})
+
+	requests = []string{}
+	assert.NilError(t, r.Reconcile(ctx, "ns/github-app"))
+	assert.Equal(t, len(requests), 0)
+
+	assert.NilError(t, r.Reconcile(ctx, "ns/deleted"))
+	got, err = stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "deleted", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(got.GetFinalizers()), 0)
+	assert.DeepEqual(t, requests, []string{"GET /api/v4/projects/owner/repo/hooks", "DELETE /api/v4/projects/owner/repo/hooks/5"})
+}
+
+func TestManagesWebhook(t *testing.T) {
+	pac := &info.PacOpts{Settings: &settings.Settings{AutoConfigureWebhooks: true}}
+	repo := credentialsRepo("repo", "https://gitlab.company.com")
+	assert.Assert(t, managesWebhook(pac, repo, info.DefaultControllerName))
+	assert.Assert(t, !managesWebhook(&info.PacOpts{Settings: &settings.Settings{}}, repo, info.DefaultControllerName))
+	assert.Assert(t, !managesWebhook(pac, repo, "other"))
+
+	wildcard := credentialsRepo("wildcard", "https://gitlab.company.com")
+	wildcard.Spec.URL = "https://gitlab.company.com/owner/*"
+	assert.Assert(t, !managesWebhook(pac, wildcard, info.DefaultControllerName))
+
+	repo.Spec.GitProvider.Secret = nil
+	assert.Assert(t, !managesWebhook(pac, repo, info.DefaultControllerName))
+}
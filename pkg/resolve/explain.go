@@ -0,0 +1,132 @@
+package resolve
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+// Explanation tells where a resolved PipelineRun comes from and on which
+// events it is matched.
+type Explanation struct {
+	// PipelineRun is the name of the PipelineRun in its file
+	PipelineRun string `json:"pipelinerun"`
+	// File is the file the PipelineRun has been decoded from
+	File string `json:"file"`
+	// Match explains on which events the PipelineRun is run
+	Match string `json:"match"`
+	// Matching are the annotations the PipelineRun is matched with
+	Matching map[string]string `json:"matching,omitempty"`
+	// Remote are the annotations of the remote Tasks and Pipelines fetched
+	// for the PipelineRun
+	Remote map[string]string `json:"remote,omitempty"`
+}
+
+// matchingAnnotations are the annotations selecting the events a PipelineRun
+// is run on.
+var matchingAnnotations = []string{
+	keys.OnEvent,
+	keys.OnTargetBranch,
+	keys.OnCelExpression,
+	keys.OnCheckRun,
+	keys.OnWorkflowRun,
+	keys.OnIssueComment,
+	keys.OnLabel,
+	keys.DraftPRs,
+	keys.TargetNamespace,
+}
+
+// remoteAnnotations are the prefixes of the annotations referencing the remote
+// Tasks and Pipelines, they can be suffixed by a number.
+var remoteAnnotations = []string{keys.Task, keys.Pipeline, keys.CustomTask}
+
+// explain returns the Explanation of a resolved PipelineRun decoded from file.
+func explain(pipelinerun *tektonv1beta1.PipelineRun, file string) Explanation {
+	explanation := Explanation{
+		PipelineRun: pipelinerun.GetLabels()[keys.OriginalPRName],
+		File:        file,
+		Matching:    map[string]string{},
+		Remote:      map[string]string{},
+	}
+	annotations := pipelinerun.GetAnnotations()
+	for _, key := range matchingAnnotations {
+		if value, ok := annotations[key]; ok {
+			explanation.Matching[key] = value
+		}
+	}
+	for key, value := range annotations {
+		for _, prefix := range remoteAnnotations {
+			if key == prefix || strings.HasPrefix(key, prefix+"-") {
+				explanation.Remote[key] = value
+			}
+		}
+	}
+	explanation.Match = matchReason(annotations)
+	return explanation
+}
+
+// ExplainPipelineRuns returns the Explanation of the PipelineRuns of a .tekton
+// directory as they are written, without resolving their Tasks, sorted by
+// name.
+func ExplainPipelineRuns(ctx context.Context, data string) []Explanation {
+	types, _ := decodeTypes(ctx, data)
+	explanations := make([]Explanation, 0, len(types.PipelineRuns))
+	for _, pipelinerun := range types.PipelineRuns {
+		explanation := explain(pipelinerun, "")
+		explanation.PipelineRun = pipelinerun.GetName()
+		if explanation.PipelineRun == "" {
+			explanation.PipelineRun = strings.TrimSuffix(pipelinerun.GetGenerateName(), "-")
+		}
+		explanations = append(explanations, explanation)
+	}
+	sort.Slice(explanations, func(i, j int) bool {
+		return explanations[i].PipelineRun < explanations[j].PipelineRun
+	})
+	return explanations
+}
+
+// matchReason explains in a sentence on which events the matching annotations
+// run a PipelineRun, the same way the annotation matcher does it.
+func matchReason(annotations map[string]string) string {
+	// the pull requests also need one of the labels of the on-label annotation
+	labeled := ""
+	if label, ok := annotations[keys.OnLabel]; ok {
+		labeled = fmt.Sprintf(" on the pull requests labeled %s", label)
+	}
+	if expression, ok := annotations[keys.OnCelExpression]; ok {
+		return fmt.Sprintf("matched on the events where the CEL expression %q is true", expression) + labeled
+	}
+	event, hasEvent := annotations[keys.OnEvent]
+	branch, hasBranch := annotations[keys.OnTargetBranch]
+	if !hasEvent || !hasBranch {
+		return fmt.Sprintf("never matched on an event, it needs the %s and %s annotations or a %s annotation",
+			keys.OnEvent, keys.OnTargetBranch, keys.OnCelExpression)
+	}
+	return fmt.Sprintf("matched on the %s events targeting %s", event, branch) + labeled
+}
+
+// sortedKeys returns the keys of a map in a stable order to print them
+func sortedKeys(m map[string]string) []string {
+	ret := make([]string, 0, len(m))
+	for key := range m {
+		ret = append(ret, key)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// String returns the Explanation as a few lines of text
+func (e Explanation) String() string {
+	text := fmt.Sprintf("PipelineRun %s from %s\n  %s\n", e.PipelineRun, e.File, e.Match)
+	for _, key := range sortedKeys(e.Matching) {
+		text += fmt.Sprintf("  %s: %s\n", key, e.Matching[key])
+	}
+	for _, key := range sortedKeys(e.Remote) {
+		text += fmt.Sprintf("  fetched from %s: %s\n", key, e.Remote[key])
+	}
+	return text
+}
@@ -0,0 +1,134 @@
+package resolve
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestMatchReason(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+	}{
+		{
+			name:        "event and branch",
+			annotations: map[string]string{keys.OnEvent: "[pull_request]", keys.OnTargetBranch: "[main]"},
+			want:        "matched on the [pull_request] events targeting [main]",
+		},
+		{
+			name: "cel expression wins",
+			annotations: map[string]string{
+				keys.OnEvent: "[pull_request]", keys.OnTargetBranch: "[main]",
+				keys.OnCelExpression: `event == "push"`,
+			},
+			want: `matched on the events where the CEL expression "event == \"push\"" is true`,
+		},
+		{
+			name: "labels",
+			annotations: map[string]string{
+				keys.OnEvent: "[pull_request]", keys.OnTargetBranch: "[main]",
+				keys.OnLabel: "[e2e]",
+			},
+			want: "matched on the [pull_request] events targeting [main] on the pull requests labeled [e2e]",
+		},
+		{
+			name:        "no target branch",
+			annotations: map[string]string{keys.OnEvent: "[pull_request]"},
+			want:        "never matched on an event",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchReason(tt.annotations)
+			assert.Assert(t, strings.HasPrefix(got, tt.want), got)
+		})
+	}
+}
+
+func TestExplainPipelineRuns(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	data := `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: push
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[push]"
+    pipelinesascode.tekton.dev/on-target-branch: "[main]"
+---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  generateName: e2e-
+  annotations:
+    pipelinesascode.tekton.dev/on-cel-expression: event == "pull_request"
+---
+apiVersion: tekton.dev/v1beta1
+kind: Task
+metadata:
+  name: task
+`
+	explanations := ExplainPipelineRuns(ctx, data)
+	assert.Equal(t, len(explanations), 2)
+	assert.Equal(t, explanations[0].PipelineRun, "e2e")
+	assert.Assert(t, strings.Contains(explanations[0].Match, "CEL expression"), explanations[0].Match)
+	assert.Equal(t, explanations[1].PipelineRun, "push")
+	assert.Equal(t, explanations[1].Match, "matched on the [push] events targeting [main]")
+}
+
+func TestResolveFilesExplain(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	pipelinerun := `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: %s
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[push]"
+    pipelinesascode.tekton.dev/on-target-branch: "[main]"
+    pipelinesascode.tekton.dev/task: "[git-clone]"
+    pipelinesascode.tekton.dev/task-1: "[https://remote/task.yaml]"
+    pipelinesascode.tekton.dev/max-keep-runs: "5"
+spec:
+  pipelineSpec:
+    tasks:
+      - name: task
+        taskSpec:
+          steps:
+            - name: step
+              image: busybox
+`
+	input := Input{
+		Files: map[string]string{
+			".tekton/push.yaml": strings.ReplaceAll(pipelinerun, "%s", "push"),
+			".tekton/nightly.yaml": strings.ReplaceAll(strings.ReplaceAll(pipelinerun, "%s", "nightly"),
+				"pipelinesascode.tekton.dev/on-target-branch", "pipelinesascode.tekton.dev/on-cel-expression"),
+		},
+		GenerateName: true,
+		Explain:      true,
+	}
+	output, err := ResolveFiles(ctx, input)
+	assert.NilError(t, err)
+	assert.Equal(t, len(output.Explanations), 2)
+
+	nightly, push := output.Explanations[0], output.Explanations[1]
+	assert.Equal(t, nightly.PipelineRun, "nightly")
+	assert.Equal(t, nightly.File, ".tekton/nightly.yaml")
+	assert.Assert(t, strings.Contains(nightly.Match, "CEL expression"), nightly.Match)
+	assert.Equal(t, push.PipelineRun, "push")
+	assert.Equal(t, push.File, ".tekton/push.yaml")
+	assert.Equal(t, push.Match, "matched on the [push] events targeting [main]")
+	assert.Equal(t, len(push.Matching), 2)
+	assert.Equal(t, len(push.Remote), 2)
+	assert.Assert(t, strings.Contains(push.String(), "fetched from pipelinesascode.tekton.dev/task-1: [https://remote/task.yaml]"), push.String())
+
+	input.Explain = false
+	output, err = ResolveFiles(ctx, input)
+	assert.NilError(t, err)
+	assert.Equal(t, len(output.Explanations), 0)
+}
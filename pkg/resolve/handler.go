@@ -0,0 +1,50 @@
+package resolve
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// maxRequestSize is the maximum size of the body we accept on the resolve
+// endpoint
+const maxRequestSize = 1 << 20
+
+// Handler serve ResolveFiles over HTTP, it takes a POST of a json Input and
+// answer with the json Output. Remote tasks annotations are not fetched from
+// the endpoint.
+func Handler(logger *zap.SugaredLogger) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			writeOutput(logger, response, http.StatusMethodNotAllowed, &Output{
+				Diagnostics: []Diagnostic{{Message: fmt.Sprintf("method %s is not allowed, use POST", request.Method)}},
+			})
+			return
+		}
+
+		input := Input{}
+		if err := json.NewDecoder(http.MaxBytesReader(response, request.Body, maxRequestSize)).Decode(&input); err != nil {
+			writeOutput(logger, response, http.StatusBadRequest, &Output{
+				Diagnostics: []Diagnostic{{Message: fmt.Sprintf("invalid request body: %v", err)}},
+			})
+			return
+		}
+
+		output, err := ResolveFiles(request.Context(), input)
+		if err != nil {
+			writeOutput(logger, response, http.StatusUnprocessableEntity, output)
+			return
+		}
+		writeOutput(logger, response, http.StatusOK, output)
+	}
+}
+
+func writeOutput(logger *zap.SugaredLogger, response http.ResponseWriter, statusCode int, output *Output) {
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(statusCode)
+	if err := json.NewEncoder(response).Encode(output); err != nil {
+		logger.Errorf("failed to write back resolve response: %v", err)
+	}
+}
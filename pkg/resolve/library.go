@@ -0,0 +1,117 @@
+package resolve
+
+import (
+	"context"
+	"sort"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/templates"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Fetcher fetch the remote Tasks and Pipelines referenced in the
+// pipelinesascode.tekton.dev/task and pipelinesascode.tekton.dev/pipeline
+// annotations of a PipelineRun.
+type Fetcher interface {
+	GetTaskFromAnnotations(ctx context.Context, annotations map[string]string) ([]*tektonv1beta1.Task, error)
+	GetPipelineFromAnnotations(ctx context.Context, annotations map[string]string) ([]*tektonv1beta1.Pipeline, error)
+}
+
+// BundleFetcher fetch the Tasks and Pipelines referenced from a Tekton OCI
+// bundle, a Fetcher implementing it get the bundles inlined when asked.
+type BundleFetcher interface {
+	GetTaskFromBundle(ctx context.Context, reference, name string) (*tektonv1beta1.Task, error)
+	GetPipelineFromBundle(ctx context.Context, reference, name string) (*tektonv1beta1.Pipeline, error)
+}
+
+// CustomTaskFetcher fetch the custom tasks referenced in the
+// pipelinesascode.tekton.dev/custom-task annotations of a PipelineRun, a
+// Fetcher implementing it get the custom tasks inlined.
+type CustomTaskFetcher interface {
+	GetCustomTaskFromAnnotations(ctx context.Context, annotations map[string]string) ([]*unstructured.Unstructured, error)
+}
+
+// Input is what we need to resolve a set of files the same way Pipelines as
+// Code does it on an event.
+type Input struct {
+	// Files map a file name to its yaml content
+	Files map[string]string `json:"files"`
+	// Params replace the {{ param }} placeholders in the files
+	Params map[string]string `json:"params,omitempty"`
+	// GenerateName set a generateName on the PipelineRuns instead of a name
+	GenerateName bool `json:"generate_name,omitempty"`
+	// SkipInlining contains the task names to not inline
+	SkipInlining []string `json:"skip_inlining,omitempty"`
+	// InlineBundles fetch and inline the tasks and pipelines referenced from
+	// OCI bundles, the Fetcher needs to be a BundleFetcher
+	InlineBundles bool `json:"inline_bundles,omitempty"`
+	// Fetcher is used to fetch the remote tasks, remote annotations are
+	// ignored when nil
+	Fetcher Fetcher `json:"-"`
+	// Explain the file each PipelineRun comes from and on which events it is
+	// matched in the Explanations of the Output
+	Explain bool `json:"explain,omitempty"`
+}
+
+// Diagnostic is a message about a document we could not use while resolving.
+type Diagnostic struct {
+	File    string `json:"file,omitempty"`
+	Message string `json:"message"`
+}
+
+// Output is the result of the resolution of an Input.
+type Output struct {
+	PipelineRuns []*tektonv1beta1.PipelineRun `json:"pipelineruns"`
+	Diagnostics  []Diagnostic                 `json:"diagnostics,omitempty"`
+	Explanations []Explanation                `json:"explanations,omitempty"`
+}
+
+// ResolveFiles resolve a set of files as a single set of PipelineRuns with
+// everything inlined. It doesn't need any clients so it can be used by other
+// tools wanting the exact same resolution as Pipelines as Code.
+func ResolveFiles(ctx context.Context, input Input) (*Output, error) {
+	output := &Output{Diagnostics: []Diagnostic{}}
+	types := Types{}
+
+	// iterate in a stable order so the result is the same between calls
+	fileNames := make([]string, 0, len(input.Files))
+	for name := range input.Files {
+		fileNames = append(fileNames, name)
+	}
+	sort.Strings(fileNames)
+
+	pipelineRunFiles := map[*tektonv1beta1.PipelineRun]string{}
+	for _, name := range fileNames {
+		content := templates.ReplacePlaceHoldersVariables(input.Files[name], input.Params)
+		fileTypes, diagnostics := decodeTypes(ctx, content)
+		for _, diagnostic := range diagnostics {
+			output.Diagnostics = append(output.Diagnostics, Diagnostic{File: name, Message: diagnostic})
+		}
+		for _, pipelinerun := range fileTypes.PipelineRuns {
+			pipelineRunFiles[pipelinerun] = name
+		}
+		types.PipelineRuns = append(types.PipelineRuns, fileTypes.PipelineRuns...)
+		types.Pipelines = append(types.Pipelines, fileTypes.Pipelines...)
+		types.Tasks = append(types.Tasks, fileTypes.Tasks...)
+		types.CustomTasks = append(types.CustomTasks, fileTypes.CustomTasks...)
+	}
+
+	ropt := &Opts{
+		GenerateName:  input.GenerateName,
+		RemoteTasks:   input.Fetcher != nil,
+		SkipInlining:  input.SkipInlining,
+		InlineBundles: input.InlineBundles,
+	}
+	pipelineRuns, err := resolveTypes(ctx, types, input.Fetcher, ropt)
+	if err != nil {
+		output.Diagnostics = append(output.Diagnostics, Diagnostic{Message: err.Error()})
+		return output, err
+	}
+	output.PipelineRuns = pipelineRuns
+	if input.Explain {
+		for _, pipelinerun := range pipelineRuns {
+			output.Explanations = append(output.Explanations, explain(pipelinerun, pipelineRunFiles[pipelinerun]))
+		}
+	}
+	return output, nil
+}
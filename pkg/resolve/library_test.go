@@ -0,0 +1,249 @@
+package resolve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+type fakeFetcher struct {
+	tasks []*tektonv1beta1.Task
+}
+
+func (f fakeFetcher) GetTaskFromAnnotations(_ context.Context, _ map[string]string) ([]*tektonv1beta1.Task, error) {
+	return f.tasks, nil
+}
+
+func (f fakeFetcher) GetPipelineFromAnnotations(_ context.Context, _ map[string]string) ([]*tektonv1beta1.Pipeline, error) {
+	return nil, nil
+}
+
+type fakeBundleFetcher struct {
+	fakeFetcher
+	task *tektonv1beta1.Task
+}
+
+func (f fakeBundleFetcher) GetTaskFromBundle(_ context.Context, _, _ string) (*tektonv1beta1.Task, error) {
+	return f.task, nil
+}
+
+func (f fakeBundleFetcher) GetPipelineFromBundle(_ context.Context, _, _ string) (*tektonv1beta1.Pipeline, error) {
+	return nil, fmt.Errorf("no pipeline in bundle")
+}
+
+func readTestdata(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name + ".yaml")
+	assert.NilError(t, err)
+	return string(data)
+}
+
+func TestResolveFiles(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           Input
+		wantErr         string
+		wantDiagnostics int
+		wantParam       string
+		wantTaskImage   string
+		wantBundle      string
+	}{
+		{
+			name: "resolve with params",
+			input: Input{
+				Files:  map[string]string{"pr.yaml": readTestdata(t, "pipelinerun-pipeline-task")},
+				Params: map[string]string{"value": "replaced"},
+			},
+			wantParam: "replaced",
+		},
+		{
+			name: "tasks split across files",
+			input: Input{
+				Files: map[string]string{
+					"pr.yaml": `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pr
+spec:
+  pipelineSpec:
+    tasks:
+      - name: task
+        taskRef:
+          name: task
+`,
+					"task.yaml": `---
+apiVersion: tekton.dev/v1beta1
+kind: Task
+metadata:
+  name: task
+spec:
+  steps:
+    - name: step
+      image: fromfile
+`,
+					"README.md": "not a yaml",
+				},
+			},
+			wantDiagnostics: 1,
+			wantTaskImage:   "fromfile",
+		},
+		{
+			name: "remote task from fetcher",
+			input: Input{
+				Files: map[string]string{"pr.yaml": `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pr
+  annotations:
+    pipelinesascode.tekton.dev/task: "task"
+spec:
+  pipelineSpec:
+    tasks:
+      - name: task
+        taskRef:
+          name: task
+`},
+				Fetcher: fakeFetcher{tasks: []*tektonv1beta1.Task{{
+					ObjectMeta: metav1.ObjectMeta{Name: "task"},
+					Spec:       tektonv1beta1.TaskSpec{Steps: []tektonv1beta1.Step{{Name: "step", Image: "remote"}}},
+				}}},
+			},
+			wantTaskImage: "remote",
+		},
+		{
+			name: "task from bundle",
+			input: Input{
+				Files: map[string]string{"pr.yaml": `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pr
+spec:
+  pipelineSpec:
+    tasks:
+      - name: task
+        taskRef:
+          name: task
+          bundle: reg.io/bundle:v1
+`},
+				InlineBundles: true,
+				Fetcher: fakeBundleFetcher{task: &tektonv1beta1.Task{
+					ObjectMeta: metav1.ObjectMeta{Name: "task"},
+					Spec:       tektonv1beta1.TaskSpec{Steps: []tektonv1beta1.Step{{Name: "step", Image: "bundled"}}},
+				}},
+			},
+			wantTaskImage: "bundled",
+		},
+		{
+			name: "bundle kept when not inlining",
+			input: Input{
+				Files: map[string]string{"pr.yaml": `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pr
+spec:
+  pipelineSpec:
+    tasks:
+      - name: task
+        taskRef:
+          name: task
+          bundle: reg.io/bundle:v1
+`},
+				Fetcher: fakeBundleFetcher{},
+			},
+			wantBundle: "reg.io/bundle:v1",
+		},
+		{
+			name:            "no pipelinerun",
+			input:           Input{Files: map[string]string{"pr.yaml": readTestdata(t, "no-pipelinerun")}},
+			wantErr:         "could not find any PipelineRun",
+			wantDiagnostics: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			output, err := ResolveFiles(ctx, tt.input)
+			assert.Equal(t, len(output.Diagnostics), tt.wantDiagnostics, "%+v", output.Diagnostics)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, len(output.PipelineRuns), 1)
+			pr := output.PipelineRuns[0]
+			if tt.wantParam != "" {
+				assert.Equal(t, pr.Spec.Params[0].Value.StringVal, tt.wantParam)
+			}
+			if tt.wantTaskImage != "" {
+				assert.Equal(t, pr.Spec.PipelineSpec.Tasks[0].TaskSpec.Steps[0].Image, tt.wantTaskImage)
+			}
+			if tt.wantBundle != "" {
+				assert.Equal(t, pr.Spec.PipelineSpec.Tasks[0].TaskRef.Bundle, tt.wantBundle)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	log, _ := logger.GetLogger()
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "bad method",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "bad body",
+			method:     http.MethodPost,
+			body:       "{",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "resolve error",
+			method:     http.MethodPost,
+			body:       `{"files": {"pr.yaml": "foo: bar"}}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "resolved",
+			method:     http.MethodPost,
+			body:       `{"files": {"pr.yaml": "apiVersion: tekton.dev/v1beta1\nkind: PipelineRun\nmetadata:\n  name: pr\nspec:\n  pipelineRef:\n    name: bundle\n    bundle: reg.io/bundle"}}`,
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/resolve", bytes.NewBufferString(tt.body))
+			rec := httptest.NewRecorder()
+			Handler(log)(rec, req)
+			assert.Equal(t, rec.Code, tt.wantStatus)
+			output := Output{}
+			assert.NilError(t, json.Unmarshal(rec.Body.Bytes(), &output))
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, len(output.PipelineRuns), 1)
+			} else {
+				assert.Assert(t, len(output.Diagnostics) > 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package resolve
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+// maxMatrixNameLength is the length limit of the label values, the name of a
+// PipelineRun of a matrix goes in the original-prname label
+const maxMatrixNameLength = 63
+
+// maxMatrixSize is the maximum number of param sets of a matrix, each of them
+// is a PipelineRun created for every matching event
+const maxMatrixSize = 32
+
+var reMatrixNameInvalid = regexp.MustCompile(`[^a-z0-9.]+`)
+
+// parseMatrix parse the param sets of the matrix annotation, a json or yaml
+// list of objects with the value of every param.
+func parseMatrix(value string) ([]map[string]string, error) {
+	sets := []map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(value), &sets); err != nil {
+		return nil, fmt.Errorf("the matrix must be a list of param sets: %w", err)
+	}
+	if len(sets) == 0 {
+		return nil, fmt.Errorf("the matrix has no param sets")
+	}
+	if len(sets) > maxMatrixSize {
+		return nil, fmt.Errorf("the matrix has %d param sets, the maximum is %d", len(sets), maxMatrixSize)
+	}
+	matrix := make([]map[string]string, 0, len(sets))
+	for i, set := range sets {
+		if len(set) == 0 {
+			return nil, fmt.Errorf("the param set %d of the matrix is empty", i)
+		}
+		params := map[string]string{}
+		for name, value := range set {
+			switch value.(type) {
+			case map[string]interface{}, []interface{}, nil:
+				return nil, fmt.Errorf("the param %s of the param set %d of the matrix must be a string", name, i)
+			}
+			params[name] = fmt.Sprint(value)
+		}
+		matrix = append(matrix, params)
+	}
+	return matrix, nil
+}
+
+// ValidateMatrix check the value of a matrix annotation can be parsed.
+func ValidateMatrix(value string) error {
+	_, err := parseMatrix(value)
+	return err
+}
+
+// matrixName returns the name of the PipelineRun of a param set, the values of
+// the params sorted by name appended to the name of the PipelineRun, ie:
+// build-1.19-linux.
+func matrixName(name string, params map[string]string) string {
+	names := make([]string, 0, len(params))
+	for param := range params {
+		names = append(names, param)
+	}
+	sort.Strings(names)
+	parts := []string{strings.TrimSuffix(name, "-")}
+	for _, param := range names {
+		if value := strings.Trim(reMatrixNameInvalid.ReplaceAllString(strings.ToLower(params[param]), "-"), "-."); value != "" {
+			parts = append(parts, value)
+		}
+	}
+	matrixName := strings.Join(parts, "-")
+	if len(matrixName) > maxMatrixNameLength {
+		matrixName = strings.TrimRight(matrixName[:maxMatrixNameLength], "-.")
+	}
+	return matrixName
+}
+
+// expandMatrix replace the PipelineRuns with a matrix annotation by one
+// PipelineRun per param set, the params of the set are added or override the
+// ones of the PipelineRun. Every PipelineRun of the matrix has its own name and
+// original-prname, for its status to be reported on its own.
+func expandMatrix(pipelineruns []*tektonv1beta1.PipelineRun) ([]*tektonv1beta1.PipelineRun, error) {
+	expanded := make([]*tektonv1beta1.PipelineRun, 0, len(pipelineruns))
+	for _, pipelinerun := range pipelineruns {
+		value, ok := pipelinerun.GetAnnotations()[apipac.Matrix]
+		if !ok {
+			expanded = append(expanded, pipelinerun)
+			continue
+		}
+		originalName := pipelinerun.GetLabels()[apipac.OriginalPRName]
+		matrix, err := parseMatrix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matrix annotation on pipelinerun %s: %w", originalName, err)
+		}
+		seen := map[string]int{}
+		for i, params := range matrix {
+			name := matrixName(originalName, params)
+			if previous, ok := seen[name]; ok {
+				return nil, fmt.Errorf("invalid matrix annotation on pipelinerun %s: the param sets %d and %d have the same name %s",
+					originalName, previous, i, name)
+			}
+			seen[name] = i
+
+			instance := pipelinerun.DeepCopy()
+			if instance.GetName() != "" {
+				instance.SetName(name)
+			}
+			if instance.GetGenerateName() != "" {
+				instance.SetGenerateName(name + "-")
+			}
+			if instance.Labels == nil {
+				instance.Labels = map[string]string{}
+			}
+			instance.Labels[apipac.OriginalPRName] = name
+			instance.Labels[apipac.MatrixPipelineRun] = originalName
+			applyParams([]*tektonv1beta1.PipelineRun{instance}, params)
+			expanded = append(expanded, instance)
+		}
+	}
+	return expanded, nil
+}
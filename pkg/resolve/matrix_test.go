@@ -0,0 +1,126 @@
+package resolve
+
+import (
+	"strings"
+	"testing"
+
+	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseMatrix(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []map[string]string
+		wantErr string
+	}{
+		{
+			name:  "json",
+			value: `[{"go": "1.19", "os": "linux"}, {"go": "1.20", "os": "darwin"}]`,
+			want:  []map[string]string{{"go": "1.19", "os": "linux"}, {"go": "1.20", "os": "darwin"}},
+		},
+		{
+			name:  "yaml",
+			value: "- go: \"1.19\"\n  race: true\n- go: \"1.20\"\n  race: false\n",
+			want:  []map[string]string{{"go": "1.19", "race": "true"}, {"go": "1.20", "race": "false"}},
+		},
+		{
+			name:    "not a list",
+			value:   `{"go": "1.19"}`,
+			wantErr: "the matrix must be a list of param sets",
+		},
+		{
+			name:    "no param sets",
+			value:   `[]`,
+			wantErr: "the matrix has no param sets",
+		},
+		{
+			name:    "too many param sets",
+			value:   "[" + strings.TrimSuffix(strings.Repeat(`{"go": "1.19"},`, maxMatrixSize+1), ",") + "]",
+			wantErr: "the matrix has 33 param sets, the maximum is 32",
+		},
+		{
+			name:    "empty param set",
+			value:   `[{"go": "1.19"}, {}]`,
+			wantErr: "the param set 1 of the matrix is empty",
+		},
+		{
+			name:    "not a string",
+			value:   `[{"go": ["1.19", "1.20"]}]`,
+			wantErr: "the param go of the param set 0 of the matrix must be a string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMatrix(tt.value)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestMatrixName(t *testing.T) {
+	assert.Equal(t, matrixName("build", map[string]string{"os": "linux", "go": "1.19"}), "build-1.19-linux")
+	assert.Equal(t, matrixName("build-", map[string]string{"image": "quay.io/Org/image:latest"}), "build-quay.io-org-image-latest")
+	assert.Equal(t, matrixName("build", map[string]string{"empty": ""}), "build")
+	long := matrixName("build", map[string]string{"image": "registry.example.com/organization/a-very-long-image-name-"})
+	assert.Equal(t, long, "build-registry.example.com-organization-a-very-long-image-name")
+}
+
+func TestExpandMatrix(t *testing.T) {
+	plain := &tektonv1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "lint-",
+			Labels:       map[string]string{apipac.OriginalPRName: "lint"},
+		},
+	}
+	matrix := &tektonv1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "build-",
+			Labels:       map[string]string{apipac.OriginalPRName: "build"},
+			Annotations:  map[string]string{apipac.Matrix: `[{"go": "1.19"}, {"go": "1.20", "race": "true"}]`},
+		},
+		Spec: tektonv1beta1.PipelineRunSpec{Params: []tektonv1beta1.Param{
+			{Name: "go", Value: *tektonv1beta1.NewStructuredValues("1.18")},
+			{Name: "race", Value: *tektonv1beta1.NewStructuredValues("false")},
+		}},
+	}
+
+	got, err := expandMatrix([]*tektonv1beta1.PipelineRun{plain, matrix})
+	assert.NilError(t, err)
+	assert.Equal(t, len(got), 3)
+	assert.Equal(t, got[0], plain)
+
+	assert.Equal(t, got[1].GetGenerateName(), "build-1.19-")
+	assert.Equal(t, got[1].GetLabels()[apipac.OriginalPRName], "build-1.19")
+	assert.Equal(t, got[1].GetLabels()[apipac.MatrixPipelineRun], "build")
+	assert.DeepEqual(t, got[1].Spec.Params, []tektonv1beta1.Param{
+		{Name: "go", Value: *tektonv1beta1.NewStructuredValues("1.19")},
+		{Name: "race", Value: *tektonv1beta1.NewStructuredValues("false")},
+	})
+
+	assert.Equal(t, got[2].GetGenerateName(), "build-1.20-true-")
+	assert.Equal(t, got[2].GetLabels()[apipac.OriginalPRName], "build-1.20-true")
+	assert.DeepEqual(t, got[2].Spec.Params, []tektonv1beta1.Param{
+		{Name: "go", Value: *tektonv1beta1.NewStructuredValues("1.20")},
+		{Name: "race", Value: *tektonv1beta1.NewStructuredValues("true")},
+	})
+	// the PipelineRun of the .tekton directory is left untouched
+	assert.Equal(t, matrix.GetGenerateName(), "build-")
+	assert.Equal(t, matrix.Spec.Params[0].Value.StringVal, "1.18")
+
+	matrix.Annotations[apipac.Matrix] = `[{"os": "Linux"}, {"os": "linux"}]`
+	_, err = expandMatrix([]*tektonv1beta1.PipelineRun{matrix})
+	assert.ErrorContains(t, err, "invalid matrix annotation on pipelinerun build: the param sets 0 and 1 have the same name build-linux")
+
+	matrix.Annotations[apipac.Matrix] = `not a matrix`
+	_, err = expandMatrix([]*tektonv1beta1.PipelineRun{matrix})
+	assert.ErrorContains(t, err, "invalid matrix annotation on pipelinerun build")
+}
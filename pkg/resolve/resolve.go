@@ -2,17 +2,22 @@ package resolve
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
 
 	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/conversion"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	k8scheme "k8s.io/client-go/kubernetes/scheme"
 )
 
@@ -21,12 +26,27 @@ type Types struct {
 	Pipelines    []*tektonv1beta1.Pipeline
 	TaskRuns     []*tektonv1beta1.TaskRun
 	Tasks        []*tektonv1beta1.Task
+	// CustomTasks are the resources run by a custom task controller and
+	// referenced from a taskRef with their apiVersion and kind
+	CustomTasks []*unstructured.Unstructured
 }
 
 var yamlDocSeparatorRe = regexp.MustCompile(`(?m)^---\s*$`)
 
-func readTypes(log *zap.SugaredLogger, data string) Types {
+func readTypes(ctx context.Context, log *zap.SugaredLogger, data string) Types {
+	types, diagnostics := decodeTypes(ctx, data)
+	for _, diagnostic := range diagnostics {
+		log.Info(diagnostic)
+	}
+	return types
+}
+
+// decodeTypes decode the yaml multi documents into Types and return a message
+// for every document it had to skip. The tekton.dev/v1 documents are converted
+// to tekton.dev/v1beta1.
+func decodeTypes(ctx context.Context, data string) (Types, []string) {
 	types := Types{}
+	diagnostics := []string{}
 	decoder := k8scheme.Codecs.UniversalDeserializer()
 
 	for _, doc := range yamlDocSeparatorRe.Split(data, -1) {
@@ -35,8 +55,18 @@ func readTypes(log *zap.SugaredLogger, data string) Types {
 		}
 
 		obj, _, err := decoder.Decode([]byte(doc), nil, nil)
+		if err != nil && runtime.IsNotRegisteredError(err) {
+			if customTask, cerr := matcher.ParseCustomTask(doc); cerr == nil {
+				types.CustomTasks = append(types.CustomTasks, customTask)
+				continue
+			}
+		}
 		if err != nil {
-			log.Infof("Skipping document not looking like a kubernetes resources: %v", err)
+			diagnostics = append(diagnostics, fmt.Sprintf("Skipping document not looking like a kubernetes resources: %v", err))
+			continue
+		}
+		if obj, err = conversion.ToV1Beta1(ctx, obj); err != nil {
+			diagnostics = append(diagnostics, fmt.Sprintf("Skipping document which cannot be converted to tekton.dev/v1beta1: %v", err))
 			continue
 		}
 		switch o := obj.(type) {
@@ -47,11 +77,11 @@ func readTypes(log *zap.SugaredLogger, data string) Types {
 		case *tektonv1beta1.Task:
 			types.Tasks = append(types.Tasks, o)
 		default:
-			log.Info("Skipping document not looking like a tekton resource we can Resolve.")
+			diagnostics = append(diagnostics, "Skipping document not looking like a tekton resource we can Resolve.")
 		}
 	}
 
-	return types
+	return types, diagnostics
 }
 
 func getTaskByName(name string, tasks []*tektonv1beta1.Task) (*tektonv1beta1.Task, error) {
@@ -63,6 +93,18 @@ func getTaskByName(name string, tasks []*tektonv1beta1.Task) (*tektonv1beta1.Tas
 	return &tektonv1beta1.Task{}, fmt.Errorf("cannot find task %s in input", name)
 }
 
+// getCustomTaskByName returns the custom task matching the apiVersion, the
+// kind and the name of a taskRef or nil when there is none, the custom task
+// controllers can as well get the resource from the cluster.
+func getCustomTaskByName(ref *tektonv1beta1.TaskRef, customTasks []*unstructured.Unstructured) *unstructured.Unstructured {
+	for _, value := range customTasks {
+		if value.GetAPIVersion() == ref.APIVersion && value.GetKind() == string(ref.Kind) && value.GetName() == ref.Name {
+			return value
+		}
+	}
+	return nil
+}
+
 func getPipelineByName(name string, tasks []*tektonv1beta1.Pipeline) (*tektonv1beta1.Pipeline, error) {
 	for _, value := range tasks {
 		if value.Name == name {
@@ -85,32 +127,84 @@ func isTektonAPIVersion(apiVersion string) bool {
 	return strings.HasPrefix(apiVersion, "tekton.dev/") || apiVersion == ""
 }
 
-func inlineTasks(tasks []tektonv1beta1.PipelineTask, ropt *Opts, types Types) ([]tektonv1beta1.PipelineTask, error) {
+func inlineTasks(ctx context.Context, tasks []tektonv1beta1.PipelineTask, ropt *Opts, types Types, bundles BundleFetcher) ([]tektonv1beta1.PipelineTask, error) {
 	pipelineTasks := []tektonv1beta1.PipelineTask{}
 	for _, task := range tasks {
-		if task.TaskRef != nil &&
+		switch {
+		case task.TaskRef != nil &&
+			task.TaskRef.Bundle != "" &&
+			bundles != nil &&
+			!skippingTask(task.TaskRef.Name, ropt.SkipInlining):
+			taskResolved, err := bundles.GetTaskFromBundle(ctx, task.TaskRef.Bundle, task.TaskRef.Name)
+			if err != nil {
+				return nil, err
+			}
+			task.TaskRef = nil
+			task.TaskSpec = &tektonv1beta1.EmbeddedTask{TaskSpec: taskResolved.Spec}
+		case task.TaskRef != nil &&
 			task.TaskRef.Bundle == "" &&
 			task.TaskRef.Resolver == "" &&
 			isTektonAPIVersion(task.TaskRef.APIVersion) &&
 			string(task.TaskRef.Kind) != "ClusterTask" &&
-			!skippingTask(task.TaskRef.Name, ropt.SkipInlining) {
+			!skippingTask(task.TaskRef.Name, ropt.SkipInlining):
 			taskResolved, err := getTaskByName(task.TaskRef.Name, types.Tasks)
 			if err != nil {
 				return nil, err
 			}
 			task.TaskRef = nil
 			task.TaskSpec = &tektonv1beta1.EmbeddedTask{TaskSpec: taskResolved.Spec}
+		case task.TaskRef != nil &&
+			task.TaskRef.Bundle == "" &&
+			task.TaskRef.Resolver == "" &&
+			!isTektonAPIVersion(task.TaskRef.APIVersion) &&
+			task.TaskRef.Kind != "" &&
+			!skippingTask(task.TaskRef.Name, ropt.SkipInlining):
+			customTask := getCustomTaskByName(task.TaskRef, types.CustomTasks)
+			if customTask == nil {
+				break
+			}
+			spec, err := json.Marshal(customTask.Object["spec"])
+			if err != nil {
+				return nil, fmt.Errorf("cannot embed the custom task %s/%s: %w", customTask.GetKind(), customTask.GetName(), err)
+			}
+			task.TaskSpec = &tektonv1beta1.EmbeddedTask{
+				TypeMeta: runtime.TypeMeta{APIVersion: task.TaskRef.APIVersion, Kind: string(task.TaskRef.Kind)},
+				Spec:     runtime.RawExtension{Raw: spec},
+			}
+			task.TaskRef = nil
 		}
 		pipelineTasks = append(pipelineTasks, task)
 	}
 	return pipelineTasks, nil
 }
 
+// inlinePipelineSpecTasks inline the tasks and finally tasks of a PipelineSpec.
+func inlinePipelineSpecTasks(ctx context.Context, spec *tektonv1beta1.PipelineSpec, ropt *Opts, types Types, bundles BundleFetcher) error {
+	tasks, err := inlineTasks(ctx, spec.Tasks, ropt, types, bundles)
+	if err != nil {
+		return err
+	}
+	spec.Tasks = tasks
+
+	finally, err := inlineTasks(ctx, spec.Finally, ropt, types, bundles)
+	if err != nil {
+		return err
+	}
+	spec.Finally = finally
+	return nil
+}
+
 type Opts struct {
 	GenerateName  bool     // whether to GenerateName
 	RemoteTasks   bool     // whether to parse annotation to fetch tasks from remote
 	SkipInlining  []string // task to skip inlining
 	ProviderToken string
+	Rules         []v1alpha1.Rule // repository rules to apply on changed files
+	ChangedFiles  []string        // files changed by the event, used to match the rules
+	InlineBundles bool            // whether to fetch and inline the tasks and pipelines referenced from OCI bundles
+	// AllowedTaskSources are globs restricting the remote tasks and pipelines to fetch
+	AllowedTaskSources []string
+	Params             map[string]string // params to add or override in all the PipelineRuns
 }
 
 // Resolve gets a large string which is a yaml multi documents containing
@@ -118,64 +212,73 @@ type Opts struct {
 // generateName can be set as True to set the name as a generateName + "-" for
 // unique pipelinerun
 func Resolve(ctx context.Context, cs *params.Run, logger *zap.SugaredLogger, providerintf provider.Interface, event *info.Event, data string, ropt *Opts) ([]*tektonv1beta1.PipelineRun, error) {
-	types := readTypes(logger, data)
+	types := readTypes(ctx, logger, data)
+	var fetcher Fetcher
+	if ropt.RemoteTasks {
+		fetcher = matcher.RemoteTasks{
+			Run:               cs,
+			Event:             event,
+			ProviderInterface: providerintf,
+			Logger:            logger,
+			AllowedSources:    ropt.AllowedTaskSources,
+		}
+	}
+	return resolveTypes(ctx, types, fetcher, ropt)
+}
+
+// resolveTypes inline the Tasks and Pipelines referenced by the PipelineRuns,
+// fetching the remote ones from annotations with the fetcher when it is
+// not nil.
+func resolveTypes(ctx context.Context, types Types, fetcher Fetcher, ropt *Opts) ([]*tektonv1beta1.PipelineRun, error) {
 	if len(types.PipelineRuns) == 0 {
 		return []*tektonv1beta1.PipelineRun{}, fmt.Errorf("could not find any PipelineRun in your .tekton/ directory")
 	}
 
 	// First resolve Annotations Tasks
 	for _, pipelinerun := range types.PipelineRuns {
-		if ropt.RemoteTasks && pipelinerun.GetObjectMeta().GetAnnotations() != nil {
-			rt := matcher.RemoteTasks{
-				Run:               cs,
-				Event:             event,
-				ProviderInterface: providerintf,
-				Logger:            logger,
-			}
-			remoteTasks, err := rt.GetTaskFromAnnotations(ctx, pipelinerun.GetObjectMeta().GetAnnotations())
+		if fetcher != nil && pipelinerun.GetObjectMeta().GetAnnotations() != nil {
+			remoteTasks, err := fetcher.GetTaskFromAnnotations(ctx, pipelinerun.GetObjectMeta().GetAnnotations())
 			if err != nil {
 				return []*tektonv1beta1.PipelineRun{}, err
 			}
 			// Merge remote tasks with local tasks
 			types.Tasks = append(types.Tasks, remoteTasks...)
 
-			remotePipelines, err := rt.GetPipelineFromAnnotations(ctx, pipelinerun.GetObjectMeta().GetAnnotations())
+			remotePipelines, err := fetcher.GetPipelineFromAnnotations(ctx, pipelinerun.GetObjectMeta().GetAnnotations())
 			if err != nil {
 				return []*tektonv1beta1.PipelineRun{}, err
 			}
 			types.Pipelines = append(types.Pipelines, remotePipelines...)
+
+			if customTaskFetcher, ok := fetcher.(CustomTaskFetcher); ok {
+				remoteCustomTasks, err := customTaskFetcher.GetCustomTaskFromAnnotations(ctx, pipelinerun.GetObjectMeta().GetAnnotations())
+				if err != nil {
+					return []*tektonv1beta1.PipelineRun{}, err
+				}
+				types.CustomTasks = append(types.CustomTasks, remoteCustomTasks...)
+			}
 		}
 	}
 
+	// only fetch the bundles when asked and the fetcher knows how to do it
+	var bundles BundleFetcher
+	if ropt.InlineBundles {
+		bundles, _ = fetcher.(BundleFetcher)
+	}
+
 	// Resolve {Finally/Task}Ref inside Pipeline
 	for _, pipeline := range types.Pipelines {
-		pipelineTasks, err := inlineTasks(pipeline.Spec.Tasks, ropt, types)
-		if err != nil {
+		if err := inlinePipelineSpecTasks(ctx, &pipeline.Spec, ropt, types, bundles); err != nil {
 			return nil, err
 		}
-		pipeline.Spec.Tasks = pipelineTasks
-
-		finallyTasks, err := inlineTasks(pipeline.Spec.Finally, ropt, types)
-		if err != nil {
-			return nil, err
-		}
-		pipeline.Spec.Finally = finallyTasks
 	}
 
 	for _, pipelinerun := range types.PipelineRuns {
 		// Resolve {Finally/Task}Ref inside PipelineSpec inside PipelineRun
 		if pipelinerun.Spec.PipelineSpec != nil {
-			truns, err := inlineTasks(pipelinerun.Spec.PipelineSpec.Tasks, ropt, types)
-			if err != nil {
+			if err := inlinePipelineSpecTasks(ctx, pipelinerun.Spec.PipelineSpec, ropt, types, bundles); err != nil {
 				return nil, err
 			}
-			pipelinerun.Spec.PipelineSpec.Tasks = truns
-
-			fruns, err := inlineTasks(pipelinerun.Spec.PipelineSpec.Finally, ropt, types)
-			if err != nil {
-				return nil, err
-			}
-			pipelinerun.Spec.PipelineSpec.Finally = fruns
 		}
 
 		// Resolve PipelineRef inside PipelineRef
@@ -186,6 +289,16 @@ func Resolve(ctx context.Context, cs *params.Run, logger *zap.SugaredLogger, pro
 			}
 			pipelinerun.Spec.PipelineRef = nil
 			pipelinerun.Spec.PipelineSpec = &pipelineResolved.Spec
+		} else if pipelinerun.Spec.PipelineRef != nil && pipelinerun.Spec.PipelineRef.Bundle != "" && bundles != nil {
+			pipelineResolved, err := bundles.GetPipelineFromBundle(ctx, pipelinerun.Spec.PipelineRef.Bundle, pipelinerun.Spec.PipelineRef.Name)
+			if err != nil {
+				return []*tektonv1beta1.PipelineRun{}, err
+			}
+			if err := inlinePipelineSpecTasks(ctx, &pipelineResolved.Spec, ropt, types, bundles); err != nil {
+				return nil, err
+			}
+			pipelinerun.Spec.PipelineRef = nil
+			pipelinerun.Spec.PipelineSpec = &pipelineResolved.Spec
 		}
 
 		var originPipelinerunName string
@@ -210,10 +323,16 @@ func Resolve(ctx context.Context, cs *params.Run, logger *zap.SugaredLogger, pro
 		}
 		pipelinerun.ObjectMeta.Labels[apipac.OriginalPRName] = originPipelinerunName
 	}
-	return types.PipelineRuns, nil
-}
 
-//nolint:gochecknoinits
-func init() {
-	_ = tektonv1beta1.AddToScheme(k8scheme.Scheme)
+	if len(ropt.Params) > 0 {
+		applyParams(types.PipelineRuns, ropt.Params)
+	}
+	if len(ropt.Rules) > 0 {
+		if err := applyRules(types.PipelineRuns, ropt.Rules, ropt.ChangedFiles); err != nil {
+			return []*tektonv1beta1.PipelineRun{}, err
+		}
+	}
+	// the params of the matrix are applied last, they are specific to every
+	// PipelineRun of the matrix
+	return expandMatrix(types.PipelineRuns)
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
@@ -124,6 +125,19 @@ func TestCustomTasksSkipped(t *testing.T) {
 	assert.Equal(t, string(resolved.Spec.PipelineSpec.Tasks[0].TaskRef.Kind), "Build")
 }
 
+func TestCustomTasksInlined(t *testing.T) {
+	resolved, log, err := readTDfile(t, "pipelinerun-with-an-inlined-customtask", false, true)
+	assert.NilError(t, err)
+	assert.Equal(t, log.FilterMessageSnippet("Skipping").Len(), 0)
+	inlined := resolved.Spec.PipelineSpec.Tasks[0]
+	assert.Assert(t, inlined.TaskRef == nil, "custom task should have been inlined")
+	assert.Equal(t, inlined.TaskSpec.APIVersion, "shipwright.io/v1alpha1")
+	assert.Equal(t, inlined.TaskSpec.Kind, "Build")
+	assert.Assert(t, strings.Contains(string(inlined.TaskSpec.Spec.Raw), "https://github.com/shipwright-io/sample-nodejs"))
+	// the custom task controller gets the resources not in the repository from the cluster
+	assert.Equal(t, resolved.Spec.PipelineSpec.Tasks[1].TaskRef.Name, "in-cluster")
+}
+
 func TestPipelineRunPipelineSpecTaskSpec(t *testing.T) {
 	resolved, _, err := readTDfile(t, "pipelinerun-pipelinespec-taskspec", false, true)
 	assert.NilError(t, err)
@@ -149,6 +163,17 @@ func TestPipelineRunPipelineSpecTaskRef(t *testing.T) {
 	assert.Equal(t, resolved.Spec.PipelineSpec.Tasks[0].TaskSpec.Steps[0].Name, "task1")
 }
 
+func TestPipelineRunV1(t *testing.T) {
+	resolved, _, err := readTDfile(t, "pipelinerun-v1", false, true)
+	assert.NilError(t, err)
+	assert.Equal(t, resolved.APIVersion, "tekton.dev/v1beta1")
+	assert.Equal(t, resolved.Kind, "PipelineRun")
+	assert.Equal(t, resolved.Spec.ServiceAccountName, "builder")
+	assert.Equal(t, resolved.Spec.Timeouts.Pipeline.Duration, time.Hour)
+	assert.Equal(t, resolved.Spec.PipelineSpec.Tasks[0].TaskSpec.Steps[0].Name, "v1-step")
+	assert.Equal(t, resolved.Spec.PipelineSpec.Tasks[1].TaskSpec.Steps[0].Name, "v1beta1-step")
+}
+
 func TestPipelineRunRemoteTaskDisabled(t *testing.T) {
 	resolved, _, err := readTDfile(t, "pipelinerun-pipeline-task-remote-task-annotation", false, false)
 	assert.NilError(t, err)
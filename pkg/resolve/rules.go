@@ -0,0 +1,113 @@
+package resolve
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gobwas/glob"
+	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+// ruleMatchChangedFiles return true if any of the changed files match one of
+// the glob of the rule paths
+func ruleMatchChangedFiles(rule v1alpha1.Rule, changedFiles []string) (bool, error) {
+	for _, path := range rule.Paths {
+		g, err := glob.Compile(path)
+		if err != nil {
+			return false, fmt.Errorf("invalid glob %s in repository rules: %w", path, err)
+		}
+		for _, file := range changedFiles {
+			if g.Match(file) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func setPipelineRunParam(pipelinerun *tektonv1beta1.PipelineRun, name, value string) {
+	for i := range pipelinerun.Spec.Params {
+		if pipelinerun.Spec.Params[i].Name == name {
+			pipelinerun.Spec.Params[i].Value = *tektonv1beta1.NewStructuredValues(value)
+			return
+		}
+	}
+	pipelinerun.Spec.Params = append(pipelinerun.Spec.Params, tektonv1beta1.Param{
+		Name:  name,
+		Value: *tektonv1beta1.NewStructuredValues(value),
+	})
+}
+
+// applyParams add or override the repository params in the PipelineRuns, the
+// params of the rules are applied after them.
+func applyParams(pipelineruns []*tektonv1beta1.PipelineRun, params map[string]string) {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, pipelinerun := range pipelineruns {
+		for _, name := range names {
+			setPipelineRunParam(pipelinerun, name, params[name])
+		}
+	}
+}
+
+// applyRules apply the scheduling hints of the repository rules matching the
+// changed files to the PipelineRuns. Rules are merged in order so later rules
+// override earlier ones, a target-namespace annotation set by the user in the
+// PipelineRun always win over the rule namespace.
+func applyRules(pipelineruns []*tektonv1beta1.PipelineRun, rules []v1alpha1.Rule, changedFiles []string) error {
+	var namespace string
+	nodeSelector := map[string]string{}
+	params := map[string]string{}
+	for _, rule := range rules {
+		matched, err := ruleMatchChangedFiles(rule, changedFiles)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+		if rule.Namespace != "" {
+			namespace = rule.Namespace
+		}
+		for k, v := range rule.NodeSelector {
+			nodeSelector[k] = v
+		}
+		for k, v := range rule.Params {
+			params[k] = v
+		}
+	}
+
+	for _, pipelinerun := range pipelineruns {
+		if namespace != "" {
+			if pipelinerun.ObjectMeta.Annotations == nil {
+				pipelinerun.ObjectMeta.Annotations = map[string]string{}
+			}
+			if _, ok := pipelinerun.ObjectMeta.Annotations[apipac.TargetNamespace]; !ok {
+				pipelinerun.ObjectMeta.Annotations[apipac.TargetNamespace] = namespace
+			}
+		}
+
+		if len(nodeSelector) > 0 {
+			if pipelinerun.Spec.PodTemplate == nil {
+				pipelinerun.Spec.PodTemplate = &pod.PodTemplate{}
+			}
+			if pipelinerun.Spec.PodTemplate.NodeSelector == nil {
+				pipelinerun.Spec.PodTemplate.NodeSelector = map[string]string{}
+			}
+			for k, v := range nodeSelector {
+				pipelinerun.Spec.PodTemplate.NodeSelector[k] = v
+			}
+		}
+
+		for k, v := range params {
+			setPipelineRunParam(pipelinerun, k, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package resolve
+
+import (
+	"testing"
+
+	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyRules(t *testing.T) {
+	tests := []struct {
+		name             string
+		rules            []v1alpha1.Rule
+		changedFiles     []string
+		annotations      map[string]string
+		params           []tektonv1beta1.Param
+		wantNamespace    string
+		wantNodeSelector map[string]string
+		wantParams       map[string]string
+		wantErr          string
+	}{
+		{
+			name: "no match",
+			rules: []v1alpha1.Rule{
+				{Paths: []string{"gpu/**"}, Namespace: "gpu", NodeSelector: map[string]string{"gpu": "true"}},
+			},
+			changedFiles: []string{"docs/README.md"},
+		},
+		{
+			name: "match nodeselector and namespace",
+			rules: []v1alpha1.Rule{
+				{Paths: []string{"gpu/**"}, Namespace: "gpu", NodeSelector: map[string]string{"gpu": "true"}},
+			},
+			changedFiles:     []string{"gpu/cuda/main.go"},
+			wantNamespace:    "gpu",
+			wantNodeSelector: map[string]string{"gpu": "true"},
+		},
+		{
+			name: "later rules override",
+			rules: []v1alpha1.Rule{
+				{Paths: []string{"**.go"}, Namespace: "go", Params: map[string]string{"arch": "amd64", "image": "golang"}},
+				{Paths: []string{"arm/**"}, Namespace: "arm", Params: map[string]string{"arch": "arm64"}},
+			},
+			changedFiles:  []string{"arm/main.go"},
+			wantNamespace: "arm",
+			wantParams:    map[string]string{"arch": "arm64", "image": "golang"},
+		},
+		{
+			name: "override existing param",
+			rules: []v1alpha1.Rule{
+				{Paths: []string{"arm/**"}, Params: map[string]string{"arch": "arm64"}},
+			},
+			params: []tektonv1beta1.Param{
+				{Name: "arch", Value: *tektonv1beta1.NewStructuredValues("amd64")},
+			},
+			changedFiles: []string{"arm/main.go"},
+			wantParams:   map[string]string{"arch": "arm64"},
+		},
+		{
+			name: "user target namespace wins",
+			rules: []v1alpha1.Rule{
+				{Paths: []string{"gpu/**"}, Namespace: "gpu"},
+			},
+			annotations:   map[string]string{apipac.TargetNamespace: "mine"},
+			changedFiles:  []string{"gpu/main.go"},
+			wantNamespace: "mine",
+		},
+		{
+			name: "bad glob",
+			rules: []v1alpha1.Rule{
+				{Paths: []string{"gpu/[**"}, Namespace: "gpu"},
+			},
+			changedFiles: []string{"gpu/main.go"},
+			wantErr:      "invalid glob gpu/[** in repository rules",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &tektonv1beta1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Spec:       tektonv1beta1.PipelineRunSpec{Params: tt.params},
+			}
+			err := applyRules([]*tektonv1beta1.PipelineRun{pr}, tt.rules, tt.changedFiles)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, pr.GetAnnotations()[apipac.TargetNamespace], tt.wantNamespace)
+			if tt.wantNodeSelector == nil {
+				assert.Assert(t, pr.Spec.PodTemplate == nil)
+			} else {
+				assert.DeepEqual(t, pr.Spec.PodTemplate.NodeSelector, tt.wantNodeSelector)
+			}
+			got := map[string]string{}
+			for _, p := range pr.Spec.Params {
+				got[p.Name] = p.Value.StringVal
+			}
+			if tt.wantParams == nil {
+				tt.wantParams = map[string]string{}
+				for _, p := range tt.params {
+					tt.wantParams[p.Name] = p.Value.StringVal
+				}
+			}
+			assert.DeepEqual(t, got, tt.wantParams)
+		})
+	}
+}
+
+func TestApplyParams(t *testing.T) {
+	pr := &tektonv1beta1.PipelineRun{
+		Spec: tektonv1beta1.PipelineRunSpec{Params: []tektonv1beta1.Param{
+			{Name: "registry", Value: *tektonv1beta1.NewStructuredValues("docker.io")},
+		}},
+	}
+	applyParams([]*tektonv1beta1.PipelineRun{pr}, map[string]string{"registry": "quay.io/org", "arch": "arm64"})
+	assert.DeepEqual(t, pr.Spec.Params, []tektonv1beta1.Param{
+		{Name: "registry", Value: *tektonv1beta1.NewStructuredValues("quay.io/org")},
+		{Name: "arch", Value: *tektonv1beta1.NewStructuredValues("arm64")},
+	})
+}
@@ -0,0 +1,61 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	ktypes "github.com/openshift-pipelines/pipelines-as-code/pkg/secrets/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var externalSecretsGVR = schema.GroupVersionResource{
+	Group:    "external-secrets.io",
+	Version:  "v1beta1",
+	Resource: "externalsecrets",
+}
+
+// externalSecretsFetcher fetches the secrets synced by the External Secrets
+// Operator, the name of the secret is the name of the ExternalSecret and the
+// value is read from its target Secret once it has been synced
+type externalSecretsFetcher struct {
+	dynamic dynamic.Interface
+	kint    kubeinteraction.Interface
+}
+
+func (e *externalSecretsFetcher) Fetch(ctx context.Context, opt ktypes.GetSecretOpt) (string, error) {
+	es, err := e.dynamic.Resource(externalSecretsGVR).Namespace(opt.Namespace).Get(ctx, opt.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("cannot get externalsecret %s/%s: %w", opt.Namespace, opt.Name, err)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(es.Object, "status", "conditions")
+	ready := false
+	message := "it has not been synced yet"
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		ready = condition["status"] == "True"
+		if m, ok := condition["message"].(string); ok && m != "" {
+			message = m
+		}
+	}
+	if !ready {
+		return "", fmt.Errorf("externalsecret %s/%s is not ready: %s", opt.Namespace, opt.Name, message)
+	}
+
+	target, _, _ := unstructured.NestedString(es.Object, "spec", "target", "name")
+	if target == "" {
+		target = opt.Name
+	}
+	return e.kint.GetSecret(ctx, ktypes.GetSecretOpt{
+		Namespace: opt.Namespace,
+		Name:      target,
+		Key:       opt.Key,
+	})
+}
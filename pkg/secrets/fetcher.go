@@ -0,0 +1,75 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	ktypes "github.com/openshift-pipelines/pipelines-as-code/pkg/secrets/types"
+)
+
+const (
+	SourceKubernetes      = "kubernetes"
+	SourceVault           = "vault"
+	SourceExternalSecrets = "external-secrets"
+)
+
+// SecretFetcher fetches the value of the key of a secret referenced by a
+// Repository, the secret is always in the namespace of the Repository
+type SecretFetcher interface {
+	Fetch(ctx context.Context, opt ktypes.GetSecretOpt) (string, error)
+}
+
+// kubernetesFetcher fetches the secrets from the Kubernetes Secrets
+type kubernetesFetcher struct {
+	kint kubeinteraction.Interface
+}
+
+func (k *kubernetesFetcher) Fetch(ctx context.Context, opt ktypes.GetSecretOpt) (string, error) {
+	return k.kint.GetSecret(ctx, opt)
+}
+
+// NewSecretFetcher returns the fetcher of the source of a secret, the Kubernetes
+// Secrets when the source is empty
+func NewSecretFetcher(run *params.Run, kint kubeinteraction.Interface, source string) (SecretFetcher, error) {
+	switch source {
+	case "", SourceKubernetes:
+		return &kubernetesFetcher{kint: kint}, nil
+	case SourceVault:
+		if run.Info.Pac == nil || run.Info.Pac.Settings == nil || run.Info.Pac.VaultAddress == "" {
+			return nil, fmt.Errorf("cannot fetch secrets from vault, vault-address is not set in the pipelines-as-code configmap")
+		}
+		return &vaultFetcher{
+			client:    run.Clients.HTTP,
+			address:   run.Info.Pac.VaultAddress,
+			role:      run.Info.Pac.VaultRole,
+			authMount: run.Info.Pac.VaultAuthMount,
+			kvMount:   run.Info.Pac.VaultKVMount,
+			tokenPath: serviceAccountTokenPath,
+		}, nil
+	case SourceExternalSecrets:
+		return &externalSecretsFetcher{dynamic: run.Clients.Dynamic, kint: kint}, nil
+	}
+	return nil, fmt.Errorf("unknown secret source %s, acceptable values: %s, %s or %s", source,
+		SourceKubernetes, SourceVault, SourceExternalSecrets)
+}
+
+// GetRepositorySecret fetches the value of a secret of a Repository from its
+// source, defaultKey is used when the secret does not specify a key
+func GetRepositorySecret(ctx context.Context, run *params.Run, kint kubeinteraction.Interface, namespace string, secret *v1alpha1.Secret, defaultKey string) (string, error) {
+	fetcher, err := NewSecretFetcher(run, kint, secret.Source)
+	if err != nil {
+		return "", err
+	}
+	key := secret.Key
+	if key == "" {
+		key = defaultKey
+	}
+	return fetcher.Fetch(ctx, ktypes.GetSecretOpt{
+		Namespace: namespace,
+		Name:      secret.Name,
+		Key:       key,
+	})
+}
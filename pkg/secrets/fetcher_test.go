@@ -0,0 +1,163 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestGetRepositorySecret(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	kint := &kubernetestint.KinterfaceTest{
+		GetSecretResult: map[string]string{
+			"token":        "kube-token",
+			"synced-token": "external-token",
+		},
+	}
+
+	makeES := func(name, target, status string) *unstructured.Unstructured {
+		es := &unstructured.Unstructured{}
+		es.SetUnstructuredContent(map[string]interface{}{
+			"apiVersion": "external-secrets.io/v1beta1",
+			"kind":       "ExternalSecret",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "ns"},
+			"spec":       map[string]interface{}{"target": map[string]interface{}{"name": target}},
+			"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": status, "message": "could not get secret data from provider"},
+			}},
+		})
+		return es
+	}
+	dynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{externalSecretsGVR: "ExternalSecretList"},
+		makeES("github", "synced-token", "True"), makeES("failing", "failing", "False"))
+
+	vaultServer := newVaultServer(t)
+	defer vaultServer.Close()
+	serviceAccountTokenPath = filepath.Join(t.TempDir(), "token")
+	assert.NilError(t, os.WriteFile(serviceAccountTokenPath, []byte("jwt\n"), 0o600))
+
+	tests := []struct {
+		name    string
+		secret  v1alpha1.Secret
+		noVault bool
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "kubernetes by default",
+			secret: v1alpha1.Secret{Name: "token"},
+			want:   "kube-token",
+		},
+		{
+			name:   "external secrets",
+			secret: v1alpha1.Secret{Name: "github", Source: SourceExternalSecrets},
+			want:   "external-token",
+		},
+		{
+			name:    "external secret not ready",
+			secret:  v1alpha1.Secret{Name: "failing", Source: SourceExternalSecrets},
+			wantErr: "externalsecret ns/failing is not ready: could not get secret data from provider",
+		},
+		{
+			name:    "external secret not there",
+			secret:  v1alpha1.Secret{Name: "notthere", Source: SourceExternalSecrets},
+			wantErr: "cannot get externalsecret ns/notthere",
+		},
+		{
+			name:   "vault",
+			secret: v1alpha1.Secret{Name: "github", Key: "token", Source: SourceVault},
+			want:   "vault-token",
+		},
+		{
+			name:    "vault with the default key",
+			secret:  v1alpha1.Secret{Name: "github", Source: SourceVault},
+			wantErr: "cannot find key provider.token in secret secret/data/ns/github from vault",
+		},
+		{
+			name:    "vault secret of another namespace",
+			secret:  v1alpha1.Secret{Name: "other", Key: "token", Source: SourceVault},
+			wantErr: "cannot read secret secret/data/ns/other from vault: vault answered with status 404 Not Found",
+		},
+		{
+			name:    "vault secret of another namespace with a relative path",
+			secret:  v1alpha1.Secret{Name: "../other/github", Key: "token", Source: SourceVault},
+			wantErr: `invalid vault secret name "../other/github"`,
+		},
+		{
+			name:    "vault not configured",
+			secret:  v1alpha1.Secret{Name: "github", Source: SourceVault},
+			noVault: true,
+			wantErr: "vault-address is not set in the pipelines-as-code configmap",
+		},
+		{
+			name:    "unknown source",
+			secret:  v1alpha1.Secret{Name: "github", Source: "cloud"},
+			wantErr: "unknown secret source cloud",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pac := &settings.Settings{
+				VaultAddress:   vaultServer.URL,
+				VaultRole:      "pac",
+				VaultAuthMount: "kubernetes",
+				VaultKVMount:   "secret",
+			}
+			if tt.noVault {
+				pac = &settings.Settings{}
+			}
+			run := &params.Run{
+				Clients: clients.Clients{Dynamic: dynClient, HTTP: *vaultServer.Client()},
+				Info:    info.Info{Pac: &info.PacOpts{Settings: pac}},
+			}
+			got, err := GetRepositorySecret(ctx, run, kint, "ns", &tt.secret, "provider.token")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+// newVaultServer fakes the login with the Kubernetes auth method and the KV
+// version 2 secrets engine of Vault
+func newVaultServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/auth/kubernetes/login", func(rw http.ResponseWriter, r *http.Request) {
+		login := map[string]string{}
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&login))
+		if login["role"] != "pac" || login["jwt"] != "jwt" {
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(rw, `{"auth": {"client_token": "s.token", "lease_duration": 3600}}`)
+	})
+	mux.HandleFunc("/v1/secret/data/ns/github", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(rw, `{"data": {"data": {"token": "vault-token"}}}`)
+	})
+	return httptest.NewServer(mux)
+}
@@ -0,0 +1,162 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/random"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	//nolint:gosec
+	registrySecretName = `pac-registry-%s`
+
+	// brokerTimeout is how long we wait for the broker to mint the
+	// credentials of a run
+	brokerTimeout = 10 * time.Second
+	// maxBrokerResponseSize is the maximum size of the answer of the broker
+	maxBrokerResponseSize = 1 << 20
+)
+
+// RegistryCredentialsRequest is what we send to the registry credentials
+// broker, it can scope the credentials it mints to the run
+type RegistryCredentialsRequest struct {
+	Namespace      string `json:"namespace"`
+	Repository     string `json:"repository"`
+	ServiceAccount string `json:"service_account"`
+	URL            string `json:"url"`
+	SHA            string `json:"sha"`
+	EventType      string `json:"event_type"`
+	Branch         string `json:"branch"`
+}
+
+// RegistryAuth is the credential of a registry, auth is computed from the
+// username and the password when the broker does not set it
+type RegistryAuth struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// RegistryCredentials is the answer of the broker, the auths of a docker
+// config.json
+type RegistryCredentials struct {
+	Auths map[string]RegistryAuth `json:"auths"`
+}
+
+// requestRegistryCredentials asks the broker to mint the registry credentials
+// of the run
+func requestRegistryCredentials(ctx context.Context, client http.Client, brokerURL string, request RegistryCredentialsRequest) (*RegistryCredentials, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, brokerTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, brokerURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot reach the registry credentials broker: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry credentials broker answered with status %s", res.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxBrokerResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	credentials := &RegistryCredentials{}
+	if err := json.Unmarshal(data, credentials); err != nil {
+		return nil, fmt.Errorf("cannot parse the answer of the registry credentials broker: %w", err)
+	}
+	if len(credentials.Auths) == 0 {
+		return nil, fmt.Errorf("registry credentials broker did not return any registry credential")
+	}
+	for registry, auth := range credentials.Auths {
+		if auth.Auth != "" {
+			continue
+		}
+		if auth.Username == "" || auth.Password == "" {
+			return nil, fmt.Errorf("registry credentials broker returned an empty credential for registry %s", registry)
+		}
+		auth.Auth = base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+		credentials.Auths[registry] = auth
+	}
+	return credentials, nil
+}
+
+// MakeRegistrySecret asks the broker to mint the registry credentials of the
+// run and make a dockerconfigjson secret with them
+func MakeRegistrySecret(ctx context.Context, client http.Client, brokerURL string, runevent *info.Event,
+	repo *v1alpha1.Repository, serviceAccount, secretName string,
+) (*corev1.Secret, error) {
+	credentials, err := requestRegistryCredentials(ctx, client, brokerURL, RegistryCredentialsRequest{
+		Namespace:      repo.GetNamespace(),
+		Repository:     repo.GetName(),
+		ServiceAccount: serviceAccount,
+		URL:            runevent.URL,
+		SHA:            runevent.SHA,
+		EventType:      runevent.EventType,
+		Branch:         runevent.BaseBranch,
+	})
+	if err != nil {
+		return nil, err
+	}
+	dockerConfig, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := map[string]string{
+		"pipelinesascode.tekton.dev/url": runevent.URL,
+		"pipelinesascode.tekton.dev/sha": runevent.SHA,
+	}
+
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by": pipelinesascode.GroupName,
+		keys.URLOrg:                    runevent.Organization,
+		keys.URLRepository:             runevent.Repository,
+	}
+
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        secretName,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: dockerConfig,
+		},
+	}, nil
+}
+
+func GenerateRegistrySecretName() string {
+	return strings.ToLower(
+		fmt.Sprintf(registrySecretName, random.AlphaString(4)))
+}
@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMakeRegistrySecret(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mint", func(rw http.ResponseWriter, r *http.Request) {
+		request := RegistryCredentialsRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Namespace != "ns" || request.ServiceAccount != "pipeline" {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(rw, `{"auths": {"registry.io": {"username": "user", "password": "token"}}}`)
+	})
+	mux.HandleFunc("/empty", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"auths": {}}`)
+	})
+	mux.HandleFunc("/nopassword", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"auths": {"registry.io": {"username": "user"}}}`)
+	})
+	mux.HandleFunc("/denied", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusForbidden)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	event := &info.Event{
+		Organization: "owner",
+		Repository:   "repo",
+		URL:          "https://forge/owner/repo",
+		SHA:          "sha",
+	}
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "repo",
+			Namespace: "ns",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "minted",
+			path: "/mint",
+			want: `{"auths":{"registry.io":{"username":"user","password":"token","auth":"dXNlcjp0b2tlbg=="}}}`,
+		},
+		{
+			name:    "no credentials",
+			path:    "/empty",
+			wantErr: "registry credentials broker did not return any registry credential",
+		},
+		{
+			name:    "empty credential",
+			path:    "/nopassword",
+			wantErr: "registry credentials broker returned an empty credential for registry registry.io",
+		},
+		{
+			name:    "denied",
+			path:    "/denied",
+			wantErr: "registry credentials broker answered with status 403 Forbidden",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret, err := MakeRegistrySecret(context.Background(), *server.Client(), server.URL+tt.path, event, repo, "pipeline", "pac-registry-test")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, secret.GetName(), "pac-registry-test")
+			assert.Equal(t, secret.Type, corev1.SecretTypeDockerConfigJson)
+			assert.Equal(t, string(secret.Data[corev1.DockerConfigJsonKey]), tt.want)
+		})
+	}
+}
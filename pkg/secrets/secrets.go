@@ -64,6 +64,10 @@ func GetSecretsAttachedToPipelineRun(ctx context.Context, k kubeinteraction.Inte
 // ReplaceSecretsInText this will take a text snippet and hide the leaked secret
 func ReplaceSecretsInText(text string, values []ktypes.SecretValue) string {
 	for _, sv := range values {
+		// an empty value would be replaced between every character
+		if sv.Value == "" {
+			continue
+		}
 		text = strings.ReplaceAll(text, sv.Value, leakedReplacement)
 	}
 	return text
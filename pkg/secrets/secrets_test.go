@@ -202,6 +202,17 @@ func TestReplaceSecretsInText(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "empty secret",
+			text:   "I am beautiful",
+			result: "I am beautiful",
+			values: []types.SecretValue{
+				{
+					Name:  "empty-secret",
+					Value: "",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,155 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	ktypes "github.com/openshift-pipelines/pipelines-as-code/pkg/secrets/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// serviceAccountTokenPath is the token of the ServiceAccount of the controller
+// we login to Vault with
+//
+//nolint:gosec
+var serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// vaultTimeout is how long we wait for an answer of Vault
+const vaultTimeout = 10 * time.Second
+
+type vaultToken struct {
+	token   string
+	expires time.Time
+}
+
+// vaultTokens cache the Vault tokens of the controller, we would otherwise
+// login to Vault for every secret
+var vaultTokens = struct {
+	sync.Mutex
+	tokens map[string]vaultToken
+}{tokens: map[string]vaultToken{}}
+
+// vaultFetcher fetches the secrets from the KV version 2 secrets engine of
+// Vault, the controller logs in with its ServiceAccount token with the
+// Kubernetes auth method. The secrets are read under the namespace of the
+// Repository so a Repository cannot read the secrets of another namespace.
+type vaultFetcher struct {
+	client    http.Client
+	address   string
+	role      string
+	authMount string
+	kvMount   string
+	tokenPath string
+}
+
+func (v *vaultFetcher) cacheKey() string {
+	return fmt.Sprintf("%s/%s/%s", v.address, v.authMount, v.role)
+}
+
+func (v *vaultFetcher) do(ctx context.Context, method, path, token string, body interface{}, out interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(b)
+	}
+	ctx, cancel := context.WithTimeout(ctx, vaultTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(v.address, "/")+"/v1/"+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	res, err := v.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("cannot reach vault: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return res.StatusCode, fmt.Errorf("vault answered with status %s", res.Status)
+	}
+	return res.StatusCode, json.NewDecoder(res.Body).Decode(out)
+}
+
+// login returns the token of the controller, from the cache when it has not
+// expired
+func (v *vaultFetcher) login(ctx context.Context) (string, error) {
+	vaultTokens.Lock()
+	defer vaultTokens.Unlock()
+	if cached, ok := vaultTokens.tokens[v.cacheKey()]; ok && time.Now().Before(cached.expires) {
+		return cached.token, nil
+	}
+
+	jwt, err := os.ReadFile(v.tokenPath)
+	if err != nil {
+		return "", fmt.Errorf("cannot read the service account token to login to vault: %w", err)
+	}
+	login := struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}{}
+	if _, err := v.do(ctx, http.MethodPost, fmt.Sprintf("auth/%s/login", v.authMount), "",
+		map[string]string{"role": v.role, "jwt": strings.TrimSpace(string(jwt))}, &login); err != nil {
+		return "", fmt.Errorf("cannot login to vault with role %s: %w", v.role, err)
+	}
+	if login.Auth.ClientToken == "" {
+		return "", fmt.Errorf("cannot login to vault with role %s: no token returned", v.role)
+	}
+	// renew the token before it expires
+	vaultTokens.tokens[v.cacheKey()] = vaultToken{
+		token:   login.Auth.ClientToken,
+		expires: time.Now().Add(time.Duration(login.Auth.LeaseDuration) * time.Second * 8 / 10),
+	}
+	return login.Auth.ClientToken, nil
+}
+
+func (v *vaultFetcher) Fetch(ctx context.Context, opt ktypes.GetSecretOpt) (string, error) {
+	// the name is a part of the path of the secret, a path going up would
+	// read the secrets of another namespace
+	if errs := validation.IsDNS1123Subdomain(opt.Name); len(errs) > 0 {
+		return "", fmt.Errorf("invalid vault secret name %q: %s", opt.Name, strings.Join(errs, ", "))
+	}
+	token, err := v.login(ctx)
+	if err != nil {
+		return "", err
+	}
+	path := fmt.Sprintf("%s/data/%s/%s", v.kvMount, opt.Namespace, opt.Name)
+	secret := struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}{}
+	status, err := v.do(ctx, http.MethodGet, path, token, nil, &secret)
+	if err != nil {
+		// the token may have been revoked, login again next time
+		if status == http.StatusForbidden {
+			vaultTokens.Lock()
+			delete(vaultTokens.tokens, v.cacheKey())
+			vaultTokens.Unlock()
+		}
+		return "", fmt.Errorf("cannot read secret %s from vault: %w", path, err)
+	}
+	value, ok := secret.Data.Data[opt.Key]
+	if !ok {
+		return "", fmt.Errorf("cannot find key %s in secret %s from vault", opt.Key, path)
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("key %s in secret %s from vault is not a string", opt.Key, path)
+	}
+	return s, nil
+}
@@ -0,0 +1,49 @@
+package sort
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const taskProgressHeader = "<br><br><b>Completed tasks %d/%d</b>\n\n"
+
+// TaskProgress render the task table of a running PipelineRun with the task
+// status template of the git provider, as for its final status, the taskruns
+// still running have the duration they have been running for so far. It
+// returns the progress of the taskruns as well, how many have started and
+// completed, so the callers can skip the update when no taskrun has started or
+// completed since the last one.
+func TaskProgress(pr *tektonv1beta1.PipelineRun, trStatus map[string]*tektonv1beta1.PipelineRunTaskRunStatus, runs *params.Run, config *info.ProviderConfig) (string, string, error) {
+	started, completed := 0, 0
+	for _, taskrunStatus := range trStatus {
+		if taskrunStatus.Status == nil || taskrunStatus.Status.StartTime.IsZero() {
+			continue
+		}
+		started++
+		if !taskrunStatus.Status.CompletionTime.IsZero() {
+			completed++
+		}
+	}
+	if started == 0 {
+		return "", "", nil
+	}
+
+	now := &metav1.Time{Time: time.Now()}
+	table, err := taskStatusTmpl(pr, trStatus, runs, config, func(t1, t2 *metav1.Time) string {
+		if t2.IsZero() {
+			return formatting.Duration(t1, now)
+		}
+		return formatting.Duration(t1, t2)
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf(taskProgressHeader, completed, len(trStatus)) + table,
+		fmt.Sprintf("%d started, %d completed", started, completed), nil
+}
@@ -0,0 +1,92 @@
+package sort
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tektontest "github.com/openshift-pipelines/pipelines-as-code/pkg/test/tekton"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTaskProgress(t *testing.T) {
+	flattedTmpl := `{{- range $taskrun := .TaskRunList }}{{ formatCondition $taskrun.PipelineRunTaskRunStatus.Status.Conditions }} {{ $taskrun.ConsoleLogURL }} {{ formatDuration $taskrun.PipelineRunTaskRunStatus.Status.StartTime $taskrun.PipelineRunTaskRunStatus.Status.CompletionTime }}|{{- end }}`
+	running := tektontest.MakePrTrStatus("running", -1)
+	running.Status.StartTime = &metav1.Time{Time: time.Now().Add(-5 * time.Minute)}
+	running.Status.Conditions[0].Status = corev1.ConditionUnknown
+	pending := tektontest.MakePrTrStatus("pending", -1)
+	pending.Status.StartTime = nil
+
+	tests := []struct {
+		name       string
+		trStatus   map[string]*tektonv1beta1.PipelineRunTaskRunStatus
+		tmpl       string
+		skipEmoji  bool
+		wantState  string
+		wantErr    bool
+		wantRegexp *regexp.Regexp
+	}{
+		{
+			name: "nothing started yet",
+			trStatus: map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+				"pending": pending,
+			},
+		},
+		{
+			name: "running tasks with their duration so far",
+			trStatus: map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+				"first":   tektontest.MakePrTrStatus("first", 5),
+				"running": running,
+				"pending": pending,
+			},
+			wantState:  "2 started, 1 completed",
+			wantRegexp: regexp.MustCompile(`(?s)Completed tasks 1/3.*✅ Succeeded \[first\]\(.*\) 10 minutes.*🏃 Running \[running\]\(.*\) 5 minutes`),
+		},
+		{
+			name: "without emoji",
+			trStatus: map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+				"first": tektontest.MakePrTrStatus("first", 5),
+			},
+			skipEmoji:  true,
+			wantState:  "1 started, 1 completed",
+			wantRegexp: regexp.MustCompile(`\|?Succeeded \[first\]`),
+		},
+		{
+			name: "bad template",
+			trStatus: map[string]*tektonv1beta1.PipelineRunTaskRunStatus{
+				"first": tektontest.MakePrTrStatus("first", 5),
+			},
+			tmpl:    "{{.XXX}}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := params.New()
+			runs.Clients.ConsoleUI = consoleui.FallBackConsole{}
+			pr := tektontest.MakePR("ns", "pr", tt.trStatus, nil)
+			tmpl := flattedTmpl
+			if tt.tmpl != "" {
+				tmpl = tt.tmpl
+			}
+			output, state, err := TaskProgress(pr, tt.trStatus, runs, &info.ProviderConfig{SkipEmoji: tt.skipEmoji, TaskStatusTMPL: tmpl})
+			if tt.wantErr {
+				assert.Assert(t, err != nil)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, state, tt.wantState)
+			if tt.wantRegexp == nil {
+				assert.Equal(t, output, "")
+				return
+			}
+			assert.Assert(t, tt.wantRegexp.MatchString(output), output)
+		})
+	}
+}
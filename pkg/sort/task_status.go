@@ -10,6 +10,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type tkr struct {
@@ -39,6 +40,10 @@ func (trs taskrunList) Less(i, j int) bool {
 
 // TaskStatusTmpl generate a template of all status of a taskruns sorted to a statusTemplate as defined by the git provider
 func TaskStatusTmpl(pr *tektonv1beta1.PipelineRun, trStatus map[string]*tektonv1beta1.PipelineRunTaskRunStatus, runs *params.Run, config *info.ProviderConfig) (string, error) {
+	return taskStatusTmpl(pr, trStatus, runs, config, formatting.Duration)
+}
+
+func taskStatusTmpl(pr *tektonv1beta1.PipelineRun, trStatus map[string]*tektonv1beta1.PipelineRunTaskRunStatus, runs *params.Run, config *info.ProviderConfig, formatDuration func(t1, t2 *metav1.Time) string) (string, error) {
 	trl := taskrunList{}
 	outputBuffer := bytes.Buffer{}
 
@@ -59,7 +64,7 @@ func TaskStatusTmpl(pr *tektonv1beta1.PipelineRun, trStatus map[string]*tektonv1
 	sort.Sort(sort.Reverse(trl))
 
 	funcMap := template.FuncMap{
-		"formatDuration":  formatting.Duration,
+		"formatDuration":  formatDuration,
 		"formatCondition": formatting.ConditionEmoji,
 	}
 
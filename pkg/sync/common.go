@@ -16,4 +16,5 @@ type Semaphore interface {
 	getLimit() int
 	getCurrentRunning() []string
 	getCurrentPending() []string
+	getQueuedTime(string) time.Time
 }
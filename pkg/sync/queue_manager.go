@@ -11,6 +11,8 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/generated/clientset/versioned"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/policy"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/sort"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	versioned2 "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
@@ -25,15 +27,19 @@ const (
 
 type QueueManager struct {
 	queueMap map[string]Semaphore
-	lock     *sync.Mutex
-	logger   *zap.SugaredLogger
+	// fanOutMap are the queues of the PipelineRuns matched by the same event
+	// for the repositories with a max parallel, by repository and event
+	fanOutMap map[string]Semaphore
+	lock      *sync.Mutex
+	logger    *zap.SugaredLogger
 }
 
 func NewQueueManager(logger *zap.SugaredLogger) *QueueManager {
 	return &QueueManager{
-		queueMap: make(map[string]Semaphore),
-		lock:     &sync.Mutex{},
-		logger:   logger,
+		queueMap:  make(map[string]Semaphore),
+		fanOutMap: make(map[string]Semaphore),
+		lock:      &sync.Mutex{},
+		logger:    logger,
 	}
 }
 
@@ -61,6 +67,70 @@ func repoKey(repo *v1alpha1.Repository) string {
 	return fmt.Sprintf("%s/%s", repo.Namespace, repo.Name)
 }
 
+// fanOutKey returns the key of the queue of the PipelineRuns of an event, the
+// PipelineRuns of an event share the same execution order so the first one of
+// it identifies the event.
+func fanOutKey(repo *v1alpha1.Repository, order string) string {
+	return fmt.Sprintf("%s#%s", repoKey(repo), strings.Split(order, ",")[0])
+}
+
+func hasMaxParallel(repo *v1alpha1.Repository) bool {
+	return repo.Spec.MaxParallel != nil && *repo.Spec.MaxParallel > 0
+}
+
+func hasConcurrencyLimit(repo *v1alpha1.Repository) bool {
+	return repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit != 0
+}
+
+// admitFanOut adds the PipelineRuns of an event to its queue when the
+// repository has a max parallel and returns the ones newly admitted to run,
+// all of them when it has none.
+func (qm *QueueManager) admitFanOut(repo *v1alpha1.Repository, list []string) []string {
+	if !hasMaxParallel(repo) || len(list) == 0 {
+		return list
+	}
+	key := fanOutKey(repo, list[0])
+	sema, found := qm.fanOutMap[key]
+	if !found {
+		sema = newSemaphore(key, *repo.Spec.MaxParallel)
+		qm.fanOutMap[key] = sema
+	}
+	for _, pr := range list {
+		sema.addToQueue(pr, time.Now())
+	}
+	admitted := []string{}
+	for acquired := sema.acquireLatest(); acquired != ""; acquired = sema.acquireLatest() {
+		qm.logger.Infof("admitted (%s) to run within the max parallel of repository (%s)", acquired, repoKey(repo))
+		admitted = append(admitted, acquired)
+	}
+	return admitted
+}
+
+// releaseFanOut removes a PipelineRun from the queue of its event and returns
+// the next one of the event admitted to run or "".
+func (qm *QueueManager) releaseFanOut(repo *v1alpha1.Repository, run *v1beta1.PipelineRun) string {
+	order, ok := run.GetAnnotations()[keys.ExecutionOrder]
+	if !ok {
+		return ""
+	}
+	key := fanOutKey(repo, order)
+	sema, found := qm.fanOutMap[key]
+	if !found {
+		return ""
+	}
+	qKey := getQueueKey(run)
+	sema.release(qKey)
+	sema.removeFromQueue(qKey)
+	next := sema.acquireLatest()
+	if next != "" {
+		qm.logger.Infof("admitted (%s) to run within the max parallel of repository (%s)", next, repoKey(repo))
+	}
+	if len(sema.getCurrentRunning()) == 0 && len(sema.getCurrentPending()) == 0 {
+		delete(qm.fanOutMap, key)
+	}
+	return next
+}
+
 func (qm *QueueManager) checkAndUpdateSemaphoreSize(repo *v1alpha1.Repository, semaphore Semaphore) error {
 	limit := *repo.Spec.ConcurrencyLimit
 	if limit != semaphore.getLimit() {
@@ -75,11 +145,18 @@ func (qm *QueueManager) checkAndUpdateSemaphoreSize(repo *v1alpha1.Repository, s
 // AddListToQueue adds the pipelineRun to the waiting queue of the repository
 // and if it is at the top and ready to run which means currently running pipelineRun < limit
 // then move it to running queue
-// This adds the pipelineRuns in the same order as in the list
+// This adds the pipelineRuns in the same order as in the list. With a max
+// parallel, only the pipelineRuns admitted by the queue of their event join
+// the queue of the repository.
 func (qm *QueueManager) AddListToQueue(repo *v1alpha1.Repository, list []string) ([]string, error) {
 	qm.lock.Lock()
 	defer qm.lock.Unlock()
 
+	list = qm.admitFanOut(repo, list)
+	if !hasConcurrencyLimit(repo) {
+		return list, nil
+	}
+
 	sema, err := qm.getSemaphore(repo)
 	if err != nil {
 		return []string{}, err
@@ -111,9 +188,10 @@ func (qm *QueueManager) RemoveFromQueue(repo *v1alpha1.Repository, run *v1beta1.
 	defer qm.lock.Unlock()
 
 	repoKey := repoKey(repo)
+	admitted := qm.releaseFanOut(repo, run)
 	sema, found := qm.queueMap[repoKey]
 	if !found {
-		return ""
+		return admitted
 	}
 
 	qKey := getQueueKey(run)
@@ -121,6 +199,10 @@ func (qm *QueueManager) RemoveFromQueue(repo *v1alpha1.Repository, run *v1beta1.
 	sema.removeFromQueue(qKey)
 	qm.logger.Infof("removed (%s) for repository (%s)", qKey, repoKey)
 
+	// the next pipelineRun of the event waits for the concurrency limit too
+	if admitted != "" {
+		sema.addToQueue(admitted, time.Now())
+	}
 	if next := sema.acquireLatest(); next != "" {
 		qm.logger.Infof("moved (%s) to running for repository (%s)", next, repoKey)
 		return next
@@ -133,7 +215,9 @@ func getQueueKey(run *v1beta1.PipelineRun) string {
 }
 
 // InitQueues rebuild all the queues for all repository if concurrency is defined before
-// reconciler started reconciling them
+// reconciler started reconciling them. The order persisted in the queue status
+// of the Repository is restored first, the PipelineRuns it doesn't know are
+// queued by their execution order.
 func (qm *QueueManager) InitQueues(ctx context.Context, tekton versioned2.Interface, pac versioned.Interface) error {
 	// fetch all repos
 	repos, err := pac.PipelinesascodeV1alpha1().Repositories("").List(ctx, v1.ListOptions{})
@@ -141,69 +225,183 @@ func (qm *QueueManager) InitQueues(ctx context.Context, tekton versioned2.Interf
 		return err
 	}
 
+	policies, err := policy.List(ctx, pac)
+	if err != nil {
+		return err
+	}
+
 	// pipelineRuns from the namespace where repository is present
 	// those are required for creating queues
-	for _, repo := range repos.Items {
-		repo := repo
-		if repo.Spec.ConcurrencyLimit == nil || *repo.Spec.ConcurrencyLimit == 0 {
+	for i := range repos.Items {
+		repo := *policy.Apply(&repos.Items[i], policies)
+		if !hasConcurrencyLimit(&repo) && !hasMaxParallel(&repo) {
 			continue
 		}
 
-		// add all pipelineRuns in started state to pending queue
+		// fetch all the pipelineRuns in started or queued state
 		prs, err := tekton.TektonV1beta1().PipelineRuns(repo.Namespace).
 			List(ctx, v1.ListOptions{
-				LabelSelector: fmt.Sprintf("%s=%s", keys.State, kubeinteraction.StateStarted),
+				LabelSelector: fmt.Sprintf("%s in (%s,%s)", keys.State, kubeinteraction.StateStarted, kubeinteraction.StateQueued),
 			})
 		if err != nil {
 			return err
 		}
+		sorted := sortPipelineRunsByCreationTimestamp(prs.Items)
+		if hasConcurrencyLimit(&repo) {
+			qm.restoreQueue(&repo, sorted)
+		}
+		if hasMaxParallel(&repo) {
+			qm.restoreFanOut(&repo, sorted)
+		}
+	}
 
-		// sort the pipelinerun by creation time before adding to queue
-		sortedPRs := sortPipelineRunsByCreationTimestamp(prs.Items)
+	return nil
+}
 
-		for _, pr := range sortedPRs {
-			pr := pr
-			order, exist := pr.GetAnnotations()[keys.ExecutionOrder]
-			if !exist {
-				// if the pipelineRun doesn't have order label then wait
-				return nil
-			}
-			orderedList := strings.Split(order, ",")
-			_, err = qm.AddListToQueue(&repo, orderedList)
-			if err != nil {
-				qm.logger.Error("failed to init queue for repo: ", repo.GetName())
+// restoreQueue adds the started pipelineRuns to the running queue and the
+// queued ones to the pending queue, the queued pipelineRuns are then started
+// when they are reconciled. The pipelineRuns still in their freeze window are
+// not restored, they join the queue when the window has ended.
+func (qm *QueueManager) restoreQueue(repo *v1alpha1.Repository, prs []*v1beta1.PipelineRun) {
+	// the queues of the other controllers of the cluster are managed by their
+	// own watcher
+	controllerName := info.ControllerName()
+
+	order := []string{}
+	queuedTimes := map[string]time.Time{}
+	if repo.QueueStatus != nil {
+		order = append(order, repo.QueueStatus.Running...)
+		for _, pending := range repo.QueueStatus.Pending {
+			order = append(order, pending.Name)
+			if pending.QueuedTime != nil {
+				queuedTimes[pending.Name] = pending.QueuedTime.Time
 			}
 		}
-
-		// now fetch all queued pipelineRun
-		prs, err = tekton.TektonV1beta1().PipelineRuns(repo.Namespace).
-			List(ctx, v1.ListOptions{
-				LabelSelector: fmt.Sprintf("%s=%s", keys.State, kubeinteraction.StateQueued),
-			})
-		if err != nil {
-			return err
+	}
+	states := map[string]string{}
+	for _, pr := range prs {
+		if !kubeinteraction.OwnedByController(pr.GetLabels(), controllerName) {
+			continue
 		}
+		executionOrder, exist := pr.GetAnnotations()[keys.ExecutionOrder]
+		if !exist {
+			// if the pipelineRun doesn't have order label then wait
+			continue
+		}
+		if until, err := time.Parse(time.RFC3339, pr.GetAnnotations()[keys.FrozenUntil]); err == nil && time.Now().Before(until) {
+			continue
+		}
+		states[getQueueKey(pr)] = pr.GetLabels()[keys.State]
+		order = append(order, strings.Split(executionOrder, ",")...)
+	}
 
-		// sort the pipelinerun by creation time before adding to queue
-		sortedPRs = sortPipelineRunsByCreationTimestamp(prs.Items)
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+	sema, err := qm.getSemaphore(repo)
+	if err != nil {
+		qm.logger.Error("failed to init queue for repo: ", repo.GetName())
+		return
+	}
 
-		for _, pr := range sortedPRs {
-			pr := pr
-			order, exist := pr.GetAnnotations()[keys.ExecutionOrder]
-			if !exist {
-				// if the pipelineRun doesn't have order label then wait
-				return nil
+	// the pipelineRuns of the order which are not started or queued anymore
+	// are skipped, they are done or deleted
+	queued := []string{}
+	seen := map[string]bool{}
+	for _, key := range order {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		switch states[key] {
+		case kubeinteraction.StateStarted:
+			sema.addToQueue(key, time.Now())
+			if sema.acquireLatest() != key {
+				sema.removeFromQueue(key)
+				qm.logger.Warnf("cannot restore the running pipelineRun (%s) for repository (%s), the concurrency limit has been lowered", key, repoKey(repo))
 			}
-			orderedList := strings.Split(order, ",")
+		case kubeinteraction.StateQueued:
+			queued = append(queued, key)
+		}
+	}
+	// the pipelineRuns keep the time they have been queued, the persisted
+	// time is at the second so it is only used when it keeps the order
+	previous := time.Time{}
+	for _, key := range queued {
+		added, ok := queuedTimes[key]
+		if !ok {
+			added = time.Now()
+		}
+		if !added.After(previous) {
+			added = previous.Add(time.Nanosecond)
+		}
+		sema.addToQueue(key, added)
+		previous = added
+	}
+	qm.logger.Infof("restored the queue of repository (%s) with %d running and %d pending pipelineRuns",
+		repoKey(repo), len(sema.getCurrentRunning()), len(queued))
+}
 
-			_, err = qm.AddListToQueue(&repo, orderedList)
-			if err != nil {
-				qm.logger.Error("failed to init queue for repo: ", repo.GetName())
-			}
+// restoreFanOut marks the pipelineRuns admitted by the queue of their event as
+// running in it, the started ones and the ones waiting in the queue of the
+// repository. The others are queued again when they are reconciled.
+func (qm *QueueManager) restoreFanOut(repo *v1alpha1.Repository, prs []*v1beta1.PipelineRun) {
+	controllerName := info.ControllerName()
+
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	waiting := map[string]bool{}
+	if sema, ok := qm.queueMap[repoKey(repo)]; ok {
+		for _, key := range sema.getCurrentPending() {
+			waiting[key] = true
 		}
 	}
+	for _, pr := range prs {
+		if !kubeinteraction.OwnedByController(pr.GetLabels(), controllerName) {
+			continue
+		}
+		order, exist := pr.GetAnnotations()[keys.ExecutionOrder]
+		if !exist {
+			continue
+		}
+		key := getQueueKey(pr)
+		if pr.GetLabels()[keys.State] != kubeinteraction.StateStarted && !waiting[key] {
+			continue
+		}
+		fanKey := fanOutKey(repo, order)
+		sema, found := qm.fanOutMap[fanKey]
+		if !found {
+			sema = newSemaphore(fanKey, *repo.Spec.MaxParallel)
+			qm.fanOutMap[fanKey] = sema
+		}
+		sema.addToQueue(key, time.Now())
+		if sema.acquireLatest() != key {
+			sema.removeFromQueue(key)
+			qm.logger.Warnf("cannot restore the running pipelineRun (%s) for repository (%s), the max parallel has been lowered", key, repoKey(repo))
+		}
+	}
+}
 
-	return nil
+// QueueStatus returns the queue of the repository to be persisted in its
+// status, nil when the repository has no queue.
+func (qm *QueueManager) QueueStatus(repo *v1alpha1.Repository) *v1alpha1.RepositoryQueueStatus {
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	sema, ok := qm.queueMap[repoKey(repo)]
+	if !ok {
+		return nil
+	}
+	status := &v1alpha1.RepositoryQueueStatus{Running: sema.getCurrentRunning()}
+	for i, key := range sema.getCurrentPending() {
+		queuedTime := v1.NewTime(sema.getQueuedTime(key))
+		status.Pending = append(status.Pending, v1alpha1.QueuedPipelineRun{
+			Name:       key,
+			Position:   i + 1,
+			QueuedTime: &queuedTime,
+		})
+	}
+	return status
 }
 
 func (qm *QueueManager) RemoveRepository(repo *v1alpha1.Repository) {
@@ -212,6 +410,21 @@ func (qm *QueueManager) RemoveRepository(repo *v1alpha1.Repository) {
 
 	repoKey := repoKey(repo)
 	delete(qm.queueMap, repoKey)
+	for key := range qm.fanOutMap {
+		if strings.HasPrefix(key, repoKey+"#") {
+			delete(qm.fanOutMap, key)
+		}
+	}
+}
+
+// Reset drops all the queues, they are rebuilt by InitQueues when the watcher
+// becomes the leader as they may have changed while it was not.
+func (qm *QueueManager) Reset() {
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	qm.queueMap = make(map[string]Semaphore)
+	qm.fanOutMap = make(map[string]Semaphore)
 }
 
 func (qm *QueueManager) QueuedPipelineRuns(repo *v1alpha1.Repository) []string {
@@ -1,6 +1,7 @@
 package sync
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -120,6 +121,87 @@ func TestNewQueueManagerReListing(t *testing.T) {
 	assert.Equal(t, len(qm.QueuedPipelineRuns(repo)), 4)
 }
 
+func TestQueueManagerMaxParallel(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	qm := NewQueueManager(logger)
+
+	repo := newTestRepo("test", 0)
+	repo.Spec.ConcurrencyLimit = nil
+	repo.Spec.MaxParallel = intPtr(2)
+
+	order := "test-ns/first,test-ns/second,test-ns/third"
+	event := map[string]string{keys.ExecutionOrder: order}
+	prFirst := newTestPR("first", time.Now(), nil, event)
+	prSecond := newTestPR("second", time.Now(), nil, event)
+	prThird := newTestPR("third", time.Now(), nil, event)
+
+	started, err := qm.AddListToQueue(repo, strings.Split(order, ","))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{"test-ns/first", "test-ns/second"})
+	started, err = qm.AddListToQueue(repo, strings.Split(order, ","))
+	assert.NilError(t, err)
+	assert.Equal(t, len(started), 0)
+
+	// the pipelineRuns of another event have their own max parallel
+	other := newTestPR("other", time.Now(), nil, map[string]string{keys.ExecutionOrder: "test-ns/other"})
+	started, err = qm.AddListToQueue(repo, []string{getQueueKey(other)})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{"test-ns/other"})
+	assert.Equal(t, qm.RemoveFromQueue(repo, other), "")
+
+	assert.Equal(t, qm.RemoveFromQueue(repo, prFirst), "test-ns/third")
+	assert.Equal(t, qm.RemoveFromQueue(repo, prSecond), "")
+	assert.Equal(t, qm.RemoveFromQueue(repo, prThird), "")
+	assert.Equal(t, len(qm.fanOutMap), 0)
+}
+
+func TestQueueManagerMaxParallelAndConcurrencyLimit(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	qm := NewQueueManager(logger)
+
+	repo := newTestRepo("test", 1)
+	repo.Spec.MaxParallel = intPtr(2)
+
+	order := "test-ns/first,test-ns/second,test-ns/third"
+	event := map[string]string{keys.ExecutionOrder: order}
+	prFirst := newTestPR("first", time.Now(), nil, event)
+	prSecond := newTestPR("second", time.Now(), nil, event)
+
+	started, err := qm.AddListToQueue(repo, strings.Split(order, ","))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{"test-ns/first"})
+	// only the pipelineRuns admitted by the max parallel wait for the
+	// concurrency limit
+	assert.DeepEqual(t, qm.QueuedPipelineRuns(repo), []string{"test-ns/second"})
+
+	// the third pipelineRun is admitted and waits for the second one
+	assert.Equal(t, qm.RemoveFromQueue(repo, prFirst), "test-ns/second")
+	assert.DeepEqual(t, qm.QueuedPipelineRuns(repo), []string{"test-ns/third"})
+	assert.Equal(t, qm.RemoveFromQueue(repo, prSecond), "test-ns/third")
+}
+
+func TestQueueManagerRestoreFanOut(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	qm := NewQueueManager(logger)
+
+	repo := newTestRepo("test", 0)
+	repo.Spec.ConcurrencyLimit = nil
+	repo.Spec.MaxParallel = intPtr(1)
+
+	event := map[string]string{keys.ExecutionOrder: "test-ns/first,test-ns/second"}
+	prFirst := newTestPR("first", time.Now(), map[string]string{keys.State: kubeinteraction.StateStarted}, event)
+	prSecond := newTestPR("second", time.Now(), map[string]string{keys.State: kubeinteraction.StateQueued}, event)
+	qm.restoreFanOut(repo, []*v1beta1.PipelineRun{prFirst, prSecond})
+
+	started, err := qm.AddListToQueue(repo, []string{"test-ns/first", "test-ns/second"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(started), 0, "the first pipelineRun is still running")
+	assert.Equal(t, qm.RemoveFromQueue(repo, prFirst), "test-ns/second")
+}
+
 func newTestRepo(name string, limit int) *v1alpha1.Repository {
 	return &v1alpha1.Repository{
 		ObjectMeta: metav1.ObjectMeta{
@@ -199,4 +281,97 @@ func TestQueueManager_InitQueues(t *testing.T) {
 	// list current pending pipelineRuns for repo
 	runs = qm.QueuedPipelineRuns(repo)
 	assert.Equal(t, len(runs), 1)
+
+	// the queues are rebuilt from the PipelineRuns once reset
+	qm.Reset()
+	assert.Equal(t, len(qm.RunningPipelineRuns(repo)), 0)
+	assert.NilError(t, qm.InitQueues(ctx, stdata.Pipeline, stdata.PipelineAsCode))
+	assert.Equal(t, len(qm.RunningPipelineRuns(repo)), 1)
+	assert.Equal(t, len(qm.QueuedPipelineRuns(repo)), 2)
+}
+
+func TestQueueManager_InitQueuesFromQueueStatus(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	logger := zap.NewNop().Sugar()
+	cw := clockwork.NewFakeClock()
+
+	startedLabel := map[string]string{keys.State: kubeinteraction.StateStarted}
+	queuedLabel := map[string]string{keys.State: kubeinteraction.StateQueued}
+	completedLabel := map[string]string{keys.State: kubeinteraction.StateCompleted}
+	annotations := map[string]string{
+		keys.ExecutionOrder: "test-ns/first,test-ns/second,test-ns/third,test-ns/fourth",
+	}
+
+	// the persisted order prevails over the execution order, the pipelineRuns
+	// done or deleted since are skipped
+	repo := newTestRepo("test", 1)
+	queuedTime := metav1.NewTime(cw.Now().Add(-time.Hour))
+	repo.QueueStatus = &v1alpha1.RepositoryQueueStatus{
+		Running: []string{"test-ns/second"},
+		Pending: []v1alpha1.QueuedPipelineRun{
+			{Name: "test-ns/deleted", Position: 1},
+			{Name: "test-ns/fourth", Position: 2, QueuedTime: &queuedTime},
+			{Name: "test-ns/first", Position: 3},
+			{Name: "test-ns/third", Position: 4, QueuedTime: &queuedTime},
+		},
+	}
+	tdata := testclient.Data{
+		Repositories: []*v1alpha1.Repository{repo},
+		PipelineRuns: []*v1beta1.PipelineRun{
+			newTestPR("first", cw.Now(), completedLabel, annotations),
+			newTestPR("second", cw.Now(), startedLabel, annotations),
+			newTestPR("third", cw.Now(), queuedLabel, annotations),
+			newTestPR("fourth", cw.Now(), queuedLabel, annotations),
+		},
+	}
+	stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+
+	qm := NewQueueManager(logger)
+	assert.NilError(t, qm.InitQueues(ctx, stdata.Pipeline, stdata.PipelineAsCode))
+	assert.DeepEqual(t, qm.RunningPipelineRuns(repo), []string{"test-ns/second"})
+	assert.DeepEqual(t, qm.QueuedPipelineRuns(repo), []string{"test-ns/fourth", "test-ns/third"})
+
+	// the pipelineRuns keep the time they have been queued
+	status := qm.QueueStatus(repo)
+	assert.DeepEqual(t, status.Running, []string{"test-ns/second"})
+	assert.Equal(t, len(status.Pending), 2)
+	for i, name := range []string{"test-ns/fourth", "test-ns/third"} {
+		assert.Equal(t, status.Pending[i].Name, name)
+		assert.Equal(t, status.Pending[i].Position, i+1)
+		assert.Equal(t, status.Pending[i].QueuedTime.Unix(), queuedTime.Unix())
+	}
+}
+
+func TestQueueManager_InitQueuesFrozen(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	logger := zap.NewNop().Sugar()
+	now := time.Now()
+
+	startedLabel := map[string]string{keys.State: kubeinteraction.StateStarted}
+	queuedLabel := map[string]string{keys.State: kubeinteraction.StateQueued}
+	order := "test-ns/first,test-ns/second,test-ns/third"
+
+	// the pipelineRuns still frozen join the queue when their freeze window
+	// has ended, not before
+	repo := newTestRepo("test", 1)
+	tdata := testclient.Data{
+		Repositories: []*v1alpha1.Repository{repo},
+		PipelineRuns: []*v1beta1.PipelineRun{
+			newTestPR("first", now, startedLabel, map[string]string{keys.ExecutionOrder: order}),
+			newTestPR("second", now, queuedLabel, map[string]string{
+				keys.ExecutionOrder: order,
+				keys.FrozenUntil:    now.Add(time.Hour).Format(time.RFC3339),
+			}),
+			newTestPR("third", now, queuedLabel, map[string]string{
+				keys.ExecutionOrder: order,
+				keys.FrozenUntil:    now.Add(-time.Hour).Format(time.RFC3339),
+			}),
+		},
+	}
+	stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+
+	qm := NewQueueManager(logger)
+	assert.NilError(t, qm.InitQueues(ctx, stdata.Pipeline, stdata.PipelineAsCode))
+	assert.DeepEqual(t, qm.RunningPipelineRuns(repo), []string{"test-ns/first"})
+	assert.DeepEqual(t, qm.QueuedPipelineRuns(repo), []string{"test-ns/third"})
 }
@@ -2,6 +2,7 @@ package sync
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -38,19 +39,31 @@ func (s *prioritySemaphore) getLimit() int {
 	return s.limit
 }
 
+// getCurrentPending returns the pending keys in the order they are acquired
 func (s *prioritySemaphore) getCurrentPending() []string {
+	items := append([]*item{}, s.pending.items...)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].priority < items[j].priority })
 	keys := []string{}
-	for _, item := range s.pending.items {
+	for _, item := range items {
 		keys = append(keys, item.key)
 	}
 	return keys
 }
 
+// getQueuedTime returns the time a pending key has been added to the queue
+func (s *prioritySemaphore) getQueuedTime(key string) time.Time {
+	if item, ok := s.pending.itemByKey[key]; ok {
+		return time.Unix(0, item.priority)
+	}
+	return time.Time{}
+}
+
 func (s *prioritySemaphore) getCurrentRunning() []string {
 	keys := []string{}
 	for k := range s.running {
 		keys = append(keys, k)
 	}
+	sort.Strings(keys)
 	return keys
 }
 
@@ -0,0 +1,53 @@
+package templates
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+const (
+	redactedValue = "*****"
+	// minSecretLength is the length under which the secrets of the event are
+	// not redacted, a short value would redact most of the text
+	minSecretLength = 6
+)
+
+// denyPatterns match the credentials which can be found in the payload of an
+// event, ie: a token pasted in the title of a pull request or a /test comment.
+var denyPatterns = []*regexp.Regexp{
+	// github personal, oauth, app and refresh tokens
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`),
+	regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{22,}\b`),
+	// gitlab personal, pipeline trigger and deploy tokens
+	regexp.MustCompile(`\bgl(pat|ptt|dt)-[A-Za-z0-9_-]{20,}`),
+	// slack tokens
+	regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),
+	// aws access keys
+	regexp.MustCompile(`\b(AKIA|ASIA)[A-Z0-9]{16}\b`),
+	// the credentials of an authorization header
+	regexp.MustCompile(`(?i)\b(bearer|basic|token)\s+[A-Za-z0-9._~+/=-]{16,}`),
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`),
+}
+
+// reURLPassword matches the password of a url, the user is kept
+var reURLPassword = regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+@`)
+
+// Redact hides the secrets of the event, its git provider token and webhook
+// secrets, the current and the previous one during a rotation, and the values
+// matching the deny patterns from a value of the event before it gets expanded
+// in a PipelineRun or logged.
+func Redact(event *info.Event, value string) string {
+	if event != nil && event.Provider != nil {
+		for _, secret := range []string{event.Provider.Token, event.Provider.WebhookSecret, event.Provider.PreviousWebhookSecret} {
+			if len(secret) >= minSecretLength {
+				value = strings.ReplaceAll(value, secret, redactedValue)
+			}
+		}
+	}
+	for _, pattern := range denyPatterns {
+		value = pattern.ReplaceAllString(value, redactedValue)
+	}
+	return reURLPassword.ReplaceAllString(value, "${1}"+redactedValue+"@")
+}
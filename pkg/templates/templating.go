@@ -12,6 +12,61 @@ import (
 
 var reTemplate = regexp.MustCompile(`{{([^}]{2,})}}`)
 
+// knownVariables are the variables replaced by Process and the
+// git_auth_secret one replaced when creating the PipelineRun
+var knownVariables = map[string]bool{
+	"revision":            true,
+	"repo_url":            true,
+	"repo_owner":          true,
+	"repo_name":           true,
+	"target_branch":       true,
+	"source_branch":       true,
+	"sender":              true,
+	"target_namespace":    true,
+	"pull_request_number": true,
+	"git_auth_secret":     true,
+
+	"pull_request_title":     true,
+	"pull_request_labels":    true,
+	"pull_request_author":    true,
+	"pull_request_assignees": true,
+	"sender_login":           true,
+	"target_project_id":      true,
+
+	"release_tag":        true,
+	"release_name":       true,
+	"release_prerelease": true,
+
+	"issue_number":       true,
+	"issue_title":        true,
+	"issue_comment_args": true,
+}
+
+// KnownVariable return true when the variable is replaced by Process, the
+// custom params of a Repository cannot use their names.
+func KnownVariable(name string) bool {
+	return knownVariables[name]
+}
+
+// UnknownVariables return the {{var}} placeholders of the template which
+// will not get replaced, with the line (starting at 1) where we found them.
+// The custom variables of the Repository are known.
+func UnknownVariables(template string, custom ...string) map[int][]string {
+	known := map[string]bool{}
+	for _, name := range custom {
+		known[name] = true
+	}
+	ret := map[int][]string{}
+	for i, line := range strings.Split(template, "\n") {
+		for _, parts := range reTemplate.FindAllStringSubmatch(line, -1) {
+			if key := strings.TrimSpace(parts[1]); !knownVariables[key] && !known[key] {
+				ret[i+1] = append(ret[i+1], key)
+			}
+		}
+	}
+	return ret
+}
+
 // ReplacePlaceHoldersVariables Replace those {{var}} placeholders to the runinfo variable
 func ReplacePlaceHoldersVariables(template string, dico map[string]string) string {
 	return reTemplate.ReplaceAllStringFunc(template, func(s string) string {
@@ -46,6 +101,33 @@ func Process(event *info.Event, repo *v1alpha1.Repository, template string) stri
 	// we don't want to get a 0 replaced
 	if event.PullRequestNumber != 0 {
 		maptemplate["pull_request_number"] = fmt.Sprintf("%d", event.PullRequestNumber)
+		// the title is written by the author of the pull request, it is kept
+		// on a single line to not add its own fields to the PipelineRun
+		maptemplate["pull_request_title"] = strings.Join(strings.Fields(event.PullRequestTitle), " ")
+		maptemplate["pull_request_labels"] = strings.Join(event.PullRequestLabels, ",")
+		maptemplate["pull_request_author"] = event.PullRequestAuthor
+		maptemplate["pull_request_assignees"] = strings.Join(event.PullRequestAssignees, ",")
+	}
+	if event.Sender != "" {
+		maptemplate["sender_login"] = event.Sender
+	}
+	if event.ReleaseTag != "" {
+		maptemplate["release_tag"] = event.ReleaseTag
+		maptemplate["release_name"] = strings.Join(strings.Fields(event.ReleaseName), " ")
+		maptemplate["release_prerelease"] = fmt.Sprintf("%t", event.ReleasePrerelease)
+	}
+	// like the pull requests, the title and the arguments are written by
+	// the users and kept on a single line
+	if event.IssueNumber != 0 {
+		maptemplate["issue_number"] = fmt.Sprintf("%d", event.IssueNumber)
+		maptemplate["issue_title"] = strings.Join(strings.Fields(event.IssueTitle), " ")
+		maptemplate["issue_comment_args"] = event.IssueCommentArgs
+	}
+	if event.TargetProjectID != 0 {
+		maptemplate["target_project_id"] = fmt.Sprintf("%d", event.TargetProjectID)
+	}
+	for key, value := range maptemplate {
+		maptemplate[key] = Redact(event, value)
 	}
 	return ReplacePlaceHoldersVariables(template, maptemplate)
 }
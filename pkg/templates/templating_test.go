@@ -69,6 +69,26 @@ func TestProcessTemplates(t *testing.T) {
 			template: `{{ source_branch }} {{ target_branch }}`,
 			expected: "ohyeah ohno",
 		},
+		{
+			name: "process release",
+			event: &info.Event{
+				ReleaseTag:        "v1.0.0",
+				ReleaseName:       "The first\nrelease",
+				ReleasePrerelease: true,
+			},
+			template: `{{ release_tag }} {{ release_name }} {{ release_prerelease }}`,
+			expected: "v1.0.0 The first release true",
+		},
+		{
+			name: "process issue",
+			event: &info.Event{
+				IssueNumber:      42,
+				IssueTitle:       "Deploy\nthe release",
+				IssueCommentArgs: "staging",
+			},
+			template: `{{ issue_number }} {{ issue_title }} {{ issue_comment_args }}`,
+			expected: "42 Deploy the release staging",
+		},
 		{
 			name: "process pull request number",
 			event: &info.Event{
@@ -77,6 +97,36 @@ func TestProcessTemplates(t *testing.T) {
 			template: `{{ pull_request_number }}`,
 			expected: "666",
 		},
+		{
+			name: "process pull request metadata",
+			event: &info.Event{
+				PullRequestNumber:    666,
+				PullRequestTitle:     "Fix the build\n  serviceAccountName: admin",
+				PullRequestLabels:    []string{"bug", "ci"},
+				PullRequestAuthor:    "Author",
+				PullRequestAssignees: []string{"first", "second"},
+				Sender:               "Apollo",
+				TargetProjectID:      42,
+			},
+			template: `{{ pull_request_title }};{{ pull_request_labels }};{{ pull_request_author }};{{ pull_request_assignees }};{{ sender_login }};{{ sender }};{{ target_project_id }}`,
+			expected: "Fix the build serviceAccountName: admin;bug,ci;Author;first,second;Apollo;apollo;42",
+		},
+		{
+			name: "secrets of the payload are redacted",
+			event: &info.Event{
+				PullRequestNumber: 1,
+				PullRequestTitle:  "Use webhooksecret456 and ghp_" + "aBcdEfGhIjKlMnOpQrStUvWxYz0123456789",
+				Provider:          &info.Provider{WebhookSecret: "webhooksecret456"},
+			},
+			template: `{{ pull_request_title }}`,
+			expected: "Use ***** and *****",
+		},
+		{
+			name:     "no pull request metadata on push",
+			event:    &info.Event{},
+			template: `{{ pull_request_title }} {{ pull_request_labels }} {{ sender_login }} {{ target_project_id }}`,
+			expected: `{{ pull_request_title }} {{ pull_request_labels }} {{ sender_login }} {{ target_project_id }}`,
+		},
 		{
 			name:     "no pull request no nothing",
 			event:    &info.Event{},
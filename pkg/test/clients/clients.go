@@ -41,6 +41,8 @@ type Data struct {
 	TaskRuns     []*pipelinev1beta1.TaskRun
 	PipelineRuns []*pipelinev1beta1.PipelineRun
 	Repositories []*v1alpha1.Repository
+	Policies     []*v1alpha1.RepositoryPolicy
+	PACSettings  []*v1alpha1.PACSettings
 	Namespaces   []*corev1.Namespace
 	Secret       []*corev1.Secret
 	Events       []*corev1.Event
@@ -86,6 +88,18 @@ func SeedTestData(t *testing.T, ctx context.Context, d Data) (Clients, Informers
 		}
 	}
 
+	for _, policy := range d.Policies {
+		if _, err := c.PipelineAsCode.PipelinesascodeV1alpha1().RepositoryPolicies().Create(ctx, policy, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, pacSettings := range d.PACSettings {
+		if _, err := c.PipelineAsCode.PipelinesascodeV1alpha1().PACSettings().Create(ctx, pacSettings, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
 	for _, n := range d.Namespaces {
 		if _, err := c.Kube.CoreV1().Namespaces().Create(ctx, n, metav1.CreateOptions{}); err != nil {
 			t.Fatal(err)
@@ -64,3 +64,7 @@ func (k *KinterfaceTest) CreateSecret(_ context.Context, _ string, _ *corev1.Sec
 func (k *KinterfaceTest) DeleteSecret(_ context.Context, _ *zap.SugaredLogger, _, _ string) error {
 	return nil
 }
+
+func (k *KinterfaceTest) GetDefaultServiceAccount(_ context.Context) string {
+	return "default"
+}
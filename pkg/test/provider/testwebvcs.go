@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
@@ -18,15 +19,26 @@ type TestProviderImp struct {
 	AllowIT                bool
 	Event                  *info.Event
 	TektonDirTemplate      string
+	TektonDirErroring      bool
 	CreateStatusErorring   bool
 	FilesInsideRepo        map[string]string
 	WantProviderRemoteTask bool
+	ChangedFiles           []string
+	CreatedStatuses        []provider.StatusOpts
+	ProtectedRefs          []string
+	Maintainers            []string
+	AutoOkToTestUsers      []string
+	// when set the payloads are only valid with this webhook secret
+	ValidWebhookSecret string
 }
 
 func (v *TestProviderImp) SetLogger(logger *zap.SugaredLogger) {
 }
 
 func (v *TestProviderImp) Validate(ctx context.Context, params *params.Run, event *info.Event) error {
+	if v.ValidWebhookSecret != "" && event.Provider.WebhookSecret != v.ValidWebhookSecret {
+		return fmt.Errorf("payload signature check failed")
+	}
 	return nil
 }
 
@@ -57,18 +69,53 @@ func (v *TestProviderImp) IsAllowed(ctx context.Context, event *info.Event) (boo
 	return false, nil
 }
 
+func (v *TestProviderImp) IsMaintainer(ctx context.Context, event *info.Event) (bool, error) {
+	for _, maintainer := range v.Maintainers {
+		if maintainer == event.Sender {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (v *TestProviderImp) IsAutoOkToTest(ctx context.Context, event *info.Event, auto *v1alpha1.AutoOkToTest) (bool, string, error) {
+	for _, user := range v.AutoOkToTestUsers {
+		if user == event.Sender {
+			return true, "test user", nil
+		}
+	}
+	return false, "", nil
+}
+
 func (v *TestProviderImp) GetTaskURI(ctx context.Context, params *params.Run, event *info.Event, task string) (bool, string, error) {
 	return v.WantProviderRemoteTask, "", nil
 }
 
+func (v *TestProviderImp) IsRefProtected(ctx context.Context, event *info.Event, ref string) (bool, error) {
+	for _, protected := range v.ProtectedRefs {
+		if protected == ref {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (v *TestProviderImp) CreateStatus(ctx context.Context, _ versioned.Interface, event *info.Event, opts *info.PacOpts, statusOpts provider.StatusOpts) error {
 	if v.CreateStatusErorring {
 		return fmt.Errorf("some provider error occurred while reporting status")
 	}
+	v.CreatedStatuses = append(v.CreatedStatuses, statusOpts)
 	return nil
 }
 
+func (v *TestProviderImp) CreateStatuses(ctx context.Context, tekton versioned.Interface, event *info.Event, opts *info.PacOpts, statuses []provider.StatusOpts) error {
+	return provider.CreateStatuses(ctx, v, tekton, event, opts, statuses)
+}
+
 func (v *TestProviderImp) GetTektonDir(ctx context.Context, event *info.Event, s string) (string, error) {
+	if v.TektonDirErroring {
+		return "", fmt.Errorf("some provider error occurred while getting the tekton directory")
+	}
 	return v.TektonDirTemplate, nil
 }
 
@@ -80,5 +127,8 @@ func (v *TestProviderImp) GetFileInsideRepo(ctx context.Context, event *info.Eve
 }
 
 func (v *TestProviderImp) GetFiles(ctx context.Context, event *info.Event) ([]string, error) {
+	if v.ChangedFiles != nil {
+		return v.ChangedFiles, nil
+	}
 	return []string{}, nil
 }
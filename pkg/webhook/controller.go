@@ -2,8 +2,13 @@ package webhook
 
 import (
 	"context"
+	"net/http"
 
+	pacclient "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/injection/client"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/generated/injection/informers/pipelinesascode/v1alpha1/repository"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
 
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
@@ -29,6 +34,15 @@ func NewAdmissionController(
 	secretInformer := secretinformer.Get(ctx)
 	options := webhook.GetOptions(ctx)
 	repositoryInformer := repository.Get(ctx)
+	run := &params.Run{
+		Clients: clients.Clients{
+			PipelineAsCode: pacclient.Get(ctx),
+			Kube:           client,
+			HTTP:           http.Client{Timeout: accessCheckTimeout},
+			Log:            logging.FromContext(ctx),
+		},
+	}
+	kinteract, _ := kubeinteraction.NewKubernetesInteraction(run)
 
 	key := types.NamespacedName{Name: name}
 
@@ -53,6 +67,9 @@ func NewAdmissionController(
 		secretlister: secretInformer.Lister(),
 
 		pacLister: repositoryInformer.Lister(),
+
+		run:       run,
+		kinteract: kinteract,
 	}
 
 	logger := logging.FromContext(ctx)
@@ -0,0 +1,214 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/controller"
+	secretinformer "knative.dev/pkg/injection/clients/namespacedkube/informers/core/v1/secret"
+	"knative.dev/pkg/kmp"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/ptr"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
+	"knative.dev/pkg/webhook"
+	certresources "knative.dev/pkg/webhook/certificates/resources"
+)
+
+// defaultingReconciler set the unset settings of the PACSettings from the
+// pipelines-as-code ConfigMap and the defaults, the PACSettings then shows all
+// the settings used by Pipelines as Code.
+type defaultingReconciler struct {
+	webhook.StatelessAdmissionImpl
+	pkgreconciler.LeaderAwareFuncs
+
+	key  types.NamespacedName
+	path string
+
+	client       kubernetes.Interface
+	secretlister corelisters.SecretLister
+
+	secretName string
+}
+
+var (
+	_ controller.Reconciler                = (*defaultingReconciler)(nil)
+	_ pkgreconciler.LeaderAware            = (*defaultingReconciler)(nil)
+	_ webhook.AdmissionController          = (*defaultingReconciler)(nil)
+	_ webhook.StatelessAdmissionController = (*defaultingReconciler)(nil)
+)
+
+// NewDefaultingAdmissionController constructs a reconciler for the
+// MutatingWebhookConfiguration of the PACSettings
+func NewDefaultingAdmissionController(ctx context.Context, name, path string) *controller.Impl {
+	client := kubeclient.Get(ctx)
+	secretInformer := secretinformer.Get(ctx)
+	options := webhook.GetOptions(ctx)
+
+	key := types.NamespacedName{Name: name}
+
+	wh := &defaultingReconciler{
+		LeaderAwareFuncs: pkgreconciler.LeaderAwareFuncs{
+			// Have this reconciler enqueue our singleton whenever it becomes leader.
+			PromoteFunc: func(bkt pkgreconciler.Bucket, enq func(pkgreconciler.Bucket, types.NamespacedName)) error {
+				enq(bkt, key)
+				return nil
+			},
+		},
+
+		key:  key,
+		path: path,
+
+		secretName: options.SecretName,
+
+		client:       client,
+		secretlister: secretInformer.Lister(),
+	}
+
+	logger := logging.FromContext(ctx)
+	c := controller.NewContext(ctx, wh, controller.ControllerOptions{WorkQueueName: "DefaultingWebhook", Logger: logger.Named("DefaultingWebhook")})
+
+	// Reconcile when the cert bundle changes.
+	secretInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterWithNameAndNamespace(system.Namespace(), wh.secretName),
+		// It doesn't matter what we enqueue because we will always Reconcile
+		// the named MWH resource.
+		Handler: controller.HandleAll(c.Enqueue),
+	})
+
+	return c
+}
+
+// Reconcile implements controller.Reconciler
+func (ac *defaultingReconciler) Reconcile(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx)
+
+	if !ac.IsLeaderFor(ac.key) {
+		logger.Debugf("Skipping key %q, not the leader.", ac.key)
+		return nil
+	}
+
+	// Look up the webhook secret, and fetch the CA cert bundle.
+	secret, err := ac.secretlister.Secrets(system.Namespace()).Get(ac.secretName)
+	if err != nil {
+		logger.Errorw("Error fetching secret", zap.Error(err))
+		return err
+	}
+	caCert, ok := secret.Data[certresources.CACert]
+	if !ok {
+		return fmt.Errorf("secret %q is missing %q key", ac.secretName, certresources.CACert)
+	}
+
+	// Reconcile the webhook configuration.
+	return ac.reconcileMutatingWebhook(ctx, caCert)
+}
+
+func (ac *defaultingReconciler) reconcileMutatingWebhook(ctx context.Context, caCert []byte) error {
+	logger := logging.FromContext(ctx)
+
+	rules := []admissionregistrationv1.RuleWithOperations{
+		{
+			Operations: []admissionregistrationv1.OperationType{
+				admissionregistrationv1.Create,
+				admissionregistrationv1.Update,
+			},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{pipelinesascode.GroupName},
+				APIVersions: []string{"v1alpha1"},
+				Resources:   []string{"pacsettings"},
+			},
+		},
+	}
+
+	// there is no vendored informer for the MutatingWebhookConfigurations, it
+	// is only reconciled on the cert bundle changes and when we become leader.
+	mwhclient := ac.client.AdmissionregistrationV1().MutatingWebhookConfigurations()
+	configuredWebhook, err := mwhclient.Get(ctx, ac.key.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error retrieving webhook: %w", err)
+	}
+
+	webhook := configuredWebhook.DeepCopy()
+
+	// Clear out any previous (bad) OwnerReferences.
+	// See: https://github.com/knative/serving/issues/5845
+	webhook.OwnerReferences = nil
+
+	for i, wh := range webhook.Webhooks {
+		if wh.Name != webhook.Name {
+			continue
+		}
+		webhook.Webhooks[i].Rules = rules
+		webhook.Webhooks[i].ClientConfig.CABundle = caCert
+		if webhook.Webhooks[i].ClientConfig.Service == nil {
+			return fmt.Errorf("missing service reference for webhook: %s", wh.Name)
+		}
+		webhook.Webhooks[i].ClientConfig.Service.Path = ptr.String(ac.Path())
+	}
+
+	ok, err := kmp.SafeEqual(configuredWebhook, webhook)
+	if err != nil {
+		return fmt.Errorf("error diffing webhooks: %w", err)
+	}
+	if !ok {
+		logger.Info("Updating webhook")
+		if _, err := mwhclient.Update(ctx, webhook, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update webhook: %w", err)
+		}
+	} else {
+		logger.Info("Webhook is valid")
+	}
+	return nil
+}
+
+// Path implements AdmissionController
+func (ac *defaultingReconciler) Path() string {
+	return ac.path
+}
+
+// Admit implements AdmissionController
+func (ac *defaultingReconciler) Admit(ctx context.Context, request *v1.AdmissionRequest) *v1.AdmissionResponse {
+	pacSettings := v1alpha1.PACSettings{}
+	if _, _, err := universalDeserializer.Decode(request.Object.Raw, nil, &pacSettings); err != nil {
+		return webhook.MakeErrorStatus("defaulting failed: %v", err)
+	}
+
+	cfg, err := ac.client.CoreV1().ConfigMaps(system.Namespace()).Get(ctx, params.PACConfigmapName, metav1.GetOptions{})
+	if err != nil {
+		return webhook.MakeErrorStatus("defaulting failed, cannot get the configmap %s: %v", params.PACConfigmapName, err)
+	}
+
+	spec := *pacSettings.Spec.DeepCopy()
+	settings.DefaultSpec(&spec, cfg.Data)
+	if reflect.DeepEqual(spec, pacSettings.Spec) {
+		return &v1.AdmissionResponse{Allowed: true}
+	}
+
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "add", "path": "/spec", "value": spec},
+	})
+	if err != nil {
+		return webhook.MakeErrorStatus("defaulting failed: %v", err)
+	}
+	patchType := v1.PatchTypeJSONPatch
+	return &v1.AdmissionResponse{
+		Allowed:   true,
+		Patch:     patch,
+		PatchType: &patchType,
+	}
+}
@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	v1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	rtesting "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/system"
+)
+
+func TestDefaultingReconciler_Admit(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		ConfigMap: []*corev1.ConfigMap{{
+			ObjectMeta: metav1.ObjectMeta{Name: params.PACConfigmapName, Namespace: system.Namespace()},
+			Data:       map[string]string{settings.HubCatalogNameKey: "custom"},
+		}},
+	})
+	r := defaultingReconciler{client: stdata.Kube}
+
+	raw, err := json.Marshal(&v1alpha1.PACSettings{
+		ObjectMeta: metav1.ObjectMeta{Name: settings.PACSettingsName},
+		Spec:       v1alpha1.PACSettingsSpec{ApplicationName: "My CI"},
+	})
+	assert.NilError(t, err)
+	res := r.Admit(ctx, &v1.AdmissionRequest{Object: runtime.RawExtension{Raw: raw}})
+	assert.Assert(t, res.Allowed)
+	assert.Equal(t, *res.PatchType, v1.PatchTypeJSONPatch)
+
+	patch := []struct {
+		Op    string                   `json:"op"`
+		Path  string                   `json:"path"`
+		Value v1alpha1.PACSettingsSpec `json:"value"`
+	}{}
+	assert.NilError(t, json.Unmarshal(res.Patch, &patch))
+	assert.Equal(t, len(patch), 1)
+	assert.Equal(t, patch[0].Path, "/spec")
+	assert.Equal(t, patch[0].Value.ApplicationName, "My CI")
+	assert.Equal(t, patch[0].Value.HubCatalogName, "custom")
+	assert.Equal(t, patch[0].Value.HubURL, settings.HubURLDefaultValue)
+
+	// an already defaulted spec is not patched
+	defaulted, err := json.Marshal(&v1alpha1.PACSettings{
+		ObjectMeta: metav1.ObjectMeta{Name: settings.PACSettingsName},
+		Spec:       patch[0].Value,
+	})
+	assert.NilError(t, err)
+	res = r.Admit(ctx, &v1.AdmissionRequest{Object: runtime.RawExtension{Raw: defaulted}})
+	assert.Assert(t, res.Allowed)
+	assert.Assert(t, res.Patch == nil)
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
 	pac "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/listers/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"go.uber.org/zap"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -40,6 +42,10 @@ type reconciler struct {
 	secretName            string
 
 	pacLister pac.RepositoryLister
+
+	// run and kinteract check the access of the tokens of the Repositories
+	run       *params.Run
+	kinteract kubeinteraction.Interface
 }
 
 var (
@@ -85,7 +91,7 @@ func (ac *reconciler) reconcileValidatingWebhook(ctx context.Context, caCert []b
 			Rule: admissionregistrationv1.Rule{
 				APIGroups:   []string{pipelinesascode.GroupName},
 				APIVersions: []string{"v1alpha1"},
-				Resources:   []string{"repositories", "repositories" + "/status"},
+				Resources:   []string{"repositories", "repositories" + "/status", "pacsettings"},
 			},
 		},
 	}
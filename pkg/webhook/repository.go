@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/credentials"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/notification"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
+	v1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/logging"
+)
+
+// accessCheckTimeout keeps the access check well under the timeout of the
+// admission webhooks, 10 seconds by default
+const accessCheckTimeout = 5 * time.Second
+
+// secretReference is a secret referenced by a field of a Repository, the
+// default key is used when the reference has no key, the secret only has to
+// exist when there is no default key either.
+type secretReference struct {
+	field      string
+	secret     v1alpha1.Secret
+	defaultKey string
+}
+
+// specChanged returns if the spec of a Repository is created or changed by
+// the admission request, the updates of its status, its annotations or its
+// finalizers, ie: by the watcher, are not checked again against the cluster
+// or the git provider.
+func specChanged(request *v1.AdmissionRequest, repo *v1alpha1.Repository) bool {
+	if request.Operation != v1.Update {
+		return true
+	}
+	if request.SubResource != "" || repo.GetDeletionTimestamp() != nil {
+		return false
+	}
+	old := v1alpha1.Repository{}
+	if _, _, err := universalDeserializer.Decode(request.OldObject.Raw, nil, &old); err != nil {
+		return true
+	}
+	return !equality.Semantic.DeepEqual(old.Spec, repo.Spec)
+}
+
+// validateRepositoryURL checks the url of a Repository is the url of a git
+// repository or of an organization with a wildcard.
+func validateRepositoryURL(repoURL string) error {
+	parsed, err := url.Parse(repoURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("the url %q must be the http or https url of a git repository, ie: https://github.com/owner/repo", repoURL)
+	}
+	if parsed.RawQuery != "" || parsed.Fragment != "" || parsed.User != nil {
+		return fmt.Errorf("the url %s must not have credentials, a query or a fragment, the token goes in the git_provider secret", repoURL)
+	}
+	if _, wildcard := formatting.WildcardURL(repoURL); wildcard {
+		return nil
+	}
+	if len(strings.Split(strings.Trim(parsed.Path, "/"), "/")) < 2 {
+		return fmt.Errorf("the url %s must have the owner and the name of the git repository, ie: https://github.com/owner/repo", repoURL)
+	}
+	return nil
+}
+
+func secretReferences(repo *v1alpha1.Repository) []secretReference {
+	refs := []secretReference{}
+	if repo.Spec.GitProvider != nil {
+		if repo.Spec.GitProvider.Secret != nil {
+			refs = append(refs, secretReference{"git_provider.secret", *repo.Spec.GitProvider.Secret, pipelineascode.DefaultGitProviderSecretKey})
+		}
+		if repo.Spec.GitProvider.WebhookSecret != nil {
+			refs = append(refs, secretReference{"git_provider.webhook_secret", *repo.Spec.GitProvider.WebhookSecret, pipelineascode.DefaultGitProviderWebhookSecretKey})
+		}
+	}
+	for i, n := range repo.Spec.Notifications {
+		refs = append(refs, secretReference{fmt.Sprintf("notifications[%d].secret", i), n.Secret, notification.DefaultSecretKey})
+	}
+	for i, mirror := range repo.Spec.Mirrors {
+		if mirror.GitProvider.Secret != nil {
+			refs = append(refs, secretReference{fmt.Sprintf("mirrors[%d].git_provider.secret", i), *mirror.GitProvider.Secret, pipelineascode.DefaultGitProviderSecretKey})
+		}
+	}
+	if repo.Spec.Incomings != nil {
+		for i, incoming := range *repo.Spec.Incomings {
+			refs = append(refs, secretReference{fmt.Sprintf("incoming[%d].secret", i), incoming.Secret, ""})
+		}
+	}
+	return refs
+}
+
+// checkSecrets checks the kubernetes secrets referenced by a Repository exist
+// in its namespace with their keys, the secrets of vault or external-secrets
+// are only fetched when they are used.
+func (ac *reconciler) checkSecrets(ctx context.Context, repo *v1alpha1.Repository) error {
+	for _, ref := range secretReferences(repo) {
+		if ref.secret.Name == "" || (ref.secret.Source != "" && ref.secret.Source != secrets.SourceKubernetes) {
+			continue
+		}
+		secret, err := ac.client.CoreV1().Secrets(repo.GetNamespace()).Get(ctx, ref.secret.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return fmt.Errorf("the secret %s of %s does not exist in the namespace %s, create it before the repository",
+				ref.secret.Name, ref.field, repo.GetNamespace())
+		}
+		if err != nil {
+			return fmt.Errorf("cannot get the secret %s of %s: %w", ref.secret.Name, ref.field, err)
+		}
+		key := ref.secret.Key
+		if key == "" {
+			key = ref.defaultKey
+		}
+		if _, ok := secret.Data[key]; key != "" && !ok {
+			return fmt.Errorf("the secret %s of %s has no %s key, set the key of the secret in the repository if it is another one",
+				ref.secret.Name, ref.field, key)
+		}
+	}
+	return nil
+}
+
+// checkAccess checks the token of a Repository can access its git repository
+// with check-repository-access, the Repositories using the GitHub App and the
+// wildcard ones are not checked. The access is not checked when the settings
+// cannot be read, to not refuse all the Repositories on a broken install.
+func (ac *reconciler) checkAccess(ctx context.Context, repo *v1alpha1.Repository) error {
+	if repo.Spec.GitProvider == nil || repo.Spec.GitProvider.Secret == nil || repo.Spec.GitProvider.Secret.Name == "" {
+		return nil
+	}
+	if source := repo.Spec.GitProvider.Secret.Source; source != "" && source != secrets.SourceKubernetes {
+		return nil
+	}
+	if _, wildcard := formatting.WildcardURL(repo.Spec.URL); wildcard {
+		return nil
+	}
+	pacSettings, err := ac.run.ReadPACSettings(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("cannot read the settings, the access of the repository is not checked: %v", err)
+		return nil
+	}
+	if !pacSettings.CheckRepositoryAccess {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, accessCheckTimeout)
+	defer cancel()
+	return credentials.CheckAccess(ctx, ac.run, ac.kinteract, repo)
+}
@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	kitesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	testnewrepo "github.com/openshift-pipelines/pipelines-as-code/pkg/test/repository"
+	"gotest.tools/v3/assert"
+	v1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestAdmitCheckRepositoryAccess(t *testing.T) {
+	t.Setenv("SYSTEM_NAMESPACE", "pac")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/projects/group/project" {
+			fmt.Fprint(w, `{"id": 1}`)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "404 Project Not Found"}`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		url     string
+		check   string
+		allowed bool
+		result  string
+	}{
+		{
+			name:    "allow accessible repository",
+			url:     "https://gitlab.company.com/group/project",
+			check:   "true",
+			allowed: true,
+		},
+		{
+			name:    "reject inaccessible repository",
+			url:     "https://gitlab.company.com/group/other",
+			check:   "true",
+			allowed: false,
+			result: fmt.Sprintf("validation failed: the token of the secret gitlab cannot access https://gitlab.company.com/group/other: "+
+				"GET %s/api/v4/projects/group/other: 404 {message: 404 Project Not Found}", server.URL),
+		},
+		{
+			name:    "allow without check-repository-access",
+			url:     "https://gitlab.company.com/group/other",
+			check:   "false",
+			allowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			log, _ := logger.GetLogger()
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				ConfigMap: []*corev1.ConfigMap{{
+					ObjectMeta: metav1.ObjectMeta{Name: params.PACConfigmapName, Namespace: "pac"},
+					Data:       map[string]string{"check-repository-access": tt.check},
+				}},
+				Secret: []*corev1.Secret{{
+					ObjectMeta: metav1.ObjectMeta{Name: "gitlab", Namespace: "namespace"},
+					Data:       map[string][]byte{"provider.token": []byte("token")},
+				}},
+			})
+			r := reconciler{
+				pacLister: stdata.RepositoryLister,
+				client:    stdata.Kube,
+				run: &params.Run{Clients: clients.Clients{
+					Kube:           stdata.Kube,
+					PipelineAsCode: stdata.PipelineAsCode,
+					Log:            log,
+				}},
+				kinteract: &kitesthelper.KinterfaceTest{GetSecretResult: map[string]string{"gitlab": "token"}},
+			}
+
+			repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+				Name:             "test-run",
+				InstallNamespace: "namespace",
+				URL:              tt.url,
+				SecretName:       "gitlab",
+				ProviderURL:      server.URL,
+			})
+			repo.Spec.GitProvider.Type = "gitlab"
+			raw, err := json.Marshal(repo)
+			assert.NilError(t, err)
+			res := r.Admit(ctx, &v1.AdmissionRequest{Operation: v1.Create, Object: runtime.RawExtension{Raw: raw}})
+
+			assert.Equal(t, res.Allowed, tt.allowed)
+			if !res.Allowed {
+				assert.Equal(t, res.Result.Message, tt.result)
+			}
+		})
+	}
+}
+
+func TestSpecChanged(t *testing.T) {
+	repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+		Name:             "test-run",
+		InstallNamespace: "namespace",
+		URL:              "https://gitlab.com/group/project",
+		SecretName:       "deleted",
+	})
+	oldRaw, err := json.Marshal(repo)
+	assert.NilError(t, err)
+
+	assert.Assert(t, specChanged(&v1.AdmissionRequest{Operation: v1.Create}, repo))
+	update := &v1.AdmissionRequest{Operation: v1.Update, OldObject: runtime.RawExtension{Raw: oldRaw}}
+	assert.Assert(t, !specChanged(update, repo), "the status or the metadata have been updated")
+	status := &v1.AdmissionRequest{Operation: v1.Update, SubResource: "status", OldObject: runtime.RawExtension{Raw: oldRaw}}
+	assert.Assert(t, !specChanged(status, repo))
+
+	changed := repo.DeepCopy()
+	changed.Spec.GitProvider.Secret = &v1alpha1.Secret{Name: "new"}
+	assert.Assert(t, specChanged(update, changed))
+
+	changed.DeletionTimestamp = &metav1.Time{}
+	assert.Assert(t, !specChanged(update, changed), "the finalizers of a deleted repository are removed")
+}
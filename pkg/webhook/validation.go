@@ -3,9 +3,18 @@ package webhook
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strings"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/credentials"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/freeze"
 	pac "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/listers/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/notification"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/templates"
 	v1 "k8s.io/api/admission/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -13,6 +22,8 @@ import (
 	"knative.dev/pkg/webhook"
 )
 
+const pacSettingsKind = "PACSettings"
+
 var universalDeserializer = serializer.NewCodecFactory(runtime.NewScheme()).UniversalDeserializer()
 
 // Path implements AdmissionController
@@ -21,12 +32,26 @@ func (ac *reconciler) Path() string {
 }
 
 // Admit implements AdmissionController
-func (ac *reconciler) Admit(_ context.Context, request *v1.AdmissionRequest) *v1.AdmissionResponse {
+func (ac *reconciler) Admit(ctx context.Context, request *v1.AdmissionRequest) *v1.AdmissionResponse {
 	raw := request.Object.Raw
+	if request.Kind.Kind == pacSettingsKind {
+		return admitPACSettings(raw)
+	}
+
 	repo := v1alpha1.Repository{}
 	if _, _, err := universalDeserializer.Decode(raw, nil, &repo); err != nil {
 		return webhook.MakeErrorStatus("validation failed: %v", err)
 	}
+	if repo.GetNamespace() == "" {
+		repo.SetNamespace(request.Namespace)
+	}
+	checkSpec := specChanged(request, &repo)
+
+	if checkSpec {
+		if err := validateRepositoryURL(repo.Spec.URL); err != nil {
+			return webhook.MakeErrorStatus("validation failed: %v", err)
+		}
+	}
 
 	exist, err := checkIfRepoExist(ac.pacLister, &repo, "")
 	if err != nil {
@@ -34,13 +59,89 @@ func (ac *reconciler) Admit(_ context.Context, request *v1.AdmissionRequest) *v1
 	}
 
 	if exist {
+		if formatting.ShowSubPath(repo) != "" {
+			return webhook.MakeErrorStatus(fmt.Sprintf("repository already exist with url: %s and sub_path: %s", repo.Spec.URL, formatting.ShowSubPath(repo)))
+		}
 		return webhook.MakeErrorStatus(fmt.Sprintf("repository already exist with url: %s", repo.Spec.URL))
 	}
 
+	if org, ok := formatting.WildcardURL(repo.Spec.URL); ok {
+		if parsed, err := url.Parse(org); err != nil || strings.Trim(parsed.Path, "/") == "" || strings.Contains(org, "*") {
+			return webhook.MakeErrorStatus("the wildcard url %s must match the git repositories of an organization or a group, ie: https://github.com/myorg/*", repo.Spec.URL)
+		}
+	}
+
 	if repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit == 0 {
 		return webhook.MakeErrorStatus("concurrency limit must be greater than 0")
 	}
 
+	if repo.Spec.MaxParallel != nil && *repo.Spec.MaxParallel <= 0 {
+		return webhook.MakeErrorStatus("max parallel must be greater than 0")
+	}
+
+	for _, window := range repo.Spec.FreezeWindows {
+		if err := freeze.Validate(window); err != nil {
+			return webhook.MakeErrorStatus("validation failed: %v", err)
+		}
+	}
+
+	for _, n := range repo.Spec.Notifications {
+		if err := notification.Validate(n); err != nil {
+			return webhook.MakeErrorStatus("validation failed: %v", err)
+		}
+	}
+
+	for _, mirror := range repo.Spec.Mirrors {
+		if err := validateRepositoryURL(mirror.URL); err != nil {
+			return webhook.MakeErrorStatus("validation failed on mirror: %v", err)
+		}
+		if _, wildcard := formatting.WildcardURL(mirror.URL); wildcard {
+			return webhook.MakeErrorStatus("the url %s of a mirror must be the url of a git repository", mirror.URL)
+		}
+		if err := credentials.ValidateMirror(mirror); err != nil {
+			return webhook.MakeErrorStatus("validation failed: %v", err)
+		}
+	}
+
+	names := map[string]bool{}
+	for _, param := range repo.Spec.CustomParams {
+		if param.Name == "" || templates.KnownVariable(param.Name) || names[param.Name] {
+			return webhook.MakeErrorStatus("custom param name %q must be set, unique and not the name of a template variable", param.Name)
+		}
+		names[param.Name] = true
+		if err := matcher.ValidateCELExpression(param.Value); err != nil {
+			return webhook.MakeErrorStatus("validation failed on custom param %s: %v", param.Name, err)
+		}
+	}
+
+	if checkSpec {
+		if err := ac.checkSecrets(ctx, &repo); err != nil {
+			return webhook.MakeErrorStatus("validation failed: %v", err)
+		}
+		if err := ac.checkAccess(ctx, &repo); err != nil {
+			return webhook.MakeErrorStatus("validation failed: %v", err)
+		}
+	}
+
+	return &v1.AdmissionResponse{Allowed: true}
+}
+
+// admitPACSettings only allow the PACSettings of the cluster with valid
+// settings, the schema of the CRD does not validate the values of the settings.
+func admitPACSettings(raw []byte) *v1.AdmissionResponse {
+	pacSettings := v1alpha1.PACSettings{}
+	if _, _, err := universalDeserializer.Decode(raw, nil, &pacSettings); err != nil {
+		return webhook.MakeErrorStatus("validation failed: %v", err)
+	}
+
+	if pacSettings.GetName() != settings.PACSettingsName {
+		return webhook.MakeErrorStatus("pacsettings must be named %s, the other ones are not used", settings.PACSettingsName)
+	}
+
+	if err := settings.Validate(settings.SpecToConfig(pacSettings.Spec)); err != nil {
+		return webhook.MakeErrorStatus("validation failed: %v", err)
+	}
+
 	return &v1.AdmissionResponse{Allowed: true}
 }
 
@@ -51,8 +152,14 @@ func checkIfRepoExist(pac pac.RepositoryLister, repo *v1alpha1.Repository, ns st
 	}
 	for i := len(repositories) - 1; i >= 0; i-- {
 		repoFromCluster := repositories[i]
-		if repoFromCluster.Spec.URL == repo.Spec.URL &&
-			(repoFromCluster.Name != repo.Name || repoFromCluster.Namespace != repo.Namespace) {
+		if repoFromCluster.Spec.URL != repo.Spec.URL ||
+			(repoFromCluster.Name == repo.Name && repoFromCluster.Namespace == repo.Namespace) {
+			continue
+		}
+		// the Repositories of a monorepo share the url, each with its own
+		// sub_path
+		subPath, clusterSubPath := formatting.ShowSubPath(*repo), formatting.ShowSubPath(*repoFromCluster)
+		if subPath == "" || clusterSubPath == "" || subPath == clusterSubPath {
 			return true, nil
 		}
 	}